@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/config"
+	"github.com/sinouw/multilingual-video-processor/internal/video"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// Set at build time, e.g. go build -ldflags "-X main.gitCommit=$(git rev-parse --short HEAD) -X main.buildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	gitCommit string
+	buildTime string
+)
+
+// toolVersionTimeout bounds each ffmpeg/ffprobe version probe at startup
+const toolVersionTimeout = 5 * time.Second
+
+// buildBuildInfo describes the deployed build for /health
+// FFmpeg tool versions are probed once here and cached for the life of the instance.
+func buildBuildInfo(ctx context.Context, cfg *config.Config, capabilities *models.CapabilitiesResponse) *models.BuildInfo {
+	info := &models.BuildInfo{
+		GitCommit:    gitCommit,
+		BuildTime:    buildTime,
+		GoVersion:    runtime.Version(),
+		Dependencies: map[string]string{},
+		Providers:    map[string]string{},
+	}
+
+	// Fall back to the VCS stamp embedded by the Go toolchain when ldflags were not set
+	if settings, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range settings.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.GitCommit == "":
+				info.GitCommit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+
+	for _, tool := range []string{"ffmpeg", "ffprobe"} {
+		probeCtx, cancel := context.WithTimeout(ctx, toolVersionTimeout)
+		version, err := video.ToolVersion(probeCtx, tool)
+		cancel()
+		if err != nil {
+			slog.Warn("Failed to probe tool version", "tool", tool, "error", err)
+			version = "unavailable"
+		}
+		info.Dependencies[tool] = version
+	}
+
+	for component, provider := range capabilities.Providers {
+		info.Providers[component] = provider
+	}
+	info.Providers["cache"] = valueOrNone(cfg.CacheBackend)
+	info.Providers["email"] = valueOrNone(cfg.EmailProvider)
+	info.Providers["separation"] = valueOrNone(cfg.AudioSeparation)
+	info.Providers["transcode"] = "local"
+	if cfg.IsTranscodeOffloadEnabled() {
+		info.Providers["transcode"] = cfg.TranscodeBackend
+	}
+	info.Providers["notifications"] = valueOrNone(strings.Join(capabilities.Notifications, ","))
+	info.Providers["jobStore"] = "memory"
+	info.Providers["storage"] = "gcs"
+	if cfg.DevMockProviders {
+		info.Providers["storage"] = "local"
+	}
+
+	return info
+}
+
+// valueOrNone returns "none" for an empty value
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"github.com/sinouw/multilingual-video-processor/internal/config"
+	"github.com/sinouw/multilingual-video-processor/internal/mock"
+	"github.com/sinouw/multilingual-video-processor/internal/notification"
+	stt "github.com/sinouw/multilingual-video-processor/internal/stt"
+	"github.com/sinouw/multilingual-video-processor/internal/translation"
+	"github.com/sinouw/multilingual-video-processor/internal/tts"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// buildCapabilities derives the capabilities document from runtime configuration
+func buildCapabilities(cfg *config.Config, translators *translation.Registry, notifiers []notification.Notifier) *models.CapabilitiesResponse {
+	channels := []string{}
+	if cfg.WebhookURL != "" {
+		channels = append(channels, "webhook")
+	}
+	for _, n := range notifiers {
+		channels = append(channels, n.Name())
+	}
+	if cfg.IsEmailEnabled() {
+		channels = append(channels, "email")
+	}
+
+	requestOptions := []string{"sourceLanguage", "preset", "profanityFilter", "pronunciations", "startTime", "endTime", "sourceAudioTrack", "outputDestinations", "allLanguages", "tags", "metadata", "subtitleUrl", "sourceText", "narration", "transcription", "branding", "outputAudio", "dubbedAudio", "voiceId", "voiceGender", "outputBucket", "subtitleProfile", "subtitleOffset", "reprocess"}
+	if cfg.IsEmailEnabled() {
+		requestOptions = append(requestOptions, "notifyEmail")
+	}
+	if cfg.WebhookURL != "" {
+		requestOptions = append(requestOptions, "webhookEvents")
+	}
+	if cfg.IsLLMTranslation() {
+		requestOptions = append(requestOptions, "styleInstructions", "summary", "analysis")
+	}
+	if cfg.IsAudioSeparationEnabled() {
+		requestOptions = append(requestOptions, "keepBackgroundMusic")
+	}
+	if !cfg.DevMockProviders {
+		requestOptions = append(requestOptions, "serviceAccount")
+	}
+
+	sttProvider, ttsProvider := stt.ProviderName, tts.ProviderName
+	if cfg.DevMockProviders {
+		sttProvider, ttsProvider = mock.ProviderName, mock.ProviderName
+	}
+
+	var maxChapteredDuration int
+	if cfg.IsChapteredProcessingEnabled() {
+		maxChapteredDuration = int(cfg.MaxChapteredDuration.Seconds())
+	}
+
+	// The budget is counted by each instance, not across the deployment
+	var spendBudget *models.SpendBudgetLimits
+	if cfg.IsSpendBudgetEnabled() {
+		spendBudget = &models.SpendBudgetLimits{
+			Scope:               models.SpendBudgetScopeInstance,
+			WindowSeconds:       int(cfg.SpendBudgetWindow.Seconds()),
+			Cost:                cfg.SpendBudgetCost,
+			STTSeconds:          cfg.SpendBudgetSTTSeconds,
+			TranslateCharacters: cfg.SpendBudgetTranslateChars,
+			TTSCharacters:       cfg.SpendBudgetTTSChars,
+		}
+	}
+
+	return &models.CapabilitiesResponse{
+		APIVersion:    cfg.APIVersion,
+		APIVersions:   models.SupportedAPIVersions,
+		Languages:     cfg.SupportedLanguages,
+		Presets:       models.SupportedPresets,
+		OutputFormats: []string{"mp4", "vtt", "txt", "mp3"},
+		Providers: map[string]string{
+			"stt":         sttProvider,
+			"translation": translators.Primary().Name(),
+			"tts":         ttsProvider,
+		},
+		Notifications: channels,
+		Features: map[string]bool{
+			"sourceLanguageAutoDetect": true,
+			"translationMemory":        true,
+			"translationMemoryStore":   cfg.TranslationMemoryBackend != "",
+			"emailNotifications":       cfg.IsEmailEnabled(),
+			"transcriptConfidenceGate": cfg.STTMinConfidence > 0,
+			"profanityFilter":          true,
+			"retryFailedLanguages":     true,
+			"styleInstructions":        cfg.IsLLMTranslation(),
+			"resultCache":              cfg.CacheBackend != "",
+			"apiKeyAuth":               len(cfg.APIKeys)+len(cfg.AdminAPIKeys) > 0,
+			"adminApi":                 len(cfg.AdminAPIKeys) > 0,
+			"transientRetry":           cfg.IsTransientRetryEnabled(),
+			"translationFallback":      cfg.HasTranslationFallback(),
+			"backgroundMusic":          cfg.IsAudioSeparationEnabled(),
+			"branding":                 cfg.IsBrandingEnabled(),
+			"geminiPipeline":           cfg.GeminiPipeline,
+			"previewPage":              cfg.PreviewPage,
+			"inputInspection":          true,
+			"sourceTranscript":         true,
+			"transcodeOffload":         cfg.IsTranscodeOffloadEnabled(),
+			"chapteredProcessing":      cfg.IsChapteredProcessingEnabled(),
+			"streamingTranscript":      cfg.IsChapteredProcessingEnabled(),
+			"elevenLabsVoices":         cfg.ElevenLabsAPIKey != "",
+		},
+		Limits: models.CapabilityLimits{
+			MaxVideoDurationSeconds:   int(cfg.MaxVideoDuration.Seconds()),
+			MaxVideoSizeMB:            cfg.MaxVideoSizeMB,
+			MaxRequestBodyBytes:       cfg.MaxRequestBodySize,
+			MaxConcurrentTranslations: cfg.MaxConcurrentTranslations,
+			RateLimitRPM:              cfg.RateLimitRPM,
+			RateLimitStatusRPM:        cfg.RateLimitStatusRPM,
+			MaxTargetLanguages:        cfg.MaxTargetLanguages,
+			MaxTranscriptChars:        cfg.MaxTranscriptChars,
+			MaxInFlightJobsPerClient:  cfg.MaxInFlightJobsPerClient,
+
+			MaxChapteredVideoDurationSeconds: maxChapteredDuration,
+			SpendBudget:                      spendBudget,
+		},
+		RequestOptions:    requestOptions,
+		TranslationRoutes: translators.Routes(),
+		TTSRoutes:         cfg.TTSRoutes,
+	}
+}
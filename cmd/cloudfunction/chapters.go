@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sinouw/multilingual-video-processor/internal/subtitles"
+	"github.com/sinouw/multilingual-video-processor/internal/translation"
+	"github.com/sinouw/multilingual-video-processor/internal/tts"
+	"github.com/sinouw/multilingual-video-processor/internal/video"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+	"golang.org/x/sync/errgroup"
+)
+
+// chapterDiskFactor is the temp disk space a chaptered job needs besides its DISK_SPACE_FACTOR reservation, as a
+// multiple of the downloaded video: the chapter copies of the video and background music, and the dubbed chapters
+// of each language until they are joined
+const chapterDiskFactor = 2
+
+// errChapterFailed reports a chapter that already failed the job, stopping the other chapters
+var errChapterFailed = errors.New("chapter processing failed")
+
+// useChapters reports whether a video too long for MAX_VIDEO_DURATION is processed in chapters
+// Only transcribed videos are split: supplied text and subtitles are not divided into chapters.
+func useChapters(req *models.TranslateRequest, duration float64) bool {
+	return cfg.IsChapteredProcessingEnabled() &&
+		duration <= cfg.MaxChapteredDuration.Seconds() &&
+		req.SourceText == "" && req.SubtitleURL == ""
+}
+
+// reserveChapterDiskSpace grows the disk reservation of a job found to need chapters by chapterDiskFactor times
+// the downloaded video's size. Returns false if the job was failed.
+func reserveChapterDiskSpace(ctx context.Context, jobID string, reserved int64, videoPath string) bool {
+	if diskTracker == nil || reserved == 0 {
+		return true // The reservation was skipped
+	}
+	info, err := os.Stat(videoPath)
+	if err != nil {
+		slog.Warn("Skipping chapter disk space reservation", "error", err, "jobID", jobID)
+		return true
+	}
+	return reserveDiskBytes(ctx, jobID, reserved+chapterDiskFactor*info.Size())
+}
+
+// forEachChapter runs fn for every chapter concurrently and returns the first error, cancelling the other chapters
+// Concurrency is bounded by the FFmpeg and API pools fn goes through.
+func forEachChapter(ctx context.Context, count int, fn func(ctx context.Context, index int) error) error {
+	group, ctx := errgroup.WithContext(ctx)
+	for i := 0; i < count; i++ {
+		group.Go(func() error {
+			return fn(ctx, i)
+		})
+	}
+	return group.Wait()
+}
+
+// splitIntoChapters cuts the source video into chapters of about CHAPTER_DURATION and measures each of them
+func splitIntoChapters(ctx context.Context, jobID string, videoPath string) ([]models.Chapter, error) {
+	setJobStage(jobID, models.StageSplittingChapters)
+	prefix := filepath.Join(os.TempDir(), fmt.Sprintf("chapter_%s_", jobID))
+	var paths []string
+	err := ffmpegPool.Do(ctx, func() (err error) {
+		paths, err = video.SplitChapters(ctx, videoPath, cfg.ChapterDuration.Seconds(), prefix)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	chapters := make([]models.Chapter, len(paths))
+	start := 0.0
+	for i, path := range paths {
+		var duration float64
+		err := ffmpegPool.Do(ctx, func() (err error) {
+			duration, err = video.GetVideoDuration(ctx, path)
+			return err
+		})
+		if err != nil {
+			for _, path := range paths {
+				removeTempFile(jobID, path)
+			}
+			return nil, fmt.Errorf("failed to get chapter duration: %w", err)
+		}
+		chapters[i] = models.Chapter{Start: start, End: start + duration, VideoPath: path}
+		start += duration
+	}
+
+	slog.Info("Video split into chapters", "jobID", jobID, "chapters", len(chapters))
+	return chapters, nil
+}
+
+// transcribeChapters transcribes the chapters in parallel and returns the language detected in the first one
+// The job is failed as soon as one chapter cannot be transcribed.
+func transcribeChapters(ctx context.Context, jobID string, req *models.TranslateRequest, chapters []models.Chapter) (string, bool) {
+	languages := make([]string, len(chapters))
+	err := forEachChapter(ctx, len(chapters), func(ctx context.Context, i int) error {
+		chapter := &chapters[i]
+		transcription, translations, ok := transcribeSourceAudio(ctx, jobID, req, chapter.VideoPath, chapter.End-chapter.Start)
+		if !ok {
+			return errChapterFailed
+		}
+		recordPartialTranscript(jobID, req, chapter.Start, chapter.End, transcription.Text)
+		chapter.Transcript = transcription.Text
+		chapter.Translations = translations
+		if translations == nil {
+			chapter.Transcript = cleanupTranscript(ctx, jobID, chapter.Transcript, transcription.Language)
+		}
+		languages[i] = transcription.Language
+		return nil
+	})
+	if err != nil {
+		return "", false
+	}
+	return languages[0], true
+}
+
+// chaptersTranscript joins the chapter transcripts into the transcript of the whole video
+func chaptersTranscript(chapters []models.Chapter) string {
+	texts := make([]string, len(chapters))
+	for i, chapter := range chapters {
+		texts[i] = chapter.Transcript
+	}
+	return strings.Join(texts, " ")
+}
+
+// clipChapterBackgrounds cuts the separated accompaniment into the ranges of the chapters
+// On failure no chapter keeps a background and the whole audio track is replaced, as when separation fails.
+func clipChapterBackgrounds(ctx context.Context, jobID string, backgroundPath string, chapters []models.Chapter) {
+	ext := filepath.Ext(backgroundPath)
+	err := forEachChapter(ctx, len(chapters), func(ctx context.Context, i int) error {
+		path, err := createTempFile(fmt.Sprintf("background_%s_%d_*%s", jobID, i, ext))
+		if err != nil {
+			return err
+		}
+		chapters[i].BackgroundPath = path
+		end := chapters[i].End
+		if i == len(chapters)-1 {
+			end = 0 // Keep the tail of the background however the chapter durations were rounded
+		}
+		return ffmpegPool.Do(ctx, func() error {
+			return video.ClipVideo(ctx, backgroundPath, chapters[i].Start, end, path)
+		})
+	})
+	if err != nil {
+		slog.Warn("Failed to cut background music into chapters, replacing the whole audio track", "error", err, "jobID", jobID)
+		for i := range chapters {
+			removeTempFile(jobID, chapters[i].BackgroundPath)
+			chapters[i].BackgroundPath = ""
+		}
+		jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+			status.Warnings = append(status.Warnings, "background music could not be cut into chapters; the original audio is fully replaced: "+err.Error())
+		})
+	}
+}
+
+// translateChapters translates each chapter on its own, reusing Gemini pipeline translations when present
+// Returns one translated text per chapter and how many sentences were reused from the translation memory.
+func translateChapters(ctx context.Context, req *models.TranslateRequest, memory *translation.Memory, checkpoint *models.JobCheckpoint, targetLanguage string) ([]string, int, error) {
+	segments := make([]string, len(checkpoint.Chapters))
+	reused := make([]int, len(checkpoint.Chapters))
+	err := forEachChapter(ctx, len(checkpoint.Chapters), func(ctx context.Context, i int) error {
+		chapter := checkpoint.Chapters[i]
+		if translated, ok := chapter.Translations[targetLanguage]; ok {
+			segments[i] = translated
+			return nil
+		}
+		sentences, reusedSentences, err := memory.Translate(ctx, chapter.Transcript, checkpoint.SourceLanguage, targetLanguage, jobTranslateFunc(req))
+		if err != nil {
+			return err
+		}
+		segments[i] = strings.Join(sentences, " ")
+		reused[i] = reusedSentences
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := 0
+	for _, count := range reused {
+		total += count
+	}
+	return segments, total, nil
+}
+
+// chapterCues estimates subtitle timings within each chapter from its translated text
+func chapterCues(chapters []models.Chapter, segments []string) []subtitles.Cue {
+	var cues []subtitles.Cue
+	for i, chapter := range chapters {
+		for _, cue := range subtitles.EstimateCues(translation.SplitSentences(segments[i]), chapter.End-chapter.Start) {
+			cue.Start += chapter.Start
+			cue.End += chapter.Start
+			cues = append(cues, cue)
+		}
+	}
+	return cues
+}
+
+// synthesizeChapters generates the speech of each chapter in parallel, at the rate fitting the chapter, and verifies
+// its duration like whole-video speech. The chapter audio files are returned for the mux and joined into audioPath
+// for the dubbed audio outputs; the duration corrections and total drift of the chapters are recorded on result.
+func synthesizeChapters(ctx context.Context, jobID string, checkpoint *models.JobCheckpoint, targetLanguage string, speechSegments []string, opts tts.Options, audioPath string, result *models.LanguageResult) ([]string, error) {
+	paths := make([]string, len(checkpoint.Chapters))
+	chapterResults := make([]*models.LanguageResult, len(checkpoint.Chapters))
+	err := forEachChapter(ctx, len(checkpoint.Chapters), func(ctx context.Context, i int) error {
+		path, err := createTempFile(fmt.Sprintf("audio_%s_%s_%d_*.mp3", jobID, targetLanguage, i))
+		if err != nil {
+			return err
+		}
+		paths[i] = path
+
+		duration := checkpoint.Chapters[i].End - checkpoint.Chapters[i].Start
+		synthesize := func(rateScale float64) error {
+			chapterOpts := opts
+			chapterOpts.RateScale = rateScale
+			return tts.GenerateTTSWithOptions(ctx, speechSegments[i], targetLanguage, duration, path, chapterOpts)
+		}
+		if err := apiPool.Do(ctx, func() error { return synthesize(0) }); err != nil {
+			return err
+		}
+		trimSpeechSilence(ctx, jobID, targetLanguage, path)
+		chapterResults[i] = &models.LanguageResult{}
+		verifyDubbingDuration(ctx, jobID, targetLanguage, path, duration, false, synthesize, chapterResults[i])
+		return nil
+	})
+	if err == nil {
+		err = ffmpegPool.Do(ctx, func() error {
+			return video.ConcatMedia(ctx, paths, audioPath)
+		})
+	}
+	if err != nil {
+		removeChapterFiles(jobID, paths)
+		return nil, err
+	}
+
+	var drift float64
+	measured := true
+	for _, chapterResult := range chapterResults {
+		for _, correction := range chapterResult.DurationCorrection {
+			if !slices.Contains(result.DurationCorrection, correction) {
+				result.DurationCorrection = append(result.DurationCorrection, correction)
+			}
+		}
+		if chapterResult.DurationDrift == nil {
+			measured = false
+		} else {
+			drift += *chapterResult.DurationDrift
+		}
+	}
+	if measured {
+		result.DurationDrift = &drift
+	}
+	return paths, nil
+}
+
+// muxChapters muxes the speech of each chapter into the chapter video in parallel and joins the dubbed chapters
+func muxChapters(ctx context.Context, jobID string, checkpoint *models.JobCheckpoint, chapterAudio []string, outputPath string, opts video.SyncOptions) error {
+	paths := make([]string, len(checkpoint.Chapters))
+	defer removeChapterFiles(jobID, paths)
+
+	err := forEachChapter(ctx, len(checkpoint.Chapters), func(ctx context.Context, i int) error {
+		chapter := checkpoint.Chapters[i]
+		path, err := createTempFile(fmt.Sprintf("video_%s_%s_%d_*%s", jobID, opts.AudioLanguage, i, filepath.Ext(chapter.VideoPath)))
+		if err != nil {
+			return err
+		}
+		paths[i] = path
+
+		chapterOpts := opts
+		chapterOpts.BackgroundPath = chapter.BackgroundPath
+		return muxDubbedVideo(ctx, jobID, chapter.VideoPath, chapterAudio[i], path, checkpoint.Video, chapterOpts)
+	})
+	if err != nil {
+		return err
+	}
+	return ffmpegPool.Do(ctx, func() error {
+		return video.ConcatMedia(ctx, paths, outputPath)
+	})
+}
+
+// removeChapterFiles deletes the per-chapter temp files of a language
+func removeChapterFiles(jobID string, paths []string) {
+	for _, path := range paths {
+		removeTempFile(jobID, path)
+	}
+}
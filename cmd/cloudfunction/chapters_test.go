@@ -0,0 +1,153 @@
+//go:build !integration
+// +build !integration
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/diskspace"
+	"github.com/sinouw/multilingual-video-processor/internal/tts"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestUseChapters(t *testing.T) {
+	ensureTestConfig(t)
+	previousChapter, previousMax := cfg.ChapterDuration, cfg.MaxChapteredDuration
+	t.Cleanup(func() { cfg.ChapterDuration, cfg.MaxChapteredDuration = previousChapter, previousMax })
+	cfg.ChapterDuration, cfg.MaxChapteredDuration = 10*time.Minute, 2*time.Hour
+
+	transcribed := &models.TranslateRequest{VideoURL: "gs://bucket/video.mp4"}
+	if !useChapters(transcribed, 3600) {
+		t.Error("expected a transcribed hour-long video to use chapters")
+	}
+	if useChapters(transcribed, 3*3600) {
+		t.Error("expected a video over MAX_CHAPTERED_VIDEO_DURATION not to use chapters")
+	}
+	if useChapters(&models.TranslateRequest{SourceText: "Hello."}, 3600) {
+		t.Error("expected supplied source text not to be split into chapters")
+	}
+	if useChapters(&models.TranslateRequest{SubtitleURL: "gs://bucket/video.srt"}, 3600) {
+		t.Error("expected supplied subtitles not to be split into chapters")
+	}
+
+	cfg.ChapterDuration = 0
+	if useChapters(transcribed, 3600) {
+		t.Error("expected no chapters without CHAPTER_DURATION")
+	}
+}
+
+func TestForEachChapter(t *testing.T) {
+	var ran atomic.Int32
+	err := forEachChapter(context.Background(), 4, func(ctx context.Context, i int) error {
+		ran.Add(1)
+		return nil
+	})
+	if err != nil || ran.Load() != 4 {
+		t.Errorf("expected 4 chapters to run without error, got %d: %v", ran.Load(), err)
+	}
+
+	// The first failure cancels the other chapters
+	failure := errors.New("chapter failed")
+	err = forEachChapter(context.Background(), 3, func(ctx context.Context, i int) error {
+		if i == 0 {
+			return failure
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("expected the chapter's error, got %v", err)
+	}
+}
+
+func TestChapterCues(t *testing.T) {
+	chapters := []models.Chapter{{Start: 0, End: 10}, {Start: 10, End: 30}}
+	cues := chapterCues(chapters, []string{"Hello there. Goodbye.", "The second chapter."})
+
+	if len(cues) != 3 {
+		t.Fatalf("expected 3 cues, got %d: %+v", len(cues), cues)
+	}
+	for _, cue := range cues[:2] {
+		if cue.Start < 0 || cue.End > 10 {
+			t.Errorf("expected the first chapter's cues within 0-10s, got %+v", cue)
+		}
+	}
+	if cues[2].Start < 10 || cues[2].End > 30 || cues[2].Text != "The second chapter." {
+		t.Errorf("expected the second chapter's cue within 10-30s, got %+v", cues[2])
+	}
+}
+
+func TestSynthesizeChapters(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ffmpeg is a shell script")
+	}
+	ensureTestConfig(t)
+	if !cfg.DevMockProviders {
+		t.Skip("requires DEV_MOCK_PROVIDERS=true")
+	}
+
+	// The fake ffmpeg writes its output, the last argument, as a concatenation would
+	bin := t.TempDir()
+	script := "#!/bin/sh\nfor last; do :; done\nprintf joined > \"$last\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "ffmpeg"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake ffmpeg: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	checkpoint := &models.JobCheckpoint{Chapters: []models.Chapter{{Start: 0, End: 10}, {Start: 10, End: 25}}}
+	audioPath := filepath.Join(t.TempDir(), "audio.mp3")
+	result := &models.LanguageResult{}
+	paths, err := synthesizeChapters(context.Background(), "chapters-job", checkpoint, "de", []string{"Hallo.", "Tschüss."}, tts.Options{}, audioPath, result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer removeChapterFiles("chapters-job", paths)
+
+	if len(paths) != 2 {
+		t.Fatalf("expected one audio file per chapter, got %v", paths)
+	}
+	for _, path := range paths {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("expected synthesized chapter audio at %s: %v", path, err)
+		}
+	}
+	if data, err := os.ReadFile(audioPath); err != nil || string(data) != "joined" {
+		t.Errorf("expected the chapters joined into %s, got %q: %v", audioPath, data, err)
+	}
+}
+
+func TestReserveChapterDiskSpace(t *testing.T) {
+	ensureTestConfig(t)
+	previous := diskTracker
+	t.Cleanup(func() { diskTracker = previous })
+	diskTracker = diskspace.NewTracker(t.TempDir(), 0)
+
+	videoPath := filepath.Join(t.TempDir(), "video.mp4")
+	if err := os.WriteFile(videoPath, make([]byte, 1000), 0644); err != nil {
+		t.Fatalf("failed to write video: %v", err)
+	}
+	if err := diskTracker.Reserve("chaptered", 3000); err != nil {
+		t.Skipf("free disk space unavailable: %v", err)
+	}
+	defer diskTracker.Release("chaptered")
+
+	if !reserveChapterDiskSpace(context.Background(), "chaptered", 3000, videoPath) {
+		t.Fatal("expected the reservation to grow")
+	}
+	if got, want := diskTracker.Reserved(), int64(3000+chapterDiskFactor*1000); got != want {
+		t.Errorf("expected %d bytes reserved, got %d", want, got)
+	}
+
+	// A skipped reservation stays skipped
+	if !reserveChapterDiskSpace(context.Background(), "unreserved", 0, videoPath) || diskTracker.Reserved() != 3000+chapterDiskFactor*1000 {
+		t.Errorf("expected no reservation for a job without one, got %d bytes reserved", diskTracker.Reserved())
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/sinouw/multilingual-video-processor/internal/api"
+	"github.com/sinouw/multilingual-video-processor/internal/storage"
+	"github.com/sinouw/multilingual-video-processor/internal/utils"
+	"github.com/sinouw/multilingual-video-processor/internal/validator"
+	"github.com/sinouw/multilingual-video-processor/internal/video"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// handleInspect probes a video and reports its media info and whether a translation job would accept it
+// Videos over MAX_VIDEO_SIZE_MB are reported from their size alone, without downloading them; others are probed
+// from their first inspectProbeBytes when possible.
+func handleInspect(w http.ResponseWriter, r *http.Request) {
+	requestID := utils.GenerateUUID()
+
+	r.Body = http.MaxBytesReader(w, r.Body, cfg.MaxRequestBodySize)
+
+	var req models.InspectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "invalid request body: "+err.Error(), requestID)
+		return
+	}
+	if err := validator.ValidateVideoURL(req.VideoURL); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error(), requestID)
+		return
+	}
+
+	// The video is read with the identity a job for it would use, so inspection cannot reach other tenants' objects
+	source := &models.TranslateRequest{VideoURL: req.VideoURL, ServiceAccount: req.ServiceAccount}
+	if err := resolveServiceAccount(r, source); err != nil {
+		api.CodedErrorResponse(w, http.StatusForbidden, "service_account_not_allowed", err.Error(), requestID, map[string]interface{}{
+			"serviceAccount": source.ServiceAccount,
+		})
+		return
+	}
+
+	slog.Info("Inspect request", "requestID", requestID, "videoUrl", req.VideoURL, "serviceAccount", source.ServiceAccount)
+
+	ctx := r.Context()
+	inspected, err := openInspectedVideo(ctx, source)
+	if errors.Is(err, storage.ErrObjectNotFound) || errors.Is(err, storage.ErrSourceUnreachable) {
+		api.CodedErrorResponse(w, http.StatusNotFound, "video_not_found", "video not found", requestID, nil)
+		return
+	}
+	if errors.Is(err, storage.ErrNotVideo) {
+		api.CodedErrorResponse(w, http.StatusUnprocessableEntity, "unreadable_video", err.Error(), requestID, nil)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to read video", "error", err, "requestID", requestID)
+		api.ErrorResponse(w, http.StatusBadGateway, "failed to read video", requestID)
+		return
+	}
+	size := inspected.size
+
+	response := &models.InspectResponse{VideoURL: req.VideoURL, SizeBytes: max(size, 0)}
+	if problems := validator.CheckMediaLimits(nil, size, cfg); len(problems) > 0 {
+		response.Problems = problems
+		writeInspectResponse(w, response, requestID)
+		return
+	}
+
+	// Most containers describe their streams in their leading bytes, so only those are fetched at first. Videos
+	// whose headers are at the end (e.g. MP4s without faststart) are downloaded whole when the prefix cannot be probed.
+	response.Media, err = probeInspectedVideo(ctx, requestID, func() (string, error) {
+		return inspected.downloadPrefix(ctx, inspectProbeBytes)
+	})
+	if errors.Is(err, errUnreadableVideo) && (size < 0 || size > inspectProbeBytes) {
+		response.Media, err = probeInspectedVideo(ctx, requestID, func() (string, error) {
+			return inspected.download(ctx)
+		})
+	}
+	if errors.Is(err, errUnreadableVideo) {
+		slog.Warn("Failed to probe video", "error", err, "requestID", requestID)
+		api.CodedErrorResponse(w, http.StatusUnprocessableEntity, "unreadable_video", "video could not be probed; it may not be a media file", requestID, nil)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to download video", "error", err, "requestID", requestID)
+		api.ErrorResponse(w, http.StatusBadGateway, "failed to download video", requestID)
+		return
+	}
+
+	response.Problems = validator.CheckMediaLimits(response.Media, size, cfg)
+	writeInspectResponse(w, response, requestID)
+}
+
+// inspectProbeBytes is how much of a video is downloaded to probe it before falling back to the whole video
+const inspectProbeBytes = 16 << 20
+
+// errUnreadableVideo is returned by probeInspectedVideo when ffprobe cannot read the downloaded video
+var errUnreadableVideo = errors.New("video could not be probed")
+
+// inspectedVideo reads a video to inspect, from Cloud Storage or over HTTPS
+type inspectedVideo struct {
+	size           int64 // Size in bytes, -1 when an HTTPS source does not report it
+	downloadPrefix func(ctx context.Context, length int64) (string, error)
+	download       func(ctx context.Context) (string, error)
+}
+
+// openInspectedVideo looks up the size of a request's video and how to download it, reading Cloud Storage objects
+// with the request's storage identity and HTTPS sources from public addresses only
+func openInspectedVideo(ctx context.Context, req *models.TranslateRequest) (*inspectedVideo, error) {
+	if storage.IsHTTPSource(req.VideoURL) {
+		headCtx, cancel := context.WithTimeout(ctx, sourceCheckTimeout)
+		defer cancel()
+		info, err := storage.HeadSource(headCtx, sourceHTTPClient, req.VideoURL)
+		if err != nil {
+			return nil, err
+		}
+		return &inspectedVideo{
+			size: info.Size,
+			downloadPrefix: func(ctx context.Context, length int64) (string, error) {
+				return storage.DownloadHTTPPrefix(ctx, sourceHTTPClient, req.VideoURL, length)
+			},
+			download: func(ctx context.Context) (string, error) {
+				return storage.DownloadHTTP(ctx, sourceHTTPClient, req.VideoURL, maxSourceBytes())
+			},
+		}, nil
+	}
+
+	bucket, path, err := storage.ParseGCSURL(req.VideoURL)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := sourceStorage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	size, err := reader.Size(ctx, bucket, path)
+	if err != nil {
+		return nil, err
+	}
+	inspected := &inspectedVideo{
+		size: size,
+		download: func(ctx context.Context) (string, error) {
+			return reader.Download(ctx, bucket, path)
+		},
+	}
+	inspected.downloadPrefix = func(ctx context.Context, length int64) (string, error) {
+		if prefixes, ok := reader.(storage.PrefixDownloader); ok {
+			return prefixes.DownloadPrefix(ctx, bucket, path, length)
+		}
+		return inspected.download(ctx)
+	}
+	return inspected, nil
+}
+
+// probeInspectedVideo downloads a video with download and probes it, wrapping probe failures in errUnreadableVideo
+func probeInspectedVideo(ctx context.Context, requestID string, download func() (string, error)) (*models.MediaInfo, error) {
+	videoPath, err := download()
+	if err != nil {
+		return nil, err
+	}
+	defer removeTempFile(requestID, videoPath)
+
+	var media *models.MediaInfo
+	err = ffmpegPool.Do(ctx, func() (err error) {
+		media, err = video.ProbeMedia(ctx, videoPath)
+		return err
+	})
+	if err != nil && ctx.Err() == nil {
+		return nil, fmt.Errorf("%w: %v", errUnreadableVideo, err)
+	}
+	return media, err
+}
+
+// writeInspectResponse marks the response valid when no problems were found and writes it
+func writeInspectResponse(w http.ResponseWriter, response *models.InspectResponse, requestID string) {
+	response.Valid = len(response.Problems) == 0
+	slog.Info("Video inspected", "requestID", requestID, "valid", response.Valid, "problems", len(response.Problems))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("Failed to encode response", "error", err, "requestID", requestID)
+	}
+}
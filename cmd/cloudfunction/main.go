@@ -3,34 +3,109 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"math"
 	"net/http"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+	"github.com/sinouw/multilingual-video-processor/internal/analysis"
 	"github.com/sinouw/multilingual-video-processor/internal/api"
+	"github.com/sinouw/multilingual-video-processor/internal/archive"
+	"github.com/sinouw/multilingual-video-processor/internal/cache"
 	"github.com/sinouw/multilingual-video-processor/internal/config"
+	"github.com/sinouw/multilingual-video-processor/internal/diskspace"
+	"github.com/sinouw/multilingual-video-processor/internal/gemini"
+	"github.com/sinouw/multilingual-video-processor/internal/instance"
+	"github.com/sinouw/multilingual-video-processor/internal/mock"
+	"github.com/sinouw/multilingual-video-processor/internal/moderation"
+	"github.com/sinouw/multilingual-video-processor/internal/notification"
+	"github.com/sinouw/multilingual-video-processor/internal/outbound"
+	"github.com/sinouw/multilingual-video-processor/internal/preview"
+	"github.com/sinouw/multilingual-video-processor/internal/separation"
 	"github.com/sinouw/multilingual-video-processor/internal/storage"
 	stt "github.com/sinouw/multilingual-video-processor/internal/stt"
+	"github.com/sinouw/multilingual-video-processor/internal/subtitles"
+	"github.com/sinouw/multilingual-video-processor/internal/tasks"
+	"github.com/sinouw/multilingual-video-processor/internal/textproc"
+	"github.com/sinouw/multilingual-video-processor/internal/transcode"
+	"github.com/sinouw/multilingual-video-processor/internal/transcript"
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
 	"github.com/sinouw/multilingual-video-processor/internal/translation"
 	"github.com/sinouw/multilingual-video-processor/internal/tts"
+	"github.com/sinouw/multilingual-video-processor/internal/usage"
 	"github.com/sinouw/multilingual-video-processor/internal/utils"
 	"github.com/sinouw/multilingual-video-processor/internal/validator"
 	"github.com/sinouw/multilingual-video-processor/internal/video"
+	"github.com/sinouw/multilingual-video-processor/internal/workerpool"
 	"github.com/sinouw/multilingual-video-processor/pkg/models"
 )
 
 var (
-	cfg           *config.Config
-	storageClient *storage.GCSStorage
-	jobStore      *api.InMemoryJobStore
-	rateLimiter   *api.RateLimiter
+	cfg               *config.Config
+	storageClient     storage.Storage
+	jobStore          *api.InMemoryJobStore
+	runningJobs       *api.JobRegistry
+	ffmpegPool        *workerpool.Pool
+	apiPool           *workerpool.Pool
+	webhookPool       *workerpool.Pool
+	notificationQueue *notification.Queue
+	rateLimiter       *api.RateLimiter
+	inFlightLimiter   *api.InFlightLimiter
+	spendBudget       *usage.Budget
+	duplicateDetector *api.DuplicateDetector
+	resultIndex       *api.ResultIndex
+	authenticator     *api.APIKeyAuthenticator
+	ipFilter          *api.IPFilter
+	translators       *translation.Registry
+	voiceRoutes       map[string][]*tts.CustomVoice
+	resultCache       cache.Store
+	translationMemory *cache.TranslationMemory
+	emailSender       notification.EmailSender
+	notifiers         []notification.Notifier
+	progressTracker   *notification.ProgressTracker
+	webhookChanges    *notification.ChangeTracker
+	retryScheduler    *tasks.CloudTasksScheduler
+	capabilities      *models.CapabilitiesResponse
+	separator         separation.Separator
+	remoteMuxer       transcode.Muxer
+	geminiClient      *gemini.Client
+	diskTracker       *diskspace.Tracker
+	instanceJobCap    int
+	textProcessors    textproc.Pipeline
+
+	// writableBuckets caches bucket write checks (bucket -> time checked)
+	writableBuckets sync.Map
 )
 
+// writableBucketTTL is how long a successful bucket write check is trusted
+const writableBucketTTL = 10 * time.Minute
+
+// diskPollInterval is how often a job waiting for temp disk space re-checks the free space
+const diskPollInterval = 15 * time.Second
+
+// sourceCheckTimeout bounds the HEAD request checking an HTTPS source
+const sourceCheckTimeout = 15 * time.Second
+
+// sourceHTTPClient fetches HTTPS sources, which are supplied by callers, from public addresses only;
+// downloads are bounded by the job context
+var sourceHTTPClient = outbound.NewPublicClient(0)
+
+// instanceBusyRetryAfter is the Retry-After sent when the instance already runs its job cap
+const instanceBusyRetryAfter = 30 * time.Second
+
 func init() {
 	var err error
 
@@ -50,17 +125,215 @@ func init() {
 
 	// Initialize storage client
 	ctx := context.Background()
-	storageClient, err = storage.NewGCSStorage(ctx)
+	storageClient, err = newStorage(ctx, cfg)
 	if err != nil {
 		slog.Error("Failed to initialize storage client", "error", err)
 		os.Exit(1)
 	}
 
+	// Outbound proxy and User-Agent for translation providers, webhooks and HTTPS sources
+	if err := outbound.Configure(outbound.Options{ProxyURL: cfg.OutboundProxy, UserAgent: cfg.OutboundUserAgent}); err != nil {
+		slog.Error("Failed to configure outbound requests", "error", err)
+		os.Exit(1)
+	}
+
+	// Custom provider endpoints, e.g. fake servers in integration tests
+	translation.SetGoogleEndpoint(cfg.GoogleTranslateEndpoint)
+	stt.SetEndpoint(cfg.SpeechEndpoint)
+	tts.SetEndpoint(cfg.TTSEndpoint)
+
+	// ElevenLabs speaks requests dubbed in a cloned voice (voiceId)
+	if cfg.ElevenLabsAPIKey != "" {
+		elevenLabs, err := tts.NewElevenLabsSynthesizer(cfg.ElevenLabsAPIKey, cfg.ElevenLabsModel)
+		if err != nil {
+			slog.Error("Failed to initialize ElevenLabs", "error", err)
+			os.Exit(1)
+		}
+		tts.SetElevenLabs(elevenLabs)
+	}
+
+	// Mock providers run the pipeline locally without GCP credentials
+	if cfg.DevMockProviders {
+		slog.Warn("DEV_MOCK_PROVIDERS is enabled: using mock speech, translation and TTS providers and local storage",
+			"storageDir", cfg.DevStorageDir)
+		stt.SetRecognizer(mock.Recognize)
+		tts.SetSynthesizer(mock.ProviderName, mock.Synthesize)
+	}
+
 	// Initialize job store with TTL
 	jobStore = api.NewInMemoryJobStore(cfg.JobTTL)
+	jobStore.SetExpireHook(releaseExpiredCheckpoint)
+	jobStore.SetStallHook(cfg.StalledJobAfter(), finishStalledJob)
+	jobStore.SetMaxJobs(cfg.JobStoreMaxEntries)
+	jobStore.SetCompactFinished(cfg.JobStoreCompact)
+	jobStore.SetTextOffloadThreshold(cfg.TextOffloadThreshold)
+
+	// Track running jobs so they can be cancelled from the admin API
+	runningJobs = api.NewJobRegistry()
+
+	// Bound ffmpeg processes (CPU-bound) and external API calls (IO-bound) across all jobs
+	ffmpegPool = workerpool.New("ffmpeg", cfg.MaxConcurrentFFmpeg)
+	apiPool = workerpool.New("api", cfg.MaxConcurrentAPICalls)
+	webhookPool = workerpool.New("webhook", cfg.WebhookConcurrency)
+	notificationQueue = notification.NewQueue(cfg.WebhookConcurrency, cfg.WebhookQueueSize)
+
+	// Bound the threads, memory and priority of each ffmpeg process so concurrent muxes share the CPUs
+	video.SetProcessLimits(video.ProcessLimits{
+		Threads:  cfg.FFmpegThreads,
+		MemoryMB: cfg.FFmpegMemoryMB,
+		Nice:     cfg.FFmpegNice,
+	})
+
+	// Reserve temp disk space per job so concurrent downloads cannot fill the workspace
+	if cfg.IsDiskSpaceCheckEnabled() {
+		diskTracker = diskspace.NewTracker(os.TempDir(), int64(cfg.DiskSpaceHeadroomMB)<<20)
+	}
+
+	// Optionally cap jobs per instance so an autoscaled container is not oversubscribed (0 for no cap)
+	resources := instance.Detect()
+	instanceJobCap = cfg.MaxInstanceJobs
+	if instanceJobCap == config.InstanceJobsAuto {
+		instanceJobCap = resources.JobCap(int64(cfg.JobMemoryMB) << 20)
+	}
+	slog.Info("Instance resources detected",
+		"cpus", resources.CPUs,
+		"memoryBytes", resources.MemoryBytes,
+		"cloudRunService", resources.Service,
+		"cloudRunRevision", resources.Revision,
+		"maxJobs", instanceJobCap,
+		"cpuAlwaysAllocated", cfg.CPUAlwaysAllocated)
 
 	// Initialize rate limiter
 	rateLimiter = api.NewRateLimiter(cfg.RateLimitRPM)
+	rateLimiter.SetScopeLimit(api.RateLimitScopeSubmit, cfg.RateLimitRPM)
+	rateLimiter.SetScopeLimit(api.RateLimitScopeStatus, cfg.RateLimitStatusRPM)
+	for name, tier := range cfg.RateLimitTiers {
+		rateLimiter.SetTierLimit(name, api.RateLimitScopeSubmit, tier.SubmitRPM)
+		rateLimiter.SetTierLimit(name, api.RateLimitScopeStatus, tier.StatusRPM)
+	}
+	inFlightLimiter = api.NewInFlightLimiter(cfg.MaxInFlightJobsPerClient)
+
+	// Initialize the spend guard (disabled when no SPEND_BUDGET_* limit is set)
+	budgetLimits := usage.BudgetLimits{
+		Cost:                cfg.SpendBudgetCost,
+		STTSeconds:          cfg.SpendBudgetSTTSeconds,
+		TranslateCharacters: cfg.SpendBudgetTranslateChars,
+		TTSCharacters:       cfg.SpendBudgetTTSChars,
+	}
+	if budgetLimits.Enabled() {
+		spendBudget = usage.NewBudget(budgetLimits, cfg.SpendBudgetWindow, cfg.PriceTable())
+	}
+
+	// Initialize API key authentication (disabled when no keys are configured)
+	authenticator = api.NewAPIKeyAuthenticator(cfg.APIKeys, cfg.AdminAPIKeys)
+	authenticator.SetTiers(cfg.APIKeyTiers)
+	authenticator.SetServiceAccounts(cfg.APIKeyServiceAccounts)
+
+	// Restrict API clients to known networks (disabled when no networks are configured)
+	ipFilter, err = api.NewIPFilter(cfg.IPAllowlist, cfg.IPDenylist, cfg.IPFilterProxyHops)
+	if err != nil {
+		slog.Error("Failed to initialize IP filter", "error", err)
+		os.Exit(1)
+	}
+
+	// Initialize duplicate job detection
+	duplicateDetector = api.NewDuplicateDetector(cfg.DuplicateJobWindow)
+	resultIndex = api.NewResultIndex(cfg.ResultReuseWindow)
+
+	// Initialize translation providers and per-language routing
+	translators, err = newTranslators(cfg)
+	if err != nil {
+		slog.Error("Failed to initialize translation providers", "error", err)
+		os.Exit(1)
+	}
+	voiceRoutes, err = newVoiceRoutes(cfg)
+	if err != nil {
+		slog.Error("Failed to initialize TTS routes", "error", err)
+		os.Exit(1)
+	}
+
+	// Rewrite translations for the target locale and for speech between translation and TTS
+	textProcessors, err = textproc.New(cfg.TextProcessors)
+	if err != nil {
+		slog.Error("Failed to initialize text processors", "error", err)
+		os.Exit(1)
+	}
+	registerSpeechAcronyms(cfg.SpeechAcronyms)
+
+	// The experimental Gemini pipeline transcribes and translates short clips in one call
+	if cfg.GeminiPipeline {
+		geminiClient, err = gemini.NewClient(ctx, cfg.GeminiProject, cfg.GeminiLocation, cfg.GeminiModel)
+		if err != nil {
+			slog.Error("Failed to initialize Gemini client", "error", err)
+			os.Exit(1)
+		}
+		slog.Warn("GEMINI_PIPELINE is enabled: short clips are transcribed and translated with Gemini",
+			"model", geminiClient.Model, "maxDuration", cfg.GeminiMaxDuration)
+	}
+
+	// Initialize the source separation backend used to keep background music
+	if cfg.IsAudioSeparationEnabled() {
+		separator, err = separation.New(cfg.AudioSeparation, cfg.AudioSeparationCommand, cfg.AudioSeparationEndpoint, cfg.AudioSeparationModel)
+		if err != nil {
+			slog.Error("Failed to initialize audio separation", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Delegate muxing of large videos to a remote worker or the Transcoder API instead of local ffmpeg
+	if cfg.IsTranscodeOffloadEnabled() {
+		remoteMuxer, err = transcode.New(ctx, cfg.TranscodeBackend, transcode.Options{
+			Endpoint: cfg.TranscodeEndpoint,
+			Project:  cfg.TranscodeProject,
+			Location: cfg.TranscodeLocation,
+			Bucket:   cfg.TranscodeBucket,
+			Storage:  storageClient,
+		})
+		if err != nil {
+			slog.Error("Failed to initialize transcode backend", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Initialize the translation and TTS cache
+	resultCache, err = newCache(cfg)
+	if err != nil {
+		slog.Error("Failed to initialize cache", "error", err)
+		os.Exit(1)
+	}
+	if resultCache != nil {
+		tts.SetCache(resultCache)
+	}
+	translationMemory, err = newTranslationMemory(cfg)
+	if err != nil {
+		slog.Error("Failed to initialize translation memory", "error", err)
+		os.Exit(1)
+	}
+
+	// Initialize notification channels
+	emailSender = newEmailSender(cfg)
+	notifiers, err = newNotifiers(ctx, cfg)
+	if err != nil {
+		slog.Error("Failed to initialize notifiers", "error", err)
+		os.Exit(1)
+	}
+	progressTracker = notification.NewProgressTracker()
+	webhookChanges = notification.NewChangeTracker()
+
+	// Re-enqueue jobs that fail on transient provider errors (disabled without a queue)
+	if cfg.IsTransientRetryEnabled() {
+		retryScheduler, err = tasks.NewCloudTasksScheduler(ctx, cfg.CloudTasksQueue, cfg.CloudTasksTargetURL, cfg.CloudTasksToken)
+		if err != nil {
+			slog.Error("Failed to initialize Cloud Tasks scheduler", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Describe this deployment for /v1/capabilities
+	capabilities = buildCapabilities(cfg, translators, notifiers)
+
+	// Report build, ffmpeg versions and backends on /health
+	api.SetBuildInfo(buildBuildInfo(ctx, cfg, capabilities))
 
 	slog.Info("Application initialized successfully")
 }
@@ -88,40 +361,169 @@ func TranslateVideo(w http.ResponseWriter, r *http.Request) {
 	case "/health/live":
 		api.LivenessHandler(w, r)
 		return
+	case "/health/startup":
+		api.StartupHandler(startupCheck)(w, r)
+		return
+	case "/v1/capabilities":
+		api.CapabilitiesHandler(capabilities)(w, r)
+		return
+	case tasks.RetryPath:
+		// Cloud Tasks authenticates with the task token rather than an API key
+		if retryScheduler == nil {
+			api.ErrorResponse(w, http.StatusNotFound, "endpoint not found", "")
+			return
+		}
+		api.TaskRetryHandler(jobStore, cfg.CloudTasksToken, admitRetry, ownerJobLimit, retryLanguages, restartJob)(w, r)
+		return
+	}
+
+	// Everything else is limited to the allowed networks, then requires an API key when auth is enabled,
+	// and an admin key for /admin and /metrics
+	ipFilter.Middleware(authenticator.Middleware(routeAuthenticated))(w, r)
+}
+
+// routeAuthenticated routes requests that passed the API key and role checks
+func routeAuthenticated(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/admin/") {
+		api.AdminHandler(adminOperations())(w, r)
+		return
+	}
+
+	if r.URL.Path == "/metrics" {
+		api.MetricsHandler(adminOperations())(w, r)
+		return
+	}
+
+	if r.URL.Path == "/v1/jobs" {
+		if !allowRequest(w, r, api.RateLimitScopeStatus) {
+			return
+		}
+		api.JobsHandler(jobStore)(w, r)
+		return
 	}
 
 	if strings.HasPrefix(r.URL.Path, "/v1/status/") {
+		if !allowRequest(w, r, api.RateLimitScopeStatus) {
+			return
+		}
 		api.StatusHandler(jobStore)(w, r)
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/v1/jobs/") && strings.HasSuffix(r.URL.Path, "/retry") {
+		api.RetryHandler(jobStore, admitRetry, ownerJobLimit, retryLanguages)(w, r)
+		return
+	}
+
+	// Exports may zip every output within the request, so they count like submissions
+	if strings.HasPrefix(r.URL.Path, "/v1/jobs/") && strings.HasSuffix(r.URL.Path, "/export") {
+		if !allowRequest(w, r, api.RateLimitScopeSubmit) {
+			return
+		}
+		api.ExportHandler(jobStore, exportJob)(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/v1/jobs/") && strings.HasSuffix(r.URL.Path, "/transcript") {
+		if !allowRequest(w, r, api.RateLimitScopeStatus) {
+			return
+		}
+		api.TranscriptHandler(jobStore)(w, r)
+		return
+	}
+
+	if r.URL.Path == "/v1/inspect" && r.Method == http.MethodPost {
+		if !allowRequest(w, r, api.RateLimitScopeSubmit) {
+			return
+		}
+		handleInspect(w, r)
+		return
+	}
+
 	if r.URL.Path == "/v1/translate" || r.URL.Path == "/translate" {
 		if r.Method == http.MethodPost {
-			// Apply rate limiting middleware
-			clientIP := api.GetClientIP(r)
-			if !rateLimiter.Allow(clientIP) {
-				api.ErrorResponse(w, http.StatusTooManyRequests, "rate limit exceeded", "")
+			if !allowRequest(w, r, api.RateLimitScopeSubmit) {
 				return
 			}
-			handleTranslate(w, r)
+			handleTranslate(w, r, models.APIVersionV1)
+			return
+		}
+	}
+
+	if r.URL.Path == "/v2/translate" && r.Method == http.MethodPost {
+		if !allowRequest(w, r, api.RateLimitScopeSubmit) {
 			return
 		}
+		handleTranslate(w, r, models.APIVersionV2)
+		return
 	}
 
 	api.ErrorResponse(w, http.StatusNotFound, "endpoint not found", "")
 }
 
-func handleTranslate(w http.ResponseWriter, r *http.Request) {
+// adminOperations exposes this instance's job store, workers and rate limiter to the admin API and metrics
+func adminOperations() api.AdminOperations {
+	return api.AdminOperations{
+		Store:       jobStore,
+		Jobs:        runningJobs,
+		RateLimiter: rateLimiter,
+		Pools:       []*workerpool.Pool{ffmpegPool, apiPool, webhookPool},
+		Cleanup:     jobStore.CleanupExpiredJobs,
+		FailJob:     failJob,
+
+		TranslationMemoryStats: translationMemoryStats(),
+	}
+}
+
+// translationMemoryStats returns the statistics function of the persistent translation memory, or nil if disabled
+func translationMemoryStats() func() (int64, int64) {
+	if translationMemory == nil {
+		return nil
+	}
+	return translationMemory.Stats
+}
+
+// allowRequest applies the rate limit of a scope to the client, setting rate limit headers
+// Authenticated clients are limited per API key owner at their tier's rates, others per IP address.
+// Writes a 429 response and returns false when the client is over the limit.
+func allowRequest(w http.ResponseWriter, r *http.Request, scope string) bool {
+	identifier, tier := clientIdentifier(r)
+	state := rateLimiter.TakeTier(scope, tier, identifier)
+	api.WriteRateLimitHeaders(w, state)
+	if !state.Allowed {
+		api.ErrorResponse(w, http.StatusTooManyRequests, "rate limit exceeded", "")
+		return false
+	}
+	return true
+}
+
+// clientIdentifier returns the identifier and tier limits apply to: the API key owner, or the IP address without a key
+// The address is read through IP_FILTER_PROXY_HOPS, so a client cannot pick a new one by writing X-Forwarded-For.
+func clientIdentifier(r *http.Request) (string, string) {
+	if principal := api.PrincipalFromRequest(r); principal != nil {
+		return api.RateLimitKeyIdentifier(principal.Owner), principal.Tier
+	}
+	return ipFilter.ClientIP(r), ""
+}
+
+// handleTranslate accepts a job in the request schema of the given API version
+// Every version is converted to a TranslateRequest, so jobs run the same pipeline whichever schema submitted them.
+func handleTranslate(w http.ResponseWriter, r *http.Request, version string) {
 	requestID := utils.GenerateUUID()
 
-	slog.Info("Translation request received", "requestID", requestID)
+	slog.Info("Translation request received", "requestID", requestID, "apiVersion", version)
 
 	// Limit request body size
 	r.Body = http.MaxBytesReader(w, r.Body, cfg.MaxRequestBodySize)
 
 	// Parse request
 	var req models.TranslateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	var reqV2 models.TranslateRequestV2
+	var body interface{ Validate() error } = &req
+	if version == models.APIVersionV2 {
+		body = &reqV2
+	}
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
 		slog.Error("Failed to parse request", "error", err, "requestID", requestID)
 		// Check if error is due to size limit
 		if err.Error() == "http: request body too large" {
@@ -133,467 +535,3071 @@ func handleTranslate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate request
-	if err := req.Validate(); err != nil {
+	if err := body.Validate(); err != nil {
 		slog.Error("Request validation failed", "error", err, "requestID", requestID)
 		api.ErrorResponse(w, http.StatusBadRequest, err.Error(), requestID)
 		return
 	}
+	if version == models.APIVersionV2 {
+		req = *reqV2.Request()
+	}
+
+	// Outputs go to the namespace and bucket of the API key owner, whose allowed output buckets are validated
+	if principal := api.PrincipalFromRequest(r); principal != nil {
+		req.Tenant = principal.Owner
+	}
 
 	if err := validator.ValidateTranslateRequest(&req, cfg); err != nil {
 		slog.Error("Request validation failed", "error", err, "requestID", requestID)
-		api.ErrorResponse(w, http.StatusBadRequest, err.Error(), requestID)
+		var limitErr *validator.LimitError
+		if errors.As(err, &limitErr) {
+			api.CodedErrorResponse(w, http.StatusBadRequest, limitErr.Code, err.Error(), requestID, map[string]interface{}{
+				"limit":  limitErr.Limit,
+				"actual": limitErr.Actual,
+			})
+		} else {
+			api.ErrorResponse(w, http.StatusBadRequest, err.Error(), requestID)
+		}
 		return
 	}
 
-	// Generate job ID
-	jobID := utils.GenerateUUID()
-
-	// Initialize job status
-	now := time.Now()
-	jobStatus := &models.StatusResponse{
-		JobID:     jobID,
-		Status:    models.StatusProcessing,
-		Results:   make(map[string]*models.LanguageResult),
-		CreatedAt: &now,
-		UpdatedAt: now,
+	// Sources are read as the request's service account, by default the one of its API key
+	if err := resolveServiceAccount(r, &req); err != nil {
+		slog.Warn("Service account impersonation refused", "error", err, "serviceAccount", req.ServiceAccount, "requestID", requestID)
+		api.CodedErrorResponse(w, http.StatusForbidden, "service_account_not_allowed", err.Error(), requestID, map[string]interface{}{
+			"serviceAccount": req.ServiceAccount,
+		})
+		return
 	}
 
-	jobStore.SetStatus(jobID, jobStatus)
-
-	// Return immediate response with job ID
-	response := models.TranslateResponse{
-		JobID:  jobID,
-		Status: models.StatusProcessing,
+	// Jobs belong to the API key owner that submitted them
+	owner := ""
+	if principal := api.PrincipalFromRequest(r); principal != nil {
+		owner = principal.Owner
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		slog.Error("Failed to encode response", "error", err, "requestID", requestID)
+	// Use the client's job ID if supplied, which makes resubmitting the same request a fetch
+	// The fetch comes before the source, destination and disk checks, which only matter for new jobs
+	// The ID is scoped to the API key owner, so other owners' jobs are never found here
+	jobID := api.ScopedJobID(owner, req.JobID)
+	if req.JobID == "" {
+		jobID = utils.GenerateUUID()
+	} else if existing, err := jobStore.GetStatus(jobID); err == nil {
+		respondExistingJob(w, existing, &req, owner, requestID)
 		return
 	}
 
-	// Start processing asynchronously (after response is sent)
-	// Use background context with timeout since request context will be cancelled after response
-	processCtx, processCancel := context.WithTimeout(context.Background(), cfg.RequestTimeout)
-	defer processCancel()
-	go processTranslation(processCtx, jobID, &req, jobStatus)
-}
-
-func processTranslation(ctx context.Context, jobID string, req *models.TranslateRequest, jobStatus *models.StatusResponse) {
-	slog.Info("Starting translation processing", "jobID", jobID)
+	// HTTPS sources must be reachable videos within the size limit before the job is accepted
+	if code, err := checkSubmittedSource(r.Context(), &req); err != nil {
+		slog.Warn("Source check failed", "error", err, "code", code, "requestID", requestID)
+		api.CodedErrorResponse(w, http.StatusBadRequest, code, err.Error(), requestID, map[string]interface{}{
+			"videoUrl": req.VideoURL,
+		})
+		return
+	}
 
-	// Track all temporary files for cleanup
-	tempFiles := []string{}
-	defer func() {
-		// Cleanup all temporary files
-		for _, file := range tempFiles {
-			if file != "" {
-				if err := os.Remove(file); err != nil {
-					// Log but don't fail if cleanup fails
-					slog.Warn("Failed to cleanup temp file", "file", file, "error", err, "jobID", jobID)
-				}
-			}
-		}
-		slog.Info("Temp file cleanup completed", "jobID", jobID, "filesCleaned", len(tempFiles))
-	}()
+	// Every output destination must be writable before the job is accepted
+	if destination, err := checkOutputDestinations(r.Context(), &req); err != nil {
+		slog.Error("Output destination check failed", "error", err, "destination", destination, "requestID", requestID)
+		api.CodedErrorResponse(w, http.StatusBadRequest, "output_destination_not_writable", err.Error(), requestID, map[string]interface{}{
+			"destination": destination,
+		})
+		return
+	}
 
-	// Check context cancellation
-	select {
-	case <-ctx.Done():
-		updateJobError(jobID, "processing cancelled: "+ctx.Err().Error())
+	// Refuse videos that cannot fit in the temp workspace instead of failing mid-download
+	if err := checkDiskSpace(r.Context(), &req); err != nil {
+		slog.Warn("Insufficient disk space for job", "error", err, "requestID", requestID)
+		api.CodedErrorResponse(w, http.StatusInsufficientStorage, "insufficient_disk_space", err.Error(), requestID, nil)
 		return
-	default:
 	}
 
-	// Parse video URL
-	bucket, path, err := storage.ParseGCSURL(req.VideoURL)
-	if err != nil {
-		updateJobError(jobID, "failed to parse video URL: "+err.Error())
+	// Submitted, retried and restarted jobs are admitted on the instance the same way
+	client, _ := clientIdentifier(r)
+	release, admissionErr := admitJob(client)
+	if admissionErr != nil {
+		api.WriteAdmissionError(w, admissionErr, requestID)
 		return
 	}
 
-	// Download video
-	slog.Info("Downloading video", "jobID", jobID, "bucket", bucket, "path", path)
-	videoPath, err := storageClient.Download(ctx, bucket, path)
-	if err != nil {
-		if ctx.Err() != nil {
-			updateJobError(jobID, "processing cancelled during download: "+ctx.Err().Error())
-		} else {
-			updateJobError(jobID, "failed to download video: "+err.Error())
+	// Reuse the results of an identical completed job instead of processing the same content again
+	// The source is only hashed here when a completed job could match; otherwise the job hashes it once running.
+	contentKey := ""
+	if !req.Reprocess && !resultIndex.Empty() {
+		contentKey = jobContentKey(r.Context(), &req, owner)
+		if reused := reusableJob(contentKey); reused != nil {
+			release()
+			respondReusedJob(w, jobID, &req, owner, reused, requestID)
+			return
 		}
-		return
 	}
-	tempFiles = append(tempFiles, videoPath)
 
-	// Check context cancellation
-	select {
-	case <-ctx.Done():
-		updateJobError(jobID, "processing cancelled: "+ctx.Err().Error())
+	// Reject resubmission of a video and languages that are already being processed
+	fingerprint := api.JobFingerprint(owner, req.SourceKey(), req.TargetLanguages)
+	if existingJobID, ok := duplicateDetector.Claim(fingerprint, jobID); !ok {
+		release()
+		api.CodedErrorResponse(w, http.StatusConflict, "duplicate_job", "an identical job was submitted recently", requestID, map[string]interface{}{
+			"jobId": existingJobID,
+		})
 		return
-	default:
 	}
 
-	// Get video duration
-	videoDuration, err := video.GetVideoDuration(ctx, videoPath)
-	if err != nil {
-		// Check if error is due to context cancellation
-		if ctx.Err() != nil {
-			updateJobError(jobID, "processing cancelled during duration check: "+ctx.Err().Error())
-		} else {
-			updateJobError(jobID, "failed to get video duration: "+err.Error())
+	// Initialize job status
+	now := time.Now()
+	jobStatus := &models.StatusResponse{
+		JobID:      jobID,
+		Status:     models.StatusProcessing,
+		Results:    models.PendingResults(req.TargetLanguages),
+		CreatedAt:  &now,
+		UpdatedAt:  now,
+		Request:    &req,
+		Owner:      owner,
+		Tags:       req.Tags,
+		Metadata:   req.Metadata,
+		Meter:      newUsageMeter(),
+		ContentKey: contentKey,
+	}
+	jobStatus.RecordEvent(models.EventJobProcessing, "", "")
+
+	// Keys on a tier may only run a bounded number of jobs at once
+	// A client job ID may also have been taken by a concurrent submission since it was checked
+	maxActiveJobs := ownerJobLimit(owner)
+	if existing, err := jobStore.ClaimStatusWithinLimit(jobID, jobStatus, maxActiveJobs); err != nil {
+		duplicateDetector.Release(fingerprint, jobID)
+		release()
+		if errors.Is(err, api.ErrJobExists) {
+			respondExistingJob(w, existing, &req, owner, requestID)
+			return
 		}
+		api.WriteAdmissionError(w, api.ActiveJobLimitError(maxActiveJobs), requestID)
 		return
 	}
 
-	// Validate video duration
-	if videoDuration > cfg.MaxVideoDuration.Seconds() {
-		updateJobError(jobID, fmt.Sprintf("video duration exceeds maximum: %.2fs > %.2fs", videoDuration, cfg.MaxVideoDuration.Seconds()))
-		return
+	// Return immediate response with job ID
+	response := models.TranslateResponse{
+		JobID:     jobID,
+		Status:    models.StatusProcessing,
+		Results:   jobStatus.Results,
+		Languages: jobStatus.Languages,
 	}
 
-	// Extract audio
-	slog.Info("Extracting audio", "jobID", jobID)
-	audioPath, err := stt.ExtractAudioFromVideo(ctx, videoPath)
+	// Content-Length lets the client read the complete response even while the job runs inside the request
+	payload, err := json.Marshal(response)
 	if err != nil {
-		// Check if error is due to context cancellation
-		if ctx.Err() != nil {
-			updateJobError(jobID, "processing cancelled during audio extraction: "+ctx.Err().Error())
-		} else {
-			updateJobError(jobID, "failed to extract audio: "+err.Error())
-		}
+		slog.Error("Failed to encode response", "error", err, "requestID", requestID)
+		release()
 		return
 	}
-	tempFiles = append(tempFiles, audioPath)
-
-	// Check context cancellation
-	select {
-	case <-ctx.Done():
-		updateJobError(jobID, "processing cancelled: "+ctx.Err().Error())
+	payload = append(payload, '\n')
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+	w.WriteHeader(http.StatusAccepted)
+	if _, err := w.Write(payload); err != nil {
+		slog.Error("Failed to write response", "error", err, "requestID", requestID)
+		release()
 		return
-	default:
 	}
 
-	// Transcribe audio
-	slog.Info("Transcribing audio", "jobID", jobID)
-	transcription, err := stt.SpeechToText(ctx, audioPath, req.SourceLanguage)
-	if err != nil {
-		// Check if error is due to context cancellation
-		if ctx.Err() != nil {
-			updateJobError(jobID, "transcription cancelled: "+ctx.Err().Error())
-		} else {
-			updateJobError(jobID, "failed to transcribe audio: "+err.Error())
+	// A job run inside the request needs the response to reach the client first
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	runJob(jobID, jobStatus.Meter, release, func(ctx context.Context) {
+		if contentKey == "" {
+			recordContentKey(ctx, jobID, &req, owner)
+		}
+		processTranslation(ctx, jobID, &req)
+	})
+}
+
+// admitJob admits a job of client on this instance: the spend budget must not be used up, and the job takes
+// one of the instance's MAX_INSTANCE_JOBS slots and one of the client's in-flight slots until release is called
+// Submissions, retries and scheduled restarts all go through it.
+func admitJob(client string) (release func(), err *api.AdmissionError) {
+	// Stop accepting new work once the providers were used up to the budget of the current window
+	if spendBudget != nil {
+		if state := spendBudget.State(); state.Exceeded != "" {
+			return nil, &api.AdmissionError{
+				StatusCode: http.StatusTooManyRequests,
+				Code:       models.ErrCodeBudgetExceeded,
+				Message:    fmt.Sprintf("spending budget exceeded (%s), new jobs are accepted again at %s", state.Exceeded, state.ResetAt.UTC().Format(time.RFC3339)),
+				Details: map[string]interface{}{
+					"limit":   state.Exceeded,
+					"resetAt": state.ResetAt.UTC().Format(time.RFC3339),
+				},
+				RetryAfter: max(time.Until(state.ResetAt), time.Second),
+			}
 		}
-		return
 	}
 
-	originalText := transcription.Text
-	sourceLanguage := transcription.Language
-	if sourceLanguage == "" {
-		sourceLanguage = req.SourceLanguage
-		if sourceLanguage == "" {
-			sourceLanguage = "auto"
+	// Leave the job to another instance when this one already runs as many as it can hold
+	// The slot is reserved now, so concurrent admissions cannot both take the last one.
+	releaseSlot, ok := runningJobs.Reserve(instanceJobCap)
+	if !ok {
+		return nil, &api.AdmissionError{
+			StatusCode: http.StatusServiceUnavailable,
+			Code:       "instance_at_capacity",
+			Message:    fmt.Sprintf("instance is processing %d jobs, its limit", instanceJobCap),
+			Details:    map[string]interface{}{"limit": instanceJobCap},
+			RetryAfter: instanceBusyRetryAfter,
 		}
 	}
 
-	// Validate transcription result
-	if originalText == "" {
-		updateJobError(jobID, "transcription returned empty text")
-		return
+	// Each client may only have a bounded number of jobs processing, however slowly it submits them
+	releaseInFlight, ok := inFlightLimiter.Acquire(client)
+	if !ok {
+		releaseSlot()
+		return nil, &api.AdmissionError{
+			StatusCode: http.StatusTooManyRequests,
+			Code:       "too_many_inflight_jobs",
+			Message:    fmt.Sprintf("too many jobs in flight: at most %d at once per client", inFlightLimiter.Limit()),
+			Details:    map[string]interface{}{"limit": inFlightLimiter.Limit()},
+		}
 	}
 
-	slog.Info("Transcription completed", "jobID", jobID, "textLength", len(originalText), "language", sourceLanguage)
+	return func() {
+		releaseInFlight()
+		releaseSlot()
+	}, nil
+}
 
-	// Check context cancellation before starting language processing
-	select {
-	case <-ctx.Done():
-		updateJobError(jobID, "processing cancelled: "+ctx.Err().Error())
-		return
-	default:
+// ownerJobLimit returns how many jobs the owner's API key may process at once, set by its rate limit tier
+func ownerJobLimit(owner string) int {
+	if owner == "" {
+		return 0
 	}
+	return cfg.RateLimitTiers[cfg.APIKeyTiers[owner]].MaxConcurrentJobs
+}
 
-	// Process each target language concurrently
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, cfg.MaxConcurrentTranslations)
+// admitRetry admits a retried or restarted job on this instance like a new submission
+// The job counts towards the in-flight jobs of its owner, or of the client retrying a job submitted without a key.
+func admitRetry(r *http.Request, status *models.StatusResponse) (func(), *api.AdmissionError) {
+	client, _ := clientIdentifier(r)
+	if status.Owner != "" {
+		client = api.RateLimitKeyIdentifier(status.Owner)
+	}
+	return admitJob(client)
+}
 
-	for _, targetLang := range req.TargetLanguages {
-		// Check context cancellation before processing each language
-		select {
-		case <-ctx.Done():
-			slog.Warn("Processing cancelled, stopping language processing", "jobID", jobID)
-			// Mark remaining languages as failed
-			for _, lang := range req.TargetLanguages {
-				if _, exists := jobStatus.Results[lang]; !exists {
-					jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
-						if status.Results == nil {
-							status.Results = make(map[string]*models.LanguageResult)
-						}
-						status.Results[lang] = &models.LanguageResult{
-							Status: models.StatusFailed,
-							Error:  "processing cancelled",
-						}
-						status.UpdatedAt = time.Now()
-					})
-				}
-			}
-			updateJobError(jobID, "processing cancelled: "+ctx.Err().Error())
-			return
-		default:
-		}
+// runJob runs a job's processing with the request timeout, registered so it can be cancelled from the admin API
+// Cloud Run throttles CPU once a request completes unless CPU is always allocated, so without CPU_ALWAYS_ALLOCATED
+// the job runs inside the current request, whose response must already be flushed; otherwise it runs in the
+// background. release gives back the job's admission once processing ended.
+func runJob(jobID string, meter *models.UsageMeter, release func(), process func(ctx context.Context)) {
+	// Use background context with timeout since request context will be cancelled after response
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.RequestTimeout)
+	runningJobs.Register(jobID, cancel)
+	ctx = usage.WithMeter(ctx, meter)
+	ctx = storage.WithObjectMetadata(ctx, "jobId", jobID)
+
+	run := func() {
+		defer release()
+		defer runningJobs.Finish(jobID)
+		process(ctx)
+	}
+	if cfg.CPUAlwaysAllocated {
+		go run()
+		return
+	}
+	run()
+}
 
-		wg.Add(1)
-		go func(lang string) {
-			defer wg.Done()
-			semaphore <- struct{}{}        // Acquire semaphore
-			defer func() { <-semaphore }() // Release semaphore
+// newUsageMeter creates the usage meter of a new job, counting its usage towards the spend budget if one is set
+func newUsageMeter() *models.UsageMeter {
+	if spendBudget == nil {
+		return models.NewUsageMeter()
+	}
+	return models.NewUsageMeterWithSink(spendBudget)
+}
 
-			result := processLanguage(ctx, jobID, originalText, sourceLanguage, lang, videoPath, videoDuration, cfg.GCSOutputBucket)
+// jobContentKey identifies the content and output options of a request for result reuse
+// Empty when RESULT_REUSE_WINDOW is disabled or the source has no content hash (HTTPS sources).
+func jobContentKey(ctx context.Context, req *models.TranslateRequest, owner string) string {
+	if cfg.ResultReuseWindow <= 0 || storage.IsHTTPSource(req.VideoURL) {
+		return ""
+	}
+	bucket, path, err := storage.ParseGCSURL(req.VideoURL)
+	if err != nil {
+		return ""
+	}
+	source, err := sourceStorage(ctx, req)
+	if err != nil {
+		return ""
+	}
+	hasher, ok := source.(storage.ContentHasher)
+	if !ok {
+		return ""
+	}
+	hash, err := hasher.ContentHash(ctx, bucket, path)
+	if err != nil {
+		slog.Warn("Failed to read source content hash, result reuse skipped", "error", err, "videoUrl", req.VideoURL)
+		return ""
+	}
+	return api.ContentKey(owner, hash, req)
+}
+
+// recordContentKey sets the content key of a job whose submission did not compute it, so identical submissions
+// can reuse its results once it completed
+func recordContentKey(ctx context.Context, jobID string, req *models.TranslateRequest, owner string) {
+	contentKey := jobContentKey(ctx, req, owner)
+	if contentKey == "" {
+		return
+	}
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		status.ContentKey = contentKey
+	})
+}
+
+// reusableJob returns the completed job indexed under the content key, if it still exists and completed
+func reusableJob(contentKey string) *models.StatusResponse {
+	jobID, ok := resultIndex.Lookup(contentKey)
+	if !ok {
+		return nil
+	}
+	status, err := jobStore.GetStatus(jobID)
+	if err != nil || status.Status != models.StatusCompleted {
+		resultIndex.Forget(contentKey, jobID)
+		return nil
+	}
+	return status
+}
+
+// respondReusedJob creates a job completed with the results of an identical completed job and answers like any
+// accepted submission. The job is notified like any completed job, and its usage is empty since nothing was processed.
+func respondReusedJob(w http.ResponseWriter, jobID string, req *models.TranslateRequest, owner string, reused *models.StatusResponse, requestID string) {
+	results := make(map[string]*models.LanguageResult, len(reused.Results))
+	for lang, result := range reused.Results {
+		copied := *result
+		results[lang] = &copied
+	}
+
+	now := time.Now()
+	jobStatus := &models.StatusResponse{
+		JobID:                jobID,
+		Status:               models.StatusCompleted,
+		Results:              results,
+		CreatedAt:            &now,
+		UpdatedAt:            now,
+		ManifestURL:          reused.ManifestURL,
+		PreviewURL:           reused.PreviewURL,
+		TranscriptConfidence: reused.TranscriptConfidence,
+		SkippedLanguages:     reused.SkippedLanguages,
+		AudioTracks:          reused.AudioTracks,
+		Source:               reused.Source,
+		SpeakerGender:        reused.SpeakerGender,
+		Request:              req,
+		Owner:                owner,
+		Tags:                 req.Tags,
+		Metadata:             req.Metadata,
+		Meter:                models.NewUsageMeter(),
+		ReusedFrom:           reused.JobID,
+	}
+	jobStatus.Usage = jobStatus.Meter.Snapshot(cfg.PriceTable())
+	jobStatus.RecordEvent(models.EventJobCompleted, "", "")
+
+	// A client job ID may have been taken by a concurrent submission since it was checked
+	if existing, err := jobStore.ClaimStatusWithinLimit(jobID, jobStatus, 0); err != nil {
+		respondExistingJob(w, existing, req, owner, requestID)
+		return
+	}
+	slog.Info("Reusing results of identical completed job", "jobID", jobID, "reusedFrom", reused.JobID, "requestID", requestID)
+	notifyJob(jobID)
+
+	response := models.TranslateResponse{
+		JobID:      jobID,
+		Status:     jobStatus.Status,
+		Results:    jobStatus.Results,
+		Languages:  jobStatus.Languages,
+		ReusedFrom: reused.JobID,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("Failed to encode response", "error", err, "requestID", requestID)
+	}
+}
+
+// respondExistingJob answers a submission whose client-supplied job ID is already taken
+// An identical request from the same owner returns the job's status; anything else is a conflict.
+func respondExistingJob(w http.ResponseWriter, existing *models.StatusResponse, req *models.TranslateRequest, owner string, requestID string) {
+	if existing.Owner != owner || !reflect.DeepEqual(existing.Request, req) {
+		api.CodedErrorResponse(w, http.StatusConflict, "job_id_conflict", "jobId is already used by a different request", requestID, map[string]interface{}{
+			"jobId": existing.JobID,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(existing); err != nil {
+		slog.Error("Failed to encode response", "error", err, "requestID", requestID)
+	}
+}
+
+// resolveServiceAccount defaults the request's service account to its API key's and refuses
+// service accounts the key may not impersonate, so tenants cannot read each other's buckets
+func resolveServiceAccount(r *http.Request, req *models.TranslateRequest) error {
+	keyAccount := ""
+	if principal := api.PrincipalFromRequest(r); principal != nil {
+		keyAccount = principal.ServiceAccount
+	}
+	if req.ServiceAccount == "" {
+		req.ServiceAccount = keyAccount
+	}
+	if req.ServiceAccount == "" {
+		return nil
+	}
+	if !cfg.CanImpersonate(req.ServiceAccount, keyAccount) {
+		return fmt.Errorf("service account %s may not be impersonated by this API key", req.ServiceAccount)
+	}
+	if _, ok := storageClient.(storage.Impersonator); !ok {
+		return fmt.Errorf("service account impersonation is not supported by the storage backend")
+	}
+	return nil
+}
+
+// sourceStorage returns the storage reading the job's source video and subtitles,
+// impersonating the request's service account when it has one
+func sourceStorage(ctx context.Context, req *models.TranslateRequest) (storage.Storage, error) {
+	if req.ServiceAccount == "" {
+		return storageClient, nil
+	}
+	impersonator, ok := storageClient.(storage.Impersonator)
+	if !ok {
+		return nil, transient.Permanent(fmt.Errorf("storage backend cannot impersonate %s", req.ServiceAccount))
+	}
+	return impersonator.Impersonate(ctx, req.ServiceAccount)
+}
+
+// headHTTPSource sends a HEAD request to an HTTPS source, returning ERR_SOURCE_UNREACHABLE, ERR_NOT_VIDEO or
+// ERR_SOURCE_TOO_LARGE with the error when it cannot be processed
+func headHTTPSource(ctx context.Context, req *models.TranslateRequest) (*storage.SourceInfo, string, error) {
+	headCtx, cancel := context.WithTimeout(ctx, sourceCheckTimeout)
+	defer cancel()
+	info, err := storage.HeadSource(headCtx, sourceHTTPClient, req.VideoURL)
+	switch {
+	case errors.Is(err, storage.ErrNotVideo):
+		return nil, models.ErrCodeNotVideo, err
+	case err != nil:
+		return nil, models.ErrCodeSourceUnreachable, err
+	}
+	if info.Size > maxSourceBytes() {
+		return nil, models.ErrCodeSourceTooLarge, fmt.Errorf("video size exceeds maximum: %d MB > %d MB", info.Size>>20, cfg.MaxVideoSizeMB)
+	}
+	return info, "", nil
+}
+
+// maxSourceBytes is the largest source video accepted, MAX_VIDEO_SIZE_MB in bytes
+func maxSourceBytes() int64 {
+	return int64(cfg.MaxVideoSizeMB) << 20
+}
+
+// checkSubmittedSource checks an HTTPS source when the job is submitted, so unreachable or oversized sources are
+// rejected before a job exists; returns the error code with the error
+func checkSubmittedSource(ctx context.Context, req *models.TranslateRequest) (string, error) {
+	if !storage.IsHTTPSource(req.VideoURL) {
+		return "", nil
+	}
+	_, code, err := headHTTPSource(ctx, req)
+	return code, err
+}
+
+// checkHTTPSource checks an HTTPS source again before the job reserves disk space or runs ffmpeg, since it may have
+// changed since submission (e.g. for restarted jobs). Returns false if the job was failed.
+func checkHTTPSource(ctx context.Context, jobID string, req *models.TranslateRequest) bool {
+	if !storage.IsHTTPSource(req.VideoURL) {
+		return true
+	}
+	setJobStage(jobID, models.StageCheckingSource)
+
+	info, code, err := headHTTPSource(ctx, req)
+	switch {
+	case ctx.Err() != nil:
+		updateJobError(jobID, "processing cancelled while checking the source: "+ctx.Err().Error())
+		return false
+	case err != nil:
+		updateJobErrorCode(jobID, code, err.Error())
+		return false
+	}
+	slog.Info("HTTPS source checked", "jobID", jobID, "sizeBytes", info.Size, "contentType", info.ContentType)
+	return true
+}
+
+// downloadSourceVideo downloads the job's video to a temp file, trimmed to the requested clip range
+func downloadSourceVideo(ctx context.Context, jobID string, req *models.TranslateRequest) (string, error) {
+	var videoPath string
+	var err error
+	if storage.IsHTTPSource(req.VideoURL) {
+		slog.Info("Downloading video", "jobID", jobID, "url", req.VideoURL)
+		videoPath, err = storage.DownloadHTTP(ctx, sourceHTTPClient, req.VideoURL, maxSourceBytes())
+	} else {
+		bucket, path, parseErr := storage.ParseGCSURL(req.VideoURL)
+		if parseErr != nil {
+			return "", transient.Permanent(fmt.Errorf("failed to parse video URL: %w", parseErr))
+		}
+		slog.Info("Downloading video", "jobID", jobID, "bucket", bucket, "path", path, "serviceAccount", req.ServiceAccount)
+		var source storage.Storage
+		source, err = sourceStorage(ctx, req)
+		if err == nil {
+			videoPath, err = source.Download(ctx, bucket, path)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to download video: %w", err)
+	}
+	if !req.IsClip() {
+		return videoPath, nil
+	}
+
+	// The full video is only needed to cut the clip
+	defer removeTempFile(jobID, videoPath)
+
+	var fullDuration float64
+	err = ffmpegPool.Do(ctx, func() (err error) {
+		fullDuration, err = video.GetVideoDuration(ctx, videoPath)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get video duration: %w", err)
+	}
+	if req.StartTime >= fullDuration {
+		return "", transient.Permanent(fmt.Errorf("startTime is beyond the end of the video: %.2fs >= %.2fs", req.StartTime, fullDuration))
+	}
+
+	clipPath, err := createTempFile(fmt.Sprintf("clip_%s_*.mp4", jobID))
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	err = ffmpegPool.Do(ctx, func() error {
+		return video.ClipVideo(ctx, videoPath, req.StartTime, req.EndTime, clipPath)
+	})
+	if err != nil {
+		removeTempFile(jobID, clipPath)
+		return "", fmt.Errorf("failed to clip video: %w", err)
+	}
+
+	slog.Info("Video clipped", "jobID", jobID, "startTime", req.StartTime, "endTime", req.EndTime)
+	return clipPath, nil
+}
+
+// diskRequirement estimates the temp disk space a job needs: the source video size times DISK_SPACE_FACTOR,
+// which covers the clip, extracted audio and per-language outputs written next to the download
+func diskRequirement(ctx context.Context, req *models.TranslateRequest) (int64, error) {
+	size, err := sourceSize(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	return int64(float64(size) * cfg.DiskSpaceFactor), nil
+}
+
+// sourceSize returns the size of the job's source video in bytes
+// HTTPS sources are sized from their HEAD response, or MAX_VIDEO_SIZE_MB (the download limit) when it has no length.
+func sourceSize(ctx context.Context, req *models.TranslateRequest) (int64, error) {
+	if storage.IsHTTPSource(req.VideoURL) {
+		info, _, err := headHTTPSource(ctx, req)
+		if err != nil {
+			return 0, err
+		}
+		if info.Size < 0 {
+			return maxSourceBytes(), nil
+		}
+		return info.Size, nil
+	}
+
+	bucket, path, err := storage.ParseGCSURL(req.VideoURL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse video URL: %w", err)
+	}
+	source, err := sourceStorage(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	size, err := source.Size(ctx, bucket, path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read video size: %w", err)
+	}
+	return size, nil
+}
+
+// checkDiskSpace returns an error when a job's video cannot fit in the temp workspace
+// Under the reject policy it must fit next to the running jobs; under queue it must fit once they finish.
+// Failures to measure the video or the disk skip the check, leaving errors to the download.
+func checkDiskSpace(ctx context.Context, req *models.TranslateRequest) error {
+	if diskTracker == nil {
+		return nil
+	}
+	need, err := diskRequirement(ctx, req)
+	if err != nil {
+		slog.Warn("Skipping disk space check", "error", err)
+		return nil
+	}
+
+	if cfg.DiskSpacePolicy == "reject" {
+		available, err := diskTracker.Available()
+		if err != nil {
+			slog.Warn("Skipping disk space check", "error", err)
+			return nil
+		}
+		if need > available {
+			return fmt.Errorf("the video needs %d MB of temporary disk space but only %d MB is free; retry later", need>>20, max(available, 0)>>20)
+		}
+		return nil
+	}
+
+	fits, err := diskTracker.Fits(need)
+	if err != nil {
+		slog.Warn("Skipping disk space check", "error", err)
+		return nil
+	}
+	if !fits {
+		return fmt.Errorf("the video needs %d MB of temporary disk space, more than this instance has", need>>20)
+	}
+	return nil
+}
+
+// reserveDiskSpace claims the job's temp disk space before it downloads, waiting for running jobs
+// to release theirs under the queue policy. Returns the bytes reserved (zero when the reservation was skipped),
+// or false if the job was failed.
+func reserveDiskSpace(ctx context.Context, jobID string, req *models.TranslateRequest) (int64, bool) {
+	if diskTracker == nil {
+		return 0, true
+	}
+	need, err := diskRequirement(ctx, req)
+	if err != nil {
+		slog.Warn("Skipping disk space reservation", "error", err, "jobID", jobID)
+		return 0, true
+	}
+	if !reserveDiskBytes(ctx, jobID, need) {
+		return 0, false
+	}
+	return need, true
+}
+
+// reserveDiskBytes claims need bytes of temp disk space for a job, replacing its previous reservation, and waits
+// for them under the queue policy. Returns false if the job was failed.
+func reserveDiskBytes(ctx context.Context, jobID string, need int64) bool {
+	err := diskTracker.Reserve(jobID, need)
+	if errors.Is(err, diskspace.ErrInsufficientSpace) && cfg.DiskSpacePolicy == "queue" {
+		slog.Info("Waiting for temp disk space", "jobID", jobID, "neededBytes", need, "reservedBytes", diskTracker.Reserved())
+		setJobStage(jobID, models.StageWaitingForDisk)
+		err = diskTracker.Wait(ctx, jobID, need, diskPollInterval)
+	}
+	switch {
+	case err == nil:
+		return true
+	case ctx.Err() != nil:
+		updateJobError(jobID, "processing cancelled while waiting for disk space: "+ctx.Err().Error())
+		return false
+	case errors.Is(err, diskspace.ErrInsufficientSpace):
+		updateJobErrorCode(jobID, models.ErrCodeInsufficientDisk, "not enough temporary disk space for the video: "+err.Error())
+		return false
+	default:
+		slog.Warn("Skipping disk space reservation", "error", err, "jobID", jobID)
+		return true
+	}
+}
+
+// recordJobTiming updates the job-wide stage timings of a job
+func recordJobTiming(jobID string, update func(timings *models.JobTimings)) {
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		if status.Timings == nil {
+			status.Timings = &models.JobTimings{}
+		}
+		update(status.Timings)
+	})
+}
+
+// setJobStage records the pipeline stage a job has reached
+func setJobStage(jobID string, stage string) {
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		status.Stage = stage
+		status.RecordEvent(models.EventJobStage, "", "")
+	})
+}
+
+// setLanguageProgress records the progress of a language that is still processing, for the job's overall progress
+func setLanguageProgress(jobID string, language string, progress int) {
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		if status.LanguageProgress == nil {
+			status.LanguageProgress = make(map[string]int)
+		}
+		status.LanguageProgress[language] = progress
+		if result, ok := status.Results[language]; ok && !result.IsFinished() {
+			result.Status = models.StatusProcessing
+			result.Progress = progress
+		}
+	})
+}
+
+// processTranslation runs a job's pipeline up to its checkpoint, then dubs every target language from it
+func processTranslation(ctx context.Context, jobID string, req *models.TranslateRequest) {
+	slog.Info("Starting translation processing", "jobID", jobID)
+
+	run := &jobRun{jobID: jobID, req: req}
+	defer run.release()
+	if err := run.pipeline().Run(ctx); err != nil {
+		failJobRun(ctx, jobID, err)
+		return
+	}
+
+	processLanguages(ctx, jobID, req, run.checkpoint, run.targetLanguages)
+}
+
+// limitTranscript applies TRANSCRIPT_LIMIT_POLICY to a source text longer than MAX_TRANSCRIPT_CHARS,
+// failing the job when the policy is fail or the text cannot be shortened
+func limitTranscript(ctx context.Context, jobID string, text string, cues []subtitles.Cue, language string) (string, []subtitles.Cue, bool) {
+	length := transcript.Length(text)
+	if cfg.MaxTranscriptChars <= 0 || length <= cfg.MaxTranscriptChars {
+		return text, cues, true
+	}
+	slog.Warn("Source text exceeds maximum length",
+		"jobID", jobID,
+		"length", length,
+		"maxChars", cfg.MaxTranscriptChars,
+		"policy", cfg.TranscriptLimitPolicy)
+
+	policy := cfg.TranscriptLimitPolicy
+	summarizer := transcriptSummarizer()
+	if policy == transcript.PolicySummarize && (cues != nil || summarizer == nil) {
+		// A summary cannot keep subtitle timings, and mock mode has no LLM provider
+		policy = transcript.PolicyTruncate
+	}
+
+	var warning string
+	switch policy {
+	case transcript.PolicyTruncate:
+		if cues != nil {
+			cues = transcript.TruncateCues(cues, cfg.MaxTranscriptChars)
+			text = subtitles.Text(cues)
+		} else {
+			text = transcript.Truncate(text, cfg.MaxTranscriptChars)
+		}
+		warning = fmt.Sprintf("source text truncated from %d to %d characters (MAX_TRANSCRIPT_CHARS)", length, transcript.Length(text))
+	case transcript.PolicySummarize:
+		setJobStage(jobID, models.StageSummarizing)
+		// Only the leading sentences that fit the model's context are summarized
+		input := transcript.Truncate(text, cfg.LLMMaxInputChars)
+		var summary string
+		err := apiPool.Do(ctx, func() error {
+			var err error
+			summary, err = summarizer.Summarize(ctx, input, language, cfg.MaxTranscriptChars)
+			return err
+		})
+		if err != nil {
+			updateJobErrorCause(jobID, err, "failed to summarize source text: "+err.Error())
+			return "", nil, false
+		}
+		usage.FromContext(ctx).AddTranslateCharacters(summarizer.Name(), transcript.Length(input))
+		// The model may overshoot the requested length
+		text = transcript.Truncate(summary, cfg.MaxTranscriptChars)
+		warning = fmt.Sprintf("source text summarized from %d to %d characters (MAX_TRANSCRIPT_CHARS)", length, transcript.Length(text))
+		if transcript.Length(input) < length {
+			warning += fmt.Sprintf("; only its first %d characters were summarized (LLM_MAX_INPUT_CHARS)", transcript.Length(input))
+		}
+	default:
+		updateJobErrorCode(jobID, models.ErrCodeTranscriptTooLong,
+			fmt.Sprintf("source text has %d characters, more than the maximum of %d", length, cfg.MaxTranscriptChars))
+		return "", nil, false
+	}
+
+	if strings.TrimSpace(text) == "" {
+		updateJobErrorCode(jobID, models.ErrCodeTranscriptTooLong,
+			fmt.Sprintf("source text has %d characters and could not be shortened to %d", length, cfg.MaxTranscriptChars))
+		return "", nil, false
+	}
+
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		status.Warnings = append(status.Warnings, warning)
+	})
+	return text, cues, true
+}
+
+// brandingSources merges the request's branding overrides over the deployment's WATERMARK_*, INTRO_URL and OUTRO_URL
+func brandingSources(req *models.TranslateRequest) models.BrandingOptions {
+	sources := models.BrandingOptions{
+		WatermarkURL:      cfg.WatermarkURL,
+		WatermarkPosition: cfg.WatermarkPosition,
+		IntroURL:          cfg.IntroURL,
+		OutroURL:          cfg.OutroURL,
+	}
+	if override := req.Branding; override != nil {
+		if override.WatermarkURL != "" {
+			sources.WatermarkURL = override.WatermarkURL
+		}
+		if override.WatermarkPosition != "" {
+			sources.WatermarkPosition = override.WatermarkPosition
+		}
+		if override.IntroURL != "" {
+			sources.IntroURL = override.IntroURL
+		}
+		if override.OutroURL != "" {
+			sources.OutroURL = override.OutroURL
+		}
+	}
+	return sources
+}
+
+// downloadBranding downloads the watermark and bumper clips of a job, or returns nil when it has none
+func downloadBranding(ctx context.Context, req *models.TranslateRequest) (*models.BrandingAssets, error) {
+	sources := brandingSources(req)
+	if sources.WatermarkURL == "" && sources.IntroURL == "" && sources.OutroURL == "" {
+		return nil, nil
+	}
+
+	// Clips named in the request are read as the request's service account, like its source video;
+	// the deployment's own clips with the function's identity
+	override := req.Branding
+	if override == nil {
+		override = &models.BrandingOptions{}
+	}
+	assets := &models.BrandingAssets{WatermarkPosition: sources.WatermarkPosition}
+	downloads := []struct {
+		url         string
+		fromRequest bool
+		path        *string
+	}{
+		{sources.WatermarkURL, override.WatermarkURL != "", &assets.WatermarkPath},
+		{sources.IntroURL, override.IntroURL != "", &assets.IntroPath},
+		{sources.OutroURL, override.OutroURL != "", &assets.OutroPath},
+	}
+	for _, download := range downloads {
+		if download.url == "" {
+			continue
+		}
+		reader := storageClient
+		bucket, path, err := storage.ParseGCSURL(download.url)
+		if err == nil && download.fromRequest {
+			reader, err = sourceStorage(ctx, req)
+		}
+		if err == nil {
+			*download.path, err = reader.Download(ctx, bucket, path)
+		}
+		if err != nil {
+			for _, file := range assets.Files() {
+				os.Remove(file)
+			}
+			return nil, fmt.Errorf("%s: %w", download.url, err)
+		}
+	}
+	return assets, nil
+}
+
+// keepBackgroundMusic reports whether the job mixes the separated music bed under the dubbed voice
+func keepBackgroundMusic(req *models.TranslateRequest) bool {
+	if separator == nil {
+		return false
+	}
+	if req.KeepBackgroundMusic != nil {
+		return *req.KeepBackgroundMusic
+	}
+	return cfg.KeepBackgroundMusic
+}
+
+// separateBackground extracts the source audio and separates its accompaniment (music and effects)
+// Separation is best effort: on failure the job replaces the whole audio track and reports a warning.
+func separateBackground(ctx context.Context, jobID string, req *models.TranslateRequest, videoPath string) string {
+	slog.Info("Separating background music", "jobID", jobID, "backend", separator.Name())
+	setJobStage(jobID, models.StageSeparatingAudio)
+
+	backgroundPath, err := extractBackground(ctx, jobID, req, videoPath)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ""
+		}
+		slog.Warn("Background music separation failed, replacing the whole audio track", "error", err, "jobID", jobID)
+		jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+			status.Warnings = append(status.Warnings, "background music could not be separated; the original audio is fully replaced: "+err.Error())
+		})
+		return ""
+	}
+
+	slog.Info("Background music separated", "jobID", jobID, "backgroundPath", backgroundPath)
+	return backgroundPath
+}
+
+// separationSampleRate keeps the extracted audio at music quality, unlike the 16kHz used for recognition
+const separationSampleRate = 44100
+
+// extractBackground runs extraction and separation in a scratch directory and keeps only the accompaniment
+func extractBackground(ctx context.Context, jobID string, req *models.TranslateRequest, videoPath string) (string, error) {
+	stemDir, err := os.MkdirTemp("", fmt.Sprintf("stems_%s_", jobID))
+	if err != nil {
+		return "", fmt.Errorf("failed to create stem directory: %w", err)
+	}
+	defer os.RemoveAll(stemDir)
+
+	extraction := stt.ExtractOptions{
+		Track:      -1,
+		SampleRate: separationSampleRate,
+		Channels:   2,
+		OutputPath: filepath.Join(stemDir, "source.wav"),
+	}
+	if req.SourceAudioTrack != nil {
+		extraction.Track = *req.SourceAudioTrack
+	}
+	var audioPath string
+	err = ffmpegPool.Do(ctx, func() (err error) {
+		audioPath, err = stt.ExtractAudio(ctx, videoPath, extraction)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Local separation is CPU-bound like ffmpeg; a separation API is an external call
+	pool := ffmpegPool
+	if separator.Name() == separation.BackendHTTP {
+		pool = apiPool
+	}
+	var stemPath string
+	err = pool.Do(ctx, func() (err error) {
+		stemPath, err = separator.Separate(ctx, audioPath, stemDir)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Move the stem out of the scratch directory so it can back the retry checkpoint
+	backgroundPath, err := createTempFile(fmt.Sprintf("background_%s_*.wav", jobID))
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	if err := os.Rename(stemPath, backgroundPath); err != nil {
+		os.Remove(backgroundPath)
+		return "", fmt.Errorf("failed to keep background stem: %w", err)
+	}
+	return backgroundPath, nil
+}
+
+// punctuateTranscripts reports whether TRANSCRIPT_CLEANUP asks Speech-to-Text for automatic punctuation
+func punctuateTranscripts() bool {
+	return cfg.TranscriptCleanup == transcript.CleanupPunctuation || cfg.TranscriptCleanup == transcript.CleanupLLM
+}
+
+// cleanupTranscript restores punctuation and casing of a recognized transcript with the LLM provider
+// when TRANSCRIPT_CLEANUP is llm. The cleanup is best effort: on failure, or when the model changed
+// any words, the job continues with the recognized text and a warning.
+func cleanupTranscript(ctx context.Context, jobID string, text string, language string) string {
+	cleaner := transcriptSummarizer()
+	if cfg.TranscriptCleanup != transcript.CleanupLLM || cleaner == nil || strings.TrimSpace(text) == "" {
+		return text
+	}
+
+	setJobStage(jobID, models.StagePunctuating)
+	// Long transcripts are cleaned up in parts that each fit the model's context
+	var parts []string
+	var err error
+	for _, chunk := range transcript.Chunk(text, cfg.LLMMaxInputChars) {
+		var part string
+		err = apiPool.Do(ctx, func() error {
+			var err error
+			part, err = cleaner.RestorePunctuation(ctx, chunk, language)
+			return err
+		})
+		if err != nil {
+			break
+		}
+		parts = append(parts, part)
+	}
+	restored := strings.Join(parts, " ")
+	var warning string
+	if err != nil {
+		slog.Warn("Failed to restore transcript punctuation", "jobID", jobID, "error", err)
+		warning = "transcript punctuation could not be restored: " + err.Error()
+	} else {
+		usage.FromContext(ctx).AddTranslateCharacters(cleaner.Name(), transcript.Length(text))
+		if transcript.SameWords(text, restored) {
+			slog.Info("Transcript punctuation restored", "jobID", jobID, "textLength", len(restored))
+			return restored
+		}
+		slog.Warn("Discarding transcript cleanup that changed words", "jobID", jobID)
+		warning = "transcript punctuation was not restored because the cleanup changed words"
+	}
+
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		status.Warnings = append(status.Warnings, warning)
+	})
+	return text
+}
+
+// transcriptSummarizer returns the LLM translation provider used to summarize long source texts and
+// restore transcript punctuation, or nil
+func transcriptSummarizer() *translation.LLMTranslator {
+	for _, service := range translators.Providers() {
+		if llm, ok := service.(*translation.LLMTranslator); ok {
+			return llm
+		}
+	}
+	return nil
+}
+
+// transcribeSourceAudio extracts the source audio track and transcribes it, failing the job on error
+// Translations keyed by target language are also returned when the Gemini pipeline produced them.
+// duration is the length of the video in seconds, which recognition is billed for.
+func transcribeSourceAudio(ctx context.Context, jobID string, req *models.TranslateRequest, videoPath string, duration float64) (*stt.SpeechToTextResponse, map[string]string, bool) {
+	// Extract audio
+	slog.Info("Extracting audio", "jobID", jobID)
+	setJobStage(jobID, models.StageExtractingAudio)
+	recognition, extraction := recognitionOptions(req)
+	var audioPath string
+	err := ffmpegPool.Do(ctx, func() (err error) {
+		audioPath, err = stt.ExtractAudio(ctx, videoPath, extraction)
+		return err
+	})
+	if err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			updateJobError(jobID, "processing cancelled during audio extraction: "+ctx.Err().Error())
+		} else {
+			updateJobError(jobID, "failed to extract audio: "+err.Error())
+		}
+		return nil, nil, false
+	}
+	defer removeTempFile(jobID, audioPath)
+
+	// Fail fast on silent or music-only audio instead of paying for transcription
+	if cfg.STTMinSpeechRatio > 0 {
+		activity, err := stt.DetectSpeech(audioPath)
+		if err != nil {
+			slog.Warn("Speech detection failed, continuing with transcription", "error", err, "jobID", jobID)
+		} else if activity.SpeechRatio < cfg.STTMinSpeechRatio {
+			slog.Info("No speech detected", "jobID", jobID, "speechRatio", activity.SpeechRatio, "duration", activity.Duration)
+			updateJobErrorCode(jobID, models.ErrCodeNoSpeech, "no speech detected in the audio (silence or music only); check that the video contains spoken dialogue or choose another sourceAudioTrack")
+			return nil, nil, false
+		}
+	}
+
+	if req.VoiceGender == models.VoiceGenderMatch {
+		recordSpeakerGender(jobID, audioPath)
+	}
+
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		updateJobError(jobID, "processing cancelled: "+ctx.Err().Error())
+		return nil, nil, false
+	default:
+	}
+
+	// Short clips can be transcribed and translated in one call, with speech-to-text as the fallback
+	if useGeminiPipeline(req, duration) {
+		setJobStage(jobID, models.StageTranscribing)
+		var result *gemini.Result
+		err = apiPool.Do(ctx, func() (err error) {
+			result, err = geminiClient.TranscribeAndTranslate(ctx, audioPath, recognition.LanguageHint, req.TargetLanguages)
+			return err
+		})
+		if err == nil {
+			meter := usage.FromContext(ctx)
+			meter.AddSTTSeconds(duration)
+			for _, translated := range result.Translations {
+				meter.AddTranslateCharacters("gemini", utf8.RuneCountInString(translated))
+			}
+			slog.Info("Transcription and translation completed with Gemini", "jobID", jobID, "textLength", len(result.Transcript), "language", result.Language)
+			return &stt.SpeechToTextResponse{Text: result.Transcript, Language: result.Language}, result.Translations, true
+		}
+		if ctx.Err() != nil {
+			updateJobError(jobID, "transcription cancelled: "+ctx.Err().Error())
+			return nil, nil, false
+		}
+		slog.Warn("Gemini transcription failed, falling back to speech-to-text", "error", err, "jobID", jobID)
+	}
+
+	// Transcribe audio
+	slog.Info("Transcribing audio", "jobID", jobID)
+	setJobStage(jobID, models.StageTranscribing)
+	var transcription *stt.SpeechToTextResponse
+	err = apiPool.Do(ctx, func() (err error) {
+		transcription, err = stt.SpeechToTextWithOptions(ctx, audioPath, recognition)
+		return err
+	})
+	if err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			updateJobError(jobID, "transcription cancelled: "+ctx.Err().Error())
+		} else {
+			updateJobErrorCause(jobID, err, "failed to transcribe audio: "+err.Error())
+		}
+		return nil, nil, false
+	}
+	usage.FromContext(ctx).AddSTTSeconds(duration)
+
+	// Validate transcription result
+	if transcription.Text == "" {
+		updateJobError(jobID, "transcription returned empty text")
+		return nil, nil, false
+	}
+
+	// Surface transcription confidence and fail early on unusable transcripts
+	warnings, err := stt.CheckConfidence(transcription, cfg.STTConfidenceWarning, cfg.STTMinConfidence)
+	if err != nil {
+		updateJobError(jobID, err.Error())
+		return nil, nil, false
+	}
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		status.TranscriptConfidence = transcription.Confidence
+		status.Warnings = append(status.Warnings, warnings...)
+	})
+
+	slog.Info("Transcription completed", "jobID", jobID, "textLength", len(transcription.Text), "language", transcription.Language)
+	return transcription, nil, true
+}
+
+// recordSpeakerGender estimates the original speaker's gender from the pitch of the audio for voiceGender "match"
+// With chapters, the first chapter estimated sets the gender of the whole job.
+func recordSpeakerGender(jobID string, audioPath string) {
+	pitch, err := stt.EstimatePitch(audioPath)
+	if err != nil {
+		slog.Warn("Speaker gender estimation failed", "error", err, "jobID", jobID)
+		return
+	}
+	slog.Info("Speaker gender estimated", "jobID", jobID, "gender", pitch.Gender(), "medianPitchHz", pitch.MedianHz)
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		if status.SpeakerGender == "" {
+			status.SpeakerGender = pitch.Gender()
+		}
+	})
+}
+
+// recordPartialTranscript publishes the text recognized for a part of the video in the job status
+// so clients can show the transcript forming before translation; profanity is masked as it will be in the transcript.
+func recordPartialTranscript(jobID string, req *models.TranslateRequest, start float64, end float64, text string) {
+	if req.ProfanityFilter {
+		text, _ = moderation.NewProfanityFilter(cfg.ProfanityWords).Mask(text)
+	}
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		status.AddPartialTranscript(models.TranscriptSegment{Start: start, End: end, Text: text})
+	})
+}
+
+// dubbingVoiceGender returns the gender of the default voices dubbing a job, empty for each language's default voice
+func dubbingVoiceGender(req *models.TranslateRequest, checkpoint *models.JobCheckpoint) string {
+	if req.VoiceGender == models.VoiceGenderMatch {
+		return checkpoint.SpeakerGender
+	}
+	return req.VoiceGender
+}
+
+// useGeminiPipeline reports whether a job's audio goes to Gemini for combined transcription and translation
+// Only short clips qualify, and requests whose translation depends on the per-job pipeline (style
+// instructions, profanity masking) or that need speech adaptation (hint phrases) keep the classic
+// speech-to-text and translation steps. So do deployments that gate transcripts on their confidence
+// (STT_MIN_CONFIDENCE), which Gemini does not report, reuse translations of earlier jobs
+// (TRANSLATION_MEMORY_BACKEND) or route a target language to its own providers (TRANSLATION_ROUTES).
+func useGeminiPipeline(req *models.TranslateRequest, duration float64) bool {
+	if geminiClient == nil || duration > cfg.GeminiMaxDuration.Seconds() {
+		return false
+	}
+	if cfg.STTMinConfidence > 0 || translationMemory != nil {
+		return false
+	}
+	routes := translators.Routes()
+	for _, lang := range req.TargetLanguages {
+		if _, ok := routes[lang]; ok {
+			return false
+		}
+	}
+	if req.Transcription != nil && len(req.Transcription.HintPhrases) > 0 {
+		return false
+	}
+	return req.StyleInstructions == "" && !req.ProfanityFilter
+}
+
+// recognitionOptions combines the configured speech recognition settings with the request's overrides
+func recognitionOptions(req *models.TranslateRequest) (stt.Options, stt.ExtractOptions) {
+	recognition := stt.Options{
+		LanguageHint:             req.SourceLanguage,
+		ProfanityFilter:          req.ProfanityFilter,
+		Model:                    cfg.STTModel,
+		AutomaticPunctuation:     cfg.STTAutomaticPunctuation || punctuateTranscripts(),
+		AlternativeLanguageCodes: cfg.STTAlternativeLanguages,
+		SampleRate:               cfg.STTSampleRate,
+	}
+	extraction := stt.ExtractOptions{
+		Track:      -1,
+		Channel:    cfg.STTAudioChannel,
+		SampleRate: cfg.STTSampleRate,
+	}
+	if req.SourceAudioTrack != nil {
+		extraction.Track = *req.SourceAudioTrack
+	}
+
+	if overrides := req.Transcription; overrides != nil {
+		if overrides.Model != "" {
+			recognition.Model = overrides.Model
+		}
+		if overrides.AutomaticPunctuation != nil {
+			recognition.AutomaticPunctuation = *overrides.AutomaticPunctuation
+		}
+		if len(overrides.AlternativeLanguages) > 0 {
+			recognition.AlternativeLanguageCodes = overrides.AlternativeLanguages
+		}
+		if overrides.AudioChannel != nil {
+			extraction.Channel = *overrides.AudioChannel
+		}
+		recognition.HintPhrases = overrides.HintPhrases
+	}
+	return recognition, extraction
+}
+
+// loadSourceSubtitles reads and parses the request's source subtitles, clipped to the requested range
+func loadSourceSubtitles(ctx context.Context, req *models.TranslateRequest) ([]subtitles.Cue, error) {
+	bucket, path, err := storage.ParseGCSURL(req.SubtitleURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse subtitle URL: %w", err)
+	}
+	source, err := sourceStorage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := source.ReadBytes(ctx, bucket, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download subtitles: %w", err)
+	}
+	cues, err := subtitles.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse subtitles: %w", err)
+	}
+	if req.IsClip() {
+		cues = subtitles.Clip(cues, req.StartTime, req.EndTime)
+		if len(cues) == 0 {
+			return nil, fmt.Errorf("no subtitle cues within the requested clip range")
+		}
+	}
+	return cues, nil
+}
+
+// processLanguages translates, dubs and uploads the given target languages from a checkpoint,
+// then updates the overall job status and sends notifications
+func processLanguages(ctx context.Context, jobID string, req *models.TranslateRequest, checkpoint *models.JobCheckpoint, languages []string) {
+	setJobStage(jobID, models.StageLanguages)
+
+	// Repeated sentences are translated and synthesized once per language
+	memory := translation.NewMemory()
+	if translationMemory != nil {
+		// Sentences translated by the tenant's earlier jobs with the same providers and style are reused as well
+		memory = translation.NewMemoryWithStore(translationMemory, func(targetLanguage string) string {
+			return req.Tenant + "/" + translationNamespace(req, targetLanguage)
+		})
+	}
+
+	// Process each target language concurrently
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, cfg.MaxConcurrentTranslations)
+
+	for _, targetLang := range languages {
+		// Check context cancellation before processing each language
+		select {
+		case <-ctx.Done():
+			slog.Warn("Processing cancelled, stopping language processing", "jobID", jobID)
+			// Mark remaining languages as failed
+			jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+				if status.Results == nil {
+					status.Results = make(map[string]*models.LanguageResult)
+				}
+				for _, lang := range languages {
+					if result, exists := status.Results[lang]; !exists || !result.IsFinished() {
+						status.Results[lang] = &models.LanguageResult{
+							Status:    models.StatusFailed,
+							Direction: models.LanguageDirection(lang),
+							Error:     "processing cancelled",
+						}
+					}
+				}
+				status.UpdatedAt = time.Now()
+			})
+			updateJobError(jobID, "processing cancelled: "+ctx.Err().Error())
+			return
+		default:
+		}
+
+		wg.Add(1)
+		go func(lang string) {
+			defer wg.Done()
+			semaphore <- struct{}{}        // Acquire semaphore
+			defer func() { <-semaphore }() // Release semaphore
+
+			dest := outputDestination(req, lang)
+			result := processLanguage(ctx, jobID, req, memory, checkpoint, lang, dest)
+
+			// Thread-safe update using UpdateStatusSafely; webhook event payloads are built under the same lock
+			var events []notification.Payload
+			jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+				if status.Results == nil {
+					status.Results = make(map[string]*models.LanguageResult)
+				}
+				status.Results[lang] = result
+				delete(status.LanguageProgress, lang)
+				status.UpdatedAt = time.Now()
+				status.RecordEvent(models.EventLanguageCompleted, lang, result.Error)
+				status.Progress = api.JobProgress(status) // The store recomputes it after the update; events need it now
+				events = languageEvents(status, lang)
+			})
+			notifyWebhookEvents(req, events)
+			replicateOutputs(jobID, lang, result, dest, req.Tenant)
+		}(targetLang)
+	}
+
+	// Wait for all goroutines with context cancellation support
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		// All goroutines completed
+	case <-ctx.Done():
+		slog.Warn("Context cancelled while waiting for goroutines", "jobID", jobID)
+		// Context is cancelled, but goroutines should finish quickly
+		// Wait with timeout for goroutines to clean up
+		timeout := time.NewTimer(5 * time.Second)
+		defer timeout.Stop()
+		select {
+		case <-done:
+			// Goroutines finished quickly
+		case <-timeout.C:
+			slog.Warn("Goroutines did not complete within timeout after cancellation", "jobID", jobID)
+		}
+	}
+
+	// Check context cancellation after all languages processed
+	select {
+	case <-ctx.Done():
+		updateJobError(jobID, "processing cancelled: "+ctx.Err().Error())
+		return
+	default:
+	}
+
+	setJobStage(jobID, models.StageFinalizing)
+
+	// Update final status using thread-safe update
+	var finalStatus models.TranslationStatus
+	// Completed languages stay available when others fail: the job is then partially completed
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		allCompleted := true
+		anyCompleted := false
+		anyFailed := false
+		for _, result := range status.Results {
+			if result.Status == models.StatusCompleted {
+				anyCompleted = true
+				continue
+			}
+			// Skipped languages count neither for nor against the job
+			if result.Status == models.StatusSkipped {
+				continue
+			}
+			allCompleted = false
+			if result.Status == models.StatusFailed {
+				anyFailed = true
+			}
+		}
+
+		if allCompleted {
+			status.Status = models.StatusCompleted
+			status.Stage = ""
+			finalStatus = models.StatusCompleted
+			status.RecordEvent(models.EventJobCompleted, "", "")
+		} else if anyFailed && anyCompleted {
+			status.Status = models.StatusPartiallyCompleted
+			status.Stage = ""
+			finalStatus = models.StatusPartiallyCompleted
+			status.RecordEvent(models.EventJobPartial, "", "")
+		} else if anyFailed {
+			status.Status = models.StatusFailed
+			status.ErrorCode = failedErrorCode(status.Results)
+			finalStatus = models.StatusFailed
+			status.RecordEvent(models.EventJobFailed, "", "")
+		}
+		status.UpdatedAt = time.Now()
+		status.Usage = status.Meter.Snapshot(cfg.PriceTable())
+	})
+
+	// Publish the job manifest for preset outputs
+	if req.Preset != "" {
+		if err := uploadManifest(ctx, jobID, req.Preset, jobDestination(req)); err != nil {
+			slog.Warn("Failed to upload job manifest", "error", err, "jobID", jobID)
+		}
+	}
+
+	// Publish a results page reviewers can open without API access
+	if cfg.PreviewPage {
+		if err := uploadPreviewPage(ctx, jobID, checkpoint, jobDestination(req)); err != nil {
+			slog.Warn("Failed to upload preview page", "error", err, "jobID", jobID)
+		}
+	}
+
+	hits, misses := memory.Stats()
+	slog.Info("Translation processing completed", "jobID", jobID, "status", finalStatus, "memoryHits", hits, "memoryMisses", misses, "memoryStoredHits", memory.StoredHits())
+
+	// Failed languages keep the source video around for a retry
+	if finalStatus == models.StatusCompleted {
+		releaseCheckpointVideo(jobID)
+		recordCompletedJob(jobID)
+	} else if finalStatus == models.StatusFailed || finalStatus == models.StatusPartiallyCompleted {
+		// A scheduled retry reports the outcome once it finishes
+		if scheduleTransientRetry(jobID) {
+			return
+		}
+		releaseDuplicateClaim(jobID)
+	}
+
+	// Send notifications if configured
+	notifyJob(jobID)
+}
+
+func processLanguage(ctx context.Context, jobID string, req *models.TranslateRequest, memory *translation.Memory, checkpoint *models.JobCheckpoint, targetLanguage string, dest storage.Destination) *models.LanguageResult {
+	timings := &models.LanguageTimings{}
+	result := &models.LanguageResult{
+		Status:    models.StatusProcessing,
+		Progress:  0,
+		Timings:   timings,
+		Direction: models.LanguageDirection(targetLanguage),
+	}
+
+	slog.Info("Processing language", "jobID", jobID, "targetLanguage", targetLanguage)
+	ctx = storage.WithObjectMetadata(ctx, "language", targetLanguage)
+
+	// Check context cancellation before translation
+	select {
+	case <-ctx.Done():
+		result.Status = models.StatusFailed
+		result.Error = "processing cancelled: " + ctx.Err().Error()
+		result.Progress = 0
+		return result
+	default:
+	}
+
+	// A target language matching the source language needs no translation or dubbing
+	if validator.IsSameLanguage(checkpoint.SourceLanguage, targetLanguage) {
+		if cfg.SameLanguagePolicy == "skip" {
+			slog.Info("Skipping target language matching the source language", "jobID", jobID, "targetLanguage", targetLanguage)
+			result.Status = models.StatusSkipped
+			now := time.Now()
+			result.ProcessedAt = &now
+			return result
+		}
+		return passthroughLanguage(ctx, jobID, req, checkpoint, targetLanguage, dest, result)
+	}
+
+	// Translate text
+	result.Progress = 20
+	setLanguageProgress(jobID, targetLanguage, result.Progress)
+	translateStart := time.Now()
+	var segments []string
+	var reusedSegments int
+	var err error
+	if translated, ok := checkpoint.Translations[targetLanguage]; ok {
+		// Already translated together with the transcript by the Gemini pipeline
+		segments = []string{translated}
+	} else if checkpoint.Chapters != nil {
+		// Long videos are translated chapter by chapter, one segment per chapter
+		segments, reusedSegments, err = translateChapters(ctx, req, memory, checkpoint, targetLanguage)
+	} else if checkpoint.Cues != nil {
+		// Source subtitles are translated cue by cue so their timings carry over
+		cueTexts := make([]string, len(checkpoint.Cues))
+		for i, cue := range checkpoint.Cues {
+			cueTexts[i] = cue.Text
+		}
+		segments, reusedSegments, err = memory.TranslateSegments(ctx, cueTexts, checkpoint.SourceLanguage, targetLanguage, jobTranslateFunc(req))
+	} else {
+		segments, reusedSegments, err = memory.Translate(ctx, checkpoint.Transcript, checkpoint.SourceLanguage, targetLanguage, jobTranslateFunc(req))
+	}
+	timings.TranslateMs = models.ElapsedMs(translateStart)
+	if err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			result.Status = models.StatusFailed
+			result.Error = "translation cancelled: " + ctx.Err().Error()
+		} else {
+			result.Status = models.StatusFailed
+			result.Error = "translation failed: " + err.Error()
+			result.Transient = transient.IsTransient(err)
+		}
+		result.Progress = 0
+		slog.Error("Translation failed", "jobID", jobID, "targetLanguage", targetLanguage, "error", err)
+		return result
+	}
+
+	// Locale formats (TEXT_PROCESSORS) apply to the translation as published in text and subtitles
+	for i, segment := range segments {
+		segments[i] = textProcessors.Text(segment, targetLanguage)
+	}
+	translatedText := strings.Join(segments, " ")
+	result.ReusedSegments = reusedSegments
+
+	// Translated cues keep the source timings; otherwise timings are estimated from text length
+	var cues []subtitles.Cue
+	if checkpoint.Cues != nil {
+		cues = make([]subtitles.Cue, len(segments))
+		for i, segment := range segments {
+			cues[i] = subtitles.Cue{Start: checkpoint.Cues[i].Start, End: checkpoint.Cues[i].End, Text: segment}
+		}
+	} else if checkpoint.Chapters != nil {
+		cues = chapterCues(checkpoint.Chapters, segments)
+	} else {
+		cues = subtitles.EstimateCues(translation.SplitSentences(translatedText), checkpoint.VideoDuration)
+	}
+	result.Progress = 40
+	setLanguageProgress(jobID, targetLanguage, result.Progress)
+
+	// Check context cancellation before TTS generation
+	select {
+	case <-ctx.Done():
+		result.Status = models.StatusFailed
+		result.Error = "processing cancelled: " + ctx.Err().Error()
+		result.Progress = 0
+		return result
+	default:
+	}
+
+	// Generate TTS audio
+	ttsStart := time.Now()
+	audioPath, err := createTempFile(fmt.Sprintf("audio_%s_%s.mp3", jobID, targetLanguage))
+	if err != nil {
+		result.Status = models.StatusFailed
+		result.Error = "failed to create temp file: " + err.Error()
+		result.Progress = 0
+		return result
+	}
+	defer os.Remove(audioPath)
+
+	// Speech-only processing (e.g., spoken units) changes what is synthesized but not the subtitles
+	speechSegments := make([]string, len(segments))
+	for i, segment := range segments {
+		speechSegments[i] = textProcessors.Speech(segment, targetLanguage)
+	}
+	speechCues := make([]subtitles.Cue, len(cues))
+	for i, cue := range cues {
+		speechCues[i] = cue
+		speechCues[i].Text = textProcessors.Speech(cue.Text, targetLanguage)
+	}
+
+	voice, _ := tts.ParseVoiceID(req.VoiceID) // Validated at submission
+	ttsOptions := tts.Options{Lexicon: req.Pronunciations[targetLanguage], Voice: voice, Gender: dubbingVoiceGender(req, checkpoint)}
+	if chain := voiceRoutes[targetLanguage]; voice == nil && len(chain) > 0 {
+		// A requested voiceId dubs every language; otherwise the language's TTS_ROUTES chain applies
+		ttsOptions.Voice, ttsOptions.Fallbacks = chain[0], chain[1:]
+	}
+	synthesize := func(rateScale float64) error {
+		opts := ttsOptions
+		opts.RateScale = rateScale
+		if checkpoint.Cues != nil {
+			return tts.GenerateTimedTTS(ctx, speechCues, targetLanguage, audioPath, opts)
+		}
+		if reusedSegments > 0 {
+			_, err := tts.GenerateSegmentedTTS(ctx, speechSegments, targetLanguage, checkpoint.VideoDuration, audioPath, opts)
+			return err
+		}
+		return tts.GenerateTTSWithOptions(ctx, strings.Join(speechSegments, " "), targetLanguage, checkpoint.VideoDuration, audioPath, opts)
+	}
+	var chapterAudio []string
+	if checkpoint.Chapters != nil {
+		// Each chapter is spoken at the rate fitting its own duration
+		chapterAudio, err = synthesizeChapters(ctx, jobID, checkpoint, targetLanguage, speechSegments, ttsOptions, audioPath, result)
+		defer removeChapterFiles(jobID, chapterAudio)
+	} else {
+		err = apiPool.Do(ctx, func() error {
+			return synthesize(0)
+		})
+	}
+	timings.TTSMs = models.ElapsedMs(ttsStart)
+	if err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			result.Status = models.StatusFailed
+			result.Error = "TTS generation cancelled: " + ctx.Err().Error()
+		} else {
+			result.Status = models.StatusFailed
+			result.Error = "TTS generation failed: " + err.Error()
+			result.Transient = transient.IsTransient(err)
+		}
+		result.Progress = 0
+		return result
+	}
+
+	if checkpoint.Chapters == nil {
+		// Chapter speech is trimmed and verified chapter by chapter; subtitle-timed speech keeps its pauses
+		// and rate per cue, so it is neither trimmed nor corrected
+		timed := checkpoint.Cues != nil
+		if !timed {
+			trimSpeechSilence(ctx, jobID, targetLanguage, audioPath)
+		}
+		verifyDubbingDuration(ctx, jobID, targetLanguage, audioPath, checkpoint.VideoDuration, timed, synthesize, result)
+	}
+	timings.TTSMs = models.ElapsedMs(ttsStart)
+
+	result.Progress = 60
+	setLanguageProgress(jobID, targetLanguage, result.Progress)
+
+	// Check context cancellation before audio sync
+	select {
+	case <-ctx.Done():
+		result.Status = models.StatusFailed
+		result.Error = "processing cancelled: " + ctx.Err().Error()
+		result.Progress = 0
+		return result
+	default:
+	}
+
+	// Sync audio with video
+	muxStart := time.Now()
+	outputVideoPath, err := createTempFile(fmt.Sprintf("video_%s_%s.mp4", jobID, targetLanguage))
+	if err != nil {
+		result.Status = models.StatusFailed
+		result.Error = "failed to create temp file: " + err.Error()
+		result.Progress = 0
+		return result
+	}
+	defer os.Remove(outputVideoPath)
+
+	syncOptions := video.SyncOptions{
+		BackgroundPath:    checkpoint.BackgroundPath,
+		BackgroundVolume:  cfg.BackgroundMusicVolume,
+		Rotation:          checkpoint.Rotation,
+		NormalizeRotation: cfg.VideoRotation == "normalize",
+		Metadata:          outputMetadata(checkpoint, targetLanguage),
+		AudioLanguage:     video.LanguageISO3(targetLanguage),
+		Audio:             audioEncoding(req),
+	}
+	if checkpoint.Chapters != nil {
+		err = muxChapters(ctx, jobID, checkpoint, chapterAudio, outputVideoPath, syncOptions)
+	} else {
+		err = muxDubbedVideo(ctx, jobID, checkpoint.VideoPath, audioPath, outputVideoPath, checkpoint.Video, syncOptions)
+	}
+	timings.MuxMs = models.ElapsedMs(muxStart)
+	if err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			result.Status = models.StatusFailed
+			result.Error = "audio sync cancelled: " + ctx.Err().Error()
+		} else {
+			result.Status = models.StatusFailed
+			result.Error = "audio sync failed: " + err.Error()
+		}
+		result.Progress = 0
+		return result
+	}
+
+	// Overlay the watermark and join the bumper clips
+	err = applyBranding(ctx, checkpoint.Branding, audioEncoding(req), outputVideoPath)
+	timings.MuxMs = models.ElapsedMs(muxStart)
+	if err != nil {
+		result.Status = models.StatusFailed
+		if ctx.Err() != nil {
+			result.Error = "branding cancelled: " + ctx.Err().Error()
+		} else {
+			result.Error = "branding failed: " + err.Error()
+		}
+		result.Progress = 0
+		return result
+	}
+
+	result.Progress = 80
+	setLanguageProgress(jobID, targetLanguage, result.Progress)
+
+	// Upload to GCS
+	uploadStart := time.Now()
+	defer func() {
+		timings.UploadMs = models.ElapsedMs(uploadStart)
+	}()
+	videoObject := languageVideoPath(jobID, targetLanguage)
+	outputPath := dest.Path(videoObject)
+	err = storageClient.Upload(storage.AsJobOutput(ctx), dest.Bucket, outputPath, outputVideoPath)
+	if err != nil {
+		result.Status = models.StatusFailed
+		result.Error = "upload failed: " + err.Error()
+		result.ErrorCode = storageErrorCode(err)
+		result.Transient = transient.IsTransient(err)
+		result.Progress = 0
+		return result
+	}
+	result.OutputObjects = append(result.OutputObjects, videoObject)
+
+	// Upload the translated text and subtitles alongside the video
+	result.TranscriptURL = checkpoint.TranscriptURL
+	timedCues := cues // Chapter markers follow the speech, not the subtitle offset
+	cues = layoutSubtitles(req, targetLanguage, cues, checkpoint.VideoDuration)
+	if err := uploadTextArtifacts(ctx, jobID, targetLanguage, translatedText, cues, dest, result); err != nil {
+		result.Status = models.StatusFailed
+		result.Error = "text artifacts upload failed: " + err.Error()
+		result.ErrorCode = storageErrorCode(err)
+		result.Transient = transient.IsTransient(err)
+		result.Progress = 0
+		return result
+	}
+
+	if req.Summary {
+		summarizeLanguage(ctx, jobID, targetLanguage, translatedText, dest, result)
+	}
+	if req.Analysis {
+		analyzeLanguage(ctx, jobID, targetLanguage, timedCues, checkpoint.VideoDuration, dest, result)
+	}
+
+	// Publish the dubbed speech on its own for downstream muxing or audio distribution
+	format := dubbedAudioFormat(req)
+	if format != "" {
+		audioURL, err := uploadDubbedAudio(ctx, jobID, targetLanguage, audioPath, format, audioEncoding(req), dest, result)
+		if err != nil {
+			result.Status = models.StatusFailed
+			result.Error = "dubbed audio upload failed: " + err.Error()
+			result.ErrorCode = storageErrorCode(err)
+			result.Transient = transient.IsTransient(err)
+			result.Progress = 0
+			return result
+		}
+		result.AudioURL = audioURL
+	}
+
+	// Upload the accessibility bundle alongside the video, reusing an MP3 dubbed audio upload
+	if req.Preset == models.PresetAccessibility {
+		audioURL := result.AudioURL
+		if format != models.AudioFormatMP3 {
+			var err error
+			audioURL, err = uploadDubbedAudio(ctx, jobID, targetLanguage, audioPath, models.AudioFormatMP3, audioEncoding(req), dest, result)
+			if err != nil {
+				result.Status = models.StatusFailed
+				result.Error = "accessibility outputs failed: " + err.Error()
+				result.Progress = 0
+				return result
+			}
+		}
+		result.Artifacts = map[string]string{
+			models.ArtifactCaptions:   result.SubtitlesURL,
+			models.ArtifactTranscript: result.TranslatedTextURL,
+			models.ArtifactAudio:      audioURL,
+		}
+	}
+
+	result.Progress = 100
+	result.Status = models.StatusCompleted
+	result.VideoURL = storageClient.GetPublicURL(dest.Bucket, outputPath)
+	result.TranslatedText = translatedText
+	now := time.Now()
+	result.ProcessedAt = &now
+
+	slog.Info("Language processing completed", "jobID", jobID, "targetLanguage", targetLanguage)
+	return result
+}
+
+// passthroughLanguage publishes the source video and transcript as the output of a target language that
+// matches the source language, without translation or dubbing (SAME_LANGUAGE_POLICY=passthrough)
+func passthroughLanguage(ctx context.Context, jobID string, req *models.TranslateRequest, checkpoint *models.JobCheckpoint, targetLanguage string, dest storage.Destination, result *models.LanguageResult) *models.LanguageResult {
+	slog.Info("Passing through target language matching the source language", "jobID", jobID, "targetLanguage", targetLanguage)
+	result.Passthrough = true
+
+	// Branding rewrites the video in place, so it is applied to a copy of the source
+	muxStart := time.Now()
+	outputVideoPath, err := createTempFile(fmt.Sprintf("video_%s_%s.mp4", jobID, targetLanguage))
+	if err != nil {
+		result.Status = models.StatusFailed
+		result.Error = "failed to create temp file: " + err.Error()
+		return result
+	}
+	defer os.Remove(outputVideoPath)
+
+	if err := copyFile(checkpoint.VideoPath, outputVideoPath); err != nil {
+		result.Status = models.StatusFailed
+		result.Error = "failed to copy source video: " + err.Error()
+		return result
+	}
+	err = applyBranding(ctx, checkpoint.Branding, audioEncoding(req), outputVideoPath)
+	result.Timings.MuxMs = models.ElapsedMs(muxStart)
+	if err != nil {
+		result.Status = models.StatusFailed
+		if ctx.Err() != nil {
+			result.Error = "branding cancelled: " + ctx.Err().Error()
+		} else {
+			result.Error = "branding failed: " + err.Error()
+		}
+		return result
+	}
+
+	result.Progress = 80
+	setLanguageProgress(jobID, targetLanguage, result.Progress)
+	uploadStart := time.Now()
+	defer func() {
+		result.Timings.UploadMs = models.ElapsedMs(uploadStart)
+	}()
+
+	videoObject := languageVideoPath(jobID, targetLanguage)
+	outputPath := dest.Path(videoObject)
+	if err := storageClient.Upload(storage.AsJobOutput(ctx), dest.Bucket, outputPath, outputVideoPath); err != nil {
+		result.Status = models.StatusFailed
+		result.Error = "upload failed: " + err.Error()
+		result.ErrorCode = storageErrorCode(err)
+		result.Transient = transient.IsTransient(err)
+		result.Progress = 0
+		return result
+	}
+	result.OutputObjects = append(result.OutputObjects, videoObject)
+
+	// The source transcript stands in for the translated text
+	cues := checkpoint.Cues
+	if cues == nil {
+		cues = subtitles.EstimateCues(translation.SplitSentences(checkpoint.Transcript), checkpoint.VideoDuration)
+	}
+	result.TranscriptURL = checkpoint.TranscriptURL
+	timedCues := cues // Chapter markers follow the speech, not the subtitle offset
+	cues = layoutSubtitles(req, targetLanguage, cues, checkpoint.VideoDuration)
+	if err := uploadTextArtifacts(ctx, jobID, targetLanguage, checkpoint.Transcript, cues, dest, result); err != nil {
+		result.Status = models.StatusFailed
+		result.Error = "text artifacts upload failed: " + err.Error()
+		result.ErrorCode = storageErrorCode(err)
+		result.Transient = transient.IsTransient(err)
+		result.Progress = 0
+		return result
+	}
+	if req.Summary {
+		summarizeLanguage(ctx, jobID, targetLanguage, checkpoint.Transcript, dest, result)
+	}
+	if req.Analysis {
+		analyzeLanguage(ctx, jobID, targetLanguage, timedCues, checkpoint.VideoDuration, dest, result)
+	}
+
+	// There is no dubbed audio track, so the accessibility bundle holds the captions and transcript
+	if req.Preset == models.PresetAccessibility {
+		result.Artifacts = map[string]string{
+			models.ArtifactCaptions:   result.SubtitlesURL,
+			models.ArtifactTranscript: result.TranslatedTextURL,
+		}
+	}
+
+	result.Progress = 100
+	result.Status = models.StatusCompleted
+	result.VideoURL = storageClient.GetPublicURL(dest.Bucket, outputPath)
+	result.TranslatedText = checkpoint.Transcript
+	now := time.Now()
+	result.ProcessedAt = &now
+
+	slog.Info("Language passed through", "jobID", jobID, "targetLanguage", targetLanguage)
+	return result
+}
+
+// muxDubbedVideo muxes the dubbed audio into the source video. With a transcode backend, videos at least
+// TRANSCODE_MIN_HEIGHT tall, as probed once in stream, are muxed remotely; others, videos that could not be probed
+// and options the backend cannot apply use local ffmpeg.
+func muxDubbedVideo(ctx context.Context, jobID string, videoPath string, audioPath string, outputPath string, stream *models.VideoStream, opts video.SyncOptions) error {
+	if remoteMuxer != nil && stream != nil && stream.Height >= cfg.TranscodeMinHeight {
+		slog.Info("Muxing with transcode backend", "jobID", jobID, "backend", remoteMuxer.Name(), "height", stream.Height)
+		err := remoteMuxer.Mux(ctx, transcode.Input{VideoPath: videoPath, AudioPath: audioPath, Video: stream, Options: opts}, outputPath)
+		if !errors.Is(err, transcode.ErrUnsupported) {
+			return err
+		}
+		slog.Info("Transcode backend cannot apply the mux options, muxing locally", "jobID", jobID, "backend", remoteMuxer.Name())
+	}
+
+	return ffmpegPool.Do(ctx, func() error {
+		return video.SyncAudioWithVideoOptions(ctx, videoPath, audioPath, outputPath, opts)
+	})
+}
+
+// trimSpeechSilence removes the leading and trailing silence of generated speech and shortens its long pauses
+// (TTS_SILENCE_TRIM), so duration checks and the mux see the speech only. Failures keep the untrimmed audio.
+func trimSpeechSilence(ctx context.Context, jobID string, targetLanguage string, audioPath string) {
+	if !cfg.TTSSilenceTrim {
+		return
+	}
+	trimmedPath := strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + "_trimmed" + filepath.Ext(audioPath)
+	err := ffmpegPool.Do(ctx, func() error {
+		return video.TrimSilence(ctx, audioPath, trimmedPath, video.SilenceTrim{
+			ThresholdDB: cfg.TTSSilenceThreshold,
+			MaxPause:    cfg.TTSMaxPause.Seconds(),
+		})
+	})
+	if err == nil {
+		err = os.Rename(trimmedPath, audioPath)
+	}
+	if err != nil {
+		os.Remove(trimmedPath)
+		slog.Warn("Failed to trim silence from speech, keeping it untrimmed", "jobID", jobID, "targetLanguage", targetLanguage, "error", err)
+	}
+}
+
+// verifyDubbingDuration measures the dubbed audio against the video and, when they differ by more than
+// DUBBING_DURATION_TOLERANCE and a DUBBING_CORRECTION is configured, re-synthesizes the speech at a corrected
+// rate (resynthesize) and retimes whatever drift remains with ffmpeg atempo. Subtitle-timed speech (timed)
+// already follows its cues, so its drift is only recorded. The final drift is recorded on the result.
+// Verification is best effort: on failure the audio is kept as generated.
+func verifyDubbingDuration(ctx context.Context, jobID string, targetLanguage string, audioPath string, videoDuration float64, timed bool, resynthesize func(rateScale float64) error, result *models.LanguageResult) {
+	tolerance := cfg.DubbingDurationTolerance
+	if tolerance <= 0 || videoDuration <= 0 {
+		return
+	}
+
+	audioDuration, err := video.GetAudioDuration(ctx, audioPath)
+	if err != nil {
+		slog.Warn("Failed to measure dubbed audio duration", "jobID", jobID, "targetLanguage", targetLanguage, "error", err)
+		return
+	}
+	exceeds := func() bool {
+		return !timed && math.Abs(audioDuration-videoDuration)/videoDuration > tolerance
+	}
+
+	if exceeds() && cfg.DubbingCorrection == "resynthesize" && resynthesize != nil {
+		slog.Info("Re-synthesizing speech to correct duration drift",
+			"jobID", jobID,
+			"targetLanguage", targetLanguage,
+			"audioDuration", audioDuration,
+			"videoDuration", videoDuration)
+		err := apiPool.Do(ctx, func() error {
+			return resynthesize(audioDuration / videoDuration)
+		})
+		if err == nil {
+			result.DurationCorrection = append(result.DurationCorrection, "resynthesize")
+			trimSpeechSilence(ctx, jobID, targetLanguage, audioPath)
+			audioDuration, err = video.GetAudioDuration(ctx, audioPath)
+		}
+		if err != nil {
+			slog.Warn("Failed to re-synthesize speech", "jobID", jobID, "targetLanguage", targetLanguage, "error", err)
+			return
+		}
+	}
+
+	if exceeds() && cfg.IsDubbingCorrectionEnabled() {
+		slog.Info("Retiming dubbed audio to correct duration drift",
+			"jobID", jobID,
+			"targetLanguage", targetLanguage,
+			"audioDuration", audioDuration,
+			"videoDuration", videoDuration)
+		retimedPath := strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + "_retimed" + filepath.Ext(audioPath)
+		err := ffmpegPool.Do(ctx, func() error {
+			return video.AdjustTempo(ctx, audioPath, retimedPath, audioDuration/videoDuration)
+		})
+		if err == nil {
+			err = os.Rename(retimedPath, audioPath)
+		}
+		if err == nil {
+			result.DurationCorrection = append(result.DurationCorrection, "atempo")
+			audioDuration, err = video.GetAudioDuration(ctx, audioPath)
+		}
+		if err != nil {
+			os.Remove(retimedPath)
+			slog.Warn("Failed to retime dubbed audio", "jobID", jobID, "targetLanguage", targetLanguage, "error", err)
+			return
+		}
+	}
+
+	drift := math.Round((audioDuration-videoDuration)*1000) / 1000
+	result.DurationDrift = &drift
+}
+
+// audioEncoding returns the output audio format of a job: OUTPUT_AUDIO_* overridden field by field by the request
+func audioEncoding(req *models.TranslateRequest) video.AudioEncoding {
+	encoding := video.AudioEncoding{
+		SampleRate: cfg.OutputAudioSampleRate,
+		Channels:   models.ChannelCount(cfg.OutputAudioChannels),
+		Bitrate:    cfg.OutputAudioBitrate,
+	}
+	if override := req.OutputAudio; override != nil {
+		if override.SampleRate > 0 {
+			encoding.SampleRate = override.SampleRate
+		}
+		if channels := models.ChannelCount(override.ChannelLayout); channels > 0 {
+			encoding.Channels = channels
+		}
+		if override.Bitrate > 0 {
+			encoding.Bitrate = override.Bitrate
+		}
+	}
+	return encoding
+}
+
+// applyBranding re-encodes a dubbed video in place with the job's watermark and bumper clips
+// Joining bumpers re-encodes the audio too, in the job's output audio format.
+func applyBranding(ctx context.Context, branding *models.BrandingAssets, audio video.AudioEncoding, videoPath string) error {
+	if branding == nil {
+		return nil
+	}
+	brandedPath := strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + "_branded" + filepath.Ext(videoPath)
+	err := ffmpegPool.Do(ctx, func() error {
+		return video.ApplyBranding(ctx, videoPath, brandedPath, video.BrandingOptions{
+			WatermarkPath:     branding.WatermarkPath,
+			WatermarkPosition: branding.WatermarkPosition,
+			WatermarkOpacity:  cfg.WatermarkOpacity,
+			IntroPath:         branding.IntroPath,
+			OutroPath:         branding.OutroPath,
+			Audio:             audio,
+		})
+	})
+	if err == nil {
+		err = os.Rename(brandedPath, videoPath)
+	}
+	if err != nil {
+		os.Remove(brandedPath)
+		return err
+	}
+	return nil
+}
+
+// jobTranslateFunc returns the batch translation function for a job, routing each target language
+// to its provider chain with fallback
+func jobTranslateFunc(req *models.TranslateRequest) translation.BatchTranslateFunc {
+	return translators.Router(func(name string, service translation.TranslationService) translation.BatchTranslateFunc {
+		return providerTranslateFunc(req, name, service)
+	})
+}
+
+// providerTranslateFunc returns the batch translation function of one provider for a job, applying its
+// style instructions and serving previously translated texts from the cache
+func providerTranslateFunc(req *models.TranslateRequest, name string, service translation.TranslationService) translation.BatchTranslateFunc {
+	translate := service.TranslateBatch
+	if llm, ok := service.(*translation.LLMTranslator); ok {
+		if req.StyleInstructions != "" {
+			translate = func(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+				return llm.TranslateBatchWithStyle(ctx, texts, sourceLanguage, targetLanguage, req.StyleInstructions)
+			}
+		}
+	}
+
+	// Only calls that reach the provider take an API worker; cache hits do not
+	providerTranslate := translate
+	translate = func(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) (translations []string, err error) {
+		err = apiPool.Do(ctx, func() error {
+			translations, err = providerTranslate(ctx, texts, sourceLanguage, targetLanguage)
+			return err
+		})
+		if err == nil {
+			characters := 0
+			for _, text := range texts {
+				characters += utf8.RuneCountInString(text)
+			}
+			usage.FromContext(ctx).AddTranslateCharacters(name, characters)
+		}
+		return translations, err
+	}
+
+	if resultCache == nil {
+		return translate
+	}
+	return cache.BatchTranslate(resultCache, providerNamespace(req, service), translate)
+}
+
+// providerNamespace identifies everything of a provider that changes its translations of a job
+func providerNamespace(req *models.TranslateRequest, service translation.TranslationService) string {
+	if llm, ok := service.(*translation.LLMTranslator); ok {
+		// Model and style instructions change the output, so they are part of the namespace
+		return llm.Name() + "/" + llm.Model + "/" + req.StyleInstructions
+	}
+	return service.Name()
+}
+
+// translationNamespace identifies the provider chain of a target language and its settings for a job,
+// so translations stored in the translation memory are only reused by jobs translating the same way
+func translationNamespace(req *models.TranslateRequest, targetLanguage string) string {
+	chain := translators.Chain(targetLanguage)
+	namespaces := make([]string, len(chain))
+	for i, name := range chain {
+		namespaces[i] = providerNamespace(req, translators.Providers()[name])
+	}
+	return strings.Join(namespaces, ",")
+}
+
+// layoutSubtitles shifts the subtitle cues of a language by the subtitle offset and lays them out to the
+// subtitle profile of the request or deployment; without a profile, cue text and timings are kept
+func layoutSubtitles(req *models.TranslateRequest, language string, cues []subtitles.Cue, duration float64) []subtitles.Cue {
+	offset := cfg.SubtitleOffset
+	if req.SubtitleOffset != nil {
+		offset = *req.SubtitleOffset
+	}
+	cues = subtitles.Shift(cues, offset, duration)
+
+	name := req.SubtitleProfile
+	if name == "" {
+		name = cfg.SubtitleProfile
+	}
+	if profile, ok := cfg.SubtitleProfileByName(name); name != "" && ok {
+		cues = subtitles.Layout(cues, language, profile, duration)
+	}
+	return cues
+}
+
+// uploadTextArtifacts uploads the translated text and subtitles for one language
+// Files are stored under translations/{jobId}/{language}/ in the destination and their URLs recorded on the result.
+// Lines of right-to-left languages that start with left-to-right text get a right-to-left mark.
+func uploadTextArtifacts(ctx context.Context, jobID string, language string, translatedText string, cues []subtitles.Cue, dest storage.Destination, result *models.LanguageResult) error {
+	prefix := fmt.Sprintf("translations/%s/%s", jobID, language)
+	if models.IsRTLLanguage(language) {
+		translatedText = subtitles.MarkRTL(translatedText)
+		cues = subtitles.MarkRTLCues(cues)
+	}
+
+	translationObject := prefix + "/translation.txt"
+	translationPath := dest.Path(translationObject)
+	if err := storageClient.UploadBytes(storage.AsJobOutput(ctx), dest.Bucket, translationPath, []byte(translatedText), "text/plain; charset=utf-8"); err != nil {
+		return fmt.Errorf("translated text upload failed: %w", err)
+	}
+	result.TranslatedTextURL = storageClient.GetPublicURL(dest.Bucket, translationPath)
+	result.OutputObjects = append(result.OutputObjects, translationObject)
+
+	subtitlesObject := prefix + "/captions.vtt"
+	subtitlesPath := dest.Path(subtitlesObject)
+	if err := storageClient.UploadBytes(storage.AsJobOutput(ctx), dest.Bucket, subtitlesPath, []byte(subtitles.FormatVTT(cues)), "text/vtt; charset=utf-8"); err != nil {
+		return fmt.Errorf("subtitles upload failed: %w", err)
+	}
+	result.SubtitlesURL = storageClient.GetPublicURL(dest.Bucket, subtitlesPath)
+	result.OutputObjects = append(result.OutputObjects, subtitlesObject)
+
+	return nil
+}
+
+// summarizeLanguage writes a short summary of the video from the text of a language, in that language, and
+// uploads it as translations/{jobId}/{language}/summary.txt
+// A summary that cannot be written leaves the language's outputs complete, with a job warning.
+func summarizeLanguage(ctx context.Context, jobID string, language string, text string, dest storage.Destination, result *models.LanguageResult) {
+	summarizer := transcriptSummarizer()
+	if summarizer == nil {
+		// Mock mode has no LLM provider
+		addJobWarning(jobID, fmt.Sprintf("no summary was written for %s: no LLM translation provider is configured", language))
+		return
+	}
+
+	// Long videos are summarized from their leading sentences so the call fits the model's context
+	if input := transcript.Truncate(text, cfg.LLMMaxInputChars); input != text {
+		addJobWarning(jobID, fmt.Sprintf("the summary for %s only covers the first %d characters of the translation", language, transcript.Length(input)))
+		text = input
+	}
+
+	var summary string
+	err := apiPool.Do(ctx, func() error {
+		var err error
+		summary, err = summarizer.Describe(ctx, text, language, cfg.SummaryMaxChars)
+		return err
+	})
+	if err != nil {
+		slog.Warn("Failed to summarize language", "error", err, "jobID", jobID, "language", language)
+		addJobWarning(jobID, fmt.Sprintf("no summary was written for %s: %v", language, err))
+		return
+	}
+	usage.FromContext(ctx).AddTranslateCharacters(summarizer.Name(), transcript.Length(text))
+
+	// The model may overshoot the requested length
+	summary = transcript.Truncate(summary, cfg.SummaryMaxChars)
+	if models.IsRTLLanguage(language) {
+		summary = subtitles.MarkRTL(summary)
+	}
+	summaryObject := fmt.Sprintf("translations/%s/%s/summary.txt", jobID, language)
+	summaryPath := dest.Path(summaryObject)
+	if err := storageClient.UploadBytes(storage.AsJobOutput(ctx), dest.Bucket, summaryPath, []byte(summary), "text/plain; charset=utf-8"); err != nil {
+		slog.Warn("Failed to upload summary", "error", err, "jobID", jobID, "language", language)
+		addJobWarning(jobID, fmt.Sprintf("the summary for %s could not be uploaded: %v", language, err))
+		return
+	}
+	result.Summary = summary
+	result.SummaryURL = storageClient.GetPublicURL(dest.Bucket, summaryPath)
+	result.OutputObjects = append(result.OutputObjects, summaryObject)
+}
+
+// analyzeLanguage extracts keywords and chapter markers from the unshifted cues of a language and uploads them as
+// translations/{jobId}/{language}/analysis.json and chapters.txt (YouTube-style chapter lines)
+// An analysis that cannot be made leaves the language's outputs complete, with a job warning.
+func analyzeLanguage(ctx context.Context, jobID string, language string, cues []subtitles.Cue, duration float64, dest storage.Destination, result *models.LanguageResult) {
+	analyzer := transcriptSummarizer()
+	if analyzer == nil {
+		// Mock mode has no LLM provider
+		addJobWarning(jobID, fmt.Sprintf("no analysis was made for %s: no LLM translation provider is configured", language))
+		return
+	}
+
+	// Long videos are analyzed from their leading cues so the call fits the model's context
+	timed, truncated := analysis.TimedTranscript(cues, cfg.LLMMaxInputChars)
+	if truncated {
+		addJobWarning(jobID, fmt.Sprintf("the analysis for %s only covers the first %d characters of the timed transcript", language, transcript.Length(timed)))
+	}
+	var reply string
+	err := apiPool.Do(ctx, func() error {
+		var err error
+		reply, err = analyzer.Analyze(ctx, timed, language, models.MaxAnalysisKeywords)
+		return err
+	})
+	if err != nil {
+		slog.Warn("Failed to analyze language", "error", err, "jobID", jobID, "language", language)
+		addJobWarning(jobID, fmt.Sprintf("no analysis was made for %s: %v", language, err))
+		return
+	}
+	usage.FromContext(ctx).AddTranslateCharacters(analyzer.Name(), transcript.Length(timed))
+
+	extracted, err := analysis.Parse(reply, duration)
+	if err != nil {
+		slog.Warn("Failed to parse language analysis", "error", err, "jobID", jobID, "language", language)
+		addJobWarning(jobID, fmt.Sprintf("no analysis was made for %s: %v", language, err))
+		return
+	}
+	data, err := json.MarshalIndent(extracted, "", "  ")
+	if err != nil {
+		addJobWarning(jobID, fmt.Sprintf("no analysis was made for %s: %v", language, err))
+		return
+	}
+
+	prefix := fmt.Sprintf("translations/%s/%s", jobID, language)
+	analysisObject, chaptersObject := prefix+"/analysis.json", prefix+"/chapters.txt"
+	analysisPath, chaptersPath := dest.Path(analysisObject), dest.Path(chaptersObject)
+	err = storageClient.UploadBytes(storage.AsJobOutput(ctx), dest.Bucket, analysisPath, data, "application/json")
+	if err == nil {
+		err = storageClient.UploadBytes(storage.AsJobOutput(ctx), dest.Bucket, chaptersPath, []byte(analysis.YouTubeChapters(extracted.Chapters)), "text/plain; charset=utf-8")
+	}
+	if err != nil {
+		slog.Warn("Failed to upload language analysis", "error", err, "jobID", jobID, "language", language)
+		addJobWarning(jobID, fmt.Sprintf("the analysis for %s could not be uploaded: %v", language, err))
+		return
+	}
+	result.Analysis = extracted
+	result.AnalysisURL = storageClient.GetPublicURL(dest.Bucket, analysisPath)
+	result.ChaptersURL = storageClient.GetPublicURL(dest.Bucket, chaptersPath)
+	result.OutputObjects = append(result.OutputObjects, analysisObject, chaptersObject)
+}
+
+// addJobWarning records a warning on the job status
+func addJobWarning(jobID string, warning string) {
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		status.Warnings = append(status.Warnings, warning)
+	})
+}
+
+// outputDestination returns where outputs for a language are written
+// A per-request destination takes precedence over OUTPUT_DESTINATIONS, which takes precedence over the job destination.
+func outputDestination(req *models.TranslateRequest, language string) storage.Destination {
+	for _, configured := range []string{req.OutputDestinations[language], cfg.OutputDestinations[language]} {
+		if configured == "" {
+			continue
+		}
+		if dest, err := storage.ParseDestination(configured); err == nil {
+			return tenantNamespace(dest, req.Tenant)
+		}
+	}
+	return jobDestination(req)
+}
+
+// jobDestination returns where the job-wide outputs (transcript, manifest, preview page) and by default the
+// language outputs are written: the request's outputBucket, the tenant's TENANT_BUCKETS destination, or else
+// the output bucket
+func jobDestination(req *models.TranslateRequest) storage.Destination {
+	dest := storage.Destination{Bucket: cfg.GCSOutputBucket}
+	if req.OutputBucket != "" {
+		dest = storage.Destination{Bucket: req.OutputBucket}
+	} else if configured := cfg.TenantBuckets[req.Tenant]; req.Tenant != "" && configured != "" {
+		if tenantDest, err := storage.ParseDestination(configured); err == nil {
+			dest = tenantDest
+		}
+	}
+	return tenantNamespace(dest, req.Tenant)
+}
+
+// tenantNamespace moves a destination under tenants/{owner} with TENANT_NAMESPACES, so the outputs of
+// different API key owners never share a path
+func tenantNamespace(dest storage.Destination, tenant string) storage.Destination {
+	if cfg.TenantNamespaces && tenant != "" {
+		dest.Prefix = dest.Path("tenants/" + tenant)
+	}
+	return dest
+}
+
+// checkOutputDestinations verifies the service account can write to every routed destination of a request
+// Returns the offending destination with the error.
+func checkOutputDestinations(ctx context.Context, req *models.TranslateRequest) (string, error) {
+	for _, lang := range req.TargetLanguages {
+		dest := outputDestination(req, lang)
+		if dest.Bucket == cfg.GCSOutputBucket {
+			continue
+		}
+		if checked, ok := writableBuckets.Load(dest.Bucket); ok && time.Since(checked.(time.Time)) < writableBucketTTL {
+			continue
+		}
+		writable, err := storageClient.CanWrite(ctx, dest.Bucket)
+		if err != nil {
+			return dest.String(), err
+		}
+		if !writable {
+			return dest.String(), fmt.Errorf("output destination %s is not writable by the service account", dest.String())
+		}
+		writableBuckets.Store(dest.Bucket, time.Now())
+	}
+	return "", nil
+}
+
+// dubbedAudioFormat returns the format the dubbed speech is uploaded in on its own, or "" when it is not
+// A request's dubbedAudio overrides DUBBED_AUDIO_FORMAT.
+func dubbedAudioFormat(req *models.TranslateRequest) string {
+	format := cfg.DubbedAudioFormat
+	if req.DubbedAudio != "" {
+		format = req.DubbedAudio
+	}
+	if format == models.AudioFormatNone {
+		return ""
+	}
+	return format
+}
 
-			// Thread-safe update using UpdateStatusSafely
+// uploadDubbedAudio uploads the dubbed audio track for one language to translations/{jobId}/{language}/audio.{format}
+// The synthesized MP3 is uploaded as is; WAV is decoded to PCM in the job's output sample rate and channels.
+func uploadDubbedAudio(ctx context.Context, jobID string, language string, audioPath string, format string, encoding video.AudioEncoding, dest storage.Destination, result *models.LanguageResult) (string, error) {
+	if format == models.AudioFormatWAV {
+		wavPath, err := createTempFile(fmt.Sprintf("audio_%s_%s_*.wav", jobID, language))
+		if err != nil {
+			return "", fmt.Errorf("failed to create temp file: %w", err)
+		}
+		defer os.Remove(wavPath)
+		err = ffmpegPool.Do(ctx, func() error {
+			return video.ExportWAV(ctx, audioPath, wavPath, encoding)
+		})
+		if err != nil {
+			return "", err
+		}
+		audioPath = wavPath
+	}
+
+	audioObject := fmt.Sprintf("translations/%s/%s/audio.%s", jobID, language, format)
+	audioOutputPath := dest.Path(audioObject)
+	if err := storageClient.Upload(storage.AsJobOutput(ctx), dest.Bucket, audioOutputPath, audioPath); err != nil {
+		return "", fmt.Errorf("audio upload failed: %w", err)
+	}
+	result.OutputObjects = append(result.OutputObjects, audioObject)
+	return storageClient.GetPublicURL(dest.Bucket, audioOutputPath), nil
+}
+
+// uploadManifest writes translations/{jobId}/manifest.json describing all outputs and records its URL
+func uploadManifest(ctx context.Context, jobID string, preset string, dest storage.Destination) error {
+	status, err := jobStore.GetStatus(jobID)
+	if err != nil {
+		return err
+	}
+
+	manifest := models.Manifest{
+		JobID:     jobID,
+		Preset:    preset,
+		Status:    status.Status,
+		Languages: make(map[string]*models.ManifestEntry),
+	}
+	for lang, result := range status.Results {
+		manifest.Languages[lang] = &models.ManifestEntry{
+			Status:    result.Status,
+			Direction: result.Direction,
+			VideoURL:  result.VideoURL,
+			Artifacts: result.Artifacts,
+			Summary:   result.Summary,
+
+			AnalysisURL: result.AnalysisURL,
+			ChaptersURL: result.ChaptersURL,
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	manifestPath := dest.Path(fmt.Sprintf("translations/%s/manifest.json", jobID))
+	if err := storageClient.UploadBytes(storage.AsJobOutput(ctx), dest.Bucket, manifestPath, data, "application/json"); err != nil {
+		return err
+	}
+
+	return jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		status.ManifestURL = storageClient.GetPublicURL(dest.Bucket, manifestPath)
+	})
+}
+
+// uploadPreviewPage renders the job's results page and uploads it to translations/{jobId}/index.html
+// The page links the outputs by their public URLs, wherever their destination bucket is.
+func uploadPreviewPage(ctx context.Context, jobID string, checkpoint *models.JobCheckpoint, dest storage.Destination) error {
+	status, err := jobStore.GetStatus(jobID)
+	if err != nil {
+		return err
+	}
+
+	page := preview.Page{
+		JobID:         jobID,
+		Title:         checkpoint.Title,
+		Status:        string(status.Status),
+		TranscriptURL: checkpoint.TranscriptURL,
+		GeneratedAt:   time.Now().UTC(),
+	}
+	for lang, result := range status.Results {
+		page.Languages = append(page.Languages, preview.Language{
+			Code:              lang,
+			Name:              video.LanguageName(lang),
+			Status:            string(result.Status),
+			Error:             result.Error,
+			VideoURL:          result.VideoURL,
+			SubtitlesURL:      result.SubtitlesURL,
+			AudioURL:          result.AudioURL,
+			TranslatedTextURL: result.TranslatedTextURL,
+		})
+	}
+	page.SortLanguages()
+
+	data, err := preview.Render(page)
+	if err != nil {
+		return err
+	}
+
+	pagePath := dest.Path(fmt.Sprintf("translations/%s/index.html", jobID))
+	if err := storageClient.UploadBytes(storage.AsJobOutput(ctx), dest.Bucket, pagePath, data, "text/html; charset=utf-8"); err != nil {
+		return err
+	}
+
+	return jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		status.PreviewURL = storageClient.GetPublicURL(dest.Bucket, pagePath)
+	})
+}
+
+// exportJob writes a job's record to exports/{jobId}/job.json in the export bucket
+// With artifacts, the record and every output file are also zipped to exports/{jobId}/job.zip.
+func exportJob(ctx context.Context, job *models.JobArchive, artifacts bool) (*models.ExportResponse, error) {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job archive: %w", err)
+	}
+
+	dest := tenantNamespace(storage.Destination{Bucket: cfg.ExportBucket}, job.Owner)
+	recordPath := dest.Path(fmt.Sprintf("exports/%s/job.json", job.JobID))
+	if err := storageClient.UploadBytes(storage.AsJobOutput(ctx), dest.Bucket, recordPath, data, "application/json"); err != nil {
+		return nil, err
+	}
+	response := &models.ExportResponse{
+		JobID:      job.JobID,
+		ArchiveURL: storageClient.GetPublicURL(dest.Bucket, recordPath),
+	}
+	if !artifacts {
+		return response, nil
+	}
+
+	zipPath, err := createTempFile(fmt.Sprintf("export_%s_*.zip", job.JobID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zip file: %w", err)
+	}
+	defer removeTempFile(job.JobID, zipPath)
+	if err := writeExportZip(ctx, job, data, zipPath); err != nil {
+		return nil, err
+	}
+
+	zipObjectPath := dest.Path(fmt.Sprintf("exports/%s/job.zip", job.JobID))
+	if err := storageClient.Upload(storage.AsJobOutput(ctx), dest.Bucket, zipObjectPath, zipPath); err != nil {
+		return nil, err
+	}
+	response.ZipURL = storageClient.GetPublicURL(dest.Bucket, zipObjectPath)
+	return response, nil
+}
+
+// writeExportZip writes the job record and its output files, downloaded one at a time, to a local zip
+func writeExportZip(ctx context.Context, job *models.JobArchive, record []byte, zipPath string) error {
+	file, err := os.Create(zipPath)
+	if err != nil {
+		return fmt.Errorf("failed to create zip file: %w", err)
+	}
+	defer file.Close()
+
+	zipWriter, err := archive.NewZip(file, record)
+	if err != nil {
+		return err
+	}
+	for _, output := range job.Outputs {
+		bucket, path, err := storage.ParseGCSURL(output.URL)
+		if err != nil {
+			return fmt.Errorf("failed to parse output URL: %w", err)
+		}
+		localPath, err := storageClient.Download(ctx, bucket, path)
+		if err != nil {
+			return fmt.Errorf("failed to download %s: %w", output.Name, err)
+		}
+		err = zipWriter.AddFile(output.Name, localPath)
+		removeTempFile(job.JobID, localPath)
+		if err != nil {
+			return err
+		}
+	}
+	return zipWriter.Close()
+}
+
+// retryLanguages re-runs failed languages of a job from its checkpoint, the way runJob runs new jobs
+// The job has already been claimed (status set to processing) and admitted by the retry handler
+func retryLanguages(jobID string, languages []string, release func()) {
+	status, err := jobStore.GetStatus(jobID)
+	if err != nil {
+		slog.Error("Failed to load job for retry", "error", err, "jobID", jobID)
+		release()
+		return
+	}
+	req, checkpoint := status.Request, status.Checkpoint
+
+	runJob(jobID, status.Meter, release, func(ctx context.Context) {
+		slog.Info("Retrying failed languages", "jobID", jobID, "languages", languages)
+
+		// Download the source video again only if the local copy was released
+		if _, err := os.Stat(checkpoint.VideoPath); checkpoint.VideoPath == "" || err != nil {
+			videoPath, err := downloadSourceVideo(ctx, jobID, req)
+			if err != nil {
+				failLanguages(jobID, languages, err.Error())
+				return
+			}
 			jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
-				if status.Results == nil {
-					status.Results = make(map[string]*models.LanguageResult)
-				}
-				status.Results[lang] = result
-				status.UpdatedAt = time.Now()
+				checkpoint.VideoPath = videoPath
 			})
-		}(targetLang)
+		}
+
+		processLanguages(ctx, jobID, req, checkpoint, languages)
+	})
+}
+
+// restartJob runs a job again from the download, the way runJob runs new jobs
+// The job has already been reset to processing and admitted by the task retry handler
+func restartJob(jobID string, release func()) {
+	status, err := jobStore.GetStatus(jobID)
+	if err != nil {
+		slog.Error("Failed to load job for restart", "error", err, "jobID", jobID)
+		release()
+		return
 	}
+	req := status.Request
 
-	// Wait for all goroutines with context cancellation support
-	done := make(chan struct{})
-	go func() {
-		wg.Wait()
-		close(done)
-	}()
+	runJob(jobID, status.Meter, release, func(ctx context.Context) {
+		processTranslation(ctx, jobID, req)
+	})
+}
 
-	select {
-	case <-done:
-		// All goroutines completed
-	case <-ctx.Done():
-		slog.Warn("Context cancelled while waiting for goroutines", "jobID", jobID)
-		// Context is cancelled, but goroutines should finish quickly
-		// Wait with timeout for goroutines to clean up
-		timeout := time.NewTimer(5 * time.Second)
-		defer timeout.Stop()
-		select {
-		case <-done:
-			// Goroutines finished quickly
-		case <-timeout.C:
-			slog.Warn("Goroutines did not complete within timeout after cancellation", "jobID", jobID)
+// scheduleTransientRetry re-enqueues a job that failed only on transient provider errors
+// Returns false when retries are disabled, exhausted, the failure is permanent or scheduling fails.
+func scheduleTransientRetry(jobID string) bool {
+	if retryScheduler == nil {
+		return false
+	}
+
+	var attempt int
+	var at time.Time
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		if !api.IsTransientFailure(status) || status.RetryAttempts >= cfg.TransientRetryMaxAttempts {
+			return
+		}
+		// The delay doubles with every attempt
+		attempt = status.RetryAttempts + 1
+		at = time.Now().Add(cfg.TransientRetryDelay << (attempt - 1))
+		status.RetryAttempts = attempt
+		status.NextRetryAt = &at
+	})
+	if attempt == 0 {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := retryScheduler.ScheduleRetry(ctx, tasks.RetryTask{JobID: jobID, Attempt: attempt}, at); err != nil {
+		slog.Error("Failed to schedule job retry", "error", err, "jobID", jobID, "attempt", attempt)
+		jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+			status.RetryAttempts = attempt - 1
+			status.NextRetryAt = nil
+		})
+		return false
+	}
+	return true
+}
+
+// failLanguages marks the given languages and the job as failed
+func failLanguages(jobID string, languages []string, errorMsg string) {
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		for _, lang := range languages {
+			status.Results[lang] = &models.LanguageResult{
+				Status:    models.StatusFailed,
+				Direction: models.LanguageDirection(lang),
+				Error:     errorMsg,
+			}
+		}
+	})
+	updateJobError(jobID, errorMsg)
+}
+
+// outputMetadata returns the container tags set on a dubbed video: its title from OUTPUT_TITLE_TEMPLATE
+func outputMetadata(checkpoint *models.JobCheckpoint, language string) map[string]string {
+	if cfg.OutputTitleTemplate == "" || checkpoint.Title == "" {
+		return nil
+	}
+	return map[string]string{"title": video.FormatTitle(cfg.OutputTitleTemplate, checkpoint.Title, language)}
+}
+
+// releaseCheckpointVideo deletes the checkpointed source video and other local files once no retry can need them
+func releaseCheckpointVideo(jobID string) {
+	var files []string
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		if status.Checkpoint != nil {
+			files = status.Checkpoint.TempFiles()
+			status.Checkpoint.VideoPath, status.Checkpoint.BackgroundPath = "", ""
+			for i := range status.Checkpoint.Chapters {
+				status.Checkpoint.Chapters[i].VideoPath, status.Checkpoint.Chapters[i].BackgroundPath = "", ""
+			}
+			status.Checkpoint.Branding = nil
+		}
+	})
+	for _, path := range files {
+		removeTempFile(jobID, path)
+	}
+}
+
+// releaseExpiredCheckpoint deletes the checkpointed source video and other local files of a job evicted from the store
+// and forgets the job's last notified webhook state
+func releaseExpiredCheckpoint(jobID string, status *models.StatusResponse) {
+	webhookChanges.Forget(jobID)
+	if status.Checkpoint != nil {
+		for _, path := range status.Checkpoint.TempFiles() {
+			removeTempFile(jobID, path)
 		}
 	}
+}
 
-	// Check context cancellation after all languages processed
-	select {
-	case <-ctx.Done():
-		updateJobError(jobID, "processing cancelled: "+ctx.Err().Error())
+func removeTempFile(jobID string, path string) {
+	if path == "" {
 		return
-	default:
 	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		slog.Warn("Failed to cleanup temp file", "file", path, "error", err, "jobID", jobID)
+	}
+}
 
-	// Update final status using thread-safe update
-	var finalStatus models.TranslationStatus
+// finishStalledJob completes a job the store failed as stalled: stops a hung worker on this instance,
+// records usage, lets the job be resubmitted and notifies the client
+func finishStalledJob(jobID string, _ *models.StatusResponse) {
+	if runningJobs.Cancel(jobID) {
+		slog.Warn("Cancelled stalled job worker", "jobID", jobID)
+	}
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		status.Usage = status.Meter.Snapshot(cfg.PriceTable())
+	})
+	releaseDuplicateClaim(jobID)
+	notifyJob(jobID)
+}
+
+// failJob marks a job and its unfinished languages as failed, e.g. when stopped from the admin API
+func failJob(jobID string, reason string) {
 	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
-		allCompleted := true
-		anyFailed := false
 		for _, result := range status.Results {
-			if result.Status != models.StatusCompleted {
-				allCompleted = false
-				if result.Status == models.StatusFailed {
-					anyFailed = true
-				}
+			if !result.IsFinished() {
+				result.Status = models.StatusFailed
+				result.Error = reason
+				result.Progress = 0
 			}
 		}
+	})
+	updateJobError(jobID, reason)
+}
 
-		if allCompleted {
-			status.Status = models.StatusCompleted
-			finalStatus = models.StatusCompleted
-		} else if anyFailed {
-			status.Status = models.StatusFailed
-			finalStatus = models.StatusFailed
+func updateJobError(jobID string, errorMsg string) {
+	updateJobErrorCode(jobID, "", errorMsg)
+}
+
+// updateJobErrorCause marks a job as failed, recording whether the cause was a transient provider error
+func updateJobErrorCause(jobID string, cause error, errorMsg string) {
+	if transient.IsTransient(cause) {
+		jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+			status.TransientFailure = true
+		})
+	}
+	updateJobErrorCode(jobID, storageErrorCode(cause), errorMsg)
+}
+
+// storageErrorCode returns ERR_INTEGRITY for transfers that failed checksum verification and ERR_SOURCE_TOO_LARGE
+// for sources over the download limit, otherwise empty
+func storageErrorCode(err error) string {
+	if errors.Is(err, storage.ErrChecksumMismatch) {
+		return models.ErrCodeIntegrity
+	}
+	if errors.Is(err, storage.ErrSourceTooLarge) {
+		return models.ErrCodeSourceTooLarge
+	}
+	return ""
+}
+
+// failedErrorCode returns the error code shared by all failed languages, or empty when they differ
+func failedErrorCode(results map[string]*models.LanguageResult) string {
+	code := ""
+	for _, result := range results {
+		if result.Status != models.StatusFailed {
+			continue
+		}
+		if result.ErrorCode == "" || (code != "" && result.ErrorCode != code) {
+			return ""
 		}
+		code = result.ErrorCode
+	}
+	return code
+}
+
+// updateJobErrorCode marks a job as failed with a machine-readable error code (may be empty)
+func updateJobErrorCode(jobID string, code string, errorMsg string) {
+	alreadyFailed := false
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		// A job stopped by an administrator fails once; its cancelled worker must not notify again
+		if status.Status == models.StatusFailed {
+			alreadyFailed = true
+			return
+		}
+		status.Status = models.StatusFailed
+		status.ErrorCode = code
 		status.UpdatedAt = time.Now()
+		status.Usage = status.Meter.Snapshot(cfg.PriceTable())
+		status.RecordEvent(models.EventJobFailed, "", errorMsg)
+		// A job failing before its checkpoint never completes the transcript the partial one stands for
+		status.PartialTranscript = nil
+		// Languages that never started fail with the job; a job without results gets a generic error
+		for _, result := range status.Results {
+			if result.Status == models.StatusPending {
+				result.Status = models.StatusFailed
+				result.Error = errorMsg
+				result.ErrorCode = code
+			}
+		}
+		if len(status.Results) == 0 {
+			status.Results = make(map[string]*models.LanguageResult)
+			status.Results["error"] = &models.LanguageResult{
+				Status: models.StatusFailed,
+				Error:  errorMsg,
+			}
+		}
 	})
+	if alreadyFailed {
+		slog.Warn("Job already failed", "jobID", jobID, "error", errorMsg)
+		return
+	}
+	slog.Error("Job failed", "jobID", jobID, "error", errorMsg)
 
-	slog.Info("Translation processing completed", "jobID", jobID, "status", finalStatus)
+	// A scheduled retry reports the outcome once it finishes
+	if scheduleTransientRetry(jobID) {
+		return
+	}
+	releaseDuplicateClaim(jobID)
 
-	// Send webhook notification if configured
-	if cfg.WebhookURL != "" {
-		go func() {
-			status, err := jobStore.GetStatus(jobID)
-			if err == nil && status != nil {
-				// Use background context for webhook since main context may be cancelled
-				webhookCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-				defer cancel()
-				if err := api.NotifyWebhook(webhookCtx, cfg.WebhookURL, status); err != nil {
-					slog.Warn("Webhook notification failed", "error", err, "jobID", jobID)
-					// Don't fail the job if webhook fails
-				}
-			}
-		}()
+	// Send notifications if configured
+	notifyJob(jobID)
+}
+
+// recordCompletedJob indexes a completed job so identical submissions reuse its results (RESULT_REUSE_WINDOW)
+func recordCompletedJob(jobID string) {
+	status, err := jobStore.GetStatus(jobID)
+	if err != nil {
+		return
 	}
+	resultIndex.Record(status.ContentKey, jobID)
 }
 
-func processLanguage(ctx context.Context, jobID string, originalText string, sourceLanguage string, targetLanguage string, videoPath string, videoDuration float64, outputBucket string) *models.LanguageResult {
-	result := &models.LanguageResult{
-		Status:   models.StatusProcessing,
-		Progress: 0,
+// releaseDuplicateClaim lets a failed job be submitted again before the duplicate window ends
+func releaseDuplicateClaim(jobID string) {
+	status, err := jobStore.GetStatus(jobID)
+	if err != nil || status.Request == nil {
+		return
 	}
+	duplicateDetector.Release(api.JobFingerprint(status.Owner, status.Request.SourceKey(), status.Request.TargetLanguages), jobID)
+}
 
-	slog.Info("Processing language", "jobID", jobID, "targetLanguage", targetLanguage)
+// notifyJob sends the current job status to every configured notification channel
+// Delivery runs in the background and never fails the job
+func notifyJob(jobID string) {
+	progressTracker.Forget(jobID)
+	deliverNotification(jobID, func() {
+		status, err := jobStore.GetStatus(jobID)
+		if err != nil || status == nil {
+			return
+		}
 
-	// Check context cancellation before translation
-	select {
-	case <-ctx.Done():
-		result.Status = models.StatusFailed
-		result.Error = "processing cancelled: " + ctx.Err().Error()
-		result.Progress = 0
-		return result
-	default:
+		targets := jobNotifiers(status)
+		if len(targets) == 0 {
+			return
+		}
+
+		// Use background context since the processing context may be cancelled; each attempt has its own timeout
+		if err := notification.NotifyAll(context.Background(), targets, status); err != nil {
+			slog.Warn("Job notification failed", "error", err, "jobID", jobID)
+		}
+	})
+}
+
+// languageEvents returns the language.completed and job.progress payloads for a finished language,
+// limited to the job's selected webhook events and the minimum progress delta
+func languageEvents(status *models.StatusResponse, language string) []notification.Payload {
+	webhook := jobWebhook(status.Request)
+	if webhook == nil {
+		return nil
 	}
 
-	// Translate text
-	result.Progress = 20
-	translatedText, err := translation.TranslateText(ctx, originalText, sourceLanguage, targetLanguage)
-	if err != nil {
-		// Check if error is due to context cancellation
-		if ctx.Err() != nil {
-			result.Status = models.StatusFailed
-			result.Error = "translation cancelled: " + ctx.Err().Error()
-		} else {
-			result.Status = models.StatusFailed
-			result.Error = "translation failed: " + err.Error()
+	var events []notification.Payload
+	if webhook.Events.Allows(models.EventLanguageCompleted) {
+		events = append(events, notification.NewLanguagePayload(status, language))
+	}
+	if webhook.Events.Allows(models.EventJobProgress) {
+		progress := notification.NewProgressPayload(status)
+		if progressTracker.ShouldSend(status.JobID, progress.Progress, webhook.Events.MinProgressDelta) {
+			events = append(events, progress)
 		}
-		result.Progress = 0
-		slog.Error("Translation failed", "jobID", jobID, "targetLanguage", targetLanguage, "error", err)
-		return result
 	}
 
-	result.Progress = 40
+	// Events sent together share the changes since the previous notification
+	if len(events) > 0 {
+		changes := webhookChanges.Diff(status, events[0].Timestamp)
+		for i := range events {
+			events[i].Changes = changes
+		}
+	}
+	return events
+}
 
-	// Check context cancellation before TTS generation
-	select {
-	case <-ctx.Done():
-		result.Status = models.StatusFailed
-		result.Error = "processing cancelled: " + ctx.Err().Error()
-		result.Progress = 0
-		return result
-	default:
+// jobStartedEvents returns the job.started webhook payload of a job when its webhook selects the event
+func jobStartedEvents(status *models.StatusResponse) []notification.Payload {
+	webhook := jobWebhook(status.Request)
+	if webhook == nil || !webhook.Events.Allows(models.EventJobStarted) {
+		return nil
 	}
+	payload := notification.NewStartedPayload(status)
+	payload.Changes = webhookChanges.Diff(status, payload.Timestamp)
+	return []notification.Payload{payload}
+}
 
-	// Generate TTS audio
-	audioPath, err := createTempFile(fmt.Sprintf("audio_%s_%s.mp3", jobID, targetLanguage))
-	if err != nil {
-		result.Status = models.StatusFailed
-		result.Error = "failed to create temp file: " + err.Error()
-		result.Progress = 0
-		return result
+// notifyWebhookEvents delivers intermediate webhook events in the background, in order
+func notifyWebhookEvents(req *models.TranslateRequest, events []notification.Payload) {
+	webhook := jobWebhook(req)
+	if webhook == nil || len(events) == 0 {
+		return
 	}
-	defer os.Remove(audioPath)
+	deliverNotification(events[0].JobID, func() {
+		for _, payload := range events {
+			if err := webhook.NotifyEvent(context.Background(), payload); err != nil {
+				slog.Warn("Webhook event delivery failed", "error", err, "jobID", payload.JobID, "event", payload.Event)
+			}
+		}
+	})
+}
 
-	err = tts.GenerateTTS(ctx, translatedText, targetLanguage, videoDuration, audioPath)
-	if err != nil {
-		// Check if error is due to context cancellation
-		if ctx.Err() != nil {
-			result.Status = models.StatusFailed
-			result.Error = "TTS generation cancelled: " + ctx.Err().Error()
-		} else {
-			result.Status = models.StatusFailed
-			result.Error = "TTS generation failed: " + err.Error()
+// deliverNotification runs a notification delivery on the bounded notification queue,
+// or inline when CPU is only allocated during requests. Deliveries that do not fit in the queue are dropped.
+func deliverNotification(jobID string, deliver func()) {
+	if !cfg.CPUAlwaysAllocated {
+		deliver()
+		return
+	}
+	if !notificationQueue.Submit(deliver) {
+		slog.Warn("Notification queue is full, dropping notification", "jobID", jobID, "pending", notificationQueue.Pending())
+	}
+}
+
+// runBackground runs fn in a goroutine, or inline when CPU is only allocated during requests
+// so that work started by a job finishes before the job's request returns
+func runBackground(fn func()) {
+	if cfg.CPUAlwaysAllocated {
+		go fn()
+		return
+	}
+	fn()
+}
+
+// startupCheck reports whether the instance can process jobs: ffmpeg is installed and the temp workspace is writable
+func startupCheck() error {
+	for _, tool := range []string{"ffmpeg", "ffprobe"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return fmt.Errorf("%s not found: %w", tool, err)
 		}
-		result.Progress = 0
-		return result
 	}
+	probe, err := os.CreateTemp("", "startup-*")
+	if err != nil {
+		return fmt.Errorf("temp directory is not writable: %w", err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
 
-	result.Progress = 60
+// jobWebhook returns the webhook notifier for a job with its event selection, or nil if no webhook is configured
+// A request's webhookEvents override WEBHOOK_EVENTS.
+func jobWebhook(req *models.TranslateRequest) *notification.WebhookNotifier {
+	if cfg.WebhookURL == "" {
+		return nil
+	}
+	webhook := notification.NewWebhookNotifier(cfg.WebhookURL)
+	webhook.Policy = webhookPolicy()
+	webhook.Pool = webhookPool
+	webhook.Changes = webhookChanges
+	webhook.Events = notification.EventFilter{
+		Events:           cfg.WebhookEvents,
+		MinProgressDelta: cfg.WebhookMinProgressDelta,
+	}
+	if req != nil && len(req.WebhookEvents) > 0 {
+		webhook.Events.Events = req.WebhookEvents
+	}
+	return webhook
+}
 
-	// Check context cancellation before audio sync
-	select {
-	case <-ctx.Done():
-		result.Status = models.StatusFailed
-		result.Error = "processing cancelled: " + ctx.Err().Error()
-		result.Progress = 0
-		return result
-	default:
+// webhookPolicy returns the webhook retry policy configured by WEBHOOK_TIMEOUT, WEBHOOK_MAX_RETRIES and WEBHOOK_BACKOFF
+func webhookPolicy() notification.RetryPolicy {
+	return notification.RetryPolicy{
+		MaxAttempts: cfg.WebhookMaxRetries + 1,
+		Backoff:     cfg.WebhookBackoff,
+		Timeout:     cfg.WebhookTimeout,
 	}
+}
 
-	// Sync audio with video
-	outputVideoPath, err := createTempFile(fmt.Sprintf("video_%s_%s.mp4", jobID, targetLanguage))
-	if err != nil {
-		result.Status = models.StatusFailed
-		result.Error = "failed to create temp file: " + err.Error()
-		result.Progress = 0
-		return result
+// jobNotifiers returns the deployment-wide notifiers plus any requested by the job itself
+func jobNotifiers(status *models.StatusResponse) []notification.Notifier {
+	result := append([]notification.Notifier{}, notifiers...)
+	if webhook := jobWebhook(status.Request); webhook != nil {
+		result = append(result, webhook)
 	}
-	defer os.Remove(outputVideoPath)
+	if emailSender != nil && status.Request != nil && status.Request.NotifyEmail != "" {
+		result = append(result, notification.NewEmailNotifier(emailSender, cfg.EmailFrom, status.Request.NotifyEmail))
+	}
+	return result
+}
 
-	err = video.SyncAudioWithVideo(ctx, videoPath, audioPath, outputVideoPath)
-	if err != nil {
-		// Check if error is due to context cancellation
-		if ctx.Err() != nil {
-			result.Status = models.StatusFailed
-			result.Error = "audio sync cancelled: " + ctx.Err().Error()
-		} else {
-			result.Status = models.StatusFailed
-			result.Error = "audio sync failed: " + err.Error()
+// newNotifiers creates the notification sinks enabled in config; each is configured independently
+// The webhook is created per job (see jobWebhook) since jobs can select their own events.
+func newNotifiers(ctx context.Context, cfg *config.Config) ([]notification.Notifier, error) {
+	var result []notification.Notifier
+	if cfg.PubSubTopic != "" {
+		pubsubNotifier, err := notification.NewPubSubNotifier(ctx, cfg.PubSubTopic)
+		if err != nil {
+			return nil, err
 		}
-		result.Progress = 0
-		return result
+		result = append(result, pubsubNotifier)
 	}
+	if cfg.SlackWebhookURL != "" {
+		result = append(result, notification.NewSlackNotifier(cfg.SlackWebhookURL))
+	}
+	return result, nil
+}
 
-	result.Progress = 80
+// newStorage creates the GCS storage client, or filesystem storage under DEV_STORAGE_DIR with mock providers
+func newStorage(ctx context.Context, cfg *config.Config) (storage.Storage, error) {
+	if cfg.DevMockProviders {
+		return storage.NewLocalStorage(cfg.DevStorageDir)
+	}
 
-	// Upload to GCS
-	outputPath := fmt.Sprintf("translations/%s/%s.mp4", jobID, targetLanguage)
-	err = storageClient.Upload(ctx, outputBucket, outputPath, outputVideoPath)
+	client, err := storage.NewGCSStorage(ctx)
 	if err != nil {
-		result.Status = models.StatusFailed
-		result.Error = "upload failed: " + err.Error()
-		result.Progress = 0
-		return result
+		return nil, err
 	}
-
-	result.Progress = 100
-	result.Status = models.StatusCompleted
-	result.VideoURL = storageClient.GetPublicURL(outputBucket, outputPath)
-	result.TranslatedText = translatedText
-	now := time.Now()
-	result.ProcessedAt = &now
-
-	slog.Info("Language processing completed", "jobID", jobID, "targetLanguage", targetLanguage)
-	return result
+	client.SetTransferOptions(storage.TransferOptions{
+		Threshold:   int64(cfg.GCSParallelThresholdMB) * 1024 * 1024,
+		PartSize:    int64(cfg.GCSPartSizeMB) * 1024 * 1024,
+		Concurrency: cfg.GCSTransferConcurrency,
+	})
+	client.SetUploadOptions(storage.UploadOptions{
+		CacheControl: cfg.GCSCacheControl,
+		StorageClass: cfg.GCSStorageClass,
+		Metadata:     cfg.GCSObjectMetadata,
+	})
+	err = client.SetAccessOptions(ctx, storage.AccessOptions{
+		BillingProject:       cfg.GCSBillingProject,
+		RequesterPaysBuckets: cfg.GCSRequesterPaysBuckets,
+		BucketCredentials:    cfg.GCSBucketCredentials,
+	})
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
 }
 
-func updateJobError(jobID string, errorMsg string) {
-	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
-		status.Status = models.StatusFailed
-		status.UpdatedAt = time.Now()
-		// Add error to the first language result or create a generic error
-		if len(status.Results) == 0 {
-			status.Results = make(map[string]*models.LanguageResult)
-			status.Results["error"] = &models.LanguageResult{
-				Status: models.StatusFailed,
-				Error:  errorMsg,
+// newVoiceRoutes parses the per-language voice chains of TTS_ROUTES
+func newVoiceRoutes(cfg *config.Config) (map[string][]*tts.CustomVoice, error) {
+	routes := make(map[string][]*tts.CustomVoice, len(cfg.TTSRoutes))
+	for lang, chain := range cfg.TTSRoutes {
+		voices, err := tts.ParseVoiceChain(lang, chain)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TTS_ROUTES entry for %s: %w", lang, err)
+		}
+		for _, voice := range voices {
+			if voice != nil && voice.Provider == tts.VoiceProviderElevenLabs && cfg.ElevenLabsAPIKey == "" && !cfg.DevMockProviders {
+				return nil, fmt.Errorf("invalid TTS_ROUTES entry for %s: voice %s requires ELEVENLABS_API_KEY", lang, voice)
 			}
 		}
+		routes[lang] = voices
+	}
+	return routes, nil
+}
+
+// newTranslators creates the translation providers used by TRANSLATION_PROVIDER, its fallbacks
+// (TRANSLATION_FALLBACK_PROVIDERS) and the per-language routes (TRANSLATION_ROUTES)
+func newTranslators(cfg *config.Config) (*translation.Registry, error) {
+	if cfg.DevMockProviders {
+		registry := translation.NewRegistry([]string{mock.ProviderName}, nil)
+		registry.Register(mock.ProviderName, &mock.Translator{})
+		return registry, nil
+	}
+
+	registry := translation.NewRegistry(cfg.TranslationChain(), cfg.TranslationRoutes)
+	for _, provider := range cfg.TranslationProviders() {
+		var service translation.TranslationService
+		var err error
+		switch provider {
+		case translation.ProviderOpenAI, translation.ProviderAnthropic:
+			service, err = translation.NewLLMTranslator(provider, cfg.LLMAPIKey, cfg.LLMModel)
+		case translation.ProviderDeepL:
+			service, err = translation.NewDeepLTranslator(cfg.DeepLAPIKey)
+		default:
+			service = &translation.DefaultTranslationService{}
+		}
+		if err != nil {
+			return nil, err
+		}
+		registry.Register(provider, service)
+	}
+	if err := registry.Validate(); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// registerSpeechAcronyms adds the SPEECH_ACRONYMS expansions to the normalize text processor
+// Language-specific expansions are registered first so they win over those for every language.
+func registerSpeechAcronyms(acronyms map[string]map[string]string) {
+	languages := make([]string, 0, len(acronyms))
+	for language := range acronyms {
+		languages = append(languages, language)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i] != "" && (languages[j] == "" || languages[i] < languages[j])
 	})
-	slog.Error("Job failed", "jobID", jobID, "error", errorMsg)
+	for _, language := range languages {
+		textproc.RegisterRule(language, textproc.Acronyms(acronyms[language]))
+	}
+}
 
-	// Send webhook notification if configured
-	if cfg.WebhookURL != "" {
-		go func() {
-			status, err := jobStore.GetStatus(jobID)
-			if err == nil && status != nil {
-				// Use background context for webhook since main context may be cancelled
-				webhookCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-				defer cancel()
-				if err := api.NotifyWebhook(webhookCtx, cfg.WebhookURL, status); err != nil {
-					slog.Warn("Webhook notification failed", "error", err, "jobID", jobID)
-					// Don't fail the job if webhook fails
-				}
-			}
-		}()
+// newCache creates the translation and TTS cache selected by CACHE_BACKEND, or nil if disabled
+func newCache(cfg *config.Config) (cache.Store, error) {
+	switch cfg.CacheBackend {
+	case "gcs":
+		return cache.NewGCSStore(storageClient, cfg.CacheBucket, "cache/"), nil
+	case "redis":
+		return cache.NewRedisStore(cfg.RedisURL, "mvp:cache:", cfg.CacheTTL)
+	default:
+		return nil, nil
+	}
+}
+
+// newTranslationMemory creates the persistent translation memory selected by TRANSLATION_MEMORY_BACKEND, or nil if disabled
+// Entries never expire in Redis; GCS entries are stored under memory/ and can be expired with a lifecycle rule.
+func newTranslationMemory(cfg *config.Config) (*cache.TranslationMemory, error) {
+	switch cfg.TranslationMemoryBackend {
+	case "gcs":
+		return cache.NewTranslationMemory(cache.NewGCSStore(storageClient, cfg.TranslationMemoryBucket, "memory/")), nil
+	case "redis":
+		store, err := cache.NewRedisStore(cfg.RedisURL, "mvp:memory:", 0)
+		if err != nil {
+			return nil, err
+		}
+		return cache.NewTranslationMemory(store), nil
+	default:
+		return nil, nil
+	}
+}
+
+// newEmailSender creates the email backend selected by EMAIL_PROVIDER, or nil if disabled
+func newEmailSender(cfg *config.Config) notification.EmailSender {
+	switch cfg.EmailProvider {
+	case "smtp":
+		return &notification.SMTPSender{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+		}
+	case "sendgrid":
+		return notification.NewSendGridSender(cfg.SendGridAPIKey)
+	default:
+		return nil
 	}
 }
 
@@ -607,6 +3613,25 @@ func createTempFile(pattern string) (string, error) {
 	return path, nil
 }
 
+// copyFile copies the contents of source to target, replacing target
+func copyFile(source string, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
 func handleCORS(w http.ResponseWriter) {
 	origins := cfg.CORSOrigins
 	if len(origins) == 0 || (len(origins) == 1 && origins[0] == "*") {
@@ -616,7 +3641,7 @@ func handleCORS(w http.ResponseWriter) {
 		w.Header().Set("Access-Control-Allow-Origin", origins[0])
 	}
 	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 	w.Header().Set("Access-Control-Max-Age", "3600")
 }
 
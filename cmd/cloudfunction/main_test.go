@@ -1,18 +1,29 @@
+//go:build !integration
 // +build !integration
 
 package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/sinouw/multilingual-video-processor/internal/api"
 	"github.com/sinouw/multilingual-video-processor/internal/config"
+	"github.com/sinouw/multilingual-video-processor/internal/gemini"
+	"github.com/sinouw/multilingual-video-processor/internal/storage"
+	"github.com/sinouw/multilingual-video-processor/internal/transcode"
+	"github.com/sinouw/multilingual-video-processor/internal/translation"
+	"github.com/sinouw/multilingual-video-processor/internal/validator"
+	"github.com/sinouw/multilingual-video-processor/internal/video"
 	"github.com/sinouw/multilingual-video-processor/pkg/models"
 )
 
@@ -84,6 +95,17 @@ func ensureTestConfig(t *testing.T) {
 	}
 }
 
+// waitForRunningJobs waits for the background jobs started by earlier requests to stop,
+// so that a test can replace the globals they read
+func waitForRunningJobs(t *testing.T) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); runningJobs.Len() > 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d jobs are still running", runningJobs.Len())
+		}
+	}
+}
+
 func TestTranslateVideo_CORS(t *testing.T) {
 	ensureTestConfig(t)
 
@@ -218,3 +240,415 @@ func TestTranslateVideo_RateLimit(t *testing.T) {
 		t.Log("Rate limiting working as expected")
 	}
 }
+
+func TestTranslateVideo_ReusesCompletedJob(t *testing.T) {
+	ensureTestConfig(t)
+	ctx := context.Background()
+
+	local, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	local.UploadBytes(ctx, "bucket", "reuse.mp4", []byte("video"), "video/mp4")
+	waitForRunningJobs(t)
+	previousStorage, previousIndex, previousWindow := storageClient, resultIndex, cfg.ResultReuseWindow
+	storageClient, resultIndex, cfg.ResultReuseWindow = local, api.NewResultIndex(time.Hour), time.Hour
+	t.Cleanup(func() {
+		storageClient, resultIndex, cfg.ResultReuseWindow = previousStorage, previousIndex, previousWindow
+	})
+
+	request := models.TranslateRequest{VideoURL: "gs://bucket/reuse.mp4", TargetLanguages: []string{"de"}}
+	validated := request
+	if err := validator.ValidateTranslateRequest(&validated, cfg); err != nil {
+		t.Fatalf("invalid test request: %v", err)
+	}
+	jobStore.SetStatus("reuse-original", &models.StatusResponse{
+		JobID:   "reuse-original",
+		Status:  models.StatusCompleted,
+		Results: map[string]*models.LanguageResult{"de": {Status: models.StatusCompleted, VideoURL: "https://example.com/de.mp4"}},
+		Request: &validated,
+	})
+	resultIndex.Record(jobContentKey(ctx, &validated, ""), "reuse-original")
+
+	submit := func(req models.TranslateRequest, remoteAddr string) (*httptest.ResponseRecorder, models.TranslateResponse) {
+		body, _ := json.Marshal(req)
+		r := httptest.NewRequest(http.MethodPost, "/v1/translate", bytes.NewBuffer(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		TranslateVideo(w, r)
+
+		var response models.TranslateResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		return w, response
+	}
+
+	w, response := submit(request, "127.0.0.2:12345")
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected %d for a reused job, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	if response.ReusedFrom != "reuse-original" || response.Status != models.StatusCompleted {
+		t.Errorf("expected a completed job reusing reuse-original, got %+v", response)
+	}
+	if result := response.Results["de"]; result == nil || result.VideoURL != "https://example.com/de.mp4" {
+		t.Errorf("expected the reused results, got %+v", response.Results)
+	}
+
+	// Reprocessing submits a new job instead
+	request.Reprocess = true
+	w, response = submit(request, "127.0.0.3:12345")
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected %d for a reprocessed job, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	if response.ReusedFrom != "" || response.Status != models.StatusProcessing {
+		t.Errorf("expected a processing job, got %+v", response)
+	}
+
+	// The job still reads the globals restored by the cleanup, so it must have stopped first
+	runningJobs.Cancel(response.JobID)
+	waitForRunningJobs(t)
+}
+
+func TestTranslateVideo_ResubmittedJobID(t *testing.T) {
+	ensureTestConfig(t)
+
+	// The source is unreachable, so the existing job must be returned before the source is checked
+	request := models.TranslateRequest{VideoURL: "https://source.invalid/gone.mp4", TargetLanguages: []string{"de"}, JobID: "resubmitted"}
+	validated := request
+	if err := validator.ValidateTranslateRequest(&validated, cfg); err != nil {
+		t.Fatalf("invalid test request: %v", err)
+	}
+	jobStore.SetStatus("resubmitted", &models.StatusResponse{
+		JobID:   "resubmitted",
+		Status:  models.StatusProcessing,
+		Request: &validated,
+	})
+
+	submit := func(req models.TranslateRequest, remoteAddr string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		r := httptest.NewRequest(http.MethodPost, "/v1/translate", bytes.NewBuffer(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		TranslateVideo(w, r)
+		return w
+	}
+
+	w := submit(request, "127.0.0.4:12345")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d for an identical resubmission, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var existing models.StatusResponse
+	if err := json.NewDecoder(w.Body).Decode(&existing); err != nil || existing.JobID != "resubmitted" || existing.Status != models.StatusProcessing {
+		t.Errorf("expected the existing job, got %+v (%v)", existing, err)
+	}
+
+	// The same job ID with a different request is a conflict
+	request.TargetLanguages = []string{"ru"}
+	w = submit(request, "127.0.0.5:12345")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected %d for a different request, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+	var conflict models.ErrorResponse
+	json.NewDecoder(w.Body).Decode(&conflict)
+	if conflict.Code != "job_id_conflict" {
+		t.Errorf("expected job_id_conflict, got %+v", conflict)
+	}
+}
+
+func TestUpdateJobError_ClearsPartialTranscript(t *testing.T) {
+	ensureTestConfig(t)
+
+	status := &models.StatusResponse{JobID: "partial-failed", Status: models.StatusProcessing}
+	status.AddPartialTranscript(models.TranscriptSegment{Start: 0, End: 60, Text: "Hello"})
+	jobStore.SetStatus("partial-failed", status)
+
+	updateJobError("partial-failed", "speech recognition failed")
+
+	failed, err := jobStore.GetStatus("partial-failed")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if failed.Status != models.StatusFailed || failed.PartialTranscript != nil {
+		t.Errorf("expected a failed job without partial transcript, got %s with %v", failed.Status, failed.PartialTranscript)
+	}
+}
+
+// recordingMuxer is a transcode backend remembering the videos it muxed
+type recordingMuxer struct {
+	inputs []transcode.Input
+}
+
+func (m *recordingMuxer) Name() string { return "recording" }
+
+func (m *recordingMuxer) Mux(ctx context.Context, input transcode.Input, outputPath string) error {
+	m.inputs = append(m.inputs, input)
+	return nil
+}
+
+func TestMuxDubbedVideo_OffloadsTallVideos(t *testing.T) {
+	ensureTestConfig(t)
+	previousMuxer, previousHeight := remoteMuxer, cfg.TranscodeMinHeight
+	t.Cleanup(func() { remoteMuxer, cfg.TranscodeMinHeight = previousMuxer, previousHeight })
+	muxer := &recordingMuxer{}
+	remoteMuxer, cfg.TranscodeMinHeight = muxer, 2160
+
+	stream := &models.VideoStream{Width: 3840, Height: 2160}
+	if err := muxDubbedVideo(context.Background(), "mux-4k", "video.mp4", "audio.mp3", "out.mp4", stream, video.SyncOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(muxer.inputs) != 1 || muxer.inputs[0].Video != stream {
+		t.Fatalf("expected the 4K video muxed remotely with its probed stream, got %+v", muxer.inputs)
+	}
+
+	// Smaller and unprobed videos stay local; without ffmpeg the local mux fails
+	for _, small := range []*models.VideoStream{{Width: 1920, Height: 1080}, nil} {
+		muxDubbedVideo(context.Background(), "mux-hd", "missing.mp4", "missing.mp3", filepath.Join(t.TempDir(), "out.mp4"), small, video.SyncOptions{})
+	}
+	if len(muxer.inputs) != 1 {
+		t.Errorf("expected only the 4K video muxed remotely, got %d muxes", len(muxer.inputs))
+	}
+}
+
+func TestAdmitRetry_HoldsAnInFlightSlot(t *testing.T) {
+	ensureTestConfig(t)
+	previousLimiter, previousCap := inFlightLimiter, instanceJobCap
+	t.Cleanup(func() { inFlightLimiter, instanceJobCap = previousLimiter, previousCap })
+	inFlightLimiter, instanceJobCap = api.NewInFlightLimiter(1), 0
+
+	// A retry of a keyed job counts towards its owner, whoever sends it
+	owned := &models.StatusResponse{JobID: "retry-owned", Owner: "tenant-a"}
+	req := httptest.NewRequest(http.MethodPost, "/v1/jobs/retry-owned/retry", nil)
+	release, admissionErr := admitRetry(req, owned)
+	if admissionErr != nil {
+		t.Fatalf("expected the first retry admitted, got %+v", admissionErr)
+	}
+	if _, admissionErr := admitJob(api.RateLimitKeyIdentifier("tenant-a")); admissionErr == nil || admissionErr.Code != "too_many_inflight_jobs" {
+		t.Errorf("expected a submission of the owner refused while the retry runs, got %+v", admissionErr)
+	}
+
+	// Restarts of jobs submitted without a key count towards the retrying client
+	anonymous := &models.StatusResponse{JobID: "retry-anonymous"}
+	other, admissionErr := admitRetry(req, anonymous)
+	if admissionErr != nil {
+		t.Fatalf("expected another client's retry admitted, got %+v", admissionErr)
+	}
+	other()
+
+	release()
+	again, admissionErr := admitRetry(req, owned)
+	if admissionErr != nil {
+		t.Fatalf("expected the slot back once the retry ended, got %+v", admissionErr)
+	}
+	again()
+}
+
+func TestUseGeminiPipeline(t *testing.T) {
+	ensureTestConfig(t)
+	previousClient, previousTranslators, previousConfidence := geminiClient, translators, cfg.STTMinConfidence
+	previousMax, previousMemory := cfg.GeminiMaxDuration, translationMemory
+	t.Cleanup(func() {
+		geminiClient, translators, cfg.STTMinConfidence = previousClient, previousTranslators, previousConfidence
+		cfg.GeminiMaxDuration, translationMemory = previousMax, previousMemory
+	})
+	geminiClient, cfg.GeminiMaxDuration, translationMemory = &gemini.Client{}, 2*time.Minute, nil
+	translators = translation.NewRegistry([]string{"google"}, map[string][]string{"ja": {"deepl"}})
+	cfg.STTMinConfidence = 0
+
+	req := &models.TranslateRequest{TargetLanguages: []string{"de"}}
+	if !useGeminiPipeline(req, 60) {
+		t.Error("expected a short clip to use the Gemini pipeline")
+	}
+	if useGeminiPipeline(req, 300) {
+		t.Error("expected a clip over GEMINI_MAX_DURATION to use the classic pipeline")
+	}
+	if useGeminiPipeline(&models.TranslateRequest{TargetLanguages: []string{"de", "ja"}}, 60) {
+		t.Error("expected a routed target language to use the classic pipeline")
+	}
+
+	// Gemini reports no confidence to check
+	cfg.STTMinConfidence = 0.5
+	if useGeminiPipeline(req, 60) {
+		t.Error("expected STT_MIN_CONFIDENCE to use the classic pipeline")
+	}
+}
+
+func TestAdmitJob_IgnoresSpoofedForwardedFor(t *testing.T) {
+	ensureTestConfig(t)
+	previousLimiter, previousCap := inFlightLimiter, instanceJobCap
+	t.Cleanup(func() { inFlightLimiter, instanceJobCap = previousLimiter, previousCap })
+	inFlightLimiter, instanceJobCap = api.NewInFlightLimiter(1), 0
+	previousFilter := ipFilter
+	t.Cleanup(func() { ipFilter = previousFilter })
+	ipFilter, _ = api.NewIPFilter(nil, nil, 1)
+
+	// The client writes X-Forwarded-For itself and the proxy in front of the service appends its address
+	submit := func(forwardedFor string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/v1/translate", nil)
+		req.Header.Set("X-Forwarded-For", forwardedFor+", 198.51.100.7")
+		return req
+	}
+
+	client, _ := clientIdentifier(submit("203.0.113.1"))
+	release, admissionErr := admitJob(client)
+	if admissionErr != nil {
+		t.Fatalf("expected the first job admitted, got %+v", admissionErr)
+	}
+	defer release()
+
+	// A new X-Forwarded-For on each request is still the same client
+	for _, forwardedFor := range []string{"203.0.113.2", "203.0.113.3"} {
+		client, _ := clientIdentifier(submit(forwardedFor))
+		if _, admissionErr := admitJob(client); admissionErr == nil || admissionErr.Code != "too_many_inflight_jobs" {
+			t.Errorf("expected too_many_inflight_jobs with X-Forwarded-For %s, got %+v", forwardedFor, admissionErr)
+		}
+	}
+}
+
+// delayedStorage takes at least delay for every download and upload, so their timings are measurable
+type delayedStorage struct {
+	storage.Storage
+	delay time.Duration
+}
+
+func (s delayedStorage) Download(ctx context.Context, bucket, path string) (string, error) {
+	time.Sleep(s.delay)
+	return s.Storage.Download(ctx, bucket, path)
+}
+
+func (s delayedStorage) Upload(ctx context.Context, bucket, path string, localPath string) error {
+	time.Sleep(s.delay)
+	return s.Storage.Upload(ctx, bucket, path, localPath)
+}
+
+// setupMockDubbing skips the test unless the mock providers run, and puts a fake ffmpeg on PATH that takes
+// 10ms and writes its output, the last argument
+func setupMockDubbing(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ffmpeg is a shell script")
+	}
+	ensureTestConfig(t)
+	if !cfg.DevMockProviders {
+		t.Skip("requires DEV_MOCK_PROVIDERS=true")
+	}
+
+	bin := t.TempDir()
+	script := "#!/bin/sh\nsleep 0.01\nfor last; do :; done\nprintf fake > \"$last\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "ffmpeg"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake ffmpeg: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestJobTimings(t *testing.T) {
+	setupMockDubbing(t)
+	ctx := context.Background()
+
+	local, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	local.UploadBytes(ctx, "bucket", "timed.mp4", []byte("video"), "video/mp4")
+	previousStorage := storageClient
+	storageClient = delayedStorage{Storage: local, delay: 5 * time.Millisecond}
+	t.Cleanup(func() { storageClient = previousStorage })
+
+	// The download and transcription record the job-wide timings
+	req := &models.TranslateRequest{VideoURL: "gs://bucket/timed.mp4", TargetLanguages: []string{"de"}}
+	run := newTestRun(t, "timed", req)
+	if err := run.download(ctx); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	defer removeTempFile("timed", run.videoPath)
+	if err := run.loadSourceText(ctx); err != nil {
+		t.Fatalf("transcription failed: %v", err)
+	}
+	status, _ := jobStore.GetStatus("timed")
+	if timings := status.Timings; timings == nil || timings.DownloadMs < 5 || timings.STTMs < 10 {
+		t.Errorf("expected the download and transcription timed, got %+v", timings)
+	}
+
+	// Dubbing a language records its stage timings, the upload once the outputs are uploaded
+	checkpoint := &models.JobCheckpoint{Transcript: run.originalText, SourceLanguage: "en", VideoDuration: 10, VideoPath: run.videoPath}
+	result := processLanguage(ctx, "timed", req, translation.NewMemory(), checkpoint, "de", storage.Destination{Bucket: "out"})
+	if result.Status != models.StatusCompleted {
+		t.Fatalf("expected the language completed, got %s: %s", result.Status, result.Error)
+	}
+	if timings := result.Timings; timings == nil || timings.MuxMs < 10 || timings.UploadMs < 5 {
+		t.Errorf("expected the language's stages timed, got %+v", timings)
+	}
+}
+
+func TestProcessLanguage_UploadsTextArtifacts(t *testing.T) {
+	setupMockDubbing(t)
+	ctx := context.Background()
+
+	local, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	previousStorage := storageClient
+	storageClient = local
+	t.Cleanup(func() { storageClient = previousStorage })
+
+	videoPath := filepath.Join(t.TempDir(), "video.mp4")
+	os.WriteFile(videoPath, []byte("video"), 0644)
+	req := &models.TranslateRequest{VideoURL: "gs://bucket/video.mp4", TargetLanguages: []string{"de"}}
+	run := newTestRun(t, "text-artifacts", req)
+	run.originalText, run.sourceLanguage, run.videoDuration, run.videoPath = "Hello there. See you soon.", "en", 10, videoPath
+	if err := run.saveCheckpoint(ctx); err != nil {
+		t.Fatalf("failed to save checkpoint: %v", err)
+	}
+
+	dest := storage.Destination{Bucket: "out"}
+	result := processLanguage(ctx, "text-artifacts", req, translation.NewMemory(), run.checkpoint, "de", dest)
+	if result.Status != models.StatusCompleted {
+		t.Fatalf("expected the language completed, got %s: %s", result.Status, result.Error)
+	}
+
+	// Every text output is stored and linked from the result
+	transcriptPath := jobDestination(req).Path("translations/text-artifacts/transcript.txt")
+	outputs := []struct {
+		name   string
+		url    string
+		bucket string
+		path   string
+		want   string
+	}{
+		{"transcript", result.TranscriptURL, jobDestination(req).Bucket, transcriptPath, run.originalText},
+		{"translated text", result.TranslatedTextURL, "out", "translations/text-artifacts/de/translation.txt", result.TranslatedText},
+		{"subtitles", result.SubtitlesURL, "out", "translations/text-artifacts/de/captions.vtt", "WEBVTT"},
+	}
+	for _, output := range outputs {
+		if want := local.GetPublicURL(output.bucket, output.path); output.url != want {
+			t.Errorf("expected %s URL %s, got %q", output.name, want, output.url)
+		}
+		data, err := local.ReadBytes(ctx, output.bucket, output.path)
+		if err != nil || !strings.Contains(string(data), output.want) {
+			t.Errorf("expected the %s uploaded with %q, got %q: %v", output.name, output.want, data, err)
+		}
+	}
+}
+
+func TestExport_RateLimited(t *testing.T) {
+	ensureTestConfig(t)
+	previous := rateLimiter
+	t.Cleanup(func() { rateLimiter = previous })
+	rateLimiter = api.NewRateLimiter(1)
+
+	export := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/v1/jobs/missing-export/export?artifacts=true", nil)
+		r.RemoteAddr = "127.0.0.6:12345"
+		w := httptest.NewRecorder()
+		TranslateVideo(w, r)
+		return w
+	}
+
+	if w := export(); w.Code != http.StatusNotFound {
+		t.Fatalf("expected %d for the first export, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+	if w := export(); w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected %d once the submission limit is used up, got %d: %s", http.StatusTooManyRequests, w.Code, w.Body.String())
+	}
+}
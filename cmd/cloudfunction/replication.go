@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/sinouw/multilingual-video-processor/internal/storage"
+	"github.com/sinouw/multilingual-video-processor/internal/utils"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// replicateOutputs copies a completed language's outputs to every REPLICA_DESTINATIONS destination
+// The copies run in the background after the primary upload; each destination's progress is reported
+// in the language result's replicas. Replicas keep the tenant namespace of the outputs (TENANT_NAMESPACES).
+func replicateOutputs(jobID string, language string, result *models.LanguageResult, source storage.Destination, tenant string) {
+	if len(cfg.ReplicaDestinations) == 0 || result.Status != models.StatusCompleted {
+		return
+	}
+
+	replicas := make(map[string]storage.Destination, len(cfg.ReplicaDestinations))
+	for _, configured := range cfg.ReplicaDestinations {
+		replica, err := storage.ParseDestination(configured)
+		if err != nil {
+			continue
+		}
+		if replica = tenantNamespace(replica, tenant); replica == source {
+			continue
+		}
+		replicas[replica.String()] = replica
+	}
+	if len(replicas) == 0 {
+		return
+	}
+
+	jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+		result.Replicas = make(map[string]*models.ReplicaResult, len(replicas))
+		for name := range replicas {
+			result.Replicas[name] = &models.ReplicaResult{Status: models.StatusProcessing}
+		}
+	})
+
+	objects := result.OutputObjects
+	runBackground(func() {
+		// Use background context since the processing context may be cancelled
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.RequestTimeout)
+		defer cancel()
+
+		var wg sync.WaitGroup
+		for name, replica := range replicas {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				replicaResult := &models.ReplicaResult{Status: models.StatusCompleted}
+				if err := copyObjects(ctx, objects, source, replica); err != nil {
+					slog.Warn("Output replication failed", "error", err, "jobID", jobID, "language", language, "destination", name)
+					replicaResult.Status = models.StatusFailed
+					replicaResult.Error = err.Error()
+				} else if slices.Contains(objects, languageVideoPath(jobID, language)) {
+					replicaResult.VideoURL = storageClient.GetPublicURL(replica.Bucket, replica.Path(languageVideoPath(jobID, language)))
+				}
+				jobStore.UpdateStatusSafely(jobID, func(status *models.StatusResponse) {
+					result.Replicas[name] = replicaResult
+				})
+			}()
+		}
+		wg.Wait()
+		slog.Info("Output replication finished", "jobID", jobID, "language", language, "destinations", len(replicas))
+	})
+}
+
+// copyObjects copies outputs from one destination to another, keeping their paths relative to the destination
+func copyObjects(ctx context.Context, objects []string, source storage.Destination, replica storage.Destination) error {
+	for _, object := range objects {
+		err := utils.Retry(ctx, func(ctx context.Context) error {
+			return storageClient.Copy(ctx, source.Bucket, source.Path(object), replica.Bucket, replica.Path(object))
+		}, utils.DefaultRetryConfig())
+		if err != nil {
+			return fmt.Errorf("failed to replicate %s: %w", object, err)
+		}
+	}
+	return nil
+}
+
+// languageVideoPath returns the dubbed video's path relative to the language's destination
+func languageVideoPath(jobID string, language string) string {
+	return fmt.Sprintf("translations/%s/%s.mp4", jobID, language)
+}
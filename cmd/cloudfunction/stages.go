@@ -0,0 +1,480 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/moderation"
+	"github.com/sinouw/multilingual-video-processor/internal/notification"
+	"github.com/sinouw/multilingual-video-processor/internal/pipeline"
+	"github.com/sinouw/multilingual-video-processor/internal/storage"
+	"github.com/sinouw/multilingual-video-processor/internal/stt"
+	"github.com/sinouw/multilingual-video-processor/internal/subtitles"
+	"github.com/sinouw/multilingual-video-processor/internal/transcript"
+	"github.com/sinouw/multilingual-video-processor/internal/validator"
+	"github.com/sinouw/multilingual-video-processor/internal/video"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// jobRun is the state of one run of a job's pipeline, shared by its stages
+type jobRun struct {
+	jobID string
+	req   *models.TranslateRequest
+
+	reserved         bool  // Temp disk space was reserved for the job
+	reservedBytes    int64 // Bytes of the reservation, zero when it was skipped
+	videoPath        string
+	videoDuration    float64
+	chaptered        bool
+	rotation         int
+	videoStream      *models.VideoStream
+	metadata         *video.Metadata
+	audioTracks      []models.AudioTrack
+	originalText     string
+	detectedLanguage string
+	sourceLanguage   string
+	cues             []subtitles.Cue
+	pretranslated    map[string]string // Gemini pipeline translations keyed by target language
+	chapters         []models.Chapter
+	targetLanguages  []string
+	backgroundPath   string
+	branding         *models.BrandingAssets
+
+	// checkpoint is set by the last stage; the languages are dubbed and retried from it
+	checkpoint *models.JobCheckpoint
+}
+
+// pipeline returns the stages taking the job's source video to the checkpoint its languages are dubbed from
+// Every stage either records its outcome on the run or returns an error; stages that fail the job themselves
+// (with an error code, or inside shared helpers) return pipeline.ErrHalted.
+func (r *jobRun) pipeline() *pipeline.Pipeline {
+	return pipeline.New(
+		pipeline.Func("source check", r.checkSource),
+		pipeline.Func("disk reservation", r.reserveDisk),
+		pipeline.Func("download", r.download),
+		pipeline.Func("duration check", r.checkDuration),
+		pipeline.Parallel("probe",
+			pipeline.Func("rotation probe", r.probeRotation),
+			pipeline.Func("metadata probe", r.probeMetadata),
+			pipeline.Func("audio track probe", r.probeAudioTracks),
+			pipeline.Func("video stream probe", r.probeVideoStream),
+		),
+		pipeline.Func("start", r.start),
+		pipeline.Func("transcription", r.loadSourceText),
+		pipeline.When(func() bool { return r.req.ProfanityFilter }, pipeline.Func("moderation", r.moderate)),
+		pipeline.Func("transcript limit", r.limitSourceText),
+		pipeline.Func("target selection", r.selectTargetLanguages),
+		pipeline.Parallel("preparation",
+			pipeline.When(r.keepsBackground, pipeline.Func("background separation", r.separateBackground)),
+			pipeline.Func("branding download", r.downloadBranding),
+		),
+		pipeline.Func("checkpoint", r.saveCheckpoint),
+	)
+}
+
+// failJobRun fails a job whose pipeline stopped, unless the stage that stopped it already did
+func failJobRun(ctx context.Context, jobID string, err error) {
+	stage, cause := "processing", err
+	var stageErr *pipeline.Error
+	if errors.As(err, &stageErr) {
+		stage, cause = stageErr.Stage, stageErr.Err
+	}
+
+	switch {
+	case errors.Is(err, pipeline.ErrHalted):
+	case ctx.Err() != nil:
+		updateJobError(jobID, fmt.Sprintf("processing cancelled during %s: %v", stage, ctx.Err()))
+	default:
+		updateJobErrorCause(jobID, cause, cause.Error())
+	}
+}
+
+// release removes the run's temp files unless they back the retry checkpoint, and frees its disk reservation
+func (r *jobRun) release() {
+	if r.checkpoint == nil {
+		removeTempFile(r.jobID, r.videoPath)
+		removeTempFile(r.jobID, r.backgroundPath)
+		for _, chapter := range r.chapters {
+			removeTempFile(r.jobID, chapter.VideoPath)
+			removeTempFile(r.jobID, chapter.BackgroundPath)
+		}
+		for _, path := range r.branding.Files() {
+			removeTempFile(r.jobID, path)
+		}
+	}
+	if r.reserved && diskTracker != nil {
+		diskTracker.Release(r.jobID)
+	}
+}
+
+// checkSource fails fast on HTTPS sources that are unreachable or not videos
+func (r *jobRun) checkSource(ctx context.Context) error {
+	if !checkHTTPSource(ctx, r.jobID, r.req) {
+		return pipeline.ErrHalted
+	}
+	return nil
+}
+
+// reserveDisk reserves temp disk space for the download and intermediate files
+func (r *jobRun) reserveDisk(ctx context.Context) error {
+	reservedBytes, ok := reserveDiskSpace(ctx, r.jobID, r.req)
+	if !ok {
+		return pipeline.ErrHalted
+	}
+	r.reserved, r.reservedBytes = true, reservedBytes
+	return nil
+}
+
+// download downloads the source video, keeping only the requested clip
+func (r *jobRun) download(ctx context.Context) error {
+	setJobStage(r.jobID, models.StageDownloading)
+	downloadStart := time.Now()
+	videoPath, err := downloadSourceVideo(ctx, r.jobID, r.req)
+	recordJobTiming(r.jobID, func(timings *models.JobTimings) {
+		timings.DownloadMs = models.ElapsedMs(downloadStart)
+	})
+	if err != nil {
+		return err
+	}
+	r.videoPath = videoPath
+	return nil
+}
+
+// checkDuration measures the video; longer videos than MAX_VIDEO_DURATION may be split into chapters
+// transcribed and dubbed in parallel
+func (r *jobRun) checkDuration(ctx context.Context) error {
+	err := ffmpegPool.Do(ctx, func() (err error) {
+		r.videoDuration, err = video.GetVideoDuration(ctx, r.videoPath)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get video duration: %w", err)
+	}
+
+	if r.videoDuration > cfg.MaxVideoDuration.Seconds() {
+		if !useChapters(r.req, r.videoDuration) {
+			limit := cfg.MaxVideoDuration
+			if useChapters(r.req, 0) {
+				limit = cfg.MaxChapteredDuration
+			}
+			return fmt.Errorf("video duration exceeds maximum: %.2fs > %.2fs", r.videoDuration, limit.Seconds())
+		}
+		r.chaptered = true
+		slog.Info("Processing long video in chapters", "jobID", r.jobID, "duration", r.videoDuration, "chapterDuration", cfg.ChapterDuration.Seconds())
+		if !reserveChapterDiskSpace(ctx, r.jobID, r.reservedBytes, r.videoPath) {
+			return pipeline.ErrHalted
+		}
+	}
+	return nil
+}
+
+// probeRotation reads the display rotation, so rotated (e.g. portrait phone) video keeps its orientation through the mux
+func (r *jobRun) probeRotation(ctx context.Context) error {
+	err := ffmpegPool.Do(ctx, func() (err error) {
+		r.rotation, err = video.ProbeRotation(ctx, r.videoPath)
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		slog.Warn("Failed to probe video rotation, assuming upright video", "error", err, "jobID", r.jobID)
+	} else if r.rotation != 0 {
+		slog.Info("Source video is rotated", "jobID", r.jobID, "rotation", r.rotation, "policy", cfg.VideoRotation)
+	}
+	return nil
+}
+
+// probeMetadata reads the container metadata (title, chapters, tags) copied to the outputs and titling them per language
+func (r *jobRun) probeMetadata(ctx context.Context) error {
+	err := ffmpegPool.Do(ctx, func() (err error) {
+		r.metadata, err = video.ProbeMetadata(ctx, r.videoPath)
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		slog.Warn("Failed to probe video metadata, titling outputs after the file name", "error", err, "jobID", r.jobID)
+	}
+	return nil
+}
+
+// probeVideoStream reads the size and frame rate of the video once, so every language's mux can tell whether
+// the transcode backend takes it
+func (r *jobRun) probeVideoStream(ctx context.Context) error {
+	if remoteMuxer == nil {
+		return nil
+	}
+	var media *models.MediaInfo
+	err := ffmpegPool.Do(ctx, func() (err error) {
+		media, err = video.ProbeMedia(ctx, r.videoPath)
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		slog.Warn("Failed to probe video for transcode offload, muxing locally", "error", err, "jobID", r.jobID)
+		return nil
+	}
+	r.videoStream = media.Video
+	return nil
+}
+
+// probeAudioTracks lists the audio streams so the requested source track can be checked and reported
+func (r *jobRun) probeAudioTracks(ctx context.Context) error {
+	err := ffmpegPool.Do(ctx, func() (err error) {
+		r.audioTracks, err = video.ProbeAudioTracks(ctx, r.videoPath)
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil || r.req.SourceAudioTrack != nil {
+			return fmt.Errorf("failed to probe audio tracks: %w", err)
+		}
+		slog.Warn("Failed to probe audio tracks, using default track", "error", err, "jobID", r.jobID)
+		return nil
+	}
+
+	jobStore.UpdateStatusSafely(r.jobID, func(status *models.StatusResponse) {
+		status.AudioTracks = r.audioTracks
+	})
+	if len(r.audioTracks) == 0 && !r.req.Narration {
+		updateJobErrorCode(r.jobID, models.ErrCodeNoAudio, "video has no audio tracks; set narration with sourceText or subtitleUrl to dub a silent video")
+		return pipeline.ErrHalted
+	}
+	if r.req.SourceAudioTrack != nil && *r.req.SourceAudioTrack >= len(r.audioTracks) {
+		return fmt.Errorf("sourceAudioTrack %d not found: video has %d audio tracks", *r.req.SourceAudioTrack, len(r.audioTracks))
+	}
+	return nil
+}
+
+// start records that processing began with a usable source; job.started reports what was probed
+func (r *jobRun) start(ctx context.Context) error {
+	source := &models.SourceInfo{Duration: r.videoDuration, Rotation: r.rotation, Chaptered: r.chaptered}
+	if info, err := os.Stat(r.videoPath); err == nil {
+		source.SizeBytes = info.Size()
+	}
+	var startedEvents []notification.Payload
+	jobStore.UpdateStatusSafely(r.jobID, func(status *models.StatusResponse) {
+		status.Source = source
+		status.RecordEvent(models.EventJobStarted, "", "")
+		startedEvents = jobStartedEvents(status)
+	})
+	notifyWebhookEvents(r.req, startedEvents)
+	return nil
+}
+
+// loadSourceText reads the supplied transcript or subtitles, which replace audio extraction and transcription,
+// or else transcribes the video or its chapters
+func (r *jobRun) loadSourceText(ctx context.Context) error {
+	switch {
+	case r.req.SourceText != "":
+		r.originalText = strings.TrimSpace(r.req.SourceText)
+		slog.Info("Using supplied source text", "jobID", r.jobID)
+	case r.req.SubtitleURL != "":
+		setJobStage(r.jobID, models.StageLoadingSubtitles)
+		cues, err := loadSourceSubtitles(ctx, r.req)
+		if err != nil {
+			return fmt.Errorf("failed to load subtitles: %w", err)
+		}
+		r.cues = cues
+		r.originalText = subtitles.Text(cues)
+		slog.Info("Source subtitles loaded", "jobID", r.jobID, "cues", len(cues))
+	case r.chaptered:
+		chapters, err := splitIntoChapters(ctx, r.jobID, r.videoPath)
+		if err != nil {
+			return fmt.Errorf("failed to split video into chapters: %w", err)
+		}
+		r.chapters = chapters
+		sttStart := time.Now()
+		language, ok := transcribeChapters(ctx, r.jobID, r.req, r.chapters)
+		recordJobTiming(r.jobID, func(timings *models.JobTimings) {
+			timings.STTMs = models.ElapsedMs(sttStart)
+		})
+		if !ok {
+			return pipeline.ErrHalted
+		}
+		r.detectedLanguage = language
+		r.originalText = chaptersTranscript(r.chapters)
+	default:
+		sttStart := time.Now()
+		var transcription *stt.SpeechToTextResponse
+		var ok bool
+		transcription, r.pretranslated, ok = transcribeSourceAudio(ctx, r.jobID, r.req, r.videoPath, r.videoDuration)
+		recordJobTiming(r.jobID, func(timings *models.JobTimings) {
+			timings.STTMs = models.ElapsedMs(sttStart)
+		})
+		if !ok {
+			return pipeline.ErrHalted
+		}
+		recordPartialTranscript(r.jobID, r.req, 0, r.videoDuration, transcription.Text)
+		r.originalText = transcription.Text
+		r.detectedLanguage = transcription.Language
+		if r.pretranslated == nil {
+			r.originalText = cleanupTranscript(ctx, r.jobID, r.originalText, r.detectedLanguage)
+		}
+	}
+
+	r.sourceLanguage = r.detectedLanguage
+	if r.sourceLanguage == "" {
+		r.sourceLanguage = r.req.SourceLanguage
+		if r.sourceLanguage == "" {
+			r.sourceLanguage = "auto"
+		}
+	}
+	return nil
+}
+
+// moderate masks profanity before the transcript reaches translation and TTS
+func (r *jobRun) moderate(ctx context.Context) error {
+	filter := moderation.NewProfanityFilter(cfg.ProfanityWords)
+	var redacted []string
+	if r.cues != nil {
+		for i := range r.cues {
+			var cueRedacted []string
+			r.cues[i].Text, cueRedacted = filter.Mask(r.cues[i].Text)
+			redacted = append(redacted, cueRedacted...)
+		}
+		r.originalText = subtitles.Text(r.cues)
+	} else if r.chapters != nil {
+		for i := range r.chapters {
+			var chapterRedacted []string
+			r.chapters[i].Transcript, chapterRedacted = filter.Mask(r.chapters[i].Transcript)
+			redacted = append(redacted, chapterRedacted...)
+		}
+		r.originalText = chaptersTranscript(r.chapters)
+	} else {
+		r.originalText, redacted = filter.Mask(r.originalText)
+	}
+	if len(redacted) > 0 {
+		slog.Info("Profanity filtered from transcript", "jobID", r.jobID, "redactedTerms", len(redacted))
+		jobStore.UpdateStatusSafely(r.jobID, func(status *models.StatusResponse) {
+			status.RedactedTerms = redacted
+		})
+	}
+	return nil
+}
+
+// limitSourceText bounds the source text before it drives translation and TTS calls
+func (r *jobRun) limitSourceText(ctx context.Context) error {
+	limitedText, cues, ok := limitTranscript(ctx, r.jobID, r.originalText, r.cues, r.sourceLanguage)
+	if !ok {
+		return pipeline.ErrHalted
+	}
+	if limitedText != r.originalText && r.chapters != nil {
+		// Chapters are translated and dubbed from their own transcripts, which a shortened text no longer matches
+		updateJobErrorCode(r.jobID, models.ErrCodeTranscriptTooLong,
+			fmt.Sprintf("transcript of the chaptered video has %d characters, more than the maximum of %d", transcript.Length(r.originalText), cfg.MaxTranscriptChars))
+		return pipeline.ErrHalted
+	}
+	if limitedText != r.originalText && r.pretranslated != nil {
+		// Translations of the full transcript no longer match the shortened text
+		slog.Info("Discarding Gemini translations of the shortened transcript", "jobID", r.jobID)
+		r.pretranslated = nil
+	}
+	r.originalText, r.cues = limitedText, cues
+
+	slog.Info("Source text ready", "jobID", r.jobID, "textLength", len(r.originalText), "language", r.sourceLanguage)
+	return nil
+}
+
+// selectTargetLanguages drops the detected source language from an all-languages request when
+// SAME_LANGUAGE_POLICY skips it; passed through, it stays a target like a listed language matching the source
+func (r *jobRun) selectTargetLanguages(ctx context.Context) error {
+	r.targetLanguages = r.req.TargetLanguages
+	if !r.req.AllLanguages || cfg.SameLanguagePolicy != "skip" {
+		return nil
+	}
+
+	var skipped []string
+	r.targetLanguages, skipped = validator.ExcludeSourceLanguage(r.req.TargetLanguages, r.detectedLanguage)
+	if len(r.targetLanguages) == 0 {
+		return fmt.Errorf("no target languages left: the detected source language %s is the only supported language", r.detectedLanguage)
+	}
+	if len(skipped) > 0 {
+		slog.Info("Skipping target languages matching the source language", "jobID", r.jobID, "skipped", skipped)
+		jobStore.UpdateStatusSafely(r.jobID, func(status *models.StatusResponse) {
+			status.SkippedLanguages = skipped
+			if status.Results == nil {
+				status.Results = make(map[string]*models.LanguageResult)
+			}
+			for _, lang := range skipped {
+				status.Results[lang] = &models.LanguageResult{Status: models.StatusSkipped, Direction: models.LanguageDirection(lang)}
+			}
+		})
+	}
+	return nil
+}
+
+// keepsBackground reports whether the music bed is separated to be mixed under the dubbed voice
+func (r *jobRun) keepsBackground() bool {
+	return keepBackgroundMusic(r.req) && (len(r.audioTracks) > 0 || !r.req.Narration)
+}
+
+// separateBackground separates the music bed, cut into the chapter ranges for chaptered videos
+// Separation failures only add a warning: the original audio is then fully replaced.
+func (r *jobRun) separateBackground(ctx context.Context) error {
+	r.backgroundPath = separateBackground(ctx, r.jobID, r.req, r.videoPath)
+	if r.backgroundPath != "" && r.chapters != nil {
+		clipChapterBackgrounds(ctx, r.jobID, r.backgroundPath, r.chapters)
+	}
+	return nil
+}
+
+// downloadBranding downloads the watermark and bumper clips once for all languages
+func (r *jobRun) downloadBranding(ctx context.Context) error {
+	branding, err := downloadBranding(ctx, r.req)
+	if err != nil {
+		return fmt.Errorf("failed to download branding assets: %w", err)
+	}
+	r.branding = branding
+	return nil
+}
+
+// saveCheckpoint publishes the source transcript and keeps a checkpoint, so failed languages can be retried
+// without downloading or transcribing again
+func (r *jobRun) saveCheckpoint(ctx context.Context) error {
+	checkpoint := &models.JobCheckpoint{
+		Transcript:     r.originalText,
+		SourceLanguage: r.sourceLanguage,
+		VideoDuration:  r.videoDuration,
+		VideoPath:      r.videoPath,
+		Cues:           r.cues,
+		BackgroundPath: r.backgroundPath,
+		Rotation:       r.rotation,
+		Video:          r.videoStream,
+		Title:          video.SourceTitle(r.metadata, r.req.VideoURL),
+		Branding:       r.branding,
+		Translations:   r.pretranslated,
+		Chapters:       r.chapters,
+	}
+
+	// Publish the source transcript as a standalone artifact
+	transcriptDest := jobDestination(r.req)
+	transcriptPath := transcriptDest.Path(fmt.Sprintf("translations/%s/transcript.txt", r.jobID))
+	transcriptText := r.originalText
+	if models.IsRTLLanguage(r.sourceLanguage) {
+		transcriptText = subtitles.MarkRTL(transcriptText)
+	}
+	if err := storageClient.UploadBytes(storage.AsJobOutput(ctx), transcriptDest.Bucket, transcriptPath, []byte(transcriptText), "text/plain; charset=utf-8"); err != nil {
+		return fmt.Errorf("failed to upload transcript: %w", err)
+	}
+	checkpoint.TranscriptURL = storageClient.GetPublicURL(transcriptDest.Bucket, transcriptPath)
+
+	jobStore.UpdateStatusSafely(r.jobID, func(status *models.StatusResponse) {
+		checkpoint.SpeakerGender = status.SpeakerGender
+		if r.req.VoiceGender == models.VoiceGenderMatch && checkpoint.SpeakerGender == "" {
+			status.Warnings = append(status.Warnings, "the original speaker's gender could not be estimated; the default voices are used")
+		}
+		status.Checkpoint = checkpoint
+		status.PartialTranscript = nil
+	})
+	r.checkpoint = checkpoint
+	return nil
+}
@@ -0,0 +1,132 @@
+//go:build !integration
+// +build !integration
+
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/internal/pipeline"
+	"github.com/sinouw/multilingual-video-processor/internal/subtitles"
+	"github.com/sinouw/multilingual-video-processor/internal/transcript"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// newTestRun stores a processing job and returns a run of its pipeline
+func newTestRun(t *testing.T, jobID string, req *models.TranslateRequest) *jobRun {
+	t.Helper()
+	ensureTestConfig(t)
+	jobStore.SetStatus(jobID, &models.StatusResponse{JobID: jobID, Status: models.StatusProcessing, Request: req})
+	return &jobRun{jobID: jobID, req: req}
+}
+
+func TestJobRun_Moderate(t *testing.T) {
+	run := newTestRun(t, "stage-moderate", &models.TranslateRequest{ProfanityFilter: true})
+	previous := cfg.ProfanityWords
+	t.Cleanup(func() { cfg.ProfanityWords = previous })
+	cfg.ProfanityWords = []string{"darn"}
+
+	run.cues = []subtitles.Cue{{Start: 0, End: 2, Text: "Darn it."}, {Start: 2, End: 4, Text: "All good."}}
+	if err := run.moderate(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(strings.ToLower(run.originalText), "darn") || run.originalText != subtitles.Text(run.cues) {
+		t.Errorf("expected the masked cues to make up the text, got %q", run.originalText)
+	}
+	status, _ := jobStore.GetStatus("stage-moderate")
+	if len(status.RedactedTerms) != 1 {
+		t.Errorf("expected one redacted term, got %v", status.RedactedTerms)
+	}
+}
+
+func TestJobRun_LimitSourceText(t *testing.T) {
+	run := newTestRun(t, "stage-limit", &models.TranslateRequest{})
+	previousMax, previousPolicy := cfg.MaxTranscriptChars, cfg.TranscriptLimitPolicy
+	t.Cleanup(func() { cfg.MaxTranscriptChars, cfg.TranscriptLimitPolicy = previousMax, previousPolicy })
+	cfg.MaxTranscriptChars, cfg.TranscriptLimitPolicy = 12, transcript.PolicyTruncate
+
+	run.originalText = "Hello there. This sentence is cut."
+	run.pretranslated = map[string]string{"de": "Hallo. Dieser Satz wird gekürzt."}
+	if err := run.limitSourceText(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.originalText != "Hello there." || run.pretranslated != nil {
+		t.Errorf("expected the truncated text without Gemini translations, got %q %v", run.originalText, run.pretranslated)
+	}
+
+	// Chapters cannot be shortened, so the job fails
+	chaptered := newTestRun(t, "stage-limit-chapters", &models.TranslateRequest{})
+	chaptered.originalText = "Hello there. This sentence is cut."
+	chaptered.chapters = []models.Chapter{{Start: 0, End: 60, Transcript: chaptered.originalText}}
+	if err := chaptered.limitSourceText(context.Background()); !errors.Is(err, pipeline.ErrHalted) {
+		t.Fatalf("expected the stage to halt, got %v", err)
+	}
+	if status, _ := jobStore.GetStatus("stage-limit-chapters"); status.ErrorCode != models.ErrCodeTranscriptTooLong {
+		t.Errorf("expected %s, got %q", models.ErrCodeTranscriptTooLong, status.ErrorCode)
+	}
+}
+
+func TestJobRun_SelectTargetLanguages(t *testing.T) {
+	run := newTestRun(t, "stage-targets", &models.TranslateRequest{TargetLanguages: []string{"en", "de"}, AllLanguages: true})
+	previous := cfg.SameLanguagePolicy
+	t.Cleanup(func() { cfg.SameLanguagePolicy = previous })
+	cfg.SameLanguagePolicy = "skip"
+	run.detectedLanguage = "en"
+	if err := run.selectTargetLanguages(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(run.targetLanguages) != 1 || run.targetLanguages[0] != "de" {
+		t.Errorf("expected only de, got %v", run.targetLanguages)
+	}
+	status, _ := jobStore.GetStatus("stage-targets")
+	if result := status.Results["en"]; result == nil || result.Status != models.StatusSkipped {
+		t.Errorf("expected en to be skipped, got %+v", result)
+	}
+
+	// Only the source language left is an error when it is skipped
+	only := newTestRun(t, "stage-targets-only", &models.TranslateRequest{TargetLanguages: []string{"en"}, AllLanguages: true})
+	only.detectedLanguage = "en"
+	if err := only.selectTargetLanguages(context.Background()); err == nil {
+		t.Error("expected an error without target languages left")
+	}
+
+	// Passed through, the source language still counts as a target
+	cfg.SameLanguagePolicy = "passthrough"
+	passthrough := newTestRun(t, "stage-targets-passthrough", &models.TranslateRequest{TargetLanguages: []string{"en"}, AllLanguages: true})
+	passthrough.detectedLanguage = "en"
+	if err := passthrough.selectTargetLanguages(context.Background()); err != nil || len(passthrough.targetLanguages) != 1 {
+		t.Errorf("expected the source language kept for passthrough, got %v: %v", passthrough.targetLanguages, err)
+	}
+
+	// Listed languages are kept as requested
+	listed := newTestRun(t, "stage-targets-listed", &models.TranslateRequest{TargetLanguages: []string{"en", "de"}})
+	listed.detectedLanguage = "en"
+	if err := listed.selectTargetLanguages(context.Background()); err != nil || len(listed.targetLanguages) != 2 {
+		t.Errorf("expected both listed languages, got %v: %v", listed.targetLanguages, err)
+	}
+}
+
+func TestFailJobRun(t *testing.T) {
+	ensureTestConfig(t)
+
+	// A halted stage already recorded the failure
+	jobStore.SetStatus("stage-halted", &models.StatusResponse{JobID: "stage-halted", Status: models.StatusProcessing})
+	failJobRun(context.Background(), "stage-halted", &pipeline.Error{Stage: "download", Err: pipeline.ErrHalted})
+	if status, _ := jobStore.GetStatus("stage-halted"); status.Status != models.StatusProcessing {
+		t.Errorf("expected a halted run to leave the job alone, got %s", status.Status)
+	}
+
+	// A cancelled job reports the stage it stopped at
+	jobStore.SetStatus("stage-cancelled", &models.StatusResponse{JobID: "stage-cancelled", Status: models.StatusProcessing})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	failJobRun(ctx, "stage-cancelled", &pipeline.Error{Stage: "transcription", Err: context.Canceled})
+	status, _ := jobStore.GetStatus("stage-cancelled")
+	if status.Status != models.StatusFailed || !strings.Contains(status.Results["error"].Error, "during transcription") {
+		t.Errorf("expected the job to fail during transcription, got %s: %+v", status.Status, status.Results["error"])
+	}
+}
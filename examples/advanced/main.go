@@ -1,104 +1,69 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/client"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
 )
 
-// Example: Advanced usage with error handling and webhooks
+// Example: Advanced usage with error handling, backoff and per-language callbacks
 
 func main() {
-	apiURL := "https://your-function-url/v1/translate"
-
-	req := map[string]interface{}{
-		"videoUrl":        "gs://your-bucket/video.mp4",
-		"targetLanguages": []string{"en", "ar", "de", "ru"},
-		"sourceLanguage":  "",
-	}
-
-	jsonData, _ := json.Marshal(req)
+	api := client.New("https://your-function-url", "your-api-key")
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	req := &models.TranslateRequest{
+		VideoURL:        "gs://your-bucket/video.mp4",
+		TargetLanguages: []string{"en", "ar", "de", "ru"},
 	}
 
-	// Send request with retry logic
-	var resp *http.Response
+	// Send request with retry logic for temporary failures (429, 5xx)
+	ctx := context.Background()
+	var resp *models.TranslateResponse
 	var err error
-	maxRetries := 3
-
-	for i := 0; i < maxRetries; i++ {
-		resp, err = client.Post(apiURL, "application/json", bytes.NewBuffer(jsonData))
-		if err == nil && resp.StatusCode < 500 {
+	for attempt := 1; attempt <= 3; attempt++ {
+		resp, err = api.Translate(ctx, req)
+		var apiErr *client.APIError
+		if err == nil || (errors.As(err, &apiErr) && !apiErr.Temporary()) {
 			break
 		}
-		if i < maxRetries-1 {
-			time.Sleep(time.Duration(i+1) * time.Second)
-		}
+		time.Sleep(time.Duration(attempt) * time.Second)
 	}
-
 	if err != nil {
-		panic(err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusAccepted {
-		fmt.Printf("Error: %s\n", string(body))
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
-
-	var result map[string]interface{}
-	json.Unmarshal(body, &result)
-
-	jobID := result["jobId"].(string)
-	fmt.Printf("Job submitted: %s\n", jobID)
-
-	// Poll with exponential backoff
-	pollInterval := 2 * time.Second
-	maxPollTime := 10 * time.Minute
-	startTime := time.Now()
-
-	for time.Since(startTime) < maxPollTime {
-		statusURL := fmt.Sprintf("%s/v1/status/%s",
-			apiURL[:len(apiURL)-len("/translate")], jobID)
-
-		statusResp, err := client.Get(statusURL)
-		if err != nil {
-			fmt.Printf("Error polling: %v\n", err)
-			time.Sleep(pollInterval)
-			continue
-		}
-
-		body, _ := io.ReadAll(statusResp.Body)
-		statusResp.Body.Close()
-
-		var status map[string]interface{}
-		json.Unmarshal(body, &status)
-
-		fmt.Printf("Status: %s\n", status["status"])
-
-		if status["status"] == "completed" || status["status"] == "failed" {
-			results := status["results"].(map[string]interface{})
-			for lang, result := range results {
-				resultMap := result.(map[string]interface{})
-				if resultMap["status"] == "completed" {
-					fmt.Printf("  %s: %s\n", lang, resultMap["videoUrl"])
-				}
+	fmt.Printf("Job submitted: %s\n", resp.JobID)
+
+	// Poll with exponential backoff for at most 10 minutes
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	result, err := api.WaitForCompletion(waitCtx, resp.JobID, client.WaitOptions{
+		InitialInterval: 2 * time.Second,
+		MaxInterval:     30 * time.Second,
+		OnLanguage: func(language string, result *models.LanguageResult) {
+			if result.Status == models.StatusCompleted {
+				fmt.Printf("  %s: %s\n", language, result.VideoURL)
+			} else {
+				fmt.Printf("  %s: %s %s\n", language, result.Status, result.Error)
 			}
-			break
-		}
+		},
+	})
+	if err != nil {
+		fmt.Printf("Error polling: %v\n", err)
+		return
+	}
 
-		time.Sleep(pollInterval)
-		pollInterval = time.Duration(float64(pollInterval) * 1.5) // Exponential backoff
-		if pollInterval > 30*time.Second {
-			pollInterval = 30 * time.Second
-		}
+	switch result.State {
+	case client.StateCompleted:
+		fmt.Println("All languages completed")
+	case client.StatePartiallyCompleted:
+		fmt.Printf("Failed languages: %v\n", result.FailedLanguages())
+	case client.StateFailed:
+		fmt.Printf("Job failed: %s\n", result.Status.ErrorCode)
 	}
 }
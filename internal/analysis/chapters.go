@@ -0,0 +1,101 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sinouw/multilingual-video-processor/internal/subtitles"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// MinChapterLength is the shortest chapter kept, in seconds, as on YouTube
+const MinChapterLength = 10
+
+// TimedTranscript renders cues as one "[seconds] text" line each, so a model can place chapters at cue starts
+// Only the leading lines fitting in maxChars characters are kept (0 keeps all); truncated reports whether any were dropped.
+func TimedTranscript(cues []subtitles.Cue, maxChars int) (transcript string, truncated bool) {
+	var b strings.Builder
+	length := 0
+	for _, cue := range cues {
+		text := strings.Join(strings.Fields(cue.Text), " ")
+		if text == "" {
+			continue
+		}
+		line := fmt.Sprintf("[%.1f] %s\n", cue.Start, text)
+		length += utf8.RuneCountInString(line)
+		if maxChars > 0 && length > maxChars {
+			return b.String(), true
+		}
+		b.WriteString(line)
+	}
+	return b.String(), false
+}
+
+// Parse reads a {"keywords": [...], "chapters": [{"start": ..., "title": ...}]} reply and normalizes it
+// Keywords are trimmed, deduplicated ignoring case and capped at models.MaxAnalysisKeywords. Chapters are sorted,
+// moved into [0, duration) (zero leaves the end unbounded), chapters shorter than MinChapterLength are merged into
+// the previous one, and the first chapter starts at 0.
+func Parse(content string, duration float64) (*models.VideoAnalysis, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("analysis does not contain a JSON object")
+	}
+	var parsed models.VideoAnalysis
+	if err := json.Unmarshal([]byte(content[start:end+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse analysis: %w", err)
+	}
+
+	analysis := &models.VideoAnalysis{Keywords: []string{}, Chapters: []models.ChapterMarker{}}
+	seen := make(map[string]bool)
+	for _, keyword := range parsed.Keywords {
+		keyword = strings.TrimSpace(keyword)
+		if keyword == "" || seen[strings.ToLower(keyword)] || len(analysis.Keywords) == models.MaxAnalysisKeywords {
+			continue
+		}
+		seen[strings.ToLower(keyword)] = true
+		analysis.Keywords = append(analysis.Keywords, keyword)
+	}
+
+	chapters := parsed.Chapters
+	sort.SliceStable(chapters, func(i, j int) bool { return chapters[i].Start < chapters[j].Start })
+	for _, chapter := range chapters {
+		chapter.Title = strings.TrimSpace(chapter.Title)
+		chapter.Start = math.Max(chapter.Start, 0)
+		if chapter.Title == "" || math.IsNaN(chapter.Start) || (duration > 0 && chapter.Start >= duration-MinChapterLength) {
+			continue
+		}
+		if n := len(analysis.Chapters); n > 0 && chapter.Start-analysis.Chapters[n-1].Start < MinChapterLength {
+			continue
+		}
+		analysis.Chapters = append(analysis.Chapters, chapter)
+	}
+	if len(analysis.Chapters) > 0 {
+		analysis.Chapters[0].Start = 0
+	}
+	return analysis, nil
+}
+
+// YouTubeChapters renders chapters as "0:00 Title" lines for a YouTube video description
+// YouTube only shows chapters for at least three markers starting at 0:00.
+func YouTubeChapters(chapters []models.ChapterMarker) string {
+	var b strings.Builder
+	for _, chapter := range chapters {
+		fmt.Fprintf(&b, "%s %s\n", chapterTimestamp(chapter.Start), chapter.Title)
+	}
+	return b.String()
+}
+
+// chapterTimestamp formats seconds as m:ss, or h:mm:ss from an hour on
+func chapterTimestamp(seconds float64) string {
+	total := int(seconds)
+	hours, minutes, secs := total/3600, total%3600/60, total%60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, secs)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, secs)
+}
@@ -0,0 +1,73 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/internal/subtitles"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestTimedTranscript(t *testing.T) {
+	cues := []subtitles.Cue{
+		{Start: 0, End: 2, Text: "Welcome back."},
+		{Start: 2.5, End: 3, Text: "  "},
+		{Start: 65.25, End: 70, Text: "Today we\nbuild a shed."},
+	}
+	got, truncated := TimedTranscript(cues, 0)
+	want := "[0.0] Welcome back.\n[65.2] Today we build a shed.\n"
+	if got != want || truncated {
+		t.Errorf("TimedTranscript() = %q, %v, want %q", got, truncated, want)
+	}
+
+	got, truncated = TimedTranscript(cues, 30)
+	if got != "[0.0] Welcome back.\n" || !truncated {
+		t.Errorf("TimedTranscript(30) = %q, %v, want the first line only", got, truncated)
+	}
+}
+
+func TestParse(t *testing.T) {
+	content := "Here you go:\n" + `{
+		"keywords": ["woodworking", " Shed ", "shed", ""],
+		"chapters": [
+			{"start": 130, "title": "Building the roof"},
+			{"start": 4, "title": "Introduction"},
+			{"start": 9, "title": "Too close"},
+			{"start": 60, "title": " Materials "},
+			{"start": 70, "title": ""},
+			{"start": 595, "title": "Too late"}
+		]
+	}`
+
+	got, err := Parse(content, 600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &models.VideoAnalysis{
+		Keywords: []string{"woodworking", "Shed"},
+		Chapters: []models.ChapterMarker{
+			{Start: 0, Title: "Introduction"},
+			{Start: 60, Title: "Materials"},
+			{Start: 130, Title: "Building the roof"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %+v, want %+v", got, want)
+	}
+
+	if _, err := Parse("no analysis", 600); err == nil {
+		t.Error("expected error for a reply without JSON")
+	}
+}
+
+func TestYouTubeChapters(t *testing.T) {
+	got := YouTubeChapters([]models.ChapterMarker{
+		{Start: 0, Title: "Introduction"},
+		{Start: 65.8, Title: "Materials"},
+		{Start: 3725, Title: "Wrap-up"},
+	})
+	want := "0:00 Introduction\n1:05 Materials\n1:02:05 Wrap-up\n"
+	if got != want {
+		t.Errorf("YouTubeChapters() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/workerpool"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// FailJobFunc marks a job and its unfinished languages as failed with the given reason
+type FailJobFunc func(jobID string, reason string)
+
+// AdminOperations are the instance operations exposed by the admin API
+type AdminOperations struct {
+	Store       JobStatusStore
+	Jobs        *JobRegistry
+	RateLimiter *RateLimiter
+	Pools       []*workerpool.Pool
+	Cleanup     func() int // Removes expired jobs, returning how many were removed
+	FailJob     FailJobFunc
+
+	// TranslationMemoryStats returns the sentences looked up in the persistent translation memory and found
+	// there; nil when it is disabled
+	TranslationMemoryStats func() (lookups int64, hits int64)
+}
+
+// AdminHandler handles the /admin API; every route requires an admin API key
+//
+//	GET  /admin/jobs                 active jobs with their stage and per-language progress
+//	GET  /admin/queue                queue depth and worker pool usage of this instance
+//	POST /admin/jobs/{id}/cancel     stop a job and mark it failed
+//	POST /admin/jobs/{id}/fail       mark a stuck job failed with an optional {"reason": "..."}
+//	POST /admin/ratelimit/flush      reset rate limiter buckets (all, or ?client=)
+//	POST /admin/cleanup              remove expired jobs from the store
+func AdminHandler(ops AdminOperations) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if principal := PrincipalFromRequest(r); principal == nil || !principal.IsAdmin() {
+			ErrorResponse(w, http.StatusForbidden, "admin API key required", "")
+			return
+		}
+
+		path := strings.TrimPrefix(r.URL.Path, "/admin")
+		switch {
+		case path == "/jobs":
+			ops.listActiveJobs(w, r)
+		case path == "/queue":
+			ops.queueStats(w, r)
+		case strings.HasPrefix(path, "/jobs/") && strings.HasSuffix(path, "/cancel"):
+			ops.stopJob(w, r, strings.TrimSuffix(strings.TrimPrefix(path, "/jobs/"), "/cancel"), "cancel")
+		case strings.HasPrefix(path, "/jobs/") && strings.HasSuffix(path, "/fail"):
+			ops.stopJob(w, r, strings.TrimSuffix(strings.TrimPrefix(path, "/jobs/"), "/fail"), "fail")
+		case path == "/ratelimit/flush":
+			ops.flushRateLimits(w, r)
+		case path == "/cleanup":
+			ops.cleanup(w, r)
+		default:
+			ErrorResponse(w, http.StatusNotFound, "endpoint not found", "")
+		}
+	}
+}
+
+// listActiveJobs returns every processing job, oldest first
+func (ops AdminOperations) listActiveJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobs := []*models.AdminJob{}
+	for _, status := range ops.Store.ListStatuses() {
+		if status.Status != models.StatusProcessing {
+			continue
+		}
+		job := &models.AdminJob{
+			JobID:     status.JobID,
+			Owner:     status.Owner,
+			Stage:     status.Stage,
+			Running:   ops.Jobs.IsRunning(status.JobID),
+			Languages: make(map[string]int),
+			CreatedAt: status.CreatedAt,
+			UpdatedAt: status.UpdatedAt,
+			Age:       time.Since(createdAt(status)).Round(time.Second).String(),
+		}
+		for lang, result := range status.Results {
+			job.Languages[lang] = result.Progress
+		}
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return adminJobCreated(jobs[i]).Before(adminJobCreated(jobs[j]))
+	})
+
+	writeJSON(w, http.StatusOK, models.AdminJobsResponse{Jobs: jobs, Count: len(jobs)})
+}
+
+// queueStats reports how much work this instance holds
+func (ops AdminOperations) queueStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ops.collectQueueStats())
+}
+
+// collectQueueStats counts the active jobs, pending languages and worker pool usage of this instance
+func (ops AdminOperations) collectQueueStats() models.QueueStats {
+	stats := models.QueueStats{RunningJobs: ops.Jobs.Len()}
+	for _, status := range ops.Store.ListStatuses() {
+		if status.Status != models.StatusProcessing {
+			continue
+		}
+		stats.ActiveJobs++
+		stats.PendingLanguages += pendingLanguages(status)
+	}
+	for _, pool := range ops.Pools {
+		stats.Pools = append(stats.Pools, pool.Stats())
+	}
+	return stats
+}
+
+// stopJob cancels a job's running work and marks it failed
+func (ops AdminOperations) stopJob(w http.ResponseWriter, r *http.Request, jobID string, action string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if jobID == "" || strings.Contains(jobID, "/") {
+		ErrorResponse(w, http.StatusBadRequest, "job ID is required", "")
+		return
+	}
+
+	status, err := ops.Store.GetStatus(jobID)
+	if err != nil {
+		ErrorResponse(w, http.StatusNotFound, "job not found", jobID)
+		return
+	}
+	if status.Status != models.StatusProcessing {
+		ErrorResponse(w, http.StatusConflict, "job is not processing", jobID)
+		return
+	}
+
+	reason := "job cancelled by administrator"
+	if action == "fail" {
+		reason = "job failed by administrator"
+		var body struct {
+			Reason string `json:"reason"`
+		}
+		if r.Body != nil && r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				ErrorResponse(w, http.StatusBadRequest, "invalid request body: "+err.Error(), jobID)
+				return
+			}
+		}
+		if body.Reason != "" {
+			reason = body.Reason
+		}
+	}
+
+	running := ops.Jobs.Cancel(jobID)
+	ops.FailJob(jobID, reason)
+
+	slog.Warn("Admin stopped job", "action", action, "jobID", jobID, "running", running, "reason", reason)
+	writeJSON(w, http.StatusOK, models.AdminActionResponse{Action: action, JobID: jobID})
+}
+
+// flushRateLimits resets rate limiter buckets
+func (ops AdminOperations) flushRateLimits(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	removed := ops.RateLimiter.Flush(r.URL.Query().Get("client"))
+	slog.Info("Admin flushed rate limiter", "client", r.URL.Query().Get("client"), "removed", removed)
+	writeJSON(w, http.StatusOK, models.AdminActionResponse{Action: "ratelimit_flush", Removed: removed})
+}
+
+// cleanup removes expired jobs from the store
+func (ops AdminOperations) cleanup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	removed := ops.Cleanup()
+	slog.Info("Admin triggered job store cleanup", "removed", removed)
+	writeJSON(w, http.StatusOK, models.AdminActionResponse{Action: "cleanup", Removed: removed})
+}
+
+func adminJobCreated(job *models.AdminJob) time.Time {
+	if job.CreatedAt != nil {
+		return *job.CreatedAt
+	}
+	return job.UpdatedAt
+}
+
+// pendingLanguages counts the target languages of a job that have not finished
+func pendingLanguages(status *models.StatusResponse) int {
+	if status.Request == nil {
+		return 0
+	}
+	pending := 0
+	for _, lang := range status.TargetLanguages() {
+		result, exists := status.Results[lang]
+		if !exists || !result.IsFinished() {
+			pending++
+		}
+	}
+	return pending
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,199 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/workerpool"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func newAdminTestOps() (AdminOperations, *mockJobStore, *[]string) {
+	store := newMockJobStore()
+	created := time.Now().Add(-time.Minute)
+	store.SetStatus("job-1", &models.StatusResponse{
+		JobID:     "job-1",
+		Status:    models.StatusProcessing,
+		Stage:     models.StageLanguages,
+		Owner:     "acme",
+		CreatedAt: &created,
+		Results: map[string]*models.LanguageResult{
+			"de": {Status: models.StatusProcessing, Progress: 40},
+		},
+		Request: &models.TranslateRequest{TargetLanguages: []string{"de", "ar"}},
+	})
+	store.SetStatus("job-2", &models.StatusResponse{JobID: "job-2", Status: models.StatusCompleted})
+
+	failed := []string{}
+	ops := AdminOperations{
+		Store:       store,
+		Jobs:        NewJobRegistry(),
+		RateLimiter: &RateLimiter{requestsPerMinute: 1},
+		Pools:       []*workerpool.Pool{workerpool.New("ffmpeg", 2)},
+		Cleanup:     func() int { return 3 },
+		FailJob: func(jobID string, reason string) {
+			failed = append(failed, jobID+": "+reason)
+			store.jobs[jobID].Status = models.StatusFailed
+		},
+	}
+	return ops, store, &failed
+}
+
+func adminRequest(method string, path string, body string, admin bool) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	role := RoleClient
+	if admin {
+		role = RoleAdmin
+	}
+	return WithPrincipal(req, &Principal{Owner: "ops", Role: role})
+}
+
+func TestAdminHandler_RequiresAdmin(t *testing.T) {
+	ops, _, _ := newAdminTestOps()
+	handler := AdminHandler(ops)
+
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{"auth disabled", httptest.NewRequest(http.MethodGet, "/admin/jobs", nil)},
+		{"client key", adminRequest(http.MethodGet, "/admin/jobs", "", false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler(w, tt.req)
+			if w.Code != http.StatusForbidden {
+				t.Errorf("expected status %d, got %d", http.StatusForbidden, w.Code)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_ListActiveJobs(t *testing.T) {
+	ops, _, _ := newAdminTestOps()
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ops.Jobs.Register("job-1", cancel)
+
+	w := httptest.NewRecorder()
+	AdminHandler(ops)(w, adminRequest(http.MethodGet, "/admin/jobs", "", true))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response models.AdminJobsResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Count != 1 {
+		t.Fatalf("expected 1 active job, got %d", response.Count)
+	}
+	job := response.Jobs[0]
+	if job.JobID != "job-1" || job.Owner != "acme" || job.Stage != models.StageLanguages || !job.Running {
+		t.Errorf("unexpected job summary: %+v", job)
+	}
+	if job.Languages["de"] != 40 {
+		t.Errorf("expected de progress 40, got %d", job.Languages["de"])
+	}
+}
+
+func TestAdminHandler_Queue(t *testing.T) {
+	ops, _, _ := newAdminTestOps()
+
+	w := httptest.NewRecorder()
+	AdminHandler(ops)(w, adminRequest(http.MethodGet, "/admin/queue", "", true))
+
+	var stats models.QueueStats
+	if err := json.NewDecoder(w.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if stats.ActiveJobs != 1 || stats.PendingLanguages != 2 || stats.RunningJobs != 0 {
+		t.Errorf("unexpected queue stats: %+v", stats)
+	}
+	if len(stats.Pools) != 1 || stats.Pools[0].Name != "ffmpeg" || stats.Pools[0].Size != 2 {
+		t.Errorf("unexpected pool stats: %+v", stats.Pools)
+	}
+}
+
+func TestAdminHandler_StopJob(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantFailed string
+	}{
+		{"cancel", "/admin/jobs/job-1/cancel", "", http.StatusOK, "job-1: job cancelled by administrator"},
+		{"fail with reason", "/admin/jobs/job-1/fail", `{"reason":"stuck in TTS"}`, http.StatusOK, "job-1: stuck in TTS"},
+		{"not processing", "/admin/jobs/job-2/cancel", "", http.StatusConflict, ""},
+		{"unknown job", "/admin/jobs/missing/fail", "", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, _, failed := newAdminTestOps()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ops.Jobs.Register("job-1", cancel)
+
+			w := httptest.NewRecorder()
+			AdminHandler(ops)(w, adminRequest(http.MethodPost, tt.path, tt.body, true))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantFailed == "" {
+				if len(*failed) != 0 {
+					t.Errorf("expected no job to be failed, got %v", *failed)
+				}
+				return
+			}
+			if len(*failed) != 1 || (*failed)[0] != tt.wantFailed {
+				t.Errorf("expected %q, got %v", tt.wantFailed, *failed)
+			}
+			if ctx.Err() == nil {
+				t.Error("expected running job to be cancelled")
+			}
+		})
+	}
+}
+
+func TestAdminHandler_FlushAndCleanup(t *testing.T) {
+	ops, _, _ := newAdminTestOps()
+	ops.RateLimiter.Allow("1.2.3.4")
+	ops.RateLimiter.Allow("5.6.7.8")
+
+	tests := []struct {
+		path        string
+		wantRemoved int
+	}{
+		{"/admin/ratelimit/flush?client=1.2.3.4", 1},
+		{"/admin/ratelimit/flush", 1},
+		{"/admin/cleanup", 3},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		AdminHandler(ops)(w, adminRequest(http.MethodPost, tt.path, "", true))
+
+		var response models.AdminActionResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode response for %s: %v", tt.path, err)
+		}
+		if response.Removed != tt.wantRemoved {
+			t.Errorf("%s: expected %d removed, got %d", tt.path, tt.wantRemoved, response.Removed)
+		}
+	}
+
+	if !ops.RateLimiter.Allow("1.2.3.4") {
+		t.Error("expected flushed client to be allowed again")
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// AdmissionError refuses to start a job on this instance now; the client may try again after RetryAfter
+type AdmissionError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    map[string]interface{}
+	RetryAfter time.Duration // Zero sends no Retry-After header
+}
+
+func (e *AdmissionError) Error() string {
+	return e.Message
+}
+
+// AdmitFunc admits a job that is about to be retried or restarted on this instance, before it is claimed
+// It reserves what the job holds while it runs; release gives the reservation back and is called once
+// the job finished, or right away when the job is not started after all.
+type AdmitFunc func(r *http.Request, status *models.StatusResponse) (release func(), err *AdmissionError)
+
+// ActiveJobLimitFunc returns how many jobs the owner's API key may process at once; zero does not limit it
+type ActiveJobLimitFunc func(owner string) int
+
+// ActiveJobLimitError refuses a job because its API key already processes maxActive jobs
+func ActiveJobLimitError(maxActive int) *AdmissionError {
+	return &AdmissionError{
+		StatusCode: http.StatusTooManyRequests,
+		Code:       "too_many_concurrent_jobs",
+		Message:    fmt.Sprintf("too many jobs processing: at most %d at once for this API key", maxActive),
+		Details:    map[string]interface{}{"limit": maxActive},
+	}
+}
+
+// WriteAdmissionError answers a refused admission with its status code, error code and Retry-After
+func WriteAdmissionError(w http.ResponseWriter, err *AdmissionError, requestID string) {
+	if err.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(max(ceilSeconds(err.RetryAfter), 1)))
+	}
+	CodedErrorResponse(w, err.StatusCode, err.Code, err.Message, requestID, err.Details)
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// ErrUnauthenticated is returned when a request carries no valid API key
+var ErrUnauthenticated = errors.New("missing or invalid API key")
+
+// Role is the set of permissions granted to an API key
+type Role string
+
+const (
+	RoleClient Role = "client" // Submits jobs and reads its own jobs (API_KEYS)
+	RoleAdmin  Role = "admin"  // Also lists and manages every job and calls /admin and /metrics (ADMIN_API_KEYS)
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Owner string // Stable owner ID recorded on jobs (never the raw key)
+	Role  Role   // Permissions of the key
+	Tier  string // Rate limit tier of the key, empty for the default limits
+
+	ServiceAccount string // Service account impersonated to read the key's sources, empty for the default credentials
+}
+
+// IsAdmin reports whether the principal holds an admin key
+func (p *Principal) IsAdmin() bool {
+	return p.Role == RoleAdmin
+}
+
+// HasRole reports whether the principal is granted the permissions of role; admins hold every role
+func (p *Principal) HasRole(role Role) bool {
+	return p.IsAdmin() || p.Role == role
+}
+
+// CanAccess reports whether the principal may see the job
+func (p *Principal) CanAccess(status *models.StatusResponse) bool {
+	return p.IsAdmin() || status.Owner == p.Owner
+}
+
+// APIKeyAuthenticator authenticates requests by API key
+// Keys are configured as "owner:key" (or a bare "key", owned by a hash of the key).
+type APIKeyAuthenticator struct {
+	keys map[string]*Principal
+}
+
+// NewAPIKeyAuthenticator creates an authenticator from client and admin key specs
+func NewAPIKeyAuthenticator(clientKeys []string, adminKeys []string) *APIKeyAuthenticator {
+	a := &APIKeyAuthenticator{keys: make(map[string]*Principal)}
+	for _, spec := range clientKeys {
+		a.add(spec, RoleClient)
+	}
+	for _, spec := range adminKeys {
+		a.add(spec, RoleAdmin)
+	}
+	return a
+}
+
+func (a *APIKeyAuthenticator) add(spec string, role Role) {
+	owner, key, found := strings.Cut(strings.TrimSpace(spec), ":")
+	if !found {
+		key = owner
+		owner = "key-" + hashKey(key)[:12]
+	}
+	if key == "" {
+		return
+	}
+	a.keys[hashKey(key)] = &Principal{Owner: owner, Role: role}
+}
+
+// SetTiers assigns rate limit tiers to key owners (owner -> tier)
+func (a *APIKeyAuthenticator) SetTiers(tiers map[string]string) {
+	for _, principal := range a.keys {
+		principal.Tier = tiers[principal.Owner]
+	}
+}
+
+// SetServiceAccounts assigns the service accounts impersonated for key owners (owner -> service account)
+func (a *APIKeyAuthenticator) SetServiceAccounts(serviceAccounts map[string]string) {
+	for _, principal := range a.keys {
+		principal.ServiceAccount = serviceAccounts[principal.Owner]
+	}
+}
+
+// Enabled reports whether any API key is configured
+func (a *APIKeyAuthenticator) Enabled() bool {
+	return len(a.keys) > 0
+}
+
+// Authenticate resolves the caller from the Authorization (Bearer) or X-API-Key header
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get("X-API-Key")
+	if auth := r.Header.Get("Authorization"); key == "" && strings.HasPrefix(auth, "Bearer ") {
+		key = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if key == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	// Compare fixed-length hashes in constant time
+	hashed := hashKey(key)
+	for candidate, principal := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(hashed)) == 1 {
+			return principal, nil
+		}
+	}
+	return nil, ErrUnauthenticated
+}
+
+// Middleware authenticates requests and enforces the role their route requires before calling next
+// Unknown keys get 401 and keys without the required role 403. Admin routes are forbidden when
+// authentication is disabled; every other route is open then.
+func (a *APIKeyAuthenticator) Middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var principal *Principal
+		if a.Enabled() {
+			var err error
+			principal, err = a.Authenticate(r)
+			if err != nil {
+				ErrorResponse(w, http.StatusUnauthorized, err.Error(), "")
+				return
+			}
+			r = WithPrincipal(r, principal)
+		}
+
+		if role := RequiredRole(r.URL.Path); role != RoleClient && (principal == nil || !principal.HasRole(role)) {
+			ErrorResponse(w, http.StatusForbidden, string(role)+" API key required", "")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// RequiredRole returns the role a request path requires: admin for /admin and /metrics, client otherwise
+func RequiredRole(path string) Role {
+	if path == "/metrics" || strings.HasPrefix(path, "/admin/") {
+		return RoleAdmin
+	}
+	return RoleClient
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+type principalContextKey struct{}
+
+// WithPrincipal returns a request carrying the authenticated principal
+func WithPrincipal(r *http.Request, principal *Principal) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), principalContextKey{}, principal))
+}
+
+// PrincipalFromRequest returns the authenticated principal, or nil when auth is disabled
+func PrincipalFromRequest(r *http.Request) *Principal {
+	principal, _ := r.Context().Value(principalContextKey{}).(*Principal)
+	return principal
+}
+
+// canAccessJob reports whether the request may see the job; everything is visible when auth is disabled
+func canAccessJob(r *http.Request, status *models.StatusResponse) bool {
+	principal := PrincipalFromRequest(r)
+	return principal == nil || principal.CanAccess(status)
+}
+
+// ScopedJobID returns the job ID stored for a client-supplied jobId: the jobId with a tag of its API key owner
+// appended, so owners never share, squat or probe each other's IDs. Without an owner the jobId is kept as is.
+func ScopedJobID(owner string, jobID string) string {
+	if owner == "" {
+		return jobID
+	}
+	sum := sha256.Sum256([]byte(owner))
+	return jobID + "-" + hex.EncodeToString(sum[:4])
+}
+
+// resolveJobID returns the stored ID of a job named in a request path: the caller's own job with that
+// client-supplied jobId, or otherwise the ID itself (generated and scoped IDs)
+func resolveJobID(r *http.Request, store JobStatusStore, jobID string) string {
+	principal := PrincipalFromRequest(r)
+	if principal == nil || principal.Owner == "" {
+		return jobID
+	}
+	scoped := ScopedJobID(principal.Owner, jobID)
+	if _, err := store.GetStatus(scoped); err == nil {
+		return scoped
+	}
+	return jobID
+}
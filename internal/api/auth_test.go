@@ -0,0 +1,183 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	auth := NewAPIKeyAuthenticator([]string{"acme:acme-key", "bare-key"}, []string{"ops:admin-key"})
+
+	if !auth.Enabled() {
+		t.Fatal("expected authenticator to be enabled")
+	}
+
+	tests := []struct {
+		name      string
+		header    string
+		value     string
+		wantErr   bool
+		wantOwner string
+		wantRole  Role
+	}{
+		{"bearer client key", "Authorization", "Bearer acme-key", false, "acme", RoleClient},
+		{"x-api-key admin key", "X-API-Key", "admin-key", false, "ops", RoleAdmin},
+		{"unknown key", "X-API-Key", "nope", true, "", ""},
+		{"missing key", "", "", true, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/jobs", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			principal, err := auth.Authenticate(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if principal.Owner != tt.wantOwner || principal.Role != tt.wantRole {
+				t.Errorf("expected owner %q role %q, got %+v", tt.wantOwner, tt.wantRole, principal)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthenticator_Tiers(t *testing.T) {
+	auth := NewAPIKeyAuthenticator([]string{"acme:acme-key", "beta:beta-key"}, nil)
+	auth.SetTiers(map[string]string{"acme": "pro"})
+
+	tests := map[string]string{"acme-key": "pro", "beta-key": ""}
+	for key, wantTier := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/v1/jobs", nil)
+		req.Header.Set("X-API-Key", key)
+		principal, err := auth.Authenticate(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if principal.Tier != wantTier {
+			t.Errorf("expected tier %q for %s, got %q", wantTier, principal.Owner, principal.Tier)
+		}
+	}
+}
+
+func TestAPIKeyAuthenticator_ServiceAccounts(t *testing.T) {
+	auth := NewAPIKeyAuthenticator([]string{"acme:acme-key", "beta:beta-key"}, nil)
+	auth.SetServiceAccounts(map[string]string{"acme": "reader@acme.iam.gserviceaccount.com"})
+
+	tests := map[string]string{"acme-key": "reader@acme.iam.gserviceaccount.com", "beta-key": ""}
+	for key, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/v1/jobs", nil)
+		req.Header.Set("X-API-Key", key)
+		principal, err := auth.Authenticate(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if principal.ServiceAccount != want {
+			t.Errorf("expected service account %q for %s, got %q", want, principal.Owner, principal.ServiceAccount)
+		}
+	}
+}
+
+func TestAPIKeyAuthenticator_BareKeyOwner(t *testing.T) {
+	auth := NewAPIKeyAuthenticator([]string{"bare-key"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/jobs", nil)
+	req.Header.Set("X-API-Key", "bare-key")
+	principal, err := auth.Authenticate(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if principal.Owner == "" || principal.Owner == "bare-key" {
+		t.Errorf("expected a derived owner that does not expose the key, got %q", principal.Owner)
+	}
+}
+
+func TestAPIKeyAuthenticator_Disabled(t *testing.T) {
+	if NewAPIKeyAuthenticator(nil, nil).Enabled() {
+		t.Error("expected authenticator without keys to be disabled")
+	}
+}
+
+func TestPrincipal_CanAccess(t *testing.T) {
+	job := &models.StatusResponse{JobID: "job-1", Owner: "acme"}
+
+	if !(&Principal{Owner: "acme"}).CanAccess(job) {
+		t.Error("expected owner to access their job")
+	}
+	if (&Principal{Owner: "globex"}).CanAccess(job) {
+		t.Error("expected other owners to be denied")
+	}
+	if !(&Principal{Owner: "ops", Role: RoleAdmin}).CanAccess(job) {
+		t.Error("expected admin to access every job")
+	}
+}
+
+func TestAPIKeyAuthenticator_Middleware(t *testing.T) {
+	enabled := NewAPIKeyAuthenticator([]string{"acme:acme-key"}, []string{"ops:admin-key"})
+	disabled := NewAPIKeyAuthenticator(nil, nil)
+
+	tests := []struct {
+		name string
+		auth *APIKeyAuthenticator
+		path string
+		key  string
+		want int
+	}{
+		{"client submits", enabled, "/v1/translate", "acme-key", http.StatusOK},
+		{"client reads jobs", enabled, "/v1/jobs", "acme-key", http.StatusOK},
+		{"client on admin API", enabled, "/admin/jobs", "acme-key", http.StatusForbidden},
+		{"client on metrics", enabled, "/metrics", "acme-key", http.StatusForbidden},
+		{"admin on admin API", enabled, "/admin/jobs/job-1/cancel", "admin-key", http.StatusOK},
+		{"admin on metrics", enabled, "/metrics", "admin-key", http.StatusOK},
+		{"admin reads jobs", enabled, "/v1/jobs", "admin-key", http.StatusOK},
+		{"missing key", enabled, "/v1/jobs", "", http.StatusUnauthorized},
+		{"unknown key", enabled, "/metrics", "nope", http.StatusUnauthorized},
+		{"auth disabled", disabled, "/v1/jobs", "", http.StatusOK},
+		{"auth disabled admin API", disabled, "/admin/queue", "", http.StatusForbidden},
+		{"auth disabled metrics", disabled, "/metrics", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var principal *Principal
+			handler := tt.auth.Middleware(func(w http.ResponseWriter, r *http.Request) {
+				principal = PrincipalFromRequest(r)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, w.Code)
+			}
+			if w.Code == http.StatusOK && tt.key != "" && principal == nil {
+				t.Error("expected the principal to be passed to the handler")
+			}
+		})
+	}
+}
+
+func TestPrincipal_HasRole(t *testing.T) {
+	client := &Principal{Owner: "acme", Role: RoleClient}
+	admin := &Principal{Owner: "ops", Role: RoleAdmin}
+
+	if !client.HasRole(RoleClient) || client.HasRole(RoleAdmin) {
+		t.Errorf("expected client key to hold only the client role")
+	}
+	if !admin.HasRole(RoleClient) || !admin.HasRole(RoleAdmin) {
+		t.Errorf("expected admin key to hold every role")
+	}
+}
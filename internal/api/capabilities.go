@@ -0,0 +1,23 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// CapabilitiesHandler serves the deployment's capabilities document
+// The document is built once at startup from runtime configuration
+func CapabilitiesHandler(capabilities *models.CapabilitiesResponse) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(capabilities)
+	}
+}
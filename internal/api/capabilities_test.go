@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestCapabilitiesHandler(t *testing.T) {
+	capabilities := &models.CapabilitiesResponse{
+		APIVersion: "v1",
+		Languages:  []string{"en", "de"},
+		Presets:    models.SupportedPresets,
+		Providers:  map[string]string{"tts": "google-tts"},
+	}
+	handler := CapabilitiesHandler(capabilities)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/capabilities", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response models.CapabilitiesResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(response.Languages) != 2 {
+		t.Errorf("expected 2 languages, got %d", len(response.Languages))
+	}
+	if response.Providers["tts"] != "google-tts" {
+		t.Errorf("expected tts provider 'google-tts', got '%s'", response.Providers["tts"])
+	}
+}
+
+func TestCapabilitiesHandler_MethodNotAllowed(t *testing.T) {
+	handler := CapabilitiesHandler(&models.CapabilitiesResponse{})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/capabilities", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// DuplicateDetector rejects resubmission of the same video and languages within a time window
+// A job's claim can be released early (e.g., when it fails) so it may be submitted again.
+type DuplicateDetector struct {
+	mu     sync.Mutex
+	window time.Duration
+	claims map[string]duplicateClaim
+}
+
+type duplicateClaim struct {
+	jobID     string
+	claimedAt time.Time
+}
+
+// NewDuplicateDetector creates a detector; a zero window disables detection
+func NewDuplicateDetector(window time.Duration) *DuplicateDetector {
+	return &DuplicateDetector{
+		window: window,
+		claims: make(map[string]duplicateClaim),
+	}
+}
+
+// JobFingerprint identifies a submission by owner, video URL and target languages, ignoring language order
+// Including the owner keeps one tenant from learning another tenant's job IDs.
+func JobFingerprint(owner string, videoURL string, targetLanguages []string) string {
+	languages := make([]string, len(targetLanguages))
+	for i, lang := range targetLanguages {
+		languages[i] = strings.ToLower(lang)
+	}
+	sort.Strings(languages)
+	return owner + "|" + videoURL + "|" + strings.Join(languages, ",")
+}
+
+// Claim records jobID for the fingerprint unless another job claimed it within the window
+// Returns the existing job ID and false when the submission is a duplicate.
+func (d *DuplicateDetector) Claim(fingerprint string, jobID string) (string, bool) {
+	if d.window <= 0 {
+		return "", true
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	if claim, exists := d.claims[fingerprint]; exists && now.Sub(claim.claimedAt) < d.window {
+		return claim.jobID, false
+	}
+
+	// Drop expired claims while holding the lock
+	for key, claim := range d.claims {
+		if now.Sub(claim.claimedAt) >= d.window {
+			delete(d.claims, key)
+		}
+	}
+
+	d.claims[fingerprint] = duplicateClaim{jobID: jobID, claimedAt: now}
+	return "", true
+}
+
+// Release removes the claim for fingerprint if it is still held by jobID
+func (d *DuplicateDetector) Release(fingerprint string, jobID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if claim, exists := d.claims[fingerprint]; exists && claim.jobID == jobID {
+		delete(d.claims, fingerprint)
+	}
+}
+
+// ContentKey identifies the outputs a request would produce: the owner, the hash of the source object's content
+// and the request options shaping the outputs. The video URL is replaced by the content hash, so a copy of the
+// same video matches; options that only label or notify (job ID, tags, metadata, notifications, service account)
+// and the order of target languages are ignored.
+func ContentKey(owner string, contentHash string, req *models.TranslateRequest) string {
+	normalized := *req
+	normalized.VideoURL = ""
+	normalized.JobID = ""
+	normalized.Tags = nil
+	normalized.Metadata = nil
+	normalized.NotifyEmail = ""
+	normalized.WebhookEvents = nil
+	normalized.ServiceAccount = ""
+	normalized.Reprocess = false
+	normalized.TargetLanguages = make([]string, len(req.TargetLanguages))
+	for i, lang := range req.TargetLanguages {
+		normalized.TargetLanguages[i] = strings.ToLower(lang)
+	}
+	sort.Strings(normalized.TargetLanguages)
+
+	// Map keys are marshaled in sorted order, so equal options always encode alike
+	options, _ := json.Marshal(&normalized)
+	sum := sha256.Sum256([]byte(owner + "|" + contentHash + "|" + string(options)))
+	return hex.EncodeToString(sum[:])
+}
+
+// ResultIndex remembers completed jobs by content key, so an identical submission within the window can
+// reuse their results instead of being processed again
+type ResultIndex struct {
+	mu     sync.Mutex
+	window time.Duration
+	jobs   map[string]indexedJob
+}
+
+type indexedJob struct {
+	jobID       string
+	completedAt time.Time
+}
+
+// NewResultIndex creates an index keeping completed jobs for window; a zero window disables reuse
+func NewResultIndex(window time.Duration) *ResultIndex {
+	return &ResultIndex{
+		window: window,
+		jobs:   make(map[string]indexedJob),
+	}
+}
+
+// Record indexes a job that completed with every language under its content key
+func (i *ResultIndex) Record(key string, jobID string) {
+	if i.window <= 0 || key == "" {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	// Drop expired jobs while holding the lock
+	now := time.Now()
+	for indexedKey, job := range i.jobs {
+		if now.Sub(job.completedAt) >= i.window {
+			delete(i.jobs, indexedKey)
+		}
+	}
+	i.jobs[key] = indexedJob{jobID: jobID, completedAt: now}
+}
+
+// Lookup returns the job that completed under the content key within the window
+func (i *ResultIndex) Lookup(key string) (string, bool) {
+	if i.window <= 0 || key == "" {
+		return "", false
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	job, exists := i.jobs[key]
+	if !exists || time.Since(job.completedAt) >= i.window {
+		return "", false
+	}
+	return job.jobID, true
+}
+
+// Empty reports whether no completed job can be reused, so submissions can skip hashing their source
+func (i *ResultIndex) Empty() bool {
+	if i.window <= 0 {
+		return true
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, job := range i.jobs {
+		if time.Since(job.completedAt) < i.window {
+			return false
+		}
+	}
+	return true
+}
+
+// Forget removes the content key if it still indexes jobID, e.g. once the job's results are gone
+func (i *ResultIndex) Forget(key string, jobID string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if job, exists := i.jobs[key]; exists && job.jobID == jobID {
+		delete(i.jobs, key)
+	}
+}
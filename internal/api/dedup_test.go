@@ -0,0 +1,133 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestJobFingerprint(t *testing.T) {
+	a := JobFingerprint("", "gs://bucket/video.mp4", []string{"de", "EN"})
+	b := JobFingerprint("", "gs://bucket/video.mp4", []string{"en", "de"})
+	if a != b {
+		t.Errorf("expected language order and case to be ignored, got %q and %q", a, b)
+	}
+
+	if a == JobFingerprint("", "gs://bucket/video.mp4", []string{"en"}) {
+		t.Error("expected different languages to produce different fingerprints")
+	}
+
+	if a == JobFingerprint("acme", "gs://bucket/video.mp4", []string{"en", "de"}) {
+		t.Error("expected different owners to produce different fingerprints")
+	}
+}
+
+func TestDuplicateDetector(t *testing.T) {
+	detector := NewDuplicateDetector(time.Minute)
+	fingerprint := JobFingerprint("", "gs://bucket/video.mp4", []string{"en"})
+
+	if _, ok := detector.Claim(fingerprint, "job-1"); !ok {
+		t.Fatal("expected first submission to be accepted")
+	}
+
+	existing, ok := detector.Claim(fingerprint, "job-2")
+	if ok || existing != "job-1" {
+		t.Errorf("expected duplicate of job-1, got ok=%v existing=%q", ok, existing)
+	}
+
+	// Releasing with another job ID must not drop the claim
+	detector.Release(fingerprint, "job-2")
+	if _, ok := detector.Claim(fingerprint, "job-3"); ok {
+		t.Error("expected claim to survive release by another job")
+	}
+
+	detector.Release(fingerprint, "job-1")
+	if _, ok := detector.Claim(fingerprint, "job-4"); !ok {
+		t.Error("expected submission to be accepted after release")
+	}
+}
+
+func TestDuplicateDetector_Window(t *testing.T) {
+	detector := NewDuplicateDetector(10 * time.Millisecond)
+	fingerprint := JobFingerprint("", "gs://bucket/video.mp4", []string{"en"})
+
+	detector.Claim(fingerprint, "job-1")
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := detector.Claim(fingerprint, "job-2"); !ok {
+		t.Error("expected submission to be accepted after the window")
+	}
+}
+
+func TestDuplicateDetector_Disabled(t *testing.T) {
+	detector := NewDuplicateDetector(0)
+	fingerprint := JobFingerprint("", "gs://bucket/video.mp4", []string{"en"})
+
+	detector.Claim(fingerprint, "job-1")
+	if _, ok := detector.Claim(fingerprint, "job-2"); !ok {
+		t.Error("expected duplicates to be accepted when detection is disabled")
+	}
+}
+
+func TestContentKey(t *testing.T) {
+	req := &models.TranslateRequest{
+		VideoURL:        "gs://bucket/video.mp4",
+		TargetLanguages: []string{"de", "EN"},
+		Tags:            []string{"campaign-a"},
+	}
+	key := ContentKey("", "crc32c:9a71bb4c:5", req)
+
+	copied := &models.TranslateRequest{
+		VideoURL:        "gs://other/copy.mp4",
+		TargetLanguages: []string{"en", "de"},
+		JobID:           "resubmission",
+		Metadata:        map[string]string{"ticket": "42"},
+		Reprocess:       true,
+	}
+	if got := ContentKey("", "crc32c:9a71bb4c:5", copied); got != key {
+		t.Error("expected a copy of the video with the same outputs to share the content key")
+	}
+
+	if ContentKey("", "crc32c:00000000:5", req) == key {
+		t.Error("expected different content to change the key")
+	}
+	if ContentKey("acme", "crc32c:9a71bb4c:5", req) == key {
+		t.Error("expected different owners to change the key")
+	}
+	withVoice := *req
+	withVoice.VoiceGender = models.VoiceGenderMale
+	if ContentKey("", "crc32c:9a71bb4c:5", &withVoice) == key {
+		t.Error("expected options shaping the outputs to change the key")
+	}
+}
+
+func TestResultIndex(t *testing.T) {
+	index := NewResultIndex(time.Minute)
+	if _, ok := index.Lookup("key"); ok || !index.Empty() {
+		t.Fatal("expected an empty index")
+	}
+
+	index.Record("key", "job-1")
+	if index.Empty() {
+		t.Error("expected a recorded job to be reusable")
+	}
+	if jobID, ok := index.Lookup("key"); !ok || jobID != "job-1" {
+		t.Errorf("expected job-1, got %q (%v)", jobID, ok)
+	}
+
+	index.Forget("key", "job-2")
+	if _, ok := index.Lookup("key"); !ok {
+		t.Error("expected Forget of another job to keep the entry")
+	}
+	index.Forget("key", "job-1")
+	if _, ok := index.Lookup("key"); ok {
+		t.Error("expected the forgotten job to be gone")
+	}
+
+	disabled := NewResultIndex(0)
+	disabled.Record("key", "job-1")
+	if _, ok := disabled.Lookup("key"); ok || !disabled.Empty() {
+		t.Error("expected a zero window to disable reuse")
+	}
+}
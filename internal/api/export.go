@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// ExportFunc writes a job archive to storage, zipping the output files alongside it when artifacts is set
+type ExportFunc func(ctx context.Context, archive *models.JobArchive, artifacts bool) (*models.ExportResponse, error)
+
+// ExportHandler handles POST /v1/jobs/{id}/export[?artifacts=true]
+// The job's record is written as JSON for archival; the job must not be processing.
+func ExportHandler(store JobStatusStore, export ExportFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		jobID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/jobs/"), "/export")
+		if jobID == "" || strings.Contains(jobID, "/") {
+			ErrorResponse(w, http.StatusBadRequest, "job ID is required", "")
+			return
+		}
+		jobID = resolveJobID(r, store, jobID)
+
+		status, err := store.GetStatus(jobID)
+		if err != nil || !canAccessJob(r, status) {
+			ErrorResponse(w, http.StatusNotFound, "job not found", jobID)
+			return
+		}
+		if status.Status == models.StatusProcessing {
+			ErrorResponse(w, http.StatusConflict, "job is still processing", jobID)
+			return
+		}
+		archive := BuildJobArchive(status)
+
+		slog.Info("Export request", "jobID", jobID)
+		response, err := export(r.Context(), archive, r.URL.Query().Get("artifacts") == "true")
+		if err != nil {
+			slog.Error("Failed to export job", "error", err, "jobID", jobID)
+			ErrorResponse(w, http.StatusInternalServerError, "failed to export job", jobID)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// BuildJobArchive collects the full record of a job: request, transcript, translations, outputs and event log
+// The status is copied so the archive is not changed by later updates to the job.
+func BuildJobArchive(status *models.StatusResponse) *models.JobArchive {
+	snapshot := *status
+	snapshot.Results = make(map[string]*models.LanguageResult, len(status.Results))
+	for lang, result := range status.Results {
+		copied := *result
+		snapshot.Results[lang] = &copied
+	}
+
+	archive := &models.JobArchive{
+		JobID:        status.JobID,
+		ExportedAt:   time.Now(),
+		Owner:        status.Owner,
+		Request:      status.Request,
+		Status:       &snapshot,
+		Translations: make(map[string]string),
+		Outputs:      archiveOutputs(status),
+		Events:       append([]models.JobEvent(nil), status.Events...),
+	}
+	if status.Checkpoint != nil {
+		archive.SourceLanguage = status.Checkpoint.SourceLanguage
+		archive.Transcript = status.Checkpoint.Transcript
+	}
+	for lang, result := range status.Results {
+		if result.TranslatedText != "" {
+			archive.Translations[lang] = result.TranslatedText
+		}
+	}
+	return archive
+}
+
+// archiveOutputs lists the job's output files, each once, with shared outputs first and languages in order
+func archiveOutputs(status *models.StatusResponse) []models.ArchivedArtifact {
+	var outputs []models.ArchivedArtifact
+	seen := make(map[string]bool)
+	add := func(language, kind, url string) {
+		if url == "" || seen[url] {
+			return
+		}
+		seen[url] = true
+		outputs = append(outputs, models.ArchivedArtifact{
+			Language: language,
+			Kind:     kind,
+			Name:     path.Join(language, path.Base(url)),
+			URL:      url,
+		})
+	}
+
+	if status.Checkpoint != nil {
+		add("", models.ArtifactTranscript, status.Checkpoint.TranscriptURL)
+	}
+	add("", "manifest", status.ManifestURL)
+
+	languages := make([]string, 0, len(status.Results))
+	for lang := range status.Results {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+
+	for _, lang := range languages {
+		result := status.Results[lang]
+		add(lang, "video", result.VideoURL)
+		add(lang, "translatedText", result.TranslatedTextURL)
+		add(lang, "subtitles", result.SubtitlesURL)
+
+		kinds := make([]string, 0, len(result.Artifacts))
+		for kind := range result.Artifacts {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			add(lang, kind, result.Artifacts[kind])
+		}
+	}
+	return outputs
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func newExportableJob(jobID string) *models.StatusResponse {
+	return &models.StatusResponse{
+		JobID:       jobID,
+		Status:      models.StatusCompleted,
+		ManifestURL: "https://storage.googleapis.com/out/translations/job-1/manifest.json",
+		Results: map[string]*models.LanguageResult{
+			"de": {
+				Status:            models.StatusCompleted,
+				VideoURL:          "https://storage.googleapis.com/out/translations/job-1/de.mp4",
+				TranslatedTextURL: "https://storage.googleapis.com/out/translations/job-1/de/translation.txt",
+				TranslatedText:    "Hallo",
+				Artifacts: map[string]string{
+					models.ArtifactTranscript: "https://storage.googleapis.com/out/translations/job-1/de/translation.txt",
+					models.ArtifactAudio:      "https://storage.googleapis.com/out/translations/job-1/de/audio.mp3",
+				},
+			},
+		},
+		Request: &models.TranslateRequest{VideoURL: "gs://in/video.mp4", TargetLanguages: []string{"de"}},
+		Checkpoint: &models.JobCheckpoint{
+			Transcript:     "Hello",
+			SourceLanguage: "en",
+			TranscriptURL:  "https://storage.googleapis.com/out/translations/job-1/transcript.txt",
+		},
+		Events: []models.JobEvent{{Event: models.EventJobCompleted}},
+	}
+}
+
+func TestBuildJobArchive(t *testing.T) {
+	status := newExportableJob("job-1")
+	archive := BuildJobArchive(status)
+
+	if archive.Transcript != "Hello" || archive.SourceLanguage != "en" {
+		t.Errorf("expected transcript from checkpoint, got %q (%s)", archive.Transcript, archive.SourceLanguage)
+	}
+	if archive.Translations["de"] != "Hallo" {
+		t.Errorf("expected German translation, got %v", archive.Translations)
+	}
+	if len(archive.Events) != 1 {
+		t.Errorf("expected 1 event, got %d", len(archive.Events))
+	}
+
+	var names []string
+	for _, output := range archive.Outputs {
+		names = append(names, output.Name)
+	}
+	want := []string{"transcript.txt", "manifest.json", "de/de.mp4", "de/translation.txt", "de/audio.mp3"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected outputs %v, got %v", want, names)
+	}
+
+	// The archive is a snapshot that later updates do not change
+	status.Results["de"].Status = models.StatusProcessing
+	if archive.Status.Results["de"].Status != models.StatusCompleted {
+		t.Error("expected archive results to be copied")
+	}
+}
+
+func TestExportHandler(t *testing.T) {
+	store := newMockJobStore()
+	store.SetStatus("job-1", newExportableJob("job-1"))
+
+	var exported *models.JobArchive
+	var withArtifacts bool
+	handler := ExportHandler(store, func(ctx context.Context, archive *models.JobArchive, artifacts bool) (*models.ExportResponse, error) {
+		exported, withArtifacts = archive, artifacts
+		return &models.ExportResponse{JobID: archive.JobID, ArchiveURL: "https://storage.googleapis.com/out/exports/job-1/job.json"}, nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/jobs/job-1/export?artifacts=true", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if exported == nil || exported.JobID != "job-1" || !withArtifacts {
+		t.Errorf("expected job-1 exported with artifacts, got %+v (artifacts %v)", exported, withArtifacts)
+	}
+
+	var response models.ExportResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.ArchiveURL == "" {
+		t.Error("expected archive URL in response")
+	}
+}
+
+func TestExportHandler_Errors(t *testing.T) {
+	store := newMockJobStore()
+	processing := newExportableJob("job-running")
+	processing.Status = models.StatusProcessing
+	store.SetStatus("job-running", processing)
+	store.SetStatus("job-1", newExportableJob("job-1"))
+
+	failing := func(ctx context.Context, archive *models.JobArchive, artifacts bool) (*models.ExportResponse, error) {
+		return nil, errors.New("bucket unavailable")
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{"wrong method", http.MethodGet, "/v1/jobs/job-1/export", http.StatusMethodNotAllowed},
+		{"unknown job", http.MethodPost, "/v1/jobs/missing/export", http.StatusNotFound},
+		{"processing job", http.MethodPost, "/v1/jobs/job-running/export", http.StatusConflict},
+		{"export failure", http.MethodPost, "/v1/jobs/job-1/export", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ExportHandler(store, failing)(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
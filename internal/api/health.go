@@ -10,12 +10,21 @@ import (
 	"github.com/sinouw/multilingual-video-processor/pkg/models"
 )
 
+// buildInfo is reported by the health check once set at startup
+var buildInfo *models.BuildInfo
+
+// SetBuildInfo sets the build and dependency information reported by HealthHandler
+func SetBuildInfo(info *models.BuildInfo) {
+	buildInfo = info
+}
+
 // HealthHandler handles health check requests
 func HealthHandler(w http.ResponseWriter, r *http.Request) {
 	response := models.HealthResponse{
 		Status:    "healthy",
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 		Version:   "1.0.0",
+		Build:     buildInfo,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -48,14 +57,42 @@ func LivenessHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// StartupHandler handles startup probe requests
+// It reports 503 until check succeeds, so Cloud Run only routes traffic to an instance that can process jobs.
+func StartupHandler(check func() error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response := models.HealthResponse{
+			Status:    "started",
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+		}
+		statusCode := http.StatusOK
+		if err := check(); err != nil {
+			slog.Warn("Startup check failed", "error", err)
+			response.Status = "starting"
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
 // ErrorResponse sends an error response
 func ErrorResponse(w http.ResponseWriter, statusCode int, message string, requestID string) {
-	slog.Error("Request error", "statusCode", statusCode, "message", message, "requestID", requestID)
+	CodedErrorResponse(w, statusCode, "", message, requestID, nil)
+}
+
+// CodedErrorResponse sends an error response with a machine-readable code and optional details
+func CodedErrorResponse(w http.ResponseWriter, statusCode int, code string, message string, requestID string, details map[string]interface{}) {
+	slog.Error("Request error", "statusCode", statusCode, "code", code, "message", message, "requestID", requestID)
 
 	response := models.ErrorResponse{
 		Error:     http.StatusText(statusCode),
+		Code:      code,
 		Message:   message,
 		RequestID: requestID,
+		Details:   details,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -43,6 +44,39 @@ func TestHealthHandler(t *testing.T) {
 	}
 }
 
+func TestHealthHandler_BuildInfo(t *testing.T) {
+	SetBuildInfo(&models.BuildInfo{
+		GitCommit:    "abc1234",
+		GoVersion:    "go1.23.4",
+		Dependencies: map[string]string{"ffmpeg": "6.1.1"},
+		Providers:    map[string]string{"cache": "redis"},
+	})
+	defer SetBuildInfo(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+
+	HealthHandler(w, req)
+
+	var response models.HealthResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if response.Build == nil {
+		t.Fatal("expected build info to be set")
+	}
+	if response.Build.GitCommit != "abc1234" {
+		t.Errorf("expected git commit 'abc1234', got '%s'", response.Build.GitCommit)
+	}
+	if response.Build.Dependencies["ffmpeg"] != "6.1.1" {
+		t.Errorf("expected ffmpeg version '6.1.1', got '%s'", response.Build.Dependencies["ffmpeg"])
+	}
+	if response.Build.Providers["cache"] != "redis" {
+		t.Errorf("expected cache provider 'redis', got '%s'", response.Build.Providers["cache"])
+	}
+}
+
 func TestReadinessHandler(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/health/ready", nil)
 	w := httptest.NewRecorder()
@@ -67,6 +101,37 @@ func TestReadinessHandler(t *testing.T) {
 	}
 }
 
+func TestStartupHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		check      func() error
+		wantCode   int
+		wantStatus string
+	}{
+		{"started", func() error { return nil }, http.StatusOK, "started"},
+		{"starting", func() error { return fmt.Errorf("ffmpeg not found") }, http.StatusServiceUnavailable, "starting"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/health/startup", nil)
+			w := httptest.NewRecorder()
+
+			StartupHandler(tt.check)(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			var response models.HealthResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Status != tt.wantStatus {
+				t.Errorf("expected status '%s', got '%s'", tt.wantStatus, response.Status)
+			}
+		})
+	}
+}
+
 func TestLivenessHandler(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/health/live", nil)
 	w := httptest.NewRecorder()
@@ -143,3 +208,25 @@ func TestErrorResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestCodedErrorResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	CodedErrorResponse(w, http.StatusConflict, "duplicate_job", "duplicate", "req-1", map[string]interface{}{"jobId": "job-1"})
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	var response models.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if response.Code != "duplicate_job" {
+		t.Errorf("expected code 'duplicate_job', got '%s'", response.Code)
+	}
+	if response.Details["jobId"] != "job-1" {
+		t.Errorf("expected jobId detail 'job-1', got %v", response.Details["jobId"])
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import "sync"
+
+// InFlightLimiter bounds how many jobs a client (IP address or API key owner) has processing at once
+// Unlike the rate limiter, which counts submissions per minute, it counts jobs until they finish, so a client
+// cannot saturate the instance with slow jobs while staying under its rate limit.
+type InFlightLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	active map[string]int
+}
+
+// NewInFlightLimiter creates a limiter allowing limit jobs per client; a zero limit disables it
+func NewInFlightLimiter(limit int) *InFlightLimiter {
+	return &InFlightLimiter{
+		limit:  limit,
+		active: make(map[string]int),
+	}
+}
+
+// Limit returns the number of jobs a client may have processing at once (zero when unlimited)
+func (l *InFlightLimiter) Limit() int {
+	return l.limit
+}
+
+// Acquire takes a slot for identifier unless it already has limit jobs in flight
+// Returns a function releasing the slot (safe to call more than once) and true, or nil and false at the limit.
+func (l *InFlightLimiter) Acquire(identifier string) (func(), bool) {
+	if l.limit <= 0 {
+		return func() {}, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.active[identifier] >= l.limit {
+		return nil, false
+	}
+	l.active[identifier]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { l.release(identifier) })
+	}, true
+}
+
+// release frees a slot of identifier, dropping clients with nothing in flight
+func (l *InFlightLimiter) release(identifier string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.active[identifier] <= 1 {
+		delete(l.active, identifier)
+		return
+	}
+	l.active[identifier]--
+}
+
+// Active returns the number of jobs identifier has in flight
+func (l *InFlightLimiter) Active(identifier string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.active[identifier]
+}
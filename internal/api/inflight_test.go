@@ -0,0 +1,50 @@
+package api
+
+import "testing"
+
+func TestInFlightLimiter(t *testing.T) {
+	limiter := NewInFlightLimiter(2)
+
+	releaseFirst, ok := limiter.Acquire("1.2.3.4")
+	if !ok {
+		t.Fatal("expected first job to be allowed")
+	}
+	if _, ok := limiter.Acquire("1.2.3.4"); !ok {
+		t.Fatal("expected second job to be allowed")
+	}
+	if _, ok := limiter.Acquire("1.2.3.4"); ok {
+		t.Error("expected third job to be rejected while two are in flight")
+	}
+
+	// Clients are limited separately
+	if _, ok := limiter.Acquire("key:acme"); !ok {
+		t.Error("expected another client's job to be allowed")
+	}
+
+	// Releasing twice frees a single slot
+	releaseFirst()
+	releaseFirst()
+	if got := limiter.Active("1.2.3.4"); got != 1 {
+		t.Errorf("Active() = %d, want 1", got)
+	}
+	if _, ok := limiter.Acquire("1.2.3.4"); !ok {
+		t.Error("expected a job to be allowed once one finished")
+	}
+	if _, ok := limiter.Acquire("1.2.3.4"); ok {
+		t.Error("expected the limit to apply again")
+	}
+}
+
+func TestInFlightLimiter_Disabled(t *testing.T) {
+	limiter := NewInFlightLimiter(0)
+	for i := 0; i < 10; i++ {
+		release, ok := limiter.Acquire("1.2.3.4")
+		if !ok {
+			t.Fatalf("expected job %d to be allowed without a limit", i+1)
+		}
+		defer release()
+	}
+	if got := limiter.Active("1.2.3.4"); got != 0 {
+		t.Errorf("Active() = %d, want 0 when disabled", got)
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// IPFilter admits API clients by network: denied networks are always rejected and, when networks are allowed,
+// only clients in them are admitted
+type IPFilter struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+
+	// proxyHops is how many proxies in front of the service append to X-Forwarded-For; the client address is
+	// the entry added by the outermost of them. 0 uses the connection address.
+	proxyHops int
+}
+
+// NewIPFilter creates a filter from CIDR ranges or single addresses, e.g. "10.0.0.0/8" or "203.0.113.7"
+func NewIPFilter(allow []string, deny []string, proxyHops int) (*IPFilter, error) {
+	allowNets, err := ParseNetworks(allow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IP allowlist: %w", err)
+	}
+	denyNets, err := ParseNetworks(deny)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IP denylist: %w", err)
+	}
+	return &IPFilter{allow: allowNets, deny: denyNets, proxyHops: proxyHops}, nil
+}
+
+// ParseNetworks parses CIDR ranges, single IPv4 or IPv6 addresses being taken as one-address ranges
+func ParseNetworks(values []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("%q is not an IP address or CIDR range", value)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an IP address or CIDR range", value)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// Enabled reports whether any network is allowed or denied
+func (f *IPFilter) Enabled() bool {
+	return len(f.allow) > 0 || len(f.deny) > 0
+}
+
+// Allows reports whether a client address is admitted; unparsable addresses are admitted only without an allowlist
+func (f *IPFilter) Allows(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return len(f.allow) == 0
+	}
+	for _, network := range f.deny {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	if len(f.allow) == 0 {
+		return true
+	}
+	for _, network := range f.allow {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address of the client as seen by the proxies in front of the service
+// Unlike GetClientIP, entries a client adds to X-Forwarded-For itself are ignored, so the address cannot be spoofed.
+func (f *IPFilter) ClientIP(r *http.Request) string {
+	if f.proxyHops > 0 {
+		var entries []string
+		for _, header := range r.Header.Values("X-Forwarded-For") {
+			for _, entry := range strings.Split(header, ",") {
+				entries = append(entries, strings.TrimSpace(entry))
+			}
+		}
+		if len(entries) >= f.proxyHops {
+			return entries[len(entries)-f.proxyHops]
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Middleware rejects clients outside the allowed networks with 403 Forbidden before authentication and rate limiting
+func (f *IPFilter) Middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if f.Enabled() && !f.Allows(f.ClientIP(r)) {
+			CodedErrorResponse(w, http.StatusForbidden, "ip_not_allowed", "client IP address is not allowed", "", nil)
+			return
+		}
+		next(w, r)
+	}
+}
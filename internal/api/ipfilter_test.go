@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIPFilter_Allows(t *testing.T) {
+	filter, err := NewIPFilter([]string{"10.0.0.0/8", "203.0.113.7", "2001:db8::/32"}, []string{"10.66.0.0/16"}, 0)
+	if err != nil {
+		t.Fatalf("NewIPFilter failed: %v", err)
+	}
+
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{"10.1.2.3", true},
+		{"10.66.1.1", false}, // Denied range inside an allowed one
+		{"203.0.113.7", true},
+		{"203.0.113.8", false},
+		{"2001:db8::1", true},
+		{"192.168.1.1", false},
+		{"not-an-ip", false},
+	}
+	for _, tt := range tests {
+		if got := filter.Allows(tt.address); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.address, got, tt.want)
+		}
+	}
+
+	denyOnly, _ := NewIPFilter(nil, []string{"198.51.100.0/24"}, 0)
+	if denyOnly.Allows("198.51.100.20") || !denyOnly.Allows("192.168.1.1") {
+		t.Error("expected a denylist alone to reject only its networks")
+	}
+}
+
+func TestNewIPFilter_Invalid(t *testing.T) {
+	if _, err := NewIPFilter([]string{"10.0.0.0/33"}, nil, 0); err == nil {
+		t.Error("expected error for invalid CIDR")
+	}
+	if _, err := NewIPFilter(nil, []string{"example.com"}, 0); err == nil {
+		t.Error("expected error for host name")
+	}
+}
+
+func TestIPFilter_ClientIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/v1/translate", nil)
+	req.RemoteAddr = "169.254.1.1:41234"
+	req.Header.Set("X-Forwarded-For", "10.1.1.1, 198.51.100.20") // The client claims 10.1.1.1
+
+	tests := []struct {
+		hops int
+		want string
+	}{
+		{0, "169.254.1.1"},
+		{1, "198.51.100.20"},
+		{2, "10.1.1.1"},
+		{3, "169.254.1.1"}, // Fewer entries than proxies
+	}
+	for _, tt := range tests {
+		filter, _ := NewIPFilter(nil, nil, tt.hops)
+		if got := filter.ClientIP(req); got != tt.want {
+			t.Errorf("ClientIP with %d hops = %q, want %q", tt.hops, got, tt.want)
+		}
+	}
+}
+
+func TestIPFilter_Middleware(t *testing.T) {
+	filter, _ := NewIPFilter([]string{"10.0.0.0/8"}, nil, 1)
+	handler := filter.Middleware(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	tests := []struct {
+		forwardedFor string
+		want         int
+	}{
+		{"10.1.2.3", http.StatusAccepted},
+		{"10.1.2.3, 198.51.100.20", http.StatusForbidden}, // Spoofed first entry
+		{"198.51.100.20", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/v1/translate", nil)
+		req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code != tt.want {
+			t.Errorf("X-Forwarded-For %q: expected status %d, got %d", tt.forwardedFor, tt.want, w.Code)
+		}
+	}
+}
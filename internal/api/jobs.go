@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+const (
+	defaultJobListLimit = 50
+	maxJobListLimit     = 200
+)
+
+// JobsHandler handles GET /v1/jobs, listing the caller's jobs newest first
+// Supports ?status= to filter by job status, ?tag= (repeatable, all must match),
+// ?metadata.{key}={value} to filter by metadata and ?limit= (default 50, max 200)
+func JobsHandler(store JobStatusStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		limit := defaultJobListLimit
+		if value := r.URL.Query().Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 {
+				ErrorResponse(w, http.StatusBadRequest, "limit must be a positive integer", "")
+				return
+			}
+			limit = min(parsed, maxJobListLimit)
+		}
+		statusFilter := models.TranslationStatus(r.URL.Query().Get("status"))
+		tagFilter := r.URL.Query()["tag"]
+		metadataFilter := map[string]string{}
+		for key, values := range r.URL.Query() {
+			if name, ok := strings.CutPrefix(key, "metadata."); ok && len(values) > 0 {
+				metadataFilter[name] = values[0]
+			}
+		}
+
+		jobs := []*models.StatusResponse{}
+		for _, status := range store.ListStatuses() {
+			if !canAccessJob(r, status) {
+				continue
+			}
+			if statusFilter != "" && status.Status != statusFilter {
+				continue
+			}
+			if !hasTags(status, tagFilter) || !hasMetadata(status, metadataFilter) {
+				continue
+			}
+			jobs = append(jobs, status)
+		}
+
+		sort.Slice(jobs, func(i, j int) bool {
+			return createdAt(jobs[i]).After(createdAt(jobs[j]))
+		})
+		if len(jobs) > limit {
+			jobs = jobs[:limit]
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(models.JobListResponse{
+			Jobs:  jobs,
+			Count: len(jobs),
+		})
+	}
+}
+
+// hasTags reports whether a job carries every given tag
+func hasTags(status *models.StatusResponse, tags []string) bool {
+	for _, tag := range tags {
+		if !slices.Contains(status.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasMetadata reports whether a job's metadata contains every given key/value pair
+func hasMetadata(status *models.StatusResponse, metadata map[string]string) bool {
+	for key, value := range metadata {
+		if actual, ok := status.Metadata[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// createdAt returns the job creation time, falling back to the last update
+func createdAt(status *models.StatusResponse) time.Time {
+	if status.CreatedAt != nil {
+		return *status.CreatedAt
+	}
+	return status.UpdatedAt
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func newJobListStore() *mockJobStore {
+	store := newMockJobStore()
+	base := time.Now()
+	jobs := []struct {
+		id     string
+		owner  string
+		status models.TranslationStatus
+		age    time.Duration
+	}{
+		{"acme-old", "acme", models.StatusCompleted, 2 * time.Hour},
+		{"acme-new", "acme", models.StatusFailed, time.Hour},
+		{"globex-1", "globex", models.StatusCompleted, 30 * time.Minute},
+	}
+	for _, job := range jobs {
+		created := base.Add(-job.age)
+		store.SetStatus(job.id, &models.StatusResponse{JobID: job.id, Owner: job.owner, Status: job.status, CreatedAt: &created})
+	}
+	return store
+}
+
+func listJobs(t *testing.T, store JobStatusStore, principal *Principal, query string) models.JobListResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/v1/jobs"+query, nil)
+	if principal != nil {
+		req = WithPrincipal(req, principal)
+	}
+	w := httptest.NewRecorder()
+	JobsHandler(store)(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var response models.JobListResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return response
+}
+
+func TestJobsHandler_ScopedToOwner(t *testing.T) {
+	response := listJobs(t, newJobListStore(), &Principal{Owner: "acme"}, "")
+
+	if response.Count != 2 {
+		t.Fatalf("expected 2 jobs, got %d", response.Count)
+	}
+	if response.Jobs[0].JobID != "acme-new" || response.Jobs[1].JobID != "acme-old" {
+		t.Errorf("expected newest first, got %s, %s", response.Jobs[0].JobID, response.Jobs[1].JobID)
+	}
+}
+
+func TestJobsHandler_AdminSeesAll(t *testing.T) {
+	response := listJobs(t, newJobListStore(), &Principal{Owner: "ops", Role: RoleAdmin}, "")
+	if response.Count != 3 {
+		t.Errorf("expected 3 jobs, got %d", response.Count)
+	}
+}
+
+func TestJobsHandler_Filters(t *testing.T) {
+	store := newJobListStore()
+
+	if response := listJobs(t, store, nil, "?status=completed"); response.Count != 2 {
+		t.Errorf("expected 2 completed jobs, got %d", response.Count)
+	}
+	if response := listJobs(t, store, nil, "?limit=1"); response.Count != 1 || response.Jobs[0].JobID != "globex-1" {
+		t.Errorf("expected only the newest job, got %+v", response.Jobs)
+	}
+}
+
+func TestJobsHandler_TagAndMetadataFilters(t *testing.T) {
+	store := newMockJobStore()
+	store.SetStatus("campaign-de", &models.StatusResponse{JobID: "campaign-de", Tags: []string{"campaign", "de"}, Metadata: map[string]string{"assetId": "42"}})
+	store.SetStatus("campaign-fr", &models.StatusResponse{JobID: "campaign-fr", Tags: []string{"campaign", "fr"}, Metadata: map[string]string{"assetId": "43"}})
+	store.SetStatus("untagged", &models.StatusResponse{JobID: "untagged"})
+
+	if response := listJobs(t, store, nil, "?tag=campaign"); response.Count != 2 {
+		t.Errorf("expected 2 campaign jobs, got %d", response.Count)
+	}
+	if response := listJobs(t, store, nil, "?tag=campaign&tag=fr"); response.Count != 1 || response.Jobs[0].JobID != "campaign-fr" {
+		t.Errorf("expected only campaign-fr, got %+v", response.Jobs)
+	}
+	if response := listJobs(t, store, nil, "?metadata.assetId=42"); response.Count != 1 || response.Jobs[0].JobID != "campaign-de" {
+		t.Errorf("expected only campaign-de, got %+v", response.Jobs)
+	}
+	if response := listJobs(t, store, nil, "?metadata.assetId=99"); response.Count != 0 {
+		t.Errorf("expected no jobs, got %d", response.Count)
+	}
+}
+
+func TestJobsHandler_InvalidLimit(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v1/jobs?limit=abc", nil)
+	w := httptest.NewRecorder()
+	JobsHandler(newMockJobStore())(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
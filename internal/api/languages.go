@@ -0,0 +1,47 @@
+package api
+
+import (
+	"slices"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// OrderedLanguages lists the requested target languages of a job in request order with their results,
+// so clients render languages consistently. Languages without a result are reported as described by unstartedResult.
+func OrderedLanguages(status *models.StatusResponse) []models.LanguageEntry {
+	entries := []models.LanguageEntry{}
+	if status.Request == nil {
+		return entries
+	}
+	for _, lang := range status.Request.TargetLanguages {
+		result, ok := status.Results[lang]
+		if !ok {
+			result = unstartedResult(status, lang)
+		}
+		entries = append(entries, models.LanguageEntry{Language: lang, LanguageResult: result})
+	}
+	return entries
+}
+
+// unstartedResult reports a language without a result: skipped when it matched the detected source language
+// (allLanguages), processing with its progress once started, pending until then, and failed with the job
+// when the job ended before reaching it
+func unstartedResult(status *models.StatusResponse, lang string) *models.LanguageResult {
+	direction := models.LanguageDirection(lang)
+	switch {
+	case slices.Contains(status.SkippedLanguages, lang):
+		return &models.LanguageResult{Status: models.StatusSkipped, Direction: direction}
+	case status.Status == models.StatusProcessing || status.Status == models.StatusIdle:
+		if progress, ok := status.LanguageProgress[lang]; ok {
+			return &models.LanguageResult{Status: models.StatusProcessing, Direction: direction, Progress: progress}
+		}
+		return &models.LanguageResult{Status: models.StatusPending, Direction: direction}
+	default:
+		result := &models.LanguageResult{Status: models.StatusFailed, Direction: direction, ErrorCode: status.ErrorCode}
+		// "error" holds the reason of a job that failed before any language started
+		if jobError, ok := status.Results["error"]; ok {
+			result.Error = jobError.Error
+		}
+		return result
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestOrderedLanguages(t *testing.T) {
+	request := &models.TranslateRequest{TargetLanguages: []string{"ru", "de", "ar", "en"}}
+	status := &models.StatusResponse{
+		Status:  models.StatusProcessing,
+		Request: request,
+		Results: map[string]*models.LanguageResult{
+			"ar": {Status: models.StatusCompleted, VideoURL: "https://example.com/ar.mp4"},
+		},
+		LanguageProgress: map[string]int{"de": 40},
+		SkippedLanguages: []string{"en"},
+	}
+
+	entries := OrderedLanguages(status)
+	want := []struct {
+		language string
+		status   models.TranslationStatus
+		progress int
+	}{
+		{"ru", models.StatusPending, 0},
+		{"de", models.StatusProcessing, 40},
+		{"ar", models.StatusCompleted, 0},
+		{"en", models.StatusSkipped, 0},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(entries))
+	}
+	for i, w := range want {
+		if entries[i].Language != w.language || entries[i].Status != w.status || entries[i].Progress != w.progress {
+			t.Errorf("entry %d: expected %s %s %d, got %s %s %d", i, w.language, w.status, w.progress,
+				entries[i].Language, entries[i].Status, entries[i].Progress)
+		}
+	}
+	if entries[2].LanguageResult != status.Results["ar"] {
+		t.Error("expected the completed entry to be the language result")
+	}
+	if entries[0].Direction != models.DirectionLTR {
+		t.Errorf("expected the pending entry to carry its direction, got %q", entries[0].Direction)
+	}
+}
+
+func TestOrderedLanguages_FailedJob(t *testing.T) {
+	status := &models.StatusResponse{
+		Status:    models.StatusFailed,
+		ErrorCode: models.ErrCodeNoSpeech,
+		Request:   &models.TranslateRequest{TargetLanguages: []string{"de"}},
+		Results: map[string]*models.LanguageResult{
+			"error": {Status: models.StatusFailed, Error: "no speech detected"},
+		},
+	}
+
+	entries := OrderedLanguages(status)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Status != models.StatusFailed || entries[0].Error != "no speech detected" || entries[0].ErrorCode != models.ErrCodeNoSpeech {
+		t.Errorf("expected language to fail with the job, got %+v", entries[0].LanguageResult)
+	}
+}
+
+func TestOrderedLanguages_JSON(t *testing.T) {
+	store := NewInMemoryJobStore(0)
+	store.SetStatus("job-1", &models.StatusResponse{
+		JobID:   "job-1",
+		Status:  models.StatusProcessing,
+		Request: &models.TranslateRequest{TargetLanguages: []string{"de"}},
+	})
+	status, _ := store.GetStatus("job-1")
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		t.Fatalf("failed to encode status: %v", err)
+	}
+	if !strings.Contains(string(data), `"languages":[{"language":"de","status":"pending","direction":"ltr"}]`) {
+		t.Errorf("expected a flat pending language entry, got %s", data)
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// MetricsHandler handles GET /metrics, exposing this instance's queue and job counts in the Prometheus text format
+func MetricsHandler(ops AdminOperations) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		stats := ops.collectQueueStats()
+		jobsByStatus := make(map[models.TranslationStatus]int)
+		for _, status := range ops.Store.ListStatuses() {
+			jobsByStatus[status.Status]++
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+
+		writeMetric(w, "video_processor_active_jobs", "Jobs in processing status", stats.ActiveJobs)
+		writeMetric(w, "video_processor_running_jobs", "Jobs with a live worker on this instance", stats.RunningJobs)
+		writeMetric(w, "video_processor_pending_languages", "Target languages of active jobs not yet finished", stats.PendingLanguages)
+
+		fmt.Fprintf(w, "# HELP video_processor_jobs Jobs in the store by status\n# TYPE video_processor_jobs gauge\n")
+		statuses := make([]string, 0, len(jobsByStatus))
+		for status := range jobsByStatus {
+			statuses = append(statuses, string(status))
+		}
+		sort.Strings(statuses)
+		for _, status := range statuses {
+			fmt.Fprintf(w, "video_processor_jobs{status=%q} %d\n", status, jobsByStatus[models.TranslationStatus(status)])
+		}
+
+		fmt.Fprintf(w, "# HELP video_processor_pool_in_use Tasks running in each worker pool\n# TYPE video_processor_pool_in_use gauge\n")
+		for _, pool := range stats.Pools {
+			fmt.Fprintf(w, "video_processor_pool_in_use{pool=%q} %d\n", pool.Name, pool.InUse)
+		}
+		fmt.Fprintf(w, "# HELP video_processor_pool_waiting Tasks queued for a free worker pool slot\n# TYPE video_processor_pool_waiting gauge\n")
+		for _, pool := range stats.Pools {
+			fmt.Fprintf(w, "video_processor_pool_waiting{pool=%q} %d\n", pool.Name, pool.Waiting)
+		}
+		fmt.Fprintf(w, "# HELP video_processor_pool_size Maximum concurrent tasks of each worker pool\n# TYPE video_processor_pool_size gauge\n")
+		for _, pool := range stats.Pools {
+			fmt.Fprintf(w, "video_processor_pool_size{pool=%q} %d\n", pool.Name, pool.Size)
+		}
+
+		if ops.TranslationMemoryStats != nil {
+			lookups, hits := ops.TranslationMemoryStats()
+			fmt.Fprintf(w, "# HELP video_processor_translation_memory_lookups_total Sentences looked up in the persistent translation memory\n# TYPE video_processor_translation_memory_lookups_total counter\nvideo_processor_translation_memory_lookups_total %d\n", lookups)
+			fmt.Fprintf(w, "# HELP video_processor_translation_memory_hits_total Sentences served from the persistent translation memory instead of a provider\n# TYPE video_processor_translation_memory_hits_total counter\nvideo_processor_translation_memory_hits_total %d\n", hits)
+		}
+	}
+}
+
+// writeMetric writes an unlabeled gauge with its help text
+func writeMetric(w io.Writer, name string, help string, value int) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", name, help, name, name, value)
+}
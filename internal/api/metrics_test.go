@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	ops, _, _ := newAdminTestOps()
+
+	w := httptest.NewRecorder()
+	MetricsHandler(ops)(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"video_processor_active_jobs 1\n",
+		"video_processor_pending_languages 2\n",
+		`video_processor_jobs{status="completed"} 1` + "\n",
+		`video_processor_pool_size{pool="ffmpeg"} 2` + "\n",
+		"# TYPE video_processor_running_jobs gauge\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "translation_memory") {
+		t.Errorf("expected no translation memory metrics while it is disabled, got:\n%s", body)
+	}
+
+	ops.TranslationMemoryStats = func() (int64, int64) { return 40, 30 }
+	w = httptest.NewRecorder()
+	MetricsHandler(ops)(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, want := range []string{
+		"video_processor_translation_memory_lookups_total 40\n",
+		"video_processor_translation_memory_hits_total 30\n",
+		"# TYPE video_processor_translation_memory_hits_total counter\n",
+	} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import "github.com/sinouw/multilingual-video-processor/pkg/models"
+
+// Share of the overall progress taken by the job-wide stages before languages are processed;
+// the languages share the rest
+const jobStagesProgress = 30
+
+// stageProgress is the overall progress reached when a job-wide stage starts
+var stageProgress = map[string]int{
+	models.StageCheckingSource:    0,
+	models.StageWaitingForDisk:    0,
+	models.StageDownloading:       2,
+	models.StageSplittingChapters: 6,
+	models.StageExtractingAudio:   8,
+	models.StageLoadingSubtitles:  10,
+	models.StageTranscribing:      12,
+	models.StagePunctuating:       22,
+	models.StageSummarizing:       24,
+	models.StageSeparatingAudio:   26,
+	models.StageLanguages:         jobStagesProgress,
+}
+
+// JobProgress returns the overall completion percentage (0-100) of a job
+// Download and transcription stages count for the first 30%, the target languages for the rest,
+// each language by its own progress. Finished jobs are at 100 and processing jobs stay below it.
+func JobProgress(status *models.StatusResponse) int {
+	switch status.Status {
+	case models.StatusProcessing:
+	case models.StatusIdle:
+		return 0
+	default:
+		return 100
+	}
+
+	targets := status.TargetLanguages()
+	if status.Stage != models.StageLanguages && status.Stage != models.StageFinalizing || len(targets) == 0 {
+		return stageProgress[status.Stage]
+	}
+
+	total := 0
+	for _, lang := range targets {
+		if result, ok := status.Results[lang]; ok && result.IsFinished() {
+			total += 100
+		} else {
+			total += status.LanguageProgress[lang]
+		}
+	}
+	progress := jobStagesProgress + (100-jobStagesProgress)*total/(100*len(targets))
+	return min(progress, 99)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestJobProgress(t *testing.T) {
+	request := &models.TranslateRequest{TargetLanguages: []string{"de", "fr"}}
+	tests := []struct {
+		name   string
+		status *models.StatusResponse
+		want   int
+	}{
+		{
+			name:   "idle",
+			status: &models.StatusResponse{Status: models.StatusIdle},
+			want:   0,
+		},
+		{
+			name:   "transcribing",
+			status: &models.StatusResponse{Status: models.StatusProcessing, Stage: models.StageTranscribing, Request: request},
+			want:   12,
+		},
+		{
+			name:   "languages started",
+			status: &models.StatusResponse{Status: models.StatusProcessing, Stage: models.StageLanguages, Request: request},
+			want:   30,
+		},
+		{
+			name: "one language done, one halfway",
+			status: &models.StatusResponse{
+				Status:           models.StatusProcessing,
+				Stage:            models.StageLanguages,
+				Request:          request,
+				Results:          map[string]*models.LanguageResult{"de": {Status: models.StatusCompleted}},
+				LanguageProgress: map[string]int{"fr": 40},
+			},
+			want: 79,
+		},
+		{
+			name: "all languages done while finalizing",
+			status: &models.StatusResponse{
+				Status:  models.StatusProcessing,
+				Stage:   models.StageFinalizing,
+				Request: request,
+				Results: map[string]*models.LanguageResult{
+					"de": {Status: models.StatusCompleted},
+					"fr": {Status: models.StatusFailed},
+				},
+			},
+			want: 99,
+		},
+		{
+			name:   "failed",
+			status: &models.StatusResponse{Status: models.StatusFailed, Stage: models.StageDownloading},
+			want:   100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JobProgress(tt.status); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,20 +1,47 @@
 package api
 
 import (
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 )
 
+// Rate limit scopes: endpoints sharing a scope share a bucket per client
+const (
+	RateLimitScopeSubmit = "submit" // Job submissions and exports
+	RateLimitScopeStatus = "status" // Status polling and job listing
+)
+
 // RateLimiter implements a simple in-memory rate limiter using token bucket algorithm
+// Each scope can have its own limit; scopes without one use the default requestsPerMinute.
+// Clients on a tier (e.g. API keys of a paid plan) use the tier's limits for the scopes it sets.
 type RateLimiter struct {
 	requestsPerMinute int
-	buckets           sync.Map // map[string]*tokenBucket
+	scopeLimits       map[string]int
+	tierLimits        map[string]map[string]int // tier -> scope -> requests per minute
+	buckets           sync.Map                  // map[bucketKey]*tokenBucket
 	cleanupInterval   time.Duration
 	stopCleanup       chan struct{}
 	mu                sync.Mutex
 }
 
+// bucketKey identifies the bucket of a client within a scope
+type bucketKey struct {
+	scope      string
+	identifier string
+}
+
+// RateLimitState describes a client's bucket after a rate limit check
+type RateLimitState struct {
+	Allowed    bool
+	Limit      int           // Requests per minute for the scope
+	Remaining  int           // Requests left in the bucket
+	Reset      time.Duration // Time until the bucket is full again
+	RetryAfter time.Duration // Time until the next request is allowed; zero when allowed
+}
+
 // tokenBucket represents a token bucket for rate limiting
 type tokenBucket struct {
 	tokens     int
@@ -36,15 +63,55 @@ func NewRateLimiter(requestsPerMinute int) *RateLimiter {
 	return rl
 }
 
+// SetScopeLimit sets the requests per minute for a scope (not safe to call concurrently with Take)
+func (rl *RateLimiter) SetScopeLimit(scope string, requestsPerMinute int) {
+	if rl.scopeLimits == nil {
+		rl.scopeLimits = make(map[string]int)
+	}
+	rl.scopeLimits[scope] = requestsPerMinute
+}
+
+// SetTierLimit sets the requests per minute of a scope for clients on a tier (not safe to call concurrently with Take)
+func (rl *RateLimiter) SetTierLimit(tier string, scope string, requestsPerMinute int) {
+	if rl.tierLimits == nil {
+		rl.tierLimits = make(map[string]map[string]int)
+	}
+	if rl.tierLimits[tier] == nil {
+		rl.tierLimits[tier] = make(map[string]int)
+	}
+	rl.tierLimits[tier][scope] = requestsPerMinute
+}
+
 // Allow checks if a request should be allowed based on rate limiting
 // Returns true if allowed, false if rate limited
 func (rl *RateLimiter) Allow(identifier string) bool {
+	return rl.Take("", identifier).Allowed
+}
+
+// Take consumes a request from the client's bucket in a scope and returns the resulting state
+func (rl *RateLimiter) Take(scope string, identifier string) RateLimitState {
+	return rl.check(scope, "", identifier, true)
+}
+
+// TakeTier is Take for a client on a tier; an empty or unknown tier uses the scope's default limit
+func (rl *RateLimiter) TakeTier(scope string, tier string, identifier string) RateLimitState {
+	return rl.check(scope, tier, identifier, true)
+}
+
+// State returns the client's current bucket state in a scope without consuming a request
+func (rl *RateLimiter) State(scope string, identifier string) RateLimitState {
+	return rl.check(scope, "", identifier, false)
+}
+
+// check refills the bucket and optionally consumes a token
+func (rl *RateLimiter) check(scope string, tier string, identifier string, consume bool) RateLimitState {
 	now := time.Now()
-	tokensPerSecond := float64(rl.requestsPerMinute) / 60.0
+	limit := rl.limit(scope, tier)
+	tokensPerSecond := float64(limit) / 60.0
 
 	// Get or create bucket for this identifier
-	value, _ := rl.buckets.LoadOrStore(identifier, &tokenBucket{
-		tokens:     rl.requestsPerMinute,
+	value, _ := rl.buckets.LoadOrStore(bucketKey{scope: scope, identifier: identifier}, &tokenBucket{
+		tokens:     limit,
 		lastRefill: now,
 	})
 
@@ -59,20 +126,65 @@ func (rl *RateLimiter) Allow(identifier string) bool {
 	tokensToAdd := int(elapsed * tokensPerSecond)
 	if tokensToAdd > 0 {
 		bucket.tokens += tokensToAdd
-		// Cap tokens at requestsPerMinute
-		if bucket.tokens > rl.requestsPerMinute {
-			bucket.tokens = rl.requestsPerMinute
+		// Cap tokens at the limit
+		if bucket.tokens > limit {
+			bucket.tokens = limit
 		}
 		bucket.lastRefill = now
 	}
 
+	state := RateLimitState{Limit: limit}
+
 	// Check if we have tokens available
 	if bucket.tokens > 0 {
-		bucket.tokens--
-		return true
+		state.Allowed = true
+		if consume {
+			bucket.tokens--
+		}
 	}
 
-	return false
+	state.Remaining = bucket.tokens
+	if tokensPerSecond > 0 {
+		// Tokens arrive one per 1/tokensPerSecond seconds, counted from the last refill
+		tokenInterval := time.Duration(float64(time.Second) / tokensPerSecond)
+		sinceRefill := now.Sub(bucket.lastRefill)
+		if missing := limit - bucket.tokens; missing > 0 {
+			state.Reset = time.Duration(missing)*tokenInterval - sinceRefill
+		}
+		if !state.Allowed {
+			state.RetryAfter = tokenInterval - sinceRefill
+		}
+	}
+	return state
+}
+
+// limit returns the requests per minute for a scope and tier
+func (rl *RateLimiter) limit(scope string, tier string) int {
+	if limit, ok := rl.tierLimits[tier][scope]; ok && tier != "" {
+		return limit
+	}
+	if limit, ok := rl.scopeLimits[scope]; ok {
+		return limit
+	}
+	return rl.requestsPerMinute
+}
+
+// WriteRateLimitHeaders sets X-RateLimit-Limit/Remaining/Reset, plus Retry-After when the request was rejected
+// Reset and Retry-After are in seconds from now, rounded up.
+func WriteRateLimitHeaders(w http.ResponseWriter, state RateLimitState) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(state.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(state.Remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(state.Reset)))
+	if !state.Allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(max(ceilSeconds(state.RetryAfter), 1)))
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
 }
 
 // startCleanup periodically removes stale buckets to prevent memory leaks
@@ -106,11 +218,31 @@ func (rl *RateLimiter) cleanup() {
 	})
 }
 
+// Flush removes the buckets of identifier in every scope, or every bucket when identifier is empty
+// Returns the number of buckets removed
+func (rl *RateLimiter) Flush(identifier string) int {
+	removed := 0
+	rl.buckets.Range(func(key, value interface{}) bool {
+		if identifier == "" || key.(bucketKey).identifier == identifier {
+			rl.buckets.Delete(key)
+			removed++
+		}
+		return true
+	})
+	return removed
+}
+
 // Stop stops the cleanup goroutine
 func (rl *RateLimiter) Stop() {
 	close(rl.stopCleanup)
 }
 
+// RateLimitKeyIdentifier returns the rate limit identifier of an API key owner, distinct from any IP address
+// Pass it to the admin rate limit flush to reset a key's buckets.
+func RateLimitKeyIdentifier(owner string) string {
+	return "key:" + owner
+}
+
 // GetClientIP extracts the client IP address from the request (exported for use in main handler)
 func GetClientIP(r *http.Request) string {
 	// Check X-Forwarded-For header (used by proxies/load balancers)
@@ -189,4 +321,3 @@ func indexLast(s string, substr string) int {
 	}
 	return -1
 }
-
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_TakeReportsState(t *testing.T) {
+	// Constructed directly to avoid the background cleanup goroutine
+	rl := &RateLimiter{requestsPerMinute: 2}
+
+	first := rl.Take(RateLimitScopeSubmit, "1.2.3.4")
+	if !first.Allowed || first.Limit != 2 || first.Remaining != 1 {
+		t.Errorf("expected allowed with 1 remaining of 2, got %+v", first)
+	}
+	if first.Reset <= 0 || first.Reset > 30*time.Second {
+		t.Errorf("expected reset within one token interval (30s), got %v", first.Reset)
+	}
+
+	rl.Take(RateLimitScopeSubmit, "1.2.3.4")
+	rejected := rl.Take(RateLimitScopeSubmit, "1.2.3.4")
+	if rejected.Allowed || rejected.Remaining != 0 {
+		t.Errorf("expected rejection with nothing remaining, got %+v", rejected)
+	}
+	if rejected.RetryAfter <= 0 || rejected.RetryAfter > 30*time.Second {
+		t.Errorf("expected retry after at most 30s, got %v", rejected.RetryAfter)
+	}
+
+	// State does not consume requests
+	if state := rl.State(RateLimitScopeSubmit, "5.6.7.8"); !state.Allowed || state.Remaining != 2 {
+		t.Errorf("expected full bucket for a new client, got %+v", state)
+	}
+	if state := rl.State(RateLimitScopeSubmit, "5.6.7.8"); state.Remaining != 2 {
+		t.Errorf("expected State not to consume, got %d remaining", state.Remaining)
+	}
+}
+
+func TestRateLimiter_ScopeLimits(t *testing.T) {
+	rl := &RateLimiter{requestsPerMinute: 1}
+	rl.SetScopeLimit(RateLimitScopeStatus, 5)
+
+	if !rl.Take(RateLimitScopeSubmit, "1.2.3.4").Allowed {
+		t.Fatal("expected first submission to be allowed")
+	}
+	if rl.Take(RateLimitScopeSubmit, "1.2.3.4").Allowed {
+		t.Error("expected second submission to be rejected")
+	}
+
+	// Status polling has its own, larger bucket
+	for i := 0; i < 5; i++ {
+		if !rl.Take(RateLimitScopeStatus, "1.2.3.4").Allowed {
+			t.Fatalf("expected status request %d to be allowed", i+1)
+		}
+	}
+	if rl.Take(RateLimitScopeStatus, "1.2.3.4").Allowed {
+		t.Error("expected sixth status request to be rejected")
+	}
+
+	// Flushing a client clears every scope
+	if removed := rl.Flush("1.2.3.4"); removed != 2 {
+		t.Errorf("expected 2 buckets removed, got %d", removed)
+	}
+}
+
+func TestRateLimiter_TierLimits(t *testing.T) {
+	rl := &RateLimiter{requestsPerMinute: 1}
+	rl.SetTierLimit("pro", RateLimitScopeSubmit, 3)
+
+	// Keys on the tier get its limit, in their own bucket
+	for i := 0; i < 3; i++ {
+		state := rl.TakeTier(RateLimitScopeSubmit, "pro", "key:acme")
+		if !state.Allowed || state.Limit != 3 {
+			t.Fatalf("expected pro request %d to be allowed with limit 3, got %+v", i+1, state)
+		}
+	}
+	if rl.TakeTier(RateLimitScopeSubmit, "pro", "key:acme").Allowed {
+		t.Error("expected fourth pro request to be rejected")
+	}
+
+	// Other clients and scopes the tier does not set keep the default limit
+	if state := rl.TakeTier(RateLimitScopeSubmit, "", "key:beta"); state.Limit != 1 {
+		t.Errorf("expected default limit 1, got %d", state.Limit)
+	}
+	if state := rl.TakeTier(RateLimitScopeStatus, "pro", "key:acme"); state.Limit != 1 {
+		t.Errorf("expected default status limit 1, got %d", state.Limit)
+	}
+	if state := rl.TakeTier(RateLimitScopeSubmit, "unknown", "key:gamma"); state.Limit != 1 {
+		t.Errorf("expected default limit for an unknown tier, got %d", state.Limit)
+	}
+}
+
+func TestWriteRateLimitHeaders(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteRateLimitHeaders(w, RateLimitState{Allowed: false, Limit: 60, Remaining: 0, Reset: 59500 * time.Millisecond, RetryAfter: 200 * time.Millisecond})
+
+	want := map[string]string{
+		"X-RateLimit-Limit":     "60",
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     "60",
+		"Retry-After":           "1",
+	}
+	for header, value := range want {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("expected %s %q, got %q", header, value, got)
+		}
+	}
+
+	allowed := httptest.NewRecorder()
+	WriteRateLimitHeaders(allowed, RateLimitState{Allowed: true, Limit: 60, Remaining: 59})
+	if allowed.Header().Get("Retry-After") != "" {
+		t.Error("expected no Retry-After header when allowed")
+	}
+}
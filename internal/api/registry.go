@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"sync"
+)
+
+// JobRegistry tracks the cancel functions of jobs being processed on this instance
+// so running work can be stopped from the admin API. It also counts the jobs admitted
+// to the instance, so a job cap is enforced atomically.
+type JobRegistry struct {
+	mu       sync.Mutex
+	cancels  map[string]context.CancelFunc
+	admitted int
+}
+
+// NewJobRegistry creates an empty job registry
+func NewJobRegistry() *JobRegistry {
+	return &JobRegistry{cancels: make(map[string]context.CancelFunc)}
+}
+
+// Register records the cancel function of a job's processing context
+func (r *JobRegistry) Register(jobID string, cancel context.CancelFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cancels[jobID] = cancel
+}
+
+// Finish cancels the job's context and removes it; called when processing ends
+func (r *JobRegistry) Finish(jobID string) {
+	r.mu.Lock()
+	cancel, exists := r.cancels[jobID]
+	delete(r.cancels, jobID)
+	r.mu.Unlock()
+
+	if exists {
+		cancel()
+	}
+}
+
+// Reserve admits one more job if fewer than limit jobs are admitted; a limit of 0 admits every job
+// The returned function gives the slot back once the job finished (or was not started); calling it again has no effect.
+func (r *JobRegistry) Reserve(limit int) (release func(), ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if limit > 0 && r.admitted >= limit {
+		return nil, false
+	}
+	r.admitted++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			r.admitted--
+			r.mu.Unlock()
+		})
+	}, true
+}
+
+// Admitted returns the number of jobs holding a slot reserved with Reserve
+func (r *JobRegistry) Admitted() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.admitted
+}
+
+// Cancel stops a running job, returning false if it is not running on this instance
+func (r *JobRegistry) Cancel(jobID string) bool {
+	r.mu.Lock()
+	cancel, exists := r.cancels[jobID]
+	r.mu.Unlock()
+
+	if exists {
+		cancel()
+	}
+	return exists
+}
+
+// IsRunning reports whether the job is being processed on this instance
+func (r *JobRegistry) IsRunning(jobID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, exists := r.cancels[jobID]
+	return exists
+}
+
+// Len returns the number of jobs being processed on this instance
+func (r *JobRegistry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.cancels)
+}
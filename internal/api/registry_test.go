@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestJobRegistry(t *testing.T) {
+	registry := NewJobRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+	registry.Register("job-1", cancel)
+
+	if !registry.IsRunning("job-1") || registry.Len() != 1 {
+		t.Fatalf("expected job-1 to be running")
+	}
+
+	if !registry.Cancel("job-1") {
+		t.Error("expected Cancel to find the running job")
+	}
+	if ctx.Err() == nil {
+		t.Error("expected job context to be cancelled")
+	}
+	if !registry.IsRunning("job-1") {
+		t.Error("expected cancelled job to stay registered until it finishes")
+	}
+
+	registry.Finish("job-1")
+	if registry.IsRunning("job-1") || registry.Len() != 0 {
+		t.Error("expected finished job to be removed")
+	}
+	if registry.Cancel("job-1") {
+		t.Error("expected Cancel to report a job that is not running")
+	}
+}
+
+func TestJobRegistry_Reserve(t *testing.T) {
+	registry := NewJobRegistry()
+
+	first, ok := registry.Reserve(2)
+	if !ok {
+		t.Fatal("expected the first slot to be reserved")
+	}
+	if _, ok := registry.Reserve(2); !ok {
+		t.Fatal("expected the second slot to be reserved")
+	}
+	if _, ok := registry.Reserve(2); ok {
+		t.Error("expected a third job to be refused at a limit of 2")
+	}
+
+	// Releasing twice only gives one slot back
+	first()
+	first()
+	if registry.Admitted() != 1 {
+		t.Errorf("expected 1 admitted job, got %d", registry.Admitted())
+	}
+	if _, ok := registry.Reserve(2); !ok {
+		t.Error("expected the released slot to be reserved again")
+	}
+
+	// A limit of 0 admits every job
+	for i := 0; i < 10; i++ {
+		if _, ok := registry.Reserve(0); !ok {
+			t.Fatal("expected no limit with 0")
+		}
+	}
+}
+
+func TestJobRegistry_ReserveConcurrent(t *testing.T) {
+	registry := NewJobRegistry()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := registry.Reserve(5); ok {
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if reserved != 5 {
+		t.Errorf("expected exactly 5 concurrent reservations, got %d", reserved)
+	}
+}
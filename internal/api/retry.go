@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// RetryFunc re-runs the given target languages of a job from its checkpoint
+// release gives back the job's admission and must be called once the languages were processed.
+// It is called after the response was sent and may process the languages before returning.
+type RetryFunc func(jobID string, languages []string, release func())
+
+// RetryHandler handles POST /v1/jobs/{id}/retry
+// Only languages that failed are re-run; the job must have a checkpoint and must not be processing.
+// The job is admitted on the instance like a new submission before it is claimed, and is claimed only while
+// its owner runs fewer jobs than activeLimit allows.
+func RetryHandler(store JobStatusStore, admit AdmitFunc, activeLimit ActiveJobLimitFunc, retry RetryFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Extract job ID from path
+		jobID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/jobs/"), "/retry")
+		if jobID == "" || strings.Contains(jobID, "/") {
+			ErrorResponse(w, http.StatusBadRequest, "job ID is required", "")
+			return
+		}
+		jobID = resolveJobID(r, store, jobID)
+
+		slog.Info("Retry request", "jobID", jobID)
+
+		current, err := store.GetStatus(jobID)
+		if err != nil || !canAccessJob(r, current) {
+			ErrorResponse(w, http.StatusNotFound, "job not found", jobID)
+			return
+		}
+		if _, conflict := retryableLanguages(current); conflict != "" {
+			ErrorResponse(w, http.StatusConflict, conflict, jobID)
+			return
+		}
+
+		// A refused admission leaves the job untouched, so the client can retry it later
+		release, admissionErr := admit(r, current)
+		if admissionErr != nil {
+			WriteAdmissionError(w, admissionErr, jobID)
+			return
+		}
+
+		// Check and claim the job in a single update so concurrent retries cannot both start
+		var languages []string
+		var conflict string
+		maxActive := activeLimit(current.Owner)
+		err = store.UpdateStatusWithinLimit(jobID, maxActive, func(status *models.StatusResponse) {
+			if languages, conflict = retryableLanguages(status); conflict == "" {
+				claimLanguages(status, languages)
+			}
+		})
+		if errors.Is(err, ErrActiveJobLimit) {
+			release()
+			WriteAdmissionError(w, ActiveJobLimitError(maxActive), jobID)
+			return
+		}
+		if err != nil {
+			release()
+			ErrorResponse(w, http.StatusNotFound, "job not found", jobID)
+			return
+		}
+		if conflict != "" {
+			release()
+			ErrorResponse(w, http.StatusConflict, conflict, jobID)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(models.RetryResponse{
+			JobID:     jobID,
+			Status:    models.StatusProcessing,
+			Languages: languages,
+		})
+		flushResponse(w)
+
+		retry(jobID, languages, release)
+	}
+}
+
+// flushResponse sends the response written so far, before work that may run inside the request
+func flushResponse(w http.ResponseWriter) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// retryableLanguages returns the failed languages of a job that can be retried, or why it cannot be
+func retryableLanguages(status *models.StatusResponse) ([]string, string) {
+	switch {
+	case status.Status == models.StatusProcessing:
+		return nil, "job is still processing"
+	case status.Checkpoint == nil:
+		return nil, "job failed before transcription and cannot be retried; submit it again"
+	}
+
+	languages := failedLanguages(status)
+	if len(languages) == 0 {
+		return nil, "job has no failed languages to retry"
+	}
+	return languages, ""
+}
+
+// claimLanguages marks a job and the given languages as processing again
+func claimLanguages(status *models.StatusResponse, languages []string) {
+	status.Status = models.StatusProcessing
+	status.ErrorCode = ""
+	status.NextRetryAt = nil
+	if status.Results == nil {
+		status.Results = make(map[string]*models.LanguageResult)
+	}
+	for _, lang := range languages {
+		status.Results[lang] = &models.LanguageResult{Status: models.StatusProcessing, Direction: models.LanguageDirection(lang)}
+	}
+	status.UpdatedAt = time.Now()
+	status.RecordEvent(models.EventJobRetried, "", strings.Join(languages, ","))
+}
+
+// failedLanguages returns the requested target languages whose result failed or is missing, sorted
+func failedLanguages(status *models.StatusResponse) []string {
+	var targets []string
+	if status.Request != nil {
+		targets = status.TargetLanguages()
+	} else {
+		for lang := range status.Results {
+			targets = append(targets, lang)
+		}
+	}
+
+	var failed []string
+	for _, lang := range targets {
+		result, exists := status.Results[lang]
+		if !exists || result.Status == models.StatusFailed {
+			failed = append(failed, lang)
+		}
+	}
+	sort.Strings(failed)
+	return failed
+}
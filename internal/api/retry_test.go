@@ -0,0 +1,264 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func newRetryableJob(jobID string) *models.StatusResponse {
+	return &models.StatusResponse{
+		JobID:  jobID,
+		Status: models.StatusFailed,
+		Results: map[string]*models.LanguageResult{
+			"de": {Status: models.StatusCompleted, VideoURL: "https://example.com/de.mp4"},
+			"ar": {Status: models.StatusFailed, Error: "TTS generation failed"},
+		},
+		Request:    &models.TranslateRequest{TargetLanguages: []string{"de", "ar", "ru"}},
+		Checkpoint: &models.JobCheckpoint{Transcript: "Hello", SourceLanguage: "en"},
+	}
+}
+
+// admitAll admits every job without reserving anything
+func admitAll(r *http.Request, status *models.StatusResponse) (func(), *AdmissionError) {
+	return func() {}, nil
+}
+
+// unlimitedJobs lets every owner process any number of jobs
+func unlimitedJobs(owner string) int {
+	return 0
+}
+
+func TestRetryHandler_RetriesFailedLanguages(t *testing.T) {
+	store := newMockJobStore()
+	store.SetStatus("job-1", newRetryableJob("job-1"))
+
+	var retried []string
+	handler := RetryHandler(store, admitAll, unlimitedJobs, func(jobID string, languages []string, release func()) {
+		retried = languages
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/jobs/job-1/retry", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, w.Code)
+	}
+
+	want := []string{"ar", "ru"}
+	if !reflect.DeepEqual(retried, want) {
+		t.Errorf("expected retried languages %v, got %v", want, retried)
+	}
+
+	var response models.RetryResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !reflect.DeepEqual(response.Languages, want) {
+		t.Errorf("expected response languages %v, got %v", want, response.Languages)
+	}
+
+	status, _ := store.GetStatus("job-1")
+	if status.Status != models.StatusProcessing {
+		t.Errorf("expected job status '%s', got '%s'", models.StatusProcessing, status.Status)
+	}
+	if status.Results["de"].Status != models.StatusCompleted {
+		t.Errorf("expected completed language to be untouched, got '%s'", status.Results["de"].Status)
+	}
+	if ar := status.Results["ar"]; ar.Status != models.StatusProcessing || ar.Error != "" || ar.Direction != models.DirectionRTL {
+		t.Errorf("expected failed language reset to processing right to left, got %+v", ar)
+	}
+}
+
+func TestFailedLanguages_ExcludesSkipped(t *testing.T) {
+	status := newRetryableJob("job-1")
+	status.SkippedLanguages = []string{"ru"}
+
+	want := []string{"ar"}
+	if got := failedLanguages(status); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected failed languages %v, got %v", want, got)
+	}
+}
+
+func TestRetryHandler_Conflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(status *models.StatusResponse)
+	}{
+		{"still processing", func(status *models.StatusResponse) { status.Status = models.StatusProcessing }},
+		{"no checkpoint", func(status *models.StatusResponse) { status.Checkpoint = nil }},
+		{"nothing failed", func(status *models.StatusResponse) {
+			status.Status = models.StatusCompleted
+			status.Request.TargetLanguages = []string{"de"}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockJobStore()
+			job := newRetryableJob("job-1")
+			tt.modify(job)
+			store.SetStatus("job-1", job)
+
+			called := false
+			handler := RetryHandler(store, admitAll, unlimitedJobs, func(jobID string, languages []string, release func()) { called = true })
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/jobs/job-1/retry", nil)
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			if w.Code != http.StatusConflict {
+				t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+			}
+			if called {
+				t.Error("expected retry not to start")
+			}
+		})
+	}
+}
+
+func TestRetryHandler_OtherOwner(t *testing.T) {
+	store := newMockJobStore()
+	job := newRetryableJob("job-1")
+	job.Owner = "acme"
+	store.SetStatus("job-1", job)
+
+	handler := RetryHandler(store, admitAll, unlimitedJobs, func(jobID string, languages []string, release func()) {
+		t.Error("expected retry not to start")
+	})
+
+	req := WithPrincipal(httptest.NewRequest(http.MethodPost, "/v1/jobs/job-1/retry", nil), &Principal{Owner: "globex"})
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if job.Status != models.StatusFailed {
+		t.Errorf("expected job to be untouched, got status '%s'", job.Status)
+	}
+}
+
+func TestRetryHandler_NotFound(t *testing.T) {
+	handler := RetryHandler(newMockJobStore(), admitAll, unlimitedJobs, func(jobID string, languages []string, release func()) {})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/jobs/missing/retry", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestRetryHandler_MethodNotAllowed(t *testing.T) {
+	handler := RetryHandler(newMockJobStore(), admitAll, unlimitedJobs, func(jobID string, languages []string, release func()) {})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/jobs/job-1/retry", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+}
+
+func TestRetryHandler_AdmissionRefused(t *testing.T) {
+	store := newMockJobStore()
+	job := newRetryableJob("job-1")
+	store.SetStatus("job-1", job)
+
+	refuse := func(r *http.Request, status *models.StatusResponse) (func(), *AdmissionError) {
+		return nil, &AdmissionError{StatusCode: http.StatusServiceUnavailable, Code: "instance_at_capacity", Message: "busy", RetryAfter: 30 * time.Second}
+	}
+	handler := RetryHandler(store, refuse, unlimitedJobs, func(jobID string, languages []string, release func()) {
+		t.Error("expected retry not to start")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/jobs/job-1/retry", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "30" {
+		t.Errorf("expected 503 with Retry-After 30, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	if job.Status != models.StatusFailed {
+		t.Errorf("expected job to stay unclaimed, got status '%s'", job.Status)
+	}
+}
+
+func TestRetryHandler_ActiveJobLimit(t *testing.T) {
+	store := newMockJobStore()
+	job := newRetryableJob("job-1")
+	job.Owner = "acme"
+	store.SetStatus("job-1", job)
+	store.SetStatus("job-2", &models.StatusResponse{JobID: "job-2", Owner: "acme", Status: models.StatusProcessing})
+
+	released := 0
+	admit := func(r *http.Request, status *models.StatusResponse) (func(), *AdmissionError) {
+		return func() { released++ }, nil
+	}
+	limit := func(owner string) int { return 1 }
+	handler := RetryHandler(store, admit, limit, func(jobID string, languages []string, release func()) {
+		t.Error("expected retry not to start")
+	})
+
+	req := WithPrincipal(httptest.NewRequest(http.MethodPost, "/v1/jobs/job-1/retry", nil), &Principal{Owner: "acme"})
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	var response models.ErrorResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	if w.Code != http.StatusTooManyRequests || response.Code != "too_many_concurrent_jobs" {
+		t.Errorf("expected 429 too_many_concurrent_jobs, got %d %q", w.Code, response.Code)
+	}
+	if job.Status != models.StatusFailed || released != 1 {
+		t.Errorf("expected the job unclaimed and its admission released, got status '%s' and %d releases", job.Status, released)
+	}
+}
+
+func TestRetryHandler_PassesAdmissionToRetry(t *testing.T) {
+	store := newMockJobStore()
+	store.SetStatus("job-1", newRetryableJob("job-1"))
+
+	released := 0
+	admit := func(r *http.Request, status *models.StatusResponse) (func(), *AdmissionError) {
+		return func() { released++ }, nil
+	}
+	handler := RetryHandler(store, admit, unlimitedJobs, func(jobID string, languages []string, release func()) {
+		release()
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/jobs/job-1/retry", nil)
+	handler(httptest.NewRecorder(), req)
+	if released != 1 {
+		t.Errorf("expected the admission released once by the retry, got %d", released)
+	}
+
+	// The job is processing now, so a second retry is a conflict and takes no admission
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/jobs/job-1/retry", nil))
+	if released != 1 {
+		t.Errorf("expected no admission for a conflicting retry, got %d releases", released)
+	}
+}
+
+func TestRetryHandler_RespondsBeforeRetryRuns(t *testing.T) {
+	store := newMockJobStore()
+	store.SetStatus("job-1", newRetryableJob("job-1"))
+
+	// Without CPU always allocated the retry runs inside the request, so the 202 must already be flushed
+	w := httptest.NewRecorder()
+	handler := RetryHandler(store, admitAll, unlimitedJobs, func(jobID string, languages []string, release func()) {
+		if !w.Flushed || w.Code != http.StatusAccepted {
+			t.Errorf("expected the 202 flushed before the retry runs, got code %d (flushed %v)", w.Code, w.Flushed)
+		}
+	})
+
+	handler(w, httptest.NewRequest(http.MethodPost, "/v1/jobs/job-1/retry", nil))
+}
@@ -2,10 +2,14 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/sinouw/multilingual-video-processor/pkg/models"
 )
@@ -15,6 +19,8 @@ type JobStatusStore interface {
 	GetStatus(jobID string) (*models.StatusResponse, error)
 	SetStatus(jobID string, status *models.StatusResponse)
 	UpdateStatusSafely(jobID string, updater func(*models.StatusResponse)) error
+	UpdateStatusWithinLimit(jobID string, maxActive int, updater func(*models.StatusResponse)) error
+	ListStatuses() []*models.StatusResponse
 }
 
 // StatusHandler handles job status requests
@@ -31,6 +37,7 @@ func StatusHandler(store JobStatusStore) http.HandlerFunc {
 			ErrorResponse(w, http.StatusBadRequest, "job ID is required", "")
 			return
 		}
+		jobID = resolveJobID(r, store, jobID)
 
 		slog.Info("Status request", "jobID", jobID)
 
@@ -41,6 +48,12 @@ func StatusHandler(store JobStatusStore) http.HandlerFunc {
 			return
 		}
 
+		// Jobs of other owners are reported as missing so IDs cannot be probed
+		if !canAccessJob(r, status) {
+			ErrorResponse(w, http.StatusNotFound, "job not found", jobID)
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(status)
@@ -50,15 +63,29 @@ func StatusHandler(store JobStatusStore) http.HandlerFunc {
 // In-memory job store (for single-instance deployments)
 // In production, use a persistent store like Redis, Firestore, or Cloud SQL
 type InMemoryJobStore struct {
-	mu     sync.RWMutex
-	jobs   map[string]*jobEntry
-	jobTTL time.Duration
+	mu       sync.RWMutex
+	jobs     map[string]*jobEntry
+	jobTTL   time.Duration
+	onExpire func(jobID string, status *models.StatusResponse)
+
+	// Processing jobs not updated for stallAfter are failed by the reaper and passed to onStall
+	stallAfter time.Duration
+	onStall    func(jobID string, status *models.StatusResponse)
+
+	// maxJobs caps the stored jobs by evicting the least recently used finished ones (zero for no cap)
+	maxJobs int
+	// compactFinished drops translated texts from finished jobs whose text was uploaded
+	compactFinished bool
+	// textOffloadThreshold drops uploaded texts longer than this many characters (zero keeps them)
+	textOffloadThreshold int
 }
 
 // jobEntry wraps a job status with metadata
 type jobEntry struct {
 	status    *models.StatusResponse
 	createdAt time.Time
+	// lastAccess is when the job was last stored, read or updated (UnixNano), for LRU eviction
+	lastAccess atomic.Int64
 }
 
 // NewInMemoryJobStore creates a new in-memory job store
@@ -72,10 +99,60 @@ func NewInMemoryJobStore(jobTTL time.Duration) *InMemoryJobStore {
 	return store
 }
 
+// SetExpireHook registers a function called for each job removed by CleanupExpiredJobs
+// Used to release resources held by a job, such as retry checkpoints
+func (s *InMemoryJobStore) SetExpireHook(hook func(jobID string, status *models.StatusResponse)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onExpire = hook
+}
+
+// SetMaxJobs caps the number of stored jobs, so a burst of jobs cannot exhaust memory before they expire
+// Past the cap, the least recently used finished jobs are evicted (and passed to the expire hook); processing
+// jobs and jobs with a scheduled retry are never evicted, so the store may exceed the cap while they run. A zero maxJobs removes the cap.
+func (s *InMemoryJobStore) SetMaxJobs(maxJobs int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxJobs = maxJobs
+	s.evictLocked("")
+}
+
+// SetCompactFinished drops the translated text of each language from jobs once they finish, keeping its URL
+// Texts without an uploaded copy are kept. Statuses hold the full translations, the bulk of a job's memory.
+func (s *InMemoryJobStore) SetCompactFinished(compact bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.compactFinished = compact
+}
+
+// SetTextOffloadThreshold keeps texts longer than chars characters only at their uploaded URL: the translated
+// text of a language as soon as it is recorded, and the transcript, translations and subtitle cues of the job
+// once it completed (failed jobs keep them for a retry). Texts without an uploaded copy are kept.
+// A zero chars keeps all texts.
+func (s *InMemoryJobStore) SetTextOffloadThreshold(chars int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.textOffloadThreshold = chars
+}
+
+// SetStallHook enables the stalled job reaper: processing jobs not updated for stallAfter (e.g. because
+// their worker hung) are marked failed with ERR_STALLED and passed to hook
+// A zero stallAfter leaves the reaper off. The reaper measures time since the last update, not whether the
+// worker is alive, and only sees the jobs of this store: jobs of an instance that died are lost with its memory.
+func (s *InMemoryJobStore) SetStallHook(stallAfter time.Duration, hook func(jobID string, status *models.StatusResponse)) {
+	s.mu.Lock()
+	s.stallAfter = stallAfter
+	s.onStall = hook
+	s.mu.Unlock()
+
+	if stallAfter > 0 {
+		go s.startStallReaper(stallAfter)
+	}
+}
+
 // SetStatus sets the status for a job (thread-safe)
 func (s *InMemoryJobStore) SetStatus(jobID string, status *models.StatusResponse) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
 	now := time.Now()
 	// Set CreatedAt if not already set
@@ -83,10 +160,43 @@ func (s *InMemoryJobStore) SetStatus(jobID string, status *models.StatusResponse
 		status.CreatedAt = &now
 	}
 
-	s.jobs[jobID] = &jobEntry{
-		status:    status,
-		createdAt: now,
+	status.Progress = JobProgress(status)
+	status.Languages = OrderedLanguages(status)
+	s.compactLocked(status)
+	s.offloadLocked(status)
+	evicted := s.putLocked(jobID, status, now)
+	hook := s.onExpire
+	s.mu.Unlock()
+
+	expireJobs(hook, evicted)
+}
+
+// ClaimStatusWithinLimit stores status under a job ID no unexpired job has, unless its owner already
+// has maxActive processing jobs (thread-safe); a zero maxActive does not limit the owner.
+// Returns the existing job with ErrJobExists when the ID is taken, or ErrActiveJobLimit at the limit.
+func (s *InMemoryJobStore) ClaimStatusWithinLimit(jobID string, status *models.StatusResponse, maxActive int) (*models.StatusResponse, error) {
+	s.mu.Lock()
+	if entry, exists := s.jobs[jobID]; exists && (s.jobTTL <= 0 || time.Since(entry.createdAt) <= s.jobTTL) {
+		s.mu.Unlock()
+		return entry.status, ErrJobExists
 	}
+
+	if maxActive > 0 && s.activeJobsLocked(status.Owner, jobID) >= maxActive {
+		s.mu.Unlock()
+		return nil, ErrActiveJobLimit
+	}
+
+	now := time.Now()
+	if status.CreatedAt == nil {
+		status.CreatedAt = &now
+	}
+	status.Languages = OrderedLanguages(status)
+	evicted := s.putLocked(jobID, status, now)
+	hook := s.onExpire
+	s.mu.Unlock()
+
+	expireJobs(hook, evicted)
+	return status, nil
 }
 
 // GetStatus retrieves the status for a job (thread-safe)
@@ -104,11 +214,34 @@ func (s *InMemoryJobStore) GetStatus(jobID string) (*models.StatusResponse, erro
 		return nil, &StatusNotFoundError{JobID: jobID}
 	}
 
+	entry.lastAccess.Store(time.Now().UnixNano())
 	return entry.status, nil
 }
 
+// ListStatuses returns all unexpired jobs (thread-safe)
+func (s *InMemoryJobStore) ListStatuses() []*models.StatusResponse {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]*models.StatusResponse, 0, len(s.jobs))
+	for _, entry := range s.jobs {
+		if s.jobTTL > 0 && time.Since(entry.createdAt) > s.jobTTL {
+			continue
+		}
+		statuses = append(statuses, entry.status)
+	}
+	return statuses
+}
+
 // UpdateStatusSafely updates a job status using an updater function (thread-safe)
 func (s *InMemoryJobStore) UpdateStatusSafely(jobID string, updater func(*models.StatusResponse)) error {
+	return s.UpdateStatusWithinLimit(jobID, 0, updater)
+}
+
+// UpdateStatusWithinLimit updates a job status using an updater function, unless the job's owner already has
+// maxActive other processing jobs (thread-safe); a zero maxActive does not limit the owner.
+// Returns ErrActiveJobLimit at the limit, without calling updater.
+func (s *InMemoryJobStore) UpdateStatusWithinLimit(jobID string, maxActive int, updater func(*models.StatusResponse)) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -122,29 +255,154 @@ func (s *InMemoryJobStore) UpdateStatusSafely(jobID string, updater func(*models
 		return &StatusNotFoundError{JobID: jobID}
 	}
 
+	if maxActive > 0 && s.activeJobsLocked(entry.status.Owner, jobID) >= maxActive {
+		return ErrActiveJobLimit
+	}
+
 	// Apply updater function
 	updater(entry.status)
 	entry.status.UpdatedAt = time.Now()
+	entry.status.Progress = JobProgress(entry.status)
+	entry.status.Languages = OrderedLanguages(entry.status)
+	s.compactLocked(entry.status)
+	s.offloadLocked(entry.status)
+	entry.lastAccess.Store(time.Now().UnixNano())
 
 	return nil
 }
 
-// CleanupExpiredJobs removes expired jobs from the store
-func (s *InMemoryJobStore) CleanupExpiredJobs() {
+// activeJobsLocked counts the unexpired processing jobs of owner other than jobID; s.mu must be held
+func (s *InMemoryJobStore) activeJobsLocked(owner string, jobID string) int {
+	active := 0
+	for id, entry := range s.jobs {
+		if id != jobID && entry.status.Owner == owner && entry.status.Status == models.StatusProcessing &&
+			(s.jobTTL <= 0 || time.Since(entry.createdAt) <= s.jobTTL) {
+			active++
+		}
+	}
+	return active
+}
+
+// putLocked stores a new entry for the job and evicts jobs past the cap, returning the evicted jobs for the
+// expire hook; s.mu must be held
+func (s *InMemoryJobStore) putLocked(jobID string, status *models.StatusResponse, now time.Time) map[string]*models.StatusResponse {
+	entry := &jobEntry{
+		status:    status,
+		createdAt: now,
+	}
+	entry.lastAccess.Store(now.UnixNano())
+	s.jobs[jobID] = entry
+	return s.evictLocked(jobID)
+}
+
+// evictLocked removes the least recently used finished jobs, other than keep, until the store is within the cap,
+// and returns them; s.mu must be held. Failed jobs with a scheduled retry are not finished yet and are kept.
+func (s *InMemoryJobStore) evictLocked(keep string) map[string]*models.StatusResponse {
+	var evicted map[string]*models.StatusResponse
+	for s.maxJobs > 0 && len(s.jobs) > s.maxJobs {
+		var oldestID string
+		var oldest *jobEntry
+		for jobID, entry := range s.jobs {
+			if jobID == keep || !isFinishedJob(entry.status) || entry.status.NextRetryAt != nil {
+				continue
+			}
+			if oldest == nil || entry.lastAccess.Load() < oldest.lastAccess.Load() {
+				oldestID, oldest = jobID, entry
+			}
+		}
+		if oldest == nil {
+			break // Only processing jobs left
+		}
+
+		delete(s.jobs, oldestID)
+		slog.Info("Evicted least recently used job", "jobID", oldestID, "maxJobs", s.maxJobs)
+		if evicted == nil {
+			evicted = make(map[string]*models.StatusResponse)
+		}
+		evicted[oldestID] = oldest.status
+	}
+	return evicted
+}
+
+// expireJobs passes jobs removed from the store to the expire hook; called after the store is unlocked, so
+// slow cleanup such as file removal does not block other requests
+func expireJobs(hook func(jobID string, status *models.StatusResponse), removed map[string]*models.StatusResponse) {
+	if hook == nil {
+		return
+	}
+	for jobID, status := range removed {
+		hook(jobID, status)
+	}
+}
+
+// compactLocked drops the uploaded translated texts of a finished job when compaction is enabled; s.mu must be held
+func (s *InMemoryJobStore) compactLocked(status *models.StatusResponse) {
+	if !s.compactFinished || !isFinishedJob(status) {
+		return
+	}
+	for _, result := range status.Results {
+		if result.TranslatedTextURL != "" {
+			result.TranslatedText = ""
+		}
+	}
+	status.PartialTranscript = nil
+}
+
+// offloadLocked drops the uploaded texts of a job longer than the offload threshold; s.mu must be held
+func (s *InMemoryJobStore) offloadLocked(status *models.StatusResponse) {
+	if s.textOffloadThreshold <= 0 {
+		return
+	}
+	for _, result := range status.Results {
+		if s.offloadable(result.TranslatedText, result.TranslatedTextURL) {
+			result.TranslatedText = ""
+		}
+	}
+
+	// No retry can need the source texts of a completed job
+	checkpoint := status.Checkpoint
+	if status.Status != models.StatusCompleted || checkpoint == nil || !s.offloadable(checkpoint.Transcript, checkpoint.TranscriptURL) {
+		return
+	}
+	checkpoint.Transcript, checkpoint.Translations, checkpoint.Cues = "", nil, nil
+	for i := range checkpoint.Chapters {
+		checkpoint.Chapters[i].Transcript, checkpoint.Chapters[i].Translations = "", nil
+	}
+	checkpoint.TextOffloaded = true
+}
+
+// offloadable reports whether text is longer than the offload threshold and was uploaded to url
+func (s *InMemoryJobStore) offloadable(text string, url string) bool {
+	return url != "" && utf8.RuneCountInString(text) > s.textOffloadThreshold
+}
+
+// isFinishedJob reports whether the job reached a terminal status
+func isFinishedJob(status *models.StatusResponse) bool {
+	return status.Status == models.StatusCompleted || status.Status == models.StatusFailed ||
+		status.Status == models.StatusPartiallyCompleted
+}
+
+// CleanupExpiredJobs removes expired jobs from the store and returns how many were removed
+func (s *InMemoryJobStore) CleanupExpiredJobs() int {
 	if s.jobTTL <= 0 {
-		return // No TTL, skip cleanup
+		return 0 // No TTL, skip cleanup
 	}
 
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	now := time.Now()
+	removed := make(map[string]*models.StatusResponse)
 	for jobID, entry := range s.jobs {
 		if now.Sub(entry.createdAt) > s.jobTTL {
 			delete(s.jobs, jobID)
+			removed[jobID] = entry.status
 			slog.Info("Removed expired job", "jobID", jobID, "age", now.Sub(entry.createdAt))
 		}
 	}
+	hook := s.onExpire
+	s.mu.Unlock()
+
+	expireJobs(hook, removed)
+	return len(removed)
 }
 
 // startCleanup starts a background goroutine that periodically cleans up expired jobs
@@ -161,6 +419,81 @@ func (s *InMemoryJobStore) startCleanup() {
 	}
 }
 
+// ReapStalledJobs fails processing jobs not updated for the stall period and returns how many were failed
+// Unfinished languages fail with the job; the stall hook is called for each job after the store is unlocked.
+func (s *InMemoryJobStore) ReapStalledJobs() int {
+	s.mu.Lock()
+	if s.stallAfter <= 0 {
+		s.mu.Unlock()
+		return 0
+	}
+
+	now := time.Now()
+	stalled := make(map[string]*models.StatusResponse)
+	for jobID, entry := range s.jobs {
+		status := entry.status
+		if status.Status != models.StatusProcessing || (s.jobTTL > 0 && now.Sub(entry.createdAt) > s.jobTTL) {
+			continue
+		}
+		lastUpdate := status.UpdatedAt
+		if lastUpdate.IsZero() {
+			lastUpdate = entry.createdAt
+		}
+		idle := now.Sub(lastUpdate)
+		if idle <= s.stallAfter {
+			continue
+		}
+
+		reason := fmt.Sprintf("job stalled: no progress for %s", idle.Round(time.Second))
+		for _, result := range status.Results {
+			if !result.IsFinished() {
+				result.Status = models.StatusFailed
+				result.Error = reason
+				result.ErrorCode = models.ErrCodeStalled
+				result.Progress = 0
+			}
+		}
+		if len(status.Results) == 0 {
+			status.Results = map[string]*models.LanguageResult{
+				"error": {Status: models.StatusFailed, Error: reason, ErrorCode: models.ErrCodeStalled},
+			}
+		}
+		status.Status = models.StatusFailed
+		status.ErrorCode = models.ErrCodeStalled
+		status.UpdatedAt = now
+		status.Progress = JobProgress(status)
+		status.Languages = OrderedLanguages(status)
+		status.RecordEvent(models.EventJobFailed, "", reason)
+		stalled[jobID] = status
+		slog.Warn("Failed stalled job", "jobID", jobID, "idle", idle)
+	}
+	hook := s.onStall
+	s.mu.Unlock()
+
+	if hook != nil {
+		for jobID, status := range stalled {
+			hook(jobID, status)
+		}
+	}
+	return len(stalled)
+}
+
+// startStallReaper periodically fails stalled jobs
+func (s *InMemoryJobStore) startStallReaper(stallAfter time.Duration) {
+	ticker := time.NewTicker(max(stallAfter/4, time.Second))
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.ReapStalledJobs()
+	}
+}
+
+// Errors returned by ClaimStatusWithinLimit and UpdateStatusWithinLimit
+var (
+	ErrJobExists      = errors.New("job ID is already in use")
+	ErrActiveJobLimit = errors.New("too many processing jobs")
+)
+
 // StatusNotFoundError represents a job not found error
 type StatusNotFoundError struct {
 	JobID string
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -42,6 +43,32 @@ func (m *mockJobStore) UpdateStatusSafely(jobID string, updater func(*models.Sta
 	return nil
 }
 
+func (m *mockJobStore) UpdateStatusWithinLimit(jobID string, maxActive int, updater func(*models.StatusResponse)) error {
+	status, exists := m.jobs[jobID]
+	if !exists {
+		return &StatusNotFoundError{JobID: jobID}
+	}
+	active := 0
+	for id, other := range m.jobs {
+		if id != jobID && other.Owner == status.Owner && other.Status == models.StatusProcessing {
+			active++
+		}
+	}
+	if maxActive > 0 && active >= maxActive {
+		return ErrActiveJobLimit
+	}
+	updater(status)
+	return nil
+}
+
+func (m *mockJobStore) ListStatuses() []*models.StatusResponse {
+	statuses := make([]*models.StatusResponse, 0, len(m.jobs))
+	for _, status := range m.jobs {
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
 func TestStatusHandler_Get(t *testing.T) {
 	store := newMockJobStore()
 	handler := StatusHandler(store)
@@ -143,11 +170,11 @@ func TestStatusHandler_CompletedJob(t *testing.T) {
 		Status: models.StatusCompleted,
 		Results: map[string]*models.LanguageResult{
 			"en": {
-				Status:        models.StatusCompleted,
-				VideoURL:      "gs://bucket/translations/job-id/en.mp4",
+				Status:         models.StatusCompleted,
+				VideoURL:       "gs://bucket/translations/job-id/en.mp4",
 				TranslatedText: "Hello, world!",
-				Progress:      100,
-				ProcessedAt:   &now,
+				Progress:       100,
+				ProcessedAt:    &now,
 			},
 		},
 		CreatedAt: &now,
@@ -185,3 +212,398 @@ func TestStatusHandler_CompletedJob(t *testing.T) {
 		t.Error("expected 'en' result to exist")
 	}
 }
+
+func TestInMemoryJobStore_ExpireHook(t *testing.T) {
+	// Constructed directly to avoid the background cleanup goroutine
+	store := &InMemoryJobStore{
+		jobs:   make(map[string]*jobEntry),
+		jobTTL: time.Nanosecond,
+	}
+
+	var expired []string
+	store.SetExpireHook(func(jobID string, status *models.StatusResponse) {
+		expired = append(expired, jobID)
+	})
+
+	store.SetStatus("old-job", &models.StatusResponse{JobID: "old-job"})
+	time.Sleep(time.Millisecond)
+	store.CleanupExpiredJobs()
+
+	if len(expired) != 1 || expired[0] != "old-job" {
+		t.Errorf("expected hook to be called for 'old-job', got %v", expired)
+	}
+	if _, err := store.GetStatus("old-job"); err == nil {
+		t.Error("expected expired job to be removed")
+	}
+}
+
+func TestInMemoryJobStore_ReapStalledJobs(t *testing.T) {
+	// Constructed directly to avoid the background goroutines
+	store := &InMemoryJobStore{
+		jobs:       make(map[string]*jobEntry),
+		stallAfter: time.Minute,
+	}
+
+	var stalled []string
+	store.onStall = func(jobID string, status *models.StatusResponse) {
+		stalled = append(stalled, jobID)
+	}
+
+	old := time.Now().Add(-2 * time.Minute)
+	store.SetStatus("stalled", &models.StatusResponse{
+		JobID:     "stalled",
+		Status:    models.StatusProcessing,
+		UpdatedAt: old,
+		Results: map[string]*models.LanguageResult{
+			"de": {Status: models.StatusCompleted},
+			"fr": {Status: models.StatusProcessing, Progress: 40},
+			"es": {Status: models.StatusPending},
+		},
+	})
+	store.SetStatus("active", &models.StatusResponse{JobID: "active", Status: models.StatusProcessing, UpdatedAt: time.Now()})
+	store.SetStatus("finished", &models.StatusResponse{JobID: "finished", Status: models.StatusCompleted, UpdatedAt: old})
+
+	if reaped := store.ReapStalledJobs(); reaped != 1 {
+		t.Fatalf("expected 1 reaped job, got %d", reaped)
+	}
+	if len(stalled) != 1 || stalled[0] != "stalled" {
+		t.Errorf("expected hook to be called for 'stalled', got %v", stalled)
+	}
+
+	status, _ := store.GetStatus("stalled")
+	if status.Status != models.StatusFailed || status.ErrorCode != models.ErrCodeStalled {
+		t.Errorf("expected failed job with %s, got %s (%s)", models.ErrCodeStalled, status.Status, status.ErrorCode)
+	}
+	if status.Results["fr"].Status != models.StatusFailed || status.Results["fr"].ErrorCode != models.ErrCodeStalled {
+		t.Errorf("expected unfinished language to fail, got %+v", status.Results["fr"])
+	}
+	if status.Results["es"].Status != models.StatusFailed {
+		t.Errorf("expected pending language to fail, got %+v", status.Results["es"])
+	}
+	if status.Results["de"].Status != models.StatusCompleted {
+		t.Errorf("expected finished language to be kept, got %s", status.Results["de"].Status)
+	}
+
+	if active, _ := store.GetStatus("active"); active.Status != models.StatusProcessing {
+		t.Errorf("expected active job to keep processing, got %s", active.Status)
+	}
+
+	// Reaped jobs are not reaped again
+	if reaped := store.ReapStalledJobs(); reaped != 0 {
+		t.Errorf("expected no more reaped jobs, got %d", reaped)
+	}
+}
+
+func TestInMemoryJobStore_MaxJobs(t *testing.T) {
+	// Constructed directly to avoid the background cleanup goroutine
+	store := &InMemoryJobStore{
+		jobs:   make(map[string]*jobEntry),
+		jobTTL: time.Hour,
+	}
+	var evicted []string
+	store.SetExpireHook(func(jobID string, status *models.StatusResponse) {
+		evicted = append(evicted, jobID)
+	})
+	store.SetMaxJobs(3)
+
+	store.SetStatus("running", &models.StatusResponse{JobID: "running", Status: models.StatusProcessing})
+	store.SetStatus("done-1", &models.StatusResponse{JobID: "done-1", Status: models.StatusCompleted})
+	time.Sleep(time.Millisecond)
+	store.SetStatus("done-2", &models.StatusResponse{JobID: "done-2", Status: models.StatusFailed})
+	time.Sleep(time.Millisecond)
+
+	// Reading done-1 makes done-2 the least recently used finished job
+	if _, err := store.GetStatus("done-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	store.SetStatus("done-3", &models.StatusResponse{JobID: "done-3", Status: models.StatusCompleted})
+	time.Sleep(time.Millisecond)
+	store.SetStatus("done-4", &models.StatusResponse{JobID: "done-4", Status: models.StatusCompleted})
+
+	if len(evicted) != 2 || evicted[0] != "done-2" || evicted[1] != "done-1" {
+		t.Errorf("expected done-2 then done-1 to be evicted, got %v", evicted)
+	}
+	for _, jobID := range []string{"running", "done-3", "done-4"} {
+		if _, err := store.GetStatus(jobID); err != nil {
+			t.Errorf("expected %s to be kept: %v", jobID, err)
+		}
+	}
+
+	// Processing jobs are kept past the cap
+	for _, jobID := range []string{"running-2", "running-3", "running-4"} {
+		store.SetStatus(jobID, &models.StatusResponse{JobID: jobID, Status: models.StatusProcessing})
+	}
+	if got := len(store.ListStatuses()); got != 4 {
+		t.Errorf("expected the 4 processing jobs to be kept, got %d jobs", got)
+	}
+}
+
+func TestInMemoryJobStore_MaxJobsKeepsScheduledRetries(t *testing.T) {
+	// Constructed directly to avoid the background cleanup goroutine
+	store := &InMemoryJobStore{
+		jobs:   make(map[string]*jobEntry),
+		jobTTL: time.Hour,
+	}
+	var evicted []string
+	store.SetExpireHook(func(jobID string, status *models.StatusResponse) {
+		// The hook runs after the store is unlocked, so it can use the store
+		store.ListStatuses()
+		evicted = append(evicted, jobID)
+	})
+	store.SetMaxJobs(2)
+
+	retryAt := time.Now().Add(time.Minute)
+	store.SetStatus("retrying", &models.StatusResponse{JobID: "retrying", Status: models.StatusFailed, NextRetryAt: &retryAt})
+	time.Sleep(time.Millisecond)
+	store.SetStatus("done-1", &models.StatusResponse{JobID: "done-1", Status: models.StatusCompleted})
+	time.Sleep(time.Millisecond)
+	store.SetStatus("done-2", &models.StatusResponse{JobID: "done-2", Status: models.StatusCompleted})
+
+	if len(evicted) != 1 || evicted[0] != "done-1" {
+		t.Errorf("expected only done-1 to be evicted, got %v", evicted)
+	}
+	if _, err := store.GetStatus("retrying"); err != nil {
+		t.Errorf("expected the job with a scheduled retry to be kept: %v", err)
+	}
+}
+
+func TestInMemoryJobStore_TextOffload(t *testing.T) {
+	// Constructed directly to avoid the background cleanup goroutine
+	store := &InMemoryJobStore{
+		jobs:   make(map[string]*jobEntry),
+		jobTTL: time.Hour,
+	}
+	store.SetTextOffloadThreshold(10)
+
+	store.SetStatus("job-1", &models.StatusResponse{
+		JobID:  "job-1",
+		Status: models.StatusProcessing,
+		Results: map[string]*models.LanguageResult{
+			"es": {Status: models.StatusCompleted, TranslatedText: "Hola a todos.", TranslatedTextURL: "https://example.com/es.txt"},
+			"de": {Status: models.StatusCompleted, TranslatedText: "Hallo.", TranslatedTextURL: "https://example.com/de.txt"},
+			"fr": {Status: models.StatusCompleted, TranslatedText: "Bonjour à tous."},
+		},
+		Checkpoint: &models.JobCheckpoint{
+			Transcript:    "Hello everyone.",
+			TranscriptURL: "https://example.com/transcript.txt",
+			Cues:          []models.SubtitleCue{{Start: 0, End: 1, Text: "Hello everyone."}},
+			Translations:  map[string]string{"es": "Hola a todos."},
+			Chapters:      []models.Chapter{{Transcript: "Hello everyone.", Translations: map[string]string{"es": "Hola a todos."}}},
+		},
+	})
+	status, _ := store.GetStatus("job-1")
+	if es := status.Results["es"]; es.TranslatedText != "" || es.TranslatedTextURL == "" {
+		t.Errorf("expected uploaded text over the threshold to be dropped and its URL kept, got %+v", es)
+	}
+	if status.Results["de"].TranslatedText != "Hallo." {
+		t.Error("expected text within the threshold to be kept")
+	}
+	if status.Results["fr"].TranslatedText != "Bonjour à tous." {
+		t.Error("expected text without an uploaded copy to be kept")
+	}
+	if checkpoint := status.Checkpoint; checkpoint.Transcript == "" || checkpoint.Cues == nil || checkpoint.TextOffloaded {
+		t.Errorf("expected source texts of processing jobs to be kept for a retry, got %+v", checkpoint)
+	}
+
+	err := store.UpdateStatusSafely("job-1", func(status *models.StatusResponse) {
+		status.Status = models.StatusPartiallyCompleted
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status, _ := store.GetStatus("job-1"); status.Checkpoint.Transcript == "" || status.Checkpoint.TextOffloaded {
+		t.Error("expected source texts of partially completed jobs to be kept for a retry")
+	}
+
+	err = store.UpdateStatusSafely("job-1", func(status *models.StatusResponse) {
+		status.Status = models.StatusCompleted
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status, _ = store.GetStatus("job-1")
+	checkpoint := status.Checkpoint
+	if !checkpoint.TextOffloaded || checkpoint.Transcript != "" || checkpoint.Cues != nil || checkpoint.Translations != nil {
+		t.Errorf("expected source texts of the completed job to be dropped, got %+v", checkpoint)
+	}
+	if checkpoint.Chapters[0].Transcript != "" || checkpoint.Chapters[0].Translations != nil {
+		t.Errorf("expected chapter texts to be dropped, got %+v", checkpoint.Chapters[0])
+	}
+	if checkpoint.TranscriptURL == "" {
+		t.Error("expected the transcript URL to be kept")
+	}
+}
+
+func TestInMemoryJobStore_TextOffloadWithoutURL(t *testing.T) {
+	store := &InMemoryJobStore{
+		jobs:   make(map[string]*jobEntry),
+		jobTTL: time.Hour,
+	}
+	store.SetTextOffloadThreshold(10)
+
+	store.SetStatus("job-1", &models.StatusResponse{
+		JobID:      "job-1",
+		Status:     models.StatusCompleted,
+		Checkpoint: &models.JobCheckpoint{Transcript: "Hello everyone."},
+	})
+	if status, _ := store.GetStatus("job-1"); status.Checkpoint.Transcript == "" || status.Checkpoint.TextOffloaded {
+		t.Error("expected a transcript without an uploaded copy to be kept")
+	}
+}
+
+func TestInMemoryJobStore_CompactFinished(t *testing.T) {
+	// Constructed directly to avoid the background cleanup goroutine
+	store := &InMemoryJobStore{
+		jobs:   make(map[string]*jobEntry),
+		jobTTL: time.Hour,
+	}
+	store.SetCompactFinished(true)
+
+	store.SetStatus("job-1", &models.StatusResponse{
+		JobID:  "job-1",
+		Status: models.StatusProcessing,
+		Results: map[string]*models.LanguageResult{
+			"es": {Status: models.StatusCompleted, TranslatedText: "Hola.", TranslatedTextURL: "https://example.com/es.txt"},
+			"fr": {Status: models.StatusProcessing},
+		},
+	})
+	status, _ := store.GetStatus("job-1")
+	if status.Results["es"].TranslatedText != "Hola." {
+		t.Error("expected texts of processing jobs to be kept")
+	}
+
+	err := store.UpdateStatusSafely("job-1", func(status *models.StatusResponse) {
+		status.Results["fr"] = &models.LanguageResult{Status: models.StatusCompleted, TranslatedText: "Bonjour."}
+		status.Status = models.StatusCompleted
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status, _ = store.GetStatus("job-1")
+	if es := status.Results["es"]; es.TranslatedText != "" || es.TranslatedTextURL == "" {
+		t.Errorf("expected uploaded text to be dropped and its URL kept, got %+v", es)
+	}
+	if status.Results["fr"].TranslatedText != "Bonjour." {
+		t.Error("expected text without an uploaded copy to be kept")
+	}
+}
+
+func TestInMemoryJobStore_ClaimStatusWithinLimit(t *testing.T) {
+	// Constructed directly to avoid the background cleanup goroutine
+	store := &InMemoryJobStore{
+		jobs:   make(map[string]*jobEntry),
+		jobTTL: time.Hour,
+	}
+	claim := func(jobID string, owner string, maxActive int) error {
+		_, err := store.ClaimStatusWithinLimit(jobID, &models.StatusResponse{JobID: jobID, Owner: owner, Status: models.StatusProcessing}, maxActive)
+		return err
+	}
+
+	store.SetStatus("done", &models.StatusResponse{JobID: "done", Owner: "acme", Status: models.StatusCompleted})
+	if err := claim("job-1", "acme", 2); err != nil {
+		t.Fatalf("expected first job to be stored, got %v", err)
+	}
+	if err := claim("job-2", "acme", 2); err != nil {
+		t.Fatalf("expected second job to be stored, got %v", err)
+	}
+	if err := claim("job-3", "acme", 2); !errors.Is(err, ErrActiveJobLimit) {
+		t.Errorf("expected ErrActiveJobLimit for third concurrent job, got %v", err)
+	}
+	if _, err := store.GetStatus("job-3"); err == nil {
+		t.Error("expected rejected job not to be stored")
+	}
+
+	// Other owners and unlimited submissions are not affected
+	if err := claim("job-4", "beta", 2); err != nil {
+		t.Errorf("expected another owner's job to be stored, got %v", err)
+	}
+	if err := claim("job-5", "acme", 0); err != nil {
+		t.Errorf("expected job without a limit to be stored, got %v", err)
+	}
+
+	// Taken IDs return the existing job, even for completed jobs
+	existing, err := store.ClaimStatusWithinLimit("done", &models.StatusResponse{JobID: "done", Owner: "acme"}, 0)
+	if !errors.Is(err, ErrJobExists) {
+		t.Fatalf("expected ErrJobExists, got %v", err)
+	}
+	if existing.Status != models.StatusCompleted {
+		t.Errorf("expected existing completed job, got status %s", existing.Status)
+	}
+}
+
+func TestInMemoryJobStore_UpdateStatusWithinLimit(t *testing.T) {
+	// Constructed directly to avoid the background cleanup goroutine
+	store := &InMemoryJobStore{
+		jobs:   make(map[string]*jobEntry),
+		jobTTL: time.Hour,
+	}
+	store.SetStatus("running", &models.StatusResponse{JobID: "running", Owner: "acme", Status: models.StatusProcessing})
+	store.SetStatus("failed", &models.StatusResponse{JobID: "failed", Owner: "acme", Status: models.StatusFailed})
+	claim := func(status *models.StatusResponse) { status.Status = models.StatusProcessing }
+
+	if err := store.UpdateStatusWithinLimit("failed", 1, claim); !errors.Is(err, ErrActiveJobLimit) {
+		t.Errorf("expected ErrActiveJobLimit while the owner runs another job, got %v", err)
+	}
+	if status, _ := store.GetStatus("failed"); status.Status != models.StatusFailed {
+		t.Errorf("expected the job not to be updated at the limit, got status %s", status.Status)
+	}
+
+	// The updated job itself does not count towards the limit
+	if err := store.UpdateStatusWithinLimit("failed", 2, claim); err != nil {
+		t.Fatalf("expected the job to be updated below the limit, got %v", err)
+	}
+	if err := store.UpdateStatusWithinLimit("failed", 2, func(*models.StatusResponse) {}); err != nil {
+		t.Errorf("expected a processing job to be updated at the limit it counts towards, got %v", err)
+	}
+}
+
+func TestStatusHandler_OtherOwner(t *testing.T) {
+	store := newMockJobStore()
+	store.SetStatus("acme-job", &models.StatusResponse{JobID: "acme-job", Owner: "acme", Status: models.StatusCompleted})
+	handler := StatusHandler(store)
+
+	req := WithPrincipal(httptest.NewRequest(http.MethodGet, "/v1/status/acme-job", nil), &Principal{Owner: "globex"})
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for another owner's job, got %d", http.StatusNotFound, w.Code)
+	}
+
+	req = WithPrincipal(httptest.NewRequest(http.MethodGet, "/v1/status/acme-job", nil), &Principal{Owner: "acme"})
+	w = httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d for the owner, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestStatusHandler_ScopedJobID(t *testing.T) {
+	store := newMockJobStore()
+	acmeID, globexID := ScopedJobID("acme", "launch"), ScopedJobID("globex", "launch")
+	if acmeID == globexID || acmeID == "launch" {
+		t.Fatalf("expected distinct scoped IDs, got %q and %q", acmeID, globexID)
+	}
+	if ScopedJobID("", "launch") != "launch" {
+		t.Error("expected job IDs without an owner to be kept")
+	}
+	store.SetStatus(acmeID, &models.StatusResponse{JobID: acmeID, Owner: "acme", Status: models.StatusCompleted})
+	handler := StatusHandler(store)
+
+	// The owner finds its job by the jobId it supplied, or by the scoped ID
+	for _, path := range []string{"/v1/status/launch", "/v1/status/" + acmeID} {
+		w := httptest.NewRecorder()
+		handler(w, WithPrincipal(httptest.NewRequest(http.MethodGet, path, nil), &Principal{Owner: "acme"}))
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d for %s, got %d", http.StatusOK, path, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler(w, WithPrincipal(httptest.NewRequest(http.MethodGet, "/v1/status/launch", nil), &Principal{Owner: "globex"}))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for another owner, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/tasks"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// RestartFunc runs a job again from the start
+// release gives back the job's admission and must be called once the job was processed.
+// It is called after the response was sent and may process the job before returning.
+type RestartFunc func(jobID string, release func())
+
+// TaskRetryHandler handles POST /tasks/retry, the Cloud Tasks callback for scheduled retries
+// Jobs with a checkpoint re-run their failed languages; jobs that failed earlier restart from the download.
+// Stale tasks (a newer attempt was scheduled, or the job is no longer failed) are acknowledged and ignored.
+// A job the instance does not admit now, or whose owner already runs as many jobs as activeLimit allows, is
+// answered with an error, so Cloud Tasks delivers the task again later.
+func TaskRetryHandler(store JobStatusStore, token string, admit AdmitFunc, activeLimit ActiveJobLimitFunc, retry RetryFunc, restart RestartFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(tasks.TokenHeader)), []byte(token)) != 1 {
+			ErrorResponse(w, http.StatusUnauthorized, "invalid task token", "")
+			return
+		}
+
+		var task tasks.RetryTask
+		if err := json.NewDecoder(r.Body).Decode(&task); err != nil || task.JobID == "" {
+			ErrorResponse(w, http.StatusBadRequest, "invalid retry task", "")
+			return
+		}
+
+		current, err := store.GetStatus(task.JobID)
+		skipReason := "job not found"
+		if err == nil {
+			skipReason = staleTaskReason(current, task)
+		}
+		if skipReason != "" {
+			ignoreTask(w, task, skipReason)
+			return
+		}
+
+		release, admissionErr := admit(r, current)
+		if admissionErr != nil {
+			WriteAdmissionError(w, admissionErr, task.JobID)
+			return
+		}
+
+		var languages []string
+		restarted := false
+		maxActive := activeLimit(current.Owner)
+		err = store.UpdateStatusWithinLimit(task.JobID, maxActive, func(status *models.StatusResponse) {
+			if skipReason = staleTaskReason(status, task); skipReason != "" {
+				return
+			}
+
+			if status.Checkpoint != nil {
+				languages = failedLanguages(status)
+				claimLanguages(status, languages)
+				return
+			}
+			if status.Request == nil {
+				skipReason = "job has no request to restart"
+				return
+			}
+
+			status.Status = models.StatusProcessing
+			status.ErrorCode = ""
+			status.Stage = ""
+			status.NextRetryAt = nil
+			status.TransientFailure = false
+			status.Results = models.PendingResults(status.Request.TargetLanguages)
+			status.UpdatedAt = time.Now()
+			restarted = true
+		})
+		if errors.Is(err, ErrActiveJobLimit) {
+			release()
+			WriteAdmissionError(w, ActiveJobLimitError(maxActive), task.JobID)
+			return
+		}
+		if err != nil {
+			skipReason = "job not found"
+		}
+
+		if skipReason != "" {
+			release()
+			ignoreTask(w, task, skipReason)
+			return
+		}
+
+		slog.Info("Running scheduled retry", "jobID", task.JobID, "attempt", task.Attempt, "restart", restarted, "languages", languages)
+		w.WriteHeader(http.StatusAccepted)
+		flushResponse(w)
+
+		if restarted {
+			restart(task.JobID, release)
+		} else {
+			retry(task.JobID, languages, release)
+		}
+	}
+}
+
+// ignoreTask acknowledges a retry task that no longer applies
+// Any 2xx acknowledges the task; errors would make Cloud Tasks redeliver it.
+func ignoreTask(w http.ResponseWriter, task tasks.RetryTask, reason string) {
+	slog.Info("Ignoring scheduled retry", "jobID", task.JobID, "attempt", task.Attempt, "reason", reason)
+	w.WriteHeader(http.StatusOK)
+}
+
+// staleTaskReason returns why a retry task no longer applies to the job, or "" if it does
+func staleTaskReason(status *models.StatusResponse, task tasks.RetryTask) string {
+	switch {
+	case status.RetryAttempts != task.Attempt:
+		return "superseded by another attempt"
+	case !IsTransientFailure(status):
+		return "job is not failed on a transient error"
+	}
+	return ""
+}
+
+// IsTransientFailure reports whether a failed or partially completed job failed only on transient provider errors
+func IsTransientFailure(status *models.StatusResponse) bool {
+	if status.Status != models.StatusFailed && status.Status != models.StatusPartiallyCompleted {
+		return false
+	}
+	if status.TransientFailure {
+		return true
+	}
+	if status.Checkpoint == nil {
+		return false
+	}
+
+	languages := failedLanguages(status)
+	for _, lang := range languages {
+		if result := status.Results[lang]; result == nil || !result.Transient {
+			return false
+		}
+	}
+	return len(languages) > 0
+}
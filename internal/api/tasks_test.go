@@ -0,0 +1,232 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/internal/tasks"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func newTransientJob(jobID string, attempts int) *models.StatusResponse {
+	return &models.StatusResponse{
+		JobID:         jobID,
+		Status:        models.StatusPartiallyCompleted,
+		RetryAttempts: attempts,
+		Results: map[string]*models.LanguageResult{
+			"de": {Status: models.StatusCompleted},
+			"ar": {Status: models.StatusFailed, Error: "TTS generation failed", Transient: true},
+		},
+		Request:    &models.TranslateRequest{TargetLanguages: []string{"de", "ar"}},
+		Checkpoint: &models.JobCheckpoint{Transcript: "Hello", SourceLanguage: "en"},
+	}
+}
+
+func newTaskRequest(body string, token string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, tasks.RetryPath, strings.NewReader(body))
+	req.Header.Set(tasks.TokenHeader, token)
+	return req
+}
+
+func TestTaskRetryHandler_RetriesFailedLanguages(t *testing.T) {
+	store := newMockJobStore()
+	store.SetStatus("job-1", newTransientJob("job-1", 1))
+
+	var retried []string
+	handler := TaskRetryHandler(store, "secret", admitAll, unlimitedJobs, func(jobID string, languages []string, release func()) {
+		retried = languages
+	}, func(jobID string, release func()) {
+		t.Error("expected no restart for a checkpointed job")
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, newTaskRequest(`{"jobId":"job-1","attempt":1}`, "secret"))
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, w.Code)
+	}
+	if want := []string{"ar"}; !reflect.DeepEqual(retried, want) {
+		t.Errorf("expected retried languages %v, got %v", want, retried)
+	}
+	status, _ := store.GetStatus("job-1")
+	if status.Status != models.StatusProcessing {
+		t.Errorf("expected job status '%s', got '%s'", models.StatusProcessing, status.Status)
+	}
+}
+
+func TestTaskRetryHandler_RestartsJobWithoutCheckpoint(t *testing.T) {
+	store := newMockJobStore()
+	store.SetStatus("job-1", &models.StatusResponse{
+		JobID:            "job-1",
+		Status:           models.StatusFailed,
+		Stage:            models.StageTranscribing,
+		RetryAttempts:    2,
+		TransientFailure: true,
+		Results:          map[string]*models.LanguageResult{"error": {Status: models.StatusFailed}},
+		Request:          &models.TranslateRequest{TargetLanguages: []string{"de"}},
+	})
+
+	var restarted string
+	handler := TaskRetryHandler(store, "secret", admitAll, unlimitedJobs, func(jobID string, languages []string, release func()) {
+		t.Error("expected no language retry without a checkpoint")
+	}, func(jobID string, release func()) {
+		restarted = jobID
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, newTaskRequest(`{"jobId":"job-1","attempt":2}`, "secret"))
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, w.Code)
+	}
+	if restarted != "job-1" {
+		t.Errorf("expected job-1 to restart, got %q", restarted)
+	}
+	status, _ := store.GetStatus("job-1")
+	if status.Status != models.StatusProcessing || status.TransientFailure {
+		t.Errorf("expected job reset for restart, got %+v", status)
+	}
+	if len(status.Results) != 1 || status.Results["de"].Status != models.StatusPending {
+		t.Errorf("expected a pending result per target language, got %+v", status.Results)
+	}
+}
+
+func TestTaskRetryHandler_IgnoresStaleTasks(t *testing.T) {
+	tests := []struct {
+		name string
+		job  *models.StatusResponse
+		body string
+	}{
+		{"unknown job", nil, `{"jobId":"missing","attempt":1}`},
+		{"superseded attempt", newTransientJob("job-1", 2), `{"jobId":"job-1","attempt":1}`},
+		{"permanent failure", func() *models.StatusResponse {
+			job := newTransientJob("job-1", 1)
+			job.Results["ar"].Transient = false
+			return job
+		}(), `{"jobId":"job-1","attempt":1}`},
+		{"already processing", func() *models.StatusResponse {
+			job := newTransientJob("job-1", 1)
+			job.Status = models.StatusProcessing
+			return job
+		}(), `{"jobId":"job-1","attempt":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockJobStore()
+			if tt.job != nil {
+				store.SetStatus(tt.job.JobID, tt.job)
+			}
+			handler := TaskRetryHandler(store, "secret", admitAll, unlimitedJobs, func(jobID string, languages []string, release func()) {
+				t.Error("expected no retry")
+			}, func(jobID string, release func()) {
+				t.Error("expected no restart")
+			})
+
+			w := httptest.NewRecorder()
+			handler(w, newTaskRequest(tt.body, "secret"))
+
+			if w.Code != http.StatusOK {
+				t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+		})
+	}
+}
+
+func TestTaskRetryHandler_AdmissionRefused(t *testing.T) {
+	store := newMockJobStore()
+	job := newTransientJob("job-1", 1)
+	store.SetStatus("job-1", job)
+
+	refuse := func(r *http.Request, status *models.StatusResponse) (func(), *AdmissionError) {
+		return nil, &AdmissionError{StatusCode: http.StatusServiceUnavailable, Code: "instance_at_capacity", Message: "busy"}
+	}
+	handler := TaskRetryHandler(store, "secret", refuse, unlimitedJobs, func(jobID string, languages []string, release func()) {
+		t.Error("expected no retry")
+	}, func(jobID string, release func()) {
+		t.Error("expected no restart")
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, newTaskRequest(`{"jobId":"job-1","attempt":1}`, "secret"))
+
+	// A non-2xx answer makes Cloud Tasks deliver the task again later
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+	if job.Status != models.StatusPartiallyCompleted {
+		t.Errorf("expected job to stay unclaimed, got status '%s'", job.Status)
+	}
+}
+
+func TestTaskRetryHandler_ActiveJobLimit(t *testing.T) {
+	store := newMockJobStore()
+	job := newTransientJob("job-1", 1)
+	job.Owner = "acme"
+	store.SetStatus("job-1", job)
+	store.SetStatus("job-2", &models.StatusResponse{JobID: "job-2", Owner: "acme", Status: models.StatusProcessing})
+
+	limit := func(owner string) int { return 1 }
+	handler := TaskRetryHandler(store, "secret", admitAll, limit, func(jobID string, languages []string, release func()) {
+		t.Error("expected no retry")
+	}, func(jobID string, release func()) {
+		t.Error("expected no restart")
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, newTaskRequest(`{"jobId":"job-1","attempt":1}`, "secret"))
+
+	// The task is delivered again once the owner runs fewer jobs
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+	if job.Status != models.StatusPartiallyCompleted {
+		t.Errorf("expected job to stay unclaimed, got status '%s'", job.Status)
+	}
+}
+
+func TestTaskRetryHandler_RejectsInvalidToken(t *testing.T) {
+	store := newMockJobStore()
+	store.SetStatus("job-1", newTransientJob("job-1", 1))
+	handler := TaskRetryHandler(store, "secret", admitAll, unlimitedJobs, func(jobID string, languages []string, release func()) {
+		t.Error("expected no retry")
+	}, func(jobID string, release func()) {
+		t.Error("expected no restart")
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, newTaskRequest(`{"jobId":"job-1","attempt":1}`, "wrong"))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestIsTransientFailure(t *testing.T) {
+	mixed := newTransientJob("job-1", 0)
+	mixed.Results["ru"] = &models.LanguageResult{Status: models.StatusFailed, Error: "unsupported language"}
+	mixed.Request.TargetLanguages = append(mixed.Request.TargetLanguages, "ru")
+
+	tests := []struct {
+		name string
+		job  *models.StatusResponse
+		want bool
+	}{
+		{"transient language failure", newTransientJob("job-1", 0), true},
+		{"mixed failures", mixed, false},
+		{"transient job failure", &models.StatusResponse{Status: models.StatusFailed, TransientFailure: true}, true},
+		{"job failure without checkpoint", &models.StatusResponse{Status: models.StatusFailed}, false},
+		{"completed", &models.StatusResponse{Status: models.StatusCompleted, TransientFailure: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientFailure(tt.job); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
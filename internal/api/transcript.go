@@ -0,0 +1,101 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// TranscriptHandler handles GET /v1/jobs/{id}/transcript
+// The source transcript is available once transcription succeeded, whatever the outcome of the target languages.
+func TranscriptHandler(store JobStatusStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		jobID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/jobs/"), "/transcript")
+		if jobID == "" || strings.Contains(jobID, "/") {
+			ErrorResponse(w, http.StatusBadRequest, "job ID is required", "")
+			return
+		}
+		jobID = resolveJobID(r, store, jobID)
+
+		status, err := store.GetStatus(jobID)
+		if err != nil || !canAccessJob(r, status) {
+			ErrorResponse(w, http.StatusNotFound, "job not found", jobID)
+			return
+		}
+
+		var response *models.TranscriptResponse
+		switch {
+		case status.Checkpoint == nil && status.Status == models.StatusProcessing && len(status.PartialTranscript) > 0:
+			response = BuildPartialTranscript(status)
+		case status.Checkpoint != nil:
+			response = BuildTranscript(status)
+		}
+		if response == nil {
+			if status.Status == models.StatusProcessing {
+				ErrorResponse(w, http.StatusConflict, "transcript is not available yet", jobID)
+			} else {
+				ErrorResponse(w, http.StatusNotFound, "job failed before transcription and has no transcript", jobID)
+			}
+			return
+		}
+
+		slog.Debug("Transcript request", "jobID", jobID)
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// BuildTranscript returns the source transcript of a job with a checkpoint
+// Segments come from the timed source subtitles; speech and supplied text transcripts have none.
+func BuildTranscript(status *models.StatusResponse) *models.TranscriptResponse {
+	checkpoint := status.Checkpoint
+	response := &models.TranscriptResponse{
+		JobID:         status.JobID,
+		Language:      checkpoint.SourceLanguage,
+		Source:        models.TranscriptSourceSpeech,
+		Text:          checkpoint.Transcript,
+		TranscriptURL: checkpoint.TranscriptURL,
+		Offloaded:     checkpoint.TextOffloaded,
+	}
+	if status.Request != nil {
+		switch {
+		case status.Request.SubtitleURL != "":
+			response.Source = models.TranscriptSourceSubtitles
+		case status.Request.SourceText != "":
+			response.Source = models.TranscriptSourceText
+		}
+	}
+	if response.Source == models.TranscriptSourceSpeech {
+		response.Confidence = status.TranscriptConfidence
+	}
+	for _, cue := range checkpoint.Cues {
+		response.Segments = append(response.Segments, models.TranscriptSegment{Start: cue.Start, End: cue.End, Text: cue.Text})
+	}
+	return response
+}
+
+// BuildPartialTranscript returns the transcript recognized so far of a job still transcribing
+// Segments are the recognized chapters (or the whole video), before cleanup and length limits.
+func BuildPartialTranscript(status *models.StatusResponse) *models.TranscriptResponse {
+	texts := make([]string, len(status.PartialTranscript))
+	for i, segment := range status.PartialTranscript {
+		texts[i] = segment.Text
+	}
+	response := &models.TranscriptResponse{
+		JobID:    status.JobID,
+		Source:   models.TranscriptSourceSpeech,
+		Text:     strings.Join(texts, " "),
+		Segments: status.PartialTranscript,
+		Partial:  true,
+	}
+	if status.Request != nil {
+		response.Language = status.Request.SourceLanguage
+	}
+	return response
+}
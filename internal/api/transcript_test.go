@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestBuildTranscript(t *testing.T) {
+	status := newExportableJob("job-1")
+	status.TranscriptConfidence = 0.9
+	transcript := BuildTranscript(status)
+	if transcript.Text != "Hello" || transcript.Language != "en" || transcript.Source != models.TranscriptSourceSpeech {
+		t.Errorf("unexpected transcript: %+v", transcript)
+	}
+	if transcript.Confidence != 0.9 || transcript.Segments != nil {
+		t.Errorf("expected confidence and no segments for speech, got %+v", transcript)
+	}
+
+	status.Request.SubtitleURL = "gs://in/video.srt"
+	status.Checkpoint.Cues = []models.SubtitleCue{{Start: 0, End: 1.5, Text: "Hello"}}
+	transcript = BuildTranscript(status)
+	if transcript.Source != models.TranscriptSourceSubtitles || transcript.Confidence != 0 {
+		t.Errorf("expected subtitles source without confidence, got %+v", transcript)
+	}
+	want := []models.TranscriptSegment{{Start: 0, End: 1.5, Text: "Hello"}}
+	if !reflect.DeepEqual(transcript.Segments, want) {
+		t.Errorf("expected segments %v, got %v", want, transcript.Segments)
+	}
+}
+
+func TestTranscriptHandler(t *testing.T) {
+	store := newMockJobStore()
+	failed := newExportableJob("job-1")
+	failed.Status = models.StatusFailed
+	store.SetStatus("job-1", failed)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/jobs/job-1/transcript", nil)
+	w := httptest.NewRecorder()
+	TranscriptHandler(store)(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var response models.TranscriptResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.JobID != "job-1" || response.Text != "Hello" || response.TranscriptURL == "" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
+
+func TestTranscriptHandler_Offloaded(t *testing.T) {
+	store := newMockJobStore()
+	completed := newExportableJob("job-1")
+	completed.Checkpoint.Transcript = ""
+	completed.Checkpoint.TextOffloaded = true
+	store.SetStatus("job-1", completed)
+
+	w := httptest.NewRecorder()
+	TranscriptHandler(store)(w, httptest.NewRequest(http.MethodGet, "/v1/jobs/job-1/transcript", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var response models.TranscriptResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !response.Offloaded || response.Text != "" || response.TranscriptURL == "" {
+		t.Errorf("expected an offloaded transcript pointing at its URL, got %+v", response)
+	}
+}
+
+func TestTranscriptHandler_Partial(t *testing.T) {
+	store := newMockJobStore()
+	running := &models.StatusResponse{JobID: "job-1", Status: models.StatusProcessing, Request: &models.TranslateRequest{SourceLanguage: "en"}}
+	running.AddPartialTranscript(models.TranscriptSegment{Start: 300, End: 600, Text: "world"})
+	running.AddPartialTranscript(models.TranscriptSegment{Start: 0, End: 300, Text: "Hello"})
+	store.SetStatus("job-1", running)
+
+	w := httptest.NewRecorder()
+	TranscriptHandler(store)(w, httptest.NewRequest(http.MethodGet, "/v1/jobs/job-1/transcript", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var response models.TranscriptResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !response.Partial || response.Text != "Hello world" || response.Language != "en" || len(response.Segments) != 2 {
+		t.Errorf("expected the chapters recognized so far in order, got %+v", response)
+	}
+}
+
+func TestTranscriptHandler_KeepsJobUpdateTime(t *testing.T) {
+	store := NewInMemoryJobStore(time.Hour)
+	running := &models.StatusResponse{JobID: "job-1", Status: models.StatusProcessing}
+	running.AddPartialTranscript(models.TranscriptSegment{Start: 0, End: 300, Text: "Hello"})
+	store.SetStatus("job-1", running)
+	updatedAt := running.UpdatedAt
+
+	w := httptest.NewRecorder()
+	TranscriptHandler(store)(w, httptest.NewRequest(http.MethodGet, "/v1/jobs/job-1/transcript", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	// Reading the transcript must not look like progress to the stall reaper
+	if status, _ := store.GetStatus("job-1"); !status.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("expected update time %v to be kept, got %v", updatedAt, status.UpdatedAt)
+	}
+}
+
+func TestTranscriptHandler_Errors(t *testing.T) {
+	store := newMockJobStore()
+	store.SetStatus("job-running", &models.StatusResponse{JobID: "job-running", Status: models.StatusProcessing})
+	store.SetStatus("job-failed", &models.StatusResponse{JobID: "job-failed", Status: models.StatusFailed})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{"wrong method", http.MethodPost, "/v1/jobs/job-running/transcript", http.StatusMethodNotAllowed},
+		{"unknown job", http.MethodGet, "/v1/jobs/missing/transcript", http.StatusNotFound},
+		{"not transcribed yet", http.MethodGet, "/v1/jobs/job-running/transcript", http.StatusConflict},
+		{"failed before transcription", http.MethodGet, "/v1/jobs/job-failed/transcript", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			TranscriptHandler(store)(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package archive
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+)
+
+// RecordName is the name of the job record inside an export zip
+const RecordName = "job.json"
+
+// Zip writes a job record followed by its output files into a zip archive
+type Zip struct {
+	writer *zip.Writer
+}
+
+// NewZip starts a zip archive on w holding the job record as RecordName
+func NewZip(w io.Writer, record []byte) (*Zip, error) {
+	z := &Zip{writer: zip.NewWriter(w)}
+	entry, err := z.writer.Create(RecordName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add job record: %w", err)
+	}
+	if _, err := entry.Write(record); err != nil {
+		return nil, fmt.Errorf("failed to write job record: %w", err)
+	}
+	return z, nil
+}
+
+// AddFile copies a local file into the archive under name
+// Files are stored uncompressed: outputs are mostly already-compressed media.
+func (z *Zip) AddFile(name string, localPath string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer file.Close()
+
+	entry, err := z.writer.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := io.CopyBuffer(entry, file, make([]byte, 32*1024)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Close finishes the archive; it does not close the underlying writer
+func (z *Zip) Close() error {
+	if err := z.writer.Close(); err != nil {
+		return fmt.Errorf("failed to finalize zip: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestZip(t *testing.T) {
+	localPath := filepath.Join(t.TempDir(), "de.mp4")
+	if err := os.WriteFile(localPath, []byte("video"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	var buf bytes.Buffer
+	z, err := NewZip(&buf, []byte(`{"jobId":"job-1"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := z.AddFile("de/de.mp4", localPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to read zip: %v", err)
+	}
+	want := map[string]string{RecordName: `{"jobId":"job-1"}`, "de/de.mp4": "video"}
+	if len(reader.File) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(reader.File))
+	}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", file.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		if string(content) != want[file.Name] {
+			t.Errorf("expected %s to contain %q, got %q", file.Name, want[file.Name], content)
+		}
+	}
+}
+
+func TestZip_MissingFile(t *testing.T) {
+	z, err := NewZip(io.Discard, []byte("{}"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := z.AddFile("de/de.mp4", "/nonexistent/de.mp4"); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Store is a content-addressed byte store used to avoid paying providers twice for identical work
+// Implementations return found=false (and no error) on a cache miss.
+type Store interface {
+	Get(ctx context.Context, key string) (data []byte, found bool, err error)
+	Set(ctx context.Context, key string, data []byte) error
+}
+
+// Key derives a cache key from its parts (e.g., provider, language, voice, text)
+// Parts are separated unambiguously before hashing, so ("ab", "c") and ("a", "bc") differ.
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// memoryStore is an in-memory Store for tests
+type memoryStore struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{entries: make(map[string][]byte)}
+}
+
+func (m *memoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, found := m.entries[key]
+	return data, found, nil
+}
+
+func (m *memoryStore) Set(ctx context.Context, key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = data
+	return nil
+}
+
+func TestKey(t *testing.T) {
+	if Key("a", "b") != Key("a", "b") {
+		t.Error("expected identical parts to produce identical keys")
+	}
+	if Key("ab", "c") == Key("a", "bc") {
+		t.Error("expected part boundaries to change the key")
+	}
+	if len(Key("x")) != 64 {
+		t.Errorf("expected hex SHA-256 key, got %q", Key("x"))
+	}
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sinouw/multilingual-video-processor/internal/storage"
+)
+
+// objectStore is the subset of storage.GCSStorage used by GCSStore
+type objectStore interface {
+	ReadBytes(ctx context.Context, bucket, path string) ([]byte, error)
+	UploadBytes(ctx context.Context, bucket, path string, data []byte, contentType string) error
+}
+
+// GCSStore keeps cache entries as objects under a bucket prefix
+// Expiry is left to the bucket's lifecycle rules.
+type GCSStore struct {
+	objects objectStore
+	bucket  string
+	prefix  string
+}
+
+// NewGCSStore creates a cache stored in bucket under prefix (e.g., "cache/")
+func NewGCSStore(objects objectStore, bucket string, prefix string) *GCSStore {
+	return &GCSStore{
+		objects: objects,
+		bucket:  bucket,
+		prefix:  prefix,
+	}
+}
+
+// Get implements Store interface
+func (s *GCSStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := s.objects.ReadBytes(ctx, s.bucket, s.prefix+key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Set implements Store interface
+func (s *GCSStore) Set(ctx context.Context, key string, data []byte) error {
+	return s.objects.UploadBytes(ctx, s.bucket, s.prefix+key, data, "application/octet-stream")
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/internal/storage"
+)
+
+// fakeObjectStore keeps objects in memory keyed by bucket/path
+type fakeObjectStore struct {
+	objects map[string][]byte
+	readErr error
+}
+
+func (f *fakeObjectStore) ReadBytes(ctx context.Context, bucket, path string) ([]byte, error) {
+	if f.readErr != nil {
+		return nil, f.readErr
+	}
+	data, exists := f.objects[bucket+"/"+path]
+	if !exists {
+		return nil, storage.ErrObjectNotFound
+	}
+	return data, nil
+}
+
+func (f *fakeObjectStore) UploadBytes(ctx context.Context, bucket, path string, data []byte, contentType string) error {
+	f.objects[bucket+"/"+path] = data
+	return nil
+}
+
+func TestGCSStore(t *testing.T) {
+	objects := &fakeObjectStore{objects: make(map[string][]byte)}
+	store := NewGCSStore(objects, "bucket", "cache/")
+
+	if _, found, err := store.Get(context.Background(), "k"); found || err != nil {
+		t.Errorf("expected clean miss, got found=%v err=%v", found, err)
+	}
+
+	if err := store.Set(context.Background(), "k", []byte("v")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, exists := objects.objects["bucket/cache/k"]; !exists {
+		t.Error("expected entry stored under the prefix")
+	}
+
+	data, found, err := store.Get(context.Background(), "k")
+	if !found || err != nil || string(data) != "v" {
+		t.Errorf("expected hit with 'v', got %q found=%v err=%v", data, found, err)
+	}
+
+	objects.readErr = errors.New("permission denied")
+	if _, _, err := store.Get(context.Background(), "k"); err == nil {
+		t.Error("expected read errors other than not-found to be returned")
+	}
+}
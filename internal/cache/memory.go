@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// memoryConcurrency bounds the entries read or written at once in stores without batch operations
+const memoryConcurrency = 16
+
+// BatchStore is implemented by stores that read and write several entries in one round trip
+type BatchStore interface {
+	// GetMany returns the entries of keys in order, nil for missing ones
+	GetMany(ctx context.Context, keys []string) ([][]byte, error)
+	// SetMany stores entries by key
+	SetMany(ctx context.Context, entries map[string][]byte) error
+}
+
+// TranslationMemory is a translation.MemoryStore keeping one entry per translated sentence in a Store
+// It counts lookups and hits across all jobs of the instance for the hit-rate metric.
+type TranslationMemory struct {
+	store   Store
+	lookups atomic.Int64
+	hits    atomic.Int64
+}
+
+// NewTranslationMemory creates a translation memory persisted in store
+func NewTranslationMemory(store Store) *TranslationMemory {
+	return &TranslationMemory{store: store}
+}
+
+// Lookup implements translation.MemoryStore
+// Stores implementing BatchStore are read in one round trip; others are read with bounded parallelism.
+// Sentences whose entry cannot be read are reported as missing; the read errors are returned with the others.
+func (m *TranslationMemory) Lookup(ctx context.Context, namespace string, sourceLanguage string, targetLanguage string, sentences []string) (map[string]string, error) {
+	keys := make([]string, len(sentences))
+	for i, sentence := range sentences {
+		keys[i] = memoryKey(namespace, sourceLanguage, targetLanguage, sentence)
+	}
+
+	var values [][]byte
+	var err error
+	if batch, ok := m.store.(BatchStore); ok {
+		values, err = batch.GetMany(ctx, keys)
+	} else {
+		values, err = m.getParallel(ctx, keys)
+	}
+
+	found := make(map[string]string)
+	for i, value := range values {
+		if value != nil {
+			found[sentences[i]] = string(value)
+		}
+	}
+	m.lookups.Add(int64(len(sentences)))
+	m.hits.Add(int64(len(found)))
+	return found, err
+}
+
+// Save implements translation.MemoryStore
+func (m *TranslationMemory) Save(ctx context.Context, namespace string, sourceLanguage string, targetLanguage string, translations map[string]string) error {
+	entries := make(map[string][]byte, len(translations))
+	for sentence, translation := range translations {
+		entries[memoryKey(namespace, sourceLanguage, targetLanguage, sentence)] = []byte(translation)
+	}
+	if batch, ok := m.store.(BatchStore); ok {
+		return batch.SetMany(ctx, entries)
+	}
+
+	var mu sync.Mutex
+	var errs []error
+	var group errgroup.Group
+	group.SetLimit(memoryConcurrency)
+	for key, data := range entries {
+		group.Go(func() error {
+			if err := m.store.Set(ctx, key, data); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	group.Wait()
+	return errors.Join(errs...)
+}
+
+// Stats returns how many sentences were looked up since the instance started and how many were found
+func (m *TranslationMemory) Stats() (lookups int64, hits int64) {
+	return m.lookups.Load(), m.hits.Load()
+}
+
+// getParallel reads keys with at most memoryConcurrency reads at once, leaving missing and unreadable entries nil
+func (m *TranslationMemory) getParallel(ctx context.Context, keys []string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	var mu sync.Mutex
+	var errs []error
+	var group errgroup.Group
+	group.SetLimit(memoryConcurrency)
+	for i, key := range keys {
+		group.Go(func() error {
+			data, ok, err := m.store.Get(ctx, key)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+				return nil
+			}
+			if ok {
+				values[i] = data
+			}
+			return nil
+		})
+	}
+	group.Wait()
+	return values, errors.Join(errs...)
+}
+
+func memoryKey(namespace string, sourceLanguage string, targetLanguage string, sentence string) string {
+	return Key("memory", namespace, sourceLanguage, targetLanguage, sentence)
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTranslationMemory(t *testing.T) {
+	memory := NewTranslationMemory(newMemoryStore())
+	ctx := context.Background()
+
+	if err := memory.Save(ctx, "tenant-a", "en", "de", map[string]string{"Hello.": "Hallo.", "Goodbye.": "Auf Wiedersehen."}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found, err := memory.Lookup(ctx, "tenant-a", "en", "de", []string{"Hello.", "New sentence.", "Goodbye."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"Hello.": "Hallo.", "Goodbye.": "Auf Wiedersehen."}
+	if !reflect.DeepEqual(found, want) {
+		t.Errorf("expected %v, got %v", want, found)
+	}
+
+	// Entries are kept per tenant and language pair
+	if found, _ := memory.Lookup(ctx, "tenant-b", "en", "de", []string{"Hello."}); len(found) != 0 {
+		t.Errorf("expected no entries for another tenant, got %v", found)
+	}
+	if found, _ := memory.Lookup(ctx, "tenant-a", "en", "ru", []string{"Hello."}); len(found) != 0 {
+		t.Errorf("expected no entries for another target language, got %v", found)
+	}
+
+	if lookups, hits := memory.Stats(); lookups != 5 || hits != 2 {
+		t.Errorf("expected 5 lookups and 2 hits, got %d and %d", lookups, hits)
+	}
+}
+
+// batchStore is a memoryStore counting its batch operations
+type batchStore struct {
+	*memoryStore
+	gets int
+	sets int
+}
+
+func (b *batchStore) GetMany(ctx context.Context, keys []string) ([][]byte, error) {
+	b.gets++
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i], _, _ = b.Get(ctx, key)
+	}
+	return values, nil
+}
+
+func (b *batchStore) SetMany(ctx context.Context, entries map[string][]byte) error {
+	b.sets++
+	for key, data := range entries {
+		b.Set(ctx, key, data)
+	}
+	return nil
+}
+
+func TestTranslationMemory_BatchStore(t *testing.T) {
+	store := &batchStore{memoryStore: newMemoryStore()}
+	memory := NewTranslationMemory(store)
+	ctx := context.Background()
+
+	if err := memory.Save(ctx, "tenant-a", "en", "de", map[string]string{"Hello.": "Hallo.", "Goodbye.": "Auf Wiedersehen."}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found, err := memory.Lookup(ctx, "tenant-a", "en", "de", []string{"Hello.", "New sentence.", "Goodbye."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(found) != 2 || found["Goodbye."] != "Auf Wiedersehen." {
+		t.Errorf("unexpected lookup result %v", found)
+	}
+	if store.gets != 1 || store.sets != 1 {
+		t.Errorf("expected one batch read and one batch write, got %d and %d", store.gets, store.sets)
+	}
+}
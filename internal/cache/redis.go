@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps cache entries in Redis with a fixed TTL
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisStore connects to the Redis server at url (redis://[:password@]host:port/db)
+// Entries expire after ttl; zero keeps them until evicted by Redis.
+func NewRedisStore(url string, prefix string, ttl time.Duration) (*RedisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	return &RedisStore{
+		client: redis.NewClient(opts),
+		prefix: prefix,
+		ttl:    ttl,
+	}, nil
+}
+
+// Get implements Store interface
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("redis get failed: %w", err)
+	}
+	return data, true, nil
+}
+
+// Set implements Store interface
+func (s *RedisStore) Set(ctx context.Context, key string, data []byte) error {
+	if err := s.client.Set(ctx, s.prefix+key, data, s.ttl).Err(); err != nil {
+		return fmt.Errorf("redis set failed: %w", err)
+	}
+	return nil
+}
+
+// GetMany implements BatchStore with a single MGET
+func (s *RedisStore) GetMany(ctx context.Context, keys []string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = s.prefix + key
+	}
+	results, err := s.client.MGet(ctx, prefixed...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis mget failed: %w", err)
+	}
+
+	values := make([][]byte, len(keys))
+	for i, result := range results {
+		if value, ok := result.(string); ok {
+			values[i] = []byte(value)
+		}
+	}
+	return values, nil
+}
+
+// SetMany implements BatchStore with a single pipelined round trip
+func (s *RedisStore) SetMany(ctx context.Context, entries map[string][]byte) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	pipe := s.client.Pipeline()
+	for key, data := range entries {
+		pipe.Set(ctx, s.prefix+key, data, s.ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis set failed: %w", err)
+	}
+	return nil
+}
+
+// Close closes the Redis connection pool
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
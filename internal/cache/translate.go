@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sinouw/multilingual-video-processor/internal/translation"
+)
+
+// BatchTranslate wraps a batch translation function so texts already translated are served from the store
+// namespace must identify everything else that changes the output (provider, style instructions).
+// Cache failures are logged and never fail the translation.
+func BatchTranslate(store Store, namespace string, translate translation.BatchTranslateFunc) translation.BatchTranslateFunc {
+	return func(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+		results := make([]string, len(texts))
+		keys := make([]string, len(texts))
+		var missing []int
+		for i, text := range texts {
+			keys[i] = Key("translation", namespace, sourceLanguage, targetLanguage, text)
+			data, found, err := store.Get(ctx, keys[i])
+			if err != nil {
+				slog.Warn("Translation cache read failed", "error", err, "targetLanguage", targetLanguage)
+			}
+			if found {
+				results[i] = string(data)
+				continue
+			}
+			missing = append(missing, i)
+		}
+
+		slog.Info("Translation cache lookup", "targetLanguage", targetLanguage, "hits", len(texts)-len(missing), "misses", len(missing))
+		if len(missing) == 0 {
+			return results, nil
+		}
+
+		pending := make([]string, len(missing))
+		for j, i := range missing {
+			pending[j] = texts[i]
+		}
+		translated, err := translate(ctx, pending, sourceLanguage, targetLanguage)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, i := range missing {
+			results[i] = translated[j]
+			if err := store.Set(ctx, keys[i], []byte(translated[j])); err != nil {
+				slog.Warn("Translation cache write failed", "error", err, "targetLanguage", targetLanguage)
+			}
+		}
+		return results, nil
+	}
+}
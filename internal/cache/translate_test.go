@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBatchTranslate(t *testing.T) {
+	store := newMemoryStore()
+	var calls [][]string
+	translate := func(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+		calls = append(calls, texts)
+		out := make([]string, len(texts))
+		for i, text := range texts {
+			out[i] = strings.ToUpper(text)
+		}
+		return out, nil
+	}
+
+	cached := BatchTranslate(store, "google-translate", translate)
+
+	first, err := cached(context.Background(), []string{"hello", "world"}, "en", "de")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(first, []string{"HELLO", "WORLD"}) {
+		t.Errorf("unexpected translations: %v", first)
+	}
+
+	second, err := cached(context.Background(), []string{"world", "again"}, "en", "de")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(second, []string{"WORLD", "AGAIN"}) {
+		t.Errorf("unexpected translations: %v", second)
+	}
+
+	if len(calls) != 2 || !reflect.DeepEqual(calls[1], []string{"again"}) {
+		t.Errorf("expected only the uncached text to be translated, got calls %v", calls)
+	}
+
+	// A different namespace (e.g., other style instructions) must not share entries
+	other := BatchTranslate(store, "openai:formal", translate)
+	if _, err := other(context.Background(), []string{"hello"}, "en", "de"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calls) != 3 {
+		t.Errorf("expected a provider call for a new namespace, got %d calls", len(calls))
+	}
+}
+
+func TestBatchTranslate_ProviderError(t *testing.T) {
+	store := newMemoryStore()
+	translate := func(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+		return nil, errors.New("quota exceeded")
+	}
+
+	if _, err := BatchTranslate(store, "ns", translate)(context.Background(), []string{"hello"}, "en", "de"); err == nil {
+		t.Error("expected provider error to be returned")
+	}
+	if len(store.entries) != 0 {
+		t.Errorf("expected nothing cached on error, got %d entries", len(store.entries))
+	}
+}
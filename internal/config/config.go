@@ -3,10 +3,18 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"math"
+	"net"
 	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
 )
 
 // Config holds all configuration for the application
@@ -21,15 +29,169 @@ type Config struct {
 	MaxVideoSizeMB            int
 	MaxConcurrentJobs         int
 	MaxConcurrentTranslations int
+	MaxConcurrentFFmpeg       int
+	MaxConcurrentAPICalls     int
 	RequestTimeout            time.Duration
 	LogLevel                  string
 	APIVersion                string
 	EnableHealthCheck         bool
 	RateLimitRPM              int
+	RateLimitStatusRPM        int
 	WebhookURL                string
+	WebhookEvents             []string
+	WebhookMinProgressDelta   int
 	CORSOrigins               []string
 	JobTTL                    time.Duration
 	MaxRequestBodySize        int64
+	EmailProvider             string
+	EmailFrom                 string
+	SMTPHost                  string
+	SMTPPort                  int
+	SMTPUsername              string
+	SMTPPassword              string
+	SendGridAPIKey            string
+	PubSubTopic               string
+	SlackWebhookURL           string
+	STTConfidenceWarning      float64
+	STTMinConfidence          float64
+	STTMinSpeechRatio         float64
+	STTModel                  string
+	STTAutomaticPunctuation   bool
+	STTAlternativeLanguages   []string
+	STTAudioChannel           int
+	STTSampleRate             int
+	ProfanityWords            []string
+	TranslationProvider       string
+	TranslationFallbacks      []string
+	TranslationRoutes         map[string][]string
+	DeepLAPIKey               string
+	LLMAPIKey                 string
+	LLMModel                  string
+	CacheBackend              string
+	CacheBucket               string
+	RedisURL                  string
+	CacheTTL                  time.Duration
+	MaxTargetLanguages        int
+	MaxVideoURLLength         int
+	DuplicateJobWindow        time.Duration
+	APIKeys                   []string
+	AdminAPIKeys              []string
+	OutputDestinations        map[string]string
+	CloudTasksQueue           string
+	CloudTasksTargetURL       string
+	CloudTasksToken           string
+	TransientRetryMaxAttempts int
+	TransientRetryDelay       time.Duration
+	GCSParallelThresholdMB    int
+	GCSPartSizeMB             int
+	GCSTransferConcurrency    int
+	ExportBucket              string
+	CostCurrency              string
+	CostSTTPerMinute          float64
+	CostTranslatePerMillion   map[string]float64
+	CostTTSPerMillion         float64
+	CostStoragePerGB          float64
+	DevMockProviders          bool
+	DevStorageDir             string
+	GoogleTranslateEndpoint   string
+	SpeechEndpoint            string
+	TTSEndpoint               string
+	MaxTranscriptChars        int
+	TranscriptLimitPolicy     string
+	DubbingDurationTolerance  float64
+	DubbingCorrection         string
+	AudioSeparation           string
+	AudioSeparationCommand    string
+	AudioSeparationEndpoint   string
+	AudioSeparationModel      string
+	KeepBackgroundMusic       bool
+	BackgroundMusicVolume     float64
+	VideoRotation             string
+	OutputTitleTemplate       string
+	WatermarkURL              string
+	WatermarkPosition         string
+	WatermarkOpacity          float64
+	IntroURL                  string
+	OutroURL                  string
+	RateLimitTiers            map[string]models.RateLimitTier
+	APIKeyTiers               map[string]string
+	WebhookTimeout            time.Duration
+	WebhookMaxRetries         int
+	WebhookBackoff            time.Duration
+	WebhookConcurrency        int
+	SameLanguagePolicy        string
+	OutputAudioSampleRate     int
+	OutputAudioBitrate        int
+	OutputAudioChannels       string
+	DubbedAudioFormat         string
+	GeminiPipeline            bool
+	GeminiProject             string
+	GeminiLocation            string
+	GeminiModel               string
+	GeminiMaxDuration         time.Duration
+	DiskSpacePolicy           string
+	DiskSpaceFactor           float64
+	DiskSpaceHeadroomMB       int
+	PreviewPage               bool
+	MaxInstanceJobs           int
+	JobMemoryMB               int
+	CPUAlwaysAllocated        bool
+	StalledJobFactor          float64
+	ReplicaDestinations       []string
+	TranscriptCleanup         string
+	TextProcessors            []string
+	GCSBillingProject         string
+	GCSRequesterPaysBuckets   []string
+	GCSBucketCredentials      map[string]string
+	APIKeyServiceAccounts     map[string]string
+	ImpersonationAccounts     []string
+	FFmpegThreads             int
+	FFmpegMemoryMB            int
+	FFmpegNice                int
+	TranscodeBackend          string
+	TranscodeEndpoint         string
+	TranscodeProject          string
+	TranscodeLocation         string
+	TranscodeBucket           string
+	TranscodeMinHeight        int
+	ChapterDuration           time.Duration
+	MaxChapteredDuration      time.Duration
+	OutboundProxy             string
+	OutboundUserAgent         string
+	IPAllowlist               []string
+	IPDenylist                []string
+	IPFilterProxyHops         int
+	TTSSilenceTrim            bool
+	TTSSilenceThreshold       float64
+	TTSMaxPause               time.Duration
+	SpeechAcronyms            map[string]map[string]string
+	TenantNamespaces          bool
+	TenantBuckets             map[string]string
+	ElevenLabsAPIKey          string
+	ElevenLabsModel           string
+	AllowedOutputBuckets      map[string][]string
+	ResultReuseWindow         time.Duration
+	MaxInFlightJobsPerClient  int
+	SubtitleProfile           string
+	SubtitleProfiles          map[string]models.SubtitleProfile
+	SubtitleOffset            float64
+	SpendBudgetWindow         time.Duration
+	SpendBudgetCost           float64
+	SpendBudgetSTTSeconds     float64
+	SpendBudgetTranslateChars int64
+	SpendBudgetTTSChars       int64
+	GCSCacheControl           string
+	GCSStorageClass           string
+	GCSObjectMetadata         map[string]string
+	TranslationMemoryBackend  string
+	TranslationMemoryBucket   string
+	SummaryMaxChars           int
+	JobStoreMaxEntries        int
+	JobStoreCompact           bool
+	TextOffloadThreshold      int
+	TTSRoutes                 map[string][]string
+	WebhookQueueSize          int
+	LLMMaxInputChars          int
 }
 
 // LoadConfig loads configuration from environment variables with defaults
@@ -45,15 +207,187 @@ func LoadConfig() (*Config, error) {
 		MaxVideoSizeMB:            parseInt(getEnv("MAX_VIDEO_SIZE_MB", "500")),
 		MaxConcurrentJobs:         parseInt(getEnv("MAX_CONCURRENT_JOBS", "10")),
 		MaxConcurrentTranslations: parseInt(getEnv("MAX_CONCURRENT_TRANSLATIONS", "3")),
+		MaxConcurrentFFmpeg:       parseInt(getEnv("MAX_CONCURRENT_FFMPEG", strconv.Itoa(runtime.NumCPU()))),
+		MaxConcurrentAPICalls:     parseInt(getEnv("MAX_CONCURRENT_API_CALLS", "16")),
 		RequestTimeout:            parseDuration(getEnv("REQUEST_TIMEOUT", "540")),
 		LogLevel:                  getEnv("LOG_LEVEL", "info"),
 		APIVersion:                getEnv("API_VERSION", "v1"),
 		EnableHealthCheck:         parseBool(getEnv("ENABLE_HEALTH_CHECK", "true")),
 		RateLimitRPM:              parseInt(getEnv("RATE_LIMIT_RPM", "60")),
+		RateLimitStatusRPM:        parseInt(getEnv("RATE_LIMIT_STATUS_RPM", "600")),
 		WebhookURL:                getEnv("WEBHOOK_URL", ""),
+		WebhookEvents:             parseStringSlice(getEnv("WEBHOOK_EVENTS", strings.Join(models.DefaultWebhookEvents, ","))),
+		WebhookMinProgressDelta:   parseInt(getEnv("WEBHOOK_MIN_PROGRESS_DELTA", "10")),
 		CORSOrigins:               parseStringSlice(getEnv("CORS_ORIGINS", "*")),
 		JobTTL:                    parseDurationString(getEnv("JOB_TTL", "24h")),
 		MaxRequestBodySize:        parseInt64(getEnv("MAX_REQUEST_BODY_SIZE_BYTES", "1048576")),
+		EmailProvider:             strings.ToLower(getEnv("EMAIL_PROVIDER", "")),
+		EmailFrom:                 getEnv("EMAIL_FROM", ""),
+		SMTPHost:                  getEnv("SMTP_HOST", ""),
+		SMTPPort:                  parseInt(getEnv("SMTP_PORT", "587")),
+		SMTPUsername:              getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:              getEnv("SMTP_PASSWORD", ""),
+		SendGridAPIKey:            getEnv("SENDGRID_API_KEY", ""),
+		PubSubTopic:               getEnv("PUBSUB_NOTIFY_TOPIC", ""),
+		SlackWebhookURL:           getEnv("SLACK_WEBHOOK_URL", ""),
+		STTConfidenceWarning:      parseFloat(getEnv("STT_CONFIDENCE_WARNING", "0.6")),
+		STTMinConfidence:          parseFloat(getEnv("STT_MIN_CONFIDENCE", "0")),
+		STTMinSpeechRatio:         parseFloat(getEnv("STT_MIN_SPEECH_RATIO", "0.02")),
+		STTModel:                  getEnv("STT_MODEL", ""),
+		STTAutomaticPunctuation:   parseBool(getEnv("STT_AUTOMATIC_PUNCTUATION", "false")),
+		STTAlternativeLanguages:   parseStringSlice(getEnv("STT_ALTERNATIVE_LANGUAGES", "")),
+		STTAudioChannel:           parseInt(getEnv("STT_AUDIO_CHANNEL", "0")),
+		STTSampleRate:             parseInt(getEnv("STT_SAMPLE_RATE", "16000")),
+		ProfanityWords:            parseStringSlice(getEnv("PROFANITY_WORDS", "")),
+		TranslationProvider:       strings.ToLower(getEnv("TRANSLATION_PROVIDER", "google")),
+		TranslationFallbacks:      parseStringSlice(strings.ToLower(getEnv("TRANSLATION_FALLBACK_PROVIDERS", ""))),
+		TranslationRoutes:         parseProviderRoutes(strings.ToLower(getEnv("TRANSLATION_ROUTES", ""))),
+		DeepLAPIKey:               getEnv("DEEPL_API_KEY", ""),
+		LLMAPIKey:                 getEnv("LLM_API_KEY", ""),
+		LLMModel:                  getEnv("LLM_MODEL", ""),
+		CacheBackend:              strings.ToLower(getEnv("CACHE_BACKEND", "")),
+		CacheBucket:               getEnv("CACHE_GCS_BUCKET", ""),
+		RedisURL:                  getEnv("REDIS_URL", ""),
+		CacheTTL:                  parseDurationString(getEnv("CACHE_TTL", "720h")),
+		MaxTargetLanguages:        parseInt(getEnv("MAX_TARGET_LANGUAGES", "10")),
+		MaxVideoURLLength:         parseInt(getEnv("MAX_VIDEO_URL_LENGTH", "2048")),
+		DuplicateJobWindow:        parseDurationString(getEnv("DUPLICATE_JOB_WINDOW", "10m")),
+		APIKeys:                   parseStringSlice(getEnv("API_KEYS", "")),
+		AdminAPIKeys:              parseStringSlice(getEnv("ADMIN_API_KEYS", "")),
+		OutputDestinations:        parseStringMap(getEnv("OUTPUT_DESTINATIONS", "")),
+		CloudTasksQueue:           getEnv("CLOUD_TASKS_QUEUE", ""),
+		CloudTasksTargetURL:       getEnv("CLOUD_TASKS_TARGET_URL", ""),
+		CloudTasksToken:           getEnv("CLOUD_TASKS_TOKEN", ""),
+		TransientRetryMaxAttempts: parseInt(getEnv("TRANSIENT_RETRY_MAX_ATTEMPTS", "3")),
+		TransientRetryDelay:       parseDurationString(getEnv("TRANSIENT_RETRY_DELAY", "1m")),
+		GCSParallelThresholdMB:    parseInt(getEnv("GCS_PARALLEL_THRESHOLD_MB", "100")),
+		GCSPartSizeMB:             parseInt(getEnv("GCS_PART_SIZE_MB", "16")),
+		GCSTransferConcurrency:    parseInt(getEnv("GCS_TRANSFER_CONCURRENCY", "8")),
+		ExportBucket:              getEnv("EXPORT_BUCKET", ""),
+		CostCurrency:              getEnv("COST_CURRENCY", "USD"),
+		CostSTTPerMinute:          parseFloat(getEnv("COST_STT_PER_MINUTE", "0.016")),
+		CostTranslatePerMillion:   parseFloatMap(strings.ToLower(getEnv("COST_TRANSLATE_PER_MILLION_CHARS", "google=20,deepl=25"))),
+		CostTTSPerMillion:         parseFloat(getEnv("COST_TTS_PER_MILLION_CHARS", "16")),
+		CostStoragePerGB:          parseFloat(getEnv("COST_STORAGE_PER_GB", "0.12")),
+		DevMockProviders:          parseBool(getEnv("DEV_MOCK_PROVIDERS", "false")),
+		DevStorageDir:             getEnv("DEV_STORAGE_DIR", filepath.Join(os.TempDir(), "mvp-storage")),
+		GoogleTranslateEndpoint:   getEnv("GOOGLE_TRANSLATE_ENDPOINT", ""),
+		SpeechEndpoint:            getEnv("SPEECH_ENDPOINT", ""),
+		TTSEndpoint:               getEnv("TTS_ENDPOINT", ""),
+		MaxTranscriptChars:        parseInt(getEnv("MAX_TRANSCRIPT_CHARS", "100000")),
+		TranscriptLimitPolicy:     strings.ToLower(getEnv("TRANSCRIPT_LIMIT_POLICY", "fail")),
+		DubbingDurationTolerance:  parseFloat(getEnv("DUBBING_DURATION_TOLERANCE", "0.05")),
+		DubbingCorrection:         strings.ToLower(getEnv("DUBBING_CORRECTION", "off")),
+		AudioSeparation:           strings.ToLower(getEnv("AUDIO_SEPARATION", "")),
+		AudioSeparationCommand:    getEnv("AUDIO_SEPARATION_COMMAND", ""),
+		AudioSeparationEndpoint:   getEnv("AUDIO_SEPARATION_ENDPOINT", ""),
+		AudioSeparationModel:      getEnv("AUDIO_SEPARATION_MODEL", ""),
+		KeepBackgroundMusic:       parseBool(getEnv("KEEP_BACKGROUND_MUSIC", "false")),
+		BackgroundMusicVolume:     parseFloat(getEnv("BACKGROUND_MUSIC_VOLUME", "1")),
+		VideoRotation:             strings.ToLower(getEnv("VIDEO_ROTATION", "preserve")),
+		OutputTitleTemplate:       getEnv("OUTPUT_TITLE_TEMPLATE", "{title} ({language} dub)"),
+		WatermarkURL:              getEnv("WATERMARK_URL", ""),
+		WatermarkPosition:         strings.ToLower(getEnv("WATERMARK_POSITION", models.WatermarkBottomRight)),
+		WatermarkOpacity:          parseFloat(getEnv("WATERMARK_OPACITY", "1")),
+		IntroURL:                  getEnv("INTRO_URL", ""),
+		OutroURL:                  getEnv("OUTRO_URL", ""),
+		RateLimitTiers:            parseRateLimitTiers(getEnv("RATE_LIMIT_TIERS", "")),
+		APIKeyTiers:               parseStringMap(getEnv("API_KEY_TIERS", "")),
+		WebhookTimeout:            parseDurationString(getEnv("WEBHOOK_TIMEOUT", "5s")),
+		WebhookMaxRetries:         parseInt(getEnv("WEBHOOK_MAX_RETRIES", "1")),
+		WebhookBackoff:            parseDurationString(getEnv("WEBHOOK_BACKOFF", "1s")),
+		WebhookConcurrency:        parseInt(getEnv("WEBHOOK_CONCURRENCY", "8")),
+		SameLanguagePolicy:        strings.ToLower(getEnv("SAME_LANGUAGE_POLICY", "passthrough")),
+		OutputAudioSampleRate:     parseInt(getEnv("OUTPUT_AUDIO_SAMPLE_RATE", "48000")),
+		OutputAudioBitrate:        parseInt(getEnv("OUTPUT_AUDIO_BITRATE", "192")),
+		OutputAudioChannels:       strings.ToLower(getEnv("OUTPUT_AUDIO_CHANNELS", models.ChannelLayoutStereo)),
+		DubbedAudioFormat:         strings.ToLower(getEnv("DUBBED_AUDIO_FORMAT", "")),
+		GeminiPipeline:            parseBool(getEnv("GEMINI_PIPELINE", "false")),
+		GeminiProject:             getEnv("GEMINI_PROJECT", os.Getenv("GOOGLE_CLOUD_PROJECT")),
+		GeminiLocation:            getEnv("GEMINI_LOCATION", "us-central1"),
+		GeminiModel:               getEnv("GEMINI_MODEL", ""),
+		GeminiMaxDuration:         parseDurationString(getEnv("GEMINI_MAX_DURATION", "2m")),
+		DiskSpacePolicy:           strings.ToLower(getEnv("DISK_SPACE_POLICY", "queue")),
+		DiskSpaceFactor:           parseFloat(getEnv("DISK_SPACE_FACTOR", "3")),
+		DiskSpaceHeadroomMB:       parseInt(getEnv("DISK_SPACE_HEADROOM_MB", "256")),
+		PreviewPage:               parseBool(getEnv("PREVIEW_PAGE", "false")),
+		MaxInstanceJobs:           parseInstanceJobs(getEnv("MAX_INSTANCE_JOBS", "0")),
+		JobMemoryMB:               parseInt(getEnv("JOB_MEMORY_MB", "1024")),
+		CPUAlwaysAllocated:        parseBool(getEnv("CPU_ALWAYS_ALLOCATED", "true")),
+		StalledJobFactor:          parseFloat(getEnv("STALLED_JOB_FACTOR", "2")),
+		ReplicaDestinations:       parseStringSlice(getEnv("REPLICA_DESTINATIONS", "")),
+		TranscriptCleanup:         strings.ToLower(getEnv("TRANSCRIPT_CLEANUP", "none")),
+		TextProcessors:            parseStringSlice(strings.ToLower(getEnv("TEXT_PROCESSORS", ""))),
+		GCSBillingProject:         getEnv("GCS_BILLING_PROJECT", ""),
+		GCSRequesterPaysBuckets:   parseStringSlice(getEnv("GCS_REQUESTER_PAYS_BUCKETS", "")),
+		GCSBucketCredentials:      parseStringMap(getEnv("GCS_BUCKET_CREDENTIALS", "")),
+		APIKeyServiceAccounts:     parseStringMap(getEnv("API_KEY_SERVICE_ACCOUNTS", "")),
+		ImpersonationAccounts:     parseStringSlice(getEnv("IMPERSONATION_SERVICE_ACCOUNTS", "")),
+		FFmpegThreads:             parseInt(getEnv("FFMPEG_THREADS", "0")),
+		FFmpegMemoryMB:            parseInt(getEnv("FFMPEG_MEMORY_MB", "0")),
+		FFmpegNice:                parseInt(getEnv("FFMPEG_NICE", "0")),
+		TranscodeBackend:          strings.ToLower(getEnv("TRANSCODE_BACKEND", "")),
+		TranscodeEndpoint:         getEnv("TRANSCODE_ENDPOINT", ""),
+		TranscodeProject:          getEnv("TRANSCODE_PROJECT", os.Getenv("GOOGLE_CLOUD_PROJECT")),
+		TranscodeLocation:         getEnv("TRANSCODE_LOCATION", "us-central1"),
+		TranscodeBucket:           getEnv("TRANSCODE_BUCKET", ""),
+		TranscodeMinHeight:        parseInt(getEnv("TRANSCODE_MIN_HEIGHT", "2160")),
+		ChapterDuration:           parseDuration(getEnv("CHAPTER_DURATION", "0")),
+		MaxChapteredDuration:      parseDuration(getEnv("MAX_CHAPTERED_VIDEO_DURATION", "7200")),
+		OutboundProxy:             getEnv("OUTBOUND_PROXY", ""),
+		OutboundUserAgent:         getEnv("OUTBOUND_USER_AGENT", ""),
+		IPAllowlist:               parseStringSlice(getEnv("IP_ALLOWLIST", "")),
+		IPDenylist:                parseStringSlice(getEnv("IP_DENYLIST", "")),
+		IPFilterProxyHops:         parseInt(getEnv("IP_FILTER_PROXY_HOPS", "1")),
+		TTSSilenceTrim:            parseBool(getEnv("TTS_SILENCE_TRIM", "false")),
+		TTSSilenceThreshold:       parseFloat(getEnv("TTS_SILENCE_THRESHOLD_DB", "-50")),
+		TTSMaxPause:               parseDurationString(getEnv("TTS_MAX_PAUSE", "1s")),
+		SpeechAcronyms:            parseAcronyms(getEnv("SPEECH_ACRONYMS", "")),
+		TenantNamespaces:          parseBool(getEnv("TENANT_NAMESPACES", strconv.FormatBool(getEnv("API_KEYS", "") != "" || getEnv("ADMIN_API_KEYS", "") != ""))),
+		TenantBuckets:             parseStringMap(getEnv("TENANT_BUCKETS", "")),
+		ElevenLabsAPIKey:          getEnv("ELEVENLABS_API_KEY", ""),
+		ElevenLabsModel:           getEnv("ELEVENLABS_MODEL", "eleven_multilingual_v2"),
+		AllowedOutputBuckets:      parseOwnerLists(getEnv("ALLOWED_OUTPUT_BUCKETS", "")),
+		ResultReuseWindow:         parseDurationString(getEnv("RESULT_REUSE_WINDOW", "0")),
+		MaxInFlightJobsPerClient:  parseInt(getEnv("MAX_INFLIGHT_JOBS_PER_CLIENT", "0")),
+		SubtitleProfile:           getEnv("SUBTITLE_PROFILE", ""),
+		SubtitleProfiles:          parseSubtitleProfiles(getEnv("SUBTITLE_PROFILES", "")),
+		SubtitleOffset:            parseFloat(getEnv("SUBTITLE_OFFSET", "0")),
+		SpendBudgetWindow:         parseDurationString(getEnv("SPEND_BUDGET_WINDOW", "24h")),
+		SpendBudgetCost:           parseFloat(getEnv("SPEND_BUDGET_COST", "0")),
+		SpendBudgetSTTSeconds:     parseFloat(getEnv("SPEND_BUDGET_STT_SECONDS", "0")),
+		SpendBudgetTranslateChars: parseInt64(getEnv("SPEND_BUDGET_TRANSLATE_CHARS", "0")),
+		SpendBudgetTTSChars:       parseInt64(getEnv("SPEND_BUDGET_TTS_CHARS", "0")),
+		GCSCacheControl:           getEnv("GCS_CACHE_CONTROL", ""),
+		GCSStorageClass:           strings.ToUpper(getEnv("GCS_STORAGE_CLASS", "")),
+		GCSObjectMetadata:         parseStringMap(getEnv("GCS_OBJECT_METADATA", "")),
+		TranslationMemoryBackend:  strings.ToLower(getEnv("TRANSLATION_MEMORY_BACKEND", "")),
+		TranslationMemoryBucket:   getEnv("TRANSLATION_MEMORY_BUCKET", ""),
+		SummaryMaxChars:           parseInt(getEnv("SUMMARY_MAX_CHARS", "500")),
+		JobStoreMaxEntries:        parseInt(getEnv("JOB_STORE_MAX_ENTRIES", "0")),
+		JobStoreCompact:           parseBool(getEnv("JOB_STORE_COMPACT", "false")),
+		TextOffloadThreshold:      parseInt(getEnv("TEXT_OFFLOAD_THRESHOLD", "0")),
+		TTSRoutes:                 parseProviderRoutes(getEnv("TTS_ROUTES", "")),
+		WebhookQueueSize:          parseInt(getEnv("WEBHOOK_QUEUE_SIZE", "1000")),
+		LLMMaxInputChars:          parseInt(getEnv("LLM_MAX_INPUT_CHARS", "100000")),
+	}
+
+	// Transcoder jobs stage their files in the output bucket by default
+	if cfg.TranscodeBucket == "" {
+		cfg.TranscodeBucket = cfg.GCSOutputBucket
+	}
+
+	// The cache defaults to the output bucket
+	if cfg.CacheBucket == "" {
+		cfg.CacheBucket = cfg.GCSOutputBucket
+	}
+	if cfg.TranslationMemoryBucket == "" {
+		cfg.TranslationMemoryBucket = cfg.CacheBucket
+	}
+
+	// Job exports default to the output bucket
+	if cfg.ExportBucket == "" {
+		cfg.ExportBucket = cfg.GCSOutputBucket
 	}
 
 	// Validate required fields
@@ -86,6 +420,69 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("MAX_CONCURRENT_TRANSLATIONS must be greater than 0")
 	}
 
+	if c.MaxConcurrentFFmpeg <= 0 || c.MaxConcurrentAPICalls <= 0 {
+		return fmt.Errorf("MAX_CONCURRENT_FFMPEG and MAX_CONCURRENT_API_CALLS must be greater than 0")
+	}
+
+	if c.FFmpegThreads < 0 || c.FFmpegMemoryMB < 0 {
+		return fmt.Errorf("FFMPEG_THREADS and FFMPEG_MEMORY_MB must not be negative")
+	}
+	if c.FFmpegNice < -20 || c.FFmpegNice > 19 {
+		return fmt.Errorf("FFMPEG_NICE must be between -20 and 19")
+	}
+
+	if c.MaxTargetLanguages < 0 || c.MaxVideoURLLength < 0 {
+		return fmt.Errorf("MAX_TARGET_LANGUAGES and MAX_VIDEO_URL_LENGTH must not be negative")
+	}
+
+	if c.DuplicateJobWindow < 0 {
+		return fmt.Errorf("DUPLICATE_JOB_WINDOW must not be negative")
+	}
+	if c.ResultReuseWindow < 0 {
+		return fmt.Errorf("RESULT_REUSE_WINDOW must not be negative")
+	}
+	if c.MaxInFlightJobsPerClient < 0 {
+		return fmt.Errorf("MAX_INFLIGHT_JOBS_PER_CLIENT must not be negative")
+	}
+
+	for name, profile := range c.SubtitleProfiles {
+		if profile.MaxLineChars <= 0 || profile.MaxLines <= 0 || profile.MaxCPS < 0 || profile.MinDuration < 0 {
+			return fmt.Errorf("invalid SUBTITLE_PROFILES entry %s (expected name=maxLineChars:maxLines[:maxCPS[:minDuration]] with positive line limits)", name)
+		}
+	}
+	if _, ok := c.SubtitleProfileByName(c.SubtitleProfile); c.SubtitleProfile != "" && !ok {
+		return fmt.Errorf("unknown SUBTITLE_PROFILE: %s", c.SubtitleProfile)
+	}
+	if math.Abs(c.SubtitleOffset) > models.MaxSubtitleOffset {
+		return fmt.Errorf("SUBTITLE_OFFSET must be between -%d and %d seconds", models.MaxSubtitleOffset, models.MaxSubtitleOffset)
+	}
+
+	if c.SpendBudgetCost < 0 || c.SpendBudgetSTTSeconds < 0 || c.SpendBudgetTranslateChars < 0 || c.SpendBudgetTTSChars < 0 {
+		return fmt.Errorf("SPEND_BUDGET_* limits must not be negative")
+	}
+	if c.IsSpendBudgetEnabled() && c.SpendBudgetWindow <= 0 {
+		return fmt.Errorf("SPEND_BUDGET_WINDOW must be positive")
+	}
+
+	if c.SummaryMaxChars < 0 {
+		return fmt.Errorf("SUMMARY_MAX_CHARS must not be negative")
+	}
+	if c.LLMMaxInputChars < 0 {
+		return fmt.Errorf("LLM_MAX_INPUT_CHARS must not be negative")
+	}
+
+	if c.JobStoreMaxEntries < 0 {
+		return fmt.Errorf("JOB_STORE_MAX_ENTRIES must not be negative")
+	}
+	if c.TextOffloadThreshold < 0 {
+		return fmt.Errorf("TEXT_OFFLOAD_THRESHOLD must not be negative")
+	}
+
+	validStorageClasses := map[string]bool{"": true, "STANDARD": true, "NEARLINE": true, "COLDLINE": true, "ARCHIVE": true}
+	if !validStorageClasses[c.GCSStorageClass] {
+		return fmt.Errorf("invalid GCS_STORAGE_CLASS: %s (must be STANDARD, NEARLINE, COLDLINE or ARCHIVE)", c.GCSStorageClass)
+	}
+
 	validLogLevels := map[string]bool{
 		"debug": true,
 		"info":  true,
@@ -96,9 +493,600 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid LOG_LEVEL: %s (must be one of: debug, info, warn, error)", c.LogLevel)
 	}
 
+	switch c.EmailProvider {
+	case "":
+	case "smtp":
+		if c.SMTPHost == "" {
+			return fmt.Errorf("SMTP_HOST is required when EMAIL_PROVIDER is smtp")
+		}
+	case "sendgrid":
+		if c.SendGridAPIKey == "" {
+			return fmt.Errorf("SENDGRID_API_KEY is required when EMAIL_PROVIDER is sendgrid")
+		}
+	default:
+		return fmt.Errorf("invalid EMAIL_PROVIDER: %s (must be one of: smtp, sendgrid)", c.EmailProvider)
+	}
+	if c.EmailProvider != "" && c.EmailFrom == "" {
+		return fmt.Errorf("EMAIL_FROM is required when EMAIL_PROVIDER is set")
+	}
+
+	if c.STTConfidenceWarning < 0 || c.STTConfidenceWarning > 1 {
+		return fmt.Errorf("STT_CONFIDENCE_WARNING must be between 0 and 1")
+	}
+
+	if c.STTMinConfidence < 0 || c.STTMinConfidence > 1 {
+		return fmt.Errorf("STT_MIN_CONFIDENCE must be between 0 and 1")
+	}
+
+	if c.STTMinSpeechRatio < 0 || c.STTMinSpeechRatio > 1 {
+		return fmt.Errorf("STT_MIN_SPEECH_RATIO must be between 0 and 1")
+	}
+
+	if !models.IsValidSTTModel(c.STTModel) {
+		return fmt.Errorf("unsupported STT_MODEL: %s (supported: %s)", c.STTModel, strings.Join(models.SupportedSTTModels, ", "))
+	}
+
+	if len(c.STTAlternativeLanguages) > models.MaxAlternativeLanguages {
+		return fmt.Errorf("STT_ALTERNATIVE_LANGUAGES accepts at most %d languages", models.MaxAlternativeLanguages)
+	}
+
+	if c.STTAudioChannel < 0 {
+		return fmt.Errorf("STT_AUDIO_CHANNEL must not be negative")
+	}
+
+	if c.STTSampleRate != 0 && (c.STTSampleRate < 8000 || c.STTSampleRate > 48000) {
+		return fmt.Errorf("STT_SAMPLE_RATE must be between 8000 and 48000")
+	}
+
+	if c.GCSParallelThresholdMB < 0 || c.GCSPartSizeMB < 0 || c.GCSTransferConcurrency < 0 {
+		return fmt.Errorf("GCS_PARALLEL_THRESHOLD_MB, GCS_PART_SIZE_MB and GCS_TRANSFER_CONCURRENCY must not be negative")
+	}
+	if c.GCSParallelThresholdMB > 0 && (c.GCSPartSizeMB == 0 || c.GCSTransferConcurrency == 0) {
+		return fmt.Errorf("GCS_PART_SIZE_MB and GCS_TRANSFER_CONCURRENCY must be greater than 0 when GCS_PARALLEL_THRESHOLD_MB is set")
+	}
+
+	if c.CostSTTPerMinute < 0 || c.CostTTSPerMillion < 0 || c.CostStoragePerGB < 0 {
+		return fmt.Errorf("COST_STT_PER_MINUTE, COST_TTS_PER_MILLION_CHARS and COST_STORAGE_PER_GB must not be negative")
+	}
+	for provider, price := range c.CostTranslatePerMillion {
+		if price < 0 {
+			return fmt.Errorf("invalid COST_TRANSLATE_PER_MILLION_CHARS: price for %s must not be negative", provider)
+		}
+	}
+
+	if c.MaxTranscriptChars < 0 {
+		return fmt.Errorf("MAX_TRANSCRIPT_CHARS must not be negative")
+	}
+	switch c.TranscriptLimitPolicy {
+	case "", "fail", "truncate":
+	case "summarize":
+		if !c.IsLLMTranslation() {
+			return fmt.Errorf("TRANSCRIPT_LIMIT_POLICY summarize requires an LLM translation provider (openai or anthropic)")
+		}
+	default:
+		return fmt.Errorf("invalid TRANSCRIPT_LIMIT_POLICY: %s (must be fail, truncate or summarize)", c.TranscriptLimitPolicy)
+	}
+	switch c.TranscriptCleanup {
+	case "", "none", "punctuation":
+	case "llm":
+		if !c.IsLLMTranslation() {
+			return fmt.Errorf("TRANSCRIPT_CLEANUP llm requires an LLM translation provider (openai or anthropic)")
+		}
+	default:
+		return fmt.Errorf("invalid TRANSCRIPT_CLEANUP: %s (must be none, punctuation or llm)", c.TranscriptCleanup)
+	}
+	normalize := false
+	for _, processor := range c.TextProcessors {
+		switch processor {
+		case "localize", "spoken":
+		case "normalize":
+			normalize = true
+		default:
+			return fmt.Errorf("invalid TEXT_PROCESSORS entry: %s (must be localize, spoken or normalize)", processor)
+		}
+	}
+	if len(c.SpeechAcronyms) > 0 && !normalize {
+		return fmt.Errorf("SPEECH_ACRONYMS requires the normalize TEXT_PROCESSORS entry")
+	}
+
+	if c.DubbingDurationTolerance < 0 || c.DubbingDurationTolerance >= 1 {
+		return fmt.Errorf("DUBBING_DURATION_TOLERANCE must be between 0 and 1")
+	}
+	switch c.DubbingCorrection {
+	case "", "off", "atempo", "resynthesize":
+	default:
+		return fmt.Errorf("invalid DUBBING_CORRECTION: %s (must be off, atempo or resynthesize)", c.DubbingCorrection)
+	}
+
+	switch c.AudioSeparation {
+	case "", "demucs", "spleeter":
+	case "http":
+		if c.AudioSeparationEndpoint == "" {
+			return fmt.Errorf("AUDIO_SEPARATION_ENDPOINT is required when AUDIO_SEPARATION is http")
+		}
+	default:
+		return fmt.Errorf("invalid AUDIO_SEPARATION: %s (must be demucs, spleeter or http)", c.AudioSeparation)
+	}
+	if c.KeepBackgroundMusic && c.AudioSeparation == "" {
+		return fmt.Errorf("KEEP_BACKGROUND_MUSIC requires AUDIO_SEPARATION")
+	}
+	if c.BackgroundMusicVolume < 0 || c.BackgroundMusicVolume > 4 {
+		return fmt.Errorf("BACKGROUND_MUSIC_VOLUME must be between 0 and 4")
+	}
+
+	switch c.TranscodeBackend {
+	case "", "local":
+	case "http":
+		if c.TranscodeEndpoint == "" {
+			return fmt.Errorf("TRANSCODE_ENDPOINT is required when TRANSCODE_BACKEND is http")
+		}
+	case "transcoder":
+		if c.TranscodeProject == "" {
+			return fmt.Errorf("TRANSCODE_PROJECT (or GOOGLE_CLOUD_PROJECT) is required when TRANSCODE_BACKEND is transcoder")
+		}
+	default:
+		return fmt.Errorf("invalid TRANSCODE_BACKEND: %s (must be local, http or transcoder)", c.TranscodeBackend)
+	}
+	if c.TranscodeMinHeight < 0 {
+		return fmt.Errorf("TRANSCODE_MIN_HEIGHT must not be negative")
+	}
+
+	if c.ChapterDuration < 0 {
+		return fmt.Errorf("CHAPTER_DURATION must not be negative")
+	}
+	if c.ChapterDuration > c.MaxVideoDuration {
+		return fmt.Errorf("CHAPTER_DURATION must not exceed MAX_VIDEO_DURATION")
+	}
+	if c.IsChapteredProcessingEnabled() && c.MaxChapteredDuration < c.MaxVideoDuration {
+		return fmt.Errorf("MAX_CHAPTERED_VIDEO_DURATION must be at least MAX_VIDEO_DURATION")
+	}
+
+	if c.TTSSilenceTrim {
+		if c.TTSSilenceThreshold < -90 || c.TTSSilenceThreshold >= 0 {
+			return fmt.Errorf("TTS_SILENCE_THRESHOLD_DB must be between -90 and 0")
+		}
+		if c.TTSMaxPause < 0 {
+			return fmt.Errorf("TTS_MAX_PAUSE must not be negative")
+		}
+	}
+
+	switch c.VideoRotation {
+	case "", "preserve", "normalize":
+	default:
+		return fmt.Errorf("invalid VIDEO_ROTATION: %s (must be preserve or normalize)", c.VideoRotation)
+	}
+
+	switch c.SameLanguagePolicy {
+	case "", "passthrough", "skip":
+	default:
+		return fmt.Errorf("invalid SAME_LANGUAGE_POLICY: %s (must be passthrough or skip)", c.SameLanguagePolicy)
+	}
+
+	if !models.IsValidSampleRate(c.OutputAudioSampleRate) {
+		return fmt.Errorf("invalid OUTPUT_AUDIO_SAMPLE_RATE: %d (supported: %v)", c.OutputAudioSampleRate, models.SupportedSampleRates)
+	}
+	if !models.IsValidAudioBitrate(c.OutputAudioBitrate) {
+		return fmt.Errorf("OUTPUT_AUDIO_BITRATE must be between %d and %d kbps", models.MinAudioBitrate, models.MaxAudioBitrate)
+	}
+	if c.OutputAudioChannels != "" && models.ChannelCount(c.OutputAudioChannels) == 0 {
+		return fmt.Errorf("invalid OUTPUT_AUDIO_CHANNELS: %s (must be mono or stereo)", c.OutputAudioChannels)
+	}
+	if !models.IsValidAudioFormat(c.DubbedAudioFormat) {
+		return fmt.Errorf("invalid DUBBED_AUDIO_FORMAT: %s (must be mp3, wav or none)", c.DubbedAudioFormat)
+	}
+
+	if c.GeminiPipeline {
+		if c.GeminiProject == "" {
+			return fmt.Errorf("GEMINI_PROJECT (or GOOGLE_CLOUD_PROJECT) is required when GEMINI_PIPELINE is enabled")
+		}
+		if c.GeminiMaxDuration <= 0 {
+			return fmt.Errorf("GEMINI_MAX_DURATION must be greater than 0")
+		}
+	}
+
+	switch c.DiskSpacePolicy {
+	case "", "off":
+	case "queue", "reject":
+		if c.DiskSpaceFactor < 1 {
+			return fmt.Errorf("DISK_SPACE_FACTOR must be at least 1")
+		}
+		if c.DiskSpaceHeadroomMB < 0 {
+			return fmt.Errorf("DISK_SPACE_HEADROOM_MB must not be negative")
+		}
+	default:
+		return fmt.Errorf("invalid DISK_SPACE_POLICY: %s (must be queue, reject or off)", c.DiskSpacePolicy)
+	}
+
+	if c.MaxInstanceJobs < InstanceJobsAuto {
+		return fmt.Errorf("MAX_INSTANCE_JOBS must be auto, 0 or a positive number")
+	}
+	if c.JobMemoryMB < 0 {
+		return fmt.Errorf("JOB_MEMORY_MB must not be negative")
+	}
+
+	networks := map[string][]string{
+		"IP_ALLOWLIST": c.IPAllowlist,
+		"IP_DENYLIST":  c.IPDenylist,
+	}
+	for name, values := range networks {
+		for _, value := range values {
+			if _, _, err := net.ParseCIDR(value); err != nil && net.ParseIP(value) == nil {
+				return fmt.Errorf("invalid %s entry %s (expected an IP address or CIDR range)", name, value)
+			}
+		}
+	}
+	if c.IPFilterProxyHops < 0 {
+		return fmt.Errorf("IP_FILTER_PROXY_HOPS must not be negative")
+	}
+
+	if c.StalledJobFactor != 0 && c.StalledJobFactor < 1 {
+		return fmt.Errorf("STALLED_JOB_FACTOR must be 0 (disabled) or at least 1")
+	}
+
+	assets := map[string]string{
+		"WATERMARK_URL": c.WatermarkURL,
+		"INTRO_URL":     c.IntroURL,
+		"OUTRO_URL":     c.OutroURL,
+	}
+	for name, url := range assets {
+		if url == "" {
+			continue
+		}
+		bucket, object, _ := strings.Cut(strings.TrimPrefix(url, "gs://"), "/")
+		if !strings.HasPrefix(url, "gs://") || bucket == "" || object == "" {
+			return fmt.Errorf("invalid %s: %s (expected gs://bucket/path)", name, url)
+		}
+	}
+	if !models.IsValidWatermarkPosition(c.WatermarkPosition) {
+		return fmt.Errorf("invalid WATERMARK_POSITION: %s (supported: %s)", c.WatermarkPosition, strings.Join(models.WatermarkPositions, ", "))
+	}
+	if c.WatermarkOpacity < 0 || c.WatermarkOpacity > 1 {
+		return fmt.Errorf("WATERMARK_OPACITY must be between 0 and 1")
+	}
+
+	for name, tier := range c.RateLimitTiers {
+		if tier.SubmitRPM <= 0 || tier.StatusRPM <= 0 || tier.MaxConcurrentJobs < 0 {
+			return fmt.Errorf("invalid RATE_LIMIT_TIERS entry %s (expected tier=submitRPM:statusRPM[:maxConcurrentJobs] with positive rates)", name)
+		}
+	}
+	for owner, tier := range c.APIKeyTiers {
+		if _, ok := c.RateLimitTiers[tier]; !ok {
+			return fmt.Errorf("invalid API_KEY_TIERS entry %s: unknown tier %s (define it in RATE_LIMIT_TIERS)", owner, tier)
+		}
+	}
+
+	endpoints := map[string]string{
+		"GOOGLE_TRANSLATE_ENDPOINT": c.GoogleTranslateEndpoint,
+		"SPEECH_ENDPOINT":           c.SpeechEndpoint,
+		"TTS_ENDPOINT":              c.TTSEndpoint,
+		"AUDIO_SEPARATION_ENDPOINT": c.AudioSeparationEndpoint,
+		"TRANSCODE_ENDPOINT":        c.TranscodeEndpoint,
+		"OUTBOUND_PROXY":            c.OutboundProxy,
+	}
+	for name, endpoint := range endpoints {
+		if endpoint != "" && !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+			return fmt.Errorf("invalid %s: must be an http:// or https:// URL", name)
+		}
+	}
+
+	var llmProviders []string
+	for _, provider := range c.TranslationProviders() {
+		switch provider {
+		case "google":
+		case "openai", "anthropic":
+			if c.LLMAPIKey == "" {
+				return fmt.Errorf("LLM_API_KEY is required when translating with %s", provider)
+			}
+			llmProviders = append(llmProviders, provider)
+		case "deepl":
+			if c.DeepLAPIKey == "" {
+				return fmt.Errorf("DEEPL_API_KEY is required when translating with deepl")
+			}
+		default:
+			return fmt.Errorf("invalid translation provider: %s (must be one of: google, openai, anthropic, deepl)", provider)
+		}
+	}
+	// LLM_API_KEY and LLM_MODEL configure a single LLM provider
+	if len(llmProviders) > 1 {
+		return fmt.Errorf("only one LLM translation provider can be used, got: %s", strings.Join(llmProviders, ", "))
+	}
+	for lang, chain := range c.TranslationRoutes {
+		if !c.IsLanguageSupported(lang) {
+			return fmt.Errorf("invalid TRANSLATION_ROUTES: %s is not a supported language", lang)
+		}
+		if len(chain) == 0 {
+			return fmt.Errorf("invalid TRANSLATION_ROUTES: no provider for %s", lang)
+		}
+	}
+	for lang, chain := range c.TTSRoutes {
+		if !c.IsLanguageSupported(lang) {
+			return fmt.Errorf("invalid TTS_ROUTES: %s is not a supported language", lang)
+		}
+		if len(chain) == 0 {
+			return fmt.Errorf("invalid TTS_ROUTES: no voice for %s", lang)
+		}
+	}
+
+	switch c.CacheBackend {
+	case "", "gcs":
+	case "redis":
+		if c.RedisURL == "" {
+			return fmt.Errorf("REDIS_URL is required when CACHE_BACKEND is redis")
+		}
+	default:
+		return fmt.Errorf("invalid CACHE_BACKEND: %s (must be one of: gcs, redis)", c.CacheBackend)
+	}
+	switch c.TranslationMemoryBackend {
+	case "", "gcs":
+	case "redis":
+		if c.RedisURL == "" {
+			return fmt.Errorf("REDIS_URL is required when TRANSLATION_MEMORY_BACKEND is redis")
+		}
+	default:
+		return fmt.Errorf("invalid TRANSLATION_MEMORY_BACKEND: %s (must be one of: gcs, redis)", c.TranslationMemoryBackend)
+	}
+
+	for _, event := range c.WebhookEvents {
+		if !models.IsValidWebhookEvent(event) {
+			return fmt.Errorf("invalid WEBHOOK_EVENTS: %s (must be one of: %s)", event, strings.Join(models.SupportedWebhookEvents, ", "))
+		}
+	}
+	if c.WebhookMinProgressDelta < 0 || c.WebhookMinProgressDelta > 100 {
+		return fmt.Errorf("WEBHOOK_MIN_PROGRESS_DELTA must be between 0 and 100")
+	}
+	if c.WebhookTimeout < 0 || c.WebhookBackoff < 0 {
+		return fmt.Errorf("WEBHOOK_TIMEOUT and WEBHOOK_BACKOFF must not be negative")
+	}
+	if c.WebhookMaxRetries < 0 || c.WebhookConcurrency < 0 || c.WebhookQueueSize < 0 {
+		return fmt.Errorf("WEBHOOK_MAX_RETRIES, WEBHOOK_CONCURRENCY and WEBHOOK_QUEUE_SIZE must not be negative")
+	}
+
+	for lang, destination := range c.OutputDestinations {
+		if !c.IsLanguageSupported(lang) {
+			return fmt.Errorf("invalid OUTPUT_DESTINATIONS: %s is not a supported language", lang)
+		}
+		bucket, _, _ := strings.Cut(strings.TrimPrefix(destination, "gs://"), "/")
+		if !strings.HasPrefix(destination, "gs://") || bucket == "" {
+			return fmt.Errorf("invalid OUTPUT_DESTINATIONS: %s (expected gs://bucket[/prefix])", destination)
+		}
+	}
+
+	if (c.TenantNamespaces || len(c.TenantBuckets) > 0) && len(c.APIKeys) == 0 && len(c.AdminAPIKeys) == 0 {
+		return fmt.Errorf("TENANT_NAMESPACES and TENANT_BUCKETS require API_KEYS or ADMIN_API_KEYS")
+	}
+	if c.TenantNamespaces {
+		for _, spec := range append(append([]string{}, c.APIKeys...), c.AdminAPIKeys...) {
+			if owner, _, found := strings.Cut(strings.TrimSpace(spec), ":"); found && !tenantPattern.MatchString(owner) {
+				return fmt.Errorf("invalid API key owner %q for TENANT_NAMESPACES (use letters, digits, '.', '_' and '-')", owner)
+			}
+		}
+	}
+	for owner, destination := range c.TenantBuckets {
+		bucket, _, _ := strings.Cut(strings.TrimPrefix(destination, "gs://"), "/")
+		if !strings.HasPrefix(destination, "gs://") || bucket == "" {
+			return fmt.Errorf("invalid TENANT_BUCKETS entry %s: %s (expected gs://bucket[/prefix])", owner, destination)
+		}
+	}
+
+	for owner, buckets := range c.AllowedOutputBuckets {
+		// With API keys every request has an owner, so buckets allowed to no owner in particular would be unused
+		if owner == "" && (len(c.APIKeys) > 0 || len(c.AdminAPIKeys) > 0) {
+			return fmt.Errorf("invalid ALLOWED_OUTPUT_BUCKETS: %s has no owner (expected owner=bucket when API keys are configured)", strings.Join(buckets, ", "))
+		}
+		for _, bucket := range buckets {
+			if !bucketPattern.MatchString(bucket) {
+				return fmt.Errorf("invalid ALLOWED_OUTPUT_BUCKETS: %q is not a bucket name", bucket)
+			}
+		}
+	}
+
+	for _, destination := range c.ReplicaDestinations {
+		bucket, _, _ := strings.Cut(strings.TrimPrefix(destination, "gs://"), "/")
+		if !strings.HasPrefix(destination, "gs://") || bucket == "" {
+			return fmt.Errorf("invalid REPLICA_DESTINATIONS: %s (expected gs://bucket[/prefix])", destination)
+		}
+	}
+
+	if len(c.GCSRequesterPaysBuckets) > 0 && c.GCSBillingProject == "" {
+		return fmt.Errorf("GCS_BILLING_PROJECT is required when GCS_REQUESTER_PAYS_BUCKETS is set")
+	}
+	for bucket, credential := range c.GCSBucketCredentials {
+		if credential == "" {
+			return fmt.Errorf("invalid GCS_BUCKET_CREDENTIALS: %s has no service account or credentials file", bucket)
+		}
+	}
+
+	for owner, serviceAccount := range c.APIKeyServiceAccounts {
+		if !isServiceAccount(serviceAccount) {
+			return fmt.Errorf("invalid API_KEY_SERVICE_ACCOUNTS entry %s: %q is not a service account email", owner, serviceAccount)
+		}
+	}
+	for _, serviceAccount := range c.ImpersonationAccounts {
+		if !isServiceAccount(serviceAccount) {
+			return fmt.Errorf("invalid IMPERSONATION_SERVICE_ACCOUNTS entry: %q is not a service account email", serviceAccount)
+		}
+	}
+
+	if c.PubSubTopic != "" {
+		parts := strings.Split(c.PubSubTopic, "/")
+		if len(parts) != 4 || parts[0] != "projects" || parts[1] == "" || parts[2] != "topics" || parts[3] == "" {
+			return fmt.Errorf("invalid PUBSUB_NOTIFY_TOPIC: %s (expected projects/{project}/topics/{topic})", c.PubSubTopic)
+		}
+	}
+
+	if c.CloudTasksQueue != "" {
+		parts := strings.Split(c.CloudTasksQueue, "/")
+		if len(parts) != 6 || parts[0] != "projects" || parts[1] == "" || parts[2] != "locations" || parts[3] == "" || parts[4] != "queues" || parts[5] == "" {
+			return fmt.Errorf("invalid CLOUD_TASKS_QUEUE: %s (expected projects/{project}/locations/{location}/queues/{queue})", c.CloudTasksQueue)
+		}
+		if c.CloudTasksTargetURL == "" {
+			return fmt.Errorf("CLOUD_TASKS_TARGET_URL is required when CLOUD_TASKS_QUEUE is set")
+		}
+		if c.CloudTasksToken == "" {
+			return fmt.Errorf("CLOUD_TASKS_TOKEN is required when CLOUD_TASKS_QUEUE is set")
+		}
+		if c.TransientRetryMaxAttempts < 1 {
+			return fmt.Errorf("TRANSIENT_RETRY_MAX_ATTEMPTS must be at least 1")
+		}
+		if c.TransientRetryDelay <= 0 {
+			return fmt.Errorf("TRANSIENT_RETRY_DELAY must be positive")
+		}
+	}
+
 	return nil
 }
 
+// IsTransientRetryEnabled reports whether failed jobs are re-enqueued through Cloud Tasks
+func (c *Config) IsTransientRetryEnabled() bool {
+	return c.CloudTasksQueue != ""
+}
+
+// IsEmailEnabled reports whether an email notification provider is configured
+func (c *Config) IsEmailEnabled() bool {
+	return c.EmailProvider != ""
+}
+
+// TranslationChain returns the default translation provider followed by its fallbacks
+func (c *Config) TranslationChain() []string {
+	provider := c.TranslationProvider
+	if provider == "" {
+		provider = "google"
+	}
+	return append([]string{provider}, c.TranslationFallbacks...)
+}
+
+// LanguageTranslationChain returns the provider chain translating a target language: its TRANSLATION_ROUTES
+// entry, or the default chain
+func (c *Config) LanguageTranslationChain(lang string) []string {
+	if chain := c.TranslationRoutes[lang]; len(chain) > 0 {
+		return chain
+	}
+	return c.TranslationChain()
+}
+
+// TranslationProviders returns every translation provider used by the default chain or a language route, once each
+func (c *Config) TranslationProviders() []string {
+	languages := make([]string, 0, len(c.TranslationRoutes))
+	for lang := range c.TranslationRoutes {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+
+	var providers []string
+	seen := make(map[string]bool)
+	add := func(chain []string) {
+		for _, provider := range chain {
+			if !seen[provider] {
+				seen[provider] = true
+				providers = append(providers, provider)
+			}
+		}
+	}
+	add(c.TranslationChain())
+	for _, lang := range languages {
+		add(c.TranslationRoutes[lang])
+	}
+	return providers
+}
+
+// PriceTable returns the configured provider prices used to estimate job cost
+func (c *Config) PriceTable() models.PriceTable {
+	return models.PriceTable{
+		Currency:                 c.CostCurrency,
+		STTPerMinute:             c.CostSTTPerMinute,
+		TranslatePerMillionChars: c.CostTranslatePerMillion,
+		TTSPerMillionChars:       c.CostTTSPerMillion,
+		StoragePerGB:             c.CostStoragePerGB,
+	}
+}
+
+// HasTranslationFallback reports whether any target language has a fallback translation provider
+func (c *Config) HasTranslationFallback() bool {
+	if len(c.TranslationChain()) > 1 {
+		return true
+	}
+	for _, chain := range c.TranslationRoutes {
+		if len(chain) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// LLMProvider returns the LLM translation provider in use (openai or anthropic), or empty if none
+func (c *Config) LLMProvider() string {
+	for _, provider := range c.TranslationProviders() {
+		if provider == "openai" || provider == "anthropic" {
+			return provider
+		}
+	}
+	return ""
+}
+
+// IsBrandingEnabled reports whether a watermark or bumper clip is configured for every job
+func (c *Config) IsBrandingEnabled() bool {
+	return c.WatermarkURL != "" || c.IntroURL != "" || c.OutroURL != ""
+}
+
+// IsAudioSeparationEnabled reports whether a source separation backend is configured to keep background music
+func (c *Config) IsAudioSeparationEnabled() bool {
+	return c.AudioSeparation != ""
+}
+
+// IsDubbingCorrectionEnabled reports whether dubbed audio drifting beyond DUBBING_DURATION_TOLERANCE is corrected
+// rather than only reported
+func (c *Config) IsDubbingCorrectionEnabled() bool {
+	return c.DubbingCorrection != "" && c.DubbingCorrection != "off"
+}
+
+// IsTranscodeOffloadEnabled reports whether muxing is delegated to a remote transcode backend instead of local ffmpeg
+func (c *Config) IsTranscodeOffloadEnabled() bool {
+	return c.TranscodeBackend != "" && c.TranscodeBackend != "local"
+}
+
+// IsChapteredProcessingEnabled reports whether videos longer than MAX_VIDEO_DURATION are processed in chapters instead of rejected
+func (c *Config) IsChapteredProcessingEnabled() bool {
+	return c.ChapterDuration > 0
+}
+
+// MaxSourceDuration returns the longest video accepted: MAX_CHAPTERED_VIDEO_DURATION with chaptered processing,
+// otherwise MAX_VIDEO_DURATION
+func (c *Config) MaxSourceDuration() time.Duration {
+	if c.IsChapteredProcessingEnabled() {
+		return c.MaxChapteredDuration
+	}
+	return c.MaxVideoDuration
+}
+
+// IsSpendBudgetEnabled reports whether a SPEND_BUDGET_* limit is set
+// The budget is counted by each instance on its own and starts again from zero when the instance restarts.
+func (c *Config) IsSpendBudgetEnabled() bool {
+	return c.SpendBudgetCost > 0 || c.SpendBudgetSTTSeconds > 0 || c.SpendBudgetTranslateChars > 0 || c.SpendBudgetTTSChars > 0
+}
+
+// IsDiskSpaceCheckEnabled returns true if jobs reserve temp disk space before downloading
+func (c *Config) IsDiskSpaceCheckEnabled() bool {
+	return c.DiskSpacePolicy == "queue" || c.DiskSpacePolicy == "reject"
+}
+
+// StalledJobAfter returns how long a processing job may go without an update before it is failed as stalled
+// Zero disables stalled job detection.
+func (c *Config) StalledJobAfter() time.Duration {
+	return time.Duration(c.StalledJobFactor * float64(c.RequestTimeout))
+}
+
+// IsLLMTranslation reports whether an LLM provider translates at least some languages (which supports style instructions)
+func (c *Config) IsLLMTranslation() bool {
+	return c.LLMProvider() != ""
+}
+
+// IsLLMTranslated reports whether a target language's primary translation provider is an LLM, so the
+// language follows style instructions
+func (c *Config) IsLLMTranslated(lang string) bool {
+	provider := c.LanguageTranslationChain(lang)[0]
+	return provider == "openai" || provider == "anthropic"
+}
+
 // GetLoggerLevel returns the slog.Level based on LogLevel string
 func (c *Config) GetLoggerLevel() slog.Level {
 	switch strings.ToLower(c.LogLevel) {
@@ -151,6 +1139,188 @@ func parseStringSlice(value string) []string {
 	return result
 }
 
+// tenantPattern matches API key owners usable as a storage path segment
+var tenantPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// bucketPattern matches GCS bucket names
+var bucketPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{1,220}[a-z0-9]$`)
+
+// isServiceAccount reports whether value is a service account email
+func isServiceAccount(value string) bool {
+	name, domain, found := strings.Cut(value, "@")
+	return found && name != "" && strings.HasSuffix(domain, ".gserviceaccount.com")
+}
+
+// HasOutputBuckets reports whether requests of the API key owner may send outputs to buckets of their own
+// (ALLOWED_OUTPUT_BUCKETS); an empty owner stands for requests without an API key
+func (c *Config) HasOutputBuckets(owner string) bool {
+	return len(c.AllowedOutputBuckets[owner]) > 0
+}
+
+// IsOutputBucketAllowed reports whether a request of the API key owner may send its outputs to bucket
+// (ALLOWED_OUTPUT_BUCKETS), so one tenant cannot write into the buckets allowed to another
+func (c *Config) IsOutputBucketAllowed(owner string, bucket string) bool {
+	for _, allowed := range c.AllowedOutputBuckets[owner] {
+		if allowed == bucket {
+			return true
+		}
+	}
+	return false
+}
+
+// SubtitleProfileByName returns a profile of SUBTITLE_PROFILES, or else a built-in profile
+func (c *Config) SubtitleProfileByName(name string) (models.SubtitleProfile, bool) {
+	if profile, ok := c.SubtitleProfiles[name]; ok {
+		return profile, true
+	}
+	profile, ok := models.SubtitleProfiles[name]
+	return profile, ok
+}
+
+// CanImpersonate reports whether a request may name the service account: it is the one configured for
+// the request's API key (keyAccount) or listed in IMPERSONATION_SERVICE_ACCOUNTS
+func (c *Config) CanImpersonate(serviceAccount string, keyAccount string) bool {
+	if serviceAccount == keyAccount {
+		return true
+	}
+	for _, allowed := range c.ImpersonationAccounts {
+		if allowed == serviceAccount {
+			return true
+		}
+	}
+	return false
+}
+
+// parseStringMap parses comma-separated key=value pairs
+func parseStringMap(value string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range parseStringSlice(value) {
+		key, val, found := strings.Cut(pair, "=")
+		if found && strings.TrimSpace(key) != "" {
+			result[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		}
+	}
+	return result
+}
+
+// parseOwnerLists parses comma-separated owner=value entries into the values of each owner, which may repeat
+// Entries without an owner are kept under the empty owner.
+func parseOwnerLists(value string) map[string][]string {
+	result := make(map[string][]string)
+	for _, entry := range parseStringSlice(value) {
+		owner, item, found := strings.Cut(entry, "=")
+		if !found {
+			owner, item = "", entry
+		}
+		owner, item = strings.TrimSpace(owner), strings.TrimSpace(item)
+		if item != "" {
+			result[owner] = append(result[owner], item)
+		}
+	}
+	return result
+}
+
+// parseAcronyms parses acronym=spoken pairs, each optionally for one language as language:acronym=spoken,
+// e.g. "GCP=G C P,de:EU=Europäische Union"; acronyms for every language are under the empty language
+func parseAcronyms(value string) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for key, spoken := range parseStringMap(value) {
+		language, acronym, found := strings.Cut(key, ":")
+		if !found {
+			language, acronym = "", key
+		}
+		if result[language] == nil {
+			result[language] = make(map[string]string)
+		}
+		result[language][acronym] = spoken
+	}
+	return result
+}
+
+// parseFloatMap parses key=number pairs, skipping pairs whose value is not a number
+func parseFloatMap(value string) map[string]float64 {
+	result := make(map[string]float64)
+	for key, val := range parseStringMap(value) {
+		if parsed, err := strconv.ParseFloat(val, 64); err == nil {
+			result[key] = parsed
+		}
+	}
+	return result
+}
+
+// parseRateLimitTiers parses tier=submitRPM:statusRPM[:maxConcurrentJobs] pairs, e.g. "free=10:120:1,pro=120:1200"
+// Malformed numbers are parsed as 0 and rejected by Validate.
+func parseRateLimitTiers(value string) map[string]models.RateLimitTier {
+	tiers := make(map[string]models.RateLimitTier)
+	for name, spec := range parseStringMap(value) {
+		parts := strings.Split(spec, ":")
+		var tier models.RateLimitTier
+		tier.SubmitRPM = parseInt(strings.TrimSpace(parts[0]))
+		if len(parts) > 1 {
+			tier.StatusRPM = parseInt(strings.TrimSpace(parts[1]))
+		}
+		if len(parts) > 2 {
+			tier.MaxConcurrentJobs = parseInt(strings.TrimSpace(parts[2]))
+		}
+		if len(parts) > 3 {
+			tier.SubmitRPM = 0 // Too many fields
+		}
+		tiers[name] = tier
+	}
+	return tiers
+}
+
+// parseSubtitleProfiles parses name=maxLineChars:maxLines[:maxCPS[:minDuration]] pairs, e.g. "mobile=32:2:15:1.2"
+// Malformed numbers are parsed as 0 and rejected by Validate.
+func parseSubtitleProfiles(value string) map[string]models.SubtitleProfile {
+	profiles := make(map[string]models.SubtitleProfile)
+	for name, spec := range parseStringMap(value) {
+		parts := strings.Split(spec, ":")
+		var profile models.SubtitleProfile
+		profile.MaxLineChars = parseInt(strings.TrimSpace(parts[0]))
+		if len(parts) > 1 {
+			profile.MaxLines = parseInt(strings.TrimSpace(parts[1]))
+		}
+		if len(parts) > 2 {
+			profile.MaxCPS = parseFloat(strings.TrimSpace(parts[2]))
+		}
+		if len(parts) > 3 {
+			profile.MinDuration = parseFloat(strings.TrimSpace(parts[3]))
+		}
+		if len(parts) > 4 {
+			profile.MaxLineChars = 0 // Too many fields
+		}
+		profiles[name] = profile
+	}
+	return profiles
+}
+
+// parseProviderRoutes parses language=provider|fallback pairs, e.g. "de=deepl|google,ar=google"
+func parseProviderRoutes(value string) map[string][]string {
+	routes := make(map[string][]string)
+	for lang, chain := range parseStringMap(value) {
+		var providers []string
+		for _, provider := range strings.Split(chain, "|") {
+			if provider = strings.TrimSpace(provider); provider != "" {
+				providers = append(providers, provider)
+			}
+		}
+		routes[lang] = providers
+	}
+	return routes
+}
+
+// InstanceJobsAuto is MaxInstanceJobs for MAX_INSTANCE_JOBS=auto, deriving the cap from the container's resources
+const InstanceJobsAuto = -1
+
+// parseInstanceJobs parses MAX_INSTANCE_JOBS: a job count, 0 for no cap, or "auto"
+func parseInstanceJobs(value string) int {
+	if strings.EqualFold(strings.TrimSpace(value), "auto") {
+		return InstanceJobsAuto
+	}
+	return parseInt(value)
+}
+
 func parseInt(value string) int {
 	parsed, err := strconv.Atoi(value)
 	if err != nil {
@@ -181,6 +1351,14 @@ func parseInt64(value string) int64 {
 	return parsed
 }
 
+func parseFloat(value string) float64 {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return parsed
+}
+
 func parseBool(value string) bool {
 	parsed, err := strconv.ParseBool(value)
 	if err != nil {
@@ -2,7 +2,11 @@ package config
 
 import (
 	"os"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -26,6 +30,27 @@ func TestLoadConfig(t *testing.T) {
 	if cfg.TranslateAPIKey != "test-key" {
 		t.Errorf("Expected TranslateAPIKey to be 'test-key', got '%s'", cfg.TranslateAPIKey)
 	}
+	if cfg.TenantNamespaces {
+		t.Error("Expected no tenant namespaces without API keys")
+	}
+}
+
+func TestLoadConfig_TenantNamespacesWithAPIKeys(t *testing.T) {
+	t.Setenv("GCS_BUCKET_OUTPUT", "test-bucket")
+	t.Setenv("API_KEYS", "acme:secret-1")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if !cfg.TenantNamespaces {
+		t.Error("Expected tenant namespaces by default with API keys")
+	}
+
+	t.Setenv("TENANT_NAMESPACES", "false")
+	if cfg, err = LoadConfig(); err != nil || cfg.TenantNamespaces {
+		t.Errorf("Expected TENANT_NAMESPACES=false to opt out, got %v: %v", cfg.TenantNamespaces, err)
+	}
 }
 
 func TestConfigValidation(t *testing.T) {
@@ -44,6 +69,20 @@ func TestConfigValidation(t *testing.T) {
 	}
 }
 
+// validConfig returns a minimal valid configuration for validation tests to override one setting at a time
+func validConfig() *Config {
+	return &Config{
+		GCSOutputBucket:           "bucket",
+		SupportedLanguages:        []string{"en"},
+		MaxVideoDuration:          60,
+		MaxVideoSizeMB:            100,
+		MaxConcurrentTranslations: 1,
+		MaxConcurrentFFmpeg:       1,
+		MaxConcurrentAPICalls:     1,
+		LogLevel:                  "info",
+	}
+}
+
 func TestIsLanguageSupported(t *testing.T) {
 	cfg := &Config{
 		SupportedLanguages: []string{"en", "ar", "de"},
@@ -57,3 +96,1096 @@ func TestIsLanguageSupported(t *testing.T) {
 		t.Error("Expected 'fr' not to be supported")
 	}
 }
+
+func TestConfigValidation_EmailProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr bool
+	}{
+		{"disabled", func(c *Config) {}, false},
+		{"smtp configured", func(c *Config) {
+			c.EmailProvider = "smtp"
+			c.SMTPHost = "smtp.example.com"
+			c.EmailFrom = "a@example.com"
+		}, false},
+		{"smtp missing host", func(c *Config) { c.EmailProvider = "smtp"; c.EmailFrom = "a@example.com" }, true},
+		{"sendgrid missing key", func(c *Config) { c.EmailProvider = "sendgrid"; c.EmailFrom = "a@example.com" }, true},
+		{"missing from", func(c *Config) { c.EmailProvider = "sendgrid"; c.SendGridAPIKey = "key" }, true},
+		{"unknown provider", func(c *Config) { c.EmailProvider = "pigeon"; c.EmailFrom = "a@example.com" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_PubSubTopic(t *testing.T) {
+	cfg := validConfig()
+	cfg.PubSubTopic = "projects/my-project/topics/jobs"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.PubSubTopic = "jobs"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for malformed topic name")
+	}
+}
+
+func TestConfigValidation_STTConfidence(t *testing.T) {
+	cfg := validConfig()
+	cfg.STTConfidenceWarning = 0.6
+	cfg.STTMinConfidence = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for STT_MIN_CONFIDENCE above 1")
+	}
+}
+
+func TestConfigValidation_TranslationProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		apiKey   string
+		wantErr  bool
+		wantLLM  bool
+	}{
+		{"default", "", "", false, false},
+		{"google", "google", "", false, false},
+		{"openai with key", "openai", "key", false, true},
+		{"anthropic missing key", "anthropic", "", true, true},
+		{"unknown provider", "babelfish", "key", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.TranslationProvider = tt.provider
+			cfg.LLMAPIKey = tt.apiKey
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if cfg.IsLLMTranslation() != tt.wantLLM {
+				t.Errorf("expected IsLLMTranslation() %v, got %v", tt.wantLLM, cfg.IsLLMTranslation())
+			}
+		})
+	}
+}
+
+func TestConfigValidation_CacheBackend(t *testing.T) {
+	cfg := validConfig()
+	cfg.CacheBackend = "redis"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for redis cache without REDIS_URL")
+	}
+
+	cfg.RedisURL = "redis://localhost:6379/0"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.CacheBackend = "memcached"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown cache backend")
+	}
+}
+
+func TestConfigValidation_WorkerPools(t *testing.T) {
+	cfg := validConfig()
+	cfg.MaxConcurrentFFmpeg = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for MAX_CONCURRENT_FFMPEG of 0")
+	}
+}
+
+func TestConfigValidation_OutputDestinations(t *testing.T) {
+	cfg := validConfig()
+	cfg.SupportedLanguages = []string{"en", "de"}
+	cfg.OutputDestinations = parseStringMap("de=gs://eu-cdn/dubbed")
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	cfg.OutputDestinations = parseStringMap("fr=gs://eu-cdn")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unsupported language")
+	}
+
+	cfg.OutputDestinations = parseStringMap("de=eu-cdn")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for destination without gs:// scheme")
+	}
+}
+
+func TestConfigValidation_ReplicaDestinations(t *testing.T) {
+	cfg := validConfig()
+	cfg.ReplicaDestinations = parseStringSlice("gs://eu-origin, gs://asia-origin/dubbed")
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	cfg.ReplicaDestinations = parseStringSlice("gs://eu-origin,asia-origin")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for destination without gs:// scheme")
+	}
+
+	cfg.ReplicaDestinations = parseStringSlice("gs:///dubbed")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for destination without bucket")
+	}
+}
+
+func TestConfigValidation_WebhookEvents(t *testing.T) {
+	cfg := validConfig()
+	cfg.WebhookEvents = parseStringSlice("job.completed,language.completed,job.progress")
+	cfg.WebhookMinProgressDelta = 25
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	cfg.WebhookEvents = parseStringSlice("job.completed,job.queued")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown webhook event")
+	}
+
+	cfg.WebhookEvents = nil
+	cfg.WebhookMinProgressDelta = 101
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for progress delta above 100")
+	}
+}
+
+func TestConfigValidation_CloudTasks(t *testing.T) {
+	cfg := validConfig()
+	cfg.CloudTasksQueue = "projects/p/locations/us-central1/queues/retries"
+	cfg.CloudTasksTargetURL = "https://service.example.com"
+	cfg.CloudTasksToken = "secret"
+	cfg.TransientRetryMaxAttempts = 3
+	cfg.TransientRetryDelay = time.Minute
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	cfg.CloudTasksToken = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for missing task token")
+	}
+
+	cfg.CloudTasksToken = "secret"
+	cfg.CloudTasksQueue = "projects/p/queues/retries"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for malformed queue name")
+	}
+
+	cfg.CloudTasksQueue = "projects/p/locations/us-central1/queues/retries"
+	cfg.TransientRetryMaxAttempts = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for zero max attempts")
+	}
+}
+
+func TestConfigValidation_STTRecognition(t *testing.T) {
+	cfg := validConfig()
+	cfg.STTModel = "latest_long"
+	cfg.STTAlternativeLanguages = []string{"fr-FR", "de-DE"}
+	cfg.STTAudioChannel = 1
+	cfg.STTSampleRate = 48000
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	cfg.STTModel = "telephony"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unsupported model")
+	}
+
+	cfg.STTModel = ""
+	cfg.STTAlternativeLanguages = []string{"fr", "de", "es", "it"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for too many alternative languages")
+	}
+
+	cfg.STTAlternativeLanguages = nil
+	cfg.STTSampleRate = 4000
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for sample rate below 8000")
+	}
+}
+
+func TestConfigValidation_ParallelTransfers(t *testing.T) {
+	cfg := validConfig()
+	cfg.GCSParallelThresholdMB = 100
+	cfg.GCSPartSizeMB = 16
+	cfg.GCSTransferConcurrency = 8
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	cfg.GCSPartSizeMB = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for zero part size with parallel transfers enabled")
+	}
+
+	cfg.GCSParallelThresholdMB = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config with parallel transfers disabled, got %v", err)
+	}
+
+	cfg.GCSTransferConcurrency = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative concurrency")
+	}
+}
+
+func TestConfigValidation_TranslationRoutes(t *testing.T) {
+	cfg := validConfig()
+	cfg.SupportedLanguages = []string{"en", "de", "ar"}
+	cfg.TranslationProvider = "google"
+	cfg.TranslationRoutes = parseProviderRoutes("de=deepl|google, ar=openai")
+	cfg.DeepLAPIKey = "key"
+	cfg.LLMAPIKey = "key"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	if got, want := cfg.TranslationRoutes["de"], []string{"deepl", "google"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected de route %v, got %v", want, got)
+	}
+	if got, want := cfg.TranslationProviders(), []string{"google", "openai", "deepl"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected providers %v, got %v", want, got)
+	}
+	if !cfg.IsLLMTranslation() || !cfg.HasTranslationFallback() {
+		t.Error("expected LLM translation with fallback")
+	}
+
+	cfg.DeepLAPIKey = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for deepl route without DEEPL_API_KEY")
+	}
+
+	cfg.DeepLAPIKey = "key"
+	cfg.TranslationFallbacks = []string{"anthropic"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for two LLM providers")
+	}
+
+	cfg.TranslationFallbacks = nil
+	cfg.TranslationRoutes = parseProviderRoutes("fr=google")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for route to an unsupported language")
+	}
+
+	cfg.TranslationRoutes = nil
+	cfg.TTSRoutes = parseProviderRoutes("de=elevenlabs:AbC123|default")
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid TTS routes, got %v", err)
+	}
+	if got, want := cfg.TTSRoutes["de"], []string{"elevenlabs:AbC123", "default"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected de voices %v, got %v", want, got)
+	}
+	cfg.TTSRoutes = parseProviderRoutes("fr=default")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for TTS route to an unsupported language")
+	}
+}
+
+func TestConfigValidation_CostPrices(t *testing.T) {
+	cfg := validConfig()
+	cfg.CostCurrency = "EUR"
+	cfg.CostSTTPerMinute = 0.016
+	cfg.CostTranslatePerMillion = parseFloatMap("google=20, deepl=25, openai=abc")
+	cfg.CostTTSPerMillion = 16
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	prices := cfg.PriceTable()
+	if got, want := prices.TranslatePerMillionChars, map[string]float64{"google": 20, "deepl": 25}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected translate prices %v, got %v", want, got)
+	}
+	if prices.Currency != "EUR" || prices.TTSPerMillionChars != 16 {
+		t.Errorf("expected EUR prices with TTS at 16, got %+v", prices)
+	}
+
+	cfg.CostTranslatePerMillion["deepl"] = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative translate price")
+	}
+
+	cfg.CostTranslatePerMillion["deepl"] = 25
+	cfg.CostStoragePerGB = -0.1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative storage price")
+	}
+}
+
+func TestConfigValidation_ProviderEndpoints(t *testing.T) {
+	cfg := validConfig()
+	cfg.GoogleTranslateEndpoint = "http://localhost:9090"
+	cfg.SpeechEndpoint = "https://speech.example.com"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.TTSEndpoint = "localhost:9092"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for endpoint without scheme")
+	}
+	cfg.TTSEndpoint = ""
+
+	cfg.OutboundProxy = "proxy.corp.example.com:3128"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for proxy without scheme")
+	}
+	cfg.OutboundProxy = "http://proxy.corp.example.com:3128"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid proxy, got %v", err)
+	}
+}
+
+func TestConfigValidation_IPFilter(t *testing.T) {
+	cfg := validConfig()
+	cfg.IPAllowlist = []string{"10.0.0.0/8", "203.0.113.7", "2001:db8::/32"}
+	cfg.IPDenylist = []string{"10.66.0.0/16"}
+	cfg.IPFilterProxyHops = 1
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.IPDenylist = []string{"10.66.0.0/40"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid CIDR range")
+	}
+	cfg.IPDenylist = nil
+
+	cfg.IPFilterProxyHops = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative proxy hops")
+	}
+}
+
+func TestConfigValidation_SilenceTrim(t *testing.T) {
+	cfg := validConfig()
+	cfg.TTSSilenceTrim = true
+	cfg.TTSSilenceThreshold = -50
+	cfg.TTSMaxPause = time.Second
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.TTSSilenceThreshold = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for non-negative silence threshold")
+	}
+	cfg.TTSSilenceThreshold = -50
+
+	cfg.TTSMaxPause = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative maximum pause")
+	}
+}
+
+func TestConfigValidation_TranscriptLimit(t *testing.T) {
+	cfg := validConfig()
+	cfg.TranslationProvider = "google"
+	cfg.MaxTranscriptChars = 1000
+	cfg.TranscriptLimitPolicy = "truncate"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.TranscriptLimitPolicy = "summarize"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for summarize without an LLM provider")
+	}
+
+	cfg.TranslationFallbacks = []string{"openai"}
+	cfg.LLMAPIKey = "key"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config with an LLM fallback, got %v", err)
+	}
+
+	cfg.TranscriptLimitPolicy = "drop"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+
+	cfg.TranscriptLimitPolicy = "fail"
+	cfg.MaxTranscriptChars = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative MAX_TRANSCRIPT_CHARS")
+	}
+}
+
+func TestConfigValidation_TranscriptCleanup(t *testing.T) {
+	cfg := validConfig()
+	cfg.TranslationProvider = "google"
+	cfg.TranscriptCleanup = "punctuation"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.TranscriptCleanup = "llm"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for llm cleanup without an LLM provider")
+	}
+
+	cfg.TranslationFallbacks = []string{"openai"}
+	cfg.LLMAPIKey = "key"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config with an LLM fallback, got %v", err)
+	}
+
+	cfg.TranscriptCleanup = "grammar"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown cleanup mode")
+	}
+}
+
+func TestConfigValidation_TextProcessors(t *testing.T) {
+	cfg := validConfig()
+	cfg.TextProcessors = []string{"localize", "spoken"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.TextProcessors = []string{"localize", "emoji"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown text processor")
+	}
+
+	cfg.TextProcessors = []string{"spoken"}
+	cfg.SpeechAcronyms = parseAcronyms("GCP=G C P,de:EU=Europäische Union")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for acronyms without the normalize processor")
+	}
+	cfg.TextProcessors = []string{"normalize"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+}
+
+func TestParseAcronyms(t *testing.T) {
+	acronyms := parseAcronyms("GCP=G C P, de:EU=Europäische Union,fr:UE=Union européenne")
+	if acronyms[""]["GCP"] != "G C P" {
+		t.Errorf("expected acronym for every language, got %v", acronyms[""])
+	}
+	if acronyms["de"]["EU"] != "Europäische Union" || acronyms["fr"]["UE"] != "Union européenne" {
+		t.Errorf("expected per-language acronyms, got %v", acronyms)
+	}
+	if len(parseAcronyms("")) != 0 {
+		t.Error("expected no acronyms for an empty value")
+	}
+}
+
+func TestConfigValidation_DubbingDuration(t *testing.T) {
+	cfg := validConfig()
+	cfg.DubbingDurationTolerance = 0.05
+	cfg.DubbingCorrection = "resynthesize"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.DubbingCorrection = "stretch"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown correction")
+	}
+
+	cfg.DubbingCorrection = "off"
+	if err := cfg.Validate(); err != nil || cfg.IsDubbingCorrectionEnabled() {
+		t.Errorf("expected off to be valid and disable correction, got %v", err)
+	}
+
+	cfg.DubbingCorrection = "atempo"
+	if !cfg.IsDubbingCorrectionEnabled() {
+		t.Error("expected atempo to enable correction")
+	}
+	cfg.DubbingDurationTolerance = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for tolerance above 1")
+	}
+}
+
+func TestConfigValidation_Transcode(t *testing.T) {
+	cfg := validConfig()
+	cfg.TranscodeBackend = "http"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for http without TRANSCODE_ENDPOINT")
+	}
+
+	cfg.TranscodeEndpoint = "https://gpu-worker.example.com/mux"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+	if !cfg.IsTranscodeOffloadEnabled() {
+		t.Error("expected transcode offload to be enabled")
+	}
+
+	cfg.TranscodeBackend = "transcoder"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for transcoder without a project")
+	}
+
+	cfg.TranscodeProject = "my-project"
+	cfg.TranscodeMinHeight = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative TRANSCODE_MIN_HEIGHT")
+	}
+
+	cfg.TranscodeMinHeight = 2160
+	cfg.TranscodeBackend = "gpu"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+
+	cfg.TranscodeBackend = "local"
+	if err := cfg.Validate(); err != nil || cfg.IsTranscodeOffloadEnabled() {
+		t.Errorf("expected local muxing, got %v", err)
+	}
+}
+
+func TestConfigValidation_AudioSeparation(t *testing.T) {
+	cfg := validConfig()
+	cfg.AudioSeparation = "demucs"
+	cfg.KeepBackgroundMusic = true
+	cfg.BackgroundMusicVolume = 0.8
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.AudioSeparation = "http"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for http separation without endpoint")
+	}
+
+	cfg.AudioSeparationEndpoint = "ftp://separator.example.com"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for non-http endpoint")
+	}
+
+	cfg.AudioSeparationEndpoint = "https://separator.example.com/separate"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	cfg.AudioSeparation = "umx"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+
+	cfg.AudioSeparation = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for KEEP_BACKGROUND_MUSIC without a backend")
+	}
+
+	cfg.AudioSeparation = "spleeter"
+	cfg.BackgroundMusicVolume = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative volume")
+	}
+}
+
+func TestConfigValidation_VideoRotation(t *testing.T) {
+	cfg := validConfig()
+	for _, policy := range []string{"", "preserve", "normalize"} {
+		cfg.VideoRotation = policy
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("expected %q to be valid, got %v", policy, err)
+		}
+	}
+
+	cfg.VideoRotation = "rotate"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown rotation policy")
+	}
+}
+
+func TestConfigValidation_SameLanguagePolicy(t *testing.T) {
+	cfg := validConfig()
+	for _, policy := range []string{"", "passthrough", "skip"} {
+		cfg.SameLanguagePolicy = policy
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("expected %q to be valid, got %v", policy, err)
+		}
+	}
+
+	cfg.SameLanguagePolicy = "translate"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown same-language policy")
+	}
+}
+
+func TestConfigValidation_OutputAudio(t *testing.T) {
+	cfg := validConfig()
+	cfg.OutputAudioSampleRate = 48000
+	cfg.OutputAudioBitrate = 192
+	cfg.OutputAudioChannels = "stereo"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+	}{
+		{"unsupported sample rate", func(c *Config) { c.OutputAudioSampleRate = 11025 }},
+		{"bitrate too high", func(c *Config) { c.OutputAudioBitrate = 1024 }},
+		{"unknown channel layout", func(c *Config) { c.OutputAudioChannels = "5.1" }},
+		{"unknown dubbed audio format", func(c *Config) { c.DubbedAudioFormat = "flac" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalid := *cfg
+			tt.modify(&invalid)
+			if err := invalid.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestConfigValidation_GeminiPipeline(t *testing.T) {
+	cfg := validConfig()
+	cfg.GeminiPipeline = true
+	cfg.GeminiProject = "my-project"
+	cfg.GeminiMaxDuration = 2 * time.Minute
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+	}{
+		{"missing project", func(c *Config) { c.GeminiProject = "" }},
+		{"zero max duration", func(c *Config) { c.GeminiMaxDuration = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalid := *cfg
+			tt.modify(&invalid)
+			if err := invalid.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+
+	// Without the pipeline the Gemini settings are not checked
+	disabled := *cfg
+	disabled.GeminiPipeline = false
+	disabled.GeminiProject = ""
+	if err := disabled.Validate(); err != nil {
+		t.Errorf("expected valid config without the Gemini pipeline, got %v", err)
+	}
+}
+
+func TestConfigValidation_DiskSpace(t *testing.T) {
+	cfg := validConfig()
+	cfg.DiskSpacePolicy = "queue"
+	cfg.DiskSpaceFactor = 3
+	cfg.DiskSpaceHeadroomMB = 256
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+	}{
+		{"unknown policy", func(c *Config) { c.DiskSpacePolicy = "wait" }},
+		{"factor below one", func(c *Config) { c.DiskSpaceFactor = 0.5 }},
+		{"negative headroom", func(c *Config) { c.DiskSpaceHeadroomMB = -1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalid := *cfg
+			tt.modify(&invalid)
+			if err := invalid.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+
+	// With the check off its settings are not used
+	disabled := *cfg
+	disabled.DiskSpacePolicy = "off"
+	disabled.DiskSpaceFactor = 0
+	if err := disabled.Validate(); err != nil {
+		t.Errorf("expected valid config with the disk check off, got %v", err)
+	}
+	if disabled.IsDiskSpaceCheckEnabled() {
+		t.Error("expected disk check to be disabled")
+	}
+}
+
+func TestConfigValidation_InstanceJobs(t *testing.T) {
+	cfg := validConfig()
+	cfg.MaxInstanceJobs = 0
+	cfg.JobMemoryMB = 1024
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+	}{
+		{"negative job cap", func(c *Config) { c.MaxInstanceJobs = -2 }},
+		{"negative job memory", func(c *Config) { c.JobMemoryMB = -1 }},
+		{"stall factor below one", func(c *Config) { c.StalledJobFactor = 0.5 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalid := *cfg
+			tt.modify(&invalid)
+			if err := invalid.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestConfig_StalledJobAfter(t *testing.T) {
+	cfg := &Config{RequestTimeout: 540 * time.Second, StalledJobFactor: 2}
+	if got := cfg.StalledJobAfter(); got != 18*time.Minute {
+		t.Errorf("expected 18m, got %v", got)
+	}
+	cfg.StalledJobFactor = 0
+	if got := cfg.StalledJobAfter(); got != 0 {
+		t.Errorf("expected stall detection to be disabled, got %v", got)
+	}
+}
+
+func TestConfigValidation_Branding(t *testing.T) {
+	cfg := validConfig()
+	cfg.WatermarkURL = "gs://brand/logo.png"
+	cfg.WatermarkPosition = "top-left"
+	cfg.WatermarkOpacity = 0.7
+	cfg.IntroURL = "gs://brand/intro.mp4"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	if !cfg.IsBrandingEnabled() {
+		t.Error("expected branding to be enabled")
+	}
+
+	cfg.OutroURL = "https://example.com/outro.mp4"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for non-GCS outro URL")
+	}
+
+	cfg.OutroURL = "gs://brand"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for outro URL without object path")
+	}
+
+	cfg.OutroURL = ""
+	cfg.WatermarkPosition = "center"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown watermark position")
+	}
+
+	cfg.WatermarkPosition = "bottom-right"
+	cfg.WatermarkOpacity = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for opacity above 1")
+	}
+}
+
+func TestParseRateLimitTiers(t *testing.T) {
+	tiers := parseRateLimitTiers("free=10:120:1, pro=120:1200,broken=fast:10")
+	want := map[string]models.RateLimitTier{
+		"free":   {SubmitRPM: 10, StatusRPM: 120, MaxConcurrentJobs: 1},
+		"pro":    {SubmitRPM: 120, StatusRPM: 1200},
+		"broken": {SubmitRPM: 0, StatusRPM: 10},
+	}
+	if !reflect.DeepEqual(tiers, want) {
+		t.Errorf("expected %v, got %v", want, tiers)
+	}
+}
+
+func TestConfigValidation_RateLimitTiers(t *testing.T) {
+	cfg := validConfig()
+	cfg.RateLimitTiers = parseRateLimitTiers("free=10:120:1,pro=120:1200")
+	cfg.APIKeyTiers = map[string]string{"acme": "pro", "beta": "free"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.APIKeyTiers["gamma"] = "enterprise"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown tier")
+	}
+
+	delete(cfg.APIKeyTiers, "gamma")
+	cfg.RateLimitTiers = parseRateLimitTiers("free=10")
+	cfg.APIKeyTiers = nil
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for tier without a status rate")
+	}
+}
+
+func TestConfigValidation_ServiceAccounts(t *testing.T) {
+	cfg := validConfig()
+	cfg.APIKeyServiceAccounts = map[string]string{"acme": "reader@acme.iam.gserviceaccount.com"}
+	cfg.ImpersonationAccounts = []string{"shared@media.iam.gserviceaccount.com"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.APIKeyServiceAccounts["beta"] = "beta@example.com"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a key service account that is not a service account email")
+	}
+
+	delete(cfg.APIKeyServiceAccounts, "beta")
+	cfg.ImpersonationAccounts = append(cfg.ImpersonationAccounts, "@iam.gserviceaccount.com")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an allowed service account without a name")
+	}
+}
+
+func TestConfig_CanImpersonate(t *testing.T) {
+	cfg := &Config{ImpersonationAccounts: []string{"shared@media.iam.gserviceaccount.com"}}
+
+	tests := []struct {
+		name           string
+		serviceAccount string
+		keyAccount     string
+		want           bool
+	}{
+		{"key service account", "reader@acme.iam.gserviceaccount.com", "reader@acme.iam.gserviceaccount.com", true},
+		{"allowed service account", "shared@media.iam.gserviceaccount.com", "reader@acme.iam.gserviceaccount.com", true},
+		{"other tenant", "reader@beta.iam.gserviceaccount.com", "reader@acme.iam.gserviceaccount.com", false},
+		{"key without service account", "reader@beta.iam.gserviceaccount.com", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.CanImpersonate(tt.serviceAccount, tt.keyAccount); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_FFmpegLimits(t *testing.T) {
+	cfg := validConfig()
+	cfg.FFmpegThreads = 2
+	cfg.FFmpegMemoryMB = 1024
+	cfg.FFmpegNice = 10
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.FFmpegThreads = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative FFMPEG_THREADS")
+	}
+
+	cfg.FFmpegThreads = 0
+	cfg.FFmpegNice = 20
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for FFMPEG_NICE above 19")
+	}
+}
+
+func TestConfigValidation_ChapteredProcessing(t *testing.T) {
+	cfg := validConfig()
+	cfg.MaxVideoDuration = 10 * time.Minute
+	cfg.ChapterDuration = 5 * time.Minute
+	cfg.MaxChapteredDuration = 2 * time.Hour
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	if got := cfg.MaxSourceDuration(); got != 2*time.Hour {
+		t.Errorf("expected chaptered maximum of 2h, got %v", got)
+	}
+
+	cfg.ChapterDuration = 20 * time.Minute
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for CHAPTER_DURATION above MAX_VIDEO_DURATION")
+	}
+
+	cfg.ChapterDuration = 5 * time.Minute
+	cfg.MaxChapteredDuration = 5 * time.Minute
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for MAX_CHAPTERED_VIDEO_DURATION below MAX_VIDEO_DURATION")
+	}
+
+	cfg.ChapterDuration = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected chapter limits to be ignored when disabled, got %v", err)
+	}
+	if got := cfg.MaxSourceDuration(); got != 10*time.Minute {
+		t.Errorf("expected MAX_VIDEO_DURATION without chapters, got %v", got)
+	}
+}
+
+func TestConfigValidation_WebhookDelivery(t *testing.T) {
+	cfg := validConfig()
+	cfg.WebhookTimeout = 5 * time.Second
+	cfg.WebhookMaxRetries = 3
+	cfg.WebhookBackoff = time.Second
+	cfg.WebhookConcurrency = 8
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.WebhookMaxRetries = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative WEBHOOK_MAX_RETRIES")
+	}
+
+	cfg.WebhookMaxRetries = 0
+	cfg.WebhookBackoff = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative WEBHOOK_BACKOFF")
+	}
+
+	cfg.WebhookBackoff = time.Second
+	cfg.WebhookQueueSize = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative WEBHOOK_QUEUE_SIZE")
+	}
+}
+
+func TestConfigValidation_TenantNamespaces(t *testing.T) {
+	cfg := validConfig()
+	cfg.TenantNamespaces = true
+	cfg.TenantBuckets = map[string]string{"acme": "gs://acme-outputs/dubbing"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for tenant namespaces without API keys")
+	}
+
+	cfg.APIKeys = []string{"acme:secret-1", "bare-secret"}
+	cfg.AdminAPIKeys = []string{"ops.team:secret-2"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.APIKeys = []string{"acme/../other:secret-1"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an owner that is not a path segment")
+	}
+	cfg.APIKeys = []string{"acme:secret-1"}
+
+	cfg.TenantBuckets["acme"] = "acme-outputs"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a tenant bucket without gs://")
+	}
+}
+
+func TestConfigValidation_AllowedOutputBuckets(t *testing.T) {
+	cfg := validConfig()
+	cfg.APIKeys = []string{"team-a:key-a", "team-b:key-b"}
+	cfg.AllowedOutputBuckets = parseOwnerLists("team-a=team-a-outputs, team-a=team-a-archive, team-b=team.b.example.com")
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	if !cfg.IsOutputBucketAllowed("team-a", "team-a-outputs") || !cfg.IsOutputBucketAllowed("team-a", "team-a-archive") {
+		t.Error("expected the owner's buckets to be allowed")
+	}
+	if cfg.IsOutputBucketAllowed("team-b", "team-a-outputs") || cfg.IsOutputBucketAllowed("team-a", "team-c-outputs") {
+		t.Error("expected buckets of other owners and unlisted buckets to be refused")
+	}
+	if !cfg.HasOutputBuckets("team-b") || cfg.HasOutputBuckets("team-c") {
+		t.Error("expected only owners with listed buckets to have output buckets")
+	}
+
+	cfg.AllowedOutputBuckets = parseOwnerLists("team-a=gs://team-a-outputs")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a bucket URL instead of a bucket name")
+	}
+
+	cfg.AllowedOutputBuckets = parseOwnerLists("shared-outputs")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a bucket without owner when API keys are configured")
+	}
+	cfg.APIKeys = nil
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected buckets without owner to be valid without API keys, got %v", err)
+	}
+}
+
+func TestConfigValidation_SubtitleProfiles(t *testing.T) {
+	cfg := validConfig()
+	cfg.SubtitleProfiles = parseSubtitleProfiles("mobile=32:2:15:1.2,standard=40:2")
+	cfg.SubtitleProfile = "mobile"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	if profile, _ := cfg.SubtitleProfileByName("mobile"); profile != (models.SubtitleProfile{MaxLineChars: 32, MaxLines: 2, MaxCPS: 15, MinDuration: 1.2}) {
+		t.Errorf("unexpected mobile profile: %+v", profile)
+	}
+	if profile, _ := cfg.SubtitleProfileByName("standard"); profile.MaxLineChars != 40 {
+		t.Errorf("expected SUBTITLE_PROFILES to override the built-in profile, got %+v", profile)
+	}
+	if _, ok := cfg.SubtitleProfileByName("broadcast"); !ok {
+		t.Error("expected built-in profiles to remain available")
+	}
+
+	cfg.SubtitleProfile = "cinema"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an unknown SUBTITLE_PROFILE")
+	}
+
+	cfg.SubtitleProfile = ""
+	cfg.SubtitleProfiles = parseSubtitleProfiles("broken=32")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a profile without a line count")
+	}
+}
+
+func TestConfigValidation_SpendBudget(t *testing.T) {
+	cfg := validConfig()
+	cfg.SpendBudgetCost = 50
+	cfg.SpendBudgetWindow = 24 * time.Hour
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.SpendBudgetWindow = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a budget without a window")
+	}
+
+	cfg.SpendBudgetWindow = time.Hour
+	cfg.SpendBudgetTTSChars = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative limit")
+	}
+}
+
+func TestConfigValidation_GCSStorageClass(t *testing.T) {
+	cfg := validConfig()
+	cfg.GCSStorageClass = "COLDLINE"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.GCSStorageClass = "GLACIER"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an unknown storage class")
+	}
+}
+
+func TestConfigValidation_LLMMaxInputChars(t *testing.T) {
+	cfg := validConfig()
+	cfg.LLMMaxInputChars = 100000
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.LLMMaxInputChars = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative LLM_MAX_INPUT_CHARS")
+	}
+}
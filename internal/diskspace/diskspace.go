@@ -0,0 +1,151 @@
+package diskspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrInsufficientSpace is returned when a reservation does not fit in the free disk space
+var ErrInsufficientSpace = errors.New("insufficient disk space")
+
+// FreeFunc reports the bytes available to unprivileged users on the file system holding dir
+type FreeFunc func(dir string) (uint64, error)
+
+// Tracker reserves temp workspace for jobs before they download, so concurrent jobs
+// do not together exceed the free disk space and fail mid-download
+// Reservations count against the free space until released; data already written by a
+// running job is counted again by the file system, which keeps the estimate conservative.
+type Tracker struct {
+	Dir      string   // Workspace directory (os.TempDir)
+	Headroom int64    // Bytes always left free for other processes
+	Free     FreeFunc // Free space probe, defaults to the file system's
+
+	mu       sync.Mutex
+	reserved map[string]int64
+	changed  chan struct{} // Closed and replaced whenever a reservation is released
+}
+
+// NewTracker creates a tracker for the workspace directory
+func NewTracker(dir string, headroom int64) *Tracker {
+	return &Tracker{
+		Dir:      dir,
+		Headroom: headroom,
+		Free:     FreeBytes,
+		reserved: make(map[string]int64),
+		changed:  make(chan struct{}),
+	}
+}
+
+// Available returns the free bytes not yet reserved by jobs, minus the headroom
+// The result may be negative when reservations exceed the free space.
+func (t *Tracker) Available() (int64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.availableLocked()
+}
+
+func (t *Tracker) availableLocked() (int64, error) {
+	free, err := t.Free(t.Dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read free disk space: %w", err)
+	}
+	available := int64(free) - t.Headroom
+	for _, bytes := range t.reserved {
+		available -= bytes
+	}
+	return available, nil
+}
+
+// Fits reports whether a job needing bytes could ever run: the need fits in the free space
+// once every other reservation is released
+func (t *Tracker) Fits(bytes int64) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	available, err := t.availableLocked()
+	if err != nil {
+		return false, err
+	}
+	for _, reserved := range t.reserved {
+		available += reserved
+	}
+	return bytes <= available, nil
+}
+
+// Reserve claims bytes of workspace for a job, returning ErrInsufficientSpace if they are not available
+// A job holds at most one reservation; reserving again replaces it.
+func (t *Tracker) Reserve(jobID string, bytes int64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous := t.reserved[jobID]
+	delete(t.reserved, jobID)
+	available, err := t.availableLocked()
+	if err != nil {
+		t.restoreLocked(jobID, previous)
+		return err
+	}
+	if bytes > available {
+		t.restoreLocked(jobID, previous)
+		return fmt.Errorf("%w: need %d bytes, %d available", ErrInsufficientSpace, bytes, available)
+	}
+	t.reserved[jobID] = bytes
+	return nil
+}
+
+// restoreLocked puts back a reservation removed by a failed Reserve
+func (t *Tracker) restoreLocked(jobID string, bytes int64) {
+	if bytes > 0 {
+		t.reserved[jobID] = bytes
+	}
+}
+
+// Wait blocks until bytes can be reserved for the job, re-checking whenever another job releases its
+// reservation or pollInterval passes (files deleted outside the tracker also free space)
+func (t *Tracker) Wait(ctx context.Context, jobID string, bytes int64, pollInterval time.Duration) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		t.mu.Lock()
+		changed := t.changed
+		t.mu.Unlock()
+
+		err := t.Reserve(jobID, bytes)
+		if !errors.Is(err, ErrInsufficientSpace) {
+			return err
+		}
+
+		select {
+		case <-changed:
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Release frees the job's reservation and wakes waiting jobs
+func (t *Tracker) Release(jobID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.reserved[jobID]; !ok {
+		return
+	}
+	delete(t.reserved, jobID)
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+// Reserved returns the total bytes currently reserved
+func (t *Tracker) Reserved() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var total int64
+	for _, bytes := range t.reserved {
+		total += bytes
+	}
+	return total
+}
@@ -0,0 +1,116 @@
+package diskspace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// fixedFree reports a constant amount of free space
+func fixedFree(bytes uint64) FreeFunc {
+	return func(dir string) (uint64, error) {
+		return bytes, nil
+	}
+}
+
+func TestTracker_Reserve(t *testing.T) {
+	tracker := NewTracker("/tmp", 100)
+	tracker.Free = fixedFree(1000)
+
+	if err := tracker.Reserve("job-1", 600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tracker.Reserve("job-2", 400); !errors.Is(err, ErrInsufficientSpace) {
+		t.Errorf("expected ErrInsufficientSpace, got %v", err)
+	}
+	if err := tracker.Reserve("job-2", 300); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := tracker.Reserved(); got != 900 {
+		t.Errorf("expected 900 reserved bytes, got %d", got)
+	}
+
+	// Reserving again replaces the job's reservation
+	if err := tracker.Reserve("job-1", 500); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := tracker.Reserved(); got != 800 {
+		t.Errorf("expected 800 reserved bytes, got %d", got)
+	}
+
+	// A failed re-reservation keeps the previous one
+	if err := tracker.Reserve("job-1", 900); !errors.Is(err, ErrInsufficientSpace) {
+		t.Errorf("expected ErrInsufficientSpace, got %v", err)
+	}
+	if got := tracker.Reserved(); got != 800 {
+		t.Errorf("expected 800 reserved bytes, got %d", got)
+	}
+
+	tracker.Release("job-1")
+	if available, _ := tracker.Available(); available != 600 {
+		t.Errorf("expected 600 available bytes, got %d", available)
+	}
+}
+
+func TestTracker_Fits(t *testing.T) {
+	tracker := NewTracker("/tmp", 100)
+	tracker.Free = fixedFree(1000)
+	tracker.Reserve("job-1", 800)
+
+	if fits, _ := tracker.Fits(900); !fits {
+		t.Error("expected 900 bytes to fit once other jobs finish")
+	}
+	if fits, _ := tracker.Fits(901); fits {
+		t.Error("expected 901 bytes never to fit")
+	}
+}
+
+func TestTracker_WaitForRelease(t *testing.T) {
+	tracker := NewTracker("/tmp", 0)
+	tracker.Free = fixedFree(1000)
+	tracker.Reserve("job-1", 800)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tracker.Wait(context.Background(), "job-2", 500, time.Hour)
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("expected wait to block, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	tracker.Release("job-1")
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected wait to finish after release")
+	}
+}
+
+func TestTracker_WaitCancelled(t *testing.T) {
+	tracker := NewTracker("/tmp", 0)
+	tracker.Free = fixedFree(100)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := tracker.Wait(ctx, "job-1", 500, time.Hour); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestFreeBytes(t *testing.T) {
+	free, err := FreeBytes(os.TempDir())
+	if err != nil {
+		t.Skipf("free disk space unavailable: %v", err)
+	}
+	if free == 0 {
+		t.Error("expected free space in the temp directory")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package diskspace
+
+import "fmt"
+
+// FreeBytes is not supported on this platform; disk checks are skipped when it fails
+func FreeBytes(dir string) (uint64, error) {
+	return 0, fmt.Errorf("free disk space is not available on this platform")
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package diskspace
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// FreeBytes reports the bytes available to unprivileged users on the file system holding dir
+func FreeBytes(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, fmt.Errorf("statfs %s: %w", dir, err)
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
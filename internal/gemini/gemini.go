@@ -0,0 +1,242 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+)
+
+const (
+	// DefaultModel is used when no Gemini model is configured
+	DefaultModel = "gemini-1.5-flash-002"
+
+	// DefaultLocation is the Vertex AI region used when none is configured
+	DefaultLocation = "us-central1"
+
+	// MaxInlineAudioBytes bounds the audio sent inline in one request (Vertex AI accepts about 20 MB per request)
+	MaxInlineAudioBytes = 15 * 1024 * 1024
+
+	// cloudPlatformScope is the OAuth scope required by Vertex AI
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// Result is the transcript of a clip and its translations from a single model call
+type Result struct {
+	Transcript   string
+	Language     string            // Detected source language code
+	Translations map[string]string // Target language -> translated transcript
+}
+
+// Client transcribes and translates audio in one call to a Gemini model on Vertex AI
+type Client struct {
+	Project  string
+	Location string
+	Model    string
+	Endpoint string // Overrides the generateContent URL (used in tests)
+	client   *http.Client
+}
+
+// NewClient creates a Vertex AI Gemini client for the given project, region and model
+// Uses the credentials file if configured, otherwise default credentials
+func NewClient(ctx context.Context, project string, location string, model string, opts ...option.ClientOption) (*Client, error) {
+	if project == "" {
+		return nil, fmt.Errorf("Gemini requires a Google Cloud project")
+	}
+	if location == "" {
+		location = DefaultLocation
+	}
+	if model == "" {
+		model = DefaultModel
+	}
+	if credentialsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); credentialsPath != "" && len(opts) == 0 {
+		opts = append(opts, option.WithCredentialsFile(credentialsPath))
+	}
+	opts = append(opts, option.WithScopes(cloudPlatformScope))
+
+	httpClient, _, err := htransport.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+
+	return &Client{
+		Project:  project,
+		Location: location,
+		Model:    model,
+		Endpoint: fmt.Sprintf("https://%s-aiplatform.googleapis.com/v1/projects/%s/locations/%s/publishers/google/models/%s:generateContent",
+			location, project, location, model),
+		client: httpClient,
+	}, nil
+}
+
+// TranscribeAndTranslate transcribes a WAV file and translates the transcript into every target language
+// sourceLanguage is a hint; empty or "auto" lets the model detect it.
+func (c *Client) TranscribeAndTranslate(ctx context.Context, audioPath string, sourceLanguage string, targetLanguages []string) (*Result, error) {
+	audio, err := os.ReadFile(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio file: %w", err)
+	}
+	if len(audio) > MaxInlineAudioBytes {
+		return nil, fmt.Errorf("audio is %d bytes, more than the %d bytes Gemini accepts inline", len(audio), MaxInlineAudioBytes)
+	}
+
+	slog.Info("Transcribing and translating audio with Gemini",
+		"model", c.Model,
+		"location", c.Location,
+		"sourceLanguage", sourceLanguage,
+		"targetLanguages", targetLanguages,
+		"audioBytes", len(audio))
+
+	body := map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{
+				"role": "user",
+				"parts": []map[string]interface{}{
+					{"inlineData": map[string]string{
+						"mimeType": "audio/wav",
+						"data":     base64.StdEncoding.EncodeToString(audio),
+					}},
+					{"text": buildPrompt(sourceLanguage, targetLanguages)},
+				},
+			},
+		},
+		"generationConfig": map[string]interface{}{
+			"temperature":      0.2,
+			"responseMimeType": "application/json",
+		},
+	}
+
+	content, err := c.generateContent(ctx, body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("Gemini request cancelled: %w", ctx.Err())
+		}
+		return nil, err
+	}
+
+	result, err := parseResult(content, targetLanguages)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Gemini transcription and translation completed",
+		"language", result.Language,
+		"textLength", len(result.Transcript),
+		"translations", len(result.Translations))
+	return result, nil
+}
+
+// buildPrompt asks for the verbatim transcript and one spoken-style translation per target language as JSON
+func buildPrompt(sourceLanguage string, targetLanguages []string) string {
+	var b strings.Builder
+	b.WriteString("Transcribe the speech in this audio verbatim, then translate the transcript for video dubbing. ")
+	if sourceLanguage == "" || sourceLanguage == "auto" {
+		b.WriteString("Detect the spoken language. ")
+	} else {
+		fmt.Fprintf(&b, "The spoken language is %s. ", sourceLanguage)
+	}
+	fmt.Fprintf(&b, "Translate into these language codes: %s. ", strings.Join(targetLanguages, ", "))
+	b.WriteString("Translations must sound natural when spoken aloud and keep roughly the same length as the original. ")
+	b.WriteString(`Reply with only a JSON object of the form {"language": "<source language code>", "transcript": "<transcript>", "translations": {"<language code>": "<translation>"}}.`)
+	return b.String()
+}
+
+// parseResult decodes the model's JSON answer, tolerating markdown code fences, and checks every target is present
+func parseResult(content string, targetLanguages []string) (*Result, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("Gemini response does not contain a JSON object")
+	}
+
+	var answer struct {
+		Language     string            `json:"language"`
+		Transcript   string            `json:"transcript"`
+		Translations map[string]string `json:"translations"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &answer); err != nil {
+		return nil, fmt.Errorf("failed to parse Gemini response: %w", err)
+	}
+
+	result := &Result{
+		Transcript:   strings.TrimSpace(answer.Transcript),
+		Language:     strings.TrimSpace(answer.Language),
+		Translations: make(map[string]string, len(targetLanguages)),
+	}
+	if result.Transcript == "" {
+		return nil, fmt.Errorf("Gemini returned an empty transcript")
+	}
+	for _, language := range targetLanguages {
+		translated := strings.TrimSpace(answer.Translations[language])
+		if translated == "" {
+			return nil, fmt.Errorf("Gemini returned no translation for %s", language)
+		}
+		result.Translations[language] = translated
+	}
+	return result, nil
+}
+
+// generateContent posts a generateContent request and returns the concatenated text parts of the first candidate
+func (c *Client) generateContent(ctx context.Context, body interface{}) (string, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", &transient.HTTPError{Service: "gemini", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var out struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+	}
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(out.Candidates) == 0 {
+		return "", fmt.Errorf("no candidates returned")
+	}
+
+	var text strings.Builder
+	for _, part := range out.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("no text content returned")
+	}
+	return text.String(), nil
+}
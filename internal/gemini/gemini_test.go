@@ -0,0 +1,131 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+func TestNewClient(t *testing.T) {
+	client, err := NewClient(context.Background(), "my-project", "", "", option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.Model != DefaultModel || client.Location != DefaultLocation {
+		t.Errorf("expected default model and location, got %s and %s", client.Model, client.Location)
+	}
+	want := "https://us-central1-aiplatform.googleapis.com/v1/projects/my-project/locations/us-central1/publishers/google/models/" + DefaultModel + ":generateContent"
+	if client.Endpoint != want {
+		t.Errorf("expected endpoint %s, got %s", want, client.Endpoint)
+	}
+
+	if _, err := NewClient(context.Background(), "", "", "", option.WithoutAuthentication()); err == nil {
+		t.Error("expected error without a project")
+	}
+}
+
+func TestClient_TranscribeAndTranslate(t *testing.T) {
+	var received struct {
+		Contents []struct {
+			Parts []struct {
+				InlineData *struct {
+					MimeType string `json:"mimeType"`
+					Data     string `json:"data"`
+				} `json:"inlineData"`
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"contents"`
+		GenerationConfig map[string]interface{} `json:"generationConfig"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"{\"language\":\"en\",\"transcript\":\"Hello.\",\"translations\":{\"de\":\"Hallo.\",\"fr\":\"Bonjour.\"}}"}]}}]}`))
+	}))
+	defer server.Close()
+
+	audioPath := filepath.Join(t.TempDir(), "audio.wav")
+	if err := os.WriteFile(audioPath, []byte("RIFF"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewClient(context.Background(), "p", "", "", option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Endpoint = server.URL
+
+	result, err := client.TranscribeAndTranslate(context.Background(), audioPath, "auto", []string{"de", "fr"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Transcript != "Hello." || result.Language != "en" {
+		t.Errorf("expected English transcript, got %q (%s)", result.Transcript, result.Language)
+	}
+	if result.Translations["de"] != "Hallo." || result.Translations["fr"] != "Bonjour." {
+		t.Errorf("unexpected translations: %v", result.Translations)
+	}
+
+	if len(received.Contents) != 1 || len(received.Contents[0].Parts) != 2 {
+		t.Fatalf("expected one content with audio and prompt parts, got %+v", received.Contents)
+	}
+	audio := received.Contents[0].Parts[0].InlineData
+	if audio == nil || audio.MimeType != "audio/wav" || audio.Data != "UklGRg==" {
+		t.Errorf("expected inline base64 WAV audio, got %+v", audio)
+	}
+	if !strings.Contains(received.Contents[0].Parts[1].Text, "de, fr") {
+		t.Errorf("expected prompt to list target languages, got %q", received.Contents[0].Parts[1].Text)
+	}
+	if received.GenerationConfig["responseMimeType"] != "application/json" {
+		t.Errorf("expected JSON response mime type, got %v", received.GenerationConfig["responseMimeType"])
+	}
+}
+
+func TestParseResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"plain JSON", `{"language":"en","transcript":"Hi","translations":{"de":"Hallo"}}`, false},
+		{"code fence", "```json\n{\"language\":\"en\",\"transcript\":\"Hi\",\"translations\":{\"de\":\"Hallo\"}}\n```", false},
+		{"missing translation", `{"language":"en","transcript":"Hi","translations":{"fr":"Salut"}}`, true},
+		{"empty transcript", `{"language":"en","transcript":" ","translations":{"de":"Hallo"}}`, true},
+		{"no JSON", "sorry", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseResult(tt.content, []string{"de"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && result.Translations["de"] != "Hallo" {
+				t.Errorf("expected translation Hallo, got %q", result.Translations["de"])
+			}
+		})
+	}
+}
+
+func TestClient_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	audioPath := filepath.Join(t.TempDir(), "audio.wav")
+	os.WriteFile(audioPath, []byte("RIFF"), 0o644)
+
+	client, _ := NewClient(context.Background(), "p", "", "", option.WithoutAuthentication())
+	client.Endpoint = server.URL
+
+	if _, err := client.TranscribeAndTranslate(context.Background(), audioPath, "en", []string{"de"}); err == nil {
+		t.Error("expected error for 429 response")
+	}
+}
@@ -0,0 +1,91 @@
+package instance
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is where the container's cgroup v2 limits are mounted
+var cgroupRoot = "/sys/fs/cgroup"
+
+// Resources describes the CPU and memory available to this instance and where it runs
+type Resources struct {
+	CPUs        float64 // CPU quota, or the number of logical CPUs when unlimited
+	MemoryBytes int64   // Memory limit, 0 when unknown or unlimited
+	Service     string  // Cloud Run service name (K_SERVICE), empty elsewhere
+	Revision    string  // Cloud Run revision name (K_REVISION)
+}
+
+// Detect reads the instance's CPU and memory limits from cgroup v2, falling back to the host
+func Detect() Resources {
+	res := Resources{
+		CPUs:     float64(runtime.NumCPU()),
+		Service:  os.Getenv("K_SERVICE"),
+		Revision: os.Getenv("K_REVISION"),
+	}
+	if cpus, ok := readCPUQuota(filepath.Join(cgroupRoot, "cpu.max")); ok && cpus < res.CPUs {
+		res.CPUs = cpus
+	}
+	if memory, ok := readMemoryLimit(filepath.Join(cgroupRoot, "memory.max")); ok {
+		res.MemoryBytes = memory
+	}
+	return res
+}
+
+// OnCloudRun reports whether the instance is a Cloud Run container
+func (r Resources) OnCloudRun() bool {
+	return r.Service != ""
+}
+
+// JobCap returns how many jobs the instance can process at once: one per whole CPU,
+// lowered so every job gets jobMemoryBytes of memory when the memory limit is known
+// The cap is at least 1.
+func (r Resources) JobCap(jobMemoryBytes int64) int {
+	jobs := int(math.Floor(r.CPUs))
+	if r.MemoryBytes > 0 && jobMemoryBytes > 0 {
+		jobs = min(jobs, int(r.MemoryBytes/jobMemoryBytes))
+	}
+	return max(jobs, 1)
+}
+
+// readCPUQuota parses cpu.max ("<quota> <period>" or "max <period>") into a CPU count
+func readCPUQuota(path string) (float64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0, false
+	}
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || quota <= 0 {
+		return 0, false
+	}
+	period, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || period <= 0 {
+		return 0, false
+	}
+	return quota / period, true
+}
+
+// readMemoryLimit parses memory.max, which is a byte count or "max"
+func readMemoryLimit(path string) (int64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, false
+	}
+	bytes, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || bytes <= 0 {
+		return 0, false
+	}
+	return bytes, true
+}
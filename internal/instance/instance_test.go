@@ -0,0 +1,79 @@
+package instance
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// withCgroup points Detect at a temp directory holding the given cpu.max and memory.max contents
+func withCgroup(t *testing.T, cpuMax string, memoryMax string) {
+	t.Helper()
+	dir := t.TempDir()
+	if cpuMax != "" {
+		os.WriteFile(filepath.Join(dir, "cpu.max"), []byte(cpuMax), 0o644)
+	}
+	if memoryMax != "" {
+		os.WriteFile(filepath.Join(dir, "memory.max"), []byte(memoryMax), 0o644)
+	}
+	previous := cgroupRoot
+	cgroupRoot = dir
+	t.Cleanup(func() { cgroupRoot = previous })
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("K_SERVICE", "video-processor")
+	t.Setenv("K_REVISION", "video-processor-00042")
+	withCgroup(t, "100000 100000\n", "4294967296\n")
+
+	res := Detect()
+	if res.CPUs != min(1, float64(runtime.NumCPU())) {
+		t.Errorf("expected 1 CPU, got %v", res.CPUs)
+	}
+	if res.MemoryBytes != 4<<30 {
+		t.Errorf("expected 4 GiB memory, got %d", res.MemoryBytes)
+	}
+	if !res.OnCloudRun() || res.Revision != "video-processor-00042" {
+		t.Errorf("expected Cloud Run revision, got %+v", res)
+	}
+}
+
+func TestDetect_Unlimited(t *testing.T) {
+	t.Setenv("K_SERVICE", "")
+	withCgroup(t, "max 100000\n", "max\n")
+
+	res := Detect()
+	if res.CPUs != float64(runtime.NumCPU()) {
+		t.Errorf("expected %d CPUs, got %v", runtime.NumCPU(), res.CPUs)
+	}
+	if res.MemoryBytes != 0 {
+		t.Errorf("expected unknown memory, got %d", res.MemoryBytes)
+	}
+	if res.OnCloudRun() {
+		t.Error("expected not to be on Cloud Run")
+	}
+}
+
+func TestResources_JobCap(t *testing.T) {
+	const gib = int64(1 << 30)
+	tests := []struct {
+		name      string
+		res       Resources
+		jobMemory int64
+		want      int
+	}{
+		{"cpu bound", Resources{CPUs: 4, MemoryBytes: 16 * gib}, gib, 4},
+		{"memory bound", Resources{CPUs: 8, MemoryBytes: 2 * gib}, gib, 2},
+		{"fractional cpu", Resources{CPUs: 2.5}, gib, 2},
+		{"unknown memory", Resources{CPUs: 4}, gib, 4},
+		{"at least one", Resources{CPUs: 0.5, MemoryBytes: gib / 2}, gib, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.res.JobCap(tt.jobMemory); got != tt.want {
+				t.Errorf("expected %d jobs, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sinouw/multilingual-video-processor/internal/stt"
+	"github.com/sinouw/multilingual-video-processor/internal/translation"
+	"github.com/sinouw/multilingual-video-processor/internal/tts"
+)
+
+// ProviderName identifies the mock providers in capabilities and logs
+const ProviderName = "mock"
+
+// Transcript is the canned text every recognition returns
+const Transcript = "This is a mock transcript. It lets the whole pipeline run locally without cloud credentials."
+
+// Recognize returns the canned transcript in the hinted language (English without a hint)
+// It implements stt.RecognizeFunc.
+func Recognize(ctx context.Context, audioPath string, opts stt.Options) (*stt.SpeechToTextResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("speech-to-text cancelled: %w", err)
+	}
+	if _, err := os.Stat(audioPath); err != nil {
+		return nil, fmt.Errorf("failed to read audio file: %w", err)
+	}
+
+	language := opts.LanguageHint
+	if language == "" {
+		language = "en"
+	}
+	return &stt.SpeechToTextResponse{Text: Transcript, Language: language, Confidence: 1}, nil
+}
+
+var _ translation.TranslationService = (*Translator)(nil)
+
+// Translator "translates" by prefixing text with the target language, e.g. "[de] Hello"
+type Translator struct{}
+
+// Name implements TranslationService interface
+func (t *Translator) Name() string {
+	return ProviderName
+}
+
+// TranslateText implements TranslationService interface
+func (t *Translator) TranslateText(ctx context.Context, text string, sourceLanguage string, targetLanguage string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("translation cancelled: %w", err)
+	}
+	return fmt.Sprintf("[%s] %s", targetLanguage, text), nil
+}
+
+// TranslateBatch implements TranslationService interface
+func (t *Translator) TranslateBatch(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+	translations := make([]string, len(texts))
+	for i, text := range texts {
+		translated, err := t.TranslateText(ctx, text, sourceLanguage, targetLanguage)
+		if err != nil {
+			return nil, err
+		}
+		translations[i] = translated
+	}
+	return translations, nil
+}
+
+// silentFrame is one MPEG-1 Layer III frame (128 kbps, 44.1 kHz, mono) whose zeroed side information
+// decodes to silence. Frames can be concatenated, like the MP3 segments the TTS package joins.
+var silentFrame = func() []byte {
+	frame := make([]byte, 417) // 144 * 128000 / 44100 bytes, without padding
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0xC4})
+	return frame
+}()
+
+// frameSeconds is the duration of one MP3 frame (1152 samples)
+const frameSeconds = 1152.0 / 44100.0
+
+var (
+	ssmlTagPattern   = regexp.MustCompile(`<[^>]*>`)
+	ssmlRatePattern  = regexp.MustCompile(`<prosody rate="(\d+)%">`)
+	ssmlBreakPattern = regexp.MustCompile(`<break time="(\d+)ms"/>`)
+)
+
+// Synthesize returns silent MP3 audio lasting as long as the SSML would take to speak
+// It implements tts.SynthesizeFunc.
+func Synthesize(ctx context.Context, ssmlText string, voiceConfig *tts.VoiceConfig) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("TTS generation cancelled: %w", err)
+	}
+
+	language, _, _ := strings.Cut(voiceConfig.LanguageCode, "-")
+	frames := int(speechSeconds(ssmlText, tts.GetSpeakingRate(language))/frameSeconds) + 1
+
+	audio := make([]byte, 0, frames*len(silentFrame))
+	for range frames {
+		audio = append(audio, silentFrame...)
+	}
+	return audio, nil
+}
+
+// speechSeconds estimates how long SSML takes to speak at wordsPerMinute, honouring its prosody rate and breaks
+func speechSeconds(ssmlText string, wordsPerMinute float64) float64 {
+	words := len(strings.Fields(ssmlTagPattern.ReplaceAllString(ssmlText, " ")))
+	seconds := float64(words) / wordsPerMinute * 60
+
+	if match := ssmlRatePattern.FindStringSubmatch(ssmlText); match != nil {
+		if rate, err := strconv.Atoi(match[1]); err == nil && rate > 0 {
+			seconds = seconds * 100 / float64(rate)
+		}
+	}
+	for _, match := range ssmlBreakPattern.FindAllStringSubmatch(ssmlText, -1) {
+		if millis, err := strconv.Atoi(match[1]); err == nil {
+			seconds += float64(millis) / 1000
+		}
+	}
+	return seconds
+}
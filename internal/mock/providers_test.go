@@ -0,0 +1,85 @@
+package mock
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/internal/stt"
+	"github.com/sinouw/multilingual-video-processor/internal/tts"
+)
+
+func TestRecognize(t *testing.T) {
+	audioPath := filepath.Join(t.TempDir(), "audio.wav")
+	if err := os.WriteFile(audioPath, []byte("RIFF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Recognize(context.Background(), audioPath, stt.Options{LanguageHint: "fr"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Text != Transcript || result.Language != "fr" || result.Confidence != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if _, err := Recognize(context.Background(), filepath.Join(t.TempDir(), "missing.wav"), stt.Options{}); err == nil {
+		t.Error("expected error for missing audio file")
+	}
+}
+
+func TestTranslator_TranslateBatch(t *testing.T) {
+	translator := &Translator{}
+	got, err := translator.TranslateBatch(context.Background(), []string{"Hello.", "Goodbye."}, "en", "de")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != "[de] Hello." || got[1] != "[de] Goodbye." {
+		t.Errorf("unexpected translations %v", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := translator.TranslateBatch(ctx, []string{"Hello."}, "en", "de"); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
+
+func TestSynthesize(t *testing.T) {
+	// 15 words at 150 words per minute take 6 seconds
+	text := strings.TrimSpace(strings.Repeat("word ", 15))
+	audio, err := Synthesize(context.Background(), `<speak><prosody rate="100%">`+text+`</prosody></speak>`, &tts.VoiceConfig{LanguageCode: "en-US"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(audio)%len(silentFrame) != 0 || audio[0] != 0xFF || audio[1] != 0xFB {
+		t.Fatal("expected whole MP3 frames")
+	}
+	if seconds := float64(len(audio)/len(silentFrame)) * frameSeconds; math.Abs(seconds-6) > 0.1 {
+		t.Errorf("expected about 6 seconds of audio, got %.2f", seconds)
+	}
+}
+
+func TestSpeechSeconds(t *testing.T) {
+	tests := []struct {
+		name string
+		ssml string
+		want float64
+	}{
+		{"plain", `<speak><prosody rate="100%">one two three</prosody></speak>`, 1.2},
+		{"fast", `<speak><prosody rate="200%">one two three</prosody></speak>`, 0.6},
+		{"breaks", `<break time="1500ms"/><speak><prosody rate="100%">one two three</prosody></speak>`, 2.7},
+		{"empty", `<speak></speak>`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := speechSeconds(tt.ssml, 150); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("expected %v seconds, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package moderation
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// apiMaskedWord matches words already masked by the Speech API profanity filter (e.g., "f***")
+var apiMaskedWord = regexp.MustCompile(`^\pL\*+$`)
+
+// ProfanityFilter masks words from a configurable wordlist
+type ProfanityFilter struct {
+	words map[string]bool
+}
+
+// NewProfanityFilter creates a filter for the given words (matched case-insensitively)
+func NewProfanityFilter(words []string) *ProfanityFilter {
+	f := &ProfanityFilter{words: make(map[string]bool, len(words))}
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" {
+			f.words[word] = true
+		}
+	}
+	return f
+}
+
+// Mask replaces listed words with their first letter followed by asterisks
+// Returns the filtered text and the masked form of every redacted term, including
+// terms already masked upstream by the Speech API. Punctuation around words is preserved.
+func (f *ProfanityFilter) Mask(text string) (string, []string) {
+	var redacted []string
+	fields := strings.Fields(text)
+	for i, field := range fields {
+		word, prefix, suffix := splitPunctuation(field)
+		if word == "" {
+			continue
+		}
+
+		if apiMaskedWord.MatchString(word) {
+			redacted = append(redacted, word)
+			continue
+		}
+
+		if f.words[strings.ToLower(word)] {
+			masked := maskWord(word)
+			fields[i] = prefix + masked + suffix
+			redacted = append(redacted, masked)
+		}
+	}
+
+	if len(redacted) == 0 {
+		return text, nil
+	}
+	return strings.Join(fields, " "), redacted
+}
+
+// maskWord keeps the first letter and replaces the rest with asterisks
+func maskWord(word string) string {
+	first, size := utf8.DecodeRuneInString(word)
+	return string(first) + strings.Repeat("*", utf8.RuneCountInString(word[size:]))
+}
+
+// splitPunctuation separates leading and trailing punctuation from a word
+func splitPunctuation(field string) (word, prefix, suffix string) {
+	start := strings.IndexFunc(field, isWordRune)
+	if start == -1 {
+		return "", field, ""
+	}
+	end := strings.LastIndexFunc(field, isWordRune)
+	_, size := utf8.DecodeRuneInString(field[end:])
+	return field[start : end+size], field[:start], field[end+size:]
+}
+
+func isWordRune(r rune) bool {
+	return r == '*' || r == '\'' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,35 @@
+package moderation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProfanityFilter_Mask(t *testing.T) {
+	filter := NewProfanityFilter([]string{"darn", "Heck"})
+
+	tests := []struct {
+		name         string
+		text         string
+		wantText     string
+		wantRedacted []string
+	}{
+		{"clean text", "hello world", "hello world", nil},
+		{"listed word", "well darn it", "well d*** it", []string{"d***"}},
+		{"case and punctuation", "Oh HECK!", "Oh H***!", []string{"H***"}},
+		{"api masked word", "what the f*** happened", "what the f*** happened", []string{"f***"}},
+		{"substring not matched", "darned socks", "darned socks", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, redacted := filter.Mask(tt.text)
+			if text != tt.wantText {
+				t.Errorf("expected text %q, got %q", tt.wantText, text)
+			}
+			if !reflect.DeepEqual(redacted, tt.wantRedacted) {
+				t.Errorf("expected redacted %v, got %v", tt.wantRedacted, redacted)
+			}
+		})
+	}
+}
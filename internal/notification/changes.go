@@ -0,0 +1,150 @@
+package notification
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// Changes describes what changed in a job since the previous webhook notification,
+// so receivers can process deltas instead of diffing the full results themselves
+type Changes struct {
+	Since     string                    `json:"since,omitempty"`     // Timestamp of the previous notification, empty for the first
+	Status    *ValueChange              `json:"status,omitempty"`    // Job status change
+	Stage     *ValueChange              `json:"stage,omitempty"`     // Pipeline stage change
+	Languages map[string]LanguageChange `json:"languages,omitempty"` // Languages whose result changed
+
+	jobID   string      // Job the changes were computed for
+	current jobSnapshot // State to remember once the changes are delivered
+}
+
+// ValueChange is a value before and after a change; From is empty for new values
+type ValueChange struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+}
+
+// LanguageChange describes how one language's result changed
+type LanguageChange struct {
+	Status *ValueChange `json:"status,omitempty"` // Language status change
+	Fields []string     `json:"fields,omitempty"` // Other result fields that changed (e.g., videoUrl, error), sorted
+}
+
+// ChangeTracker remembers the last notified state of each job to compute the changes in the next notification
+type ChangeTracker struct {
+	mu   sync.Mutex
+	last map[string]jobSnapshot
+}
+
+// jobSnapshot is the notified state of a job
+type jobSnapshot struct {
+	at        string
+	status    string
+	stage     string
+	languages map[string]languageSnapshot
+}
+
+// languageSnapshot holds the fields of a language result compared between notifications
+type languageSnapshot map[string]string
+
+// NewChangeTracker creates an empty change tracker
+func NewChangeTracker() *ChangeTracker {
+	return &ChangeTracker{last: make(map[string]jobSnapshot)}
+}
+
+// Diff returns the changes in a job since the last committed notification for it
+// Every language of the first notification of a job is reported as changed.
+// The job's current state is only remembered once the changes are passed to Commit,
+// so a failed delivery is reported again in the next notification.
+func (t *ChangeTracker) Diff(jobStatus *models.StatusResponse, timestamp string) *Changes {
+	current := jobSnapshot{
+		at:        timestamp,
+		status:    string(jobStatus.Status),
+		stage:     jobStatus.Stage,
+		languages: make(map[string]languageSnapshot, len(jobStatus.Results)),
+	}
+	for language, result := range jobStatus.Results {
+		current.languages[language] = snapshotLanguage(result)
+	}
+
+	t.mu.Lock()
+	previous := t.last[jobStatus.JobID]
+	t.mu.Unlock()
+
+	changes := &Changes{
+		Since:   previous.at,
+		Status:  changeOf(previous.status, current.status),
+		Stage:   changeOf(previous.stage, current.stage),
+		jobID:   jobStatus.JobID,
+		current: current,
+	}
+	for language, now := range current.languages {
+		before := previous.languages[language]
+		change := LanguageChange{Status: changeOf(before["status"], now["status"])}
+		for field, value := range now {
+			if field != "status" && before[field] != value {
+				change.Fields = append(change.Fields, field)
+			}
+		}
+		for field := range before {
+			if _, ok := now[field]; !ok && field != "status" {
+				change.Fields = append(change.Fields, field) // Cleared, e.g. the error of a retried language
+			}
+		}
+		if change.Status == nil && len(change.Fields) == 0 {
+			continue
+		}
+		sort.Strings(change.Fields)
+		if changes.Languages == nil {
+			changes.Languages = make(map[string]LanguageChange)
+		}
+		changes.Languages[language] = change
+	}
+	return changes
+}
+
+// Commit remembers the job state the changes were computed from, after they were delivered
+func (t *ChangeTracker) Commit(changes *Changes) {
+	if changes == nil || changes.jobID == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.last[changes.jobID] = changes.current
+}
+
+// Forget drops the state recorded for a job
+func (t *ChangeTracker) Forget(jobID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.last, jobID)
+}
+
+// snapshotLanguage keeps the compared fields of a language result under their JSON names, omitting empty ones
+func snapshotLanguage(result *models.LanguageResult) languageSnapshot {
+	fields := map[string]string{
+		"status":            string(result.Status),
+		"videoUrl":          result.VideoURL,
+		"subtitlesUrl":      result.SubtitlesURL,
+		"translatedTextUrl": result.TranslatedTextURL,
+		"audioUrl":          result.AudioURL,
+		"error":             result.Error,
+		"errorCode":         result.ErrorCode,
+	}
+	snapshot := make(languageSnapshot, len(fields))
+	for field, value := range fields {
+		if value != "" {
+			snapshot[field] = value
+		}
+	}
+	return snapshot
+}
+
+// changeOf returns the change between two values, or nil if they are equal
+func changeOf(from string, to string) *ValueChange {
+	if from == to {
+		return nil
+	}
+	return &ValueChange{From: from, To: to}
+}
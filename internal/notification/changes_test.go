@@ -0,0 +1,130 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestChangeTracker_Diff(t *testing.T) {
+	tracker := NewChangeTracker()
+	status := &models.StatusResponse{
+		JobID:  "job-1",
+		Status: models.StatusProcessing,
+		Stage:  "translating",
+		Results: map[string]*models.LanguageResult{
+			"de": {Status: models.StatusProcessing},
+			"fr": {Status: models.StatusProcessing},
+		},
+	}
+
+	first := tracker.Diff(status, "t1")
+	tracker.Commit(first)
+	if first.Since != "" || first.Status == nil || first.Status.To != "processing" {
+		t.Errorf("expected first notification to report the initial status, got %+v", first)
+	}
+	if len(first.Languages) != 2 {
+		t.Errorf("expected every language to be new, got %v", first.Languages)
+	}
+
+	status.Stage = "uploading"
+	status.Results["de"] = &models.LanguageResult{Status: models.StatusCompleted, VideoURL: "https://example.com/de.mp4"}
+	second := tracker.Diff(status, "t2")
+	tracker.Commit(second)
+	if second.Since != "t1" {
+		t.Errorf("expected changes since t1, got %q", second.Since)
+	}
+	if second.Status != nil {
+		t.Errorf("expected unchanged job status, got %+v", second.Status)
+	}
+	if second.Stage == nil || second.Stage.From != "translating" || second.Stage.To != "uploading" {
+		t.Errorf("expected stage change, got %+v", second.Stage)
+	}
+	de, ok := second.Languages["de"]
+	if !ok || de.Status == nil || de.Status.From != "processing" || de.Status.To != "completed" {
+		t.Fatalf("expected de to complete, got %+v", second.Languages)
+	}
+	if !reflect.DeepEqual(de.Fields, []string{"videoUrl"}) {
+		t.Errorf("expected videoUrl to change, got %v", de.Fields)
+	}
+	if _, ok := second.Languages["fr"]; ok {
+		t.Error("expected unchanged fr to be omitted")
+	}
+
+	// A retried language clears its error
+	status.Results["fr"] = &models.LanguageResult{Status: models.StatusFailed, Error: "tts failed"}
+	tracker.Commit(tracker.Diff(status, "t3"))
+	status.Results["fr"] = &models.LanguageResult{Status: models.StatusProcessing}
+	fourth := tracker.Diff(status, "t4")
+	if fr := fourth.Languages["fr"]; !reflect.DeepEqual(fr.Fields, []string{"error"}) {
+		t.Errorf("expected cleared error to be reported, got %+v", fr)
+	}
+
+	tracker.Forget("job-1")
+	if again := tracker.Diff(status, "t5"); again.Since != "" {
+		t.Errorf("expected forgotten job to start over, got since %q", again.Since)
+	}
+}
+
+func TestWebhookNotifier_Changes(t *testing.T) {
+	var received Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	notifier.Changes = NewChangeTracker()
+	status := &models.StatusResponse{
+		JobID:   "job-1",
+		Status:  models.StatusCompleted,
+		Results: map[string]*models.LanguageResult{"de": {Status: models.StatusCompleted}},
+	}
+
+	if err := notifier.Notify(context.Background(), status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received.Changes == nil || received.Changes.Languages["de"].Status == nil {
+		t.Errorf("expected changes in payload, got %+v", received.Changes)
+	}
+}
+
+func TestWebhookNotifier_ChangesAfterFailedDelivery(t *testing.T) {
+	var received Payload
+	var fail atomic.Bool
+	fail.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	notifier.Policy = RetryPolicy{MaxAttempts: 1}
+	notifier.Changes = NewChangeTracker()
+	status := &models.StatusResponse{
+		JobID:   "job-1",
+		Status:  models.StatusCompleted,
+		Results: map[string]*models.LanguageResult{"de": {Status: models.StatusCompleted}},
+	}
+
+	if err := notifier.Notify(context.Background(), status); err == nil {
+		t.Fatal("expected the rejected delivery to fail")
+	}
+	fail.Store(false)
+	if err := notifier.Notify(context.Background(), status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received.Changes == nil || received.Changes.Since != "" || received.Changes.Languages["de"].Status == nil {
+		t.Errorf("expected the undelivered changes to be reported again, got %+v", received.Changes)
+	}
+}
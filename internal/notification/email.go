@@ -0,0 +1,223 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+const (
+	SendGridAPIURL = "https://api.sendgrid.com/v3/mail/send"
+)
+
+// EmailMessage represents a plain-text email
+type EmailMessage struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string
+}
+
+// EmailSender defines the interface for email delivery backends
+type EmailSender interface {
+	Send(ctx context.Context, msg EmailMessage) error
+}
+
+// EmailNotifier sends job status notifications by email
+type EmailNotifier struct {
+	Sender EmailSender
+	From   string
+	To     []string
+	Policy RetryPolicy
+}
+
+// NewEmailNotifier creates an email notifier for the given recipients
+func NewEmailNotifier(sender EmailSender, from string, to ...string) *EmailNotifier {
+	return &EmailNotifier{
+		Sender: sender,
+		From:   from,
+		To:     to,
+		Policy: DefaultRetryPolicy(),
+	}
+}
+
+// Name implements Notifier
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+// Notify implements Notifier
+func (n *EmailNotifier) Notify(ctx context.Context, jobStatus *models.StatusResponse) error {
+	if n.Sender == nil || len(n.To) == 0 {
+		return nil // Email not configured, skip
+	}
+
+	msg := BuildEmailMessage(NewPayload(jobStatus))
+	msg.From = n.From
+	msg.To = n.To
+
+	return deliverWithRetry(ctx, n.Name(), jobStatus.JobID, n.Policy, func(ctx context.Context) error {
+		return n.Sender.Send(ctx, msg)
+	})
+}
+
+// BuildEmailMessage renders the subject and body of a job notification email
+func BuildEmailMessage(payload Payload) EmailMessage {
+	subject := fmt.Sprintf("Translation job %s %s", payload.JobID, payload.Status)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Job ID: %s\n", payload.JobID)
+	fmt.Fprintf(&body, "Status: %s\n", payload.Status)
+	if payload.Error != "" {
+		fmt.Fprintf(&body, "Error: %s\n", payload.Error)
+	}
+
+	if len(payload.Results) > 0 {
+		// Sort languages for a stable message layout
+		languages := make([]string, 0, len(payload.Results))
+		for lang := range payload.Results {
+			languages = append(languages, lang)
+		}
+		sort.Strings(languages)
+
+		body.WriteString("\nResults:\n")
+		for _, lang := range languages {
+			result := payload.Results[lang]
+			fmt.Fprintf(&body, "- %s: %s", lang, result.Status)
+			if result.VideoURL != "" {
+				fmt.Fprintf(&body, " %s", result.VideoURL)
+			}
+			if result.Error != "" {
+				fmt.Fprintf(&body, " (%s)", result.Error)
+			}
+			body.WriteString("\n")
+		}
+	}
+
+	return EmailMessage{
+		Subject: subject,
+		Body:    body.String(),
+	}
+}
+
+// SMTPSender delivers email through an SMTP server
+type SMTPSender struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// Send implements EmailSender
+func (s *SMTPSender) Send(ctx context.Context, msg EmailMessage) error {
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	var data strings.Builder
+	fmt.Fprintf(&data, "From: %s\r\n", msg.From)
+	fmt.Fprintf(&data, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&data, "Subject: %s\r\n", msg.Subject)
+	data.WriteString("MIME-Version: 1.0\r\n")
+	data.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	data.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	// net/smtp has no context support, so run the send in the background
+	sendDone := make(chan error, 1)
+	go func() {
+		sendDone <- smtp.SendMail(addr, auth, msg.From, msg.To, []byte(data.String()))
+	}()
+
+	select {
+	case err := <-sendDone:
+		if err != nil {
+			return fmt.Errorf("failed to send email via SMTP: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("email send cancelled: %w", ctx.Err())
+	}
+}
+
+// SendGridSender delivers email through the SendGrid v3 API
+type SendGridSender struct {
+	APIKey   string
+	Endpoint string // Defaults to SendGridAPIURL
+	client   *http.Client
+}
+
+// NewSendGridSender creates a SendGrid email sender
+func NewSendGridSender(apiKey string) *SendGridSender {
+	return &SendGridSender{
+		APIKey:   apiKey,
+		Endpoint: SendGridAPIURL,
+		client:   &http.Client{},
+	}
+}
+
+// Send implements EmailSender
+func (s *SendGridSender) Send(ctx context.Context, msg EmailMessage) error {
+	type address struct {
+		Email string `json:"email"`
+	}
+	type content struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	type personalization struct {
+		To []address `json:"to"`
+	}
+
+	to := make([]address, 0, len(msg.To))
+	for _, recipient := range msg.To {
+		to = append(to, address{Email: recipient})
+	}
+
+	body, err := json.Marshal(struct {
+		Personalizations []personalization `json:"personalizations"`
+		From             address           `json:"from"`
+		Subject          string            `json:"subject"`
+		Content          []content         `json:"content"`
+	}{
+		Personalizations: []personalization{{To: to}},
+		From:             address{Email: msg.From},
+		Subject:          msg.Subject,
+		Content:          []content{{Type: "text/plain", Value: msg.Body}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal SendGrid request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return fmt.Errorf("failed to create SendGrid request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := s.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send email via SendGrid: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("SendGrid API returned status %d", resp.StatusCode)
+	}
+	return nil
+}
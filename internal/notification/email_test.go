@@ -0,0 +1,109 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// fakeEmailSender records sent messages and can fail a number of times
+type fakeEmailSender struct {
+	sent     []EmailMessage
+	failures int
+}
+
+func (f *fakeEmailSender) Send(ctx context.Context, msg EmailMessage) error {
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("temporary failure")
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func TestEmailNotifier_Notify(t *testing.T) {
+	sender := &fakeEmailSender{failures: 1}
+	notifier := NewEmailNotifier(sender, "noreply@example.com", "user@example.com")
+	notifier.Policy.Backoff = time.Millisecond
+
+	status := &models.StatusResponse{
+		JobID:  "job-1",
+		Status: models.StatusCompleted,
+		Results: map[string]*models.LanguageResult{
+			"en": {Status: models.StatusCompleted, VideoURL: "https://storage.googleapis.com/bucket/en.mp4"},
+		},
+	}
+
+	if err := notifier.Notify(context.Background(), status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("expected 1 email sent, got %d", len(sender.sent))
+	}
+
+	msg := sender.sent[0]
+	if msg.From != "noreply@example.com" {
+		t.Errorf("expected from 'noreply@example.com', got '%s'", msg.From)
+	}
+	if len(msg.To) != 1 || msg.To[0] != "user@example.com" {
+		t.Errorf("unexpected recipients: %v", msg.To)
+	}
+	if !strings.Contains(msg.Subject, "job-1") {
+		t.Errorf("expected subject to mention job ID, got '%s'", msg.Subject)
+	}
+	if !strings.Contains(msg.Body, "en.mp4") {
+		t.Errorf("expected body to include result URL, got '%s'", msg.Body)
+	}
+}
+
+func TestEmailNotifier_NoRecipients(t *testing.T) {
+	sender := &fakeEmailSender{}
+	notifier := NewEmailNotifier(sender, "noreply@example.com")
+
+	status := &models.StatusResponse{JobID: "job-2", Status: models.StatusCompleted}
+	if err := notifier.Notify(context.Background(), status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sender.sent) != 0 {
+		t.Errorf("expected no email sent, got %d", len(sender.sent))
+	}
+}
+
+func TestSendGridSender_Send(t *testing.T) {
+	var authHeader string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := NewSendGridSender("sg-key")
+	sender.Endpoint = server.URL
+
+	err := sender.Send(context.Background(), EmailMessage{
+		From:    "noreply@example.com",
+		To:      []string{"user@example.com"},
+		Subject: "subject",
+		Body:    "body",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if authHeader != "Bearer sg-key" {
+		t.Errorf("expected bearer auth header, got '%s'", authHeader)
+	}
+	if body["subject"] != "subject" {
+		t.Errorf("expected subject in request body, got %v", body["subject"])
+	}
+}
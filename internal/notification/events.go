@@ -0,0 +1,57 @@
+package notification
+
+import (
+	"sync"
+)
+
+// EventFilter selects which webhook events are delivered
+type EventFilter struct {
+	Events           []string // Empty delivers every event
+	MinProgressDelta int      // Minimum progress increase (percentage points) between job.progress events
+}
+
+// Allows reports whether event is selected by the filter
+func (f EventFilter) Allows(event string) bool {
+	if len(f.Events) == 0 {
+		return true
+	}
+	for _, selected := range f.Events {
+		if selected == event {
+			return true
+		}
+	}
+	return false
+}
+
+// ProgressTracker remembers the last job.progress value sent per job so receivers
+// only get progress events once progress grew by the minimum delta
+type ProgressTracker struct {
+	mu   sync.Mutex
+	last map[string]int
+}
+
+// NewProgressTracker creates an empty progress tracker
+func NewProgressTracker() *ProgressTracker {
+	return &ProgressTracker{last: make(map[string]int)}
+}
+
+// ShouldSend reports whether progress for a job should be delivered, recording it if so
+// Completion (100) is always delivered once.
+func (t *ProgressTracker) ShouldSend(jobID string, progress int, minDelta int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	last := t.last[jobID]
+	if progress <= last || (progress < 100 && progress-last < minDelta) {
+		return false
+	}
+	t.last[jobID] = progress
+	return true
+}
+
+// Forget drops the progress recorded for a job once it has finished
+func (t *ProgressTracker) Forget(jobID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.last, jobID)
+}
@@ -0,0 +1,45 @@
+package notification
+
+import "testing"
+
+func TestEventFilter_Allows(t *testing.T) {
+	all := EventFilter{}
+	if !all.Allows("job.progress") {
+		t.Error("expected empty filter to allow every event")
+	}
+
+	final := EventFilter{Events: []string{"job.completed", "job.failed"}}
+	if !final.Allows("job.failed") {
+		t.Error("expected job.failed to be allowed")
+	}
+	if final.Allows("language.completed") {
+		t.Error("expected language.completed to be filtered out")
+	}
+}
+
+func TestProgressTracker_ShouldSend(t *testing.T) {
+	tracker := NewProgressTracker()
+
+	steps := []struct {
+		progress int
+		want     bool
+	}{
+		{5, false},   // Below the first delta
+		{25, true},   // First report
+		{30, false},  // Only 5 points more
+		{50, true},   // 25 points more
+		{50, false},  // No change
+		{100, true},  // Completion is always reported
+		{100, false}, // But only once
+	}
+	for _, step := range steps {
+		if got := tracker.ShouldSend("job-1", step.progress, 20); got != step.want {
+			t.Errorf("progress %d: expected %v, got %v", step.progress, step.want, got)
+		}
+	}
+
+	tracker.Forget("job-1")
+	if !tracker.ShouldSend("job-1", 50, 20) {
+		t.Error("expected progress to be sent again after Forget")
+	}
+}
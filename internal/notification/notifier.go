@@ -0,0 +1,210 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// Notifier defines the interface for delivering job status notifications
+// Implementations exist for HTTP webhooks, email, Google Pub/Sub and Slack
+type Notifier interface {
+	// Name returns a short identifier for the notification channel (used in logs)
+	Name() string
+
+	// Notify delivers a notification for the given job status
+	Notify(ctx context.Context, jobStatus *models.StatusResponse) error
+}
+
+// RetryPolicy controls how notification deliveries are retried
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration // Delay before the second attempt, grows linearly per attempt
+	Timeout     time.Duration // Timeout for a single delivery attempt
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 2,
+		Backoff:     1 * time.Second,
+		Timeout:     5 * time.Second,
+	}
+}
+
+// Payload represents the notification payload shared by all channels
+type Payload struct {
+	Event     string                            `json:"event"`
+	JobID     string                            `json:"jobId"`
+	Status    models.TranslationStatus          `json:"status"`
+	Results   map[string]*models.LanguageResult `json:"results,omitempty"`
+	Language  string                            `json:"language,omitempty"` // Set for language.completed
+	Progress  int                               `json:"progress,omitempty"` // Overall job progress as reported in the job status, set for job.progress
+	Tags      []string                          `json:"tags,omitempty"`
+	Metadata  map[string]string                 `json:"metadata,omitempty"`
+	Timestamp string                            `json:"timestamp"`
+	Error     string                            `json:"error,omitempty"`
+	ErrorCode string                            `json:"errorCode,omitempty"`
+	Failures  map[string]string                 `json:"failures,omitempty"` // Error per failed language, set for failed and partially completed jobs
+	Usage     *models.JobUsage                  `json:"usage,omitempty"`    // Billable units and estimated cost, set for finished jobs
+	Source    *models.SourceInfo                `json:"source,omitempty"`   // Probed source video, set for job.started
+
+	// Changes describes what changed since the job's previous webhook notification (webhooks only)
+	Changes *Changes `json:"changes,omitempty"`
+}
+
+// NewPayload builds a notification payload from a job status
+func NewPayload(jobStatus *models.StatusResponse) Payload {
+	// Determine event type based on status
+	event := models.EventJobCompleted
+	if jobStatus.Status == models.StatusFailed {
+		event = models.EventJobFailed
+	} else if jobStatus.Status == models.StatusPartiallyCompleted {
+		event = models.EventJobPartial
+	} else if jobStatus.Status == models.StatusProcessing {
+		event = models.EventJobProcessing
+	}
+
+	payload := Payload{
+		Event:     event,
+		JobID:     jobStatus.JobID,
+		Status:    jobStatus.Status,
+		Results:   jobStatus.Results,
+		Tags:      jobStatus.Tags,
+		Metadata:  jobStatus.Metadata,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Usage:     jobStatus.Usage,
+	}
+
+	// Add error message if failed
+	if jobStatus.Status == models.StatusFailed {
+		payload.ErrorCode = jobStatus.ErrorCode
+		// Try to extract error from results
+		for _, result := range jobStatus.Results {
+			if result.Error != "" {
+				payload.Error = result.Error
+				break
+			}
+		}
+	}
+
+	// Enumerate failed languages so receivers can act on each
+	if jobStatus.Status == models.StatusFailed || jobStatus.Status == models.StatusPartiallyCompleted {
+		for lang, result := range jobStatus.Results {
+			if result.Status == models.StatusFailed {
+				if payload.Failures == nil {
+					payload.Failures = make(map[string]string)
+				}
+				payload.Failures[lang] = result.Error
+			}
+		}
+	}
+
+	return payload
+}
+
+// NewLanguagePayload builds a language.completed payload for one finished target language
+// Status is the language's own status and Results holds only that language.
+func NewLanguagePayload(jobStatus *models.StatusResponse, language string) Payload {
+	payload := Payload{
+		Event:     models.EventLanguageCompleted,
+		JobID:     jobStatus.JobID,
+		Status:    jobStatus.Status,
+		Language:  language,
+		Tags:      jobStatus.Tags,
+		Metadata:  jobStatus.Metadata,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	if result, ok := jobStatus.Results[language]; ok {
+		payload.Status = result.Status
+		payload.Results = map[string]*models.LanguageResult{language: result}
+		payload.Error = result.Error
+	}
+	return payload
+}
+
+// NewProgressPayload builds a job.progress payload from a job status
+func NewProgressPayload(jobStatus *models.StatusResponse) Payload {
+	return Payload{
+		Event:     models.EventJobProgress,
+		JobID:     jobStatus.JobID,
+		Status:    jobStatus.Status,
+		Progress:  jobStatus.Progress,
+		Tags:      jobStatus.Tags,
+		Metadata:  jobStatus.Metadata,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// NewStartedPayload builds a job.started payload for a job whose source video was downloaded and probed
+func NewStartedPayload(jobStatus *models.StatusResponse) Payload {
+	return Payload{
+		Event:     models.EventJobStarted,
+		JobID:     jobStatus.JobID,
+		Status:    jobStatus.Status,
+		Source:    jobStatus.Source,
+		Tags:      jobStatus.Tags,
+		Metadata:  jobStatus.Metadata,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// deliverWithRetry runs a single delivery attempt function according to the retry policy
+// Each attempt gets its own timeout derived from ctx
+func deliverWithRetry(ctx context.Context, channel string, jobID string, policy RetryPolicy, attempt func(ctx context.Context) error) error {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+
+	var lastErr error
+	for i := 0; i < policy.MaxAttempts; i++ {
+		attemptCtx := ctx
+		cancel := func() {}
+		if policy.Timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, policy.Timeout)
+		}
+		lastErr = attempt(attemptCtx)
+		cancel()
+
+		if lastErr == nil {
+			slog.Info("Notification sent successfully", "channel", channel, "jobID", jobID, "attempt", i+1)
+			return nil
+		}
+
+		if transient.IsPermanent(lastErr) {
+			slog.Error("Notification rejected, not retrying", "channel", channel, "error", lastErr, "jobID", jobID, "attempt", i+1)
+			return fmt.Errorf("%s notification rejected: %w", channel, lastErr)
+		}
+
+		if i < policy.MaxAttempts-1 {
+			slog.Warn("Notification attempt failed, retrying", "channel", channel, "error", lastErr, "jobID", jobID, "attempt", i+1)
+			select {
+			case <-time.After(time.Duration(i+1) * policy.Backoff): // Linear backoff
+			case <-ctx.Done():
+				return fmt.Errorf("%s notification cancelled: %w", channel, ctx.Err())
+			}
+		}
+	}
+
+	slog.Error("Notification failed", "channel", channel, "error", lastErr, "jobID", jobID)
+	return fmt.Errorf("failed to send %s notification after %d attempts: %w", channel, policy.MaxAttempts, lastErr)
+}
+
+// NotifyAll delivers the job status to every notifier, continuing past failures
+// Returns the first error encountered, if any
+func NotifyAll(ctx context.Context, notifiers []Notifier, jobStatus *models.StatusResponse) error {
+	var firstErr error
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, jobStatus); err != nil {
+			slog.Warn("Notifier failed", "channel", n.Name(), "error", err, "jobID", jobStatus.JobID)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,75 @@
+package notification
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"google.golang.org/api/option"
+	pubsub "google.golang.org/api/pubsub/v1"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// PubSubNotifier publishes job status payloads to a Google Pub/Sub topic
+type PubSubNotifier struct {
+	Topic   string // Full topic name: projects/{project}/topics/{topic}
+	Policy  RetryPolicy
+	service *pubsub.Service
+}
+
+// NewPubSubNotifier creates a Pub/Sub notifier for the given topic
+// Uses the credentials file if configured, otherwise default credentials
+func NewPubSubNotifier(ctx context.Context, topic string, opts ...option.ClientOption) (*PubSubNotifier, error) {
+	if credentialsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); credentialsPath != "" && len(opts) == 0 {
+		opts = append(opts, option.WithCredentialsFile(credentialsPath))
+	}
+
+	service, err := pubsub.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Pub/Sub client: %w", err)
+	}
+
+	return &PubSubNotifier{
+		Topic:   topic,
+		Policy:  DefaultRetryPolicy(),
+		service: service,
+	}, nil
+}
+
+// Name implements Notifier
+func (n *PubSubNotifier) Name() string {
+	return "pubsub"
+}
+
+// Notify implements Notifier
+func (n *PubSubNotifier) Notify(ctx context.Context, jobStatus *models.StatusResponse) error {
+	payload := NewPayload(jobStatus)
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		slog.Error("Failed to marshal Pub/Sub payload", "error", err, "jobID", jobStatus.JobID)
+		return fmt.Errorf("failed to marshal Pub/Sub payload: %w", err)
+	}
+
+	req := &pubsub.PublishRequest{
+		Messages: []*pubsub.PubsubMessage{
+			{
+				Data: base64.StdEncoding.EncodeToString(jsonData),
+				// Attributes allow subscribers to filter without decoding the body
+				Attributes: map[string]string{
+					"event":  payload.Event,
+					"jobId":  payload.JobID,
+					"status": string(payload.Status),
+				},
+			},
+		},
+	}
+
+	return deliverWithRetry(ctx, n.Name(), jobStatus.JobID, n.Policy, func(ctx context.Context) error {
+		_, err := n.service.Projects.Topics.Publish(n.Topic, req).Context(ctx).Do()
+		return err
+	})
+}
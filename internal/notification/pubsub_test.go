@@ -0,0 +1,67 @@
+package notification
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestPubSubNotifier_Notify(t *testing.T) {
+	var path string
+	var request struct {
+		Messages []struct {
+			Data       string            `json:"data"`
+			Attributes map[string]string `json:"attributes"`
+		} `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"messageIds":["1"]}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	notifier, err := NewPubSubNotifier(ctx, "projects/p/topics/jobs", option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("failed to create notifier: %v", err)
+	}
+
+	status := &models.StatusResponse{JobID: "job-1", Status: models.StatusCompleted}
+	if err := notifier.Notify(ctx, status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasSuffix(path, "projects/p/topics/jobs:publish") {
+		t.Errorf("unexpected publish path: %s", path)
+	}
+	if len(request.Messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(request.Messages))
+	}
+
+	msg := request.Messages[0]
+	if msg.Attributes["event"] != "job.completed" || msg.Attributes["jobId"] != "job-1" {
+		t.Errorf("unexpected attributes: %v", msg.Attributes)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(msg.Data)
+	if err != nil {
+		t.Fatalf("failed to decode message data: %v", err)
+	}
+	var payload Payload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("failed to unmarshal payload: %v", err)
+	}
+	if payload.JobID != "job-1" {
+		t.Errorf("expected jobId 'job-1', got '%s'", payload.JobID)
+	}
+}
@@ -0,0 +1,41 @@
+package notification
+
+// Queue runs notification deliveries on a fixed set of workers
+// Deliveries wait in a bounded buffer, so a slow endpoint cannot pile up goroutines.
+type Queue struct {
+	tasks chan func()
+}
+
+// NewQueue starts workers goroutines draining a buffer of size pending deliveries
+func NewQueue(workers, size int) *Queue {
+	if workers <= 0 {
+		workers = 1
+	}
+	if size < 0 {
+		size = 0
+	}
+	q := &Queue{tasks: make(chan func(), size)}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for task := range q.tasks {
+				task()
+			}
+		}()
+	}
+	return q
+}
+
+// Submit queues a delivery, returning false without blocking when the buffer is full
+func (q *Queue) Submit(task func()) bool {
+	select {
+	case q.tasks <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+// Pending returns the number of deliveries waiting for a worker
+func (q *Queue) Pending() int {
+	return len(q.tasks)
+}
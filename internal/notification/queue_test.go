@@ -0,0 +1,48 @@
+package notification
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueue_RunsTasks(t *testing.T) {
+	q := NewQueue(2, 4)
+	done := make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		i := i
+		if !q.Submit(func() { done <- i }) {
+			t.Fatalf("expected task %d to be queued", i)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for queued tasks")
+		}
+	}
+}
+
+func TestQueue_DropsWhenFull(t *testing.T) {
+	q := NewQueue(1, 1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	defer close(release)
+
+	q.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started // The only worker is now busy
+
+	if !q.Submit(func() {}) {
+		t.Fatal("expected a task to fit in the buffer")
+	}
+	if q.Submit(func() {}) {
+		t.Error("expected a full queue to reject the task")
+	}
+	if q.Pending() != 1 {
+		t.Errorf("expected 1 pending task, got %d", q.Pending())
+	}
+}
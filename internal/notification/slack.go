@@ -0,0 +1,52 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sinouw/multilingual-video-processor/internal/outbound"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// SlackNotifier posts job summaries to a Slack incoming webhook
+type SlackNotifier struct {
+	WebhookURL string
+	Policy     RetryPolicy
+	client     *http.Client
+}
+
+// NewSlackNotifier creates a Slack notifier for an incoming webhook URL
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{
+		WebhookURL: webhookURL,
+		Policy:     DefaultRetryPolicy(),
+		client:     outbound.NewClient(0),
+	}
+}
+
+// Name implements Notifier
+func (n *SlackNotifier) Name() string {
+	return "slack"
+}
+
+// Notify implements Notifier
+func (n *SlackNotifier) Notify(ctx context.Context, jobStatus *models.StatusResponse) error {
+	if n.WebhookURL == "" {
+		return nil // Slack not configured, skip
+	}
+
+	// Slack renders the same summary used for email, with the subject in bold
+	msg := BuildEmailMessage(NewPayload(jobStatus))
+	jsonData, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", msg.Subject, msg.Body),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Slack message: %w", err)
+	}
+
+	return deliverWithRetry(ctx, n.Name(), jobStatus.JobID, n.Policy, func(ctx context.Context) error {
+		return postJSON(ctx, n.client, n.WebhookURL, jsonData)
+	})
+}
@@ -0,0 +1,32 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestSlackNotifier_Notify(t *testing.T) {
+	var message map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&message)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL)
+	status := &models.StatusResponse{JobID: "job-1", Status: models.StatusFailed}
+
+	if err := notifier.Notify(context.Background(), status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(message["text"], "*Translation job job-1 failed*") {
+		t.Errorf("unexpected Slack text: %q", message["text"])
+	}
+}
@@ -0,0 +1,107 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/sinouw/multilingual-video-processor/internal/outbound"
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+	"github.com/sinouw/multilingual-video-processor/internal/workerpool"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// WebhookNotifier posts job status payloads to an HTTP endpoint
+type WebhookNotifier struct {
+	URL    string
+	Policy RetryPolicy
+	Events EventFilter // Events to deliver; the zero value delivers all
+	client *http.Client
+
+	// Pool bounds concurrent deliveries across notifiers; nil delivers immediately
+	Pool *workerpool.Pool
+
+	// Changes adds the changes since the job's previous notification to status payloads; nil omits them
+	Changes *ChangeTracker
+}
+
+// NewWebhookNotifier creates a webhook notifier with the default retry policy
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:    url,
+		Policy: DefaultRetryPolicy(),
+		client: outbound.NewClient(0),
+	}
+}
+
+// Name implements Notifier
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// Notify implements Notifier
+func (n *WebhookNotifier) Notify(ctx context.Context, jobStatus *models.StatusResponse) error {
+	payload := NewPayload(jobStatus)
+	if n.Changes != nil && n.Events.Allows(payload.Event) {
+		payload.Changes = n.Changes.Diff(jobStatus, payload.Timestamp)
+	}
+	return n.NotifyEvent(ctx, payload)
+}
+
+// NotifyEvent delivers a payload if its event is selected by the notifier's event filter
+func (n *WebhookNotifier) NotifyEvent(ctx context.Context, payload Payload) error {
+	if n.URL == "" || !n.Events.Allows(payload.Event) {
+		return nil // No webhook configured or event not selected, skip
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		slog.Error("Failed to marshal webhook payload", "error", err, "jobID", payload.JobID)
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	deliver := func() error {
+		return deliverWithRetry(ctx, n.Name(), payload.JobID, n.Policy, func(ctx context.Context) error {
+			return postJSON(ctx, n.client, n.URL, jsonData)
+		})
+	}
+	if n.Pool == nil {
+		err = deliver()
+	} else {
+		err = n.Pool.Do(ctx, deliver)
+	}
+	if err == nil && n.Changes != nil {
+		n.Changes.Commit(payload.Changes) // Only delivered changes advance the job's notified state
+	}
+	return err
+}
+
+// postJSON sends a JSON body and treats any non-2xx response as an error
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "multilingual-video-processor/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+		// Client errors other than timeouts and rate limiting will be rejected again
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+			return transient.Permanent(err)
+		}
+		return err
+	}
+	return nil
+}
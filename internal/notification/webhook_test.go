@@ -0,0 +1,293 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/workerpool"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestWebhookNotifier_Success(t *testing.T) {
+	var received Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	status := &models.StatusResponse{JobID: "job-1", Status: models.StatusCompleted}
+
+	if err := notifier.Notify(context.Background(), status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.Event != "job.completed" {
+		t.Errorf("expected event 'job.completed', got '%s'", received.Event)
+	}
+	if received.JobID != "job-1" {
+		t.Errorf("expected jobId 'job-1', got '%s'", received.JobID)
+	}
+}
+
+func TestNewPayload_TagsAndMetadata(t *testing.T) {
+	status := &models.StatusResponse{
+		JobID:    "job-1",
+		Status:   models.StatusCompleted,
+		Tags:     []string{"campaign"},
+		Metadata: map[string]string{"assetId": "42"},
+	}
+
+	for _, payload := range []Payload{NewPayload(status), NewLanguagePayload(status, "de"), NewProgressPayload(status)} {
+		if len(payload.Tags) != 1 || payload.Tags[0] != "campaign" {
+			t.Errorf("%s: expected tags to be echoed, got %v", payload.Event, payload.Tags)
+		}
+		if payload.Metadata["assetId"] != "42" {
+			t.Errorf("%s: expected metadata to be echoed, got %v", payload.Event, payload.Metadata)
+		}
+	}
+}
+
+func TestWebhookNotifier_RetriesOnServerError(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	notifier.Policy.Backoff = time.Millisecond
+
+	status := &models.StatusResponse{JobID: "job-2", Status: models.StatusFailed}
+	if err := notifier.Notify(context.Background(), status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Errorf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestWebhookNotifier_ExhaustsRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	notifier.Policy.Backoff = time.Millisecond
+
+	status := &models.StatusResponse{JobID: "job-3", Status: models.StatusCompleted}
+	if err := notifier.Notify(context.Background(), status); err == nil {
+		t.Error("expected error after exhausting retries")
+	}
+}
+
+func TestWebhookNotifier_ClientErrorNotRetried(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	notifier.Policy.MaxAttempts = 3
+	notifier.Policy.Backoff = time.Millisecond
+
+	status := &models.StatusResponse{JobID: "job-4", Status: models.StatusCompleted}
+	if err := notifier.Notify(context.Background(), status); err == nil {
+		t.Error("expected error for rejected webhook")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestWebhookNotifier_Pool(t *testing.T) {
+	var running, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			observed := atomic.LoadInt32(&peak)
+			if current <= observed || atomic.CompareAndSwapInt32(&peak, observed, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pool := workerpool.New("webhook", 1)
+	done := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		go func() {
+			notifier := NewWebhookNotifier(server.URL)
+			notifier.Pool = pool
+			done <- notifier.Notify(context.Background(), &models.StatusResponse{JobID: "job-4", Status: models.StatusCompleted})
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		if err := <-done; err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&peak); got != 1 {
+		t.Errorf("expected deliveries to run one at a time, got %d concurrent", got)
+	}
+}
+
+func TestNewPayload_FailedJobIncludesError(t *testing.T) {
+	status := &models.StatusResponse{
+		JobID:  "job-4",
+		Status: models.StatusFailed,
+		Results: map[string]*models.LanguageResult{
+			"en": {Status: models.StatusFailed, Error: "translation failed"},
+		},
+	}
+
+	payload := NewPayload(status)
+	if payload.Event != "job.failed" {
+		t.Errorf("expected event 'job.failed', got '%s'", payload.Event)
+	}
+	if payload.Error != "translation failed" {
+		t.Errorf("expected error 'translation failed', got '%s'", payload.Error)
+	}
+}
+
+func TestNewPayload_FailedJobIncludesErrorCode(t *testing.T) {
+	status := &models.StatusResponse{
+		JobID:     "job-5",
+		Status:    models.StatusFailed,
+		ErrorCode: models.ErrCodeNoSpeech,
+		Results: map[string]*models.LanguageResult{
+			"error": {Status: models.StatusFailed, Error: "no speech detected"},
+		},
+	}
+
+	payload := NewPayload(status)
+	if payload.ErrorCode != models.ErrCodeNoSpeech {
+		t.Errorf("expected error code '%s', got '%s'", models.ErrCodeNoSpeech, payload.ErrorCode)
+	}
+}
+
+func TestWebhookNotifier_FiltersEvents(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload Payload
+		json.NewDecoder(r.Body).Decode(&payload)
+		received = append(received, payload.Event)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	notifier.Events = EventFilter{Events: []string{models.EventJobFailed, models.EventLanguageCompleted}}
+
+	status := &models.StatusResponse{
+		JobID:   "job-1",
+		Status:  models.StatusCompleted,
+		Results: map[string]*models.LanguageResult{"de": {Status: models.StatusCompleted}},
+	}
+	if err := notifier.Notify(context.Background(), status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := notifier.NotifyEvent(context.Background(), NewLanguagePayload(status, "de")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(received) != 1 || received[0] != models.EventLanguageCompleted {
+		t.Errorf("expected only language.completed to be delivered, got %v", received)
+	}
+}
+
+func TestNewLanguagePayload(t *testing.T) {
+	status := &models.StatusResponse{
+		JobID:  "job-1",
+		Status: models.StatusProcessing,
+		Results: map[string]*models.LanguageResult{
+			"de": {Status: models.StatusFailed, Error: "TTS generation failed"},
+			"ar": {Status: models.StatusCompleted},
+		},
+	}
+
+	payload := NewLanguagePayload(status, "de")
+	if payload.Event != models.EventLanguageCompleted || payload.Language != "de" {
+		t.Errorf("expected language.completed for de, got %s for %s", payload.Event, payload.Language)
+	}
+	if payload.Status != models.StatusFailed || payload.Error != "TTS generation failed" {
+		t.Errorf("expected the language's failed status and error, got %s (%s)", payload.Status, payload.Error)
+	}
+	if len(payload.Results) != 1 {
+		t.Errorf("expected only the language's result, got %d results", len(payload.Results))
+	}
+}
+
+func TestNewStartedPayload(t *testing.T) {
+	status := &models.StatusResponse{
+		JobID:   "job-1",
+		Status:  models.StatusProcessing,
+		Results: models.PendingResults([]string{"de"}),
+		Source:  &models.SourceInfo{Duration: 42.5, SizeBytes: 1024},
+		Tags:    []string{"campaign"},
+	}
+
+	payload := NewStartedPayload(status)
+	if payload.Event != models.EventJobStarted || payload.JobID != "job-1" {
+		t.Errorf("expected job.started for job-1, got %s for %s", payload.Event, payload.JobID)
+	}
+	if payload.Source == nil || payload.Source.Duration != 42.5 || payload.Source.SizeBytes != 1024 {
+		t.Errorf("expected the probed source, got %+v", payload.Source)
+	}
+	if payload.Results != nil {
+		t.Errorf("expected no results, got %d", len(payload.Results))
+	}
+	if len(payload.Tags) != 1 {
+		t.Errorf("expected the job tags, got %v", payload.Tags)
+	}
+}
+
+func TestNewProgressPayload(t *testing.T) {
+	status := &models.StatusResponse{JobID: "job-1", Status: models.StatusProcessing, Progress: 64}
+	if got := NewProgressPayload(status).Progress; got != 64 {
+		t.Errorf("expected the job status progress 64, got %d", got)
+	}
+}
+
+func TestNewPayload_PartiallyCompleted(t *testing.T) {
+	status := &models.StatusResponse{
+		JobID:  "job-1",
+		Status: models.StatusPartiallyCompleted,
+		Results: map[string]*models.LanguageResult{
+			"de": {Status: models.StatusCompleted, VideoURL: "https://example.com/de.mp4"},
+			"ar": {Status: models.StatusFailed, Error: "TTS generation failed"},
+			"ru": {Status: models.StatusFailed, Error: "translation failed"},
+		},
+	}
+
+	payload := NewPayload(status)
+	if payload.Event != models.EventJobPartial {
+		t.Errorf("expected event '%s', got '%s'", models.EventJobPartial, payload.Event)
+	}
+	if len(payload.Failures) != 2 || payload.Failures["ar"] != "TTS generation failed" || payload.Failures["ru"] != "translation failed" {
+		t.Errorf("expected failures for ar and ru, got %v", payload.Failures)
+	}
+	if payload.Results["de"].VideoURL == "" {
+		t.Error("expected completed language results to be included")
+	}
+}
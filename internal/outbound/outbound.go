@@ -0,0 +1,193 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Options configure the requests sent to translation providers, webhooks and HTTPS video sources
+type Options struct {
+	ProxyURL  string // HTTP(S) proxy for every outbound request; empty uses HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+	UserAgent string // User-Agent header of every outbound request; empty keeps the one set by each client
+}
+
+var (
+	mu              sync.RWMutex
+	transport       http.RoundTripper = http.DefaultTransport
+	publicTransport http.RoundTripper = newPublicTransport(http.DefaultTransport.(*http.Transport).Clone(), false)
+)
+
+var (
+	// ErrPrivateAddress is returned when a public client would connect to a private, loopback or link-local address
+	ErrPrivateAddress = errors.New("destination address is not public")
+	// ErrInsecureRedirect is returned when a public client is redirected from HTTPS to another scheme
+	ErrInsecureRedirect = errors.New("redirect leaves HTTPS")
+)
+
+// maxRedirects bounds the redirects followed by public clients, as the default client does
+const maxRedirects = 10
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), not routable on the internet
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Configure applies a proxy and User-Agent to the clients created by NewClient, including existing ones
+func Configure(opts Options) error {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.ProxyURL != "" {
+		proxy, err := url.Parse(opts.ProxyURL)
+		if err != nil || proxy.Host == "" || (proxy.Scheme != "http" && proxy.Scheme != "https") {
+			return fmt.Errorf("invalid proxy URL: %s", opts.ProxyURL)
+		}
+		base.Proxy = http.ProxyURL(proxy)
+	}
+
+	var configured http.RoundTripper = base
+	public := newPublicTransport(base.Clone(), opts.ProxyURL != "")
+	if opts.UserAgent != "" {
+		configured = &userAgentTransport{base: base, userAgent: opts.UserAgent}
+		public = &userAgentTransport{base: public, userAgent: opts.UserAgent}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	transport = configured
+	publicTransport = public
+	return nil
+}
+
+// NewClient creates an HTTP client sending requests through the configured proxy and User-Agent
+// A zero timeout leaves requests bounded by their context only.
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: Transport{},
+	}
+}
+
+// NewPublicClient creates a client for URLs supplied by callers, such as HTTPS video sources
+// It refuses to connect to private, loopback and link-local addresses (including the metadata server), checked
+// after DNS resolution on every redirect hop, and to follow redirects from HTTPS to another scheme.
+func NewPublicClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:       timeout,
+		Transport:     PublicTransport{},
+		CheckRedirect: checkPublicRedirect,
+	}
+}
+
+// IsPublicIP reports whether ip is routable on the internet: not private, loopback, link-local, multicast,
+// unspecified or carrier-grade NAT
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip))
+}
+
+// checkPublicRedirect stops public clients after maxRedirects hops and on redirects from HTTPS to another scheme
+// The address of each hop is checked when it is dialed.
+func checkPublicRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if via[0].URL.Scheme == "https" && req.URL.Scheme != "https" {
+		return fmt.Errorf("%w: %s", ErrInsecureRedirect, req.URL.Redacted())
+	}
+	return nil
+}
+
+// newPublicTransport restricts a transport to public addresses
+// Connections are checked once resolved; through a proxy, which resolves the host itself, the request host is
+// resolved and checked before the request is sent instead.
+func newPublicTransport(base *http.Transport, proxied bool) http.RoundTripper {
+	if proxied {
+		return &publicHostTransport{base: base}
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   checkDialAddress,
+	}
+	base.DialContext = dialer.DialContext
+	return base
+}
+
+// checkDialAddress refuses connections to addresses that are not public, after DNS resolution
+func checkDialAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// publicHostTransport refuses requests whose host resolves to an address that is not public
+type publicHostTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *publicHostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := checkPublicHost(req.Context(), req.URL.Hostname()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// checkPublicHost resolves host and returns an error unless all of its addresses are public
+func checkPublicHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !IsPublicIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrPrivateAddress, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// PublicTransport sends requests with the settings of the last Configure call, to public addresses only
+type PublicTransport struct{}
+
+// RoundTrip implements http.RoundTripper
+func (PublicTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mu.RLock()
+	current := publicTransport
+	mu.RUnlock()
+	return current.RoundTrip(req)
+}
+
+// Transport sends requests with the settings of the last Configure call
+type Transport struct{}
+
+// RoundTrip implements http.RoundTripper
+func (Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mu.RLock()
+	current := transport
+	mu.RUnlock()
+	return current.RoundTrip(req)
+}
+
+// userAgentTransport replaces the User-Agent of requests
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(req)
+}
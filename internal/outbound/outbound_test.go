@@ -0,0 +1,131 @@
+package outbound
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestNewClient_UserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+	}))
+	defer server.Close()
+	defer Configure(Options{})
+
+	if err := Configure(Options{UserAgent: "acme-dubbing/2.0"}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("User-Agent", "multilingual-video-processor/1.0")
+	resp, err := NewClient(0).Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if userAgent != "acme-dubbing/2.0" {
+		t.Errorf("expected configured User-Agent, got %q", userAgent)
+	}
+	if req.Header.Get("User-Agent") != "multilingual-video-processor/1.0" {
+		t.Errorf("expected caller's request to be left unchanged, got %q", req.Header.Get("User-Agent"))
+	}
+}
+
+func TestNewClient_Proxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+	}))
+	defer proxy.Close()
+	defer Configure(Options{})
+
+	// Clients created before Configure also use the proxy
+	client := NewClient(0)
+	if err := Configure(Options{ProxyURL: proxy.URL}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	resp, err := client.Get("http://translation.example.com/v2")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if proxiedHost != "translation.example.com" {
+		t.Errorf("expected request to go through the proxy, got host %q", proxiedHost)
+	}
+}
+
+func TestConfigure_InvalidProxy(t *testing.T) {
+	defer Configure(Options{})
+
+	for _, proxyURL := range []string{"proxy.corp:3128", "ftp://proxy.corp", "http://"} {
+		if err := Configure(Options{ProxyURL: proxyURL}); err == nil {
+			t.Errorf("expected error for proxy URL %q", proxyURL)
+		}
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.216.34":   true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false, // Metadata server
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+		"::ffff:10.0.0.1": false,
+	}
+	for address, want := range tests {
+		if got := IsPublicIP(net.ParseIP(address)); got != want {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", address, got, want)
+		}
+	}
+}
+
+func TestNewPublicClient_PrivateAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected no request to reach a loopback server")
+	}))
+	defer server.Close()
+
+	_, err := NewPublicClient(0).Get(server.URL)
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("expected ErrPrivateAddress, got %v", err)
+	}
+}
+
+func TestCheckPublicRedirect(t *testing.T) {
+	request := func(rawURL string) *http.Request {
+		u, _ := url.Parse(rawURL)
+		return &http.Request{URL: u}
+	}
+	via := []*http.Request{request("https://cdn.example.com/video.mp4")}
+
+	if err := checkPublicRedirect(request("https://mirror.example.com/video.mp4"), via); err != nil {
+		t.Errorf("unexpected error for an HTTPS redirect: %v", err)
+	}
+	if err := checkPublicRedirect(request("http://mirror.example.com/video.mp4"), via); !errors.Is(err, ErrInsecureRedirect) {
+		t.Errorf("expected ErrInsecureRedirect for a downgrade, got %v", err)
+	}
+
+	long := make([]*http.Request, maxRedirects)
+	for i := range long {
+		long[i] = via[0]
+	}
+	if err := checkPublicRedirect(request("https://mirror.example.com/video.mp4"), long); err == nil {
+		t.Error("expected error past the redirect limit")
+	}
+}
@@ -0,0 +1,119 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrHalted is returned by a stage that stopped the pipeline after recording the outcome itself (e.g., failing the job)
+var ErrHalted = errors.New("pipeline halted")
+
+// Stage is one step of a pipeline
+// Stages share the state of a run through the receiver of their function, usually the run's struct.
+type Stage interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+// funcStage is a stage running a function
+type funcStage struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+func (s *funcStage) Name() string {
+	return s.name
+}
+
+func (s *funcStage) Run(ctx context.Context) error {
+	return s.run(ctx)
+}
+
+// Func returns a stage running fn
+func Func(name string, fn func(ctx context.Context) error) Stage {
+	return &funcStage{name: name, run: fn}
+}
+
+// When returns a stage running stage only when cond reports true at the time the stage is reached
+func When(cond func() bool, stage Stage) Stage {
+	return Func(stage.Name(), func(ctx context.Context) error {
+		if !cond() {
+			return nil
+		}
+		return stage.Run(ctx)
+	})
+}
+
+// Parallel returns a stage running stages concurrently
+// The first error cancels the other stages and is returned once they all return. Parallel stages must not
+// write the same state.
+func Parallel(name string, stages ...Stage) Stage {
+	return Func(name, func(ctx context.Context) error {
+		group, ctx := errgroup.WithContext(ctx)
+		for _, stage := range stages {
+			group.Go(func() error {
+				return run(ctx, stage)
+			})
+		}
+		return group.Wait()
+	})
+}
+
+// Error reports the stage a pipeline stopped at
+type Error struct {
+	Stage string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return e.Stage + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Pipeline runs stages one after the other
+type Pipeline struct {
+	stages []Stage
+}
+
+// New creates a pipeline running stages in order
+func New(stages ...Stage) *Pipeline {
+	return &Pipeline{stages: stages}
+}
+
+// Stages returns the names of the pipeline's stages in order
+func (p *Pipeline) Stages() []string {
+	names := make([]string, len(p.stages))
+	for i, stage := range p.stages {
+		names[i] = stage.Name()
+	}
+	return names
+}
+
+// Run runs the stages in order, stopping at the first error or once ctx is cancelled
+// The returned error is an *Error naming the innermost stage that failed or was about to start.
+func (p *Pipeline) Run(ctx context.Context) error {
+	for _, stage := range p.stages {
+		if err := run(ctx, stage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run runs a stage unless ctx is already cancelled, wrapping its error in an *Error
+func run(ctx context.Context, stage Stage) error {
+	if err := ctx.Err(); err != nil {
+		return &Error{Stage: stage.Name(), Err: err}
+	}
+	err := stage.Run(ctx)
+	var stageErr *Error
+	if err == nil || errors.As(err, &stageErr) {
+		return err
+	}
+	return &Error{Stage: stage.Name(), Err: err}
+}
@@ -0,0 +1,109 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPipeline_RunsStagesInOrder(t *testing.T) {
+	var order []string
+	record := func(name string) Stage {
+		return Func(name, func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	p := New(record("download"), When(func() bool { return false }, record("moderation")), record("transcription"))
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := []string{"download", "transcription"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("ran %v, want %v", order, want)
+	}
+	if want := []string{"download", "moderation", "transcription"}; !reflect.DeepEqual(p.Stages(), want) {
+		t.Errorf("Stages() = %v, want %v", p.Stages(), want)
+	}
+}
+
+func TestPipeline_StopsAtFirstError(t *testing.T) {
+	failure := errors.New("probe failed")
+	ran := false
+	p := New(
+		Func("probe", func(ctx context.Context) error { return failure }),
+		Func("transcription", func(ctx context.Context) error { ran = true; return nil }),
+	)
+
+	err := p.Run(context.Background())
+	var stageErr *Error
+	if !errors.As(err, &stageErr) || stageErr.Stage != "probe" || !errors.Is(err, failure) {
+		t.Fatalf("Run() error = %v, want the probe stage error", err)
+	}
+	if ran {
+		t.Error("expected stages after the failed one not to run")
+	}
+}
+
+func TestPipeline_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := New(
+		Func("download", func(ctx context.Context) error { cancel(); return nil }),
+		Func("probe", func(ctx context.Context) error { t.Error("expected probe not to run"); return nil }),
+	)
+
+	err := p.Run(ctx)
+	var stageErr *Error
+	if !errors.As(err, &stageErr) || stageErr.Stage != "probe" || !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want cancellation before probe", err)
+	}
+}
+
+func TestParallel(t *testing.T) {
+	var running, peak atomic.Int32
+	stage := func(name string) Stage {
+		return Func(name, func(ctx context.Context) error {
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				observed := peak.Load()
+				if current <= observed || peak.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		})
+	}
+
+	if err := New(Parallel("probe", stage("rotation"), stage("metadata"), stage("audio tracks"))).Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if peak.Load() < 2 {
+		t.Errorf("expected stages to run concurrently, peak was %d", peak.Load())
+	}
+}
+
+func TestParallel_FirstErrorCancelsOthers(t *testing.T) {
+	failure := errors.New("branding download failed")
+	p := New(Parallel("preparation",
+		Func("branding", func(ctx context.Context) error { return failure }),
+		Func("background", func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+				return errors.New("expected cancellation")
+			}
+		}),
+	))
+
+	err := p.Run(context.Background())
+	var stageErr *Error
+	if !errors.As(err, &stageErr) || stageErr.Stage != "branding" || !errors.Is(err, failure) {
+		t.Errorf("Run() error = %v, want the branding stage error", err)
+	}
+}
@@ -0,0 +1,85 @@
+package preview
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"time"
+)
+
+// Page is a job's shareable results page: one section per target language with its dubbed video
+type Page struct {
+	JobID         string
+	Title         string // Source video title
+	Status        string
+	TranscriptURL string // Source-language transcript, empty when not published
+	GeneratedAt   time.Time
+	Languages     []Language
+}
+
+// Language is one target language's outputs on the page
+type Language struct {
+	Code              string
+	Name              string // English language name, e.g. German
+	Status            string
+	Error             string
+	VideoURL          string
+	SubtitlesURL      string // WebVTT track shown in the player
+	AudioURL          string
+	TranslatedTextURL string
+}
+
+// SortLanguages orders the page's languages by name so the page is stable across renders
+func (p *Page) SortLanguages() {
+	sort.Slice(p.Languages, func(i, j int) bool {
+		return p.Languages[i].Name < p.Languages[j].Name
+	})
+}
+
+// Render returns the page as a self-contained HTML document
+// URLs and text are escaped by html/template; players load the outputs from their public URLs.
+func Render(page Page) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, page); err != nil {
+		return nil, fmt.Errorf("failed to render preview page: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+var pageTemplate = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} – dubbed versions</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem auto; max-width: 960px; padding: 0 1rem; color: #222; }
+section { border-top: 1px solid #ddd; padding: 1rem 0; }
+video { width: 100%; max-height: 480px; background: #000; }
+.status { font-size: 0.85rem; color: #666; }
+.error { color: #b00020; }
+ul.links { padding-left: 1.2rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="status">Job {{.JobID}} · {{.Status}} · generated {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}{{if .TranscriptURL}} · <a href="{{.TranscriptURL}}">source transcript</a>{{end}}</p>
+{{range .Languages}}
+<section id="{{.Code}}">
+<h2>{{.Name}} <span class="status">({{.Code}}, {{.Status}})</span></h2>
+{{if .VideoURL}}<video controls preload="metadata" src="{{.VideoURL}}">
+{{if .SubtitlesURL}}<track kind="subtitles" srclang="{{.Code}}" label="{{.Name}}" src="{{.SubtitlesURL}}" default>{{end}}
+</video>{{end}}
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<ul class="links">
+{{if .VideoURL}}<li><a href="{{.VideoURL}}">Video</a></li>{{end}}
+{{if .SubtitlesURL}}<li><a href="{{.SubtitlesURL}}">Subtitles (WebVTT)</a></li>{{end}}
+{{if .AudioURL}}<li><a href="{{.AudioURL}}">Dubbed audio</a></li>{{end}}
+{{if .TranslatedTextURL}}<li><a href="{{.TranslatedTextURL}}">Translated text</a></li>{{end}}
+</ul>
+</section>
+{{end}}
+</body>
+</html>
+`))
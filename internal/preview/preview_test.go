@@ -0,0 +1,54 @@
+package preview
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	page := Page{
+		JobID:       "job-1",
+		Title:       "Launch <keynote>",
+		Status:      "partially_completed",
+		GeneratedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Languages: []Language{
+			{Code: "de", Name: "German", Status: "completed", VideoURL: "https://storage.googleapis.com/out/de.mp4", SubtitlesURL: "https://storage.googleapis.com/out/de.vtt"},
+			{Code: "fr", Name: "French", Status: "failed", Error: "TTS failed"},
+		},
+	}
+	page.SortLanguages()
+
+	data, err := Render(page)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	html := string(data)
+
+	for _, want := range []string{
+		`<title>Launch &lt;keynote&gt; – dubbed versions</title>`,
+		`<video controls preload="metadata" src="https://storage.googleapis.com/out/de.mp4">`,
+		`<track kind="subtitles" srclang="de" label="German" src="https://storage.googleapis.com/out/de.vtt" default>`,
+		`<p class="error">TTS failed</p>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected page to contain %q", want)
+		}
+	}
+	if strings.Index(html, `id="fr"`) > strings.Index(html, `id="de"`) {
+		t.Error("expected languages sorted by name")
+	}
+	if strings.Contains(html, `<video controls preload="metadata" src=""`) {
+		t.Error("expected no player for a language without video")
+	}
+}
+
+func TestRender_UnsafeURL(t *testing.T) {
+	data, err := Render(Page{Languages: []Language{{Code: "de", Name: "German", VideoURL: "javascript:alert(1)"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), "javascript:") {
+		t.Error("expected unsafe URL to be filtered")
+	}
+}
@@ -0,0 +1,221 @@
+package separation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Supported separation backends
+const (
+	BackendDemucs   = "demucs"
+	BackendSpleeter = "spleeter"
+	BackendHTTP     = "http"
+)
+
+// DefaultDemucsModel is the Demucs model used when none is configured
+const DefaultDemucsModel = "htdemucs"
+
+// Separator splits an audio file into speech and accompaniment, keeping the accompaniment (music and effects)
+type Separator interface {
+	// Name returns the backend name (demucs, spleeter or http)
+	Name() string
+	// Separate writes the accompaniment of audioPath into outputDir and returns its path
+	Separate(ctx context.Context, audioPath string, outputDir string) (string, error)
+}
+
+// New creates the separator for a backend
+// command overrides the binary of demucs and spleeter; endpoint is required for http.
+func New(backend string, command string, endpoint string, model string) (Separator, error) {
+	switch backend {
+	case BackendDemucs:
+		if command == "" {
+			command = BackendDemucs
+		}
+		if model == "" {
+			model = DefaultDemucsModel
+		}
+		return &DemucsSeparator{Command: command, Model: model}, nil
+	case BackendSpleeter:
+		if command == "" {
+			command = BackendSpleeter
+		}
+		return &SpleeterSeparator{Command: command}, nil
+	case BackendHTTP:
+		if endpoint == "" {
+			return nil, fmt.Errorf("endpoint is required for separation backend %s", BackendHTTP)
+		}
+		return NewHTTPSeparator(endpoint), nil
+	default:
+		return nil, fmt.Errorf("unsupported separation backend: %s", backend)
+	}
+}
+
+// DemucsSeparator runs the Demucs CLI in two-stem mode
+type DemucsSeparator struct {
+	Command string
+	Model   string
+}
+
+// Name implements Separator interface
+func (s *DemucsSeparator) Name() string {
+	return BackendDemucs
+}
+
+// Separate implements Separator interface
+// Demucs writes the accompaniment to <outputDir>/<model>/<input name>/no_vocals.wav.
+func (s *DemucsSeparator) Separate(ctx context.Context, audioPath string, outputDir string) (string, error) {
+	if err := run(ctx, s.Command, demucsArgs(audioPath, outputDir, s.Model)); err != nil {
+		return "", err
+	}
+	return stemPath(filepath.Join(outputDir, s.Model, baseName(audioPath), "no_vocals.wav"))
+}
+
+// demucsArgs builds the Demucs arguments
+// demucs --two-stems=vocals -n htdemucs -o outputDir input.wav
+func demucsArgs(audioPath string, outputDir string, model string) []string {
+	return []string{
+		"--two-stems=vocals", // Split into vocals and everything else
+		"-n", model,
+		"-o", outputDir,
+		audioPath,
+	}
+}
+
+// SpleeterSeparator runs the Spleeter CLI with the 2stems model
+type SpleeterSeparator struct {
+	Command string
+}
+
+// Name implements Separator interface
+func (s *SpleeterSeparator) Name() string {
+	return BackendSpleeter
+}
+
+// Separate implements Separator interface
+// Spleeter writes the accompaniment to <outputDir>/<input name>/accompaniment.wav.
+func (s *SpleeterSeparator) Separate(ctx context.Context, audioPath string, outputDir string) (string, error) {
+	if err := run(ctx, s.Command, spleeterArgs(audioPath, outputDir)); err != nil {
+		return "", err
+	}
+	return stemPath(filepath.Join(outputDir, baseName(audioPath), "accompaniment.wav"))
+}
+
+// spleeterArgs builds the Spleeter arguments
+// spleeter separate -p spleeter:2stems -o outputDir input.wav
+func spleeterArgs(audioPath string, outputDir string) []string {
+	return []string{
+		"separate",
+		"-p", "spleeter:2stems", // Vocals and accompaniment
+		"-o", outputDir,
+		audioPath,
+	}
+}
+
+// HTTPSeparator posts the audio to a separation API which answers with the accompaniment
+// The request body is the WAV file (audio/wav); the response body is the accompaniment audio.
+type HTTPSeparator struct {
+	Endpoint string
+	client   *http.Client
+}
+
+// NewHTTPSeparator creates a separator calling the given API endpoint
+func NewHTTPSeparator(endpoint string) *HTTPSeparator {
+	return &HTTPSeparator{
+		Endpoint: endpoint,
+		client: &http.Client{
+			Timeout: 10 * time.Minute, // Separation runs at roughly real time on CPU
+		},
+	}
+}
+
+// Name implements Separator interface
+func (s *HTTPSeparator) Name() string {
+	return BackendHTTP
+}
+
+// Separate implements Separator interface
+func (s *HTTPSeparator) Separate(ctx context.Context, audioPath string, outputDir string) (string, error) {
+	audio, err := os.Open(audioPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open audio: %w", err)
+	}
+	defer audio.Close()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint, audio)
+	if err != nil {
+		return "", fmt.Errorf("failed to create separation request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "audio/wav")
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("separation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("separation API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	outputPath := filepath.Join(outputDir, baseName(audioPath)+"_accompaniment.wav")
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create accompaniment file: %w", err)
+	}
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		return "", fmt.Errorf("failed to read separation response: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return "", fmt.Errorf("failed to write accompaniment file: %w", err)
+	}
+	return stemPath(outputPath)
+}
+
+// run executes a separation CLI, reporting its stderr on failure
+func run(ctx context.Context, command string, args []string) error {
+	slog.Info("Separating audio", "command", command, "args", args)
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("audio separation cancelled: %w", ctx.Err())
+		}
+		return fmt.Errorf("failed to separate audio: %w, stderr: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// stemPath checks that a separation backend produced a non-empty stem
+func stemPath(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("accompaniment stem not found: %w", err)
+	}
+	if info.Size() == 0 {
+		return "", fmt.Errorf("accompaniment stem is empty: %s", path)
+	}
+	return path, nil
+}
+
+// baseName returns the file name without directory and extension, as the separation CLIs name their output folders
+func baseName(path string) string {
+	name := filepath.Base(path)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
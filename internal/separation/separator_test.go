@@ -0,0 +1,146 @@
+package separation
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		backend  string
+		command  string
+		endpoint string
+		model    string
+		want     Separator
+		wantErr  bool
+	}{
+		{name: "demucs defaults", backend: "demucs", want: &DemucsSeparator{Command: "demucs", Model: DefaultDemucsModel}},
+		{name: "demucs overrides", backend: "demucs", command: "/opt/demucs", model: "mdx_extra", want: &DemucsSeparator{Command: "/opt/demucs", Model: "mdx_extra"}},
+		{name: "spleeter", backend: "spleeter", want: &SpleeterSeparator{Command: "spleeter"}},
+		{name: "http without endpoint", backend: "http", wantErr: true},
+		{name: "unknown backend", backend: "umx", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.backend, tt.command, tt.endpoint, tt.model)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	got, err := New("http", "", "https://separator.example.com/separate", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Name() != BackendHTTP {
+		t.Errorf("expected %s, got %s", BackendHTTP, got.Name())
+	}
+}
+
+func TestSeparatorArgs(t *testing.T) {
+	demucs := demucsArgs("/tmp/audio.wav", "/tmp/stems", "htdemucs")
+	wantDemucs := []string{"--two-stems=vocals", "-n", "htdemucs", "-o", "/tmp/stems", "/tmp/audio.wav"}
+	if !reflect.DeepEqual(demucs, wantDemucs) {
+		t.Errorf("expected %v, got %v", wantDemucs, demucs)
+	}
+
+	spleeter := spleeterArgs("/tmp/audio.wav", "/tmp/stems")
+	wantSpleeter := []string{"separate", "-p", "spleeter:2stems", "-o", "/tmp/stems", "/tmp/audio.wav"}
+	if !reflect.DeepEqual(spleeter, wantSpleeter) {
+		t.Errorf("expected %v, got %v", wantSpleeter, spleeter)
+	}
+}
+
+func TestDemucsSeparator_Separate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake CLI is a shell script")
+	}
+	dir := t.TempDir()
+	audioPath := filepath.Join(dir, "source.wav")
+	if err := os.WriteFile(audioPath, []byte("RIFF"), 0644); err != nil {
+		t.Fatalf("failed to write audio: %v", err)
+	}
+
+	// The fake CLI writes the stem where Demucs would: <out>/<model>/<name>/no_vocals.wav
+	script := filepath.Join(dir, "demucs")
+	body := "#!/bin/sh\nmkdir -p \"$5/$3/source\" && printf music > \"$5/$3/source/no_vocals.wav\"\n"
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	separator := &DemucsSeparator{Command: script, Model: "htdemucs"}
+	outputDir := filepath.Join(dir, "stems")
+	path, err := separator.Separate(context.Background(), audioPath, outputDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join(outputDir, "htdemucs", "source", "no_vocals.wav"); path != want {
+		t.Errorf("expected %s, got %s", want, path)
+	}
+
+	// A CLI that exits cleanly without writing the stem is an error
+	empty := &SpleeterSeparator{Command: "true"}
+	if _, err := empty.Separate(context.Background(), audioPath, filepath.Join(dir, "none")); err == nil {
+		t.Errorf("expected error for missing stem, got nil")
+	}
+}
+
+func TestHTTPSeparator_Separate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "audio/wav" {
+			t.Errorf("expected audio/wav content type, got %s", r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) == "fail" {
+			http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("accompaniment of " + string(body)))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	audioPath := filepath.Join(dir, "source.wav")
+	if err := os.WriteFile(audioPath, []byte("speech"), 0644); err != nil {
+		t.Fatalf("failed to write audio: %v", err)
+	}
+
+	separator := NewHTTPSeparator(server.URL)
+	path, err := separator.Separate(context.Background(), audioPath, filepath.Join(dir, "stems"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read stem: %v", err)
+	}
+	if string(data) != "accompaniment of speech" {
+		t.Errorf("expected accompaniment of speech, got %s", data)
+	}
+
+	if err := os.WriteFile(audioPath, []byte("fail"), 0644); err != nil {
+		t.Fatalf("failed to write audio: %v", err)
+	}
+	if _, err := separator.Separate(context.Background(), audioPath, filepath.Join(dir, "stems")); err == nil {
+		t.Errorf("expected error for failed request, got nil")
+	}
+}
@@ -0,0 +1,149 @@
+package ssml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Speaking rate bounds of the prosody wrapping every document, in percent
+const (
+	MinRatePercent = 50
+	MaxRatePercent = 200
+)
+
+// MaxBreakMillis is the longest pause a single <break> may request
+const MaxBreakMillis = 10000
+
+// allowedElements lists the SSML elements kept in user-supplied fragments, with their allowed attributes
+// prosody may not set rate: the speaking rate of a dub is controlled by the document so it fits the video.
+var allowedElements = map[string]map[string]bool{
+	"break":    {"time": true, "strength": true},
+	"emphasis": {"level": true},
+	"say-as":   {"interpret-as": true, "format": true, "detail": true},
+	"sub":      {"alias": true},
+	"phoneme":  {"alphabet": true, "ph": true},
+	"prosody":  {"pitch": true, "volume": true},
+	"s":        {},
+	"p":        {},
+}
+
+// breakTimePattern matches valid <break time> values
+var breakTimePattern = regexp.MustCompile(`^\d+(\.\d+)?(ms|s)$`)
+
+// Escape escapes text for SSML content or a double-quoted attribute value
+// Characters that are not allowed in XML, such as control characters, are dropped.
+func Escape(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range text {
+		switch {
+		case r == '&':
+			b.WriteString("&amp;")
+		case r == '<':
+			b.WriteString("&lt;")
+		case r == '>':
+			b.WriteString("&gt;")
+		case r == '"':
+			b.WriteString("&quot;")
+		case r == '\'':
+			b.WriteString("&apos;")
+		case isXMLChar(r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isXMLChar reports whether r may appear in an XML 1.0 document
+func isXMLChar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		(r >= 0x20 && r <= 0xD7FF) ||
+		(r >= 0xE000 && r <= 0xFFFD) ||
+		(r >= 0x10000 && r <= 0x10FFFF)
+}
+
+// Speak wraps SSML content in a <speak> document spoken at ratePercent, clamped to the supported range
+// leadingMillis of silence is inserted before the speech.
+func Speak(content string, ratePercent int, leadingMillis int) string {
+	ratePercent = max(MinRatePercent, min(ratePercent, MaxRatePercent))
+	return fmt.Sprintf(`<speak>%s<prosody rate="%d%%">%s</prosody></speak>`, Breaks(leadingMillis), ratePercent, content)
+}
+
+// Breaks returns <break> elements for a pause of millis, split into breaks of at most MaxBreakMillis
+func Breaks(millis int) string {
+	var b strings.Builder
+	for millis > 0 {
+		chunk := min(millis, MaxBreakMillis)
+		fmt.Fprintf(&b, `<break time="%dms"/>`, chunk)
+		millis -= chunk
+	}
+	return b.String()
+}
+
+// Sanitize parses a user-supplied SSML fragment and rewrites it with only the allowed elements and attributes
+// Other elements are dropped but their text is kept, a <prosody> inside another is flattened into its parent,
+// and all text is re-escaped. Malformed XML is an error.
+func Sanitize(fragment string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader("<fragment>" + fragment + "</fragment>"))
+	decoder.Strict = true
+
+	var b strings.Builder
+	var open []bool // Whether each open element was written
+	prosodyDepth := 0
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid SSML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if len(open) == 0 {
+				open = append(open, false) // The <fragment> wrapper
+				continue
+			}
+			name := t.Name.Local
+			attributes, allowed := allowedElements[name]
+			if t.Name.Space != "" || (name == "prosody" && prosodyDepth > 0) {
+				allowed = false
+			}
+			if name == "prosody" {
+				prosodyDepth++
+			}
+			open = append(open, allowed)
+			if !allowed {
+				continue
+			}
+			b.WriteString("<" + name)
+			for _, attr := range t.Attr {
+				if attr.Name.Space != "" || !attributes[attr.Name.Local] {
+					continue
+				}
+				if name == "break" && attr.Name.Local == "time" && !breakTimePattern.MatchString(attr.Value) {
+					continue
+				}
+				fmt.Fprintf(&b, ` %s="%s"`, attr.Name.Local, Escape(attr.Value))
+			}
+			b.WriteString(">")
+		case xml.EndElement:
+			written := open[len(open)-1]
+			open = open[:len(open)-1]
+			if t.Name.Local == "prosody" {
+				prosodyDepth--
+			}
+			if written {
+				b.WriteString("</" + t.Name.Local + ">")
+			}
+		case xml.CharData:
+			b.WriteString(Escape(string(t)))
+		}
+	}
+	return strings.ReplaceAll(b.String(), "></break>", "/>"), nil
+}
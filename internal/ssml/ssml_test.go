@@ -0,0 +1,72 @@
+package ssml
+
+import "testing"
+
+func TestEscape(t *testing.T) {
+	got := Escape("Tom's \"R&D\" <team>\x00\x1b\tok")
+	want := "Tom&apos;s &quot;R&amp;D&quot; &lt;team&gt;\tok"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSpeak(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    int
+		leading int
+		want    string
+	}{
+		{"plain", 100, 0, `<speak><prosody rate="100%">Hi</prosody></speak>`},
+		{"clamped slow", 20, 0, `<speak><prosody rate="50%">Hi</prosody></speak>`},
+		{"clamped fast", 350, 0, `<speak><prosody rate="200%">Hi</prosody></speak>`},
+		{"leading silence", 100, 12500, `<speak><break time="10000ms"/><break time="2500ms"/><prosody rate="100%">Hi</prosody></speak>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Speak("Hi", tt.rate, tt.leading); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name     string
+		fragment string
+		want     string
+	}{
+		{"plain text", "Acme", "Acme"},
+		{"allowed elements", `<say-as interpret-as="characters">SQL</say-as><break time="300ms"/>`, `<say-as interpret-as="characters">SQL</say-as><break time="300ms"/>`},
+		{"disallowed element keeps text", `<audio src="https://example.com/a.mp3">beep</audio>`, "beep"},
+		{"disallowed attribute", `<emphasis level="strong" onclick="x">now</emphasis>`, `<emphasis level="strong">now</emphasis>`},
+		{"prosody rate removed", `<prosody rate="300%" pitch="+2st">fast</prosody>`, `<prosody pitch="+2st">fast</prosody>`},
+		{"nested prosody flattened", `<prosody volume="loud">a <prosody pitch="low">b</prosody> c</prosody>`, `<prosody volume="loud">a b c</prosody>`},
+		{"invalid break time", `<break time="forever"/>`, `<break/>`},
+		{"nested speak dropped", `<speak>hello</speak>`, "hello"},
+		{"text re-escaped", `Tom&apos;s &amp; "co"`, "Tom&apos;s &amp; &quot;co&quot;"},
+		{"comments dropped", `a<!-- note -->b`, "ab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sanitize(tt.fragment)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSanitize_Malformed(t *testing.T) {
+	for _, fragment := range []string{"<emphasis>open", "a < b", "</sub>", "R&D"} {
+		if _, err := Sanitize(fragment); err == nil {
+			t.Errorf("expected error for %q", fragment)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+)
+
+// AccessOptions configures access to buckets owned by other projects
+type AccessOptions struct {
+	BillingProject       string            // Project billed for requests to requester-pays buckets
+	RequesterPaysBuckets []string          // Buckets billing the requester; "*" for every bucket
+	BucketCredentials    map[string]string // Bucket -> service account to impersonate, or a credentials JSON file
+}
+
+// requesterPays reports whether requests to a bucket are billed to the billing project
+func (o AccessOptions) requesterPays(bucket string) bool {
+	if o.BillingProject == "" {
+		return false
+	}
+	for _, candidate := range o.RequesterPaysBuckets {
+		if candidate == "*" || candidate == bucket {
+			return true
+		}
+	}
+	return false
+}
+
+// IsServiceAccount reports whether a bucket credential is a service account email to impersonate
+// rather than the path of a credentials file
+func IsServiceAccount(credential string) bool {
+	return strings.Contains(credential, "@") && strings.HasSuffix(credential, ".gserviceaccount.com")
+}
+
+// SetAccessOptions bills requester-pays buckets to the billing project and creates a client for every bucket
+// with its own credentials. Other buckets keep the default client.
+func (s *GCSStorage) SetAccessOptions(ctx context.Context, opts AccessOptions) error {
+	clients := make(map[string]*storage.Client, len(opts.BucketCredentials))
+	for bucket, credential := range opts.BucketCredentials {
+		client, err := newCredentialClient(ctx, credential)
+		if err != nil {
+			for _, created := range clients {
+				created.Close()
+			}
+			return fmt.Errorf("failed to create GCS client for bucket %s: %w", bucket, err)
+		}
+		clients[bucket] = client
+	}
+	s.access = opts
+	s.bucketClients = clients
+	return nil
+}
+
+// Impersonator is implemented by storages that can access buckets as another service account
+type Impersonator interface {
+	// Impersonate returns a storage acting as the service account
+	Impersonate(ctx context.Context, serviceAccount string) (Storage, error)
+}
+
+// Impersonate returns a storage accessing every bucket as the service account through the IAM Credentials API,
+// so a tenant's bucket only needs to grant its own service account. Requester-pays buckets are still billed to
+// the billing project. Storages are created once per service account and closed with s.
+func (s *GCSStorage) Impersonate(ctx context.Context, serviceAccount string) (Storage, error) {
+	if !IsServiceAccount(serviceAccount) {
+		return nil, fmt.Errorf("invalid service account: %s", serviceAccount)
+	}
+
+	s.impersonatedMu.Lock()
+	defer s.impersonatedMu.Unlock()
+	if impersonated, ok := s.impersonated[serviceAccount]; ok {
+		return impersonated, nil
+	}
+
+	// The token source refreshes tokens for as long as the storage is cached, beyond the caller's context
+	client, err := newCredentialClient(context.WithoutCancel(ctx), serviceAccount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client for %s: %w", serviceAccount, err)
+	}
+	impersonated := &GCSStorage{
+		client:   client,
+		transfer: s.transfer,
+		upload:   s.upload,
+		access:   AccessOptions{BillingProject: s.access.BillingProject, RequesterPaysBuckets: s.access.RequesterPaysBuckets},
+	}
+	if s.impersonated == nil {
+		s.impersonated = make(map[string]*GCSStorage)
+	}
+	s.impersonated[serviceAccount] = impersonated
+	return impersonated, nil
+}
+
+// newCredentialClient creates a GCS client impersonating a service account or using a credentials file
+func newCredentialClient(ctx context.Context, credential string) (*storage.Client, error) {
+	if !IsServiceAccount(credential) {
+		return storage.NewClient(ctx, option.WithCredentialsFile(credential))
+	}
+	tokens, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: credential,
+		Scopes:          []string{storage.ScopeReadWrite},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate %s: %w", credential, err)
+	}
+	return storage.NewClient(ctx, option.WithTokenSource(tokens))
+}
+
+// bucket returns the handle of a bucket, with its own client and billing project when configured
+func (s *GCSStorage) bucket(name string) *storage.BucketHandle {
+	client := s.client
+	if bucketClient, ok := s.bucketClients[name]; ok {
+		client = bucketClient
+	}
+	handle := client.Bucket(name)
+	if s.access.requesterPays(name) {
+		handle = handle.UserProject(s.access.BillingProject)
+	}
+	return handle
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestAccessOptions_RequesterPays(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   AccessOptions
+		bucket string
+		want   bool
+	}{
+		{"listed bucket", AccessOptions{BillingProject: "billing", RequesterPaysBuckets: []string{"shared"}}, "shared", true},
+		{"other bucket", AccessOptions{BillingProject: "billing", RequesterPaysBuckets: []string{"shared"}}, "own", false},
+		{"every bucket", AccessOptions{BillingProject: "billing", RequesterPaysBuckets: []string{"*"}}, "own", true},
+		{"no billing project", AccessOptions{RequesterPaysBuckets: []string{"shared"}}, "shared", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.requesterPays(tt.bucket); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIsServiceAccount(t *testing.T) {
+	tests := map[string]bool{
+		"reader@partner-project.iam.gserviceaccount.com": true,
+		"/secrets/partner-key.json":                      false,
+		"gserviceaccount.com":                            false,
+	}
+	for credential, want := range tests {
+		if got := IsServiceAccount(credential); got != want {
+			t.Errorf("expected %v for %s, got %v", want, credential, got)
+		}
+	}
+}
+
+func TestSetAccessOptions_InvalidCredentialsFile(t *testing.T) {
+	s := &GCSStorage{}
+	err := s.SetAccessOptions(context.Background(), AccessOptions{
+		BucketCredentials: map[string]string{"partner": filepath.Join(t.TempDir(), "missing.json")},
+	})
+	if err == nil {
+		t.Error("expected error for a missing credentials file")
+	}
+}
+
+func TestImpersonate_InvalidServiceAccount(t *testing.T) {
+	s := &GCSStorage{}
+	if _, err := s.Impersonate(context.Background(), "/secrets/partner-key.json"); err == nil {
+		t.Error("expected error for a credentials file path")
+	}
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Destination is an output location: a bucket and an optional object prefix
+type Destination struct {
+	Bucket string
+	Prefix string // Without leading or trailing slashes; empty for the bucket root
+}
+
+// ParseDestination parses a destination URL of the form gs://bucket or gs://bucket/prefix
+func ParseDestination(url string) (Destination, error) {
+	if !strings.HasPrefix(url, "gs://") {
+		return Destination{}, fmt.Errorf("unsupported destination: %s (expected gs://bucket[/prefix])", url)
+	}
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(url, "gs://"), "/")
+	if bucket == "" {
+		return Destination{}, fmt.Errorf("destination bucket is required: %s", url)
+	}
+	return Destination{Bucket: bucket, Prefix: strings.Trim(prefix, "/")}, nil
+}
+
+// Path returns the object path for a path relative to the destination
+func (d Destination) Path(relative string) string {
+	if d.Prefix == "" {
+		return relative
+	}
+	return d.Prefix + "/" + relative
+}
+
+// String returns the destination as a gs:// URL
+func (d Destination) String() string {
+	if d.Prefix == "" {
+		return "gs://" + d.Bucket
+	}
+	return "gs://" + d.Bucket + "/" + d.Prefix
+}
+
+// CanWrite reports whether the service account may create objects in the bucket
+// Uses an IAM permission check, so nothing is written.
+func (s *GCSStorage) CanWrite(ctx context.Context, bucket string) (bool, error) {
+	granted, err := s.bucket(bucket).IAM().TestPermissions(ctx, []string{"storage.objects.create"})
+	if err != nil {
+		return false, fmt.Errorf("failed to check bucket permissions: %w", err)
+	}
+	return len(granted) == 1, nil
+}
@@ -0,0 +1,47 @@
+package storage
+
+import "testing"
+
+func TestParseDestination(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    Destination
+		wantErr bool
+	}{
+		{"bucket only", "gs://eu-cdn", Destination{Bucket: "eu-cdn"}, false},
+		{"bucket with prefix", "gs://eu-cdn/videos/dubbed/", Destination{Bucket: "eu-cdn", Prefix: "videos/dubbed"}, false},
+		{"missing bucket", "gs:///videos", Destination{}, true},
+		{"https URL", "https://storage.googleapis.com/eu-cdn", Destination{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDestination(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDestination_Path(t *testing.T) {
+	root := Destination{Bucket: "out"}
+	if got := root.Path("translations/job/de.mp4"); got != "translations/job/de.mp4" {
+		t.Errorf("expected path without prefix, got %s", got)
+	}
+	if got := root.String(); got != "gs://out" {
+		t.Errorf("expected gs://out, got %s", got)
+	}
+
+	prefixed := Destination{Bucket: "eu-cdn", Prefix: "dubbed"}
+	if got := prefixed.Path("translations/job/de.mp4"); got != "dubbed/translations/job/de.mp4" {
+		t.Errorf("expected prefixed path, got %s", got)
+	}
+	if got := prefixed.String(); got != "gs://eu-cdn/dubbed" {
+		t.Errorf("expected gs://eu-cdn/dubbed, got %s", got)
+	}
+}
@@ -2,20 +2,34 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
-	"path/filepath"
 	"strings"
+	"sync"
 
 	"cloud.google.com/go/storage"
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+	"github.com/sinouw/multilingual-video-processor/internal/usage"
 	"google.golang.org/api/option"
 )
 
+// ErrObjectNotFound is returned by ReadBytes when the object does not exist
+var ErrObjectNotFound = errors.New("object not found")
+
 // GCSStorage implements Storage interface for Google Cloud Storage
 type GCSStorage struct {
-	client *storage.Client
+	client   *storage.Client
+	transfer TransferOptions
+	upload   UploadOptions
+
+	access        AccessOptions
+	bucketClients map[string]*storage.Client // Clients of buckets with their own credentials
+
+	impersonatedMu sync.Mutex
+	impersonated   map[string]*GCSStorage // Storages acting as a service account, by service account
 }
 
 // NewGCSStorage creates a new GCS storage client
@@ -43,8 +57,16 @@ func NewGCSStorage(ctx context.Context) (*GCSStorage, error) {
 	return &GCSStorage{client: client}, nil
 }
 
-// Close closes the storage client
+// Close closes the storage clients
 func (s *GCSStorage) Close() error {
+	for _, client := range s.bucketClients {
+		client.Close()
+	}
+	s.impersonatedMu.Lock()
+	for _, impersonated := range s.impersonated {
+		impersonated.Close()
+	}
+	s.impersonatedMu.Unlock()
 	return s.client.Close()
 }
 
@@ -53,39 +75,62 @@ func (s *GCSStorage) Close() error {
 func (s *GCSStorage) Download(ctx context.Context, bucket, path string) (string, error) {
 	slog.Info("Downloading from GCS", "bucket", bucket, "path", path)
 
-	obj := s.client.Bucket(bucket).Object(path)
-	reader, err := obj.NewReader(ctx)
+	obj := s.bucket(bucket).Object(path)
+
+	// The attributes carry the checksums the downloaded data is verified against
+	attrs, err := obj.Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return "", transient.Permanent(fmt.Errorf("failed to read object attributes: %w", ErrObjectNotFound))
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to create reader: %w", err)
+		return "", fmt.Errorf("failed to read object attributes: %w", err)
 	}
-	defer reader.Close()
+	// Pin the generation so the data read matches the checksums
+	obj = obj.Generation(attrs.Generation)
+
+	// Each download gets its own temporary file, so concurrent jobs reading objects of the same name never share one
+	file, err := createDownloadFile(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	tmpPath := file.Name()
 
-	// Create temporary file
-	tmpDir := os.TempDir()
-	fileName := filepath.Base(path)
-	if fileName == "" || fileName == "." {
-		fileName = "downloaded_file"
+	// Large objects are fetched with concurrent ranged reads
+	if s.transfer.parallel(attrs.Size) {
+		if err := s.downloadParallel(ctx, obj, attrs, tmpPath); err != nil {
+			os.Remove(tmpPath) // Clean up on error
+			if ctx.Err() != nil {
+				return "", fmt.Errorf("download cancelled: %w", ctx.Err())
+			}
+			return "", err
+		}
+		usage.FromContext(ctx).AddStorageBytes(attrs.Size)
+		slog.Info("Download completed", "localPath", tmpPath)
+		return tmpPath, nil
 	}
-	tmpPath := filepath.Join(tmpDir, fmt.Sprintf("download_%d_%s", os.Getpid(), fileName))
 
-	file, err := os.Create(tmpPath)
+	reader, err := obj.NewReader(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp file: %w", err)
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to create reader: %w", err)
 	}
-	defer file.Close()
+	defer reader.Close()
 
 	// Check context cancellation before copy
 	select {
 	case <-ctx.Done():
+		os.Remove(tmpPath)
 		return "", fmt.Errorf("download cancelled: %w", ctx.Err())
 	default:
 	}
 
-	// Copy data with context awareness
+	// Copy data with context awareness, computing checksums on the way
 	// Use io.CopyBuffer for better control and context checking
+	checksums := newChecksumWriter()
 	copyDone := make(chan error, 1)
 	go func() {
-		_, err := io.CopyBuffer(file, reader, make([]byte, 32*1024)) // 32KB buffer
+		_, err := io.CopyBuffer(io.MultiWriter(file, checksums), reader, make([]byte, 32*1024)) // 32KB buffer
 		copyDone <- err
 	}()
 
@@ -105,13 +150,52 @@ func (s *GCSStorage) Download(ctx context.Context, bucket, path string) (string,
 
 	// Verify copy completed successfully
 	if ctx.Err() != nil {
+		os.Remove(tmpPath)
 		return "", fmt.Errorf("download cancelled: %w", ctx.Err())
 	}
 
+	if err := verifyChecksums(attrs, checksums.Sum()); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	usage.FromContext(ctx).AddStorageBytes(attrs.Size)
+
 	slog.Info("Download completed", "localPath", tmpPath)
 	return tmpPath, nil
 }
 
+// DownloadPrefix downloads at most length leading bytes of an object with a ranged read
+// The partial data cannot be verified against the object's checksums.
+func (s *GCSStorage) DownloadPrefix(ctx context.Context, bucket, path string, length int64) (string, error) {
+	slog.Info("Downloading object prefix from GCS", "bucket", bucket, "path", path, "length", length)
+
+	reader, err := s.bucket(bucket).Object(path).NewRangeReader(ctx, 0, length)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return "", transient.Permanent(fmt.Errorf("failed to create reader: %w", ErrObjectNotFound))
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create reader: %w", err)
+	}
+	defer reader.Close()
+
+	file, err := createDownloadFile(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	written, err := io.CopyBuffer(file, reader, make([]byte, 32*1024))
+	if err != nil {
+		os.Remove(file.Name())
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("download cancelled: %w", ctx.Err())
+		}
+		return "", fmt.Errorf("failed to copy data: %w", err)
+	}
+	usage.FromContext(ctx).AddStorageBytes(written)
+	return file.Name(), nil
+}
+
 // Upload uploads a file from local path to GCS
 func (s *GCSStorage) Upload(ctx context.Context, bucket, path string, localPath string) error {
 	slog.Info("Uploading to GCS", "bucket", bucket, "path", path, "localPath", localPath)
@@ -123,10 +207,40 @@ func (s *GCSStorage) Upload(ctx context.Context, bucket, path string, localPath
 	}
 	defer file.Close()
 
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat local file: %w", err)
+	}
+
+	// Checksums are sent with the upload so GCS rejects data corrupted in transit
+	checksums, err := computeChecksums(file)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind local file: %w", err)
+	}
+
+	// Large files are uploaded as concurrent parts composed into the object
+	if s.transfer.parallel(info.Size()) {
+		if err := s.uploadParallel(ctx, bucket, path, file, info.Size(), checksums); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("upload cancelled: %w", ctx.Err())
+			}
+			return err
+		}
+		usage.FromContext(ctx).AddStorageBytes(info.Size())
+		slog.Info("Upload completed", "bucket", bucket, "path", path)
+		return nil
+	}
+
 	// Upload to GCS
-	obj := s.client.Bucket(bucket).Object(path)
+	obj := s.bucket(bucket).Object(path)
 	writer := obj.NewWriter(ctx)
-	defer writer.Close()
+	s.applyUploadOptions(ctx, &writer.ObjectAttrs, path, "")
+	writer.MD5 = checksums.MD5
+	writer.CRC32C = checksums.CRC32C
+	writer.SendCRC32C = true
 
 	// Check context cancellation before copy
 	select {
@@ -145,6 +259,7 @@ func (s *GCSStorage) Upload(ctx context.Context, bucket, path string, localPath
 	select {
 	case err := <-copyDone:
 		if err != nil {
+			writer.Close()
 			return fmt.Errorf("failed to upload file: %w", err)
 		}
 	case <-ctx.Done():
@@ -152,20 +267,82 @@ func (s *GCSStorage) Upload(ctx context.Context, bucket, path string, localPath
 		writer.Close() // Close writer to stop copy
 		file.Close()
 		// Try to delete the object that was being written
-		obj := s.client.Bucket(bucket).Object(path)
+		obj := s.bucket(bucket).Object(path)
 		obj.Delete(context.Background()) // Use background context for cleanup
 		return fmt.Errorf("upload cancelled during copy: %w", ctx.Err())
 	}
 
 	// Verify copy completed successfully
 	if ctx.Err() != nil {
+		writer.Close()
 		return fmt.Errorf("upload cancelled: %w", ctx.Err())
 	}
 
+	// The object is only committed once the writer is closed
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to finalize upload: %w", checksumRejection(err))
+	}
+	if err := verifyChecksums(writer.Attrs(), checksums); err != nil {
+		obj.Delete(context.Background()) // Do not leave a corrupted object behind
+		return err
+	}
+	usage.FromContext(ctx).AddStorageBytes(info.Size())
+
+	slog.Info("Upload completed", "bucket", bucket, "path", path)
+	return nil
+}
+
+// UploadBytes uploads in-memory content to GCS with the given content type
+func (s *GCSStorage) UploadBytes(ctx context.Context, bucket, path string, data []byte, contentType string) error {
+	slog.Info("Uploading content to GCS", "bucket", bucket, "path", path, "size", len(data))
+
+	obj := s.bucket(bucket).Object(path)
+	writer := obj.NewWriter(ctx)
+	s.applyUploadOptions(ctx, &writer.ObjectAttrs, path, contentType)
+	hasher := newChecksumWriter()
+	hasher.Write(data)
+	checksums := hasher.Sum()
+	writer.MD5 = checksums.MD5
+	writer.CRC32C = checksums.CRC32C
+	writer.SendCRC32C = true
+
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		if ctx.Err() != nil {
+			return fmt.Errorf("upload cancelled: %w", ctx.Err())
+		}
+		return fmt.Errorf("failed to upload content: %w", err)
+	}
+
+	// The object is only committed once the writer is closed
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to finalize upload: %w", checksumRejection(err))
+	}
+	usage.FromContext(ctx).AddStorageBytes(int64(len(data)))
+
 	slog.Info("Upload completed", "bucket", bucket, "path", path)
 	return nil
 }
 
+// ReadBytes reads a small GCS object fully into memory
+// Returns ErrObjectNotFound if the object does not exist
+func (s *GCSStorage) ReadBytes(ctx context.Context, bucket, path string) ([]byte, error) {
+	reader, err := s.bucket(bucket).Object(path).NewReader(ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reader: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	return data, nil
+}
+
 // GetPublicURL returns a public URL for a GCS file
 func (s *GCSStorage) GetPublicURL(bucket, path string) string {
 	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", bucket, path)
@@ -175,7 +352,7 @@ func (s *GCSStorage) GetPublicURL(bucket, path string) string {
 func (s *GCSStorage) Delete(ctx context.Context, bucket, path string) error {
 	slog.Info("Deleting from GCS", "bucket", bucket, "path", path)
 
-	obj := s.client.Bucket(bucket).Object(path)
+	obj := s.bucket(bucket).Object(path)
 	err := obj.Delete(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to delete file: %w", err)
@@ -187,7 +364,7 @@ func (s *GCSStorage) Delete(ctx context.Context, bucket, path string) error {
 
 // Exists checks if a file exists in GCS
 func (s *GCSStorage) Exists(ctx context.Context, bucket, path string) (bool, error) {
-	obj := s.client.Bucket(bucket).Object(path)
+	obj := s.bucket(bucket).Object(path)
 	_, err := obj.Attrs(ctx)
 	if err == storage.ErrObjectNotExist {
 		return false, nil
@@ -198,6 +375,49 @@ func (s *GCSStorage) Exists(ctx context.Context, bucket, path string) (bool, err
 	return true, nil
 }
 
+// Size returns the size of an object from its attributes
+// Returns ErrObjectNotFound if the object does not exist
+func (s *GCSStorage) Size(ctx context.Context, bucket, path string) (int64, error) {
+	attrs, err := s.bucket(bucket).Object(path).Attrs(ctx)
+	if err == storage.ErrObjectNotExist {
+		return 0, ErrObjectNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read object attributes: %w", err)
+	}
+	return attrs.Size, nil
+}
+
+// ContentHash identifies an object's content by the CRC32C and size GCS stores with it
+// Returns ErrObjectNotFound if the object does not exist
+func (s *GCSStorage) ContentHash(ctx context.Context, bucket, path string) (string, error) {
+	attrs, err := s.bucket(bucket).Object(path).Attrs(ctx)
+	if err == storage.ErrObjectNotExist {
+		return "", ErrObjectNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read object attributes: %w", err)
+	}
+	return contentHash(attrs.CRC32C, attrs.Size), nil
+}
+
+// Copy copies an object server-side, also across buckets in other regions
+// Returns ErrObjectNotFound if the source object does not exist
+func (s *GCSStorage) Copy(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	slog.Info("Copying GCS object", "srcBucket", srcBucket, "srcPath", srcPath, "dstBucket", dstBucket, "dstPath", dstPath)
+
+	src := s.bucket(srcBucket).Object(srcPath)
+	dst := s.bucket(dstBucket).Object(dstPath)
+	_, err := dst.CopierFrom(src).Run(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return transient.Permanent(fmt.Errorf("failed to copy object: %w", ErrObjectNotFound))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy object: %w", err)
+	}
+	return nil
+}
+
 // ParseGCSURL parses a GCS URL (gs://bucket/path or https://storage.googleapis.com/bucket/path)
 // Returns bucket and path
 func ParseGCSURL(url string) (bucket, path string, err error) {
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+)
+
+var (
+	// ErrSourceUnreachable is returned when an HTTPS source cannot be fetched
+	ErrSourceUnreachable = errors.New("source URL is unreachable")
+	// ErrNotVideo is returned when an HTTPS source is not served with a video content type
+	ErrNotVideo = errors.New("source URL is not a video")
+	// ErrSourceTooLarge is returned when an HTTPS source is larger than the download limit
+	ErrSourceTooLarge = errors.New("source is too large")
+)
+
+// IsHTTPSource reports whether a video URL is fetched over HTTPS rather than with the GCS API
+// Cloud Storage URLs (https://storage.googleapis.com/...) are read with the service credentials instead.
+func IsHTTPSource(url string) bool {
+	return strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "https://storage.googleapis.com/")
+}
+
+// SourceInfo describes an HTTPS source from the headers of its HEAD response
+type SourceInfo struct {
+	Size        int64  // Content length in bytes, -1 when not reported
+	ContentType string // Media type without parameters, e.g. video/mp4
+}
+
+// HeadSource checks that an HTTPS source answers a HEAD request with a video/* content type
+// Failed requests and non-2xx responses return ErrSourceUnreachable; other content types return ErrNotVideo.
+func HeadSource(ctx context.Context, client *http.Client, url string) (*SourceInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSourceUnreachable, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSourceUnreachable, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%w: HEAD returned status %d", ErrSourceUnreachable, resp.StatusCode)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasPrefix(contentType, "video/") {
+		return nil, fmt.Errorf("%w: content type is %q", ErrNotVideo, resp.Header.Get("Content-Type"))
+	}
+	return &SourceInfo{Size: resp.ContentLength, ContentType: contentType}, nil
+}
+
+// DownloadHTTPPrefix downloads at most length leading bytes of an HTTPS source to a temporary local file and returns
+// its path. The bytes are requested with a Range header; servers ignoring it are read up to length bytes.
+func DownloadHTTPPrefix(ctx context.Context, client *http.Client, url string, length int64) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", transient.Permanent(fmt.Errorf("failed to create request: %w", err))
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", length-1))
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download source: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("failed to download source: %w", &transient.HTTPError{Service: "source", StatusCode: resp.StatusCode, Body: string(body)})
+	}
+
+	file, err := createDownloadFile(path.Base(req.URL.Path))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if _, err := io.CopyBuffer(file, io.LimitReader(resp.Body, length), make([]byte, 32*1024)); err != nil {
+		os.Remove(file.Name())
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("download cancelled: %w", ctx.Err())
+		}
+		return "", fmt.Errorf("failed to copy data: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// DownloadHTTP downloads an HTTPS source of at most maxBytes to a temporary local file and returns its path
+// Rate limited (429) and server-side (5xx) responses are reported as transient errors. Sources that report or
+// turn out to have more than maxBytes fail with ErrSourceTooLarge, whatever their Content-Length claims.
+func DownloadHTTP(ctx context.Context, client *http.Client, url string, maxBytes int64) (string, error) {
+	slog.Info("Downloading over HTTPS", "url", url)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", transient.Permanent(fmt.Errorf("failed to create request: %w", err))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download source: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("failed to download source: %w", &transient.HTTPError{Service: "source", StatusCode: resp.StatusCode, Body: string(body)})
+	}
+	if resp.ContentLength > maxBytes {
+		return "", transient.Permanent(fmt.Errorf("%w: %d bytes > %d bytes", ErrSourceTooLarge, resp.ContentLength, maxBytes))
+	}
+
+	file, err := createDownloadFile(path.Base(req.URL.Path))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	// One byte past the limit tells an oversized source from one of exactly maxBytes
+	written, err := io.CopyBuffer(file, io.LimitReader(resp.Body, maxBytes+1), make([]byte, 32*1024))
+	if err != nil {
+		os.Remove(file.Name())
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("download cancelled: %w", ctx.Err())
+		}
+		return "", fmt.Errorf("failed to copy data: %w", err)
+	}
+	if written > maxBytes {
+		os.Remove(file.Name())
+		return "", transient.Permanent(fmt.Errorf("%w: more than %d bytes", ErrSourceTooLarge, maxBytes))
+	}
+
+	slog.Info("Download completed", "localPath", file.Name())
+	return file.Name(), nil
+}
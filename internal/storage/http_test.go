@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+)
+
+func TestIsHTTPSource(t *testing.T) {
+	tests := map[string]bool{
+		"https://cdn.example.com/video.mp4":                 true,
+		"https://storage.googleapis.com/bucket/video.mp4":   false,
+		"gs://bucket/video.mp4":                             false,
+		"http://cdn.example.com/video.mp4":                  false,
+		"https://storage.googleapis.com.evil.com/video.mp4": true,
+	}
+	for url, want := range tests {
+		if got := IsHTTPSource(url); got != want {
+			t.Errorf("expected %v for %s, got %v", want, url, got)
+		}
+	}
+}
+
+func TestHeadSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("expected HEAD request, got %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/video.mp4":
+			w.Header().Set("Content-Type", "video/mp4; codecs=avc1")
+			w.Header().Set("Content-Length", "2048")
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	info, err := HeadSource(context.Background(), server.Client(), server.URL+"/video.mp4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Size != 2048 || info.ContentType != "video/mp4" {
+		t.Errorf("unexpected source info: %+v", info)
+	}
+
+	tests := []struct {
+		name string
+		url  string
+		want error
+	}{
+		{"not a video", server.URL + "/page.html", ErrNotVideo},
+		{"missing", server.URL + "/missing.mp4", ErrSourceUnreachable},
+		{"connection refused", "http://127.0.0.1:1/video.mp4", ErrSourceUnreachable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := HeadSource(context.Background(), server.Client(), tt.url)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestDownloadHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/busy.mp4":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case "/chunked.mp4":
+			// Flushing before the end omits Content-Length
+			w.Write([]byte("video"))
+			w.(http.Flusher).Flush()
+		}
+		w.Write([]byte("video data"))
+	}))
+	defer server.Close()
+
+	path, err := DownloadHTTP(context.Background(), server.Client(), server.URL+"/video.mp4", 1024)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(path)
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "video data" {
+		t.Errorf("expected downloaded data, got %q (%v)", data, err)
+	}
+
+	_, err = DownloadHTTP(context.Background(), server.Client(), server.URL+"/busy.mp4", 1024)
+	if !transient.IsTransient(err) {
+		t.Errorf("expected 503 to be transient, got %v", err)
+	}
+
+	// The limit holds for sources whose length is unknown as well
+	_, err = DownloadHTTP(context.Background(), server.Client(), server.URL+"/chunked.mp4", 4)
+	if !errors.Is(err, ErrSourceTooLarge) {
+		t.Errorf("expected ErrSourceTooLarge, got %v", err)
+	}
+}
+
+func TestDownloadHTTPPrefix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ranged.mp4" {
+			http.ServeContent(w, r, "ranged.mp4", time.Time{}, strings.NewReader("video data"))
+			return
+		}
+		w.Write([]byte("video data")) // Ignores the Range header
+	}))
+	defer server.Close()
+
+	for _, name := range []string{"/ranged.mp4", "/whole.mp4"} {
+		path, err := DownloadHTTPPrefix(context.Background(), server.Client(), server.URL+name, 5)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		data, _ := os.ReadFile(path)
+		os.Remove(path)
+		if string(data) != "video" {
+			t.Errorf("%s: expected the first 5 bytes, got %q", name, data)
+		}
+	}
+}
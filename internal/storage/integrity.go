@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+)
+
+// ErrChecksumMismatch is returned when transferred data does not match the object's checksums
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// crc32cTable is the Castagnoli table GCS uses for CRC32C checksums
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// contentHash formats an object's CRC32C and size as a ContentHash value
+func contentHash(crc32c uint32, size int64) string {
+	return fmt.Sprintf("crc32c:%08x:%d", crc32c, size)
+}
+
+// Checksums holds the MD5 and CRC32C of transferred data
+type Checksums struct {
+	MD5    []byte
+	CRC32C uint32
+}
+
+// checksumWriter computes MD5 and CRC32C of everything written to it
+type checksumWriter struct {
+	md5    hash.Hash
+	crc32c hash.Hash32
+}
+
+func newChecksumWriter() *checksumWriter {
+	return &checksumWriter{md5: md5.New(), crc32c: crc32.New(crc32cTable)}
+}
+
+func (w *checksumWriter) Write(p []byte) (int, error) {
+	w.md5.Write(p)
+	w.crc32c.Write(p)
+	return len(p), nil
+}
+
+// Sum returns the checksums of the data written so far
+func (w *checksumWriter) Sum() Checksums {
+	return Checksums{MD5: w.md5.Sum(nil), CRC32C: w.crc32c.Sum32()}
+}
+
+// computeChecksums reads r to the end and returns its checksums
+func computeChecksums(r io.Reader) (Checksums, error) {
+	w := newChecksumWriter()
+	if _, err := io.CopyBuffer(w, r, make([]byte, 32*1024)); err != nil {
+		return Checksums{}, fmt.Errorf("failed to compute checksums: %w", err)
+	}
+	return w.Sum(), nil
+}
+
+// verifyChecksums compares local checksums with the object's attributes
+// Composite objects have no MD5, so only CRC32C is compared for them. Objects served
+// with decompressive transcoding are not byte-identical to what is stored and are skipped.
+func verifyChecksums(attrs *storage.ObjectAttrs, got Checksums) error {
+	if attrs.ContentEncoding == "gzip" {
+		return nil
+	}
+	object := fmt.Sprintf("gs://%s/%s", attrs.Bucket, attrs.Name)
+	if len(attrs.MD5) > 0 && !bytes.Equal(attrs.MD5, got.MD5) {
+		return fmt.Errorf("%w for %s: md5 %x, expected %x", ErrChecksumMismatch, object, got.MD5, attrs.MD5)
+	}
+	if attrs.CRC32C != got.CRC32C {
+		return fmt.Errorf("%w for %s: crc32c %08x, expected %08x", ErrChecksumMismatch, object, got.CRC32C, attrs.CRC32C)
+	}
+	return nil
+}
+
+// checksumRejection converts GCS rejecting an upload whose data does not match the sent checksums into ErrChecksumMismatch
+func checksumRejection(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest {
+		message := strings.ToLower(apiErr.Message)
+		if strings.Contains(message, "crc32c") || strings.Contains(message, "md5") {
+			return fmt.Errorf("%w: %v", ErrChecksumMismatch, err)
+		}
+	}
+	return err
+}
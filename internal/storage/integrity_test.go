@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"crypto/md5"
+	"errors"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+)
+
+func TestComputeChecksums(t *testing.T) {
+	got, err := computeChecksums(strings.NewReader("123456789"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Standard CRC32C check value
+	if got.CRC32C != 0xe3069283 {
+		t.Errorf("expected crc32c e3069283, got %08x", got.CRC32C)
+	}
+	want := md5.Sum([]byte("123456789"))
+	if string(got.MD5) != string(want[:]) {
+		t.Errorf("expected md5 %x, got %x", want, got.MD5)
+	}
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	data, _ := computeChecksums(strings.NewReader("video bytes"))
+	other, _ := computeChecksums(strings.NewReader("corrupted"))
+
+	tests := []struct {
+		name    string
+		attrs   storage.ObjectAttrs
+		wantErr bool
+	}{
+		{"matching", storage.ObjectAttrs{MD5: data.MD5, CRC32C: data.CRC32C}, false},
+		{"composite without md5", storage.ObjectAttrs{CRC32C: data.CRC32C}, false},
+		{"md5 mismatch", storage.ObjectAttrs{MD5: other.MD5, CRC32C: data.CRC32C}, true},
+		{"crc32c mismatch", storage.ObjectAttrs{CRC32C: other.CRC32C}, true},
+		{"transcoded", storage.ObjectAttrs{ContentEncoding: "gzip", CRC32C: other.CRC32C}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := tt.attrs
+			attrs.Bucket, attrs.Name = "in", "video.mp4"
+			err := verifyChecksums(&attrs, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrChecksumMismatch) {
+				t.Errorf("expected ErrChecksumMismatch, got %v", err)
+			}
+		})
+	}
+}
+
+func TestChecksumRejection(t *testing.T) {
+	rejected := &googleapi.Error{Code: 400, Message: "Provided CRC32C \"abc=\" doesn't match calculated CRC32C \"def=\"."}
+	if err := checksumRejection(rejected); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("expected ErrChecksumMismatch, got %v", err)
+	}
+
+	other := &googleapi.Error{Code: 400, Message: "Invalid bucket name"}
+	if err := checksumRejection(other); errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("expected other errors to be returned unchanged, got %v", err)
+	}
+}
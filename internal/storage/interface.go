@@ -2,16 +2,21 @@ package storage
 
 import (
 	"context"
-	"io"
 )
 
 // Storage defines the interface for storage operations
 type Storage interface {
-	// Download downloads a file from storage and returns a reader
-	Download(ctx context.Context, bucket, path string) (io.ReadCloser, error)
+	// Download downloads a file from storage to a temporary local file and returns its path
+	Download(ctx context.Context, bucket, path string) (string, error)
 
-	// Upload uploads a file to storage
-	Upload(ctx context.Context, bucket, path string, reader io.Reader) error
+	// Upload uploads a local file to storage
+	Upload(ctx context.Context, bucket, path string, localPath string) error
+
+	// UploadBytes uploads in-memory content with the given content type
+	UploadBytes(ctx context.Context, bucket, path string, data []byte, contentType string) error
+
+	// ReadBytes reads a small object fully into memory, returning ErrObjectNotFound if it does not exist
+	ReadBytes(ctx context.Context, bucket, path string) ([]byte, error)
 
 	// GetPublicURL returns a public URL for a stored file
 	GetPublicURL(bucket, path string) string
@@ -21,4 +26,25 @@ type Storage interface {
 
 	// Exists checks if a file exists in storage
 	Exists(ctx context.Context, bucket, path string) (bool, error)
+
+	// Size returns the size of an object in bytes, or ErrObjectNotFound if it does not exist
+	Size(ctx context.Context, bucket, path string) (int64, error)
+
+	// Copy copies an object to another bucket or path, returning ErrObjectNotFound if the source does not exist
+	Copy(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string) error
+
+	// CanWrite reports whether objects may be created in the bucket
+	CanWrite(ctx context.Context, bucket string) (bool, error)
+}
+
+// PrefixDownloader is implemented by storages that can download the start of an object without reading all of it
+type PrefixDownloader interface {
+	// DownloadPrefix downloads at most length leading bytes of an object to a temporary local file and returns its path
+	DownloadPrefix(ctx context.Context, bucket, path string, length int64) (string, error)
+}
+
+// ContentHasher is implemented by storages that can identify an object's content without downloading it
+type ContentHasher interface {
+	// ContentHash returns a value that changes with the object's content, or ErrObjectNotFound if it does not exist
+	ContentHash(ctx context.Context, bucket, path string) (string, error)
 }
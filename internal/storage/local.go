@@ -0,0 +1,277 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage implements Storage interface on the local filesystem, for development without GCP credentials
+// Each bucket is a directory under the root, so gs://bucket/path maps to root/bucket/path.
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage creates a filesystem storage rooted at root, creating the directory if needed
+func NewLocalStorage(root string) (*LocalStorage, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalStorage{root: root}, nil
+}
+
+// objectPath returns the file backing an object, rejecting paths that escape the bucket directory
+func (s *LocalStorage) objectPath(bucket, path string) (string, error) {
+	if bucket == "" || strings.ContainsAny(bucket, `/\`) || bucket == "." || bucket == ".." {
+		return "", fmt.Errorf("invalid bucket name: %q", bucket)
+	}
+	cleaned := filepath.Clean("/" + path)
+	if cleaned == "/" {
+		return "", fmt.Errorf("invalid object path: %q", path)
+	}
+	return filepath.Join(s.root, bucket, cleaned), nil
+}
+
+// Download copies an object to a temporary local file and returns its path
+func (s *LocalStorage) Download(ctx context.Context, bucket, path string) (string, error) {
+	slog.Info("Downloading from local storage", "bucket", bucket, "path", path)
+
+	source, err := s.objectPath(bucket, path)
+	if err != nil {
+		return "", err
+	}
+	file, err := createDownloadFile(source)
+	if err != nil {
+		return "", err
+	}
+	tmpPath := file.Name()
+	file.Close()
+	if err := copyFile(source, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to download file: %w", err)
+	}
+
+	slog.Info("Download completed", "bucket", bucket, "path", path, "localPath", tmpPath)
+	return tmpPath, nil
+}
+
+// DownloadPrefix copies at most length leading bytes of an object to a temporary local file and returns its path
+func (s *LocalStorage) DownloadPrefix(ctx context.Context, bucket, path string, length int64) (string, error) {
+	source, err := s.objectPath(bucket, path)
+	if err != nil {
+		return "", err
+	}
+	in, err := os.Open(source)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", ErrObjectNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer in.Close()
+
+	file, err := createDownloadFile(source)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, io.LimitReader(in, length)); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to download file: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// Upload copies a local file into storage
+func (s *LocalStorage) Upload(ctx context.Context, bucket, path string, localPath string) error {
+	slog.Info("Uploading to local storage", "bucket", bucket, "path", path, "localPath", localPath)
+
+	target, err := s.objectPath(bucket, path)
+	if err != nil {
+		return err
+	}
+	if err := copyFile(localPath, target); err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	slog.Info("Upload completed", "bucket", bucket, "path", path)
+	return nil
+}
+
+// UploadBytes writes in-memory content into storage; the content type is not kept
+func (s *LocalStorage) UploadBytes(ctx context.Context, bucket, path string, data []byte, contentType string) error {
+	target, err := s.objectPath(bucket, path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create object directory: %w", err)
+	}
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		return fmt.Errorf("failed to upload content: %w", err)
+	}
+	return nil
+}
+
+// ReadBytes reads an object fully into memory
+// Returns ErrObjectNotFound if the object does not exist
+func (s *LocalStorage) ReadBytes(ctx context.Context, bucket, path string) ([]byte, error) {
+	source, err := s.objectPath(bucket, path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(source)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	return data, nil
+}
+
+// GetPublicURL returns a file:// URL for an object
+func (s *LocalStorage) GetPublicURL(bucket, path string) string {
+	target, err := s.objectPath(bucket, path)
+	if err != nil {
+		return ""
+	}
+	return "file://" + filepath.ToSlash(target)
+}
+
+// Delete deletes an object
+func (s *LocalStorage) Delete(ctx context.Context, bucket, path string) error {
+	target, err := s.objectPath(bucket, path)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// Exists checks if an object exists
+func (s *LocalStorage) Exists(ctx context.Context, bucket, path string) (bool, error) {
+	target, err := s.objectPath(bucket, path)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check existence: %w", err)
+	}
+	return true, nil
+}
+
+// Size returns the size of an object in bytes
+// Returns ErrObjectNotFound if the object does not exist
+func (s *LocalStorage) Size(ctx context.Context, bucket, path string) (int64, error) {
+	target, err := s.objectPath(bucket, path)
+	if err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, ErrObjectNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat file: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// ContentHash identifies an object's content by its CRC32C and size, like GCS
+// Returns ErrObjectNotFound if the object does not exist
+func (s *LocalStorage) ContentHash(ctx context.Context, bucket, path string) (string, error) {
+	target, err := s.objectPath(bucket, path)
+	if err != nil {
+		return "", err
+	}
+	file, err := os.Open(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", ErrObjectNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	checksum := crc32.New(crc32cTable)
+	size, err := io.Copy(checksum, file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return contentHash(checksum.Sum32(), size), nil
+}
+
+// Copy copies an object to another bucket or path
+// Returns ErrObjectNotFound if the source object does not exist
+func (s *LocalStorage) Copy(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	source, err := s.objectPath(srcBucket, srcPath)
+	if err != nil {
+		return err
+	}
+	target, err := s.objectPath(dstBucket, dstPath)
+	if err != nil {
+		return err
+	}
+	err = copyFile(source, target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrObjectNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+	return nil
+}
+
+// CanWrite always reports true: buckets are created on first write
+func (s *LocalStorage) CanWrite(ctx context.Context, bucket string) (bool, error) {
+	return true, nil
+}
+
+// copyFile copies source to target, creating the target directory if needed
+// createDownloadFile creates a uniquely named temporary file for a download, keeping the object's base name
+func createDownloadFile(name string) (*os.File, error) {
+	base := filepath.Base(name)
+	if base == "" || base == "." || base == "/" {
+		base = "downloaded_file"
+	}
+	file, err := os.CreateTemp(os.TempDir(), "download_*_"+base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	return file, nil
+}
+
+func copyFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var (
+	_ Storage       = (*GCSStorage)(nil)
+	_ Storage       = (*LocalStorage)(nil)
+	_ ContentHasher = (*GCSStorage)(nil)
+	_ ContentHasher = (*LocalStorage)(nil)
+
+	_ PrefixDownloader = (*GCSStorage)(nil)
+	_ PrefixDownloader = (*LocalStorage)(nil)
+)
+
+func TestLocalStorage_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := store.ReadBytes(ctx, "bucket", "missing.txt"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("expected ErrObjectNotFound, got %v", err)
+	}
+
+	if err := store.UploadBytes(ctx, "bucket", "jobs/job-1/transcript.txt", []byte("hello"), "text/plain"); err != nil {
+		t.Fatalf("unexpected upload error: %v", err)
+	}
+	data, err := store.ReadBytes(ctx, "bucket", "jobs/job-1/transcript.txt")
+	if err != nil || string(data) != "hello" {
+		t.Errorf("expected hello, got %q (%v)", data, err)
+	}
+
+	localPath, err := store.Download(ctx, "bucket", "jobs/job-1/transcript.txt")
+	if err != nil {
+		t.Fatalf("unexpected download error: %v", err)
+	}
+	defer os.Remove(localPath)
+
+	if err := store.Upload(ctx, "other", "copy.txt", localPath); err != nil {
+		t.Fatalf("unexpected upload error: %v", err)
+	}
+	if exists, err := store.Exists(ctx, "other", "copy.txt"); err != nil || !exists {
+		t.Errorf("expected uploaded copy to exist, got %v (%v)", exists, err)
+	}
+	if size, err := store.Size(ctx, "other", "copy.txt"); err != nil || size != 5 {
+		t.Errorf("expected size 5, got %d (%v)", size, err)
+	}
+	if _, err := store.Size(ctx, "other", "missing.txt"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("expected ErrObjectNotFound, got %v", err)
+	}
+
+	if err := store.Copy(ctx, "other", "copy.txt", "replica", "eu/copy.txt"); err != nil {
+		t.Fatalf("unexpected copy error: %v", err)
+	}
+	if data, err := store.ReadBytes(ctx, "replica", "eu/copy.txt"); err != nil || string(data) != "hello" {
+		t.Errorf("expected copied hello, got %q (%v)", data, err)
+	}
+	if err := store.Copy(ctx, "other", "missing.txt", "replica", "missing.txt"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("expected ErrObjectNotFound, got %v", err)
+	}
+
+	url := store.GetPublicURL("other", "copy.txt")
+	if !strings.HasPrefix(url, "file://") || !strings.HasSuffix(url, "/other/copy.txt") {
+		t.Errorf("unexpected public URL %s", url)
+	}
+
+	if err := store.Delete(ctx, "other", "copy.txt"); err != nil {
+		t.Fatalf("unexpected delete error: %v", err)
+	}
+	if exists, _ := store.Exists(ctx, "other", "copy.txt"); exists {
+		t.Error("expected deleted object to be gone")
+	}
+}
+
+func TestLocalStorage_DownloadSameName(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store.UploadBytes(ctx, "bucket", "job-1/video.mp4", []byte("first"), "video/mp4")
+	store.UploadBytes(ctx, "bucket", "job-2/video.mp4", []byte("second"), "video/mp4")
+
+	// Objects sharing a base name must not be downloaded to the same file
+	first, err := store.Download(ctx, "bucket", "job-1/video.mp4")
+	if err != nil {
+		t.Fatalf("unexpected download error: %v", err)
+	}
+	defer os.Remove(first)
+	second, err := store.Download(ctx, "bucket", "job-2/video.mp4")
+	if err != nil {
+		t.Fatalf("unexpected download error: %v", err)
+	}
+	defer os.Remove(second)
+
+	if first == second {
+		t.Fatalf("expected distinct temp files, both downloaded to %s", first)
+	}
+	if data, _ := os.ReadFile(first); string(data) != "first" {
+		t.Errorf("expected the first download to be kept, got %q", data)
+	}
+	if !strings.HasSuffix(first, "_video.mp4") {
+		t.Errorf("expected the object's name to be kept, got %s", first)
+	}
+}
+
+func TestLocalStorage_DownloadPrefix(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store.UploadBytes(ctx, "bucket", "video.mp4", []byte("video data"), "video/mp4")
+
+	path, err := store.DownloadPrefix(ctx, "bucket", "video.mp4", 5)
+	if err != nil {
+		t.Fatalf("unexpected download error: %v", err)
+	}
+	defer os.Remove(path)
+	if data, _ := os.ReadFile(path); string(data) != "video" {
+		t.Errorf("expected the first 5 bytes, got %q", data)
+	}
+
+	if _, err := store.DownloadPrefix(ctx, "bucket", "missing.mp4", 5); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("expected ErrObjectNotFound, got %v", err)
+	}
+}
+
+func TestLocalStorage_ContentHash(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store.UploadBytes(ctx, "bucket", "a.mp4", []byte("hello"), "video/mp4")
+	store.UploadBytes(ctx, "bucket", "b.mp4", []byte("hello"), "video/mp4")
+	store.UploadBytes(ctx, "bucket", "c.mp4", []byte("hellp"), "video/mp4")
+
+	a, err := store.ContentHash(ctx, "bucket", "a.mp4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// CRC32C of "hello" as GCS reports it
+	if a != "crc32c:9a71bb4c:5" {
+		t.Errorf("unexpected content hash %s", a)
+	}
+	if b, _ := store.ContentHash(ctx, "bucket", "b.mp4"); b != a {
+		t.Errorf("expected identical content to hash alike, got %s and %s", a, b)
+	}
+	if c, _ := store.ContentHash(ctx, "bucket", "c.mp4"); c == a {
+		t.Error("expected different content to hash differently")
+	}
+	if _, err := store.ContentHash(ctx, "bucket", "missing.mp4"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("expected ErrObjectNotFound, got %v", err)
+	}
+}
+
+func TestLocalStorage_StaysInsideRoot(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := store.UploadBytes(context.Background(), "bucket", "../../escape.txt", []byte("x"), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "bucket", "escape.txt")); err != nil {
+		t.Errorf("expected object kept inside the bucket directory: %v", err)
+	}
+
+	for _, bucket := range []string{"", "..", "a/b"} {
+		if err := store.UploadBytes(context.Background(), bucket, "file.txt", []byte("x"), ""); err == nil {
+			t.Errorf("expected error for bucket %q", bucket)
+		}
+	}
+}
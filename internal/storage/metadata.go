@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"cloud.google.com/go/storage"
+)
+
+// UploadOptions sets the metadata of every object uploaded to GCS
+type UploadOptions struct {
+	CacheControl string            // Cache-Control header served with the objects; empty keeps the GCS default
+	StorageClass string            // STANDARD, NEARLINE, COLDLINE or ARCHIVE for job outputs; empty uses the bucket's default class
+	Metadata     map[string]string // Custom metadata added to every object
+}
+
+// SetUploadOptions sets the cache control, storage class and custom metadata of uploaded objects
+func (s *GCSStorage) SetUploadOptions(opts UploadOptions) {
+	s.upload = opts
+}
+
+// contentTypes lists the media types of output files that the system MIME tables may not know
+var contentTypes = map[string]string{
+	".json": "application/json",
+	".m3u8": "application/vnd.apple.mpegurl",
+	".mp3":  "audio/mpeg",
+	".mp4":  "video/mp4",
+	".srt":  "application/x-subrip",
+	".ts":   "video/mp2t",
+	".txt":  "text/plain; charset=utf-8",
+	".vtt":  "text/vtt",
+	".wav":  "audio/wav",
+	".zip":  "application/zip",
+}
+
+// ContentType returns the media type of an object from its extension, or "" (which lets GCS detect it) if unknown
+func ContentType(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if contentType, ok := contentTypes[ext]; ok {
+		return contentType
+	}
+	return mime.TypeByExtension(ext)
+}
+
+type metadataKey struct{}
+
+// WithObjectMetadata returns a context whose uploads carry the custom metadata key=value, besides the
+// metadata of ctx, e.g. the job ID and language of the outputs
+func WithObjectMetadata(ctx context.Context, key, value string) context.Context {
+	parent := objectMetadata(ctx)
+	metadata := make(map[string]string, len(parent)+1)
+	for k, v := range parent {
+		metadata[k] = v
+	}
+	metadata[key] = value
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// objectMetadata returns the custom metadata of a context's uploads (must not be modified)
+func objectMetadata(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return metadata
+}
+
+type jobOutputKey struct{}
+
+// AsJobOutput returns a context whose uploads are final job outputs, which get the configured storage class
+// Other uploads (transcoder staging files, cache entries) are short-lived and keep the bucket's default class,
+// so they are not billed for the minimum storage duration of the colder classes.
+func AsJobOutput(ctx context.Context) context.Context {
+	return context.WithValue(ctx, jobOutputKey{}, true)
+}
+
+// isJobOutput reports whether a context's uploads are final job outputs
+func isJobOutput(ctx context.Context) bool {
+	output, _ := ctx.Value(jobOutputKey{}).(bool)
+	return output
+}
+
+// applyUploadOptions sets the attributes of an object about to be written to path
+// contentType overrides the type derived from the extension; context metadata overrides configured metadata.
+func (s *GCSStorage) applyUploadOptions(ctx context.Context, attrs *storage.ObjectAttrs, path, contentType string) {
+	if contentType == "" {
+		contentType = ContentType(path)
+	}
+	attrs.ContentType = contentType
+	attrs.CacheControl = s.upload.CacheControl
+	if isJobOutput(ctx) {
+		attrs.StorageClass = s.upload.StorageClass
+	}
+
+	jobMetadata := objectMetadata(ctx)
+	if len(s.upload.Metadata) == 0 && len(jobMetadata) == 0 {
+		return
+	}
+	attrs.Metadata = make(map[string]string, len(s.upload.Metadata)+len(jobMetadata))
+	for key, value := range s.upload.Metadata {
+		attrs.Metadata[key] = value
+	}
+	for key, value := range jobMetadata {
+		attrs.Metadata[key] = value
+	}
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/storage"
+)
+
+func TestContentType(t *testing.T) {
+	tests := map[string]string{
+		"jobs/1/de/subtitles.vtt":  "text/vtt",
+		"jobs/1/de/subtitles.SRT":  "application/x-subrip",
+		"jobs/1/de/video.mp4":      "video/mp4",
+		"jobs/1/hls/master.m3u8":   "application/vnd.apple.mpegurl",
+		"jobs/1/manifest.json":     "application/json",
+		"jobs/1/without-extension": "",
+	}
+	for path, want := range tests {
+		if got := ContentType(path); got != want {
+			t.Errorf("ContentType(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestApplyUploadOptions(t *testing.T) {
+	s := &GCSStorage{}
+	s.SetUploadOptions(UploadOptions{
+		CacheControl: "public, max-age=3600",
+		StorageClass: "NEARLINE",
+		Metadata:     map[string]string{"team": "media", "language": "none"},
+	})
+
+	ctx := WithObjectMetadata(context.Background(), "jobId", "job-1")
+	languageCtx := WithObjectMetadata(ctx, "language", "de")
+
+	var attrs storage.ObjectAttrs
+	s.applyUploadOptions(AsJobOutput(languageCtx), &attrs, "jobs/job-1/de/subtitles.vtt", "")
+	if attrs.ContentType != "text/vtt" || attrs.CacheControl != "public, max-age=3600" || attrs.StorageClass != "NEARLINE" {
+		t.Errorf("unexpected attributes: %+v", attrs)
+	}
+	want := map[string]string{"team": "media", "jobId": "job-1", "language": "de"}
+	if !reflect.DeepEqual(attrs.Metadata, want) {
+		t.Errorf("Metadata = %v, want %v", attrs.Metadata, want)
+	}
+
+	// Deriving a context leaves the metadata of its parent unchanged
+	if got := objectMetadata(ctx); !reflect.DeepEqual(got, map[string]string{"jobId": "job-1"}) {
+		t.Errorf("expected the parent context to keep its metadata, got %v", got)
+	}
+
+	// Uploads that are not job outputs keep the bucket's default class
+	attrs = storage.ObjectAttrs{}
+	s.applyUploadOptions(languageCtx, &attrs, "staging/job-1/video.mp4", "")
+	if attrs.StorageClass != "" || attrs.CacheControl != "public, max-age=3600" {
+		t.Errorf("expected no storage class for a staging upload, got %+v", attrs)
+	}
+
+	attrs = storage.ObjectAttrs{}
+	(&GCSStorage{}).applyUploadOptions(context.Background(), &attrs, "jobs/job-1/status.json", "text/plain")
+	if attrs.ContentType != "text/plain" || attrs.Metadata != nil {
+		t.Errorf("expected the given content type and no metadata, got %+v", attrs)
+	}
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSplitRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int64
+		partSize int64
+		want     []byteRange
+	}{
+		{"exact multiple", 30, 10, []byteRange{{0, 10}, {10, 10}, {20, 10}}},
+		{"short last part", 25, 10, []byteRange{{0, 10}, {10, 10}, {20, 5}}},
+		{"smaller than part", 4, 10, []byteRange{{0, 4}}},
+		{"empty", 0, 10, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitRanges(tt.size, tt.partSize); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTransferOptions_Parallel(t *testing.T) {
+	opts := TransferOptions{Threshold: 100, PartSize: 10, Concurrency: 4}
+	if opts.parallel(99) {
+		t.Error("expected objects below the threshold to use a single stream")
+	}
+	if !opts.parallel(100) {
+		t.Error("expected objects at the threshold to transfer in parts")
+	}
+	if (TransferOptions{}).parallel(1 << 30) {
+		t.Error("expected zero options to disable parallel transfers")
+	}
+	if (TransferOptions{Threshold: 100, PartSize: 10, Concurrency: 1}).parallel(1000) {
+		t.Error("expected a concurrency of 1 to disable parallel transfers")
+	}
+}
+
+func TestForEachPart(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	seen := make(map[int]bool)
+
+	err := forEachPart(context.Background(), 10, 3, func(ctx context.Context, part int) error {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		seen[part] = true
+		mu.Unlock()
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 10 {
+		t.Errorf("expected 10 parts, got %d", len(seen))
+	}
+	if peak > 3 {
+		t.Errorf("expected at most 3 concurrent parts, got %d", peak)
+	}
+}
+
+func TestForEachPart_Error(t *testing.T) {
+	partErr := errors.New("part failed")
+	err := forEachPart(context.Background(), 20, 2, func(ctx context.Context, part int) error {
+		if part == 1 {
+			return partErr
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, partErr) {
+		t.Errorf("expected part error, got %v", err)
+	}
+}
+
+func TestForEachPart_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := forEachPart(ctx, 5, 2, func(ctx context.Context, part int) error {
+		calls++
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no parts to run, got %d", calls)
+	}
+}
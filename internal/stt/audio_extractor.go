@@ -6,13 +6,44 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
+
+	"github.com/sinouw/multilingual-video-processor/internal/video"
 )
 
+// ExtractOptions selects and formats the audio extracted for recognition
+type ExtractOptions struct {
+	Track      int // Audio stream (0-based among audio streams); negative for FFmpeg's default stream
+	Channel    int // 1-based channel of the stream to keep; 0 downmixes all channels
+	SampleRate int // Output sample rate; 0 for DefaultSampleRate
+
+	// Channels keeps this many output channels (e.g. 2 for stereo); 0 for mono
+	Channels int
+	// OutputPath is the WAV file to write; empty for a temporary file
+	OutputPath string
+}
+
 // ExtractAudioFromVideo extracts audio from video file using FFmpeg
+// FFmpeg picks the default audio stream; use ExtractAudioTrack to choose one.
 func ExtractAudioFromVideo(ctx context.Context, videoPath string) (string, error) {
-	slog.Info("Extracting audio from video", "videoPath", videoPath)
+	return ExtractAudio(ctx, videoPath, ExtractOptions{Track: -1})
+}
+
+// ExtractAudioTrack extracts the given audio stream (0-based among audio streams) from a video file
+func ExtractAudioTrack(ctx context.Context, videoPath string, track int) (string, error) {
+	if track < 0 {
+		return "", fmt.Errorf("invalid audio track: %d", track)
+	}
+	return ExtractAudio(ctx, videoPath, ExtractOptions{Track: track})
+}
+
+// ExtractAudio converts one audio stream to LINEAR16 WAV (mono unless Channels is set), optionally keeping a single channel
+func ExtractAudio(ctx context.Context, videoPath string, opts ExtractOptions) (string, error) {
+	if opts.Channel < 0 {
+		return "", fmt.Errorf("invalid audio channel: %d", opts.Channel)
+	}
+	slog.Info("Extracting audio from video", "videoPath", videoPath, "track", opts.Track, "channel", opts.Channel)
 
 	// Check context cancellation before starting
 	select {
@@ -22,20 +53,12 @@ func ExtractAudioFromVideo(ctx context.Context, videoPath string) (string, error
 	}
 
 	// Create temporary audio file
-	tmpDir := os.TempDir()
-	audioPath := filepath.Join(tmpDir, fmt.Sprintf("audio_%d.wav", os.Getpid()))
-
-	// Use FFmpeg command to extract audio
-	// ffmpeg -i input.mp4 -vn -acodec pcm_s16le -ar 16000 -ac 1 output.wav
-	cmd := exec.CommandContext(ctx, "ffmpeg",
-		"-i", videoPath,
-		"-vn",                  // No video
-		"-acodec", "pcm_s16le", // Audio codec
-		"-ar", "16000", // Sample rate
-		"-ac", "1", // Mono
-		"-y", // Overwrite output file
-		audioPath,
-	)
+	audioPath := opts.OutputPath
+	if audioPath == "" {
+		audioPath = filepath.Join(os.TempDir(), fmt.Sprintf("audio_%d.wav", os.Getpid()))
+	}
+
+	cmd := video.FFmpegCommand(ctx, extractAudioArgs(videoPath, audioPath, opts)...)
 
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
@@ -52,3 +75,32 @@ func ExtractAudioFromVideo(ctx context.Context, videoPath string) (string, error
 	slog.Info("Audio extracted successfully", "audioPath", audioPath)
 	return audioPath, nil
 }
+
+// extractAudioArgs builds the FFmpeg arguments for ExtractAudio
+// ffmpeg -i input.mp4 [-map 0:a:N] -vn [-af pan=mono|c0=cK] -acodec pcm_s16le -ar 16000 -ac 1 output.wav
+func extractAudioArgs(videoPath string, audioPath string, opts ExtractOptions) []string {
+	sampleRate := opts.SampleRate
+	if sampleRate == 0 {
+		sampleRate = DefaultSampleRate
+	}
+	channels := opts.Channels
+	if channels == 0 || opts.Channel > 0 {
+		channels = 1
+	}
+
+	args := []string{"-i", videoPath}
+	if opts.Track >= 0 {
+		args = append(args, "-map", fmt.Sprintf("0:a:%d", opts.Track)) // Selected audio stream
+	}
+	args = append(args, "-vn") // No video
+	if opts.Channel > 0 {
+		args = append(args, "-af", fmt.Sprintf("pan=mono|c0=c%d", opts.Channel-1)) // Keep one channel instead of downmixing
+	}
+	return append(args,
+		"-acodec", "pcm_s16le", // Audio codec
+		"-ar", strconv.Itoa(sampleRate), // Sample rate
+		"-ac", strconv.Itoa(channels), // Mono unless more channels are requested
+		"-y", // Overwrite output file
+		audioPath,
+	)
+}
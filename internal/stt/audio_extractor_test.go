@@ -0,0 +1,45 @@
+package stt
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestExtractAudioArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ExtractOptions
+		want []string
+	}{
+		{
+			"default stream downmixed",
+			ExtractOptions{Track: -1},
+			[]string{"-i", "in.mp4", "-vn", "-acodec", "pcm_s16le", "-ar", "16000", "-ac", "1", "-y", "out.wav"},
+		},
+		{
+			"second stream, right channel at 48kHz",
+			ExtractOptions{Track: 1, Channel: 2, SampleRate: 48000},
+			[]string{"-i", "in.mp4", "-map", "0:a:1", "-vn", "-af", "pan=mono|c0=c1", "-acodec", "pcm_s16le", "-ar", "48000", "-ac", "1", "-y", "out.wav"},
+		},
+		{
+			"stereo at 44.1kHz",
+			ExtractOptions{Track: 0, SampleRate: 44100, Channels: 2},
+			[]string{"-i", "in.mp4", "-map", "0:a:0", "-vn", "-acodec", "pcm_s16le", "-ar", "44100", "-ac", "2", "-y", "out.wav"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractAudioArgs("in.mp4", "out.wav", tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExtractAudio_InvalidChannel(t *testing.T) {
+	if _, err := ExtractAudio(context.Background(), "in.mp4", ExtractOptions{Track: -1, Channel: -1}); err == nil {
+		t.Error("expected error for negative channel")
+	}
+}
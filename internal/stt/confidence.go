@@ -0,0 +1,35 @@
+package stt
+
+import (
+	"fmt"
+)
+
+// LowConfidenceError is returned when a transcript falls below the required confidence
+type LowConfidenceError struct {
+	Confidence float64
+	Minimum    float64
+}
+
+func (e *LowConfidenceError) Error() string {
+	return fmt.Sprintf("transcription confidence %.2f is below the required minimum %.2f", e.Confidence, e.Minimum)
+}
+
+// CheckConfidence evaluates a transcript's confidence against the configured thresholds
+// Returns warnings when confidence is below warnThreshold, and a LowConfidenceError when
+// it is below minConfidence. A threshold of 0 disables the corresponding check, and
+// transcripts without confidence information are never rejected.
+func CheckConfidence(resp *SpeechToTextResponse, warnThreshold float64, minConfidence float64) ([]string, error) {
+	if resp == nil || resp.Confidence <= 0 {
+		return nil, nil
+	}
+
+	if minConfidence > 0 && resp.Confidence < minConfidence {
+		return nil, &LowConfidenceError{Confidence: resp.Confidence, Minimum: minConfidence}
+	}
+
+	var warnings []string
+	if warnThreshold > 0 && resp.Confidence < warnThreshold {
+		warnings = append(warnings, fmt.Sprintf("low transcription confidence (%.2f < %.2f): dubbed output may be based on an inaccurate transcript", resp.Confidence, warnThreshold))
+	}
+	return warnings, nil
+}
@@ -0,0 +1,39 @@
+package stt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckConfidence(t *testing.T) {
+	tests := []struct {
+		name         string
+		confidence   float64
+		warn         float64
+		min          float64
+		wantWarnings int
+		wantErr      bool
+	}{
+		{"high confidence", 0.9, 0.6, 0.3, 0, false},
+		{"low confidence warns", 0.5, 0.6, 0.3, 1, false},
+		{"below minimum fails", 0.2, 0.6, 0.3, 0, true},
+		{"minimum disabled", 0.2, 0.6, 0, 1, false},
+		{"unknown confidence", 0, 0.6, 0.3, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := CheckConfidence(&SpeechToTextResponse{Text: "x", Confidence: tt.confidence}, tt.warn, tt.min)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckConfidence() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("expected %d warnings, got %v", tt.wantWarnings, warnings)
+			}
+			var lowErr *LowConfidenceError
+			if tt.wantErr && !errors.As(err, &lowErr) {
+				t.Errorf("expected LowConfidenceError, got %T", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package stt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+const (
+	// pitchFrameDuration is the pitch analysis window in seconds, long enough for two periods of a low voice
+	pitchFrameDuration = 0.04
+
+	// Fundamental frequency range searched, covering adult and children's voices
+	pitchMinHz = 60.0
+	pitchMaxHz = 400.0
+
+	// pitchMinCorrelation is the normalized autocorrelation above which a frame counts as voiced
+	pitchMinCorrelation = 0.6
+
+	// pitchOctaveTolerance keeps the shortest period whose correlation is this close to the best one,
+	// so a multiple of the period is not mistaken for it (an octave error)
+	pitchOctaveTolerance = 0.9
+
+	// pitchMaxFrames bounds the analysis to about a minute of voiced speech
+	pitchMaxFrames = 1500
+
+	// pitchMinFrames is how many voiced frames an estimate needs (about a second of voiced speech)
+	pitchMinFrames = 25
+
+	// GenderPitchThresholdHz separates typical male (about 85-155 Hz) from female (about 165-255 Hz) voices
+	GenderPitchThresholdHz = 160.0
+)
+
+// ErrNoVoicedSpeech is returned when the audio has too little voiced speech to estimate a pitch
+var ErrNoVoicedSpeech = errors.New("not enough voiced speech to estimate the speaker's pitch")
+
+// Speaker genders estimated from the voice pitch
+const (
+	SpeakerMale   = "male"
+	SpeakerFemale = "female"
+)
+
+// SpeakerPitch is the estimated voice pitch of the main speaker in a recording
+type SpeakerPitch struct {
+	MedianHz float64 // Median fundamental frequency of the voiced frames
+	Frames   int     // Voiced frames measured
+}
+
+// Gender returns the gender whose typical pitch range the speaker's median pitch falls in
+func (p *SpeakerPitch) Gender() string {
+	if p.MedianHz < GenderPitchThresholdHz {
+		return SpeakerMale
+	}
+	return SpeakerFemale
+}
+
+// EstimatePitch measures the fundamental frequency of the speech frames of a 16-bit PCM WAV file by autocorrelation
+// Only the first channel is analysed. The median over the voiced frames reflects the dominant speaker.
+func EstimatePitch(audioPath string) (*SpeakerPitch, error) {
+	file, err := os.Open(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	sampleRate, channels, err := readWAVHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	frameLength := int(float64(sampleRate) * pitchFrameDuration)
+	minLag, maxLag := int(float64(sampleRate)/pitchMaxHz), int(float64(sampleRate)/pitchMinHz)
+	if minLag < 1 || maxLag >= frameLength {
+		return nil, fmt.Errorf("unsupported WAV sample rate for pitch estimation: %d", sampleRate)
+	}
+
+	interleaved := make([]int16, frameLength*channels)
+	buf := make([]byte, len(interleaved)*2)
+	frame := make([]float64, frameLength)
+	var pitches []float64
+	for len(pitches) < pitchMaxFrames {
+		n, err := readSamples(reader, buf, interleaved)
+		if n == len(interleaved) && analyzeFrame(interleaved).speech {
+			for i := range frame {
+				frame[i] = float64(interleaved[i*channels])
+			}
+			if hz, ok := framePitch(frame, minLag, maxLag, sampleRate); ok {
+				pitches = append(pitches, hz)
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to read audio samples: %w", err)
+		}
+	}
+
+	if len(pitches) < pitchMinFrames {
+		return nil, ErrNoVoicedSpeech
+	}
+	sort.Float64s(pitches)
+	return &SpeakerPitch{MedianHz: pitches[len(pitches)/2], Frames: len(pitches)}, nil
+}
+
+// framePitch returns the fundamental frequency of a frame, or false when the frame is not voiced
+func framePitch(frame []float64, minLag int, maxLag int, sampleRate int) (float64, bool) {
+	correlations := make([]float64, maxLag+1)
+	best := 0.0
+	for lag := minLag; lag <= maxLag; lag++ {
+		var sum, energyA, energyB float64
+		for i := 0; i+lag < len(frame); i++ {
+			sum += frame[i] * frame[i+lag]
+			energyA += frame[i] * frame[i]
+			energyB += frame[i+lag] * frame[i+lag]
+		}
+		if energyA == 0 || energyB == 0 {
+			continue
+		}
+		correlations[lag] = sum / math.Sqrt(energyA*energyB)
+		best = max(best, correlations[lag])
+	}
+	if best < pitchMinCorrelation {
+		return 0, false
+	}
+
+	// The shortest period close to the best correlation, at a local peak
+	for lag := minLag; lag <= maxLag; lag++ {
+		if correlations[lag] >= best*pitchOctaveTolerance && (lag == maxLag || correlations[lag] >= correlations[lag+1]) {
+			return float64(sampleRate) / float64(lag), true
+		}
+	}
+	return 0, false
+}
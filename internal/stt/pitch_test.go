@@ -0,0 +1,72 @@
+package stt
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// voiced approximates a voiced sound: a fundamental with decaying harmonics
+func voiced(sampleRate int, seconds float64, f0 float64) []int16 {
+	samples := make([]int16, int(float64(sampleRate)*seconds))
+	for i := range samples {
+		var value float64
+		for harmonic := 1; harmonic <= 5; harmonic++ {
+			value += math.Sin(2*math.Pi*f0*float64(harmonic)*float64(i)/float64(sampleRate)) / float64(harmonic)
+		}
+		samples[i] = int16(0.3 * 32767 * value / 2.3)
+	}
+	return samples
+}
+
+func TestEstimatePitch(t *testing.T) {
+	const sampleRate = 16000
+	tests := []struct {
+		name    string
+		samples []int16
+		wantHz  float64
+		gender  string
+	}{
+		{"low tone", tone(sampleRate, 2, 120, 0.3), 120, SpeakerMale},
+		{"high tone", tone(sampleRate, 2, 220, 0.3), 220, SpeakerFemale},
+		{"low voice with harmonics", voiced(sampleRate, 2, 110), 110, SpeakerMale},
+		{"high voice with harmonics", voiced(sampleRate, 2, 210), 210, SpeakerFemale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pitch, err := EstimatePitch(writeTestWAV(t, sampleRate, tt.samples))
+			if err != nil {
+				t.Fatalf("EstimatePitch() error = %v", err)
+			}
+			if math.Abs(pitch.MedianHz-tt.wantHz) > tt.wantHz*0.05 {
+				t.Errorf("MedianHz = %.1f, want about %.0f", pitch.MedianHz, tt.wantHz)
+			}
+			if got := pitch.Gender(); got != tt.gender {
+				t.Errorf("Gender() = %q, want %q", got, tt.gender)
+			}
+		})
+	}
+}
+
+func TestEstimatePitch_NoVoicedSpeech(t *testing.T) {
+	const sampleRate = 16000
+	random := rand.New(rand.NewSource(1))
+	noise := make([]int16, sampleRate*2)
+	for i := range noise {
+		noise[i] = int16(random.Intn(2000) - 1000)
+	}
+
+	for name, samples := range map[string][]int16{
+		"silence": make([]int16, sampleRate*2),
+		"noise":   noise,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := EstimatePitch(writeTestWAV(t, sampleRate, samples))
+			if !errors.Is(err, ErrNoVoicedSpeech) {
+				t.Errorf("EstimatePitch() error = %v, want ErrNoVoicedSpeech", err)
+			}
+		})
+	}
+}
@@ -12,37 +12,68 @@ import (
 	"google.golang.org/api/option"
 )
 
+// ProviderName identifies this speech-to-text backend in capabilities and logs
+const ProviderName = "google-speech"
+
 // SpeechToTextResponse represents the response from Google Cloud Speech-to-Text API
 type SpeechToTextResponse struct {
-	Text     string `json:"text"`
-	Language string `json:"language,omitempty"` // Detected language code
+	Text       string  `json:"text"`
+	Language   string  `json:"language,omitempty"`   // Detected language code
+	Confidence float64 `json:"confidence,omitempty"` // Average recognition confidence (0-1), 0 when unavailable
+}
+
+// DefaultSampleRate is the sample rate audio is extracted at for recognition
+const DefaultSampleRate = 16000
+
+// Options controls optional recognition features
+type Options struct {
+	LanguageHint             string   // Optional language code hint (e.g., "fr", "en"). If empty, auto-detect.
+	ProfanityFilter          bool     // Ask the API to mask profanities (e.g., "f***")
+	Model                    string   // Recognition model (e.g., "latest_long", "video"); empty for the API default
+	AutomaticPunctuation     bool     // Insert punctuation into the transcript
+	AlternativeLanguageCodes []string // Other possible languages, used for auto-detection
+	SampleRate               int      // Sample rate of the LINEAR16 audio; 0 for DefaultSampleRate
+	HintPhrases              []string // Vocabulary the recognizer should favor (speech adaptation)
+}
+
+// RecognizeFunc transcribes an audio file in place of the Speech-to-Text API
+type RecognizeFunc func(ctx context.Context, audioPath string, opts Options) (*SpeechToTextResponse, error)
+
+// recognizer replaces the Speech-to-Text API when set
+var recognizer RecognizeFunc
+
+// SetRecognizer routes recognition to fn instead of Google Cloud (e.g., mock providers); nil restores the API
+func SetRecognizer(fn RecognizeFunc) {
+	recognizer = fn
+}
+
+// endpoint replaces the Speech-to-Text API endpoint when set
+var endpoint string
+
+// SetEndpoint sends recognition requests to a REST endpoint such as a fake server (e.g., "http://localhost:9090")
+// Plain http:// endpoints are called without credentials; empty restores the API endpoint.
+func SetEndpoint(url string) {
+	endpoint = url
 }
 
 // SpeechToText converts audio to text using Google Cloud Speech-to-Text API
 // languageHint: Optional language code hint (e.g., "fr", "en"). If empty, Google Cloud Speech-to-Text will auto-detect.
 func SpeechToText(ctx context.Context, audioPath string, languageHint string) (*SpeechToTextResponse, error) {
+	return SpeechToTextWithOptions(ctx, audioPath, Options{LanguageHint: languageHint})
+}
+
+// SpeechToTextWithOptions converts audio to text using Google Cloud Speech-to-Text API with optional features
+func SpeechToTextWithOptions(ctx context.Context, audioPath string, opts Options) (*SpeechToTextResponse, error) {
+	languageHint := opts.LanguageHint
 	slog.Info("Converting speech to text", "audioPath", audioPath, "languageHint", languageHint)
+	if recognizer != nil {
+		return recognizer(ctx, audioPath, opts)
+	}
 
 	// Initialize Speech-to-Text client
-	// Use service account from environment or default credentials
-	credentialsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
-	var client *speech.Client
-	var err error
-
-	if credentialsPath != "" {
-		client, err = speech.NewClient(ctx, option.WithCredentialsFile(credentialsPath))
-		if err != nil {
-			slog.Warn("Failed to create client with credentials file, trying default", "error", err)
-			client, err = speech.NewClient(ctx)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create Speech-to-Text client: %w", err)
-			}
-		}
-	} else {
-		client, err = speech.NewClient(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create Speech-to-Text client: %w", err)
-		}
+	client, err := newClient(ctx)
+	if err != nil {
+		return nil, err
 	}
 	defer client.Close()
 
@@ -60,14 +91,8 @@ func SpeechToText(ctx context.Context, audioPath string, languageHint string) (*
 	}
 
 	// Build recognition config
-	config := &speechpb.RecognitionConfig{
-		Encoding:        speechpb.RecognitionConfig_LINEAR16,
-		SampleRateHertz: 16000,
-	}
-
-	// Set language code if hint is provided, otherwise auto-detect
+	config := buildRecognitionConfig(opts)
 	if languageHint != "" {
-		config.LanguageCode = languageHint
 		slog.Info("Using language hint", "language", languageHint)
 	} else {
 		slog.Info("No language hint provided, Google Cloud Speech-to-Text will auto-detect")
@@ -103,15 +128,28 @@ func SpeechToText(ctx context.Context, audioPath string, languageHint string) (*
 
 	// Concatenate all alternative transcripts
 	var fullText strings.Builder
+	var confidenceSum float64
+	confidenceCount := 0
 	for _, result := range resp.Results {
 		if len(result.Alternatives) > 0 {
 			if fullText.Len() > 0 {
 				fullText.WriteString(" ")
 			}
 			fullText.WriteString(result.Alternatives[0].Transcript)
+
+			// The API reports 0 when confidence is not available for a result
+			if confidence := result.Alternatives[0].Confidence; confidence > 0 {
+				confidenceSum += float64(confidence)
+				confidenceCount++
+			}
 		}
 	}
 
+	averageConfidence := 0.0
+	if confidenceCount > 0 {
+		averageConfidence = confidenceSum / float64(confidenceCount)
+	}
+
 	transcribedText := fullText.String()
 	if transcribedText == "" {
 		return nil, fmt.Errorf("no transcribed text found in results")
@@ -136,10 +174,75 @@ func SpeechToText(ctx context.Context, audioPath string, languageHint string) (*
 
 	slog.Info("Speech-to-text completed",
 		"textLength", len(transcribedText),
-		"detectedLanguage", detectedLanguage)
+		"detectedLanguage", detectedLanguage,
+		"confidence", averageConfidence)
 
 	return &SpeechToTextResponse{
-		Text:     transcribedText,
-		Language: detectedLanguage,
+		Text:       transcribedText,
+		Language:   detectedLanguage,
+		Confidence: averageConfidence,
 	}, nil
 }
+
+// newClient creates a Speech-to-Text client for the configured endpoint, using the credentials file if configured
+func newClient(ctx context.Context) (*speech.Client, error) {
+	if endpoint != "" {
+		opts := []option.ClientOption{option.WithEndpoint(endpoint)}
+		if strings.HasPrefix(endpoint, "http://") {
+			opts = append(opts, option.WithoutAuthentication())
+		}
+		client, err := speech.NewRESTClient(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Speech-to-Text client: %w", err)
+		}
+		return client, nil
+	}
+
+	// Use service account from environment or default credentials
+	credentialsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	var client *speech.Client
+	var err error
+
+	if credentialsPath != "" {
+		client, err = speech.NewClient(ctx, option.WithCredentialsFile(credentialsPath))
+		if err != nil {
+			slog.Warn("Failed to create client with credentials file, trying default", "error", err)
+			client, err = speech.NewClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create Speech-to-Text client: %w", err)
+			}
+		}
+	} else {
+		client, err = speech.NewClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Speech-to-Text client: %w", err)
+		}
+	}
+	return client, nil
+}
+
+// buildRecognitionConfig builds the recognition config for LINEAR16 audio from the options
+func buildRecognitionConfig(opts Options) *speechpb.RecognitionConfig {
+	sampleRate := opts.SampleRate
+	if sampleRate == 0 {
+		sampleRate = DefaultSampleRate
+	}
+
+	config := &speechpb.RecognitionConfig{
+		Encoding:                   speechpb.RecognitionConfig_LINEAR16,
+		SampleRateHertz:            int32(sampleRate),
+		ProfanityFilter:            opts.ProfanityFilter,
+		Model:                      opts.Model,
+		EnableAutomaticPunctuation: opts.AutomaticPunctuation,
+		AlternativeLanguageCodes:   opts.AlternativeLanguageCodes,
+	}
+
+	// Set language code if hint is provided, otherwise auto-detect
+	if opts.LanguageHint != "" {
+		config.LanguageCode = opts.LanguageHint
+	}
+	if len(opts.HintPhrases) > 0 {
+		config.SpeechContexts = []*speechpb.SpeechContext{{Phrases: opts.HintPhrases}}
+	}
+	return config
+}
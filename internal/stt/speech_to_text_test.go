@@ -44,3 +44,35 @@ func TestSpeechToText_ContextTimeout(t *testing.T) {
 		t.Error("expected error for timed out context")
 	}
 }
+
+func TestBuildRecognitionConfig(t *testing.T) {
+	config := buildRecognitionConfig(Options{})
+	if config.SampleRateHertz != DefaultSampleRate || config.Model != "" || config.EnableAutomaticPunctuation || config.LanguageCode != "" {
+		t.Errorf("unexpected default config: %+v", config)
+	}
+
+	config = buildRecognitionConfig(Options{
+		LanguageHint:             "en-US",
+		Model:                    "video",
+		AutomaticPunctuation:     true,
+		AlternativeLanguageCodes: []string{"fr-FR", "de-DE"},
+		SampleRate:               48000,
+	})
+	if config.LanguageCode != "en-US" || config.Model != "video" || !config.EnableAutomaticPunctuation {
+		t.Errorf("expected options applied, got %+v", config)
+	}
+	if config.SampleRateHertz != 48000 {
+		t.Errorf("expected sample rate 48000, got %d", config.SampleRateHertz)
+	}
+	if len(config.AlternativeLanguageCodes) != 2 {
+		t.Errorf("expected 2 alternative languages, got %v", config.AlternativeLanguageCodes)
+	}
+	if len(config.SpeechContexts) != 0 {
+		t.Errorf("expected no speech contexts without hint phrases, got %v", config.SpeechContexts)
+	}
+
+	config = buildRecognitionConfig(Options{HintPhrases: []string{"Kubernetes", "Dr. Okonkwo"}})
+	if len(config.SpeechContexts) != 1 || len(config.SpeechContexts[0].Phrases) != 2 {
+		t.Errorf("expected hint phrases in one speech context, got %v", config.SpeechContexts)
+	}
+}
@@ -0,0 +1,214 @@
+package stt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+const (
+	// vadFrameDuration is the analysis window in seconds
+	vadFrameDuration = 0.03
+
+	// vadMinFrameDBFS is the loudness below which a frame counts as silence
+	vadMinFrameDBFS = -45.0
+
+	// Zero-crossing rates (crossings per sample) typical of voiced and unvoiced speech
+	// Low-frequency hum and bass-heavy music fall below the range, broadband noise above it.
+	vadMinZeroCrossingRate = 0.01
+	vadMaxZeroCrossingRate = 0.35
+
+	// Speech changes pitch and loudness from syllable to syllable, while tones, hum and sustained notes do not:
+	// a run of at least vadMinSteadyFrames frames (450 ms) whose zero crossings (a proxy for pitch) and loudness
+	// stay within vadSteadyTolerance of the run's first frame is not speech.
+	vadMinSteadyFrames = 15
+	vadSteadyTolerance = 0.1
+)
+
+// vadFrame holds the features of one analysis frame
+type vadFrame struct {
+	seconds   float64
+	rms       float64
+	crossings int
+	speech    bool
+}
+
+// SpeechActivity summarizes voice activity detection over an audio file
+type SpeechActivity struct {
+	Duration      float64 // Audio duration in seconds
+	SpeechSeconds float64 // Seconds of frames classified as speech
+	SpeechRatio   float64 // SpeechSeconds / Duration
+}
+
+// DetectSpeech runs an energy, zero-crossing and steadiness voice activity check over a 16-bit PCM WAV file
+// It is a cheap pre-check to reject silent, tone or music-only audio before calling the Speech API.
+func DetectSpeech(audioPath string) (*SpeechActivity, error) {
+	file, err := os.Open(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	sampleRate, channels, err := readWAVHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	frameSize := int(float64(sampleRate)*vadFrameDuration) * channels
+	if frameSize <= 0 {
+		return nil, fmt.Errorf("invalid WAV sample rate: %d", sampleRate)
+	}
+
+	var frames []vadFrame
+	frame := make([]int16, frameSize)
+	buf := make([]byte, frameSize*2)
+	frameSeconds := float64(frameSize/channels) / float64(sampleRate)
+	for {
+		n, err := readSamples(reader, buf, frame)
+		if n > 0 {
+			features := analyzeFrame(frame[:n])
+			features.seconds = frameSeconds * float64(n) / float64(frameSize)
+			frames = append(frames, features)
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to read audio samples: %w", err)
+		}
+	}
+	rejectSteadyRuns(frames)
+
+	activity := &SpeechActivity{}
+	for _, frame := range frames {
+		activity.Duration += frame.seconds
+		if frame.speech {
+			activity.SpeechSeconds += frame.seconds
+		}
+	}
+	if activity.Duration > 0 {
+		activity.SpeechRatio = activity.SpeechSeconds / activity.Duration
+	}
+	return activity, nil
+}
+
+// analyzeFrame measures a frame and classifies it as speech when it is loud enough and its zero-crossing rate
+// is in the speech range
+func analyzeFrame(samples []int16) vadFrame {
+	if len(samples) == 0 {
+		return vadFrame{}
+	}
+
+	var sumSquares float64
+	crossings := 0
+	for i, sample := range samples {
+		value := float64(sample) / 32768.0
+		sumSquares += value * value
+		if i > 0 && (sample >= 0) != (samples[i-1] >= 0) {
+			crossings++
+		}
+	}
+
+	features := vadFrame{rms: math.Sqrt(sumSquares / float64(len(samples))), crossings: crossings}
+	if features.rms == 0 || 20*math.Log10(features.rms) < vadMinFrameDBFS {
+		return features
+	}
+
+	zcr := float64(crossings) / float64(len(samples))
+	features.speech = zcr >= vadMinZeroCrossingRate && zcr <= vadMaxZeroCrossingRate
+	return features
+}
+
+// rejectSteadyRuns reclassifies runs of at least vadMinSteadyFrames speech frames with a steady pitch and
+// loudness as not speech
+func rejectSteadyRuns(frames []vadFrame) {
+	start := 0
+	for i := 1; i <= len(frames); i++ {
+		if i < len(frames) && frames[start].speech && frames[i].speech && steadyFrames(frames[start], frames[i]) {
+			continue
+		}
+		if frames[start].speech && i-start >= vadMinSteadyFrames {
+			for j := start; j < i; j++ {
+				frames[j].speech = false
+			}
+		}
+		start = i
+	}
+}
+
+// steadyFrames reports whether frame b has about the zero crossings and loudness of frame a
+// A difference of one crossing is always tolerated, as a frame boundary can split a period.
+func steadyFrames(a vadFrame, b vadFrame) bool {
+	crossingTolerance := max(1, int(vadSteadyTolerance*float64(a.crossings)))
+	crossingDiff := a.crossings - b.crossings
+	if crossingDiff < -crossingTolerance || crossingDiff > crossingTolerance {
+		return false
+	}
+	return math.Abs(a.rms-b.rms) <= vadSteadyTolerance*a.rms
+}
+
+// readWAVHeader parses RIFF chunks up to the data chunk, returning the sample rate and channel count
+// Only 16-bit PCM is supported, which is what audio extraction produces.
+func readWAVHeader(r io.Reader) (int, int, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return 0, 0, fmt.Errorf("failed to read WAV header: %w", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return 0, 0, fmt.Errorf("not a WAV file")
+	}
+
+	sampleRate, channels := 0, 0
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return 0, 0, fmt.Errorf("WAV file has no data chunk: %w", err)
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "fmt ":
+			format := make([]byte, size)
+			if _, err := io.ReadFull(r, format); err != nil || size < 16 {
+				return 0, 0, fmt.Errorf("invalid WAV fmt chunk")
+			}
+			audioFormat := binary.LittleEndian.Uint16(format[0:2])
+			channels = int(binary.LittleEndian.Uint16(format[2:4]))
+			sampleRate = int(binary.LittleEndian.Uint32(format[4:8]))
+			bitsPerSample := binary.LittleEndian.Uint16(format[14:16])
+			if audioFormat != 1 || bitsPerSample != 16 || channels == 0 {
+				return 0, 0, fmt.Errorf("unsupported WAV format: only 16-bit PCM is supported")
+			}
+		case "data":
+			if sampleRate == 0 {
+				return 0, 0, fmt.Errorf("WAV data chunk before fmt chunk")
+			}
+			return sampleRate, channels, nil
+		default:
+			// Skip unknown chunks (e.g., LIST metadata); chunks are padded to an even size
+			if _, err := io.CopyN(io.Discard, r, size+size%2); err != nil {
+				return 0, 0, fmt.Errorf("failed to read WAV chunk %q: %w", id, err)
+			}
+		}
+	}
+}
+
+// readSamples fills samples with little-endian 16-bit values using buf (twice the length of samples)
+// Returns how many samples were read.
+func readSamples(r io.Reader, buf []byte, samples []int16) (int, error) {
+	n, err := io.ReadFull(r, buf)
+	count := n / 2
+	for i := 0; i < count; i++ {
+		samples[i] = int16(binary.LittleEndian.Uint16(buf[i*2:]))
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return count, err
+}
@@ -0,0 +1,120 @@
+package stt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestWAV writes 16-bit mono PCM samples to a WAV file
+func writeTestWAV(t *testing.T, sampleRate int, samples []int16) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	dataSize := uint32(len(samples) * 2)
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // Mono
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(2))
+	binary.Write(&buf, binary.LittleEndian, uint16(16))
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	binary.Write(&buf, binary.LittleEndian, samples)
+
+	path := filepath.Join(t.TempDir(), "audio.wav")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("failed to write WAV: %v", err)
+	}
+	return path
+}
+
+func tone(sampleRate int, seconds float64, frequency float64, amplitude float64) []int16 {
+	samples := make([]int16, int(float64(sampleRate)*seconds))
+	for i := range samples {
+		samples[i] = int16(amplitude * 32767 * math.Sin(2*math.Pi*frequency*float64(i)/float64(sampleRate)))
+	}
+	return samples
+}
+
+// speechLike synthesizes syllables of a voice with harmonics, each gliding in pitch and swelling in loudness,
+// separated by short pauses
+func speechLike(sampleRate int, syllables int) []int16 {
+	var samples []int16
+	for s := 0; s < syllables; s++ {
+		length := int(float64(sampleRate) * 0.2)
+		f0 := 110.0 + float64(s%5)*20
+		phase := 0.0
+		for i := 0; i < length; i++ {
+			progress := float64(i) / float64(length)
+			phase += 2 * math.Pi * f0 * (1 + 0.3*progress) / float64(sampleRate)
+			envelope := 0.1 + 0.9*math.Sin(math.Pi*progress)
+			value := 0.0
+			for harmonic := 1.0; harmonic <= 8; harmonic++ {
+				value += math.Sin(harmonic*phase) / harmonic
+			}
+			samples = append(samples, int16(0.2*envelope*value*32767))
+		}
+		samples = append(samples, make([]int16, sampleRate/20)...)
+	}
+	return samples
+}
+
+func TestDetectSpeech(t *testing.T) {
+	const sampleRate = 16000
+	random := rand.New(rand.NewSource(1))
+	noise := make([]int16, sampleRate)
+	for i := range noise {
+		noise[i] = int16(random.Intn(65536) - 32768)
+	}
+
+	tests := []struct {
+		name     string
+		samples  []int16
+		minRatio float64
+		maxRatio float64
+	}{
+		{"silence", make([]int16, sampleRate), 0, 0},
+		{"low hum", tone(sampleRate, 1, 50, 0.5), 0, 0.05},
+		{"white noise", noise, 0, 0.05},
+		{"steady 300 Hz tone", tone(sampleRate, 1, 300, 0.3), 0, 0.05},
+		{"steady 130 Hz tone", tone(sampleRate, 1, 130, 0.3), 0, 0.05},
+		{"speech-like", speechLike(sampleRate, 8), 0.7, 1},
+		{"half speech", append(speechLike(sampleRate, 4), make([]int16, sampleRate)...), 0.3, 0.55},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activity, err := DetectSpeech(writeTestWAV(t, sampleRate, tt.samples))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if activity.SpeechRatio < tt.minRatio || activity.SpeechRatio > tt.maxRatio {
+				t.Errorf("expected speech ratio in [%.2f, %.2f], got %.3f", tt.minRatio, tt.maxRatio, activity.SpeechRatio)
+			}
+			if math.Abs(activity.Duration-float64(len(tt.samples))/sampleRate) > 0.001 {
+				t.Errorf("expected duration %.3f, got %.3f", float64(len(tt.samples))/sampleRate, activity.Duration)
+			}
+		})
+	}
+}
+
+func TestDetectSpeech_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audio.wav")
+	os.WriteFile(path, []byte("not a wav file"), 0644)
+
+	if _, err := DetectSpeech(path); err == nil {
+		t.Error("expected error for non-WAV file")
+	}
+	if _, err := DetectSpeech("/nonexistent/audio.wav"); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
@@ -0,0 +1,52 @@
+package subtitles
+
+import (
+	"strings"
+	"unicode"
+)
+
+// RightToLeftMark sets the base direction of a line that does not start with right-to-left text
+const RightToLeftMark = "\u200f"
+
+// MarkRTL prefixes a right-to-left mark to every line of text in a right-to-left language whose first strong
+// character is not right-to-left, e.g. a line opening with a brand name
+// Players and editors take a line's direction from its first strong character, so such a line would otherwise be
+// laid out left to right, with its punctuation on the wrong side. Lines starting with Arabic or Hebrew letters,
+// which are already laid out right to left, are left as they are.
+func MarkRTL(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" && !startsRTL(line) {
+			lines[i] = RightToLeftMark + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MarkRTLCues applies MarkRTL to the text of every cue
+func MarkRTLCues(cues []Cue) []Cue {
+	marked := make([]Cue, len(cues))
+	for i, cue := range cues {
+		cue.Text = MarkRTL(cue.Text)
+		marked[i] = cue
+	}
+	return marked
+}
+
+// startsRTL reports whether the first strong directional character of line is right to left
+func startsRTL(line string) bool {
+	for _, r := range line {
+		switch {
+		case isRTLRune(r) || r == '\u200f':
+			return true
+		case unicode.IsLetter(r) || r == '\u200e':
+			return false
+		}
+	}
+	return false
+}
+
+// isRTLRune reports whether r is a letter of a right-to-left script
+func isRTLRune(r rune) bool {
+	return unicode.In(r, unicode.Arabic, unicode.Hebrew, unicode.Syriac, unicode.Thaana, unicode.Nko)
+}
@@ -0,0 +1,26 @@
+package subtitles
+
+import "testing"
+
+func TestMarkRTL(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"arabic line", "مرحبا بكم.", "مرحبا بكم."},
+		{"name first", "Google هي شركة.", RightToLeftMark + "Google هي شركة."},
+		{"number first", "2024 كان عاما جيدا.", "2024 كان عاما جيدا."},
+		{"per line", "مرحبا\niPhone جديد", "مرحبا\n" + RightToLeftMark + "iPhone جديد"},
+		{"already marked", RightToLeftMark + "Google هي شركة.", RightToLeftMark + "Google هي شركة."},
+		{"empty line", "مرحبا\n\nشكرا", "مرحبا\n\nشكرا"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarkRTL(tt.text); got != tt.want {
+				t.Errorf("MarkRTL(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package subtitles
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// Punctuation that must not start a line in Chinese and Japanese, and that must not end one
+const (
+	noLineStart = "、。，．！？：；」』）】〕〉》ー…・っゃゅょッャュョ.,!?:;)"
+	noLineEnd   = "「『（【〔〈《("
+)
+
+// token is an unbreakable piece of cue text: a word, or a character in Chinese and Japanese
+type token struct {
+	text  string
+	space bool // Separated from the previous token by a space
+}
+
+// Shift moves cues by offset seconds, dropping cues moved entirely outside the video
+// Cues are clamped to [0, duration]; a zero duration leaves the end of the video unbounded.
+func Shift(cues []Cue, offset float64, duration float64) []Cue {
+	if offset == 0 {
+		return cues
+	}
+	shifted := make([]Cue, 0, len(cues))
+	for _, cue := range cues {
+		cue.Start += offset
+		cue.End += offset
+		if cue.End <= 0 || (duration > 0 && cue.Start >= duration) {
+			continue
+		}
+		cue.Start = max(cue.Start, 0)
+		if duration > 0 {
+			cue.End = min(cue.End, duration)
+		}
+		shifted = append(shifted, cue)
+	}
+	return shifted
+}
+
+// Layout breaks the text of cues into balanced lines and splits cues holding more than profile's lines,
+// then extends cues shown too briefly to be read into the gaps around them
+// Chinese and Japanese break between characters, except before closing or after opening punctuation; other
+// languages break between words. In right-to-left languages, embedded left-to-right words (names, numbers)
+// are kept on one line. duration bounds the last cue; zero leaves it unbounded.
+func Layout(cues []Cue, language string, profile models.SubtitleProfile, duration float64) []Cue {
+	var laidOut []Cue
+	for _, cue := range cues {
+		tokens := tokenize(cue.Text)
+		if len(tokens) == 0 {
+			continue
+		}
+		if models.IsRTLLanguage(language) {
+			tokens = joinLTRRuns(tokens, profile.MaxLineChars)
+		}
+		laidOut = append(laidOut, splitCue(cue, tokens, profile)...)
+	}
+	retime(laidOut, profile, duration)
+	return laidOut
+}
+
+// splitCue lays out the tokens of a cue in cues of at most profile.MaxLines lines, sharing its time by text width
+func splitCue(cue Cue, tokens []token, profile models.SubtitleProfile) []Cue {
+	lines := wrap(tokens, profile.MaxLineChars)
+	var groups [][]token
+	for i := 0; i < len(lines); i += profile.MaxLines {
+		var group []token
+		for _, line := range lines[i:min(i+profile.MaxLines, len(lines))] {
+			group = append(group, line...)
+		}
+		groups = append(groups, group)
+	}
+
+	total := 0
+	for _, group := range groups {
+		total += tokensWidth(group)
+	}
+	cues := make([]Cue, len(groups))
+	position := cue.Start
+	for i, group := range groups {
+		length := (cue.End - cue.Start) * float64(tokensWidth(group)) / float64(total)
+		cues[i] = Cue{Start: position, End: position + length, Text: balance(group, profile.MaxLineChars)}
+		position += length
+	}
+	cues[len(cues)-1].End = cue.End
+	return cues
+}
+
+// balance renders tokens in as few lines as fit maxWidth, narrowing the lines while their number stays the same
+// so that two lines have similar lengths rather than a long line and a short one
+func balance(tokens []token, maxWidth int) string {
+	lines := wrap(tokens, maxWidth)
+	for width := maxWidth - 1; width > 0 && len(lines) > 1; width-- {
+		narrower := wrap(tokens, width)
+		if len(narrower) > len(lines) {
+			break
+		}
+		lines = narrower
+	}
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = joinTokens(line)
+	}
+	return strings.Join(texts, "\n")
+}
+
+// wrap fills lines of at most width greedily; a token wider than width gets a line of its own
+func wrap(tokens []token, width int) [][]token {
+	var lines [][]token
+	var line []token
+	lineWidth := 0
+	for _, tok := range tokens {
+		tokWidth := textWidth(tok.text)
+		if len(line) > 0 && tok.space {
+			tokWidth++
+		}
+		if len(line) > 0 && lineWidth+tokWidth > width {
+			lines = append(lines, line)
+			line, lineWidth = nil, 0
+			tokWidth = textWidth(tok.text)
+		}
+		line = append(line, tok)
+		lineWidth += tokWidth
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// retime extends cues shorter than the profile's minimum duration or reading speed allows, first into the gap
+// before the next cue, then into the gap after the previous one; cues never overlap
+func retime(cues []Cue, profile models.SubtitleProfile, duration float64) {
+	for i := range cues {
+		required := profile.MinDuration
+		if profile.MaxCPS > 0 {
+			required = max(required, float64(textWidth(strings.ReplaceAll(cues[i].Text, "\n", " ")))/profile.MaxCPS)
+		}
+		if cues[i].End-cues[i].Start >= required {
+			continue
+		}
+
+		limit := math.Inf(1)
+		if duration > 0 {
+			limit = duration
+		}
+		if i+1 < len(cues) {
+			limit = min(limit, cues[i+1].Start)
+		}
+		cues[i].End = max(cues[i].End, min(cues[i].Start+required, limit))
+
+		earliest := 0.0
+		if i > 0 {
+			earliest = cues[i-1].End
+		}
+		cues[i].Start = min(cues[i].Start, max(earliest, cues[i].End-required))
+	}
+}
+
+// tokenize splits text into words, and Chinese and Japanese text into characters with their punctuation attached
+func tokenize(text string) []token {
+	var tokens []token
+	for _, field := range strings.Fields(text) {
+		space := len(tokens) > 0
+		emit := func(text string) {
+			tokens = append(tokens, token{text: text, space: space})
+			space = false
+		}
+
+		var run []rune
+		for _, r := range field {
+			switch {
+			case len(run) == 0 && !space && len(tokens) > 0 && strings.ContainsRune(noLineStart, r):
+				tokens[len(tokens)-1].text += string(r)
+			case isIdeographic(r):
+				if len(run) > 0 && strings.ContainsFunc(string(run), func(r rune) bool { return !strings.ContainsRune(noLineEnd, r) }) {
+					emit(string(run))
+					run = nil
+				}
+				emit(string(append(run, r)))
+				run = nil
+			default:
+				run = append(run, r)
+			}
+		}
+		if len(run) > 0 {
+			emit(string(run))
+		}
+	}
+	return tokens
+}
+
+// joinLTRRuns merges consecutive words without right-to-left letters, up to maxWidth, so an embedded
+// left-to-right phrase is not broken over lines, which reorders it visually
+func joinLTRRuns(tokens []token, maxWidth int) []token {
+	joined := []token{tokens[0]}
+	for _, tok := range tokens[1:] {
+		last := &joined[len(joined)-1]
+		if tok.space && isLTRWord(last.text) && isLTRWord(tok.text) && textWidth(last.text)+1+textWidth(tok.text) <= maxWidth {
+			last.text += " " + tok.text
+			continue
+		}
+		joined = append(joined, tok)
+	}
+	return joined
+}
+
+// isLTRWord reports whether a word has letters or digits and none of them is written right to left
+func isLTRWord(word string) bool {
+	hasAlphanumeric := false
+	for _, r := range word {
+		if isRTLRune(r) {
+			return false
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			hasAlphanumeric = true
+		}
+	}
+	return hasAlphanumeric
+}
+
+func joinTokens(tokens []token) string {
+	var b strings.Builder
+	for i, tok := range tokens {
+		if i > 0 && tok.space {
+			b.WriteByte(' ')
+		}
+		b.WriteString(tok.text)
+	}
+	return b.String()
+}
+
+func tokensWidth(tokens []token) int {
+	return textWidth(joinTokens(tokens))
+}
+
+// textWidth measures text in columns: full-width characters count two, combining marks none
+func textWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case isWide(r):
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+// isIdeographic reports whether r is a Chinese or Japanese character, between which lines may break
+func isIdeographic(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// isWide reports whether r is displayed full-width
+func isWide(r rune) bool {
+	return isIdeographic(r) || unicode.Is(unicode.Hangul, r) ||
+		(r >= 0x3000 && r <= 0x303F) || // CJK symbols and punctuation
+		(r >= 0xFF01 && r <= 0xFF60) // Full-width forms
+}
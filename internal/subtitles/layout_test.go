@@ -0,0 +1,127 @@
+package subtitles
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestShift(t *testing.T) {
+	cues := []Cue{
+		{Start: 0, End: 1, Text: "gone"},
+		{Start: 1, End: 3, Text: "clamped"},
+		{Start: 4, End: 6, Text: "moved"},
+	}
+
+	got := Shift(cues, -1.5, 4)
+	want := []Cue{
+		{Start: 0, End: 1.5, Text: "clamped"},
+		{Start: 2.5, End: 4, Text: "moved"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Shift() = %+v, want %+v", got, want)
+	}
+	if got := Shift(cues, 0, 4); !reflect.DeepEqual(got, cues) {
+		t.Errorf("expected a zero offset to keep cues, got %+v", got)
+	}
+}
+
+func TestLayout_BreaksLongCues(t *testing.T) {
+	profile := models.SubtitleProfile{MaxLineChars: 20, MaxLines: 2, MaxCPS: 100}
+	text := "The quick brown fox jumps over the lazy dog and keeps running far away"
+	cues := Layout([]Cue{{Start: 0, End: 10, Text: text}}, "en", profile, 10)
+
+	if len(cues) != 2 {
+		t.Fatalf("expected the cue to be split in two, got %+v", cues)
+	}
+	var words []string
+	for _, cue := range cues {
+		lines := strings.Split(cue.Text, "\n")
+		if len(lines) > 2 {
+			t.Errorf("cue %q has more than 2 lines", cue.Text)
+		}
+		for _, line := range lines {
+			if len(line) > 20 {
+				t.Errorf("line %q is longer than 20 characters", line)
+			}
+		}
+		words = append(words, strings.Fields(cue.Text)...)
+	}
+	if strings.Join(words, " ") != text {
+		t.Errorf("expected every word in order, got %q", strings.Join(words, " "))
+	}
+	if cues[0].Start != 0 || cues[1].End != 10 || cues[0].End != cues[1].Start {
+		t.Errorf("expected the cue's time to be shared, got %+v", cues)
+	}
+}
+
+func TestLayout_BalancesLines(t *testing.T) {
+	profile := models.SubtitleProfile{MaxLineChars: 42, MaxLines: 2}
+	cues := Layout([]Cue{{Start: 0, End: 5, Text: "This sentence is a little too long to fit on one line"}}, "en", profile, 0)
+
+	want := "This sentence is a little\ntoo long to fit on one line"
+	if len(cues) != 1 || cues[0].Text != want {
+		t.Errorf("expected balanced lines %q, got %+v", want, cues)
+	}
+}
+
+func TestLayout_CJK(t *testing.T) {
+	profile := models.SubtitleProfile{MaxLineChars: 16, MaxLines: 2}
+	cues := Layout([]Cue{{Start: 0, End: 5, Text: "今日はとても良い天気ですね。「散歩」に行きましょう。"}}, "ja", profile, 0)
+
+	if len(cues) != 2 {
+		t.Fatalf("expected full-width characters to count double, got %+v", cues)
+	}
+	for _, cue := range cues {
+		for _, line := range strings.Split(cue.Text, "\n") {
+			if textWidth(line) > 16 {
+				t.Errorf("line %q is wider than 16 columns", line)
+			}
+			if first := []rune(line)[0]; strings.ContainsRune(noLineStart, first) {
+				t.Errorf("line %q starts with closing punctuation", line)
+			}
+			if last := []rune(line)[len([]rune(line))-1]; strings.ContainsRune(noLineEnd, last) {
+				t.Errorf("line %q ends with opening punctuation", line)
+			}
+		}
+	}
+}
+
+func TestLayout_KeepsEmbeddedLTRTogether(t *testing.T) {
+	profile := models.SubtitleProfile{MaxLineChars: 24, MaxLines: 2}
+	cues := Layout([]Cue{{Start: 0, End: 5, Text: "مرحبا بكم في Google Cloud Platform اليوم"}}, "ar", profile, 0)
+
+	found := false
+	for _, cue := range cues {
+		for _, line := range strings.Split(cue.Text, "\n") {
+			if strings.Contains(line, "Google Cloud Platform") {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected the English name on one line, got %+v", cues)
+	}
+}
+
+func TestLayout_ReadingSpeed(t *testing.T) {
+	profile := models.SubtitleProfile{MaxLineChars: 42, MaxLines: 2, MaxCPS: 10, MinDuration: 1}
+	cues := Layout([]Cue{
+		{Start: 1, End: 1.5, Text: "Twenty characters!!!"},
+		{Start: 2.5, End: 2.8, Text: "Hi"},
+		{Start: 5, End: 9, Text: "Plenty of time"},
+	}, "en", profile, 10)
+
+	// The first cue needs 2 seconds: it takes the gap before the next cue, then the one before it
+	if cues[0].Start != 0.5 || cues[0].End != 2.5 {
+		t.Errorf("expected the first cue at 0.5-2.5, got %+v", cues[0])
+	}
+	if cues[1].Start != 2.5 || cues[1].End != 3.5 {
+		t.Errorf("expected the second cue extended to its minimum duration, got %+v", cues[1])
+	}
+	if cues[2].Start != 5 || cues[2].End != 9 {
+		t.Errorf("expected a readable cue to keep its timing, got %+v", cues[2])
+	}
+}
@@ -0,0 +1,129 @@
+package subtitles
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// markupPattern matches WebVTT/SRT inline tags (<i>, <c.yellow>, <00:00:01.000>) and SSA overrides ({\an8})
+var markupPattern = regexp.MustCompile(`<[^>]*>|\{\\[^}]*\}`)
+
+// Parse reads SRT or WebVTT subtitles into cues, in document order
+// Cue numbers, WebVTT headers, NOTE/STYLE/REGION blocks, cue settings and inline markup are ignored;
+// multi-line cue text is joined with spaces.
+func Parse(data []byte) ([]Cue, error) {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	var cues []Cue
+	for _, block := range strings.Split(text, "\n\n") {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+		timing := -1
+		for i, line := range lines {
+			if strings.Contains(line, "-->") {
+				timing = i
+				break
+			}
+		}
+		if timing < 0 || strings.HasPrefix(lines[0], "NOTE") {
+			continue
+		}
+
+		start, end, err := parseTiming(lines[timing])
+		if err != nil {
+			return nil, err
+		}
+
+		var parts []string
+		for _, line := range lines[timing+1:] {
+			if cleaned := strings.TrimSpace(markupPattern.ReplaceAllString(line, "")); cleaned != "" {
+				parts = append(parts, cleaned)
+			}
+		}
+		if len(parts) == 0 {
+			continue
+		}
+		cues = append(cues, Cue{Start: start, End: end, Text: strings.Join(parts, " ")})
+	}
+
+	if len(cues) == 0 {
+		return nil, fmt.Errorf("no subtitle cues found")
+	}
+	return cues, nil
+}
+
+// Text joins the text of all cues into a single transcript
+func Text(cues []Cue) string {
+	texts := make([]string, len(cues))
+	for i, cue := range cues {
+		texts[i] = cue.Text
+	}
+	return strings.Join(texts, " ")
+}
+
+// Clip keeps the cues overlapping [start, end) and shifts them so start becomes zero
+// An end of zero means the end of the video.
+func Clip(cues []Cue, start float64, end float64) []Cue {
+	var clipped []Cue
+	for _, cue := range cues {
+		if cue.End <= start || (end > 0 && cue.Start >= end) {
+			continue
+		}
+		if end > 0 && cue.End > end {
+			cue.End = end
+		}
+		cue.Start = max(cue.Start, start) - start
+		cue.End -= start
+		clipped = append(clipped, cue)
+	}
+	return clipped
+}
+
+// parseTiming parses a "start --> end [settings]" line
+func parseTiming(line string) (float64, float64, error) {
+	startText, endText, _ := strings.Cut(line, "-->")
+	endFields := strings.Fields(endText)
+	if len(endFields) == 0 {
+		return 0, 0, fmt.Errorf("invalid cue timing: %q", line)
+	}
+
+	start, err := parseTimestamp(strings.TrimSpace(startText))
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseTimestamp(endFields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("cue ends before it starts: %q", line)
+	}
+	return start, end, nil
+}
+
+// parseTimestamp parses HH:MM:SS,mmm (SRT) or [HH:]MM:SS.mmm (WebVTT) into seconds
+func parseTimestamp(value string) (float64, error) {
+	parts := strings.Split(strings.Replace(value, ",", ".", 1), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp: %q", value)
+	}
+
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil || seconds < 0 || seconds >= 60 {
+		return 0, fmt.Errorf("invalid timestamp: %q", value)
+	}
+	total := seconds
+	multiplier := 60.0
+	for i := len(parts) - 2; i >= 0; i-- {
+		unit, err := strconv.Atoi(parts[i])
+		if err != nil || unit < 0 {
+			return 0, fmt.Errorf("invalid timestamp: %q", value)
+		}
+		total += float64(unit) * multiplier
+		multiplier *= 60
+	}
+	return total, nil
+}
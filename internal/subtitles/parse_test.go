@@ -0,0 +1,106 @@
+package subtitles
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse_SRT(t *testing.T) {
+	data := "\ufeff1\r\n00:00:01,000 --> 00:00:03,500\r\nHello <i>there</i>.\r\n\r\n2\r\n00:00:04,250 --> 00:00:06,000\r\n{\\an8}How are\r\nyou?\r\n"
+
+	cues, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Cue{
+		{Start: 1, End: 3.5, Text: "Hello there."},
+		{Start: 4.25, End: 6, Text: "How are you?"},
+	}
+	if !reflect.DeepEqual(cues, want) {
+		t.Errorf("expected %+v, got %+v", want, cues)
+	}
+}
+
+func TestParse_VTT(t *testing.T) {
+	data := `WEBVTT - Episode 1
+
+NOTE this block --> is ignored
+
+STYLE
+::cue { color: yellow }
+
+intro
+00:01.000 --> 00:02.500 align:start position:10%
+<v Anna>Welcome back.</v>
+
+01:00:00.000 --> 01:00:01.000
+
+01:00:02.000 --> 01:00:03.000
+Goodbye.
+`
+
+	cues, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Cue{
+		{Start: 1, End: 2.5, Text: "Welcome back."},
+		{Start: 3602, End: 3603, Text: "Goodbye."},
+	}
+	if !reflect.DeepEqual(cues, want) {
+		t.Errorf("expected %+v, got %+v", want, cues)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"no cues", "WEBVTT\n\nNOTE nothing here\n"},
+		{"bad timestamp", "1\n00:00:aa,000 --> 00:00:02,000\nHello\n"},
+		{"end before start", "1\n00:00:05,000 --> 00:00:02,000\nHello\n"},
+		{"missing end", "1\n00:00:01,000 -->\nHello\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestClip(t *testing.T) {
+	cues := []Cue{
+		{Start: 0, End: 2, Text: "before"},
+		{Start: 4, End: 6, Text: "straddles start"},
+		{Start: 7, End: 9, Text: "inside"},
+		{Start: 9, End: 12, Text: "straddles end"},
+		{Start: 12, End: 14, Text: "after"},
+	}
+
+	want := []Cue{
+		{Start: 0, End: 1, Text: "straddles start"},
+		{Start: 2, End: 4, Text: "inside"},
+		{Start: 4, End: 5, Text: "straddles end"},
+	}
+	if got := Clip(cues, 5, 10); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	if got := Clip(cues, 11, 0); len(got) != 2 || got[1].Start != 1 || got[1].End != 3 {
+		t.Errorf("expected cues to the end of the video, got %+v", got)
+	}
+}
+
+func TestText(t *testing.T) {
+	cues := []Cue{{Text: "Hello there."}, {Text: "How are you?"}}
+	if got := Text(cues); got != "Hello there. How are you?" {
+		t.Errorf("expected joined text, got %q", got)
+	}
+}
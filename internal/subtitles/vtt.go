@@ -0,0 +1,64 @@
+package subtitles
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// Cue represents a single timed subtitle entry
+// It is the checkpoint type so parsed source subtitles can be stored with a job.
+type Cue = models.SubtitleCue
+
+// EstimateCues distributes text segments over a duration proportionally to their length
+// Used when the transcript has no word-level timings
+func EstimateCues(segments []string, duration float64) []Cue {
+	totalChars := 0
+	for _, segment := range segments {
+		totalChars += utf8.RuneCountInString(segment)
+	}
+	if totalChars == 0 || duration <= 0 {
+		return nil
+	}
+
+	cues := make([]Cue, 0, len(segments))
+	position := 0.0
+	for _, segment := range segments {
+		length := float64(utf8.RuneCountInString(segment)) / float64(totalChars) * duration
+		cues = append(cues, Cue{
+			Start: position,
+			End:   position + length,
+			Text:  segment,
+		})
+		position += length
+	}
+
+	// Avoid floating point drift past the end of the video
+	cues[len(cues)-1].End = duration
+	return cues
+}
+
+// FormatVTT renders cues as a WebVTT document
+func FormatVTT(cues []Cue) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for i, cue := range cues {
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, formatTimestamp(cue.Start, "."), formatTimestamp(cue.End, "."), cue.Text)
+	}
+	return b.String()
+}
+
+// formatTimestamp renders seconds as HH:MM:SS<sep>mmm
+func formatTimestamp(seconds float64, millisSeparator string) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	totalMillis := int64(seconds*1000 + 0.5)
+	hours := totalMillis / 3600000
+	minutes := (totalMillis % 3600000) / 60000
+	secs := (totalMillis % 60000) / 1000
+	millis := totalMillis % 1000
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, secs, millisSeparator, millis)
+}
@@ -0,0 +1,50 @@
+package subtitles
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimateCues(t *testing.T) {
+	cues := EstimateCues([]string{"abcd", "ab", "ab"}, 8.0)
+
+	if len(cues) != 3 {
+		t.Fatalf("expected 3 cues, got %d", len(cues))
+	}
+
+	if cues[0].Start != 0 || cues[0].End != 4.0 {
+		t.Errorf("unexpected first cue timing: %+v", cues[0])
+	}
+	if cues[1].Start != 4.0 || cues[1].End != 6.0 {
+		t.Errorf("unexpected second cue timing: %+v", cues[1])
+	}
+	if cues[2].End != 8.0 {
+		t.Errorf("expected last cue to end at duration, got %f", cues[2].End)
+	}
+}
+
+func TestEstimateCues_Empty(t *testing.T) {
+	if cues := EstimateCues(nil, 10); cues != nil {
+		t.Errorf("expected no cues, got %v", cues)
+	}
+	if cues := EstimateCues([]string{"text"}, 0); cues != nil {
+		t.Errorf("expected no cues for zero duration, got %v", cues)
+	}
+}
+
+func TestFormatVTT(t *testing.T) {
+	vtt := FormatVTT([]Cue{
+		{Start: 0, End: 1.5, Text: "Hello"},
+		{Start: 1.5, End: 3661.25, Text: "World"},
+	})
+
+	if !strings.HasPrefix(vtt, "WEBVTT\n\n") {
+		t.Errorf("expected WEBVTT header, got %q", vtt)
+	}
+	if !strings.Contains(vtt, "00:00:00.000 --> 00:00:01.500\nHello") {
+		t.Errorf("missing first cue in %q", vtt)
+	}
+	if !strings.Contains(vtt, "00:00:01.500 --> 01:01:01.250\nWorld") {
+		t.Errorf("missing second cue in %q", vtt)
+	}
+}
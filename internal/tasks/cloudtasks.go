@@ -0,0 +1,88 @@
+package tasks
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	cloudtasks "google.golang.org/api/cloudtasks/v2"
+	"google.golang.org/api/option"
+)
+
+const (
+	// RetryPath is the endpoint Cloud Tasks calls to re-run a job
+	RetryPath = "/tasks/retry"
+
+	// TokenHeader carries the shared secret that authenticates task callbacks
+	TokenHeader = "X-Task-Token"
+)
+
+// RetryTask is the body of a scheduled retry callback
+type RetryTask struct {
+	JobID   string `json:"jobId"`
+	Attempt int    `json:"attempt"`
+}
+
+// CloudTasksScheduler schedules delayed job retries on a Cloud Tasks queue
+type CloudTasksScheduler struct {
+	Queue     string // Full queue name: projects/{project}/locations/{location}/queues/{queue}
+	TargetURL string // Base URL of this service; RetryPath is appended
+	token     string
+	service   *cloudtasks.Service
+}
+
+// NewCloudTasksScheduler creates a scheduler for the given queue
+// Uses the credentials file if configured, otherwise default credentials
+func NewCloudTasksScheduler(ctx context.Context, queue, targetURL, token string, opts ...option.ClientOption) (*CloudTasksScheduler, error) {
+	if credentialsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); credentialsPath != "" && len(opts) == 0 {
+		opts = append(opts, option.WithCredentialsFile(credentialsPath))
+	}
+
+	service, err := cloudtasks.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Tasks client: %w", err)
+	}
+
+	return &CloudTasksScheduler{
+		Queue:     queue,
+		TargetURL: strings.TrimSuffix(targetURL, "/"),
+		token:     token,
+		service:   service,
+	}, nil
+}
+
+// ScheduleRetry creates an HTTP task that calls back RetryPath at the given time
+func (s *CloudTasksScheduler) ScheduleRetry(ctx context.Context, task RetryTask, at time.Time) error {
+	body, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal retry task: %w", err)
+	}
+
+	req := &cloudtasks.CreateTaskRequest{
+		Task: &cloudtasks.Task{
+			ScheduleTime: at.UTC().Format(time.RFC3339),
+			HttpRequest: &cloudtasks.HttpRequest{
+				HttpMethod: "POST",
+				Url:        s.TargetURL + RetryPath,
+				Headers: map[string]string{
+					"Content-Type": "application/json",
+					TokenHeader:    s.token,
+				},
+				Body: base64.StdEncoding.EncodeToString(body),
+			},
+		},
+	}
+
+	created, err := s.service.Projects.Locations.Queues.Tasks.Create(s.Queue, req).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to create retry task: %w", err)
+	}
+
+	slog.Info("Scheduled job retry", "jobID", task.JobID, "attempt", task.Attempt, "at", at, "task", created.Name)
+	return nil
+}
@@ -0,0 +1,93 @@
+package tasks
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/option"
+)
+
+func TestCloudTasksScheduler_ScheduleRetry(t *testing.T) {
+	var path string
+	var request struct {
+		Task struct {
+			ScheduleTime string `json:"scheduleTime"`
+			HTTPRequest  struct {
+				HTTPMethod string            `json:"httpMethod"`
+				URL        string            `json:"url"`
+				Headers    map[string]string `json:"headers"`
+				Body       string            `json:"body"`
+			} `json:"httpRequest"`
+		} `json:"task"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"projects/p/locations/l/queues/q/tasks/1"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	scheduler, err := NewCloudTasksScheduler(ctx, "projects/p/locations/l/queues/q", "https://service.example.com/", "secret",
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("failed to create scheduler: %v", err)
+	}
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := scheduler.ScheduleRetry(ctx, RetryTask{JobID: "job-1", Attempt: 2}, at); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasSuffix(path, "projects/p/locations/l/queues/q/tasks") {
+		t.Errorf("unexpected create path: %s", path)
+	}
+	if request.Task.ScheduleTime != "2026-01-02T03:04:05Z" {
+		t.Errorf("expected schedule time 2026-01-02T03:04:05Z, got %s", request.Task.ScheduleTime)
+	}
+
+	httpReq := request.Task.HTTPRequest
+	if httpReq.HTTPMethod != "POST" || httpReq.URL != "https://service.example.com/tasks/retry" {
+		t.Errorf("unexpected target: %s %s", httpReq.HTTPMethod, httpReq.URL)
+	}
+	if httpReq.Headers[TokenHeader] != "secret" {
+		t.Errorf("expected token header, got %v", httpReq.Headers)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(httpReq.Body)
+	if err != nil {
+		t.Fatalf("failed to decode task body: %v", err)
+	}
+	var task RetryTask
+	if err := json.Unmarshal(data, &task); err != nil {
+		t.Fatalf("failed to unmarshal task body: %v", err)
+	}
+	if task.JobID != "job-1" || task.Attempt != 2 {
+		t.Errorf("unexpected task body: %+v", task)
+	}
+}
+
+func TestCloudTasksScheduler_ScheduleRetryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	scheduler, err := NewCloudTasksScheduler(ctx, "projects/p/locations/l/queues/q", "https://service.example.com", "secret",
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("failed to create scheduler: %v", err)
+	}
+
+	if err := scheduler.ScheduleRetry(ctx, RetryTask{JobID: "job-1", Attempt: 1}, time.Now()); err == nil {
+		t.Error("expected error for rejected task")
+	}
+}
@@ -0,0 +1,112 @@
+package textproc
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Localizer formats ISO dates and decimal numbers for the target locale, e.g. "2024-03-15" as
+// "15. März 2024" and "3.5" as "3,5" in German
+type Localizer struct{}
+
+// dateFormats writes a date in the long form of a language
+var dateFormats = map[string]func(day int, month int, year int) string{
+	"en": func(d, m, y int) string { return months["en"][m-1] + " " + strconv.Itoa(d) + ", " + strconv.Itoa(y) },
+	"de": func(d, m, y int) string { return strconv.Itoa(d) + ". " + months["de"][m-1] + " " + strconv.Itoa(y) },
+	"fr": func(d, m, y int) string { return frenchDay(d) + " " + months["fr"][m-1] + " " + strconv.Itoa(y) },
+	"es": func(d, m, y int) string {
+		return strconv.Itoa(d) + " de " + months["es"][m-1] + " de " + strconv.Itoa(y)
+	},
+	"it": func(d, m, y int) string { return strconv.Itoa(d) + " " + months["it"][m-1] + " " + strconv.Itoa(y) },
+	"pt": func(d, m, y int) string {
+		return strconv.Itoa(d) + " de " + months["pt"][m-1] + " de " + strconv.Itoa(y)
+	},
+}
+
+var months = map[string][12]string{
+	"en": {"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	"de": {"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	"fr": {"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	"es": {"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	"it": {"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+	"pt": {"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+}
+
+// decimalCommaLanguages write decimals with a comma
+var decimalCommaLanguages = map[string]bool{"de": true, "fr": true, "es": true, "it": true, "pt": true}
+
+var (
+	isoDatePattern = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	decimalPattern = regexp.MustCompile(`\d+\.\d+`)
+)
+
+// Name implements Processor
+func (Localizer) Name() string { return NameLocalize }
+
+// SpeechOnly implements Processor; localized formats are also shown in subtitles
+func (Localizer) SpeechOnly() bool { return false }
+
+// Process implements Processor
+func (Localizer) Process(text string, language string) string {
+	language = baseLanguage(language)
+	if format, ok := dateFormats[language]; ok {
+		text = isoDatePattern.ReplaceAllStringFunc(text, func(match string) string {
+			parts := isoDatePattern.FindStringSubmatch(match)
+			year, _ := strconv.Atoi(parts[1])
+			month, _ := strconv.Atoi(parts[2])
+			day, _ := strconv.Atoi(parts[3])
+			if month < 1 || month > 12 || day < 1 || day > 31 {
+				return match
+			}
+			return format(day, month, year)
+		})
+	}
+	if decimalCommaLanguages[language] {
+		text = localizeDecimals(text)
+	}
+	return text
+}
+
+// localizeDecimals replaces the decimal point of numbers such as "3.5" with a comma
+// Three fractional digits ("1.000") may be a thousands separator, and numbers that are part of
+// versions, IP addresses, English-grouped figures ("1,234.5") or identifiers ("v2.5") are left alone.
+func localizeDecimals(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range decimalPattern.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		match := text[start:end]
+		point := strings.IndexByte(match, '.')
+		if len(match)-point-1 == 3 || !standalone(text, start, end) {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(match[:point] + "," + match[point+1:])
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// standalone reports whether text[start:end] is not preceded by a letter or separator, nor followed by further digits
+func standalone(text string, start int, end int) bool {
+	if before, _ := utf8.DecodeLastRuneInString(text[:start]); before == '.' || before == ',' || unicode.IsLetter(before) {
+		return false
+	}
+	after := text[end:]
+	if len(after) > 1 && (after[0] == '.' || after[0] == ',') && after[1] >= '0' && after[1] <= '9' {
+		return false
+	}
+	return true
+}
+
+// frenchDay writes the first day of a month as "1er", as French dates do
+func frenchDay(day int) string {
+	if day == 1 {
+		return "1er"
+	}
+	return strconv.Itoa(day)
+}
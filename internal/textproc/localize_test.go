@@ -0,0 +1,32 @@
+package textproc
+
+import "testing"
+
+func TestLocalizer_Process(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		language string
+		want     string
+	}{
+		{"english date", "Released on 2024-03-15.", "en", "Released on March 15, 2024."},
+		{"french first of month", "Le 2024-05-01 à Paris", "fr-CA", "Le 1er mai 2024 à Paris"},
+		{"spanish date", "el 2023-12-24", "es", "el 24 de diciembre de 2023"},
+		{"invalid date", "code 2024-13-40", "de", "code 2024-13-40"},
+		{"german decimal", "Es waren 3.5 Meter und 12.75 Euro.", "de", "Es waren 3,5 Meter und 12,75 Euro."},
+		{"english decimal", "It was 3.5 meters.", "en", "It was 3.5 meters."},
+		{"thousands separator", "Es kamen 1.000 Gäste.", "de", "Es kamen 1.000 Gäste."},
+		{"version number", "Version 2.0.1 und v2.5", "de", "Version 2.0.1 und v2.5"},
+		{"english grouping", "Il a payé 1,234.50 dollars.", "fr", "Il a payé 1,234.50 dollars."},
+		{"sentence end", "Le total est de 4.5.", "fr", "Le total est de 4,5."},
+		{"unknown language", "2024-03-15 3.5", "ja", "2024-03-15 3.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Localizer{}).Process(tt.text, tt.language); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
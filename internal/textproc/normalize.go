@@ -0,0 +1,124 @@
+package textproc
+
+import (
+	"regexp"
+	"sync"
+)
+
+// Rule rewrites terms of a text into the forms speech synthesis should read, e.g. an acronym into its expansion
+type Rule interface {
+	// Apply returns the rewritten text for a target language (a base code such as "de")
+	Apply(text string, language string) string
+}
+
+// RuleFunc adapts a function to the Rule interface
+type RuleFunc func(text string, language string) string
+
+// Apply implements Rule
+func (f RuleFunc) Apply(text string, language string) string {
+	return f(text, language)
+}
+
+// languageRule is a rule registered for one base language, or every language when empty
+type languageRule struct {
+	language string
+	rule     Rule
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = []languageRule{
+		{rule: RuleFunc(spellCurrencies)},
+		{rule: RuleFunc(Speller{}.Process)},
+	}
+)
+
+// RegisterRule adds a rule to the normalize processor for a language ("de", "pt-BR" counts as "pt"),
+// or for every language when language is empty. Rules run in registration order after the built-in
+// currency, unit and ordinal rules.
+func RegisterRule(language string, rule Rule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules = append(rules, languageRule{language: baseLanguage(language), rule: rule})
+}
+
+// Normalizer rewrites text into speakable forms for synthesis with the registered rules: currency amounts
+// ("$5" as "5 dollars"), units and ordinals as the spoken processor, and custom rules such as acronyms
+type Normalizer struct{}
+
+// Name implements Processor
+func (Normalizer) Name() string { return NameNormalize }
+
+// SpeechOnly implements Processor; subtitles keep the written forms
+func (Normalizer) SpeechOnly() bool { return true }
+
+// Process implements Processor
+func (Normalizer) Process(text string, language string) string {
+	language = baseLanguage(language)
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	for _, registered := range rules {
+		if registered.language == "" || registered.language == language {
+			text = registered.rule.Apply(text, language)
+		}
+	}
+	return text
+}
+
+// wordPattern matches the words of a text, runs of letters and digits
+var wordPattern = regexp.MustCompile(`[\p{L}\d]+`)
+
+// Acronyms returns a rule replacing acronyms with their spoken forms, e.g. "EU" with "European Union" or
+// "SQL" with "sequel". Only whole words match, case-sensitively, so ordinary words are left alone.
+func Acronyms(expansions map[string]string) Rule {
+	return RuleFunc(func(text string, language string) string {
+		if len(expansions) == 0 {
+			return text
+		}
+		return wordPattern.ReplaceAllStringFunc(text, func(word string) string {
+			if expansion, ok := expansions[word]; ok {
+				return expansion
+			}
+			return word
+		})
+	})
+}
+
+// currencyNames are the singular and plural spoken names of currency symbols per language
+var currencyNames = map[string]map[string][2]string{
+	"en": {"$": {"dollar", "dollars"}, "€": {"euro", "euros"}, "£": {"pound", "pounds"}, "¥": {"yen", "yen"}},
+	"de": {"$": {"Dollar", "Dollar"}, "€": {"Euro", "Euro"}, "£": {"Pfund", "Pfund"}, "¥": {"Yen", "Yen"}},
+	"fr": {"$": {"dollar", "dollars"}, "€": {"euro", "euros"}, "£": {"livre", "livres"}, "¥": {"yen", "yens"}},
+	"es": {"$": {"dólar", "dólares"}, "€": {"euro", "euros"}, "£": {"libra", "libras"}, "¥": {"yen", "yenes"}},
+	"it": {"$": {"dollaro", "dollari"}, "€": {"euro", "euro"}, "£": {"sterlina", "sterline"}, "¥": {"yen", "yen"}},
+	"pt": {"$": {"dólar", "dólares"}, "€": {"euro", "euros"}, "£": {"libra", "libras"}, "¥": {"iene", "ienes"}},
+}
+
+var (
+	// A currency symbol before ("$5", "€ 2.50") or after ("5 €", "2,50€") an amount
+	currencyBefore = regexp.MustCompile(`([$€£¥])\s?(\d+(?:[.,]\d+)*)`)
+	currencyAfter  = regexp.MustCompile(`(\d+(?:[.,]\d+)*)\s?([$€£¥])`)
+)
+
+// spellCurrencies writes currency amounts as the amount followed by the currency name
+func spellCurrencies(text string, language string) string {
+	names, ok := currencyNames[language]
+	if !ok {
+		return text
+	}
+	spell := func(amount string, symbol string) string {
+		name := names[symbol]
+		if singular(amount, language) {
+			return amount + " " + name[0]
+		}
+		return amount + " " + name[1]
+	}
+	text = currencyBefore.ReplaceAllStringFunc(text, func(match string) string {
+		parts := currencyBefore.FindStringSubmatch(match)
+		return spell(parts[2], parts[1])
+	})
+	return currencyAfter.ReplaceAllStringFunc(text, func(match string) string {
+		parts := currencyAfter.FindStringSubmatch(match)
+		return spell(parts[1], parts[2])
+	})
+}
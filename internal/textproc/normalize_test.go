@@ -0,0 +1,65 @@
+package textproc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizer_Process(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		language string
+		want     string
+	}{
+		{"dollars before amount", "It costs $5 or $1.", "en-US", "It costs 5 dollars or 1 dollar."},
+		{"euros after amount", "Preis: 2,50 € statt 3€", "de", "Preis: 2,50 Euro statt 3 Euro"},
+		{"french singular below two", "1,5 € le kilo", "fr", "1,5 euro le kilo"},
+		{"units and ordinals", "The 2nd car drove 120 km/h", "en", "The second car drove 120 kilometers per hour"},
+		{"unknown language", "$5", "ja", "$5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Normalizer{}).Process(tt.text, tt.language); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	saved := rules
+	defer func() { rules = saved }()
+
+	RegisterRule("", Acronyms(map[string]string{"GCP": "G C P"}))
+	RegisterRule("de-DE", Acronyms(map[string]string{"EU": "Europäische Union"}))
+	RegisterRule("en", RuleFunc(func(text string, language string) string { return strings.ToUpper(text) }))
+
+	normalizer := Normalizer{}
+	if got, want := normalizer.Process("Die EU nutzt GCP.", "de"), "Die Europäische Union nutzt G C P."; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got, want := normalizer.Process("The EU uses GCP.", "en"), "THE EU USES G C P."; got != want {
+		t.Errorf("expected rules of other languages to be skipped, got %q", got)
+	}
+}
+
+func TestAcronyms(t *testing.T) {
+	rule := Acronyms(map[string]string{"EU": "European Union", "SQL": "sequel"})
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"EU/SQL rules", "European Union/sequel rules"},
+		{"EU EU", "European Union European Union"},
+		{"EUR and Europe and eu", "EUR and Europe and eu"}, // Whole words only, case-sensitive
+		{"SQLite", "SQLite"},
+	}
+	for _, tt := range tests {
+		if got := rule.Apply(tt.text, "en"); got != tt.want {
+			t.Errorf("Apply(%q): expected %q, got %q", tt.text, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package textproc
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Speller writes percentages, units and ordinals out in words so speech synthesis reads them naturally,
+// e.g. "50%" as "50 Prozent" in German and "3rd" as "third" in English
+type Speller struct{}
+
+// unitNames are the singular and plural spoken names of unit symbols per language
+var unitNames = map[string]map[string][2]string{
+	"en": {
+		"%":    {"percent", "percent"},
+		"km/h": {"kilometer per hour", "kilometers per hour"},
+		"km":   {"kilometer", "kilometers"},
+		"kg":   {"kilogram", "kilograms"},
+		"cm":   {"centimeter", "centimeters"},
+		"mm":   {"millimeter", "millimeters"},
+		"°C":   {"degree Celsius", "degrees Celsius"},
+	},
+	"de": {
+		"%":    {"Prozent", "Prozent"},
+		"km/h": {"Kilometer pro Stunde", "Kilometer pro Stunde"},
+		"km":   {"Kilometer", "Kilometer"},
+		"kg":   {"Kilogramm", "Kilogramm"},
+		"cm":   {"Zentimeter", "Zentimeter"},
+		"mm":   {"Millimeter", "Millimeter"},
+		"°C":   {"Grad Celsius", "Grad Celsius"},
+	},
+	"fr": {
+		"%":    {"pour cent", "pour cent"},
+		"km/h": {"kilomètre par heure", "kilomètres par heure"},
+		"km":   {"kilomètre", "kilomètres"},
+		"kg":   {"kilogramme", "kilogrammes"},
+		"cm":   {"centimètre", "centimètres"},
+		"mm":   {"millimètre", "millimètres"},
+		"°C":   {"degré Celsius", "degrés Celsius"},
+	},
+	"es": {
+		"%":    {"por ciento", "por ciento"},
+		"km/h": {"kilómetro por hora", "kilómetros por hora"},
+		"km":   {"kilómetro", "kilómetros"},
+		"kg":   {"kilogramo", "kilogramos"},
+		"cm":   {"centímetro", "centímetros"},
+		"mm":   {"milímetro", "milímetros"},
+		"°C":   {"grado Celsius", "grados Celsius"},
+	},
+	"it": {
+		"%":    {"per cento", "per cento"},
+		"km/h": {"chilometro orario", "chilometri orari"},
+		"km":   {"chilometro", "chilometri"},
+		"kg":   {"chilogrammo", "chilogrammi"},
+		"cm":   {"centimetro", "centimetri"},
+		"mm":   {"millimetro", "millimetri"},
+		"°C":   {"grado Celsius", "gradi Celsius"},
+	},
+	"pt": {
+		"%":    {"por cento", "por cento"},
+		"km/h": {"quilômetro por hora", "quilômetros por hora"},
+		"km":   {"quilômetro", "quilômetros"},
+		"kg":   {"quilograma", "quilogramas"},
+		"cm":   {"centímetro", "centímetros"},
+		"mm":   {"milímetro", "milímetros"},
+		"°C":   {"grau Celsius", "graus Celsius"},
+	},
+}
+
+var (
+	// A number followed by a unit symbol that does not run into a word (so "5 cmd" is not "5 cm" + "d")
+	unitPattern       = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s?(%|km/h|km|kg|cm|mm|°C)([^\p{L}\d/]|$)`)
+	englishOrdinal    = regexp.MustCompile(`\b(\d{1,2})(?:st|nd|rd|th)\b`)
+	frenchFirstPrefix = regexp.MustCompile(`\b1(er|re)\b`)
+)
+
+var (
+	englishTens         = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	englishOrdinalOnes  = []string{"", "first", "second", "third", "fourth", "fifth", "sixth", "seventh", "eighth", "ninth", "tenth", "eleventh", "twelfth", "thirteenth", "fourteenth", "fifteenth", "sixteenth", "seventeenth", "eighteenth", "nineteenth"}
+	englishOrdinalTens  = []string{"", "", "twentieth", "thirtieth", "fortieth", "fiftieth", "sixtieth", "seventieth", "eightieth", "ninetieth"}
+	frenchFirstOrdinals = map[string]string{"er": "premier", "re": "première"}
+)
+
+// Name implements Processor
+func (Speller) Name() string { return NameSpoken }
+
+// SpeechOnly implements Processor; subtitles keep the compact forms
+func (Speller) SpeechOnly() bool { return true }
+
+// Process implements Processor
+func (Speller) Process(text string, language string) string {
+	language = baseLanguage(language)
+	if names, ok := unitNames[language]; ok {
+		text = unitPattern.ReplaceAllStringFunc(text, func(match string) string {
+			parts := unitPattern.FindStringSubmatch(match)
+			name := names[parts[2]]
+			spoken := name[1]
+			if singular(parts[1], language) {
+				spoken = name[0]
+			}
+			return parts[1] + " " + spoken + parts[3]
+		})
+	}
+
+	switch language {
+	case "en":
+		text = englishOrdinal.ReplaceAllStringFunc(text, func(match string) string {
+			n, _ := strconv.Atoi(englishOrdinal.FindStringSubmatch(match)[1])
+			if word := englishOrdinalWord(n); word != "" {
+				return word
+			}
+			return match
+		})
+	case "fr":
+		text = frenchFirstPrefix.ReplaceAllStringFunc(text, func(match string) string {
+			return frenchFirstOrdinals[match[1:]]
+		})
+	}
+	return text
+}
+
+// singular reports whether a quantity takes the singular unit name: exactly one, or below two in French
+func singular(number string, language string) bool {
+	value, err := strconv.ParseFloat(strings.Replace(number, ",", ".", 1), 64)
+	if err != nil {
+		return false
+	}
+	if language == "fr" {
+		return value < 2
+	}
+	return value == 1
+}
+
+// englishOrdinalWord writes 1 to 99 as an ordinal word (e.g., 21 as "twenty-first"), or returns empty
+func englishOrdinalWord(n int) string {
+	switch {
+	case n <= 0 || n >= 100:
+		return ""
+	case n < 20:
+		return englishOrdinalOnes[n]
+	case n%10 == 0:
+		return englishOrdinalTens[n/10]
+	default:
+		return englishTens[n/10] + "-" + englishOrdinalOnes[n%10]
+	}
+}
@@ -0,0 +1,30 @@
+package textproc
+
+import "testing"
+
+func TestSpeller_Process(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		language string
+		want     string
+	}{
+		{"english ordinals", "On the 3rd and 21st of May, 11th place", "en-US", "On the third and twenty-first of May, eleventh place"},
+		{"english units", "1 km, 5 kg and 30°C", "en", "1 kilometer, 5 kilograms and 30 degrees Celsius"},
+		{"speed before distance", "120 km/h", "en", "120 kilometers per hour"},
+		{"percent", "Nous avons 50 % de réduction.", "fr", "Nous avons 50 pour cent de réduction."},
+		{"french singular below two", "1,5 km", "fr", "1,5 kilomètre"},
+		{"french first", "le 1er mai, la 1re fois", "fr", "le premier mai, la première fois"},
+		{"german units", "2,5 kg und 10%", "de", "2,5 Kilogramm und 10 Prozent"},
+		{"unit inside word", "run 5 cmd", "en", "run 5 cmd"},
+		{"unknown language", "50%", "ja", "50%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Speller{}).Process(tt.text, tt.language); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
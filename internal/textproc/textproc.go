@@ -0,0 +1,73 @@
+package textproc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Processor rewrites translated text for a target language between translation and speech synthesis
+type Processor interface {
+	// Name identifies the processor in TEXT_PROCESSORS
+	Name() string
+	// SpeechOnly reports whether the rewrite is only for synthesis; other processors also change
+	// the translated text and subtitles
+	SpeechOnly() bool
+	// Process returns the rewritten text; languages the processor does not know are returned unchanged
+	Process(text string, language string) string
+}
+
+// Names of the built-in processors
+const (
+	NameLocalize  = "localize"  // Locale formats for dates and decimal numbers
+	NameSpoken    = "spoken"    // Spoken forms of percentages, units and ordinals for TTS
+	NameNormalize = "normalize" // Spoken forms of currencies, units, ordinals and registered rules for TTS
+)
+
+// registry creates the built-in processors by name
+var registry = map[string]func() Processor{
+	NameLocalize:  func() Processor { return Localizer{} },
+	NameSpoken:    func() Processor { return Speller{} },
+	NameNormalize: func() Processor { return Normalizer{} },
+}
+
+// Pipeline applies processors in order
+type Pipeline []Processor
+
+// New builds a pipeline from processor names, in the given order
+func New(names []string) (Pipeline, error) {
+	pipeline := make(Pipeline, 0, len(names))
+	for _, name := range names {
+		create, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown text processor: %s", name)
+		}
+		pipeline = append(pipeline, create())
+	}
+	return pipeline, nil
+}
+
+// Text applies the processors that also change the translated text and subtitles
+func (p Pipeline) Text(text string, language string) string {
+	for _, processor := range p {
+		if !processor.SpeechOnly() {
+			text = processor.Process(text, language)
+		}
+	}
+	return text
+}
+
+// Speech applies the speech-only processors to text that has already been through Text
+func (p Pipeline) Speech(text string, language string) string {
+	for _, processor := range p {
+		if processor.SpeechOnly() {
+			text = processor.Process(text, language)
+		}
+	}
+	return text
+}
+
+// baseLanguage strips the region of a language code (e.g., "pt-BR" to "pt")
+func baseLanguage(language string) string {
+	base, _, _ := strings.Cut(strings.ToLower(language), "-")
+	return base
+}
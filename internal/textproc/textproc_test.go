@@ -0,0 +1,35 @@
+package textproc
+
+import "testing"
+
+func TestNew(t *testing.T) {
+	pipeline, err := New([]string{NameLocalize, NameSpoken})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pipeline) != 2 || pipeline[0].Name() != NameLocalize || pipeline[1].Name() != NameSpoken {
+		t.Errorf("expected processors in order, got %v", pipeline)
+	}
+
+	if _, err := New([]string{"emoji"}); err == nil {
+		t.Error("expected error for unknown processor")
+	}
+}
+
+func TestPipeline_TextAndSpeech(t *testing.T) {
+	pipeline, _ := New([]string{NameLocalize, NameSpoken})
+
+	text := pipeline.Text("Am 2024-03-15 stieg der Preis um 2.5%.", "de-DE")
+	if want := "Am 15. März 2024 stieg der Preis um 2,5%."; text != want {
+		t.Errorf("expected text %q, got %q", want, text)
+	}
+	speech := pipeline.Speech(text, "de-DE")
+	if want := "Am 15. März 2024 stieg der Preis um 2,5 Prozent."; speech != want {
+		t.Errorf("expected speech %q, got %q", want, speech)
+	}
+
+	var empty Pipeline
+	if got := empty.Speech("50%", "de"); got != "50%" {
+		t.Errorf("expected empty pipeline to keep text, got %q", got)
+	}
+}
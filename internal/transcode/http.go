@@ -0,0 +1,147 @@
+package transcode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/outbound"
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+)
+
+// HTTPMuxer posts the video and its dubbed audio to a remote worker (e.g. a GPU instance running ffmpeg)
+// The request is multipart/form-data with the files "video", "audio" and, when mixed, "background", plus
+// the JSON field "options"; the response body is the dubbed MP4.
+type HTTPMuxer struct {
+	Endpoint string
+	client   *http.Client
+}
+
+// NewHTTPMuxer creates a muxer calling the given worker endpoint
+func NewHTTPMuxer(endpoint string) *HTTPMuxer {
+	return &HTTPMuxer{
+		Endpoint: endpoint,
+		client:   outbound.NewClient(30 * time.Minute), // Uploading and muxing a long 4K video
+	}
+}
+
+// muxOptions are the mux options sent to the worker
+type muxOptions struct {
+	BackgroundVolume  float64           `json:"backgroundVolume,omitempty"`
+	Rotation          int               `json:"rotation,omitempty"`
+	NormalizeRotation bool              `json:"normalizeRotation,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	AudioLanguage     string            `json:"audioLanguage,omitempty"`
+	SampleRate        int               `json:"sampleRate,omitempty"`
+	Channels          int               `json:"channels,omitempty"`
+	BitrateKbps       int               `json:"bitrateKbps,omitempty"`
+}
+
+// Name implements Muxer interface
+func (m *HTTPMuxer) Name() string {
+	return BackendHTTP
+}
+
+// Mux implements Muxer interface
+// The multipart body is streamed so large videos are not held in memory.
+func (m *HTTPMuxer) Mux(ctx context.Context, input Input, outputPath string) error {
+	opts := input.Options
+	options, err := json.Marshal(muxOptions{
+		BackgroundVolume:  opts.BackgroundVolume,
+		Rotation:          opts.Rotation,
+		NormalizeRotation: opts.NormalizeRotation,
+		Metadata:          opts.Metadata,
+		AudioLanguage:     opts.AudioLanguage,
+		SampleRate:        opts.Audio.SampleRate,
+		Channels:          opts.Audio.Channels,
+		BitrateKbps:       opts.Audio.Bitrate,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode mux options: %w", err)
+	}
+
+	files := map[string]string{"video": input.VideoPath, "audio": input.AudioPath}
+	if opts.BackgroundPath != "" {
+		files["background"] = opts.BackgroundPath
+	}
+
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		writer.CloseWithError(writeForm(form, options, files))
+	}()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.Endpoint, body)
+	if err != nil {
+		body.Close()
+		return fmt.Errorf("failed to create mux request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("mux request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &transient.HTTPError{Service: "transcode worker", StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(message))}
+	}
+	return writeOutput(outputPath, resp.Body)
+}
+
+// writeForm writes the options field and the files of a mux request
+func writeForm(form *multipart.Writer, options []byte, files map[string]string) error {
+	if err := form.WriteField("options", string(options)); err != nil {
+		return err
+	}
+	for field, path := range files {
+		if err := writeFile(form, field, path); err != nil {
+			return err
+		}
+	}
+	return form.Close()
+}
+
+// writeFile copies a local file into a form file field
+func writeFile(form *multipart.Writer, field string, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", field, err)
+	}
+	defer file.Close()
+
+	part, err := form.CreateFormFile(field, field)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, file)
+	return err
+}
+
+// writeOutput writes the dubbed video received from a backend, checking it is not empty
+func writeOutput(outputPath string, body io.Reader) error {
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	size, err := io.Copy(out, body)
+	if err != nil {
+		out.Close()
+		return fmt.Errorf("failed to read dubbed video: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write dubbed video: %w", err)
+	}
+	if size == 0 {
+		return fmt.Errorf("transcode backend returned an empty video")
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package transcode
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+	"github.com/sinouw/multilingual-video-processor/internal/video"
+)
+
+func writeTempFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestHTTPMuxer_Mux(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("failed to parse form: %v", err)
+		}
+		var options muxOptions
+		if err := json.Unmarshal([]byte(r.FormValue("options")), &options); err != nil {
+			t.Errorf("failed to parse options: %v", err)
+		}
+		if options.AudioLanguage != "deu" || options.Channels != 2 {
+			t.Errorf("unexpected options: %+v", options)
+		}
+		for field, want := range map[string]string{"video": "video-bytes", "audio": "audio-bytes"} {
+			file, _, err := r.FormFile(field)
+			if err != nil {
+				t.Fatalf("missing %s: %v", field, err)
+			}
+			data, _ := io.ReadAll(file)
+			if string(data) != want {
+				t.Errorf("expected %s content %q, got %q", field, want, data)
+			}
+		}
+		if _, _, err := r.FormFile("background"); err == nil {
+			t.Error("expected no background without a background mix")
+		}
+		w.Write([]byte("dubbed-video"))
+	}))
+	defer server.Close()
+
+	outputPath := filepath.Join(t.TempDir(), "out.mp4")
+	err := NewHTTPMuxer(server.URL).Mux(context.Background(), Input{
+		VideoPath: writeTempFile(t, "video.mp4", "video-bytes"),
+		AudioPath: writeTempFile(t, "audio.mp3", "audio-bytes"),
+		Options:   video.SyncOptions{AudioLanguage: "deu", Audio: video.AudioEncoding{Channels: 2}},
+	}, outputPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(outputPath)
+	if string(data) != "dubbed-video" {
+		t.Errorf("expected the worker's video, got %q", data)
+	}
+}
+
+func TestHTTPMuxer_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewHTTPMuxer(server.URL).Mux(context.Background(), Input{
+		VideoPath: writeTempFile(t, "video.mp4", "video-bytes"),
+		AudioPath: writeTempFile(t, "audio.mp3", "audio-bytes"),
+	}, filepath.Join(t.TempDir(), "out.mp4"))
+	if !transient.IsTransient(err) {
+		t.Errorf("expected a transient error, got %v", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(context.Background(), BackendHTTP, Options{}); err == nil {
+		t.Error("expected error for http without an endpoint")
+	}
+	if _, err := New(context.Background(), BackendTranscoder, Options{Project: "p"}); err == nil {
+		t.Error("expected error for transcoder without a staging bucket")
+	}
+	if _, err := New(context.Background(), "gpu", Options{}); err == nil {
+		t.Error("expected error for an unknown backend")
+	}
+	muxer, err := New(context.Background(), BackendHTTP, Options{Endpoint: "http://worker"})
+	if err != nil || muxer.Name() != BackendHTTP {
+		t.Errorf("expected http muxer, got %v, %v", muxer, err)
+	}
+}
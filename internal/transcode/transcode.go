@@ -0,0 +1,63 @@
+package transcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sinouw/multilingual-video-processor/internal/storage"
+	"github.com/sinouw/multilingual-video-processor/internal/video"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// Supported remote mux backends; local ffmpeg is used when none is configured
+const (
+	BackendHTTP       = "http"
+	BackendTranscoder = "transcoder"
+)
+
+// ErrUnsupported is returned when a backend cannot apply the mux options, so the caller muxes locally instead
+var ErrUnsupported = errors.New("mux options not supported by the transcode backend")
+
+// Input is a dubbed video to mux: the source video, its new audio and the local mux options
+type Input struct {
+	VideoPath string
+	AudioPath string
+	Video     *models.VideoStream // Probed source video stream, used to match its frame rate and size
+	Options   video.SyncOptions
+}
+
+// Muxer replaces the audio of a video with the dubbed audio away from the instance, for videos too large to mux locally
+type Muxer interface {
+	// Name returns the backend name (http or transcoder)
+	Name() string
+	// Mux writes the dubbed video to outputPath, or returns ErrUnsupported when the options need a local mux
+	Mux(ctx context.Context, input Input, outputPath string) error
+}
+
+// Options configure the remote mux backends
+type Options struct {
+	Endpoint string          // Remote worker URL (http)
+	Project  string          // Google Cloud project running the jobs (transcoder)
+	Location string          // Transcoder API region (transcoder)
+	Bucket   string          // Bucket staging the inputs and output of Transcoder jobs (transcoder)
+	Storage  storage.Storage // Storage of the staging bucket (transcoder)
+}
+
+// New creates the muxer for a backend
+func New(ctx context.Context, backend string, opts Options) (Muxer, error) {
+	switch backend {
+	case BackendHTTP:
+		if opts.Endpoint == "" {
+			return nil, fmt.Errorf("endpoint is required for transcode backend %s", BackendHTTP)
+		}
+		return NewHTTPMuxer(opts.Endpoint), nil
+	case BackendTranscoder:
+		if opts.Bucket == "" || opts.Storage == nil {
+			return nil, fmt.Errorf("a staging bucket is required for transcode backend %s", BackendTranscoder)
+		}
+		return NewTranscoderMuxer(ctx, opts.Project, opts.Location, opts.Bucket, opts.Storage)
+	default:
+		return nil, fmt.Errorf("unsupported transcode backend: %s", backend)
+	}
+}
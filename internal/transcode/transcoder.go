@@ -0,0 +1,318 @@
+package transcode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/text/language"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+
+	"github.com/sinouw/multilingual-video-processor/internal/storage"
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+	"github.com/sinouw/multilingual-video-processor/internal/utils"
+)
+
+const (
+	// DefaultLocation is the Transcoder API region used when none is configured
+	DefaultLocation = "us-central1"
+
+	// defaultPollInterval is how often a running Transcoder job is checked
+	defaultPollInterval = 5 * time.Second
+
+	// cloudPlatformScope is the OAuth scope required by the Transcoder API
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+	// outputFileName is the name of the dubbed video written by a Transcoder job
+	outputFileName = "dubbed.mp4"
+)
+
+// TranscoderMuxer runs the mux as a GCP Transcoder API job, which re-encodes the video with H.264
+// The inputs are staged in a bucket and the job's output is downloaded, then all staged objects are deleted.
+// Background music mixes, rotated videos and container tags (Metadata) are left to the local mux; the audio
+// language is set on the dubbed audio stream.
+type TranscoderMuxer struct {
+	Project      string
+	Location     string
+	Bucket       string
+	Endpoint     string        // Overrides the API base URL (used in tests)
+	PollInterval time.Duration // How often the job state is read
+	storage      storage.Storage
+	client       *http.Client
+}
+
+// NewTranscoderMuxer creates a Transcoder API muxer staging files in bucket
+// Uses the credentials file if configured, otherwise default credentials
+func NewTranscoderMuxer(ctx context.Context, project string, location string, bucket string, store storage.Storage, opts ...option.ClientOption) (*TranscoderMuxer, error) {
+	if project == "" {
+		return nil, fmt.Errorf("the Transcoder API requires a Google Cloud project")
+	}
+	if location == "" {
+		location = DefaultLocation
+	}
+	if credentialsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); credentialsPath != "" && len(opts) == 0 {
+		opts = append(opts, option.WithCredentialsFile(credentialsPath))
+	}
+	opts = append(opts, option.WithScopes(cloudPlatformScope))
+
+	httpClient, _, err := htransport.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Transcoder client: %w", err)
+	}
+
+	return &TranscoderMuxer{
+		Project:      project,
+		Location:     location,
+		Bucket:       bucket,
+		Endpoint:     "https://transcoder.googleapis.com",
+		PollInterval: defaultPollInterval,
+		storage:      store,
+		client:       httpClient,
+	}, nil
+}
+
+// Name implements Muxer interface
+func (m *TranscoderMuxer) Name() string {
+	return BackendTranscoder
+}
+
+// transcoderJob is the subset of a Transcoder API job read back while polling
+type transcoderJob struct {
+	Name  string `json:"name"`
+	State string `json:"state"` // PENDING, RUNNING, SUCCEEDED or FAILED
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Mux implements Muxer interface
+func (m *TranscoderMuxer) Mux(ctx context.Context, input Input, outputPath string) error {
+	if input.Options.BackgroundPath != "" || input.Options.Rotation != 0 || len(input.Options.Metadata) > 0 {
+		return ErrUnsupported
+	}
+
+	prefix := "transcode/" + utils.GenerateUUID() + "/"
+	videoObject := prefix + "video" + filepath.Ext(input.VideoPath)
+	audioObject := prefix + "audio" + filepath.Ext(input.AudioPath)
+	defer m.cleanup(prefix, videoObject, audioObject)
+
+	if err := m.storage.Upload(ctx, m.Bucket, videoObject, input.VideoPath); err != nil {
+		return fmt.Errorf("failed to stage video: %w", err)
+	}
+	if err := m.storage.Upload(ctx, m.Bucket, audioObject, input.AudioPath); err != nil {
+		return fmt.Errorf("failed to stage audio: %w", err)
+	}
+
+	job, err := m.createJob(ctx, jobRequest(input, m.gcsURI(prefix), m.gcsURI(videoObject), m.gcsURI(audioObject)))
+	if err != nil {
+		return err
+	}
+	slog.Info("Transcoder job created", "job", job.Name)
+
+	if err := m.waitForJob(ctx, job.Name); err != nil {
+		return err
+	}
+
+	localPath, err := m.storage.Download(ctx, m.Bucket, prefix+outputFileName)
+	if err != nil {
+		return fmt.Errorf("failed to download dubbed video: %w", err)
+	}
+	if err := os.Rename(localPath, outputPath); err != nil {
+		os.Remove(localPath)
+		return fmt.Errorf("failed to move dubbed video: %w", err)
+	}
+	return nil
+}
+
+// createJob submits a Transcoder job
+func (m *TranscoderMuxer) createJob(ctx context.Context, body map[string]interface{}) (*transcoderJob, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Transcoder job: %w", err)
+	}
+	url := fmt.Sprintf("%s/v1/projects/%s/locations/%s/jobs", m.Endpoint, m.Project, m.Location)
+	return m.do(ctx, http.MethodPost, url, data)
+}
+
+// waitForJob polls a Transcoder job until it succeeds, fails or ctx ends
+func (m *TranscoderMuxer) waitForJob(ctx context.Context, name string) error {
+	ticker := time.NewTicker(m.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("transcode cancelled: %w", ctx.Err())
+		case <-ticker.C:
+		}
+
+		job, err := m.do(ctx, http.MethodGet, m.Endpoint+"/v1/"+name, nil)
+		if err != nil {
+			return err
+		}
+		switch job.State {
+		case "SUCCEEDED":
+			return nil
+		case "FAILED":
+			message := "unknown error"
+			if job.Error != nil {
+				message = job.Error.Message
+			}
+			return fmt.Errorf("Transcoder job failed: %s", message)
+		}
+	}
+}
+
+// do sends a Transcoder API request and decodes the job in the response
+func (m *TranscoderMuxer) do(ctx context.Context, method string, url string, body []byte) (*transcoderJob, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Transcoder request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Transcoder request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Transcoder response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &transient.HTTPError{Service: "Transcoder", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var job transcoderJob
+	if err := json.Unmarshal(respBody, &job); err != nil {
+		return nil, fmt.Errorf("failed to parse Transcoder response: %w", err)
+	}
+	return &job, nil
+}
+
+// cleanup deletes the staged inputs and the job output, even when the job was cancelled
+func (m *TranscoderMuxer) cleanup(prefix string, objects ...string) {
+	ctx := context.Background()
+	for _, object := range append(objects, prefix+outputFileName) {
+		if exists, err := m.storage.Exists(ctx, m.Bucket, object); err != nil || !exists {
+			continue
+		}
+		if err := m.storage.Delete(ctx, m.Bucket, object); err != nil {
+			slog.Warn("Failed to delete staged transcode object", "bucket", m.Bucket, "path", object, "error", err)
+		}
+	}
+}
+
+// gcsURI returns the gs:// URI of an object in the staging bucket
+func (m *TranscoderMuxer) gcsURI(object string) string {
+	return "gs://" + m.Bucket + "/" + object
+}
+
+// jobRequest builds a Transcoder job muxing the video of one input with the audio of the other into an MP4
+// The video is re-encoded at the source frame rate and size; the dubbed audio is encoded as AAC
+// with the requested sample rate, channels and bitrate, mono speech being copied to every channel.
+func jobRequest(input Input, outputURI string, videoURI string, audioURI string) map[string]interface{} {
+	frameRate, height := 30.0, 0
+	if input.Video != nil {
+		height = input.Video.Height
+		if input.Video.FrameRate > 0 && input.Video.FrameRate <= 120 {
+			frameRate = input.Video.FrameRate
+		}
+	}
+
+	audio := input.Options.Audio
+	channels, sampleRate, bitrate := 1, 48000, 192
+	if audio.Channels > 0 {
+		channels = audio.Channels
+	}
+	if audio.SampleRate > 0 {
+		sampleRate = audio.SampleRate
+	}
+	if audio.Bitrate > 0 {
+		bitrate = audio.Bitrate
+	}
+	audioStream := map[string]interface{}{
+		"codec":           "aac",
+		"bitrateBps":      bitrate * 1000,
+		"channelCount":    channels,
+		"sampleRateHertz": sampleRate,
+	}
+	// The Transcoder API takes BCP-47 tags, so the ISO 639-2 code is shortened where a two-letter code exists
+	if base, err := language.ParseBase(input.Options.AudioLanguage); err == nil {
+		audioStream["languageCode"] = base.String()
+	}
+	mapping := make([]map[string]interface{}, channels)
+	for channel := range mapping {
+		mapping[channel] = map[string]interface{}{
+			"atomKey":       "atom0",
+			"inputKey":      "audio",
+			"inputTrack":    0,
+			"inputChannel":  0,
+			"outputChannel": channel,
+		}
+	}
+	audioStream["mapping"] = mapping
+
+	return map[string]interface{}{
+		"outputUri": outputURI,
+		"config": map[string]interface{}{
+			"inputs": []map[string]interface{}{
+				{"key": "video", "uri": videoURI},
+				{"key": "audio", "uri": audioURI},
+			},
+			"editList": []map[string]interface{}{
+				{"key": "atom0", "inputs": []string{"video", "audio"}},
+			},
+			"elementaryStreams": []map[string]interface{}{
+				{
+					"key": "video-stream",
+					"videoStream": map[string]interface{}{
+						"h264": map[string]interface{}{
+							"frameRate":  frameRate,
+							"bitrateBps": videoBitrate(height),
+						},
+					},
+				},
+				{
+					"key":         "audio-stream",
+					"audioStream": audioStream,
+				},
+			},
+			"muxStreams": []map[string]interface{}{
+				{
+					"key":               "dubbed",
+					"container":         "mp4",
+					"fileName":          outputFileName,
+					"elementaryStreams": []string{"video-stream", "audio-stream"},
+				},
+			},
+		},
+	}
+}
+
+// videoBitrate returns the H.264 bitrate in bits per second for a video height
+func videoBitrate(height int) int {
+	switch {
+	case height >= 2160:
+		return 35_000_000
+	case height >= 1440:
+		return 16_000_000
+	case height >= 1080:
+		return 8_000_000
+	case height >= 720:
+		return 5_000_000
+	default:
+		return 2_500_000
+	}
+}
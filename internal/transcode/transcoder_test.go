@@ -0,0 +1,163 @@
+package transcode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/option"
+
+	"github.com/sinouw/multilingual-video-processor/internal/storage"
+	"github.com/sinouw/multilingual-video-processor/internal/video"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func newTestTranscoder(t *testing.T, endpoint string) (*TranscoderMuxer, *storage.LocalStorage) {
+	t.Helper()
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	muxer, err := NewTranscoderMuxer(context.Background(), "my-project", "", "staging", store, option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	muxer.Endpoint = endpoint
+	muxer.PollInterval = time.Millisecond
+	return muxer, store
+}
+
+func TestTranscoderMuxer_Mux(t *testing.T) {
+	var store *storage.LocalStorage
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/projects/my-project/locations/us-central1/jobs":
+			var body struct {
+				OutputURI string `json:"outputUri"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			// The job writes its output next to the staged inputs
+			prefix := strings.TrimPrefix(body.OutputURI, "gs://staging/")
+			store.UploadBytes(r.Context(), "staging", prefix+outputFileName, []byte("dubbed-video"), "video/mp4")
+			w.Write([]byte(`{"name":"projects/my-project/locations/us-central1/jobs/job-1","state":"PENDING"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/projects/my-project/locations/us-central1/jobs/job-1":
+			polls++
+			state := "RUNNING"
+			if polls > 1 {
+				state = "SUCCEEDED"
+			}
+			w.Write([]byte(`{"name":"projects/my-project/locations/us-central1/jobs/job-1","state":"` + state + `"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	muxer, local := newTestTranscoder(t, server.URL)
+	store = local
+
+	outputPath := filepath.Join(t.TempDir(), "out.mp4")
+	err := muxer.Mux(context.Background(), Input{
+		VideoPath: writeTempFile(t, "video.mp4", "video-bytes"),
+		AudioPath: writeTempFile(t, "audio.mp3", "audio-bytes"),
+		Video:     &models.VideoStream{Height: 2160, FrameRate: 25},
+	}, outputPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(outputPath)
+	if string(data) != "dubbed-video" {
+		t.Errorf("expected the job's output, got %q", data)
+	}
+	if polls != 2 {
+		t.Errorf("expected 2 polls, got %d", polls)
+	}
+}
+
+func TestTranscoderMuxer_JobFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"name":"projects/my-project/locations/us-central1/jobs/job-1","state":"PENDING"}`))
+			return
+		}
+		w.Write([]byte(`{"name":"projects/my-project/locations/us-central1/jobs/job-1","state":"FAILED","error":{"message":"invalid input"}}`))
+	}))
+	defer server.Close()
+
+	muxer, _ := newTestTranscoder(t, server.URL)
+	err := muxer.Mux(context.Background(), Input{
+		VideoPath: writeTempFile(t, "video.mp4", "video-bytes"),
+		AudioPath: writeTempFile(t, "audio.mp3", "audio-bytes"),
+	}, filepath.Join(t.TempDir(), "out.mp4"))
+	if err == nil || !strings.Contains(err.Error(), "invalid input") {
+		t.Errorf("expected job failure, got %v", err)
+	}
+}
+
+func TestTranscoderMuxer_UnsupportedOptions(t *testing.T) {
+	muxer, _ := newTestTranscoder(t, "http://unused")
+	err := muxer.Mux(context.Background(), Input{Options: video.SyncOptions{BackgroundPath: "/tmp/music.wav"}}, "out.mp4")
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported for a background mix, got %v", err)
+	}
+
+	err = muxer.Mux(context.Background(), Input{Options: video.SyncOptions{Metadata: map[string]string{"title": "Demo"}}}, "out.mp4")
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported for container tags, got %v", err)
+	}
+}
+
+func TestJobRequest(t *testing.T) {
+	body := jobRequest(Input{
+		Video:   &models.VideoStream{Height: 1080, FrameRate: 29.97},
+		Options: video.SyncOptions{AudioLanguage: "ara", Audio: video.AudioEncoding{Channels: 2, SampleRate: 44100}},
+	}, "gs://staging/out/", "gs://staging/video.mp4", "gs://staging/audio.mp3")
+
+	data, _ := json.Marshal(body)
+	var job struct {
+		Config struct {
+			ElementaryStreams []struct {
+				VideoStream *struct {
+					H264 struct {
+						FrameRate  float64 `json:"frameRate"`
+						BitrateBps int     `json:"bitrateBps"`
+					} `json:"h264"`
+				} `json:"videoStream"`
+				AudioStream *struct {
+					ChannelCount    int    `json:"channelCount"`
+					SampleRateHertz int    `json:"sampleRateHertz"`
+					BitrateBps      int    `json:"bitrateBps"`
+					LanguageCode    string `json:"languageCode"`
+					Mapping         []struct {
+						InputKey      string `json:"inputKey"`
+						OutputChannel int    `json:"outputChannel"`
+					} `json:"mapping"`
+				} `json:"audioStream"`
+			} `json:"elementaryStreams"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(data, &job); err != nil {
+		t.Fatalf("failed to decode job: %v", err)
+	}
+
+	videoStream := job.Config.ElementaryStreams[0].VideoStream
+	if videoStream.H264.FrameRate != 29.97 || videoStream.H264.BitrateBps != 8_000_000 {
+		t.Errorf("unexpected video stream: %+v", videoStream.H264)
+	}
+	audioStream := job.Config.ElementaryStreams[1].AudioStream
+	if audioStream.ChannelCount != 2 || audioStream.SampleRateHertz != 44100 || audioStream.BitrateBps != 192000 || audioStream.LanguageCode != "ar" {
+		t.Errorf("unexpected audio stream: %+v", audioStream)
+	}
+	if len(audioStream.Mapping) != 2 || audioStream.Mapping[1].InputKey != "audio" || audioStream.Mapping[1].OutputChannel != 1 {
+		t.Errorf("expected mono speech mapped to both channels, got %+v", audioStream.Mapping)
+	}
+}
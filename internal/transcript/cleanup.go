@@ -0,0 +1,35 @@
+package transcript
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Cleanup modes applied to speech-to-text transcripts before translation (TRANSCRIPT_CLEANUP)
+const (
+	CleanupNone        = "none"        // Use the transcript as recognized
+	CleanupPunctuation = "punctuation" // Ask Speech-to-Text to insert punctuation
+	CleanupLLM         = "llm"         // Also restore punctuation and casing with the LLM translation provider
+)
+
+// SameWords reports whether two texts contain the same words in the same order, ignoring case and punctuation
+// A cleaned-up transcript must pass this check, so the cleanup cannot change what was said.
+func SameWords(a string, b string) bool {
+	wordsA, wordsB := words(a), words(b)
+	if len(wordsA) != len(wordsB) {
+		return false
+	}
+	for i := range wordsA {
+		if wordsA[i] != wordsB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// words splits text into lowercase words at every character that is not a letter or digit
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
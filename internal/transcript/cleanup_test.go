@@ -0,0 +1,26 @@
+package transcript
+
+import "testing"
+
+func TestSameWords(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{"punctuation and casing", "so we deployed it to kubernetes yesterday it went well", "So, we deployed it to Kubernetes yesterday. It went well!", true},
+		{"non-latin script", "привет как дела", "Привет! Как дела?", true},
+		{"changed word", "we deployed it yesterday", "We shipped it yesterday.", false},
+		{"dropped word", "um we deployed it", "We deployed it.", false},
+		{"added word", "we deployed it", "We deployed it, finally.", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SameWords(tt.a, tt.b); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package transcript
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sinouw/multilingual-video-processor/internal/subtitles"
+	"github.com/sinouw/multilingual-video-processor/internal/translation"
+)
+
+// Policies applied to transcripts longer than the configured maximum (TRANSCRIPT_LIMIT_POLICY)
+const (
+	PolicyFail      = "fail"      // Fail the job with ERR_TRANSCRIPT_TOO_LONG
+	PolicyTruncate  = "truncate"  // Keep the leading sentences (or subtitle cues) that fit
+	PolicySummarize = "summarize" // Condense the transcript with the LLM translation provider
+)
+
+// Length returns the length of text in characters
+func Length(text string) int {
+	return utf8.RuneCountInString(text)
+}
+
+// Truncate shortens text to at most maxChars characters, cutting after the last whole sentence that fits
+// A first sentence longer than maxChars is cut at a word boundary, or mid-word as a last resort.
+func Truncate(text string, maxChars int) string {
+	if maxChars <= 0 || Length(text) <= maxChars {
+		return text
+	}
+
+	var kept []string
+	length := 0
+	for _, sentence := range translation.SplitSentences(text) {
+		added := Length(sentence)
+		if len(kept) > 0 {
+			added++ // Joining space
+		}
+		if length+added > maxChars {
+			break
+		}
+		kept = append(kept, sentence)
+		length += added
+	}
+	if len(kept) > 0 {
+		return strings.Join(kept, " ")
+	}
+
+	runes := []rune(text)[:maxChars]
+	if cut := strings.LastIndexFunc(string(runes), isSpace); cut > 0 {
+		return strings.TrimSpace(string(runes)[:cut])
+	}
+	return string(runes)
+}
+
+// Chunk splits text into consecutive parts of at most maxChars characters, so each fits one LLM call
+// Parts end after whole sentences; a sentence longer than maxChars (e.g. an unpunctuated transcript) is split at
+// word boundaries, and a single word longer than maxChars is a part of its own.
+func Chunk(text string, maxChars int) []string {
+	if maxChars <= 0 || Length(text) <= maxChars {
+		return []string{text}
+	}
+
+	var pieces []string
+	for _, sentence := range translation.SplitSentences(text) {
+		if Length(sentence) <= maxChars {
+			pieces = append(pieces, sentence)
+			continue
+		}
+		pieces = append(pieces, pack(strings.Fields(sentence), maxChars)...)
+	}
+	return pack(pieces, maxChars)
+}
+
+// pack joins consecutive parts with spaces into chunks of at most maxChars characters
+// A part longer than maxChars is a chunk of its own.
+func pack(parts []string, maxChars int) []string {
+	var chunks []string
+	var current strings.Builder
+	length := 0
+	for _, part := range parts {
+		if length > 0 && length+1+Length(part) > maxChars {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			length = 0
+		}
+		if length > 0 {
+			current.WriteByte(' ')
+			length++
+		}
+		current.WriteString(part)
+		length += Length(part)
+	}
+	if length > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// TruncateCues keeps the leading cues whose joined text fits in maxChars characters
+func TruncateCues(cues []subtitles.Cue, maxChars int) []subtitles.Cue {
+	if maxChars <= 0 {
+		return cues
+	}
+
+	length := 0
+	for i, cue := range cues {
+		added := Length(cue.Text)
+		if i > 0 {
+			added++ // Joining space, as in subtitles.Text
+		}
+		if length+added > maxChars {
+			return cues[:i]
+		}
+		length += added
+	}
+	return cues
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\n' || r == '\t'
+}
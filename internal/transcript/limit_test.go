@@ -0,0 +1,82 @@
+package transcript
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/internal/subtitles"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxChars int
+		want     string
+	}{
+		{"within limit", "Hello world. Bye.", 50, "Hello world. Bye."},
+		{"unlimited", "Hello world. Bye.", 0, "Hello world. Bye."},
+		{"whole sentences", "Hello world. How are you? Fine!", 26, "Hello world. How are you?"},
+		{"first sentence too long", "One two three four five.", 12, "One two"},
+		{"single long word", "Supercalifragilistic", 5, "Super"},
+		{"multibyte", "Привет мир. Пока.", 12, "Привет мир."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.text, tt.maxChars)
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+			if tt.maxChars > 0 && Length(got) > tt.maxChars {
+				t.Errorf("expected at most %d characters, got %d", tt.maxChars, Length(got))
+			}
+		})
+	}
+}
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxChars int
+		want     []string
+	}{
+		{"within limit", "Hello world. Bye.", 50, []string{"Hello world. Bye."}},
+		{"unlimited", "Hello world. Bye.", 0, []string{"Hello world. Bye."}},
+		{"whole sentences", "Hello world. How are you? Fine!", 26, []string{"Hello world. How are you?", "Fine!"}},
+		{"unpunctuated", "one two three four five", 10, []string{"one two", "three four", "five"}},
+		{"single long word", "Supercalifragilistic is long", 5, []string{"Supercalifragilistic", "is", "long"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Chunk(tt.text, tt.maxChars)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+			if !SameWords(tt.text, strings.Join(got, " ")) {
+				t.Errorf("expected the chunks to keep every word, got %q", got)
+			}
+		})
+	}
+}
+
+func TestTruncateCues(t *testing.T) {
+	cues := []subtitles.Cue{
+		{Start: 0, End: 2, Text: "Hello there."},
+		{Start: 2, End: 4, Text: "How are you?"},
+		{Start: 4, End: 6, Text: "Fine."},
+	}
+
+	if got := TruncateCues(cues, 25); len(got) != 2 {
+		t.Errorf("expected 2 cues, got %d", len(got))
+	}
+	if got := TruncateCues(cues, 5); len(got) != 0 {
+		t.Errorf("expected no cues, got %d", len(got))
+	}
+	if got := TruncateCues(cues, 0); len(got) != 3 {
+		t.Errorf("expected all cues when unlimited, got %d", len(got))
+	}
+}
@@ -0,0 +1,49 @@
+package transient
+
+import (
+	"context"
+	"errors"
+)
+
+// PermanentError marks a failure that cannot succeed on retry, such as invalid input or a missing object
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps err so retry loops give up on it immediately; nil stays nil
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent reports whether err or any error it wraps was marked permanent
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+// IsRetryable reports whether another attempt may succeed after err
+// Permanent errors, cancelled or expired contexts and non-transient provider statuses (e.g. HTTP 400)
+// are not retryable; other errors, such as network failures, are.
+func IsRetryable(err error) bool {
+	if err == nil || IsPermanent(err) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if transient, known := classify(err); known {
+		return transient
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package transient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestPermanent(t *testing.T) {
+	if Permanent(nil) != nil {
+		t.Error("expected nil for a nil error")
+	}
+
+	cause := errors.New("object not found")
+	err := fmt.Errorf("failed to download video: %w", Permanent(cause))
+	if !IsPermanent(err) {
+		t.Error("expected wrapped permanent error to be permanent")
+	}
+	if !errors.Is(err, cause) {
+		t.Error("expected permanent error to unwrap to its cause")
+	}
+	if err.Error() != "failed to download video: object not found" {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+
+	// A permanent marker overrides a transient status code
+	if IsTransient(Permanent(&HTTPError{Service: "openai", StatusCode: 503})) {
+		t.Error("expected permanent HTTP 503 not to be transient")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("connection reset"), true},
+		{"HTTP 400", &HTTPError{StatusCode: 400}, false},
+		{"HTTP 503", &HTTPError{StatusCode: 503}, true},
+		{"permanent", Permanent(errors.New("invalid URL")), false},
+		{"wrapped permanent", fmt.Errorf("download: %w", Permanent(errors.New("not found"))), false},
+		{"context cancelled", fmt.Errorf("upload: %w", context.Canceled), false},
+		{"deadline exceeded", context.DeadlineExceeded, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package transient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// HTTPError is returned by REST providers for non-success responses
+// Quota (429) and server-side (5xx) statuses are transient.
+type HTTPError struct {
+	Service    string // e.g., "Google Translate" or "openai"
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s API error (status %d): %s", e.Service, e.StatusCode, e.Body)
+}
+
+// IsTransient reports whether err was caused by an exhausted quota or a server-side failure
+// Recognizes HTTPError, Google API REST errors and gRPC status errors anywhere in the chain.
+// Errors marked Permanent are never transient.
+func IsTransient(err error) bool {
+	if err == nil || IsPermanent(err) {
+		return false
+	}
+	transient, _ := classify(err)
+	return transient
+}
+
+// classify reports whether err carries a provider status (known) and whether that status is transient
+func classify(err error) (transient bool, known bool) {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return isTransientHTTPStatus(httpErr.StatusCode), true
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return isTransientHTTPStatus(apiErr.Code), true
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		switch grpcErr.GRPCStatus().Code() {
+		case codes.ResourceExhausted, codes.Unavailable, codes.Internal, codes.Aborted, codes.DeadlineExceeded:
+			return true, true
+		}
+		return false, true
+	}
+	return false, false
+}
+
+// isTransientHTTPStatus reports whether an HTTP status means rate limiting or a server error
+func isTransientHTTPStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
@@ -0,0 +1,48 @@
+package transient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("invalid audio"), false},
+		{"context cancelled", context.Canceled, false},
+		{"HTTP 429", &HTTPError{Service: "openai", StatusCode: 429}, true},
+		{"HTTP 503", &HTTPError{Service: "Google Translate", StatusCode: 503}, true},
+		{"HTTP 400", &HTTPError{Service: "Google Translate", StatusCode: 400}, false},
+		{"wrapped HTTP 502", fmt.Errorf("translation failed: %w", &HTTPError{StatusCode: 502}), true},
+		{"Google API 500", &googleapi.Error{Code: 500}, true},
+		{"Google API 403", &googleapi.Error{Code: 403}, false},
+		{"gRPC resource exhausted", fmt.Errorf("speech recognition failed: %w", status.Error(codes.ResourceExhausted, "quota")), true},
+		{"gRPC unavailable", status.Error(codes.Unavailable, "try again"), true},
+		{"gRPC invalid argument", status.Error(codes.InvalidArgument, "bad config"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHTTPError_Message(t *testing.T) {
+	err := &HTTPError{Service: "Google Translate", StatusCode: 503, Body: "backend unavailable"}
+	if got := err.Error(); got != "Google Translate API error (status 503): backend unavailable" {
+		t.Errorf("unexpected message: %s", got)
+	}
+}
@@ -0,0 +1,140 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/outbound"
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+)
+
+const (
+	// ProviderDeepL identifies the DeepL translation backend
+	ProviderDeepL = "deepl"
+
+	DeepLAPIURL     = "https://api.deepl.com/v2/translate"
+	DeepLFreeAPIURL = "https://api-free.deepl.com/v2/translate"
+
+	// deepLMaxTexts is the most texts DeepL accepts in one request
+	deepLMaxTexts = 50
+)
+
+// DeepLTranslator translates text with the DeepL API
+type DeepLTranslator struct {
+	APIKey   string
+	Endpoint string // Overrides the API URL (used in tests)
+	client   *http.Client
+}
+
+// NewDeepLTranslator creates a DeepL translator; free-plan keys (ending in ":fx") use the free API endpoint
+func NewDeepLTranslator(apiKey string) (*DeepLTranslator, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required for translation provider %s", ProviderDeepL)
+	}
+	endpoint := DeepLAPIURL
+	if strings.HasSuffix(apiKey, ":fx") {
+		endpoint = DeepLFreeAPIURL
+	}
+	return &DeepLTranslator{
+		APIKey:   apiKey,
+		Endpoint: endpoint,
+		client:   outbound.NewClient(60 * time.Second),
+	}, nil
+}
+
+// Name implements TranslationService interface
+func (t *DeepLTranslator) Name() string {
+	return ProviderDeepL
+}
+
+// TranslateText implements TranslationService interface
+func (t *DeepLTranslator) TranslateText(ctx context.Context, text string, sourceLanguage string, targetLanguage string) (string, error) {
+	translations, err := t.TranslateBatch(ctx, []string{text}, sourceLanguage, targetLanguage)
+	if err != nil {
+		return "", err
+	}
+	return translations[0], nil
+}
+
+// TranslateBatch translates several texts with DeepL, in requests of at most 50 texts
+// The returned slice has the same order and length as texts
+func (t *DeepLTranslator) TranslateBatch(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+	slog.Info("Translating text with DeepL",
+		"targetLanguage", targetLanguage,
+		"sourceLanguage", sourceLanguage,
+		"segments", len(texts))
+
+	translations := make([]string, 0, len(texts))
+	for start := 0; start < len(texts); start += deepLMaxTexts {
+		end := min(start+deepLMaxTexts, len(texts))
+		chunk, err := t.translateChunk(ctx, texts[start:end], sourceLanguage, targetLanguage)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, chunk...)
+	}
+	return translations, nil
+}
+
+// translateChunk translates up to deepLMaxTexts texts in a single DeepL request
+func (t *DeepLTranslator) translateChunk(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+	body := map[string]interface{}{
+		"text":        texts,
+		"target_lang": strings.ToUpper(targetLanguage),
+	}
+	// DeepL detects the source language when it is omitted
+	if sourceLanguage != "" {
+		body["source_lang"] = strings.ToUpper(sourceLanguage)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+t.APIKey)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &transient.HTTPError{Service: ProviderDeepL, StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var result struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(result.Translations) != len(texts) {
+		return nil, fmt.Errorf("expected %d translations, got %d", len(texts), len(result.Translations))
+	}
+
+	translations := make([]string, len(result.Translations))
+	for i, translation := range result.Translations {
+		translations[i] = translation.Text
+	}
+	return translations, nil
+}
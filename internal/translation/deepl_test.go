@@ -0,0 +1,110 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+)
+
+func TestNewDeepLTranslator(t *testing.T) {
+	if _, err := NewDeepLTranslator(""); err == nil {
+		t.Error("expected error for missing API key")
+	}
+
+	pro, _ := NewDeepLTranslator("key")
+	if pro.Endpoint != DeepLAPIURL {
+		t.Errorf("expected %s, got %s", DeepLAPIURL, pro.Endpoint)
+	}
+	free, _ := NewDeepLTranslator("key:fx")
+	if free.Endpoint != DeepLFreeAPIURL {
+		t.Errorf("expected %s for a free key, got %s", DeepLFreeAPIURL, free.Endpoint)
+	}
+}
+
+func TestDeepLTranslator_TranslateBatch(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "DeepL-Auth-Key secret" {
+			t.Errorf("expected DeepL auth key, got %q", r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"translations":[{"detected_source_language":"EN","text":"Hallo."},{"detected_source_language":"EN","text":"Tschüss."}]}`))
+	}))
+	defer server.Close()
+
+	translator, _ := NewDeepLTranslator("secret")
+	translator.Endpoint = server.URL
+
+	got, err := translator.TranslateBatch(context.Background(), []string{"Hello.", "Bye."}, "en", "de")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"Hallo.", "Tschüss."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if received["target_lang"] != "DE" || received["source_lang"] != "EN" {
+		t.Errorf("expected upper-case language codes, got %v", received)
+	}
+}
+
+func TestDeepLTranslator_TranslateBatchChunks(t *testing.T) {
+	var requests []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var received struct {
+			Text []string `json:"text"`
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		requests = append(requests, len(received.Text))
+
+		var response struct {
+			Translations []map[string]string `json:"translations"`
+		}
+		for _, text := range received.Text {
+			response.Translations = append(response.Translations, map[string]string{"text": "de:" + text})
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	translator, _ := NewDeepLTranslator("secret")
+	translator.Endpoint = server.URL
+
+	texts := make([]string, 120)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("Sentence %d.", i)
+	}
+	got, err := translator.TranslateBatch(context.Background(), texts, "en", "de")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []int{50, 50, 20}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests of %v texts, got %v", want, requests)
+	}
+	if len(got) != len(texts) || got[0] != "de:Sentence 0." || got[119] != "de:Sentence 119." {
+		t.Errorf("expected translations in order, got %d texts", len(got))
+	}
+}
+
+func TestDeepLTranslator_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	translator, _ := NewDeepLTranslator("secret")
+	translator.Endpoint = server.URL
+
+	_, err := translator.TranslateBatch(context.Background(), []string{"Hello."}, "", "de")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !transient.IsTransient(err) {
+		t.Errorf("expected 429 to be transient, got %v", err)
+	}
+}
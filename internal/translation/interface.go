@@ -7,14 +7,30 @@ import (
 // TranslationService defines the interface for translation operations
 // This interface enables mocking for testing and allows alternative implementations
 type TranslationService interface {
+	// Name returns the provider identifier used in capabilities and logs
+	Name() string
+
 	// TranslateText translates text from source language to target language
 	TranslateText(ctx context.Context, text string, sourceLanguage string, targetLanguage string) (string, error)
+
+	// TranslateBatch translates several texts in one call, preserving order
+	TranslateBatch(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error)
 }
 
 // DefaultTranslationService is the default implementation using Google Cloud Translation API
 type DefaultTranslationService struct{}
 
+// Name implements TranslationService interface
+func (s *DefaultTranslationService) Name() string {
+	return ProviderName
+}
+
 // TranslateText implements TranslationService interface
 func (s *DefaultTranslationService) TranslateText(ctx context.Context, text string, sourceLanguage string, targetLanguage string) (string, error) {
 	return TranslateText(ctx, text, sourceLanguage, targetLanguage)
 }
+
+// TranslateBatch implements TranslationService interface
+func (s *DefaultTranslationService) TranslateBatch(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+	return TranslateBatch(ctx, texts, sourceLanguage, targetLanguage)
+}
@@ -0,0 +1,419 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/outbound"
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+)
+
+const (
+	// LLM translation providers selectable via TRANSLATION_PROVIDER
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+
+	OpenAIAPIURL    = "https://api.openai.com/v1/chat/completions"
+	AnthropicAPIURL = "https://api.anthropic.com/v1/messages"
+
+	// AnthropicAPIVersion is sent in the anthropic-version header
+	AnthropicAPIVersion = "2023-06-01"
+
+	// MaxStyleInstructionsLength bounds the per-request style prompt
+	MaxStyleInstructionsLength = 500
+)
+
+// defaultLLMModels are used when no model is configured
+var defaultLLMModels = map[string]string{
+	ProviderOpenAI:    "gpt-4o-mini",
+	ProviderAnthropic: "claude-3-5-haiku-latest",
+}
+
+// LLMTranslator translates text with a chat-based large language model
+// Unlike machine translation it accepts free-form style instructions (tone, register, humour)
+type LLMTranslator struct {
+	Provider string // ProviderOpenAI or ProviderAnthropic
+	APIKey   string
+	Model    string
+	Endpoint string // Overrides the provider API URL (used in tests)
+	client   *http.Client
+}
+
+// NewLLMTranslator creates a translator for the given provider, using its default model if model is empty
+func NewLLMTranslator(provider string, apiKey string, model string) (*LLMTranslator, error) {
+	defaultModel, ok := defaultLLMModels[provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported LLM translation provider: %s", provider)
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required for LLM translation provider %s", provider)
+	}
+	if model == "" {
+		model = defaultModel
+	}
+
+	endpoint := OpenAIAPIURL
+	if provider == ProviderAnthropic {
+		endpoint = AnthropicAPIURL
+	}
+
+	return &LLMTranslator{
+		Provider: provider,
+		APIKey:   apiKey,
+		Model:    model,
+		Endpoint: endpoint,
+		client:   outbound.NewClient(120 * time.Second),
+	}, nil
+}
+
+// Name returns the provider identifier used in capabilities and logs
+func (t *LLMTranslator) Name() string {
+	return t.Provider
+}
+
+// TranslateText implements TranslationService interface
+func (t *LLMTranslator) TranslateText(ctx context.Context, text string, sourceLanguage string, targetLanguage string) (string, error) {
+	translations, err := t.TranslateBatch(ctx, []string{text}, sourceLanguage, targetLanguage)
+	if err != nil {
+		return "", err
+	}
+	return translations[0], nil
+}
+
+// TranslateBatch implements TranslationService interface
+func (t *LLMTranslator) TranslateBatch(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+	return t.TranslateBatchWithStyle(ctx, texts, sourceLanguage, targetLanguage, "")
+}
+
+// TranslateBatchWithStyle translates several texts in one model call, following optional style instructions
+// The returned slice has the same order and length as texts
+func (t *LLMTranslator) TranslateBatchWithStyle(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string, styleInstructions string) ([]string, error) {
+	slog.Info("Translating text with LLM",
+		"provider", t.Provider,
+		"model", t.Model,
+		"targetLanguage", targetLanguage,
+		"sourceLanguage", sourceLanguage,
+		"segments", len(texts),
+		"styled", styleInstructions != "")
+
+	userPrompt, err := buildLLMUserPrompt(texts, sourceLanguage, targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+	systemPrompt := buildLLMSystemPrompt(styleInstructions)
+
+	content, err := t.complete(ctx, systemPrompt, userPrompt)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("translation cancelled: %w", ctx.Err())
+		}
+		return nil, err
+	}
+
+	translations, err := parseLLMTranslations(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(translations) != len(texts) {
+		return nil, fmt.Errorf("expected %d translations, got %d", len(texts), len(translations))
+	}
+
+	slog.Info("Translation completed", "provider", t.Provider, "targetLanguage", targetLanguage)
+	return translations, nil
+}
+
+// Summarize condenses text in its own language to at most maxChars characters
+// The model is asked to stay within the limit; callers should still enforce it.
+func (t *LLMTranslator) Summarize(ctx context.Context, text string, language string, maxChars int) (string, error) {
+	slog.Info("Summarizing text with LLM",
+		"provider", t.Provider,
+		"model", t.Model,
+		"language", language,
+		"textLength", len(text),
+		"maxChars", maxChars)
+
+	languageNote := ""
+	if language != "" && language != "auto" {
+		languageNote = " (" + language + ")"
+	}
+	systemPrompt := fmt.Sprintf("You condense video transcripts for voice-over dubbing. "+
+		"Summarize the transcript in its original language%s, keeping the main points in the original order and first person voice. "+
+		"The summary must not exceed %d characters. Reply with the summary text only.", languageNote, maxChars)
+
+	content, err := t.complete(ctx, systemPrompt, text)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("summarization cancelled: %w", ctx.Err())
+		}
+		return "", err
+	}
+
+	summary := strings.TrimSpace(content)
+	if summary == "" {
+		return "", fmt.Errorf("empty summary returned")
+	}
+	return summary, nil
+}
+
+// Describe writes a short third-person summary of a video from its transcript, in the transcript's language,
+// for catalog descriptions. The model is asked to stay within maxChars characters; callers should still enforce it.
+func (t *LLMTranslator) Describe(ctx context.Context, text string, language string, maxChars int) (string, error) {
+	slog.Info("Describing video with LLM",
+		"provider", t.Provider,
+		"model", t.Model,
+		"language", language,
+		"textLength", len(text),
+		"maxChars", maxChars)
+
+	languageNote := ""
+	if language != "" && language != "auto" {
+		languageNote = " (" + language + ")"
+	}
+	systemPrompt := fmt.Sprintf("You write catalog descriptions of videos from their transcripts. "+
+		"Summarize what the video is about in the language of the transcript%s, in the third person, without quoting it. "+
+		"The summary must not exceed %d characters. Reply with the summary text only.", languageNote, maxChars)
+
+	content, err := t.complete(ctx, systemPrompt, text)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("summarization cancelled: %w", ctx.Err())
+		}
+		return "", err
+	}
+
+	summary := strings.TrimSpace(content)
+	if summary == "" {
+		return "", fmt.Errorf("empty summary returned")
+	}
+	return summary, nil
+}
+
+// Analyze extracts up to maxKeywords keywords and chapter markers from a transcript whose lines start with their
+// time in seconds ("[12.5] text"), returning the model's reply: a JSON object with "keywords" (strings) and
+// "chapters" (objects with "start" in seconds and "title"). Titles and keywords are in the transcript's language.
+func (t *LLMTranslator) Analyze(ctx context.Context, timedTranscript string, language string, maxKeywords int) (string, error) {
+	slog.Info("Analyzing transcript with LLM",
+		"provider", t.Provider,
+		"model", t.Model,
+		"language", language,
+		"textLength", len(timedTranscript))
+
+	languageNote := ""
+	if language != "" && language != "auto" {
+		languageNote = " (" + language + ")"
+	}
+	systemPrompt := fmt.Sprintf("You index videos from their timed transcripts, where each line starts with its time in seconds. "+
+		"Extract at most %d keywords and split the video into chapters where the topic changes, each starting at the time of a line, "+
+		"with a short title. Write keywords and titles in the language of the transcript%s. "+
+		`Reply with a JSON object only: {"keywords": ["..."], "chapters": [{"start": 0, "title": "..."}]}.`, maxKeywords, languageNote)
+
+	content, err := t.complete(ctx, systemPrompt, timedTranscript)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("analysis cancelled: %w", ctx.Err())
+		}
+		return "", err
+	}
+
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", fmt.Errorf("empty analysis returned")
+	}
+	return content, nil
+}
+
+// RestorePunctuation adds punctuation, sentence breaks and casing to an unpunctuated transcript
+// The model is told not to change any words; callers should still verify that it did not.
+func (t *LLMTranslator) RestorePunctuation(ctx context.Context, text string, language string) (string, error) {
+	slog.Info("Restoring transcript punctuation with LLM",
+		"provider", t.Provider,
+		"model", t.Model,
+		"language", language,
+		"textLength", len(text))
+
+	languageNote := ""
+	if language != "" && language != "auto" {
+		languageNote = " (" + language + ")"
+	}
+	systemPrompt := "You clean up speech recognition transcripts for translation and voice-over dubbing. " +
+		"Add punctuation, sentence breaks and correct capitalization to the transcript in its original language" + languageNote + ". " +
+		"Do not add, remove, reorder or correct any words. Reply with the cleaned-up transcript only."
+
+	content, err := t.complete(ctx, systemPrompt, text)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("punctuation restoration cancelled: %w", ctx.Err())
+		}
+		return "", err
+	}
+
+	restored := strings.TrimSpace(content)
+	if restored == "" {
+		return "", fmt.Errorf("empty transcript returned")
+	}
+	return restored, nil
+}
+
+// complete sends one system and user message pair to the provider's chat API and returns the reply text
+func (t *LLMTranslator) complete(ctx context.Context, systemPrompt string, userPrompt string) (string, error) {
+	if t.Provider == ProviderAnthropic {
+		return t.callAnthropic(ctx, systemPrompt, userPrompt)
+	}
+	return t.callOpenAI(ctx, systemPrompt, userPrompt)
+}
+
+// buildLLMSystemPrompt describes the task and output format, appending user style instructions
+func buildLLMSystemPrompt(styleInstructions string) string {
+	var b strings.Builder
+	b.WriteString("You are a professional translator preparing scripts for video dubbing. ")
+	b.WriteString("Translate each segment naturally so it can be spoken aloud, keeping the meaning and roughly the same length. ")
+	b.WriteString("Reply with only a JSON array of strings containing one translation per input segment, in the same order.")
+	if styleInstructions != "" {
+		b.WriteString("\n\nStyle instructions from the requester (apply them to every segment): ")
+		b.WriteString(styleInstructions)
+	}
+	return b.String()
+}
+
+// buildLLMUserPrompt encodes the segments and languages as JSON
+func buildLLMUserPrompt(texts []string, sourceLanguage string, targetLanguage string) (string, error) {
+	if sourceLanguage == "" || sourceLanguage == "auto" {
+		sourceLanguage = "auto-detect"
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"sourceLanguage": sourceLanguage,
+		"targetLanguage": targetLanguage,
+		"segments":       texts,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal prompt: %w", err)
+	}
+	return string(data), nil
+}
+
+// parseLLMTranslations extracts the JSON array of translations, tolerating markdown code fences
+func parseLLMTranslations(content string) ([]string, error) {
+	start := strings.Index(content, "[")
+	end := strings.LastIndex(content, "]")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("LLM response does not contain a JSON array")
+	}
+
+	var translations []string
+	if err := json.Unmarshal([]byte(content[start:end+1]), &translations); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM response: %w", err)
+	}
+	return translations, nil
+}
+
+// callOpenAI sends a chat completion request and returns the assistant message
+func (t *LLMTranslator) callOpenAI(ctx context.Context, systemPrompt string, userPrompt string) (string, error) {
+	body := map[string]interface{}{
+		"model":       t.Model,
+		"temperature": 0.2,
+		"messages": []map[string]string{
+			{"role": "system", "content": systemPrompt},
+			{"role": "user", "content": userPrompt},
+		},
+	}
+	headers := map[string]string{
+		"Authorization": "Bearer " + t.APIKey,
+	}
+
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := t.postJSON(ctx, body, headers, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no choices returned")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// callAnthropic sends a messages request and returns the concatenated text blocks
+func (t *LLMTranslator) callAnthropic(ctx context.Context, systemPrompt string, userPrompt string) (string, error) {
+	body := map[string]interface{}{
+		"model":       t.Model,
+		"max_tokens":  8192,
+		"temperature": 0.2,
+		"system":      systemPrompt,
+		"messages": []map[string]string{
+			{"role": "user", "content": userPrompt},
+		},
+	}
+	headers := map[string]string{
+		"x-api-key":         t.APIKey,
+		"anthropic-version": AnthropicAPIVersion,
+	}
+
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := t.postJSON(ctx, body, headers, &resp); err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("no text content returned")
+	}
+	return text.String(), nil
+}
+
+// postJSON posts a JSON body to the provider endpoint and decodes the JSON response into out
+func (t *LLMTranslator) postJSON(ctx context.Context, body interface{}, headers map[string]string, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &transient.HTTPError{Service: t.Provider, StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
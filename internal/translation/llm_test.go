@@ -0,0 +1,214 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewLLMTranslator(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  string
+		apiKey    string
+		model     string
+		wantModel string
+		wantErr   bool
+	}{
+		{"openai default model", ProviderOpenAI, "key", "", defaultLLMModels[ProviderOpenAI], false},
+		{"anthropic custom model", ProviderAnthropic, "key", "custom-model", "custom-model", false},
+		{"unknown provider", "other", "key", "", "", true},
+		{"missing api key", ProviderOpenAI, "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			translator, err := NewLLMTranslator(tt.provider, tt.apiKey, tt.model)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && translator.Model != tt.wantModel {
+				t.Errorf("expected model %s, got %s", tt.wantModel, translator.Model)
+			}
+		})
+	}
+}
+
+func TestLLMTranslator_OpenAI(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("expected bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"choices":[{"message":{"content":"[\"Hallo.\", \"Tschüss.\"]"}}]}`))
+	}))
+	defer server.Close()
+
+	translator, _ := NewLLMTranslator(ProviderOpenAI, "secret", "")
+	translator.Endpoint = server.URL
+
+	got, err := translator.TranslateBatchWithStyle(context.Background(), []string{"Hello.", "Bye."}, "en", "de", "formal tone")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"Hallo.", "Tschüss."}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	messages := received["messages"].([]interface{})
+	system := messages[0].(map[string]interface{})["content"].(string)
+	if !strings.Contains(system, "formal tone") {
+		t.Errorf("expected style instructions in system prompt, got %q", system)
+	}
+}
+
+func TestLLMTranslator_Anthropic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "secret" || r.Header.Get("anthropic-version") != AnthropicAPIVersion {
+			t.Errorf("missing anthropic headers: %v", r.Header)
+		}
+		w.Write([]byte(`{"content":[{"type":"text","text":"` + "```json\\n[\\\"Привет\\\"]\\n```" + `"}]}`))
+	}))
+	defer server.Close()
+
+	translator, _ := NewLLMTranslator(ProviderAnthropic, "secret", "")
+	translator.Endpoint = server.URL
+
+	got, err := translator.TranslateText(context.Background(), "Hi", "en", "ru")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "Привет" {
+		t.Errorf("expected 'Привет', got %q", got)
+	}
+}
+
+func TestLLMTranslator_CountMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"content":"[\"only one\"]"}}]}`))
+	}))
+	defer server.Close()
+
+	translator, _ := NewLLMTranslator(ProviderOpenAI, "secret", "")
+	translator.Endpoint = server.URL
+
+	if _, err := translator.TranslateBatch(context.Background(), []string{"a", "b"}, "en", "de"); err == nil {
+		t.Error("expected error when the model returns fewer translations")
+	}
+}
+
+func TestLLMTranslator_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	translator, _ := NewLLMTranslator(ProviderOpenAI, "secret", "")
+	translator.Endpoint = server.URL
+
+	if _, err := translator.TranslateText(context.Background(), "Hello", "en", "de"); err == nil {
+		t.Error("expected error for non-200 response")
+	}
+}
+
+func TestLLMTranslator_Summarize(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"content":[{"type":"text","text":"  A short summary.\n"}]}`))
+	}))
+	defer server.Close()
+
+	translator, _ := NewLLMTranslator(ProviderAnthropic, "secret", "")
+	translator.Endpoint = server.URL
+
+	got, err := translator.Summarize(context.Background(), "A very long transcript.", "en", 120)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "A short summary." {
+		t.Errorf("expected trimmed summary, got %q", got)
+	}
+	if system, _ := received["system"].(string); !strings.Contains(system, "120 characters") {
+		t.Errorf("expected character limit in system prompt, got %q", system)
+	}
+}
+
+func TestLLMTranslator_Describe(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"content":[{"type":"text","text":"\nEin Video über Vulkane.  "}]}`))
+	}))
+	defer server.Close()
+
+	translator, _ := NewLLMTranslator(ProviderAnthropic, "secret", "")
+	translator.Endpoint = server.URL
+
+	got, err := translator.Describe(context.Background(), "Heute sprechen wir über Vulkane.", "de", 300)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "Ein Video über Vulkane." {
+		t.Errorf("expected trimmed summary, got %q", got)
+	}
+	system, _ := received["system"].(string)
+	if !strings.Contains(system, "300 characters") || !strings.Contains(system, "(de)") {
+		t.Errorf("expected character limit and language in system prompt, got %q", system)
+	}
+}
+
+func TestLLMTranslator_Analyze(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"choices":[{"message":{"content":" {\"keywords\": [\"sheds\"], \"chapters\": []}\n"}}]}`))
+	}))
+	defer server.Close()
+
+	translator, _ := NewLLMTranslator(ProviderOpenAI, "secret", "")
+	translator.Endpoint = server.URL
+
+	got, err := translator.Analyze(context.Background(), "[0.0] Today we build a shed.\n", "en", 8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != `{"keywords": ["sheds"], "chapters": []}` {
+		t.Errorf("expected trimmed reply, got %q", got)
+	}
+	messages, _ := received["messages"].([]interface{})
+	if len(messages) != 2 || !strings.Contains(fmt.Sprint(messages[0]), "at most 8 keywords") {
+		t.Errorf("expected keyword limit in system prompt, got %v", messages)
+	}
+}
+
+func TestLLMTranslator_RestorePunctuation(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"content":[{"type":"text","text":"Hello there. How are you?\n"}]}`))
+	}))
+	defer server.Close()
+
+	translator, _ := NewLLMTranslator(ProviderAnthropic, "secret", "")
+	translator.Endpoint = server.URL
+
+	got, err := translator.RestorePunctuation(context.Background(), "hello there how are you", "en")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "Hello there. How are you?" {
+		t.Errorf("expected trimmed transcript, got %q", got)
+	}
+	if system, _ := received["system"].(string); !strings.Contains(system, "(en)") {
+		t.Errorf("expected language in system prompt, got %q", system)
+	}
+}
@@ -0,0 +1,282 @@
+package translation
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// BatchTranslateFunc translates several texts in one call, preserving order
+type BatchTranslateFunc func(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error)
+
+// MemoryStore persists translated sentences across jobs, so recurring phrasing (e.g. in a series) is
+// translated once. Entries are kept per namespace, such as the tenant owning the jobs and the providers
+// and style that produced the translations.
+type MemoryStore interface {
+	// Lookup returns the stored translations of sentences, by sentence; sentences without one are left out
+	Lookup(ctx context.Context, namespace string, sourceLanguage string, targetLanguage string, sentences []string) (map[string]string, error)
+
+	// Save stores translations, by source sentence
+	Save(ctx context.Context, namespace string, sourceLanguage string, targetLanguage string, translations map[string]string) error
+}
+
+// Memory is a per-job sentence-level translation memory
+// Repeated sentences (refrains, repeated calls to action) are translated once per
+// target language and reused. It is safe for concurrent use across languages.
+type Memory struct {
+	mu         sync.Mutex
+	entries    map[string]string // key: targetLanguage + "\x00" + sentence
+	hits       int
+	misses     int
+	store      MemoryStore
+	namespace  func(targetLanguage string) string
+	storedHits int
+}
+
+// NewMemory creates an empty translation memory
+func NewMemory() *Memory {
+	return &Memory{
+		entries: make(map[string]string),
+	}
+}
+
+// NewMemoryWithStore creates a translation memory that also reuses, and saves, the sentences of earlier
+// jobs kept in store under the namespace returned for each target language
+func NewMemoryWithStore(store MemoryStore, namespace func(targetLanguage string) string) *Memory {
+	memory := NewMemory()
+	memory.store = store
+	memory.namespace = namespace
+	return memory
+}
+
+// Stats returns the number of sentence lookups served from memory and translated by the provider
+func (m *Memory) Stats() (hits int, misses int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.hits, m.misses
+}
+
+// StoredHits returns how many of the hits were served from the persistent store
+func (m *Memory) StoredHits() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.storedHits
+}
+
+// Translate translates text sentence by sentence, reusing translations of repeated sentences
+// Returns the translated sentences in order and how many of them were reused.
+// Text without repeated or stored sentences is translated in a single call to keep full context; with a
+// store, its sentences are saved afterwards when the translation splits into as many sentences.
+func (m *Memory) Translate(ctx context.Context, text string, sourceLanguage string, targetLanguage string, translate BatchTranslateFunc) ([]string, int, error) {
+	sentences := SplitSentences(text)
+	if hasDuplicates(sentences) {
+		return m.TranslateSegments(ctx, sentences, sourceLanguage, targetLanguage, translate)
+	}
+
+	var found map[string]string
+	if m.store != nil {
+		found = m.lookup(ctx, m.pending(sentences, targetLanguage), sourceLanguage, targetLanguage)
+		if len(found) > 0 {
+			return m.translateSegments(ctx, sentences, found, sourceLanguage, targetLanguage, translate)
+		}
+	}
+
+	translated, err := translate(ctx, []string{text}, sourceLanguage, targetLanguage)
+	if err != nil {
+		return nil, 0, err
+	}
+	if m.store != nil {
+		m.saveAligned(ctx, sentences, translated[0], sourceLanguage, targetLanguage)
+	}
+	return translated, 0, nil
+}
+
+// TranslateSegments translates segments one-to-one (e.g., subtitle cues), reusing translations of repeated segments
+// Returns the translated segments in order and how many of them were reused.
+func (m *Memory) TranslateSegments(ctx context.Context, sentences []string, sourceLanguage string, targetLanguage string, translate BatchTranslateFunc) ([]string, int, error) {
+	return m.translateSegments(ctx, sentences, nil, sourceLanguage, targetLanguage, translate)
+}
+
+// translateSegments implements TranslateSegments; found holds the stored translations already looked up,
+// or is nil to look up the sentences not yet in memory
+func (m *Memory) translateSegments(ctx context.Context, sentences []string, found map[string]string, sourceLanguage string, targetLanguage string, translate BatchTranslateFunc) ([]string, int, error) {
+	// Collect unique sentences that are not yet in memory
+	pending := m.pending(sentences, targetLanguage)
+	queued := make(map[string]bool, len(pending))
+	for _, sentence := range pending {
+		queued[sentence] = true
+	}
+
+	// Sentences translated by earlier jobs are served from the store instead of the provider
+	stored := make(map[string]bool)
+	if m.store != nil && len(pending) > 0 {
+		if found == nil {
+			found = m.lookup(ctx, pending, sourceLanguage, targetLanguage)
+		}
+		m.mu.Lock()
+		var remaining []string
+		for _, sentence := range pending {
+			if translation, ok := found[sentence]; ok {
+				m.entries[memoryKey(targetLanguage, sentence)] = translation
+				stored[sentence] = true
+				continue
+			}
+			remaining = append(remaining, sentence)
+		}
+		m.mu.Unlock()
+		pending = remaining
+	}
+
+	if len(pending) > 0 {
+		translated, err := translate(ctx, pending, sourceLanguage, targetLanguage)
+		if err != nil {
+			return nil, 0, err
+		}
+		m.mu.Lock()
+		for i, sentence := range pending {
+			m.entries[memoryKey(targetLanguage, sentence)] = translated[i]
+		}
+		m.mu.Unlock()
+
+		if m.store != nil {
+			translations := make(map[string]string, len(pending))
+			for i, sentence := range pending {
+				translations[sentence] = translated[i]
+			}
+			m.save(ctx, sourceLanguage, targetLanguage, translations)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]string, len(sentences))
+	reused := 0
+	for i, sentence := range sentences {
+		result[i] = m.entries[memoryKey(targetLanguage, sentence)]
+		if stored[sentence] {
+			// Every occurrence of a stored sentence is a reuse
+			reused++
+			m.hits++
+			m.storedHits++
+		} else if queued[sentence] {
+			// First occurrence is a provider call, later ones are reuses
+			queued[sentence] = false
+			m.misses++
+		} else {
+			reused++
+			m.hits++
+		}
+	}
+
+	return result, reused, nil
+}
+
+// pending returns the unique sentences that are not yet in memory for targetLanguage, in order
+func (m *Memory) pending(sentences []string, targetLanguage string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pending []string
+	queued := make(map[string]bool)
+	for _, sentence := range sentences {
+		if _, ok := m.entries[memoryKey(targetLanguage, sentence)]; !ok && !queued[sentence] {
+			pending = append(pending, sentence)
+			queued[sentence] = true
+		}
+	}
+	return pending
+}
+
+// lookup returns the stored translations of sentences; a failed lookup only costs provider calls
+func (m *Memory) lookup(ctx context.Context, sentences []string, sourceLanguage string, targetLanguage string) map[string]string {
+	if len(sentences) == 0 {
+		return map[string]string{}
+	}
+	found, err := m.store.Lookup(ctx, m.namespace(targetLanguage), sourceLanguage, targetLanguage, sentences)
+	if err != nil {
+		slog.Warn("Translation memory lookup failed", "error", err, "targetLanguage", targetLanguage)
+	}
+	if found == nil {
+		found = map[string]string{}
+	}
+	return found
+}
+
+// save stores translations by source sentence; the translation succeeded, so a failure to store it
+// only costs a later provider call
+func (m *Memory) save(ctx context.Context, sourceLanguage string, targetLanguage string, translations map[string]string) {
+	if err := m.store.Save(ctx, m.namespace(targetLanguage), sourceLanguage, targetLanguage, translations); err != nil {
+		slog.Warn("Translation memory save failed", "error", err, "targetLanguage", targetLanguage)
+	}
+}
+
+// saveAligned stores the sentences of a text translated in one call, pairing them with the sentences of the
+// translation; nothing is stored when the sentence counts differ, since the pairs could not be trusted
+func (m *Memory) saveAligned(ctx context.Context, sentences []string, translated string, sourceLanguage string, targetLanguage string) {
+	translatedSentences := SplitSentences(translated)
+	if len(translatedSentences) != len(sentences) {
+		slog.Debug("Translation not aligned with source sentences, not stored in translation memory",
+			"targetLanguage", targetLanguage, "sentences", len(sentences), "translatedSentences", len(translatedSentences))
+		return
+	}
+
+	translations := make(map[string]string, len(sentences))
+	m.mu.Lock()
+	for i, sentence := range sentences {
+		m.entries[memoryKey(targetLanguage, sentence)] = translatedSentences[i]
+		translations[sentence] = translatedSentences[i]
+	}
+	m.mu.Unlock()
+	m.save(ctx, sourceLanguage, targetLanguage, translations)
+}
+
+// SplitSentences splits text into trimmed sentences on terminal punctuation
+func SplitSentences(text string) []string {
+	var sentences []string
+	var current strings.Builder
+
+	flush := func() {
+		sentence := strings.TrimSpace(current.String())
+		if sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		current.Reset()
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		current.WriteRune(r)
+		if isSentenceTerminator(r) && (i == len(runes)-1 || unicode.IsSpace(runes[i+1])) {
+			flush()
+		}
+	}
+	flush()
+
+	return sentences
+}
+
+func isSentenceTerminator(r rune) bool {
+	switch r {
+	case '.', '!', '?', '。', '！', '？', '؟':
+		return true
+	}
+	return false
+}
+
+func hasDuplicates(sentences []string) bool {
+	seen := make(map[string]bool, len(sentences))
+	for _, sentence := range sentences {
+		if seen[sentence] {
+			return true
+		}
+		seen[sentence] = true
+	}
+	return false
+}
+
+func memoryKey(targetLanguage string, sentence string) string {
+	return targetLanguage + "\x00" + sentence
+}
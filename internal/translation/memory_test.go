@@ -0,0 +1,251 @@
+package translation
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeBatchTranslator upper-cases texts and records each call
+type fakeBatchTranslator struct {
+	calls [][]string
+}
+
+func (f *fakeBatchTranslator) translate(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+	f.calls = append(f.calls, texts)
+	out := make([]string, len(texts))
+	for i, text := range texts {
+		out[i] = targetLanguage + ":" + strings.ToUpper(text)
+	}
+	return out, nil
+}
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Hello world. How are you? Fine!", []string{"Hello world.", "How are you?", "Fine!"}},
+		{"no punctuation here", []string{"no punctuation here"}},
+		{"Version 1.5 is out. Great.", []string{"Version 1.5 is out.", "Great."}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := SplitSentences(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitSentences(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemory_ReusesRepeatedSentences(t *testing.T) {
+	fake := &fakeBatchTranslator{}
+	memory := NewMemory()
+
+	text := "Subscribe now. Great video. Subscribe now."
+	segments, reused, err := memory.Translate(context.Background(), text, "en", "de", fake.translate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"de:SUBSCRIBE NOW.", "de:GREAT VIDEO.", "de:SUBSCRIBE NOW."}
+	if !reflect.DeepEqual(segments, want) {
+		t.Errorf("expected %v, got %v", want, segments)
+	}
+	if reused != 1 {
+		t.Errorf("expected 1 reused segment, got %d", reused)
+	}
+	if len(fake.calls) != 1 || len(fake.calls[0]) != 2 {
+		t.Errorf("expected one provider call with 2 unique sentences, got %v", fake.calls)
+	}
+
+	hits, misses := memory.Stats()
+	if hits != 1 || misses != 2 {
+		t.Errorf("expected 1 hit and 2 misses, got %d hits and %d misses", hits, misses)
+	}
+}
+
+func TestMemory_NoDuplicatesTranslatesWholeText(t *testing.T) {
+	fake := &fakeBatchTranslator{}
+	memory := NewMemory()
+
+	text := "First sentence. Second sentence."
+	segments, reused, err := memory.Translate(context.Background(), text, "en", "ar", fake.translate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(segments) != 1 || reused != 0 {
+		t.Errorf("expected whole text as a single segment, got %v (reused %d)", segments, reused)
+	}
+	if len(fake.calls) != 1 || fake.calls[0][0] != text {
+		t.Errorf("expected a single call with the full text, got %v", fake.calls)
+	}
+}
+
+func TestMemory_LanguagesAreIsolated(t *testing.T) {
+	fake := &fakeBatchTranslator{}
+	memory := NewMemory()
+
+	text := "Hi. Hi."
+	if _, _, err := memory.Translate(context.Background(), text, "en", "de", fake.translate); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	segments, _, err := memory.Translate(context.Background(), text, "en", "ru", fake.translate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if segments[0] != "ru:HI." {
+		t.Errorf("expected russian translation, got %s", segments[0])
+	}
+	if len(fake.calls) != 2 {
+		t.Errorf("expected one provider call per language, got %d", len(fake.calls))
+	}
+}
+
+func TestMemory_TranslateSegmentsKeepsAlignment(t *testing.T) {
+	fake := &fakeBatchTranslator{}
+	memory := NewMemory()
+
+	cues := []string{"Hello there.", "How are", "you?", "Hello there."}
+	segments, reused, err := memory.TranslateSegments(context.Background(), cues, "en", "de", fake.translate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"de:HELLO THERE.", "de:HOW ARE", "de:YOU?", "de:HELLO THERE."}
+	if !reflect.DeepEqual(segments, want) {
+		t.Errorf("expected %v, got %v", want, segments)
+	}
+	if reused != 1 {
+		t.Errorf("expected 1 reused segment, got %d", reused)
+	}
+	if len(fake.calls) != 1 || len(fake.calls[0]) != 3 {
+		t.Errorf("expected one provider call with 3 unique segments, got %v", fake.calls)
+	}
+}
+
+// fakeMemoryStore keeps translations in a map by namespace, languages and sentence
+type fakeMemoryStore struct {
+	entries map[string]string
+}
+
+func (f *fakeMemoryStore) Lookup(ctx context.Context, namespace string, sourceLanguage string, targetLanguage string, sentences []string) (map[string]string, error) {
+	found := make(map[string]string)
+	for _, sentence := range sentences {
+		if translation, ok := f.entries[namespace+"/"+sourceLanguage+"/"+targetLanguage+"/"+sentence]; ok {
+			found[sentence] = translation
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeMemoryStore) Save(ctx context.Context, namespace string, sourceLanguage string, targetLanguage string, translations map[string]string) error {
+	for sentence, translation := range translations {
+		f.entries[namespace+"/"+sourceLanguage+"/"+targetLanguage+"/"+sentence] = translation
+	}
+	return nil
+}
+
+func TestMemory_ReusesStoredSentencesAcrossJobs(t *testing.T) {
+	store := &fakeMemoryStore{entries: make(map[string]string)}
+	fake := &fakeBatchTranslator{}
+
+	// The first job translates the whole text in one call and stores its aligned sentences
+	first := NewMemoryWithStore(store, staticNamespace("tenant-a"))
+	segments, _, err := first.Translate(context.Background(), "Previously on the show. A new day.", "en", "de", fake.translate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(segments) != 1 || len(fake.calls) != 1 || len(fake.calls[0]) != 1 {
+		t.Fatalf("expected the whole text translated in one call, got segments %v and calls %v", segments, fake.calls)
+	}
+	if len(store.entries) != 2 {
+		t.Fatalf("expected two stored sentences, got %v", store.entries)
+	}
+
+	// A later job of the same tenant only sends the new sentence to the provider
+	second := NewMemoryWithStore(store, staticNamespace("tenant-a"))
+	segments, reused, err := second.Translate(context.Background(), "Previously on the show. Another day.", "en", "de", fake.translate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"de:PREVIOUSLY ON THE SHOW.", "de:ANOTHER DAY."}
+	if !reflect.DeepEqual(segments, want) {
+		t.Errorf("expected %v, got %v", want, segments)
+	}
+	if reused != 1 || second.StoredHits() != 1 {
+		t.Errorf("expected 1 sentence from the store, got reused %d and stored hits %d", reused, second.StoredHits())
+	}
+	if last := fake.calls[len(fake.calls)-1]; !reflect.DeepEqual(last, []string{"Another day."}) {
+		t.Errorf("expected only the new sentence to be translated, got %v", last)
+	}
+
+	// Other tenants do not share entries
+	other := NewMemoryWithStore(store, staticNamespace("tenant-b"))
+	if _, _, err := other.Translate(context.Background(), "Previously on the show.", "en", "de", fake.translate); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other.StoredHits() != 0 {
+		t.Errorf("expected no stored hits for another tenant, got %d", other.StoredHits())
+	}
+}
+
+func TestMemory_StoreNamespacePerTargetLanguage(t *testing.T) {
+	store := &fakeMemoryStore{entries: make(map[string]string)}
+	fake := &fakeBatchTranslator{}
+	namespace := func(targetLanguage string) string {
+		// e.g. German is routed to another provider than the other languages
+		if targetLanguage == "de" {
+			return "tenant-a/deepl"
+		}
+		return "tenant-a/google"
+	}
+
+	memory := NewMemoryWithStore(store, namespace)
+	if _, _, err := memory.Translate(context.Background(), "Hello there. Bye.", "en", "de", fake.translate); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := store.entries["tenant-a/deepl/en/de/Hello there."]; !ok {
+		t.Errorf("expected the sentence stored under the German provider namespace, got %v", store.entries)
+	}
+
+	// Another provider or style does not reuse the stored sentence
+	changed := NewMemoryWithStore(store, staticNamespace("tenant-a/llm/formal"))
+	if _, _, err := changed.Translate(context.Background(), "Hello there.", "en", "de", fake.translate); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changed.StoredHits() != 0 {
+		t.Errorf("expected no stored hits under another namespace, got %d", changed.StoredHits())
+	}
+}
+
+func TestMemory_MisalignedTranslationNotStored(t *testing.T) {
+	store := &fakeMemoryStore{entries: make(map[string]string)}
+	merge := func(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+		// The provider merges the two source sentences into one
+		return []string{"Hallo und tschüss."}, nil
+	}
+
+	memory := NewMemoryWithStore(store, staticNamespace("tenant-a"))
+	segments, _, err := memory.Translate(context.Background(), "Hello. Bye.", "en", "de", merge)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(segments, []string{"Hallo und tschüss."}) {
+		t.Errorf("expected the whole translation, got %v", segments)
+	}
+	if len(store.entries) != 0 {
+		t.Errorf("expected nothing stored for a misaligned translation, got %v", store.entries)
+	}
+}
+
+// staticNamespace returns the same store namespace for every target language
+func staticNamespace(namespace string) func(string) string {
+	return func(string) string { return namespace }
+}
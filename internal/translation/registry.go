@@ -0,0 +1,114 @@
+package translation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+// Registry holds the configured translation providers and the provider chain used for each target language
+// The first provider of a chain is the primary; the others are fallbacks tried in order when it fails.
+type Registry struct {
+	providers    map[string]TranslationService
+	defaultChain []string
+	routes       map[string][]string
+}
+
+// NewRegistry creates a registry routing target languages without a route to defaultChain
+func NewRegistry(defaultChain []string, routes map[string][]string) *Registry {
+	return &Registry{
+		providers:    make(map[string]TranslationService),
+		defaultChain: defaultChain,
+		routes:       routes,
+	}
+}
+
+// Register adds a provider under its configuration name (e.g., google, deepl)
+func (r *Registry) Register(name string, service TranslationService) {
+	r.providers[name] = service
+}
+
+// Validate checks that every provider used by a chain is registered
+func (r *Registry) Validate() error {
+	if len(r.defaultChain) == 0 {
+		return fmt.Errorf("default translation provider chain is empty")
+	}
+	for _, chain := range append([][]string{r.defaultChain}, r.routeChains()...) {
+		for _, name := range chain {
+			if r.providers[name] == nil {
+				return fmt.Errorf("translation provider %s is not configured", name)
+			}
+		}
+	}
+	return nil
+}
+
+// Chain returns the provider names tried, in order, for a target language
+func (r *Registry) Chain(targetLanguage string) []string {
+	if chain, ok := r.routes[targetLanguage]; ok && len(chain) > 0 {
+		return chain
+	}
+	return r.defaultChain
+}
+
+// Primary returns the first provider of the default chain
+func (r *Registry) Primary() TranslationService {
+	return r.providers[r.defaultChain[0]]
+}
+
+// Routes returns the provider chain of every target language with its own route
+func (r *Registry) Routes() map[string][]string {
+	return r.routes
+}
+
+// Providers returns the registered providers by configuration name
+func (r *Registry) Providers() map[string]TranslationService {
+	return r.providers
+}
+
+// Router returns a batch translation function that sends each target language to its provider chain
+// wrap builds the function called for a provider (e.g., adding caching); the next provider is tried when one fails.
+func (r *Registry) Router(wrap func(name string, service TranslationService) BatchTranslateFunc) BatchTranslateFunc {
+	funcs := make(map[string]BatchTranslateFunc, len(r.providers))
+	for name, service := range r.providers {
+		funcs[name] = wrap(name, service)
+	}
+
+	return func(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+		chain := r.Chain(targetLanguage)
+		var lastErr error
+		for i, name := range chain {
+			translations, err := funcs[name](ctx, texts, sourceLanguage, targetLanguage)
+			if err == nil {
+				return translations, nil
+			}
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			lastErr = err
+			if i < len(chain)-1 {
+				slog.Warn("Translation provider failed, falling back", "provider", name, "fallback", chain[i+1], "targetLanguage", targetLanguage, "error", err)
+			}
+		}
+		if len(chain) == 1 {
+			return nil, lastErr
+		}
+		return nil, fmt.Errorf("all translation providers failed for %s: %w", targetLanguage, lastErr)
+	}
+}
+
+// routeChains returns the per-language chains in a stable order
+func (r *Registry) routeChains() [][]string {
+	languages := make([]string, 0, len(r.routes))
+	for lang := range r.routes {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+
+	chains := make([][]string, 0, len(languages))
+	for _, lang := range languages {
+		chains = append(chains, r.routes[lang])
+	}
+	return chains
+}
@@ -0,0 +1,110 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeTranslator prefixes texts with its name, or fails when err is set
+type fakeTranslator struct {
+	name  string
+	err   error
+	calls int
+}
+
+func (f *fakeTranslator) Name() string { return f.name }
+
+func (f *fakeTranslator) TranslateText(ctx context.Context, text string, sourceLanguage string, targetLanguage string) (string, error) {
+	translations, err := f.TranslateBatch(ctx, []string{text}, sourceLanguage, targetLanguage)
+	if err != nil {
+		return "", err
+	}
+	return translations[0], nil
+}
+
+func (f *fakeTranslator) TranslateBatch(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	translations := make([]string, len(texts))
+	for i, text := range texts {
+		translations[i] = f.name + ":" + text
+	}
+	return translations, nil
+}
+
+func newTestRegistry(google, deepl *fakeTranslator) *Registry {
+	registry := NewRegistry([]string{"google"}, map[string][]string{"de": {"deepl", "google"}})
+	registry.Register("google", google)
+	registry.Register("deepl", deepl)
+	return registry
+}
+
+func direct(name string, service TranslationService) BatchTranslateFunc {
+	return service.TranslateBatch
+}
+
+func TestRegistry_Routing(t *testing.T) {
+	google, deepl := &fakeTranslator{name: "google"}, &fakeTranslator{name: "deepl"}
+	translate := newTestRegistry(google, deepl).Router(direct)
+
+	got, err := translate(context.Background(), []string{"Hello"}, "en", "de")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"deepl:Hello"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	got, _ = translate(context.Background(), []string{"Hello"}, "en", "ar")
+	if want := []string{"google:Hello"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected unrouted language to use the default chain, got %v", got)
+	}
+}
+
+func TestRegistry_Fallback(t *testing.T) {
+	google, deepl := &fakeTranslator{name: "google"}, &fakeTranslator{name: "deepl", err: errors.New("quota exceeded")}
+	translate := newTestRegistry(google, deepl).Router(direct)
+
+	got, err := translate(context.Background(), []string{"Hello"}, "en", "de")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"google:Hello"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected fallback translation %v, got %v", want, got)
+	}
+	if deepl.calls != 1 || google.calls != 1 {
+		t.Errorf("expected one call per provider, got deepl=%d google=%d", deepl.calls, google.calls)
+	}
+}
+
+func TestRegistry_AllProvidersFail(t *testing.T) {
+	quota := errors.New("quota exceeded")
+	google, deepl := &fakeTranslator{name: "google", err: quota}, &fakeTranslator{name: "deepl", err: errors.New("down")}
+	translate := newTestRegistry(google, deepl).Router(direct)
+
+	_, err := translate(context.Background(), []string{"Hello"}, "en", "de")
+	if !errors.Is(err, quota) || !strings.Contains(err.Error(), "all translation providers failed") {
+		t.Errorf("expected wrapped error of the last provider, got %v", err)
+	}
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	registry := NewRegistry([]string{"google"}, map[string][]string{"de": {"deepl"}})
+	registry.Register("google", &fakeTranslator{name: "google"})
+	if err := registry.Validate(); err == nil {
+		t.Error("expected error for route to an unregistered provider")
+	}
+
+	registry.Register("deepl", &fakeTranslator{name: "deepl"})
+	if err := registry.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if registry.Primary().Name() != "google" {
+		t.Errorf("expected primary google, got %s", registry.Primary().Name())
+	}
+}
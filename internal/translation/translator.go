@@ -10,29 +10,65 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/outbound"
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
 )
 
 const (
 	GoogleTranslateAPIURL = "https://translation.googleapis.com/language/translate/v2"
+
+	// ProviderName identifies this translation backend in capabilities and logs
+	ProviderName = "google-translate"
 )
 
+// googleTranslateURL is the Translation API URL requests are sent to
+var googleTranslateURL = GoogleTranslateAPIURL
+
+// SetGoogleEndpoint sends Google Translate requests to another host such as a fake server (e.g., "http://localhost:9090")
+// Empty restores the API endpoint.
+func SetGoogleEndpoint(endpoint string) {
+	googleTranslateURL = GoogleTranslateAPIURL
+	if endpoint != "" {
+		googleTranslateURL = strings.TrimRight(endpoint, "/") + "/language/translate/v2"
+	}
+}
+
 // TranslateText translates text from source language to target language using Google Cloud Translation API
 func TranslateText(ctx context.Context, text string, sourceLanguage string, targetLanguage string) (string, error) {
+	translations, err := TranslateBatch(ctx, []string{text}, sourceLanguage, targetLanguage)
+	if err != nil {
+		return "", err
+	}
+	return translations[0], nil
+}
+
+// TranslateBatch translates several texts in a single Google Cloud Translation API request
+// The returned slice has the same order and length as texts
+func TranslateBatch(ctx context.Context, texts []string, sourceLanguage string, targetLanguage string) ([]string, error) {
+	textLength := 0
+	for _, text := range texts {
+		textLength += len(text)
+	}
 	slog.Info("Translating text",
 		"targetLanguage", targetLanguage,
 		"sourceLanguage", sourceLanguage,
-		"textLength", len(text))
+		"segments", len(texts),
+		"textLength", textLength)
 
 	apiKey := os.Getenv("GOOGLE_TRANSLATE_API_KEY")
 	if apiKey == "" {
-		return "", fmt.Errorf("Google Translate API key not configured (GOOGLE_TRANSLATE_API_KEY)")
+		return nil, fmt.Errorf("Google Translate API key not configured (GOOGLE_TRANSLATE_API_KEY)")
 	}
 
 	// Prepare request
-	requestURL := fmt.Sprintf("%s?key=%s", GoogleTranslateAPIURL, apiKey)
+	requestURL := fmt.Sprintf("%s?key=%s", googleTranslateURL, apiKey)
 	data := url.Values{}
-	data.Set("q", text)
+	for _, text := range texts {
+		data.Add("q", text)
+	}
 
 	// Set source language - if empty, API will auto-detect
 	if sourceLanguage != "" {
@@ -44,51 +80,56 @@ func TranslateText(ctx context.Context, text string, sourceLanguage string, targ
 
 	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, bytes.NewBufferString(data.Encode()))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	// Send request with timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-	resp, err := client.Do(req)
+	resp, err := outbound.NewClient(30 * time.Second).Do(req)
 	if err != nil {
 		// Check if error is due to context cancellation
 		if ctx.Err() != nil {
-			return "", fmt.Errorf("translation cancelled: %w", ctx.Err())
+			return nil, fmt.Errorf("translation cancelled: %w", ctx.Err())
 		}
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("Google Translate API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, &transient.HTTPError{Service: "Google Translate", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	// Parse response
 	var googleResp GoogleTranslateResponse
 	err = json.Unmarshal(body, &googleResp)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if len(googleResp.Data.Translations) == 0 {
-		return "", fmt.Errorf("no translations returned")
+		return nil, fmt.Errorf("no translations returned")
+	}
+	if len(googleResp.Data.Translations) != len(texts) {
+		return nil, fmt.Errorf("expected %d translations, got %d", len(texts), len(googleResp.Data.Translations))
 	}
 
-	translatedText := googleResp.Data.Translations[0].TranslatedText
+	translations := make([]string, len(googleResp.Data.Translations))
+	translatedLength := 0
+	for i, t := range googleResp.Data.Translations {
+		translations[i] = t.TranslatedText
+		translatedLength += len(t.TranslatedText)
+	}
 	slog.Info("Translation completed",
 		"targetLanguage", targetLanguage,
-		"translatedLength", len(translatedText))
+		"translatedLength", translatedLength)
 
-	return translatedText, nil
+	return translations, nil
 }
 
 // GoogleTranslateResponse represents the response from Google Translate API
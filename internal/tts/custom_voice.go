@@ -0,0 +1,127 @@
+package tts
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Custom voice providers, the prefix of a voiceId
+const (
+	VoiceProviderGoogle     = "google"     // Google Cloud Custom Voice model
+	VoiceProviderElevenLabs = "elevenlabs" // ElevenLabs cloned or designed voice
+)
+
+// ElevenLabsLanguages lists the languages spoken by ElevenLabs voices through the multilingual model
+var ElevenLabsLanguages = []string{
+	"ar", "bg", "cs", "da", "de", "el", "en", "es", "fi", "fil", "fr", "hi", "hr", "id", "it",
+	"ja", "ko", "ms", "nl", "pl", "pt", "ro", "ru", "sk", "sv", "ta", "tr", "uk", "zh",
+}
+
+var (
+	// elevenLabsVoicePattern matches ElevenLabs voice IDs, which are used as a URL path segment
+	elevenLabsVoicePattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+	// googleModelPattern matches Custom Voice model names
+	googleModelPattern = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/models/[^/]+$`)
+)
+
+// CustomVoice is a brand voice cloned or trained with a TTS provider, selected by a request's voiceId
+// voiceId is "elevenlabs:<voice ID>" or "google:<model name>@<locale>", the locale being the language
+// the Custom Voice model was trained in (e.g., "google:projects/p/locations/global/models/brand@en-US").
+type CustomVoice struct {
+	Provider string
+	ID       string // ElevenLabs voice ID or Custom Voice model name
+	Locale   string // Language of a Custom Voice model; ElevenLabs voices speak every ElevenLabsLanguages language
+}
+
+// ParseVoiceID parses a request's voiceId; an empty voiceId returns nil, the language's default voice
+func ParseVoiceID(voiceID string) (*CustomVoice, error) {
+	if voiceID == "" {
+		return nil, nil
+	}
+	provider, id, _ := strings.Cut(voiceID, ":")
+	switch provider {
+	case VoiceProviderElevenLabs:
+		if !elevenLabsVoicePattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid ElevenLabs voice ID: %q", id)
+		}
+		return &CustomVoice{Provider: provider, ID: id}, nil
+	case VoiceProviderGoogle:
+		model, locale, _ := strings.Cut(id, "@")
+		if !googleModelPattern.MatchString(model) {
+			return nil, fmt.Errorf("invalid Custom Voice model: %q (expected projects/{project}/locations/{location}/models/{model})", model)
+		}
+		if locale == "" {
+			return nil, fmt.Errorf("Custom Voice model %s needs the locale it was trained in (e.g., %s@en-US)", model, model)
+		}
+		return &CustomVoice{Provider: provider, ID: model, Locale: locale}, nil
+	default:
+		return nil, fmt.Errorf("unsupported voiceId %q (expected elevenlabs:<voice ID> or google:<model>@<locale>)", voiceID)
+	}
+}
+
+// DefaultVoice names the language's default Google Cloud voice in a TTS_ROUTES chain
+const DefaultVoice = "default"
+
+// ParseVoiceChain parses the voices of a TTS_ROUTES chain for a language, checking each can speak it
+// The default voice is returned as a nil entry.
+func ParseVoiceChain(language string, chain []string) ([]*CustomVoice, error) {
+	voices := make([]*CustomVoice, len(chain))
+	for i, entry := range chain {
+		if entry == DefaultVoice {
+			if GetVoiceConfig(language) == nil {
+				return nil, fmt.Errorf("no default voice for %s", language)
+			}
+			continue
+		}
+		voice, err := ParseVoiceID(entry)
+		if err != nil {
+			return nil, err
+		}
+		if !voice.SupportsLanguage(language) {
+			return nil, fmt.Errorf("voice %s does not support language %s", voice, language)
+		}
+		voices[i] = voice
+	}
+	return voices, nil
+}
+
+// String returns the voice as a voiceId
+func (v *CustomVoice) String() string {
+	if v.Locale != "" {
+		return v.Provider + ":" + v.ID + "@" + v.Locale
+	}
+	return v.Provider + ":" + v.ID
+}
+
+// SupportsLanguage reports whether the voice can speak a target language
+func (v *CustomVoice) SupportsLanguage(language string) bool {
+	base := baseLanguage(language)
+	if v.Provider == VoiceProviderGoogle {
+		return base == baseLanguage(v.Locale)
+	}
+	for _, supported := range ElevenLabsLanguages {
+		if supported == base {
+			return true
+		}
+	}
+	return false
+}
+
+// voiceConfig returns the configuration synthesizing a language in the custom voice
+func (v *CustomVoice) voiceConfig(language string) (*VoiceConfig, error) {
+	if !v.SupportsLanguage(language) {
+		return nil, fmt.Errorf("voice %s does not support language %s", v, language)
+	}
+	if v.Provider == VoiceProviderGoogle {
+		return &VoiceConfig{LanguageCode: v.Locale, CustomModel: v.ID}, nil
+	}
+	return &VoiceConfig{LanguageCode: language, VoiceName: v.ID}, nil
+}
+
+// baseLanguage returns the language of a code without its region (e.g., "en" for "en-US")
+func baseLanguage(code string) string {
+	base, _, _ := strings.Cut(strings.ToLower(code), "-")
+	return base
+}
@@ -0,0 +1,100 @@
+package tts
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseVoiceID(t *testing.T) {
+	tests := []struct {
+		voiceID string
+		want    *CustomVoice
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"elevenlabs:21m00Tcm4TlvDq8ikWAM", &CustomVoice{Provider: VoiceProviderElevenLabs, ID: "21m00Tcm4TlvDq8ikWAM"}, false},
+		{"google:projects/acme/locations/global/models/brand@en-US", &CustomVoice{Provider: VoiceProviderGoogle, ID: "projects/acme/locations/global/models/brand", Locale: "en-US"}, false},
+		{"elevenlabs:", nil, true},
+		{"elevenlabs:../voices", nil, true},
+		{"google:projects/acme/locations/global/models/brand", nil, true}, // No locale
+		{"google:brand@en-US", nil, true},
+		{"polly:Joanna", nil, true},
+		{"21m00Tcm4TlvDq8ikWAM", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVoiceID(tt.voiceID)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVoiceID(%q) error = %v, wantErr %v", tt.voiceID, err, tt.wantErr)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("ParseVoiceID(%q) = %+v, want %+v", tt.voiceID, got, tt.want)
+		}
+		if got != nil && got.String() != tt.voiceID {
+			t.Errorf("expected %q back from String, got %q", tt.voiceID, got.String())
+		}
+	}
+}
+
+func TestCustomVoice_SupportsLanguage(t *testing.T) {
+	cloned := &CustomVoice{Provider: VoiceProviderElevenLabs, ID: "abc"}
+	if !cloned.SupportsLanguage("de") || !cloned.SupportsLanguage("pt-BR") {
+		t.Error("expected ElevenLabs voices to speak German and Brazilian Portuguese")
+	}
+	if cloned.SupportsLanguage("sw") {
+		t.Error("expected ElevenLabs voices not to speak Swahili")
+	}
+
+	trained := &CustomVoice{Provider: VoiceProviderGoogle, ID: "projects/p/locations/l/models/m", Locale: "en-US"}
+	if !trained.SupportsLanguage("en") {
+		t.Error("expected a Custom Voice model to speak its own language")
+	}
+	if trained.SupportsLanguage("de") {
+		t.Error("expected a Custom Voice model to speak only its own language")
+	}
+}
+
+func TestParseVoiceChain(t *testing.T) {
+	voices, err := ParseVoiceChain("de", []string{"elevenlabs:abc", DefaultVoice})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(voices) != 2 || voices[0] == nil || voices[0].ID != "abc" || voices[1] != nil {
+		t.Errorf("expected the ElevenLabs voice then the default voice, got %v", voices)
+	}
+
+	if _, err := ParseVoiceChain("de", []string{"google:projects/p/locations/global/models/brand@en-US"}); err == nil {
+		t.Error("expected error for a voice that cannot speak the language")
+	}
+	if _, err := ParseVoiceChain("de", []string{"polly:hans"}); err == nil {
+		t.Error("expected error for an unknown voice")
+	}
+}
+
+func TestNewSession_CustomVoice(t *testing.T) {
+	previousSynthesizer, previousName, previousElevenLabs := synthesizer, synthesizerName, elevenLabs
+	t.Cleanup(func() {
+		synthesizer, synthesizerName, elevenLabs = previousSynthesizer, previousName, previousElevenLabs
+	})
+	synthesizer, synthesizerName = nil, ProviderName
+	elevenLabs = nil
+
+	voice := &CustomVoice{Provider: VoiceProviderElevenLabs, ID: "abc"}
+	if _, err := newSession(context.Background(), "de", Options{Voice: voice}); err == nil {
+		t.Error("expected error for an ElevenLabs voice without ElevenLabs configured")
+	}
+
+	elevenLabs, _ = NewElevenLabsSynthesizer("secret", "")
+	s, err := newSession(context.Background(), "sv", Options{Voice: voice})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.name != VoiceProviderElevenLabs || s.voiceConfig.VoiceName != "abc" || s.voiceConfig.LanguageCode != "sv" {
+		t.Errorf("expected the ElevenLabs voice for sv, got %s %+v", s.name, s.voiceConfig)
+	}
+
+	if _, err := newSession(context.Background(), "sw", Options{Voice: voice}); err == nil {
+		t.Error("expected error for a language the voice does not speak")
+	}
+}
@@ -0,0 +1,203 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sinouw/multilingual-video-processor/internal/outbound"
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+	"github.com/sinouw/multilingual-video-processor/internal/usage"
+)
+
+const (
+	// ElevenLabsAPIURL is the ElevenLabs API base URL
+	ElevenLabsAPIURL = "https://api.elevenlabs.io"
+
+	// DefaultElevenLabsModel speaks every ElevenLabsLanguages language
+	DefaultElevenLabsModel = "eleven_multilingual_v2"
+
+	// ElevenLabs voice settings accept speeds from 0.7 to 1.2
+	elevenLabsMinSpeed = 0.7
+	elevenLabsMaxSpeed = 1.2
+
+	// elevenLabsMaxBreak is the longest pause a single ElevenLabs break tag may request, in milliseconds
+	elevenLabsMaxBreak = 3000
+)
+
+// elevenLabs synthesizes custom voices of the elevenlabs provider; nil when ElevenLabs is not configured
+var elevenLabs *ElevenLabsSynthesizer
+
+// SetElevenLabs enables ElevenLabs custom voices; nil disables them
+func SetElevenLabs(synthesizer *ElevenLabsSynthesizer) {
+	elevenLabs = synthesizer
+}
+
+// ElevenLabsSynthesizer speaks text in ElevenLabs voices
+// ElevenLabs does not read SSML: the document's speaking rate becomes the voice speed, its pauses become
+// ElevenLabs break tags, and other markup is reduced to the text it speaks.
+type ElevenLabsSynthesizer struct {
+	APIKey   string
+	Model    string
+	Endpoint string // Overrides the API base URL (used in tests)
+	client   *http.Client
+}
+
+// NewElevenLabsSynthesizer creates an ElevenLabs synthesizer using model, or DefaultElevenLabsModel when empty
+func NewElevenLabsSynthesizer(apiKey string, model string) (*ElevenLabsSynthesizer, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required for custom voice provider %s", VoiceProviderElevenLabs)
+	}
+	if model == "" {
+		model = DefaultElevenLabsModel
+	}
+	return &ElevenLabsSynthesizer{
+		APIKey:   apiKey,
+		Model:    model,
+		Endpoint: ElevenLabsAPIURL,
+		client:   outbound.NewClient(120 * time.Second),
+	}, nil
+}
+
+// Synthesize implements SynthesizeFunc, speaking in the voice named by voiceConfig.VoiceName
+func (s *ElevenLabsSynthesizer) Synthesize(ctx context.Context, ssmlText string, voiceConfig *VoiceConfig) ([]byte, error) {
+	// Check context cancellation before making API call
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("TTS generation cancelled: %w", ctx.Err())
+	default:
+	}
+
+	text, ratePercent, err := elevenLabsText(ssmlText)
+	if err != nil {
+		return nil, err
+	}
+	speed := max(elevenLabsMinSpeed, min(float64(ratePercent)/100, elevenLabsMaxSpeed))
+	data, err := json.Marshal(map[string]interface{}{
+		"text":           text,
+		"model_id":       s.Model,
+		"voice_settings": map[string]interface{}{"speed": speed},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/text-to-speech/%s?output_format=mp3_24000_48", s.Endpoint, url.PathEscape(voiceConfig.VoiceName))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "audio/mpeg")
+	req.Header.Set("xi-api-key", s.APIKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("TTS generation cancelled: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	audio, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &transient.HTTPError{Service: VoiceProviderElevenLabs, StatusCode: resp.StatusCode, Body: string(audio)}
+	}
+
+	// ElevenLabs bills the characters of the text
+	usage.FromContext(ctx).AddTTSCharacters(utf8.RuneCountInString(text))
+	return audio, nil
+}
+
+// elevenLabsText converts an SSML document into ElevenLabs text and the speaking rate of its prosody, in percent
+// <sub> is replaced by its alias and pauses longer than elevenLabsMaxBreak are split into several break tags.
+func elevenLabsText(ssmlText string) (string, int, error) {
+	decoder := xml.NewDecoder(strings.NewReader(ssmlText))
+	decoder.Strict = true
+
+	var b strings.Builder
+	ratePercent := 100
+	subDepth := 0
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid SSML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "prosody":
+				if rate, ok := attribute(t, "rate"); ok {
+					if percent, err := strconv.Atoi(strings.TrimSuffix(rate, "%")); err == nil && percent > 0 {
+						ratePercent = percent
+					}
+				}
+			case "break":
+				if value, ok := attribute(t, "time"); ok {
+					writeElevenLabsBreaks(&b, breakMillis(value))
+				}
+			case "sub":
+				if alias, ok := attribute(t, "alias"); ok {
+					b.WriteString(alias)
+					subDepth++
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "sub" && subDepth > 0 {
+				subDepth--
+			}
+		case xml.CharData:
+			if subDepth == 0 {
+				b.Write(t)
+			}
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " "), ratePercent, nil
+}
+
+// writeElevenLabsBreaks writes break tags for a pause of millis, each at most elevenLabsMaxBreak
+func writeElevenLabsBreaks(b *strings.Builder, millis int) {
+	for millis > 0 {
+		chunk := min(millis, elevenLabsMaxBreak)
+		fmt.Fprintf(b, ` <break time="%.1fs" /> `, float64(chunk)/1000)
+		millis -= chunk
+	}
+}
+
+// breakMillis parses an SSML break time ("500ms" or "1.5s"), returning 0 when it is invalid
+func breakMillis(value string) int {
+	if millis, ok := strings.CutSuffix(value, "ms"); ok {
+		parsed, _ := strconv.ParseFloat(millis, 64)
+		return int(parsed)
+	}
+	seconds, _ := strconv.ParseFloat(strings.TrimSuffix(value, "s"), 64)
+	return int(seconds * 1000)
+}
+
+// attribute returns the value of an element's attribute
+func attribute(element xml.StartElement, name string) (string, bool) {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,102 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+)
+
+func TestNewElevenLabsSynthesizer(t *testing.T) {
+	if _, err := NewElevenLabsSynthesizer("", ""); err == nil {
+		t.Error("expected error for missing API key")
+	}
+	synthesizer, _ := NewElevenLabsSynthesizer("secret", "")
+	if synthesizer.Model != DefaultElevenLabsModel {
+		t.Errorf("expected %s, got %s", DefaultElevenLabsModel, synthesizer.Model)
+	}
+}
+
+func TestElevenLabsSynthesizer_Synthesize(t *testing.T) {
+	var received struct {
+		Text          string `json:"text"`
+		ModelID       string `json:"model_id"`
+		VoiceSettings struct {
+			Speed float64 `json:"speed"`
+		} `json:"voice_settings"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/text-to-speech/abc" {
+			t.Errorf("expected the voice in the path, got %s", r.URL.Path)
+		}
+		if r.Header.Get("xi-api-key") != "secret" {
+			t.Errorf("expected the API key, got %q", r.Header.Get("xi-api-key"))
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte("mp3"))
+	}))
+	defer server.Close()
+
+	synthesizer, _ := NewElevenLabsSynthesizer("secret", "eleven_turbo_v2_5")
+	synthesizer.Endpoint = server.URL
+
+	ssmlText := buildTimedSSML("Hello & welcome", 4, 1.1, nil)
+	audio, err := synthesizer.Synthesize(context.Background(), ssmlText, &VoiceConfig{LanguageCode: "en", VoiceName: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(audio) != "mp3" {
+		t.Errorf("expected the response audio, got %q", audio)
+	}
+	if want := `<break time="3.0s" /> <break time="1.0s" /> Hello & welcome`; received.Text != want {
+		t.Errorf("expected %q, got %q", want, received.Text)
+	}
+	if received.ModelID != "eleven_turbo_v2_5" || received.VoiceSettings.Speed != 1.1 {
+		t.Errorf("expected the model and a speed of 1.1, got %s and %v", received.ModelID, received.VoiceSettings.Speed)
+	}
+}
+
+func TestElevenLabsSynthesizer_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	synthesizer, _ := NewElevenLabsSynthesizer("secret", "")
+	synthesizer.Endpoint = server.URL
+
+	_, err := synthesizer.Synthesize(context.Background(), buildSSML("Hello", 1, nil), &VoiceConfig{VoiceName: "abc"})
+	var httpErr *transient.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected an HTTP error with status 429, got %v", err)
+	}
+}
+
+func TestElevenLabsText(t *testing.T) {
+	tests := []struct {
+		ssmlText string
+		wantText string
+		wantRate int
+	}{
+		{`<speak><prosody rate="80%">Hello world</prosody></speak>`, "Hello world", 80},
+		{`<speak><prosody rate="100%">Run <sub alias="engine x">Nginx</sub> on <phoneme alphabet="ipa" ph="x">GKE</phoneme></prosody></speak>`, "Run engine x on GKE", 100},
+		{`<speak><prosody rate="150%">Wait<break time="1500ms"/>now</prosody></speak>`, `Wait <break time="1.5s" /> now`, 150},
+	}
+	for _, tt := range tests {
+		text, rate, err := elevenLabsText(tt.ssmlText)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if text != tt.wantText || rate != tt.wantRate {
+			t.Errorf("elevenLabsText(%q) = %q at %d%%, want %q at %d%%", tt.ssmlText, text, rate, tt.wantText, tt.wantRate)
+		}
+	}
+
+	if _, _, err := elevenLabsText("<speak>unclosed"); err == nil {
+		t.Error("expected error for malformed SSML")
+	}
+}
@@ -0,0 +1,96 @@
+package tts
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sinouw/multilingual-video-processor/internal/ssml"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// applyLexicon wraps whole-word, case-insensitive matches of lexicon words in <phoneme> or <sub> tags
+// escapedText must already be XML-escaped. Longer words win over words they contain.
+func applyLexicon(escapedText string, lexicon []models.Pronunciation) string {
+	if len(lexicon) == 0 {
+		return escapedText
+	}
+
+	entries := make(map[string]models.Pronunciation, len(lexicon))
+	words := make([]string, 0, len(lexicon))
+	for _, entry := range lexicon {
+		word := ssml.Escape(strings.TrimSpace(entry.Word))
+		if word == "" {
+			continue
+		}
+		key := strings.ToLower(word)
+		if _, exists := entries[key]; !exists {
+			words = append(words, regexp.QuoteMeta(word))
+		}
+		entries[key] = entry
+	}
+	if len(words) == 0 {
+		return escapedText
+	}
+
+	// Regexp alternation is leftmost-first, so try longer words first
+	sort.Slice(words, func(i, j int) bool { return len(words[i]) > len(words[j]) })
+	pattern := regexp.MustCompile(`(?i)` + strings.Join(words, "|"))
+
+	var b strings.Builder
+	last := 0
+	for _, match := range pattern.FindAllStringIndex(escapedText, -1) {
+		start, end := match[0], match[1]
+		if !isWordBoundary(escapedText, start, end) {
+			continue
+		}
+		matched := escapedText[start:end]
+		b.WriteString(escapedText[last:start])
+		b.WriteString(pronunciationSSML(matched, entries[strings.ToLower(matched)]))
+		last = end
+	}
+	b.WriteString(escapedText[last:])
+	return b.String()
+}
+
+// pronunciationSSML renders a single lexicon match
+// A user-supplied SSML fragment that does not sanitize leaves the match as it is.
+func pronunciationSSML(text string, entry models.Pronunciation) string {
+	switch {
+	case entry.SSML != "":
+		fragment, err := ssml.Sanitize(entry.SSML)
+		if err != nil {
+			return text
+		}
+		return fragment
+	case entry.Phoneme != "":
+		return fmt.Sprintf(`<phoneme alphabet="ipa" ph="%s">%s</phoneme>`, ssml.Escape(entry.Phoneme), text)
+	default:
+		return fmt.Sprintf(`<sub alias="%s">%s</sub>`, ssml.Escape(entry.Alias), text)
+	}
+}
+
+// isWordBoundary reports whether text[start:end] is not part of a longer word or an XML entity
+func isWordBoundary(text string, start int, end int) bool {
+	if start > 0 {
+		if text[start-1] == '&' {
+			return false
+		}
+		if r, _ := utf8.DecodeLastRuneInString(text[:start]); isLexiconWordRune(r) {
+			return false
+		}
+	}
+	if end < len(text) {
+		if r, _ := utf8.DecodeRuneInString(text[end:]); isLexiconWordRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isLexiconWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
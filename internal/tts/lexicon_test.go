@@ -0,0 +1,63 @@
+package tts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestApplyLexicon(t *testing.T) {
+	lexicon := []models.Pronunciation{
+		{Word: "Nginx", Alias: "engine x"},
+		{Word: "Kubernetes", Phoneme: "ˌkuːbərˈnɛtiːz"},
+		{Word: "New York", Alias: "N Y"},
+		{Word: "amp", Alias: "ampere"},
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"alias", "We use nginx.", `We use <sub alias="engine x">nginx</sub>.`},
+		{"phoneme", "Kubernetes rocks", `<phoneme alphabet="ipa" ph="ˌkuːbərˈnɛtiːz">Kubernetes</phoneme> rocks`},
+		{"phrase", "Hello New York!", `Hello <sub alias="N Y">New York</sub>!`},
+		{"partial word untouched", "nginxes and Kubernetesque", "nginxes and Kubernetesque"},
+		{"entity untouched", "salt &amp; pepper", "salt &amp; pepper"},
+		{"no lexicon match", "plain text", "plain text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := applyLexicon(tt.text, lexicon)
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestApplyLexicon_SSML(t *testing.T) {
+	lexicon := []models.Pronunciation{
+		{Word: "SQL", SSML: `<say-as interpret-as="characters">SQL</say-as><prosody rate="50%">!</prosody>`},
+		{Word: "Acme", SSML: "<broken>"},
+	}
+
+	got := applyLexicon("SQL at Acme", lexicon)
+	want := `<say-as interpret-as="characters">SQL</say-as><prosody>!</prosody> at Acme`
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestBuildSSML_Lexicon(t *testing.T) {
+	ssml := buildSSML(`Say "R&D" at Acme`, 1.0, []models.Pronunciation{{Word: "Acme", Alias: `"ak-mee"`}})
+
+	if !strings.Contains(ssml, `R&amp;D`) {
+		t.Errorf("expected text to be XML-escaped, got %q", ssml)
+	}
+	if !strings.Contains(ssml, `<sub alias="&quot;ak-mee&quot;">Acme</sub>`) {
+		t.Errorf("expected escaped alias attribute, got %q", ssml)
+	}
+}
@@ -7,20 +7,199 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode/utf8"
 
 	"cloud.google.com/go/texttospeech/apiv1"
 	"cloud.google.com/go/texttospeech/apiv1/texttospeechpb"
+	"github.com/sinouw/multilingual-video-processor/internal/cache"
+	"github.com/sinouw/multilingual-video-processor/internal/ssml"
+	"github.com/sinouw/multilingual-video-processor/internal/subtitles"
+	"github.com/sinouw/multilingual-video-processor/internal/usage"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
 	"google.golang.org/api/option"
 )
 
+// ProviderName identifies this text-to-speech backend in capabilities and logs
+const ProviderName = "google-tts"
+
+// audioCache stores synthesized audio by request content; nil disables caching
+var audioCache cache.Store
+
+// SetCache enables caching of synthesized audio, so identical text in the same voice is synthesized once
+func SetCache(store cache.Store) {
+	audioCache = store
+}
+
+// endpoint replaces the Text-to-Speech API endpoint when set
+var endpoint string
+
+// SetEndpoint sends synthesis requests to a REST endpoint such as a fake server (e.g., "http://localhost:9090")
+// Plain http:// endpoints are called without credentials; empty restores the API endpoint.
+func SetEndpoint(url string) {
+	endpoint = url
+}
+
+// SynthesizeFunc returns MP3 audio for SSML spoken in a voice
+type SynthesizeFunc func(ctx context.Context, ssmlText string, voiceConfig *VoiceConfig) ([]byte, error)
+
+// synthesizer replaces the Text-to-Speech API when set, and synthesizerName keys its cached audio
+var (
+	synthesizer     SynthesizeFunc
+	synthesizerName = ProviderName
+)
+
+// SetSynthesizer routes synthesis to fn instead of Google Cloud (e.g., mock providers); nil restores the API
+func SetSynthesizer(name string, fn SynthesizeFunc) {
+	synthesizer = fn
+	synthesizerName = name
+	if fn == nil {
+		synthesizerName = ProviderName
+	}
+}
+
+// Options controls optional text-to-speech behaviour
+type Options struct {
+	Lexicon   []models.Pronunciation // Pronunciation overrides injected as <phoneme>/<sub> SSML tags
+	RateScale float64                // Multiplies the estimated speaking rate, e.g. to correct a measured duration drift; 0 means 1
+	Voice     *CustomVoice           // Brand voice replacing the language's default voice (voiceId); nil for the default
+	Fallbacks []*CustomVoice         // Voices tried in order when Voice fails (TTS_ROUTES); nil entries are the default voice
+	Gender    string                 // Voice gender (male or female) of the language's default voice; empty for the default
+}
+
+// scaleRate applies the rate scale to a speed ratio
+func (o Options) scaleRate(speedRatio float64) float64 {
+	if o.RateScale > 0 {
+		return speedRatio * o.RateScale
+	}
+	return speedRatio
+}
+
 // GenerateTTS generates text-to-speech audio using Google Cloud TTS
 func GenerateTTS(ctx context.Context, text string, language string, originalDuration float64, outputPath string) error {
+	return GenerateTTSWithOptions(ctx, text, language, originalDuration, outputPath, Options{})
+}
+
+// GenerateTTSWithOptions generates text-to-speech audio using Google Cloud TTS with the given options
+func GenerateTTSWithOptions(ctx context.Context, text string, language string, originalDuration float64, outputPath string, opts Options) error {
 	slog.Info("Generating TTS",
 		"language", language,
 		"textLength", len(text),
 		"originalDuration", originalDuration)
 
-	// Initialize TTS client
+	// Initialize TTS client for the language's voice
+	s, err := newSession(ctx, language, opts)
+	if err != nil {
+		return err
+	}
+	defer s.release()
+
+	// Calculate speed adjustment to match original duration
+	speedRatio := opts.scaleRate(calculateSpeedRatio(text, originalDuration, language))
+
+	audioContent, err := s.synthesize(ctx, buildSSML(text, speedRatio, opts.Lexicon))
+	if err != nil {
+		return err
+	}
+
+	if err := writeAudioFile(outputPath, audioContent); err != nil {
+		return err
+	}
+
+	slog.Info("TTS audio generated successfully", "outputPath", outputPath)
+	return nil
+}
+
+// GenerateSegmentedTTS generates speech for a sequence of text segments, synthesizing
+// each distinct segment only once and reusing its audio for repeated segments.
+// The MP3 segments are concatenated in order. Returns the number of reused segments.
+func GenerateSegmentedTTS(ctx context.Context, segments []string, language string, originalDuration float64, outputPath string, opts Options) (int, error) {
+	slog.Info("Generating segmented TTS",
+		"language", language,
+		"segments", len(segments),
+		"originalDuration", originalDuration)
+
+	s, err := newSession(ctx, language, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer s.release()
+
+	// Speed is derived from the full text so all segments share one speaking rate
+	speedRatio := opts.scaleRate(calculateSpeedRatio(strings.Join(segments, " "), originalDuration, language))
+
+	synthesized := make(map[string][]byte)
+	reused := 0
+	var audio []byte
+	for _, segment := range segments {
+		content, ok := synthesized[segment]
+		if ok {
+			reused++
+		} else {
+			content, err = s.synthesize(ctx, buildSSML(segment, speedRatio, opts.Lexicon))
+			if err != nil {
+				return 0, err
+			}
+			synthesized[segment] = content
+		}
+		audio = append(audio, content...)
+	}
+
+	if err := writeAudioFile(outputPath, audio); err != nil {
+		return 0, err
+	}
+
+	slog.Info("Segmented TTS audio generated successfully", "outputPath", outputPath, "reusedSegments", reused)
+	return reused, nil
+}
+
+// GenerateTimedTTS generates speech aligned to subtitle cues: each cue is preceded by the silence
+// since the previous cue and spoken at the rate that fits its own duration.
+// The MP3 segments are concatenated in order. Timing is approximate, like the whole-text speed adjustment.
+func GenerateTimedTTS(ctx context.Context, cues []subtitles.Cue, language string, outputPath string, opts Options) error {
+	slog.Info("Generating timed TTS",
+		"language", language,
+		"cues", len(cues))
+
+	s, err := newSession(ctx, language, opts)
+	if err != nil {
+		return err
+	}
+	defer s.release()
+
+	var audio []byte
+	position := 0.0
+	for _, cue := range cues {
+		speedRatio := calculateSpeedRatio(cue.Text, cue.End-cue.Start, language)
+		content, err := s.synthesize(ctx, buildTimedSSML(cue.Text, cue.Start-position, speedRatio, opts.Lexicon))
+		if err != nil {
+			return err
+		}
+		audio = append(audio, content...)
+		position = cue.End
+	}
+
+	if err := writeAudioFile(outputPath, audio); err != nil {
+		return err
+	}
+
+	slog.Info("Timed TTS audio generated successfully", "outputPath", outputPath)
+	return nil
+}
+
+// newClient creates a TTS client for the configured endpoint, using the credentials file if configured
+func newClient(ctx context.Context) (*texttospeech.Client, error) {
+	if endpoint != "" {
+		opts := []option.ClientOption{option.WithEndpoint(endpoint)}
+		if strings.HasPrefix(endpoint, "http://") {
+			opts = append(opts, option.WithoutAuthentication())
+		}
+		client, err := texttospeech.NewRESTClient(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create TTS client: %w", err)
+		}
+		return client, nil
+	}
+
 	credentialsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
 	var client *texttospeech.Client
 	var err error
@@ -31,31 +210,130 @@ func GenerateTTS(ctx context.Context, text string, language string, originalDura
 			slog.Warn("Failed to create client with credentials file, trying default", "error", err)
 			client, err = texttospeech.NewClient(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to create TTS client: %w", err)
+				return nil, fmt.Errorf("failed to create TTS client: %w", err)
 			}
 		}
 	} else {
 		client, err = texttospeech.NewClient(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to create TTS client: %w", err)
+			return nil, fmt.Errorf("failed to create TTS client: %w", err)
 		}
 	}
-	defer client.Close()
+	return client, nil
+}
+
+// session is the voice and synthesis backend of one generation
+type session struct {
+	speak       SynthesizeFunc
+	name        string // Backend name keying cached audio
+	voiceConfig *VoiceConfig
+	release     func()   // Releases the backend's client
+	fallback    *session // Next voice of the chain, used when this one fails; nil for none
+}
 
-	// Get voice configuration for language
-	voiceConfig := GetVoiceConfig(language)
-	if voiceConfig == nil {
-		return fmt.Errorf("unsupported language for TTS: %s", language)
+// newSession selects the voice for a language, the custom voice or gender when set, and the backend speaking it,
+// followed by the sessions of the fallback voices
+func newSession(ctx context.Context, language string, opts Options) (*session, error) {
+	s, err := newVoiceSession(ctx, language, opts)
+	if err != nil || len(opts.Fallbacks) == 0 {
+		return s, err
 	}
+	fallbackOpts := opts
+	fallbackOpts.Voice, fallbackOpts.Fallbacks = opts.Fallbacks[0], opts.Fallbacks[1:]
+	if s.fallback, err = newSession(ctx, language, fallbackOpts); err != nil {
+		s.release()
+		return nil, err
+	}
+	primaryRelease := s.release
+	s.release = func() {
+		primaryRelease()
+		s.fallback.release()
+	}
+	return s, nil
+}
 
-	// Calculate speed adjustment to match original duration
-	speedRatio := calculateSpeedRatio(text, originalDuration, language)
-	ssmlText := buildSSML(text, speedRatio)
+// newVoiceSession returns the session speaking a language in opts.Voice, or the language's default voice
+func newVoiceSession(ctx context.Context, language string, opts Options) (*session, error) {
+	voice := opts.Voice
+	voiceConfig := GetVoiceConfigForGender(language, opts.Gender)
+	if voice != nil {
+		var err error
+		if voiceConfig, err = voice.voiceConfig(language); err != nil {
+			return nil, err
+		}
+	} else if voiceConfig == nil {
+		return nil, fmt.Errorf("unsupported language for TTS: %s", language)
+	}
 
+	s := &session{voiceConfig: voiceConfig, release: func() {}}
+	switch {
+	case synthesizer != nil:
+		s.speak, s.name = synthesizer, synthesizerName
+	case voice != nil && voice.Provider == VoiceProviderElevenLabs:
+		if elevenLabs == nil {
+			return nil, fmt.Errorf("voice %s requires ElevenLabs, which is not configured", voice)
+		}
+		s.speak, s.name = elevenLabs.Synthesize, VoiceProviderElevenLabs
+	default:
+		client, err := newClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.speak = func(ctx context.Context, ssmlText string, voiceConfig *VoiceConfig) ([]byte, error) {
+			return synthesizeSpeech(ctx, client, ssmlText, voiceConfig)
+		}
+		s.name, s.release = ProviderName, func() { client.Close() }
+	}
+	return s, nil
+}
+
+// synthesize returns MP3 audio for the SSML, falling back to the next voice of the chain when this one fails
+func (s *session) synthesize(ctx context.Context, ssmlText string) ([]byte, error) {
+	audio, err := s.synthesizeVoice(ctx, ssmlText)
+	if err == nil || s.fallback == nil || ctx.Err() != nil {
+		return audio, err
+	}
+	slog.Warn("TTS voice failed, falling back", "provider", s.name, "fallback", s.fallback.name, "language", s.voiceConfig.LanguageCode, "error", err)
+	return s.fallback.synthesize(ctx, ssmlText)
+}
+
+// synthesizeVoice returns MP3 audio for the SSML in the session's voice, from the audio cache when available
+func (s *session) synthesizeVoice(ctx context.Context, ssmlText string) ([]byte, error) {
+	speak, voiceConfig := s.speak, s.voiceConfig
+	if audioCache == nil {
+		return speak(ctx, ssmlText, voiceConfig)
+	}
+
+	voiceName := voiceConfig.VoiceName
+	if voiceConfig.CustomModel != "" {
+		voiceName = voiceConfig.CustomModel
+	}
+	key := cache.Key("tts", s.name, voiceConfig.LanguageCode, voiceName, voiceConfig.Gender.String(), ssmlText)
+	audio, found, err := audioCache.Get(ctx, key)
+	if err != nil {
+		slog.Warn("TTS cache read failed", "error", err, "language", voiceConfig.LanguageCode)
+	}
+	if found {
+		slog.Info("TTS audio served from cache", "language", voiceConfig.LanguageCode)
+		return audio, nil
+	}
+
+	audio, err = speak(ctx, ssmlText, voiceConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := audioCache.Set(ctx, key, audio); err != nil {
+		slog.Warn("TTS cache write failed", "error", err, "language", voiceConfig.LanguageCode)
+	}
+	return audio, nil
+}
+
+// synthesizeSpeech performs a single SSML synthesis request and returns MP3 audio content
+func synthesizeSpeech(ctx context.Context, client *texttospeech.Client, ssmlText string, voiceConfig *VoiceConfig) ([]byte, error) {
 	// Check context cancellation before making API call
 	select {
 	case <-ctx.Done():
-		return fmt.Errorf("TTS generation cancelled: %w", ctx.Err())
+		return nil, fmt.Errorf("TTS generation cancelled: %w", ctx.Err())
 	default:
 	}
 
@@ -78,30 +356,39 @@ func GenerateTTS(ctx context.Context, text string, language string, originalDura
 		},
 	}
 
+	if voiceConfig.CustomModel != "" {
+		req.Voice.CustomVoice = &texttospeechpb.CustomVoiceParams{Model: voiceConfig.CustomModel}
+	}
+
 	// Perform the text-to-speech request with context
 	resp, err := client.SynthesizeSpeech(ctx, req)
 	if err != nil {
 		// Check if error is due to context cancellation
 		if ctx.Err() != nil {
-			return fmt.Errorf("TTS generation cancelled: %w", ctx.Err())
+			return nil, fmt.Errorf("TTS generation cancelled: %w", ctx.Err())
 		}
-		return fmt.Errorf("failed to synthesize speech: %w", err)
+		return nil, fmt.Errorf("failed to synthesize speech: %w", err)
 	}
 
+	// Synthesis is billed per input character, SSML markup included
+	usage.FromContext(ctx).AddTTSCharacters(utf8.RuneCountInString(ssmlText))
+	return resp.AudioContent, nil
+}
+
+// writeAudioFile writes audio content to outputPath, creating the directory if needed
+func writeAudioFile(outputPath string, audioContent []byte) error {
 	// Create output directory if needed
 	outputDir := filepath.Dir(outputPath)
-	err = os.MkdirAll(outputDir, 0755)
+	err := os.MkdirAll(outputDir, 0755)
 	if err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
 	// Write the audio content to file
-	err = os.WriteFile(outputPath, resp.AudioContent, 0644)
+	err = os.WriteFile(outputPath, audioContent, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to write audio file: %w", err)
 	}
-
-	slog.Info("TTS audio generated successfully", "outputPath", outputPath)
 	return nil
 }
 
@@ -139,20 +426,13 @@ func calculateSpeedRatio(text string, originalDuration float64, language string)
 }
 
 // buildSSML builds SSML text with speed control
-func buildSSML(text string, speedRatio float64) string {
-	// Escape XML special characters
-	text = strings.ReplaceAll(text, "&", "&amp;")
-	text = strings.ReplaceAll(text, "<", "&lt;")
-	text = strings.ReplaceAll(text, ">", "&gt;")
-
-	// Build SSML with prosody for speed control
-	speedPercent := int(speedRatio * 100)
-	if speedPercent < 50 {
-		speedPercent = 50
-	} else if speedPercent > 200 {
-		speedPercent = 200
-	}
-
-	ssml := fmt.Sprintf(`<speak><prosody rate="%d%%">%s</prosody></speak>`, speedPercent, text)
-	return ssml
+func buildSSML(text string, speedRatio float64, lexicon []models.Pronunciation) string {
+	return buildTimedSSML(text, 0, speedRatio, lexicon)
+}
+
+// buildTimedSSML builds SSML with speed control, preceded by the given seconds of silence
+func buildTimedSSML(text string, silence float64, speedRatio float64, lexicon []models.Pronunciation) string {
+	// Escape XML special characters, then inject pronunciation overrides
+	content := applyLexicon(ssml.Escape(text), lexicon)
+	return ssml.Speak(content, int(speedRatio*100), int(silence*1000+0.5))
 }
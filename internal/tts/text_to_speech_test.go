@@ -2,10 +2,16 @@ package tts
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"cloud.google.com/go/texttospeech/apiv1/texttospeechpb"
+
+	"github.com/sinouw/multilingual-video-processor/internal/subtitles"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
 )
 
 func TestGenerateTTS_UnsupportedLanguage(t *testing.T) {
@@ -72,8 +78,8 @@ func TestGetVoiceConfig(t *testing.T) {
 		{"ar", false},
 		{"de", false},
 		{"ru", false},
-		{"xx", true},  // Unsupported
-		{"", true},    // Empty
+		{"xx", true}, // Unsupported
+		{"", true},   // Empty
 	}
 
 	for _, tt := range tests {
@@ -88,3 +94,123 @@ func TestGetVoiceConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestGetVoiceConfigForGender(t *testing.T) {
+	for _, language := range []string{"en", "ar", "de", "ru", "fr"} {
+		t.Run(language, func(t *testing.T) {
+			male := GetVoiceConfigForGender(language, models.VoiceGenderMale)
+			if male == nil || male.Gender != texttospeechpb.SsmlVoiceGender_MALE {
+				t.Fatalf("expected a male voice for language %s, got %+v", language, male)
+			}
+			if male.LanguageCode != GetVoiceConfig(language).LanguageCode {
+				t.Errorf("expected male voice locale %s, got %s", GetVoiceConfig(language).LanguageCode, male.LanguageCode)
+			}
+			if female := GetVoiceConfigForGender(language, models.VoiceGenderFemale); female.VoiceName != GetVoiceConfig(language).VoiceName {
+				t.Errorf("expected the default voice for female, got %s", female.VoiceName)
+			}
+		})
+	}
+
+	if GetVoiceConfigForGender("xx", models.VoiceGenderMale) != nil {
+		t.Error("expected nil config for unsupported language")
+	}
+}
+
+func TestBuildTimedSSML(t *testing.T) {
+	tests := []struct {
+		name    string
+		silence float64
+		want    string
+	}{
+		{"no gap", 0, `<speak><prosody rate="100%">Hello</prosody></speak>`},
+		{"short gap", 1.25, `<speak><break time="1250ms"/><prosody rate="100%">Hello</prosody></speak>`},
+		{"long gap", 12.5, `<speak><break time="10000ms"/><break time="2500ms"/><prosody rate="100%">Hello</prosody></speak>`},
+		{"overlapping cue", -0.5, `<speak><prosody rate="100%">Hello</prosody></speak>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildTimedSSML("Hello", tt.silence, 1.0, nil); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerateTimedTTS_UnsupportedLanguage(t *testing.T) {
+	outputPath := filepath.Join(os.TempDir(), "test_timed_output.mp3")
+	cues := []subtitles.Cue{{Start: 0, End: 2, Text: "Hello"}}
+
+	if err := GenerateTimedTTS(context.Background(), cues, "xx", outputPath, Options{}); err == nil {
+		t.Error("expected error for unsupported language")
+	}
+}
+
+func TestGenerateSegmentedTTS_Synthesizer(t *testing.T) {
+	var calls []string
+	SetSynthesizer("fake", func(ctx context.Context, ssmlText string, voiceConfig *VoiceConfig) ([]byte, error) {
+		calls = append(calls, ssmlText)
+		return []byte(voiceConfig.LanguageCode + ";"), nil
+	})
+	defer SetSynthesizer("", nil)
+
+	outputPath := filepath.Join(t.TempDir(), "speech.mp3")
+	reused, err := GenerateSegmentedTTS(context.Background(), []string{"Hello.", "Bye.", "Hello."}, "en", 0, outputPath, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reused != 1 || len(calls) != 2 {
+		t.Errorf("expected 2 synthesis calls and 1 reused segment, got %d calls and %d reused", len(calls), reused)
+	}
+
+	audio, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if string(audio) != "en-US;en-US;en-US;" {
+		t.Errorf("expected concatenated segment audio, got %q", audio)
+	}
+	if synthesizerName != "fake" {
+		t.Errorf("expected cache namespace fake, got %s", synthesizerName)
+	}
+}
+
+func TestOptions_ScaleRate(t *testing.T) {
+	if got := (Options{}).scaleRate(1.2); got != 1.2 {
+		t.Errorf("expected unscaled rate 1.2, got %v", got)
+	}
+	if got := (Options{RateScale: 1.5}).scaleRate(1.2); got < 1.799 || got > 1.801 {
+		t.Errorf("expected scaled rate 1.8, got %v", got)
+	}
+}
+
+func TestSession_Fallback(t *testing.T) {
+	failing := func(ctx context.Context, ssmlText string, voiceConfig *VoiceConfig) ([]byte, error) {
+		return nil, errors.New("voice unavailable")
+	}
+	var spokenBy string
+	speaking := func(ctx context.Context, ssmlText string, voiceConfig *VoiceConfig) ([]byte, error) {
+		spokenBy = voiceConfig.VoiceName
+		return []byte("audio"), nil
+	}
+	s := &session{
+		speak:       failing,
+		name:        VoiceProviderElevenLabs,
+		voiceConfig: &VoiceConfig{LanguageCode: "de", VoiceName: "brand"},
+		fallback: &session{
+			speak:       speaking,
+			name:        ProviderName,
+			voiceConfig: &VoiceConfig{LanguageCode: "de-DE", VoiceName: "de-DE-Neural2-B"},
+		},
+	}
+
+	audio, err := s.synthesize(context.Background(), "<speak>Hallo</speak>")
+	if err != nil || string(audio) != "audio" || spokenBy != "de-DE-Neural2-B" {
+		t.Errorf("expected the fallback voice to speak, got %q %v (voice %q)", audio, err, spokenBy)
+	}
+
+	s.fallback.speak = failing
+	if _, err := s.synthesize(context.Background(), "<speak>Hallo</speak>"); err == nil {
+		t.Error("expected an error when every voice fails")
+	}
+}
@@ -2,6 +2,8 @@ package tts
 
 import (
 	"cloud.google.com/go/texttospeech/apiv1/texttospeechpb"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
 )
 
 // VoiceConfig holds voice configuration for a language
@@ -9,6 +11,7 @@ type VoiceConfig struct {
 	LanguageCode string
 	VoiceName    string
 	Gender       texttospeechpb.SsmlVoiceGender
+	CustomModel  string // Custom Voice model speaking instead of VoiceName, for brand voices
 }
 
 // GetVoiceConfig returns voice configuration for a language
@@ -45,6 +48,44 @@ func GetVoiceConfig(language string) *VoiceConfig {
 	return configs[language]
 }
 
+// GetVoiceConfigForGender returns the voice configuration of a language speaking with a male or female voice
+// Any other gender returns the language's default voice; returns nil if language is not supported.
+func GetVoiceConfigForGender(language string, gender string) *VoiceConfig {
+	if gender != models.VoiceGenderMale {
+		return GetVoiceConfig(language)
+	}
+
+	configs := map[string]*VoiceConfig{
+		"en": {
+			LanguageCode: "en-US",
+			VoiceName:    "en-US-Neural2-D", // Natural male voice
+			Gender:       texttospeechpb.SsmlVoiceGender_MALE,
+		},
+		"ar": {
+			LanguageCode: "ar-XA",
+			VoiceName:    "ar-XA-Wavenet-B",
+			Gender:       texttospeechpb.SsmlVoiceGender_MALE,
+		},
+		"de": {
+			LanguageCode: "de-DE",
+			VoiceName:    "de-DE-Neural2-B",
+			Gender:       texttospeechpb.SsmlVoiceGender_MALE,
+		},
+		"ru": {
+			LanguageCode: "ru-RU",
+			VoiceName:    "ru-RU-Wavenet-D",
+			Gender:       texttospeechpb.SsmlVoiceGender_MALE,
+		},
+		"fr": {
+			LanguageCode: "fr-FR",
+			VoiceName:    "fr-FR-Neural2-B",
+			Gender:       texttospeechpb.SsmlVoiceGender_MALE,
+		},
+	}
+
+	return configs[language]
+}
+
 // GetSpeakingRate returns the average speaking rate (words per minute) for a language
 func GetSpeakingRate(language string) float64 {
 	rates := map[string]float64{
@@ -0,0 +1,121 @@
+package usage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// Budget limits that can be exceeded, as reported in BudgetState.Exceeded
+const (
+	BudgetLimitCost                = "cost"
+	BudgetLimitSTTSeconds          = "sttSeconds"
+	BudgetLimitTranslateCharacters = "translateCharacters"
+	BudgetLimitTTSCharacters       = "ttsCharacters"
+)
+
+// BudgetLimits caps the usage of all jobs within a budget window; a zero limit leaves its unit unlimited
+type BudgetLimits struct {
+	Cost                float64 // Estimated cost in the price table's currency
+	STTSeconds          float64 // Seconds of audio sent to speech recognition
+	TranslateCharacters int64   // Characters sent to translation, across providers
+	TTSCharacters       int64   // Characters sent to speech synthesis
+}
+
+// Enabled reports whether any limit is set
+func (l BudgetLimits) Enabled() bool {
+	return l.Cost > 0 || l.STTSeconds > 0 || l.TranslateCharacters > 0 || l.TTSCharacters > 0
+}
+
+// BudgetState is the usage of the current budget window
+type BudgetState struct {
+	Spent    models.JobUsage // Usage so far, with its estimated cost
+	Exceeded string          // The first limit reached, empty while within budget
+	ResetAt  time.Time       // When the window ends and usage starts again from zero
+}
+
+// Budget tracks provider usage across jobs in fixed windows against spending limits
+// It is a UsageSink, so attaching it to job meters records usage as the providers are called. Running jobs are
+// not stopped when a limit is reached; callers check Exceeded before accepting new work.
+type Budget struct {
+	mu          sync.Mutex
+	limits      BudgetLimits
+	window      time.Duration
+	prices      models.PriceTable
+	windowStart time.Time
+	spent       models.JobUsage
+	now         func() time.Time
+}
+
+// NewBudget creates a budget whose first window starts now; prices estimate the cost limited by limits.Cost
+func NewBudget(limits BudgetLimits, window time.Duration, prices models.PriceTable) *Budget {
+	b := &Budget{
+		limits: limits,
+		window: window,
+		prices: prices,
+		now:    time.Now,
+	}
+	b.reset(b.now())
+	return b
+}
+
+// RecordUsage adds usage to the current window
+func (b *Budget) RecordUsage(usage models.JobUsage) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.roll()
+	b.spent.STTSeconds += usage.STTSeconds
+	for provider, characters := range usage.TranslateCharacters {
+		b.spent.TranslateCharacters[provider] += characters
+	}
+	b.spent.TTSCharacters += usage.TTSCharacters
+	b.spent.StorageBytes += usage.StorageBytes
+}
+
+// State returns the usage of the current window and the limit it reached, if any
+func (b *Budget) State() BudgetState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.roll()
+	spent := b.spent
+	spent.TranslateCharacters = make(map[string]int64, len(b.spent.TranslateCharacters))
+	translated := int64(0)
+	for provider, characters := range b.spent.TranslateCharacters {
+		spent.TranslateCharacters[provider] = characters
+		translated += characters
+	}
+	spent.EstimatedCost = b.prices.Cost(spent)
+	spent.Currency = b.prices.Currency
+
+	state := BudgetState{Spent: spent, ResetAt: b.windowStart.Add(b.window)}
+	switch {
+	case b.limits.Cost > 0 && spent.EstimatedCost >= b.limits.Cost:
+		state.Exceeded = BudgetLimitCost
+	case b.limits.STTSeconds > 0 && spent.STTSeconds >= b.limits.STTSeconds:
+		state.Exceeded = BudgetLimitSTTSeconds
+	case b.limits.TranslateCharacters > 0 && translated >= b.limits.TranslateCharacters:
+		state.Exceeded = BudgetLimitTranslateCharacters
+	case b.limits.TTSCharacters > 0 && spent.TTSCharacters >= b.limits.TTSCharacters:
+		state.Exceeded = BudgetLimitTTSCharacters
+	}
+	return state
+}
+
+// roll starts a new window once the current one ended (b.mu must be held)
+func (b *Budget) roll() {
+	now := b.now()
+	if now.Before(b.windowStart.Add(b.window)) {
+		return
+	}
+	// Windows stay aligned to the first one, however long no usage was recorded
+	elapsed := now.Sub(b.windowStart) / b.window
+	b.reset(b.windowStart.Add(elapsed * b.window))
+}
+
+func (b *Budget) reset(start time.Time) {
+	b.windowStart = start
+	b.spent = models.JobUsage{TranslateCharacters: make(map[string]int64)}
+}
@@ -0,0 +1,58 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestBudget(t *testing.T) {
+	prices := models.PriceTable{Currency: "USD", TTSPerMillionChars: 16}
+	budget := NewBudget(BudgetLimits{Cost: 10, STTSeconds: 3600}, time.Hour, prices)
+	start := time.Date(2026, 1, 19, 12, 0, 0, 0, time.UTC)
+	now := start
+	budget.now = func() time.Time { return now }
+	budget.reset(start)
+
+	// Usage reaches the budget through job meters
+	meter := models.NewUsageMeterWithSink(budget)
+	meter.AddTTSCharacters(400000)
+	meter.AddSTTSeconds(600)
+	state := budget.State()
+	if state.Exceeded != "" || state.Spent.EstimatedCost != 6.4 || state.Spent.STTSeconds != 600 {
+		t.Fatalf("expected usage within budget, got %+v", state)
+	}
+	if !state.ResetAt.Equal(start.Add(time.Hour)) {
+		t.Errorf("ResetAt = %v, want the end of the window", state.ResetAt)
+	}
+
+	models.NewUsageMeterWithSink(budget).AddTTSCharacters(250000)
+	if state := budget.State(); state.Exceeded != BudgetLimitCost {
+		t.Errorf("expected the cost limit to be reached, got %+v", state)
+	}
+
+	// A new window starts from zero, aligned to the first one
+	now = start.Add(2*time.Hour + 10*time.Minute)
+	state = budget.State()
+	if state.Exceeded != "" || state.Spent.TTSCharacters != 0 {
+		t.Errorf("expected a fresh window, got %+v", state)
+	}
+	if !state.ResetAt.Equal(start.Add(3 * time.Hour)) {
+		t.Errorf("ResetAt = %v, want %v", state.ResetAt, start.Add(3*time.Hour))
+	}
+
+	budget.RecordUsage(models.JobUsage{STTSeconds: 3600})
+	if state := budget.State(); state.Exceeded != BudgetLimitSTTSeconds {
+		t.Errorf("expected the speech recognition limit to be reached, got %+v", state)
+	}
+}
+
+func TestBudgetLimits_Enabled(t *testing.T) {
+	if (BudgetLimits{}).Enabled() {
+		t.Error("expected no limits to disable the budget")
+	}
+	if !(BudgetLimits{TranslateCharacters: 1000000}).Enabled() {
+		t.Error("expected a character limit to enable the budget")
+	}
+}
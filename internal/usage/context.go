@@ -0,0 +1,20 @@
+package usage
+
+import (
+	"context"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+type meterKey struct{}
+
+// WithMeter returns a context whose provider calls are recorded on meter
+func WithMeter(ctx context.Context, meter *models.UsageMeter) context.Context {
+	return context.WithValue(ctx, meterKey{}, meter)
+}
+
+// FromContext returns the context's usage meter, or nil (which ignores usage) when there is none
+func FromContext(ctx context.Context) *models.UsageMeter {
+	meter, _ := ctx.Value(meterKey{}).(*models.UsageMeter)
+	return meter
+}
@@ -0,0 +1,50 @@
+package usage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestMeterFromContext(t *testing.T) {
+	// Without a meter, usage is silently ignored
+	FromContext(context.Background()).AddSTTSeconds(10)
+
+	meter := models.NewUsageMeter()
+	ctx := WithMeter(context.Background(), meter)
+	FromContext(ctx).AddSTTSeconds(90)
+	FromContext(ctx).AddTranslateCharacters("google", 500000)
+	FromContext(ctx).AddTranslateCharacters("deepl", 200000)
+	FromContext(ctx).AddTTSCharacters(250000)
+	FromContext(ctx).AddStorageBytes(1 << 30)
+
+	prices := models.PriceTable{
+		Currency:                 "USD",
+		STTPerMinute:             0.016,
+		TranslatePerMillionChars: map[string]float64{"google": 20, "deepl": 25},
+		TTSPerMillionChars:       16,
+		StoragePerGB:             0.12,
+	}
+	got := meter.Snapshot(prices)
+
+	if got.STTSeconds != 90 || got.TTSCharacters != 250000 || got.StorageBytes != 1<<30 {
+		t.Errorf("unexpected usage %+v", got)
+	}
+	if got.TranslateCharacters["google"] != 500000 || got.TranslateCharacters["deepl"] != 200000 {
+		t.Errorf("expected per-provider characters, got %v", got.TranslateCharacters)
+	}
+	// 1.5 min * 0.016 + 0.5M * 20 + 0.2M * 25 + 0.25M * 16 + 1 GB * 0.12
+	if want := 19.144; got.EstimatedCost != want {
+		t.Errorf("expected cost %v, got %v", want, got.EstimatedCost)
+	}
+	if got.Currency != "USD" {
+		t.Errorf("expected currency USD, got %s", got.Currency)
+	}
+
+	// Snapshots are independent of later usage
+	meter.AddTranslateCharacters("google", 1)
+	if got.TranslateCharacters["google"] != 500000 {
+		t.Error("expected snapshot to be unaffected by later usage")
+	}
+}
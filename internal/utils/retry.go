@@ -1,9 +1,13 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"math/rand"
 	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
 )
 
 // RetryConfig holds retry configuration
@@ -12,6 +16,7 @@ type RetryConfig struct {
 	InitialDelay time.Duration
 	MaxDelay     time.Duration
 	Multiplier   float64
+	Jitter       float64 // Fraction (0-1) of each delay that is randomized, spreading out concurrent retries
 }
 
 // DefaultRetryConfig returns a default retry configuration
@@ -21,16 +26,22 @@ func DefaultRetryConfig() RetryConfig {
 		InitialDelay: 1 * time.Second,
 		MaxDelay:     10 * time.Second,
 		Multiplier:   2.0,
+		Jitter:       0.2,
 	}
 }
 
-// Retry executes a function with retry logic
-func Retry(fn func() error, config RetryConfig) error {
+// Retry executes a function with retry logic and exponential backoff
+// It stops early when ctx is done or the error is not retryable (transient.IsRetryable), returning that error unchanged.
+func Retry(ctx context.Context, fn func(ctx context.Context) error, config RetryConfig) error {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 1
+	}
+
 	var lastErr error
 	delay := config.InitialDelay
 
 	for attempt := 1; attempt <= config.MaxAttempts; attempt++ {
-		err := fn()
+		err := fn(ctx)
 		if err == nil {
 			if attempt > 1 {
 				slog.Info("Retry succeeded", "attempt", attempt)
@@ -39,16 +50,24 @@ func Retry(fn func() error, config RetryConfig) error {
 		}
 
 		lastErr = err
+		if !transient.IsRetryable(err) {
+			return err
+		}
 		if attempt < config.MaxAttempts {
+			wait := jitter(delay, config.Jitter)
 			slog.Warn("Retry attempt failed, retrying",
 				"attempt", attempt,
 				"maxAttempts", config.MaxAttempts,
-				"delay", delay,
+				"delay", wait,
 				"error", err)
 
-			time.Sleep(delay)
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return fmt.Errorf("retry cancelled after %d attempts: %w", attempt, ctx.Err())
+			}
 			delay = time.Duration(float64(delay) * config.Multiplier)
-			if delay > config.MaxDelay {
+			if config.MaxDelay > 0 && delay > config.MaxDelay {
 				delay = config.MaxDelay
 			}
 		}
@@ -56,3 +75,14 @@ func Retry(fn func() error, config RetryConfig) error {
 
 	return fmt.Errorf("retry exhausted after %d attempts: %w", config.MaxAttempts, lastErr)
 }
+
+// jitter randomizes a fraction of delay, returning a value in [delay*(1-fraction), delay]
+func jitter(delay time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 || delay <= 0 {
+		return delay
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	return delay - time.Duration(rand.Float64()*fraction*float64(delay))
+}
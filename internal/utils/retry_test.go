@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+)
+
+func TestRetry(t *testing.T) {
+	failure := errors.New("connection reset")
+	tests := []struct {
+		name         string
+		errs         []error // Result of each attempt; attempts past the end succeed
+		wantAttempts int
+		wantErr      bool
+	}{
+		{"succeeds first time", nil, 1, false},
+		{"succeeds after failures", []error{failure, failure}, 3, false},
+		{"exhausted", []error{failure, failure, failure}, 3, true},
+		{"permanent error stops", []error{transient.Permanent(failure)}, 1, true},
+		{"client error stops", []error{&transient.HTTPError{StatusCode: 400}}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := Retry(context.Background(), func(ctx context.Context) error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			}, RetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond, Multiplier: 2, Jitter: 0.5})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.wantAttempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRetry_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := Retry(ctx, func(ctx context.Context) error {
+		attempts++
+		cancel()
+		return errors.New("unavailable")
+	}, RetryConfig{MaxAttempts: 5, InitialDelay: time.Hour})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	delay := 100 * time.Millisecond
+	for i := 0; i < 50; i++ {
+		if got := jitter(delay, 0.3); got < 70*time.Millisecond || got > delay {
+			t.Fatalf("expected delay between 70ms and 100ms, got %v", got)
+		}
+	}
+	if got := jitter(delay, 0); got != delay {
+		t.Errorf("expected no jitter, got %v", got)
+	}
+}
@@ -0,0 +1,45 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/sinouw/multilingual-video-processor/internal/config"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// Problem codes for videos a translation job would reject
+const (
+	CodeVideoTooLarge = "video_too_large"
+	CodeVideoTooLong  = "video_too_long"
+	CodeNoVideoStream = "no_video_stream"
+	CodeNoAudioStream = "no_audio_stream"
+)
+
+// CheckMediaLimits lists the reasons a video of the given size and media info breaks the configured limits
+// media may be nil when the video was not probed, in which case only its size is checked.
+func CheckMediaLimits(media *models.MediaInfo, sizeBytes int64, cfg *config.Config) []models.InspectProblem {
+	var problems []models.InspectProblem
+	if maxBytes := int64(cfg.MaxVideoSizeMB) << 20; maxBytes > 0 && sizeBytes > maxBytes {
+		problems = append(problems, models.InspectProblem{
+			Code:    CodeVideoTooLarge,
+			Message: fmt.Sprintf("video size exceeds maximum: %d MB > %d MB", sizeBytes>>20, cfg.MaxVideoSizeMB),
+		})
+	}
+	if media == nil {
+		return problems
+	}
+
+	if maxSeconds := cfg.MaxSourceDuration().Seconds(); maxSeconds > 0 && media.Duration > maxSeconds {
+		problems = append(problems, models.InspectProblem{
+			Code:    CodeVideoTooLong,
+			Message: fmt.Sprintf("video duration exceeds maximum: %.2fs > %.2fs", media.Duration, maxSeconds),
+		})
+	}
+	if media.Video == nil {
+		problems = append(problems, models.InspectProblem{Code: CodeNoVideoStream, Message: "file has no video stream"})
+	}
+	if len(media.AudioTracks) == 0 {
+		problems = append(problems, models.InspectProblem{Code: CodeNoAudioStream, Message: "file has no audio stream to transcribe"})
+	}
+	return problems
+}
@@ -0,0 +1,44 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/config"
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestCheckMediaLimits(t *testing.T) {
+	cfg := &config.Config{MaxVideoDuration: 10 * time.Minute, MaxVideoSizeMB: 100}
+	valid := &models.MediaInfo{
+		Duration:    120,
+		Video:       &models.VideoStream{Codec: "h264"},
+		AudioTracks: []models.AudioTrack{{Codec: "aac"}},
+	}
+
+	tests := []struct {
+		name      string
+		media     *models.MediaInfo
+		sizeBytes int64
+		want      []string
+	}{
+		{name: "within limits", media: valid, sizeBytes: 50 << 20},
+		{name: "too large, not probed", sizeBytes: 200 << 20, want: []string{CodeVideoTooLarge}},
+		{name: "too long", media: &models.MediaInfo{Duration: 900, Video: valid.Video, AudioTracks: valid.AudioTracks}, want: []string{CodeVideoTooLong}},
+		{name: "no streams", media: &models.MediaInfo{Duration: 60}, want: []string{CodeNoVideoStream, CodeNoAudioStream}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := CheckMediaLimits(tt.media, tt.sizeBytes, cfg)
+			if len(problems) != len(tt.want) {
+				t.Fatalf("expected problems %v, got %+v", tt.want, problems)
+			}
+			for i, code := range tt.want {
+				if problems[i].Code != code {
+					t.Errorf("expected problem %s, got %s", code, problems[i].Code)
+				}
+			}
+		})
+	}
+}
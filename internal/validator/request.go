@@ -2,20 +2,117 @@ package validator
 
 import (
 	"fmt"
+	"math"
+	"net/mail"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/sinouw/multilingual-video-processor/internal/config"
+	"github.com/sinouw/multilingual-video-processor/internal/ssml"
+	"github.com/sinouw/multilingual-video-processor/internal/storage"
+	"github.com/sinouw/multilingual-video-processor/internal/translation"
+	"github.com/sinouw/multilingual-video-processor/internal/tts"
 	"github.com/sinouw/multilingual-video-processor/pkg/models"
 )
 
+// Error codes for requests exceeding configured limits
+const (
+	CodeVideoURLTooLong   = "video_url_too_long"
+	CodeTooManyLanguages  = "too_many_languages"
+	CodeSourceTextTooLong = "source_text_too_long"
+)
+
+// MaxSourceTextLength bounds a supplied source transcript (in characters), which is kept in memory with the job
+const MaxSourceTextLength = 100000
+
+// LimitError is returned when a request exceeds a configured limit
+type LimitError struct {
+	Code   string
+	Field  string
+	Limit  int
+	Actual int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s exceeds limit: %d > %d", e.Field, e.Actual, e.Limit)
+}
+
+// ValidateRequestLimits checks the request against configured size limits (zero disables a limit)
+func ValidateRequestLimits(req *models.TranslateRequest, cfg *config.Config) error {
+	if cfg.MaxVideoURLLength > 0 && len(req.VideoURL) > cfg.MaxVideoURLLength {
+		return &LimitError{Code: CodeVideoURLTooLong, Field: "videoUrl length", Limit: cfg.MaxVideoURLLength, Actual: len(req.VideoURL)}
+	}
+	if cfg.MaxTargetLanguages > 0 && len(req.TargetLanguages) > cfg.MaxTargetLanguages {
+		return &LimitError{Code: CodeTooManyLanguages, Field: "targetLanguages", Limit: cfg.MaxTargetLanguages, Actual: len(req.TargetLanguages)}
+	}
+	return nil
+}
+
 // ValidateTranslateRequest validates a translation request
 func ValidateTranslateRequest(req *models.TranslateRequest, cfg *config.Config) error {
+	// Expand the all-languages shortcut so the expanded list is validated and limited like an explicit one
+	if err := ExpandTargetLanguages(req, cfg.SupportedLanguages); err != nil {
+		return fmt.Errorf("invalid target languages: %w", err)
+	}
+
+	// Check size limits before any further parsing
+	if err := ValidateRequestLimits(req, cfg); err != nil {
+		return err
+	}
+
 	// Validate video URL
 	if err := ValidateVideoURL(req.VideoURL); err != nil {
 		return fmt.Errorf("invalid video URL: %w", err)
 	}
 
+	// Validate the client-supplied job ID if provided
+	if req.JobID != "" {
+		if err := ValidateJobID(req.JobID); err != nil {
+			return fmt.Errorf("invalid jobId: %w", err)
+		}
+	}
+
+	// Validate source subtitles if provided
+	if req.SubtitleURL != "" {
+		if err := ValidateSubtitleURL(req.SubtitleURL); err != nil {
+			return fmt.Errorf("invalid subtitle URL: %w", err)
+		}
+	}
+
+	// The impersonated service account reads the source bucket
+	if req.ServiceAccount != "" && !storage.IsServiceAccount(req.ServiceAccount) {
+		return fmt.Errorf("invalid serviceAccount: %s is not a service account email", req.ServiceAccount)
+	}
+
+	// Validate the supplied source transcript if provided
+	if req.SourceText != "" {
+		if req.SubtitleURL != "" {
+			return fmt.Errorf("sourceText and subtitleUrl are mutually exclusive")
+		}
+		if strings.TrimSpace(req.SourceText) == "" {
+			return fmt.Errorf("sourceText must not be blank")
+		}
+		if length := utf8.RuneCountInString(req.SourceText); length > MaxSourceTextLength {
+			return &LimitError{Code: CodeSourceTextTooLong, Field: "sourceText length", Limit: MaxSourceTextLength, Actual: length}
+		}
+	}
+
+	// Validate speech recognition overrides if provided
+	if err := ValidateTranscriptionOptions(req.Transcription); err != nil {
+		return fmt.Errorf("invalid transcription options: %w", err)
+	}
+
+	// Narration over a silent video needs supplied text, since there is no speech to transcribe
+	if req.Narration {
+		if req.SourceText == "" && req.SubtitleURL == "" {
+			return fmt.Errorf("narration requires sourceText or subtitleUrl")
+		}
+		if req.SourceAudioTrack != nil {
+			return fmt.Errorf("narration cannot be combined with sourceAudioTrack")
+		}
+	}
+
 	// Validate target languages
 	if err := ValidateLanguageCodes(req.TargetLanguages, cfg.SupportedLanguages); err != nil {
 		return fmt.Errorf("invalid target languages: %w", err)
@@ -30,6 +127,367 @@ func ValidateTranslateRequest(req *models.TranslateRequest, cfg *config.Config)
 		}
 	}
 
+	// Validate clip range if provided
+	if err := ValidateClipRange(req.StartTime, req.EndTime, cfg.MaxSourceDuration().Seconds()); err != nil {
+		return fmt.Errorf("invalid clip range: %w", err)
+	}
+
+	// Validate source audio track if provided (existence is checked once the video is probed)
+	if req.SourceAudioTrack != nil && *req.SourceAudioTrack < 0 {
+		return fmt.Errorf("sourceAudioTrack must not be negative")
+	}
+
+	// Validate output preset if provided
+	if !models.IsValidPreset(req.Preset) {
+		return fmt.Errorf("unsupported preset: %s (supported: %s)", req.Preset, strings.Join(models.SupportedPresets, ", "))
+	}
+
+	// Validate style instructions if provided
+	if req.StyleInstructions != "" {
+		if !cfg.IsLLMTranslation() {
+			return fmt.Errorf("styleInstructions is not supported: requires an LLM translation provider")
+		}
+		if languages := nonLLMLanguages(req, cfg); len(languages) > 0 {
+			return fmt.Errorf("styleInstructions is not supported for %s: not translated by an LLM provider", strings.Join(languages, ", "))
+		}
+		if utf8.RuneCountInString(req.StyleInstructions) > translation.MaxStyleInstructionsLength {
+			return fmt.Errorf("styleInstructions must be at most %d characters", translation.MaxStyleInstructionsLength)
+		}
+	}
+
+	// Summaries, keywords and chapters are written by the LLM provider for every target language, whatever
+	// provider translates the language
+	if req.Summary {
+		if !cfg.IsLLMTranslation() {
+			return fmt.Errorf("summary is not supported: requires an LLM translation provider")
+		}
+		if cfg.SummaryMaxChars == 0 {
+			return fmt.Errorf("summary is not supported: summaries are disabled (SUMMARY_MAX_CHARS=0)")
+		}
+	}
+
+	if req.Analysis && !cfg.IsLLMTranslation() {
+		return fmt.Errorf("analysis is not supported: requires an LLM translation provider")
+	}
+
+	// Keeping the background music needs a separation backend
+	if req.KeepBackgroundMusic != nil && *req.KeepBackgroundMusic && !cfg.IsAudioSeparationEnabled() {
+		return fmt.Errorf("keepBackgroundMusic is not supported: no audio separation backend is configured")
+	}
+
+	// Validate branding overrides if provided (the assets are downloaded when the job runs)
+	if err := ValidateBranding(req.Branding); err != nil {
+		return fmt.Errorf("invalid branding: %w", err)
+	}
+
+	// Validate output audio overrides if provided
+	if err := ValidateOutputAudio(req.OutputAudio); err != nil {
+		return fmt.Errorf("invalid outputAudio: %w", err)
+	}
+
+	// Validate the dubbed audio artifact format if provided
+	if !models.IsValidAudioFormat(req.DubbedAudio) {
+		return fmt.Errorf("unsupported dubbedAudio: %s (must be mp3, wav or none)", req.DubbedAudio)
+	}
+
+	// Validate pronunciation overrides if provided
+	if err := ValidatePronunciations(req.Pronunciations, req.TargetLanguages); err != nil {
+		return fmt.Errorf("invalid pronunciations: %w", err)
+	}
+
+	// Validate the brand voice if provided: its provider must be configured and speak every target language
+	if req.VoiceID != "" {
+		if err := ValidateVoiceID(req.VoiceID, req.TargetLanguages, cfg); err != nil {
+			return fmt.Errorf("invalid voiceId: %w", err)
+		}
+	}
+
+	// Validate the voice gender if provided; a brand voice has its own gender
+	if !models.IsValidVoiceGender(req.VoiceGender) {
+		return fmt.Errorf("unsupported voiceGender: %s (must be match, male or female)", req.VoiceGender)
+	}
+	if req.VoiceGender != "" && req.VoiceID != "" {
+		return fmt.Errorf("voiceGender cannot be combined with voiceId")
+	}
+
+	// Validate the output bucket override if provided (write access is checked separately)
+	// Buckets are allowed per API key owner (req.Tenant)
+	if req.OutputBucket != "" {
+		if !cfg.HasOutputBuckets(req.Tenant) {
+			return fmt.Errorf("outputBucket is not supported: ALLOWED_OUTPUT_BUCKETS lists no bucket for this API key")
+		}
+		if !cfg.IsOutputBucketAllowed(req.Tenant, req.OutputBucket) {
+			return fmt.Errorf("outputBucket %s is not allowed", req.OutputBucket)
+		}
+	}
+
+	// Validate the subtitle layout if provided
+	if _, ok := cfg.SubtitleProfileByName(req.SubtitleProfile); req.SubtitleProfile != "" && !ok {
+		return fmt.Errorf("unknown subtitleProfile: %s", req.SubtitleProfile)
+	}
+	if req.SubtitleOffset != nil && math.Abs(*req.SubtitleOffset) > models.MaxSubtitleOffset {
+		return fmt.Errorf("subtitleOffset must be between -%d and %d seconds", models.MaxSubtitleOffset, models.MaxSubtitleOffset)
+	}
+
+	// Validate output destinations if provided (write access is checked separately)
+	if err := ValidateOutputDestinations(req.OutputDestinations, req.TargetLanguages); err != nil {
+		return fmt.Errorf("invalid outputDestinations: %w", err)
+	}
+	// Their buckets are allowlisted like outputBucket, or any bucket the service account can write to would do
+	if len(req.OutputDestinations) > 0 && !cfg.HasOutputBuckets(req.Tenant) {
+		return fmt.Errorf("outputDestinations is not supported: ALLOWED_OUTPUT_BUCKETS lists no bucket for this API key")
+	}
+	for lang, destination := range req.OutputDestinations {
+		if parsed, _ := storage.ParseDestination(destination); !cfg.IsOutputBucketAllowed(req.Tenant, parsed.Bucket) {
+			return fmt.Errorf("invalid outputDestinations: bucket %s of %s is not allowed", parsed.Bucket, lang)
+		}
+	}
+
+	// Validate webhook event selection if provided
+	if len(req.WebhookEvents) > 0 {
+		if cfg.WebhookURL == "" {
+			return fmt.Errorf("webhookEvents is not supported: no webhook is configured")
+		}
+		for _, event := range req.WebhookEvents {
+			if !models.IsValidWebhookEvent(event) {
+				return fmt.Errorf("unsupported webhook event: %s (supported: %s)", event, strings.Join(models.SupportedWebhookEvents, ", "))
+			}
+		}
+	}
+
+	// Validate tags and metadata if provided
+	if err := ValidateTagsAndMetadata(req.Tags, req.Metadata); err != nil {
+		return err
+	}
+
+	// Validate notification email if provided
+	if req.NotifyEmail != "" {
+		if !cfg.IsEmailEnabled() {
+			return fmt.Errorf("notifyEmail is not supported: email notifications are not configured")
+		}
+		if err := ValidateEmailAddress(req.NotifyEmail); err != nil {
+			return fmt.Errorf("invalid notifyEmail: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// nonLLMLanguages returns the requested target languages whose primary translation provider is not an LLM;
+// an all-languages request targets every supported language
+func nonLLMLanguages(req *models.TranslateRequest, cfg *config.Config) []string {
+	targets := req.TargetLanguages
+	if req.WantsAllLanguages() {
+		targets = cfg.SupportedLanguages
+	}
+	var languages []string
+	for _, lang := range targets {
+		if !cfg.IsLLMTranslated(lang) {
+			languages = append(languages, lang)
+		}
+	}
+	return languages
+}
+
+// ExpandTargetLanguages replaces targetLanguages ["*"] or allLanguages with every supported language,
+// excluding the source language when one is given; the detected source is excluded during processing
+func ExpandTargetLanguages(req *models.TranslateRequest, supportedLanguages []string) error {
+	if !req.WantsAllLanguages() {
+		for _, lang := range req.TargetLanguages {
+			if lang == models.AllLanguagesWildcard {
+				return fmt.Errorf("%q cannot be combined with other target languages", models.AllLanguagesWildcard)
+			}
+		}
+		return nil
+	}
+	if req.AllLanguages && len(req.TargetLanguages) > 0 && !(len(req.TargetLanguages) == 1 && req.TargetLanguages[0] == models.AllLanguagesWildcard) {
+		return fmt.Errorf("allLanguages cannot be combined with explicit target languages")
+	}
+
+	targets, _ := ExcludeSourceLanguage(supportedLanguages, req.SourceLanguage)
+	if len(targets) == 0 {
+		return fmt.Errorf("no supported languages other than the source language %s", req.SourceLanguage)
+	}
+
+	req.AllLanguages = true
+	req.TargetLanguages = targets
+	return nil
+}
+
+// ExcludeSourceLanguage splits languages into those to translate to and those matching the source language
+// Languages match on their base code, so a detected "en-US" source excludes "en".
+func ExcludeSourceLanguage(languages []string, source string) ([]string, []string) {
+	var kept, skipped []string
+	for _, lang := range languages {
+		if IsSameLanguage(source, lang) {
+			skipped = append(skipped, lang)
+		} else {
+			kept = append(kept, lang)
+		}
+	}
+	return kept, skipped
+}
+
+// IsSameLanguage reports whether a target language matches the source language on their base code
+// An unknown source (empty or "auto") matches nothing.
+func IsSameLanguage(source string, target string) bool {
+	sourceBase := baseLanguage(source)
+	return sourceBase != "" && sourceBase == baseLanguage(target)
+}
+
+// baseLanguage returns the lowercase primary subtag of a language code (e.g., "en-US" -> "en")
+func baseLanguage(code string) string {
+	base, _, _ := strings.Cut(strings.ToLower(code), "-")
+	return base
+}
+
+// ValidateClipRange validates optional startTime/endTime seconds
+// An end of 0 means the end of the video; a positive maxDuration bounds the clip length when end is set.
+func ValidateClipRange(start float64, end float64, maxDuration float64) error {
+	if start < 0 || end < 0 {
+		return fmt.Errorf("startTime and endTime must not be negative")
+	}
+	if end > 0 && end <= start {
+		return fmt.Errorf("endTime must be greater than startTime")
+	}
+	if end > 0 && maxDuration > 0 && end-start > maxDuration {
+		return fmt.Errorf("clip length exceeds maximum video duration: %.2fs > %.2fs", end-start, maxDuration)
+	}
+	return nil
+}
+
+// ValidateOutputDestinations validates per-language output locations
+// Each language must be a requested target language and each destination a gs://bucket[/prefix] URL
+func ValidateOutputDestinations(destinations map[string]string, targetLanguages []string) error {
+	for lang, destination := range destinations {
+		if !containsLanguage(targetLanguages, lang) {
+			return fmt.Errorf("language %s is not a target language", lang)
+		}
+		if _, err := storage.ParseDestination(destination); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bounds on job tags and metadata, which are kept in memory and sent with every notification
+const (
+	MaxTags             = 20
+	MaxTagLength        = 64
+	MaxMetadataEntries  = 20
+	MaxMetadataKeyLen   = 64
+	MaxMetadataValueLen = 512
+)
+
+// ValidateVoiceID checks a custom voice can dub every target language with the configured providers
+func ValidateVoiceID(voiceID string, targetLanguages []string, cfg *config.Config) error {
+	voice, err := tts.ParseVoiceID(voiceID)
+	if err != nil {
+		return err
+	}
+	if voice.Provider == tts.VoiceProviderElevenLabs && cfg.ElevenLabsAPIKey == "" && !cfg.DevMockProviders {
+		return fmt.Errorf("%s voices are not supported: ELEVENLABS_API_KEY is not configured", voice.Provider)
+	}
+	var unsupported []string
+	for _, lang := range targetLanguages {
+		if !voice.SupportsLanguage(lang) {
+			unsupported = append(unsupported, lang)
+		}
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("voice %s does not support target languages: %s", voice, strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+// ValidateTagsAndMetadata validates job tags and metadata against their size limits
+func ValidateTagsAndMetadata(tags []string, metadata map[string]string) error {
+	if len(tags) > MaxTags {
+		return fmt.Errorf("too many tags: %d (maximum: %d)", len(tags), MaxTags)
+	}
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("tags must not be empty")
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return fmt.Errorf("tag %q is longer than %d characters", tag, MaxTagLength)
+		}
+	}
+
+	if len(metadata) > MaxMetadataEntries {
+		return fmt.Errorf("too many metadata entries: %d (maximum: %d)", len(metadata), MaxMetadataEntries)
+	}
+	for key, value := range metadata {
+		if key == "" {
+			return fmt.Errorf("metadata keys must not be empty")
+		}
+		if utf8.RuneCountInString(key) > MaxMetadataKeyLen {
+			return fmt.Errorf("metadata key %q is longer than %d characters", key, MaxMetadataKeyLen)
+		}
+		if utf8.RuneCountInString(value) > MaxMetadataValueLen {
+			return fmt.Errorf("metadata value for %q is longer than %d characters", key, MaxMetadataValueLen)
+		}
+	}
+	return nil
+}
+
+// MaxPronunciationsPerLanguage bounds the size of a request's lexicon
+const MaxPronunciationsPerLanguage = 100
+
+// ValidatePronunciations validates per-language pronunciation overrides
+// Each language must be a requested target language and each entry needs a word and exactly one of phoneme,
+// alias or a well-formed SSML fragment
+func ValidatePronunciations(pronunciations map[string][]models.Pronunciation, targetLanguages []string) error {
+	for lang, entries := range pronunciations {
+		if !containsLanguage(targetLanguages, lang) {
+			return fmt.Errorf("language %s is not a target language", lang)
+		}
+
+		if len(entries) > MaxPronunciationsPerLanguage {
+			return fmt.Errorf("too many entries for %s: %d (maximum: %d)", lang, len(entries), MaxPronunciationsPerLanguage)
+		}
+
+		for _, entry := range entries {
+			if strings.TrimSpace(entry.Word) == "" {
+				return fmt.Errorf("word is required for every entry (%s)", lang)
+			}
+			set := 0
+			for _, value := range []string{entry.Phoneme, entry.Alias, entry.SSML} {
+				if value != "" {
+					set++
+				}
+			}
+			if set != 1 {
+				return fmt.Errorf("entry %q for %s must set exactly one of phoneme, alias or ssml", entry.Word, lang)
+			}
+			if entry.SSML != "" {
+				if _, err := ssml.Sanitize(entry.SSML); err != nil {
+					return fmt.Errorf("entry %q for %s: %w", entry.Word, lang, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func containsLanguage(languages []string, lang string) bool {
+	for _, candidate := range languages {
+		if candidate == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateEmailAddress validates a single bare email address (no display name)
+func ValidateEmailAddress(address string) error {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return fmt.Errorf("malformed email address: %s", address)
+	}
+	if parsed.Address != address {
+		return fmt.Errorf("email address must not include a display name: %s", address)
+	}
 	return nil
 }
 
@@ -62,6 +520,116 @@ func ValidateVideoURL(url string) error {
 	return fmt.Errorf("unsupported URL format: %s (must be gs:// or https://)", url)
 }
 
+// MaxJobIDLength bounds a client-supplied job ID, which appears in output paths and status URLs
+const MaxJobIDLength = 64
+
+// jobIDPattern matches UUIDs and slugs: letters, digits, hyphens and underscores, starting with a letter or digit
+var jobIDPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// ValidateJobID validates a client-supplied job ID (a UUID or slug)
+func ValidateJobID(jobID string) error {
+	if len(jobID) > MaxJobIDLength {
+		return fmt.Errorf("job ID is longer than %d characters", MaxJobIDLength)
+	}
+	if !jobIDPattern.MatchString(jobID) {
+		return fmt.Errorf("job ID must be a UUID or slug (letters, digits, hyphens and underscores): %s", jobID)
+	}
+	return nil
+}
+
+// ValidateTranscriptionOptions validates per-request speech recognition overrides (nil is valid)
+func ValidateTranscriptionOptions(opts *models.TranscriptionOptions) error {
+	if opts == nil {
+		return nil
+	}
+	if !models.IsValidSTTModel(opts.Model) {
+		return fmt.Errorf("unsupported model: %s (supported: %s)", opts.Model, strings.Join(models.SupportedSTTModels, ", "))
+	}
+	if len(opts.AlternativeLanguages) > models.MaxAlternativeLanguages {
+		return fmt.Errorf("too many alternative languages: %d (maximum: %d)", len(opts.AlternativeLanguages), models.MaxAlternativeLanguages)
+	}
+	for _, lang := range opts.AlternativeLanguages {
+		if !isValidLanguageCode(lang) {
+			return fmt.Errorf("invalid alternative language code: %s", lang)
+		}
+	}
+	if opts.AudioChannel != nil && *opts.AudioChannel < 0 {
+		return fmt.Errorf("audioChannel must not be negative")
+	}
+	if len(opts.HintPhrases) > models.MaxHintPhrases {
+		return fmt.Errorf("too many hint phrases: %d (maximum: %d)", len(opts.HintPhrases), models.MaxHintPhrases)
+	}
+	for _, phrase := range opts.HintPhrases {
+		if strings.TrimSpace(phrase) == "" {
+			return fmt.Errorf("hint phrases must not be empty")
+		}
+		if utf8.RuneCountInString(phrase) > models.MaxHintPhraseLength {
+			return fmt.Errorf("hint phrase exceeds %d characters: %s", models.MaxHintPhraseLength, phrase)
+		}
+	}
+	return nil
+}
+
+// ValidateSubtitleURL validates a source subtitles URL: a Cloud Storage URL to an .srt or .vtt file
+func ValidateSubtitleURL(url string) error {
+	if err := ValidateVideoURL(url); err != nil {
+		return err
+	}
+	// Subtitles are read with the GCS API; other HTTPS hosts are not fetched
+	if strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "https://storage.googleapis.com/") {
+		return fmt.Errorf("unsupported subtitle URL: %s (must be gs:// or https://storage.googleapis.com/)", url)
+	}
+	path := strings.ToLower(url)
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	if !strings.HasSuffix(path, ".srt") && !strings.HasSuffix(path, ".vtt") {
+		return fmt.Errorf("unsupported subtitle format: %s (expected .srt or .vtt)", url)
+	}
+	return nil
+}
+
+// ValidateOutputAudio checks the sample rate, bitrate and channel layout of a request's output audio overrides
+func ValidateOutputAudio(opts *models.OutputAudioOptions) error {
+	if opts == nil {
+		return nil
+	}
+	if !models.IsValidSampleRate(opts.SampleRate) {
+		return fmt.Errorf("unsupported sampleRate: %d (supported: %v)", opts.SampleRate, models.SupportedSampleRates)
+	}
+	if !models.IsValidAudioBitrate(opts.Bitrate) {
+		return fmt.Errorf("bitrate must be between %d and %d kbps", models.MinAudioBitrate, models.MaxAudioBitrate)
+	}
+	if opts.ChannelLayout != "" && models.ChannelCount(opts.ChannelLayout) == 0 {
+		return fmt.Errorf("unsupported channelLayout: %s (must be mono or stereo)", opts.ChannelLayout)
+	}
+	return nil
+}
+
+// ValidateBranding checks the asset URLs and watermark position of a request's branding overrides
+func ValidateBranding(branding *models.BrandingOptions) error {
+	if branding == nil {
+		return nil
+	}
+	assets := []struct{ field, url string }{
+		{"watermarkUrl", branding.WatermarkURL},
+		{"introUrl", branding.IntroURL},
+		{"outroUrl", branding.OutroURL},
+	}
+	for _, asset := range assets {
+		if asset.url == "" {
+			continue
+		}
+		if _, _, err := storage.ParseGCSURL(asset.url); err != nil {
+			return fmt.Errorf("invalid %s: %w", asset.field, err)
+		}
+	}
+	if !models.IsValidWatermarkPosition(branding.WatermarkPosition) {
+		return fmt.Errorf("unsupported watermarkPosition: %s (supported: %s)", branding.WatermarkPosition, strings.Join(models.WatermarkPositions, ", "))
+	}
+	return nil
+}
+
 // isValidLanguageCode performs basic language code validation (ISO 639-1 format)
 func isValidLanguageCode(code string) bool {
 	// Basic validation: 2-5 character language code (e.g., "en", "en-US")
@@ -1,6 +1,9 @@
 package validator
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/sinouw/multilingual-video-processor/internal/config"
@@ -41,6 +44,78 @@ func TestValidateTranslateRequest(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"dubbed audio as WAV",
+			&models.TranslateRequest{
+				VideoURL:        "gs://bucket/video.mp4",
+				TargetLanguages: []string{"en"},
+				DubbedAudio:     models.AudioFormatWAV,
+			},
+			false,
+		},
+		{
+			"unsupported dubbed audio format",
+			&models.TranslateRequest{
+				VideoURL:        "gs://bucket/video.mp4",
+				TargetLanguages: []string{"en"},
+				DubbedAudio:     "flac",
+			},
+			true,
+		},
+		{
+			"matching voice gender",
+			&models.TranslateRequest{
+				VideoURL:        "gs://bucket/video.mp4",
+				TargetLanguages: []string{"en"},
+				VoiceGender:     models.VoiceGenderMatch,
+			},
+			false,
+		},
+		{
+			"unsupported voice gender",
+			&models.TranslateRequest{
+				VideoURL:        "gs://bucket/video.mp4",
+				TargetLanguages: []string{"en"},
+				VoiceGender:     "neutral",
+			},
+			true,
+		},
+		{
+			"accessibility preset",
+			&models.TranslateRequest{
+				VideoURL:        "gs://bucket/video.mp4",
+				TargetLanguages: []string{"en"},
+				Preset:          models.PresetAccessibility,
+			},
+			false,
+		},
+		{
+			"unsupported preset",
+			&models.TranslateRequest{
+				VideoURL:        "gs://bucket/video.mp4",
+				TargetLanguages: []string{"en"},
+				Preset:          "cinema",
+			},
+			true,
+		},
+		{
+			"source audio track",
+			&models.TranslateRequest{
+				VideoURL:         "gs://bucket/video.mp4",
+				TargetLanguages:  []string{"en"},
+				SourceAudioTrack: intPtr(1),
+			},
+			false,
+		},
+		{
+			"negative source audio track",
+			&models.TranslateRequest{
+				VideoURL:         "gs://bucket/video.mp4",
+				TargetLanguages:  []string{"en"},
+				SourceAudioTrack: intPtr(-1),
+			},
+			true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -53,6 +128,575 @@ func TestValidateTranslateRequest(t *testing.T) {
 	}
 }
 
+func TestExpandTargetLanguages(t *testing.T) {
+	supported := []string{"en", "ar", "de"}
+
+	tests := []struct {
+		name    string
+		req     *models.TranslateRequest
+		want    []string
+		wantErr bool
+	}{
+		{"explicit languages untouched", &models.TranslateRequest{TargetLanguages: []string{"de"}}, []string{"de"}, false},
+		{"wildcard", &models.TranslateRequest{TargetLanguages: []string{"*"}}, []string{"en", "ar", "de"}, false},
+		{"allLanguages flag", &models.TranslateRequest{AllLanguages: true}, []string{"en", "ar", "de"}, false},
+		{"excludes source language", &models.TranslateRequest{TargetLanguages: []string{"*"}, SourceLanguage: "en-US"}, []string{"ar", "de"}, false},
+		{"wildcard with other languages", &models.TranslateRequest{TargetLanguages: []string{"*", "de"}}, nil, true},
+		{"allLanguages with explicit languages", &models.TranslateRequest{AllLanguages: true, TargetLanguages: []string{"de"}}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ExpandTargetLanguages(tt.req, supported)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandTargetLanguages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.req.TargetLanguages, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, tt.req.TargetLanguages)
+			}
+		})
+	}
+}
+
+func TestExpandTargetLanguages_OnlySourceSupported(t *testing.T) {
+	req := &models.TranslateRequest{AllLanguages: true, SourceLanguage: "en"}
+	if err := ExpandTargetLanguages(req, []string{"en"}); err == nil {
+		t.Error("expected error when the source is the only supported language")
+	}
+}
+
+func TestExcludeSourceLanguage(t *testing.T) {
+	kept, skipped := ExcludeSourceLanguage([]string{"en", "ar", "de"}, "de-DE")
+	if !reflect.DeepEqual(kept, []string{"en", "ar"}) {
+		t.Errorf("expected kept [en ar], got %v", kept)
+	}
+	if !reflect.DeepEqual(skipped, []string{"de"}) {
+		t.Errorf("expected skipped [de], got %v", skipped)
+	}
+
+	kept, skipped = ExcludeSourceLanguage([]string{"en", "ar"}, "")
+	if len(kept) != 2 || len(skipped) != 0 {
+		t.Errorf("expected nothing skipped for unknown source, got kept %v, skipped %v", kept, skipped)
+	}
+}
+
+func TestIsSameLanguage(t *testing.T) {
+	tests := []struct {
+		source string
+		target string
+		want   bool
+	}{
+		{"en", "en", true},
+		{"en-US", "en", true},
+		{"pt-BR", "PT-pt", true},
+		{"en", "de", false},
+		{"auto", "en", false},
+		{"", "en", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsSameLanguage(tt.source, tt.target); got != tt.want {
+			t.Errorf("IsSameLanguage(%q, %q): expected %v, got %v", tt.source, tt.target, tt.want, got)
+		}
+	}
+}
+
+func TestValidateTranslateRequest_WebhookEvents(t *testing.T) {
+	req := &models.TranslateRequest{
+		VideoURL:        "gs://bucket/video.mp4",
+		TargetLanguages: []string{"en"},
+		WebhookEvents:   []string{"job.progress"},
+	}
+
+	disabled := &config.Config{SupportedLanguages: []string{"en"}}
+	if err := ValidateTranslateRequest(req, disabled); err == nil {
+		t.Error("expected error when no webhook is configured")
+	}
+
+	enabled := &config.Config{SupportedLanguages: []string{"en"}, WebhookURL: "https://example.com/hook"}
+	if err := ValidateTranslateRequest(req, enabled); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	req.WebhookEvents = []string{"job.queued"}
+	if err := ValidateTranslateRequest(req, enabled); err == nil {
+		t.Error("expected error for unknown webhook event")
+	}
+}
+
+func TestValidateTranslateRequest_SourceText(t *testing.T) {
+	cfg := &config.Config{SupportedLanguages: []string{"en", "de"}}
+	req := &models.TranslateRequest{
+		VideoURL:        "gs://bucket/video.mp4",
+		TargetLanguages: []string{"de"},
+		SourceText:      "Hello and welcome.",
+	}
+	if err := ValidateTranslateRequest(req, cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	req.SubtitleURL = "gs://bucket/video.srt"
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error when combined with subtitleUrl")
+	}
+
+	req.SubtitleURL = ""
+	req.SourceText = "   "
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for blank source text")
+	}
+
+	req.SourceText = strings.Repeat("a", MaxSourceTextLength+1)
+	var limitErr *LimitError
+	if err := ValidateTranslateRequest(req, cfg); !errors.As(err, &limitErr) || limitErr.Code != CodeSourceTextTooLong {
+		t.Errorf("expected %s limit error, got %v", CodeSourceTextTooLong, err)
+	}
+}
+
+func TestValidateTranslateRequest_Narration(t *testing.T) {
+	cfg := &config.Config{SupportedLanguages: []string{"en", "de"}}
+	track := 0
+
+	tests := []struct {
+		name    string
+		req     models.TranslateRequest
+		wantErr bool
+	}{
+		{"with source text", models.TranslateRequest{SourceText: "Welcome."}, false},
+		{"with subtitles", models.TranslateRequest{SubtitleURL: "gs://bucket/video.vtt"}, false},
+		{"without text", models.TranslateRequest{}, true},
+		{"with audio track", models.TranslateRequest{SourceText: "Welcome.", SourceAudioTrack: &track}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			req.VideoURL = "gs://bucket/video.mp4"
+			req.TargetLanguages = []string{"de"}
+			req.Narration = true
+			err := ValidateTranslateRequest(&req, cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTranslateRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTranslateRequest_NotifyEmail(t *testing.T) {
+	req := &models.TranslateRequest{
+		VideoURL:        "gs://bucket/video.mp4",
+		TargetLanguages: []string{"en"},
+		NotifyEmail:     "user@example.com",
+	}
+
+	disabled := &config.Config{SupportedLanguages: []string{"en"}}
+	if err := ValidateTranslateRequest(req, disabled); err == nil {
+		t.Error("expected error when email notifications are not configured")
+	}
+
+	enabled := &config.Config{SupportedLanguages: []string{"en"}, EmailProvider: "smtp"}
+	if err := ValidateTranslateRequest(req, enabled); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	req.NotifyEmail = "not-an-email"
+	if err := ValidateTranslateRequest(req, enabled); err == nil {
+		t.Error("expected error for malformed email")
+	}
+}
+
+func TestValidateTranslateRequest_ServiceAccount(t *testing.T) {
+	cfg := &config.Config{SupportedLanguages: []string{"en"}}
+	req := &models.TranslateRequest{
+		VideoURL:        "gs://bucket/video.mp4",
+		TargetLanguages: []string{"en"},
+		ServiceAccount:  "reader@acme.iam.gserviceaccount.com",
+	}
+	if err := ValidateTranslateRequest(req, cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	req.ServiceAccount = "user@example.com"
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for an email that is not a service account")
+	}
+}
+
+func TestValidateTranslateRequest_VoiceID(t *testing.T) {
+	cfg := &config.Config{SupportedLanguages: []string{"en", "de", "sw"}}
+	req := &models.TranslateRequest{
+		VideoURL:        "gs://bucket/video.mp4",
+		TargetLanguages: []string{"de"},
+		VoiceID:         "elevenlabs:21m00Tcm4TlvDq8ikWAM",
+	}
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for an ElevenLabs voice without ELEVENLABS_API_KEY")
+	}
+
+	cfg.ElevenLabsAPIKey = "secret"
+	if err := ValidateTranslateRequest(req, cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	req.TargetLanguages = []string{"de", "sw"}
+	err := ValidateTranslateRequest(req, cfg)
+	if err == nil || !strings.Contains(err.Error(), "sw") {
+		t.Errorf("expected error naming the unsupported language, got %v", err)
+	}
+
+	// The all-languages shortcut is expanded before the voice is checked against it
+	allLanguages := &models.TranslateRequest{VideoURL: "gs://bucket/video.mp4", AllLanguages: true, VoiceID: req.VoiceID}
+	err = ValidateTranslateRequest(allLanguages, cfg)
+	if err == nil || !strings.Contains(err.Error(), "sw") {
+		t.Errorf("expected error naming the unsupported expanded language, got %v", err)
+	}
+
+	req.TargetLanguages = []string{"en"}
+	req.VoiceID = "google:projects/acme/locations/global/models/brand@de-DE"
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for a Custom Voice model trained in another language")
+	}
+
+	req.VoiceID = "brand-voice"
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for a voiceId without provider")
+	}
+
+	req.VoiceID = "elevenlabs:21m00Tcm4TlvDq8ikWAM"
+	req.VoiceGender = models.VoiceGenderMale
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for voiceGender combined with voiceId")
+	}
+}
+
+func TestValidateTranslateRequest_OutputBucket(t *testing.T) {
+	cfg := &config.Config{SupportedLanguages: []string{"en"}}
+	req := &models.TranslateRequest{
+		VideoURL:        "gs://bucket/video.mp4",
+		TargetLanguages: []string{"en"},
+		OutputBucket:    "team-a-outputs",
+	}
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for outputBucket without ALLOWED_OUTPUT_BUCKETS")
+	}
+
+	cfg.AllowedOutputBuckets = map[string][]string{"team-a": {"team-a-outputs"}, "team-b": {"team-b-outputs"}}
+	req.Tenant = "team-a"
+	if err := ValidateTranslateRequest(req, cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Buckets allowed to another API key owner are refused
+	req.OutputBucket = "team-b-outputs"
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for a bucket allowed to another owner")
+	}
+
+	req.Tenant = "team-c"
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for an owner without allowed buckets")
+	}
+}
+
+func TestValidateTranslateRequest_OutputDestinationBuckets(t *testing.T) {
+	cfg := &config.Config{SupportedLanguages: []string{"en", "de"}}
+	req := &models.TranslateRequest{
+		VideoURL:           "gs://bucket/video.mp4",
+		TargetLanguages:    []string{"en", "de"},
+		OutputDestinations: map[string]string{"de": "gs://eu-cdn/dubbed"},
+	}
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for outputDestinations without ALLOWED_OUTPUT_BUCKETS")
+	}
+
+	cfg.AllowedOutputBuckets = map[string][]string{"team-a": {"eu-cdn"}, "team-b": {"team-b-outputs"}}
+	req.Tenant = "team-a"
+	if err := ValidateTranslateRequest(req, cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	req.OutputDestinations["en"] = "gs://team-b-outputs"
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for a destination bucket allowed to another owner")
+	}
+}
+
+func TestValidateTranslateRequest_Subtitles(t *testing.T) {
+	cfg := &config.Config{
+		SupportedLanguages: []string{"en"},
+		SubtitleProfiles:   map[string]models.SubtitleProfile{"mobile": {MaxLineChars: 32, MaxLines: 2}},
+	}
+	req := &models.TranslateRequest{
+		VideoURL:        "gs://bucket/video.mp4",
+		TargetLanguages: []string{"en"},
+	}
+	for _, profile := range []string{"broadcast", "mobile"} {
+		req.SubtitleProfile = profile
+		if err := ValidateTranslateRequest(req, cfg); err != nil {
+			t.Errorf("unexpected error for profile %s: %v", profile, err)
+		}
+	}
+
+	req.SubtitleProfile = "cinema"
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for an unknown subtitleProfile")
+	}
+
+	req.SubtitleProfile = ""
+	offset := -models.MaxSubtitleOffset - 1.0
+	req.SubtitleOffset = &offset
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for a subtitleOffset out of range")
+	}
+}
+
+func TestValidateTranslateRequest_StyleInstructions(t *testing.T) {
+	req := &models.TranslateRequest{
+		VideoURL:          "gs://bucket/video.mp4",
+		TargetLanguages:   []string{"en"},
+		StyleInstructions: "formal tone, keep jokes",
+	}
+
+	google := &config.Config{SupportedLanguages: []string{"en"}, TranslationProvider: "google"}
+	if err := ValidateTranslateRequest(req, google); err == nil {
+		t.Error("expected error when translation provider is not an LLM")
+	}
+
+	llm := &config.Config{SupportedLanguages: []string{"en"}, TranslationProvider: "openai"}
+	if err := ValidateTranslateRequest(req, llm); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	routed := &config.Config{
+		SupportedLanguages:  []string{"en", "de"},
+		TranslationProvider: "openai",
+		TranslationRoutes:   map[string][]string{"de": {"deepl", "openai"}},
+	}
+	if err := ValidateTranslateRequest(req, routed); err != nil {
+		t.Errorf("unexpected error for a language translated by the LLM: %v", err)
+	}
+	req.TargetLanguages = []string{"en", "de"}
+	if err := ValidateTranslateRequest(req, routed); err == nil || !strings.Contains(err.Error(), "de") {
+		t.Errorf("expected error naming the language routed to DeepL, got %v", err)
+	}
+
+	req.TargetLanguages = []string{"en"}
+	req.StyleInstructions = strings.Repeat("x", 501)
+	if err := ValidateTranslateRequest(req, llm); err == nil {
+		t.Error("expected error for overly long style instructions")
+	}
+}
+
+func TestValidateTranslateRequest_Summary(t *testing.T) {
+	req := &models.TranslateRequest{
+		VideoURL:        "gs://bucket/video.mp4",
+		TargetLanguages: []string{"en"},
+		Summary:         true,
+	}
+
+	google := &config.Config{SupportedLanguages: []string{"en"}, TranslationProvider: "google", SummaryMaxChars: 500}
+	if err := ValidateTranslateRequest(req, google); err == nil {
+		t.Error("expected error when translation provider is not an LLM")
+	}
+
+	llm := &config.Config{SupportedLanguages: []string{"en"}, TranslationProvider: "openai", SummaryMaxChars: 500}
+	if err := ValidateTranslateRequest(req, llm); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	llm.SummaryMaxChars = 0
+	if err := ValidateTranslateRequest(req, llm); err == nil {
+		t.Error("expected error when summaries are disabled")
+	}
+}
+
+func TestValidateTranslateRequest_Analysis(t *testing.T) {
+	req := &models.TranslateRequest{
+		VideoURL:        "gs://bucket/video.mp4",
+		TargetLanguages: []string{"en"},
+		Analysis:        true,
+	}
+
+	google := &config.Config{SupportedLanguages: []string{"en"}, TranslationProvider: "google"}
+	if err := ValidateTranslateRequest(req, google); err == nil {
+		t.Error("expected error when translation provider is not an LLM")
+	}
+
+	llm := &config.Config{SupportedLanguages: []string{"en"}, TranslationProvider: "anthropic"}
+	if err := ValidateTranslateRequest(req, llm); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateTranslateRequest_KeepBackgroundMusic(t *testing.T) {
+	keep, replace := true, false
+	req := &models.TranslateRequest{
+		VideoURL:            "gs://bucket/video.mp4",
+		TargetLanguages:     []string{"en"},
+		KeepBackgroundMusic: &keep,
+	}
+
+	cfg := &config.Config{SupportedLanguages: []string{"en"}}
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error when no separation backend is configured")
+	}
+
+	req.KeepBackgroundMusic = &replace
+	if err := ValidateTranslateRequest(req, cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.AudioSeparation = "demucs"
+	req.KeepBackgroundMusic = &keep
+	if err := ValidateTranslateRequest(req, cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateBranding(t *testing.T) {
+	tests := []struct {
+		name     string
+		branding *models.BrandingOptions
+		wantErr  bool
+	}{
+		{"none", nil, false},
+		{"watermark and bumpers", &models.BrandingOptions{WatermarkURL: "gs://brand/logo.png", WatermarkPosition: "top-right", IntroURL: "gs://brand/intro.mp4", OutroURL: "gs://brand/outro.mp4"}, false},
+		{"position only", &models.BrandingOptions{WatermarkPosition: "bottom-left"}, false},
+		{"unsupported URL", &models.BrandingOptions{IntroURL: "ftp://brand/intro.mp4"}, true},
+		{"bucket without object", &models.BrandingOptions{WatermarkURL: "gs://brand"}, true},
+		{"unknown position", &models.BrandingOptions{WatermarkPosition: "center"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBranding(tt.branding)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateRequestLimits(t *testing.T) {
+	cfg := &config.Config{
+		SupportedLanguages: []string{"en", "ar", "de"},
+		MaxTargetLanguages: 2,
+		MaxVideoURLLength:  40,
+	}
+
+	tests := []struct {
+		name     string
+		req      *models.TranslateRequest
+		wantCode string
+	}{
+		{"within limits", &models.TranslateRequest{VideoURL: "gs://bucket/video.mp4", TargetLanguages: []string{"en", "de"}}, ""},
+		{"too many languages", &models.TranslateRequest{VideoURL: "gs://bucket/video.mp4", TargetLanguages: []string{"en", "ar", "de"}}, CodeTooManyLanguages},
+		{"url too long", &models.TranslateRequest{VideoURL: "gs://bucket/" + strings.Repeat("a", 40), TargetLanguages: []string{"en"}}, CodeVideoURLTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTranslateRequest(tt.req, cfg)
+			var limitErr *LimitError
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("expected LimitError, got %v", err)
+			}
+			if limitErr.Code != tt.wantCode {
+				t.Errorf("expected code %s, got %s", tt.wantCode, limitErr.Code)
+			}
+		})
+	}
+}
+
+func TestValidatePronunciations(t *testing.T) {
+	targets := []string{"en", "de"}
+
+	tests := []struct {
+		name           string
+		pronunciations map[string][]models.Pronunciation
+		wantErr        bool
+	}{
+		{"none", nil, false},
+		{"alias and phoneme", map[string][]models.Pronunciation{
+			"en": {{Word: "Nginx", Alias: "engine x"}},
+			"de": {{Word: "Kubernetes", Phoneme: "kuːbɐˈneːtəs"}},
+		}, false},
+		{"language not requested", map[string][]models.Pronunciation{"ru": {{Word: "a", Alias: "b"}}}, true},
+		{"missing word", map[string][]models.Pronunciation{"en": {{Alias: "b"}}}, true},
+		{"both phoneme and alias", map[string][]models.Pronunciation{"en": {{Word: "a", Alias: "b", Phoneme: "c"}}}, true},
+		{"neither phoneme nor alias", map[string][]models.Pronunciation{"en": {{Word: "a"}}}, true},
+		{"ssml", map[string][]models.Pronunciation{"en": {{Word: "SQL", SSML: `<say-as interpret-as="characters">SQL</say-as>`}}}, false},
+		{"ssml and alias", map[string][]models.Pronunciation{"en": {{Word: "SQL", SSML: "sequel", Alias: "sequel"}}}, true},
+		{"malformed ssml", map[string][]models.Pronunciation{"en": {{Word: "SQL", SSML: "<say-as>SQL"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePronunciations(tt.pronunciations, targets)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePronunciations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateOutputDestinations(t *testing.T) {
+	targets := []string{"en", "de"}
+
+	tests := []struct {
+		name         string
+		destinations map[string]string
+		wantErr      bool
+	}{
+		{"none", nil, false},
+		{"bucket and prefix", map[string]string{"en": "gs://us-cdn", "de": "gs://eu-cdn/dubbed"}, false},
+		{"language not requested", map[string]string{"fr": "gs://eu-cdn"}, true},
+		{"not a gs URL", map[string]string{"de": "https://eu-cdn.example.com"}, true},
+		{"missing bucket", map[string]string{"de": "gs://"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOutputDestinations(tt.destinations, targets)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateOutputDestinations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTagsAndMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []string
+		metadata map[string]string
+		wantErr  bool
+	}{
+		{"none", nil, nil, false},
+		{"tags and metadata", []string{"campaign", "spring"}, map[string]string{"assetId": "42"}, false},
+		{"empty tag", []string{" "}, nil, true},
+		{"long tag", []string{strings.Repeat("a", MaxTagLength+1)}, nil, true},
+		{"too many tags", make([]string, MaxTags+1), nil, true},
+		{"empty metadata key", nil, map[string]string{"": "x"}, true},
+		{"long metadata value", nil, map[string]string{"notes": strings.Repeat("a", MaxMetadataValueLen+1)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTagsAndMetadata(tt.tags, tt.metadata)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTagsAndMetadata() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateVideoURL(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -75,3 +719,153 @@ func TestValidateVideoURL(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSubtitleURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"SRT in GCS", "gs://bucket/captions/video.srt", false},
+		{"VTT in Cloud Storage over HTTPS", "https://storage.googleapis.com/bucket/captions/video.VTT", false},
+		{"VTT on another HTTPS host", "https://example.com/captions/video.vtt?token=abc", true},
+		{"unsupported extension", "gs://bucket/captions/video.ass", true},
+		{"invalid URL", "captions.srt", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSubtitleURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSubtitleURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTranslateRequest_SubtitleURLOverHTTPS(t *testing.T) {
+	cfg := &config.Config{SupportedLanguages: []string{"en", "de"}}
+	req := &models.TranslateRequest{
+		VideoURL:        "https://example.com/video.mp4",
+		TargetLanguages: []string{"de"},
+		SubtitleURL:     "https://example.com/video.srt",
+	}
+	// HTTPS sources are downloaded, but subtitles are only read from Cloud Storage
+	if err := ValidateTranslateRequest(req, cfg); err == nil {
+		t.Error("expected error for subtitles on an HTTPS host")
+	}
+
+	req.SubtitleURL = "https://storage.googleapis.com/bucket/video.srt"
+	if err := ValidateTranslateRequest(req, cfg); err != nil {
+		t.Errorf("unexpected error for subtitles in Cloud Storage: %v", err)
+	}
+}
+
+func TestValidateJobID(t *testing.T) {
+	tests := []struct {
+		name    string
+		jobID   string
+		wantErr bool
+	}{
+		{"UUID", "3f2b8c1e-9d4a-4b7e-8f21-6c5d0a9e7b13", false},
+		{"slug", "campaign_2024-launch", false},
+		{"leading hyphen", "-job", true},
+		{"path separator", "jobs/1", true},
+		{"whitespace", "my job", true},
+		{"too long", strings.Repeat("a", MaxJobIDLength+1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJobID(tt.jobID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateJobID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateOutputAudio(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *models.OutputAudioOptions
+		wantErr bool
+	}{
+		{"not set", nil, false},
+		{"HD stereo", &models.OutputAudioOptions{SampleRate: 48000, Bitrate: 256, ChannelLayout: "stereo"}, false},
+		{"bitrate only", &models.OutputAudioOptions{Bitrate: 96}, false},
+		{"unsupported sample rate", &models.OutputAudioOptions{SampleRate: 96000}, true},
+		{"bitrate too low", &models.OutputAudioOptions{Bitrate: 8}, true},
+		{"surround layout", &models.OutputAudioOptions{ChannelLayout: "5.1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOutputAudio(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateOutputAudio() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTranscriptionOptions(t *testing.T) {
+	punctuation := true
+	channel, negative := 2, -1
+
+	tests := []struct {
+		name    string
+		opts    *models.TranscriptionOptions
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"all options", &models.TranscriptionOptions{Model: "video", AutomaticPunctuation: &punctuation, AlternativeLanguages: []string{"fr", "de"}, AudioChannel: &channel}, false},
+		{"unsupported model", &models.TranscriptionOptions{Model: "telephony"}, true},
+		{"too many alternative languages", &models.TranscriptionOptions{AlternativeLanguages: []string{"fr", "de", "es", "it"}}, true},
+		{"invalid alternative language", &models.TranscriptionOptions{AlternativeLanguages: []string{"french"}}, true},
+		{"negative channel", &models.TranscriptionOptions{AudioChannel: &negative}, true},
+		{"hint phrases", &models.TranscriptionOptions{HintPhrases: []string{"Kubernetes", "Dr. Okonkwo"}}, false},
+		{"empty hint phrase", &models.TranscriptionOptions{HintPhrases: []string{"Kubernetes", " "}}, true},
+		{"hint phrase too long", &models.TranscriptionOptions{HintPhrases: []string{strings.Repeat("a", models.MaxHintPhraseLength+1)}}, true},
+		{"too many hint phrases", &models.TranscriptionOptions{HintPhrases: make([]string, models.MaxHintPhrases+1)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTranscriptionOptions(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTranscriptionOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateClipRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   float64
+		end     float64
+		wantErr bool
+	}{
+		{"no clip", 0, 0, false},
+		{"range", 30, 90, false},
+		{"start only", 30, 0, false},
+		{"negative start", -1, 10, true},
+		{"end before start", 30, 20, true},
+		{"end equals start", 30, 30, true},
+		{"longer than maximum", 0, 700, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateClipRange(tt.start, tt.end, 600)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateClipRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
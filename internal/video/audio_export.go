@@ -0,0 +1,55 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// ExportWAV decodes an audio file to 16-bit PCM WAV in the given format
+// Zero encoding fields keep the sample rate and channels of the input; the bitrate does not apply to PCM.
+func ExportWAV(ctx context.Context, audioPath string, outputPath string, encoding AudioEncoding) error {
+	slog.Info("Exporting audio as WAV",
+		"audioPath", audioPath,
+		"outputPath", outputPath)
+
+	// Check context cancellation before starting
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("audio export cancelled: %w", ctx.Err())
+	default:
+	}
+
+	cmd := FFmpegCommand(ctx, exportWAVArgs(audioPath, outputPath, encoding)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			return fmt.Errorf("audio export cancelled: %w", ctx.Err())
+		}
+		return fmt.Errorf("failed to export audio: %w, stderr: %s", err, stderr.String())
+	}
+
+	slog.Info("Audio exported", "outputPath", outputPath)
+	return nil
+}
+
+// exportWAVArgs builds the FFmpeg arguments for ExportWAV
+// ffmpeg -i audio.mp3 -vn -acodec pcm_s16le [-ar 48000] [-ac 2] -y output.wav
+func exportWAVArgs(audioPath string, outputPath string, encoding AudioEncoding) []string {
+	args := []string{
+		"-i", audioPath,
+		"-vn",                  // No video
+		"-acodec", "pcm_s16le", // Audio codec
+	}
+	encoding.Bitrate = 0
+	args = append(args, encoding.args()...)
+	return append(args,
+		"-y", // Overwrite output file
+		outputPath,
+	)
+}
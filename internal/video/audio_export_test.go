@@ -0,0 +1,34 @@
+package video
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExportWAVArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding AudioEncoding
+		want     []string
+	}{
+		{
+			"input format",
+			AudioEncoding{},
+			[]string{"-i", "speech.mp3", "-vn", "-acodec", "pcm_s16le", "-y", "speech.wav"},
+		},
+		{
+			"resampled stereo ignores bitrate",
+			AudioEncoding{SampleRate: 48000, Channels: 2, Bitrate: 192},
+			[]string{"-i", "speech.mp3", "-vn", "-acodec", "pcm_s16le", "-ar", "48000", "-ac", "2", "-y", "speech.wav"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := exportWAVArgs("speech.mp3", "speech.wav", tt.encoding)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -6,15 +6,64 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 )
 
+// SyncOptions controls how the new audio is muxed into the video
+type SyncOptions struct {
+	BackgroundPath   string  // Optional accompaniment (music and effects) mixed under the new audio
+	BackgroundVolume float64 // Gain applied to the background; 0 for 1 (unchanged)
+
+	// Rotation is the source display rotation from ProbeRotation, in degrees clockwise
+	Rotation int
+	// NormalizeRotation re-encodes rotated video upright instead of keeping the rotation as metadata
+	NormalizeRotation bool
+
+	// Metadata sets container tags (e.g. title) over the ones copied from the source video
+	Metadata map[string]string
+	// AudioLanguage tags the new audio stream with an ISO 639-2 code (e.g. "ara")
+	AudioLanguage string
+
+	// Audio sets the encoding of the new audio stream, resampling and upmixing the speech as needed
+	Audio AudioEncoding
+}
+
+// AudioEncoding sets the format of an encoded AAC track; zero fields keep FFmpeg's defaults
+type AudioEncoding struct {
+	SampleRate int // Hz
+	Channels   int // 1 for mono, 2 for stereo (mono input is copied to both channels)
+	Bitrate    int // kbps
+}
+
+// args returns the FFmpeg output options for the encoding
+func (e AudioEncoding) args() []string {
+	var args []string
+	if e.SampleRate > 0 {
+		args = append(args, "-ar", strconv.Itoa(e.SampleRate))
+	}
+	if e.Channels > 0 {
+		args = append(args, "-ac", strconv.Itoa(e.Channels))
+	}
+	if e.Bitrate > 0 {
+		args = append(args, "-b:a", strconv.Itoa(e.Bitrate)+"k")
+	}
+	return args
+}
+
 // SyncAudioWithVideo replaces audio track in video with new TTS audio
 func SyncAudioWithVideo(ctx context.Context, videoPath string, audioPath string, outputPath string) error {
+	return SyncAudioWithVideoOptions(ctx, videoPath, audioPath, outputPath, SyncOptions{})
+}
+
+// SyncAudioWithVideoOptions replaces the audio track in video with new TTS audio,
+// mixing it over a background track when one is given
+func SyncAudioWithVideoOptions(ctx context.Context, videoPath string, audioPath string, outputPath string, opts SyncOptions) error {
 	slog.Info("Synchronizing audio with video",
 		"videoPath", videoPath,
 		"audioPath", audioPath,
+		"backgroundPath", opts.BackgroundPath,
 		"outputPath", outputPath)
 
 	// Check context cancellation before starting
@@ -55,19 +104,7 @@ func SyncAudioWithVideo(ctx context.Context, videoPath string, audioPath string,
 	}
 
 	// Use FFmpeg to replace audio track
-	// ffmpeg -i video.mp4 -i audio.wav -c:v copy -c:a aac -map 0:v:0 -map 1:a:0 -shortest output.mp4
-	// -shortest will trim to shortest stream (video or audio)
-	cmd := exec.CommandContext(ctx, "ffmpeg",
-		"-i", videoPath,
-		"-i", audioPath,
-		"-c:v", "copy", // Copy video codec (no re-encoding)
-		"-c:a", "aac", // Audio codec
-		"-map", "0:v:0", // Map video from first input
-		"-map", "1:a:0", // Map audio from second input
-		"-shortest", // Finish encoding when the shortest input stream ends
-		"-y",        // Overwrite output file
-		outputPath,
-	)
+	cmd := FFmpegCommand(ctx, syncArgs(videoPath, audioPath, outputPath, opts)...)
 
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
@@ -84,3 +121,82 @@ func SyncAudioWithVideo(ctx context.Context, videoPath string, audioPath string,
 	slog.Info("Audio-video synchronization completed", "outputPath", outputPath)
 	return nil
 }
+
+// syncArgs builds the FFmpeg arguments for SyncAudioWithVideoOptions
+// ffmpeg -i video.mp4 -i audio.wav -c:v copy -c:a aac -map 0:v:0 -map 1:a:0 -map_metadata 0 -map_metadata:s:v 0:s:v -shortest output.mp4
+// -shortest will trim to shortest stream (video or audio)
+// With a background, the speech is mixed over it:
+// ffmpeg -i video.mp4 -i audio.wav -i background.wav -filter_complex "[2:a]volume=V[bg];[1:a][bg]amix=..." -map 0:v:0 -map [a] ...
+func syncArgs(videoPath string, audioPath string, outputPath string, opts SyncOptions) []string {
+	args := []string{"-i", videoPath, "-i", audioPath}
+	audioMap := "1:a:0" // Map audio from second input
+	if opts.BackgroundPath != "" {
+		volume := opts.BackgroundVolume
+		if volume == 0 {
+			volume = 1
+		}
+		// duration=longest keeps the music under the end of the video; normalize=0 keeps both tracks at their own level
+		args = append(args,
+			"-i", opts.BackgroundPath,
+			"-filter_complex", fmt.Sprintf("[2:a]volume=%s[bg];[1:a][bg]amix=inputs=2:duration=longest:dropout_transition=0:normalize=0[a]",
+				strconv.FormatFloat(volume, 'f', -1, 64)),
+		)
+		audioMap = "[a]"
+	}
+	args = append(args, videoCodecArgs(opts)...)
+	args = append(args, "-c:a", "aac") // Audio codec
+	args = append(args, opts.Audio.args()...)
+	args = append(args,
+		"-map", "0:v:0", // Map video from first input
+		"-map", audioMap,
+		"-map_metadata", "0", // Keep the container metadata of the video
+		"-map_metadata:s:v", "0:s:v", // Keep the video stream metadata (language, handler, rotate tag)
+		"-map_chapters", "0", // Keep the chapters of the video
+	)
+	args = append(args, metadataArgs(opts)...)
+	return append(args,
+		"-shortest", // Finish encoding when the shortest input stream ends
+		"-y",        // Overwrite output file
+		outputPath,
+	)
+}
+
+// videoCodecArgs copies the video stream, keeping or normalizing its rotation
+// A stream copy keeps the display matrix; the rotate tag is also set for FFmpeg versions that only write rotation from it.
+// Normalizing re-encodes the video, which FFmpeg autorotates upright, and clears the copied rotate tag.
+func videoCodecArgs(opts SyncOptions) []string {
+	if opts.Rotation == 0 {
+		return []string{"-c:v", "copy"} // Copy video codec (no re-encoding)
+	}
+	if opts.NormalizeRotation {
+		return []string{
+			"-c:v", "libx264",
+			"-preset", "veryfast",
+			"-crf", "18", // Visually lossless
+			"-pix_fmt", "yuv420p",
+			"-metadata:s:v:0", "rotate=0",
+		}
+	}
+	return []string{
+		"-c:v", "copy",
+		"-metadata:s:v:0", "rotate=" + strconv.Itoa(opts.Rotation),
+	}
+}
+
+// metadataArgs sets the container tags and audio language in a stable order
+func metadataArgs(opts SyncOptions) []string {
+	keys := make([]string, 0, len(opts.Metadata))
+	for key := range opts.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var args []string
+	for _, key := range keys {
+		args = append(args, "-metadata", key+"="+opts.Metadata[key])
+	}
+	if opts.AudioLanguage != "" {
+		args = append(args, "-metadata:s:a:0", "language="+opts.AudioLanguage)
+	}
+	return args
+}
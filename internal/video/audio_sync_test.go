@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -53,3 +54,70 @@ func TestSyncAudioWithVideo_ContextTimeout(t *testing.T) {
 		t.Error("expected error for timed out context")
 	}
 }
+
+func TestSyncArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SyncOptions
+		want []string
+	}{
+		{
+			"replace audio",
+			SyncOptions{},
+			[]string{"-i", "in.mp4", "-i", "speech.wav", "-c:v", "copy", "-c:a", "aac", "-map", "0:v:0", "-map", "1:a:0", "-map_metadata", "0", "-map_metadata:s:v", "0:s:v", "-map_chapters", "0", "-shortest", "-y", "out.mp4"},
+		},
+		{
+			"mix over background",
+			SyncOptions{BackgroundPath: "music.wav", BackgroundVolume: 0.8},
+			[]string{"-i", "in.mp4", "-i", "speech.wav", "-i", "music.wav",
+				"-filter_complex", "[2:a]volume=0.8[bg];[1:a][bg]amix=inputs=2:duration=longest:dropout_transition=0:normalize=0[a]",
+				"-c:v", "copy", "-c:a", "aac", "-map", "0:v:0", "-map", "[a]", "-map_metadata", "0", "-map_metadata:s:v", "0:s:v", "-map_chapters", "0", "-shortest", "-y", "out.mp4"},
+		},
+		{
+			"background at unchanged volume",
+			SyncOptions{BackgroundPath: "music.wav"},
+			[]string{"-i", "in.mp4", "-i", "speech.wav", "-i", "music.wav",
+				"-filter_complex", "[2:a]volume=1[bg];[1:a][bg]amix=inputs=2:duration=longest:dropout_transition=0:normalize=0[a]",
+				"-c:v", "copy", "-c:a", "aac", "-map", "0:v:0", "-map", "[a]", "-map_metadata", "0", "-map_metadata:s:v", "0:s:v", "-map_chapters", "0", "-shortest", "-y", "out.mp4"},
+		},
+		{
+			"resampled stereo upmix",
+			SyncOptions{Audio: AudioEncoding{SampleRate: 48000, Channels: 2, Bitrate: 192}},
+			[]string{"-i", "in.mp4", "-i", "speech.wav", "-c:v", "copy", "-c:a", "aac", "-ar", "48000", "-ac", "2", "-b:a", "192k", "-map", "0:v:0", "-map", "1:a:0",
+				"-map_metadata", "0", "-map_metadata:s:v", "0:s:v", "-map_chapters", "0", "-shortest", "-y", "out.mp4"},
+		},
+		{
+			"rotation kept as metadata",
+			SyncOptions{Rotation: 90},
+			[]string{"-i", "in.mp4", "-i", "speech.wav", "-c:v", "copy", "-metadata:s:v:0", "rotate=90", "-c:a", "aac", "-map", "0:v:0", "-map", "1:a:0",
+				"-map_metadata", "0", "-map_metadata:s:v", "0:s:v", "-map_chapters", "0", "-shortest", "-y", "out.mp4"},
+		},
+		{
+			"rotation normalized",
+			SyncOptions{Rotation: 270, NormalizeRotation: true},
+			[]string{"-i", "in.mp4", "-i", "speech.wav", "-c:v", "libx264", "-preset", "veryfast", "-crf", "18", "-pix_fmt", "yuv420p", "-metadata:s:v:0", "rotate=0",
+				"-c:a", "aac", "-map", "0:v:0", "-map", "1:a:0", "-map_metadata", "0", "-map_metadata:s:v", "0:s:v", "-map_chapters", "0", "-shortest", "-y", "out.mp4"},
+		},
+		{
+			"upright video is copied even when normalizing",
+			SyncOptions{NormalizeRotation: true},
+			[]string{"-i", "in.mp4", "-i", "speech.wav", "-c:v", "copy", "-c:a", "aac", "-map", "0:v:0", "-map", "1:a:0",
+				"-map_metadata", "0", "-map_metadata:s:v", "0:s:v", "-map_chapters", "0", "-shortest", "-y", "out.mp4"},
+		},
+		{
+			"title and audio language",
+			SyncOptions{Metadata: map[string]string{"title": "Demo (Arabic dub)", "comment": "dubbed"}, AudioLanguage: "ara"},
+			[]string{"-i", "in.mp4", "-i", "speech.wav", "-c:v", "copy", "-c:a", "aac", "-map", "0:v:0", "-map", "1:a:0",
+				"-map_metadata", "0", "-map_metadata:s:v", "0:s:v", "-map_chapters", "0",
+				"-metadata", "comment=dubbed", "-metadata", "title=Demo (Arabic dub)", "-metadata:s:a:0", "language=ara", "-shortest", "-y", "out.mp4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := syncArgs("in.mp4", "speech.wav", "out.mp4", tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
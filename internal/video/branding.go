@@ -0,0 +1,271 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// watermarkMargin is the distance in pixels between the watermark and the frame edges
+const watermarkMargin = 10
+
+// BrandingOptions selects the watermark and bumper clips applied to a dubbed video
+type BrandingOptions struct {
+	WatermarkPath     string  // Image overlaid on the whole video (PNG with transparency works best)
+	WatermarkPosition string  // Corner of the watermark; "" for bottom-right
+	WatermarkOpacity  float64 // 0 for fully opaque
+	IntroPath         string  // Clip prepended to the video
+	OutroPath         string  // Clip appended to the video
+
+	// Audio sets the format the audio is re-encoded to when bumpers are joined; zero fields use 48 kHz stereo
+	Audio AudioEncoding
+}
+
+// bumper is an intro or outro clip as seen by the concat filter
+type bumper struct {
+	Path     string
+	HasAudio bool    // Clips without audio are joined with silence
+	Duration float64 // Seconds, for the silence of clips without audio
+}
+
+// ApplyBranding overlays the watermark and joins the bumper clips to a video, re-encoding it to outputPath
+// Bumpers are scaled and padded to the size of the video so the clips can be concatenated.
+func ApplyBranding(ctx context.Context, videoPath string, outputPath string, opts BrandingOptions) error {
+	slog.Info("Applying branding",
+		"videoPath", videoPath,
+		"watermark", opts.WatermarkPath,
+		"intro", opts.IntroPath,
+		"outro", opts.OutroPath,
+		"outputPath", outputPath)
+
+	// Check context cancellation before starting
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("branding cancelled: %w", ctx.Err())
+	default:
+	}
+
+	var width, height int
+	var intro, outro *bumper
+	if opts.IntroPath != "" || opts.OutroPath != "" {
+		var err error
+		width, height, err = ProbeVideoSize(ctx, videoPath)
+		if err != nil {
+			return err
+		}
+		if intro, err = probeBumper(ctx, opts.IntroPath); err != nil {
+			return fmt.Errorf("invalid intro clip: %w", err)
+		}
+		if outro, err = probeBumper(ctx, opts.OutroPath); err != nil {
+			return fmt.Errorf("invalid outro clip: %w", err)
+		}
+	}
+
+	cmd := FFmpegCommand(ctx, brandingArgs(videoPath, outputPath, opts, intro, outro, width, height)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			return fmt.Errorf("branding cancelled: %w", ctx.Err())
+		}
+		return fmt.Errorf("failed to apply branding: %w, stderr: %s", err, stderr.String())
+	}
+
+	slog.Info("Branding applied", "outputPath", outputPath)
+	return nil
+}
+
+// probeBumper reads the audio and duration of a bumper clip, or returns nil when path is empty
+func probeBumper(ctx context.Context, path string) (*bumper, error) {
+	if path == "" {
+		return nil, nil
+	}
+	tracks, err := ProbeAudioTracks(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	duration, err := GetVideoDuration(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return &bumper{Path: path, HasAudio: len(tracks) > 0, Duration: duration}, nil
+}
+
+// brandingArgs builds the FFmpeg arguments for ApplyBranding
+// ffmpeg -i video.mp4 [-i intro.mp4] [-i outro.mp4] [-i logo.png] -filter_complex "..." -map [v] -map [a] -c:v libx264 ... output.mp4
+func brandingArgs(videoPath string, outputPath string, opts BrandingOptions, intro *bumper, outro *bumper, width int, height int) []string {
+	args := []string{"-i", videoPath}
+	var filters []string
+	input := 1
+
+	// Joined clips need a common sample rate and channel layout
+	sampleRate, layout := 48000, "stereo"
+	if opts.Audio.SampleRate > 0 {
+		sampleRate = opts.Audio.SampleRate
+	}
+	if opts.Audio.Channels == 1 {
+		layout = "mono"
+	}
+	audioFormat := fmt.Sprintf("aformat=sample_rates=%d:channel_layouts=%s", sampleRate, layout)
+
+	// Each bumper is fitted into the video frame; its audio (or silence) is converted to a common format
+	segment := func(clip *bumper, label string) {
+		args = append(args, "-i", clip.Path)
+		filters = append(filters, fmt.Sprintf("[%d:v]scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1[%sv]",
+			input, width, height, width, height, label))
+		if clip.HasAudio {
+			filters = append(filters, fmt.Sprintf("[%d:a]%s[%sa]", input, audioFormat, label))
+		} else {
+			filters = append(filters, fmt.Sprintf("anullsrc=r=%d:cl=%s,atrim=duration=%s[%sa]", sampleRate, layout, formatSeconds(clip.Duration), label))
+		}
+		input++
+	}
+	if intro != nil {
+		segment(intro, "intro")
+	}
+	if outro != nil {
+		segment(outro, "outro")
+	}
+
+	// The watermark only covers the dubbed video, not the bumpers
+	mainVideo := "[0:v]"
+	if opts.WatermarkPath != "" {
+		args = append(args, "-i", opts.WatermarkPath)
+		opacity := opts.WatermarkOpacity
+		if opacity == 0 {
+			opacity = 1
+		}
+		filters = append(filters,
+			fmt.Sprintf("[%d:v]format=rgba,colorchannelmixer=aa=%s[logo]", input, strconv.FormatFloat(opacity, 'f', -1, 64)),
+			fmt.Sprintf("%s[logo]overlay=%s[mainv]", mainVideo, watermarkOverlay(opts.WatermarkPosition)),
+		)
+		mainVideo = "[mainv]"
+	}
+
+	videoOut, audioOut := mainVideo, "0:a:0"
+	if intro != nil || outro != nil {
+		filters = append(filters,
+			fmt.Sprintf("%sscale=%d:%d,setsar=1[mainsv]", mainVideo, width, height),
+			"[0:a]"+audioFormat+"[maina]",
+		)
+		var segments []string
+		if intro != nil {
+			segments = append(segments, "[introv][introa]")
+		}
+		segments = append(segments, "[mainsv][maina]")
+		if outro != nil {
+			segments = append(segments, "[outrov][outroa]")
+		}
+		filters = append(filters, fmt.Sprintf("%sconcat=n=%d:v=1:a=1[v][a]", strings.Join(segments, ""), len(segments)))
+		videoOut, audioOut = "[v]", "[a]"
+	} else if mainVideo == "[0:v]" {
+		videoOut = "0:v:0"
+	}
+
+	if len(filters) > 0 {
+		args = append(args, "-filter_complex", strings.Join(filters, ";"))
+	}
+	args = append(args,
+		"-map", videoOut,
+		"-map", audioOut,
+		"-c:v", "libx264",
+		"-preset", "veryfast",
+		"-crf", "18", // Visually lossless
+		"-pix_fmt", "yuv420p",
+		"-metadata:s:v:0", "rotate=0", // Decoding autorotates the video, so the output is upright
+		"-map_metadata", "0", // Keep the container metadata (title) of the dubbed video
+		"-map_metadata:s:a", "0:s:a", // Keep the audio language tag
+	)
+	if audioOut == "0:a:0" {
+		args = append(args, "-c:a", "copy") // Only the picture changes
+	} else {
+		args = append(args, "-c:a", "aac")
+		if opts.Audio.Bitrate > 0 {
+			args = append(args, "-b:a", strconv.Itoa(opts.Audio.Bitrate)+"k")
+		}
+	}
+	if intro != nil {
+		args = append(args, "-map_chapters", "-1") // Chapter times would be off by the intro length
+	}
+	return append(args,
+		"-movflags", "+faststart",
+		"-y", // Overwrite output file
+		outputPath,
+	)
+}
+
+// watermarkOverlay returns the overlay position of a watermark corner
+func watermarkOverlay(position string) string {
+	margin := strconv.Itoa(watermarkMargin)
+	switch position {
+	case models.WatermarkTopLeft:
+		return margin + ":" + margin
+	case models.WatermarkTopRight:
+		return "W-w-" + margin + ":" + margin
+	case models.WatermarkBottomLeft:
+		return margin + ":H-h-" + margin
+	default:
+		return "W-w-" + margin + ":H-h-" + margin
+	}
+}
+
+// ProbeVideoSize returns the display width and height of the first video stream, accounting for rotation
+func ProbeVideoSize(ctx context.Context, videoPath string) (int, int, error) {
+	// ffprobe -v error -select_streams v:0 -show_entries stream=width,height:stream_tags=rotate:stream_side_data=rotation -of json video.mp4
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:stream_tags=rotate:stream_side_data=rotation",
+		"-of", "json",
+		videoPath,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			return 0, 0, fmt.Errorf("video size probe cancelled: %w", ctx.Err())
+		}
+		return 0, 0, fmt.Errorf("failed to probe video size: %w, stderr: %s", err, stderr.String())
+	}
+	return parseVideoSize(stdout.Bytes())
+}
+
+// parseVideoSize reads the frame size from ffprobe JSON output, swapping it for quarter-turn rotations
+func parseVideoSize(data []byte) (int, int, error) {
+	var probe struct {
+		Streams []struct {
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse video size probe: %w", err)
+	}
+	if len(probe.Streams) == 0 || probe.Streams[0].Width == 0 || probe.Streams[0].Height == 0 {
+		return 0, 0, fmt.Errorf("no video stream found")
+	}
+
+	width, height := probe.Streams[0].Width, probe.Streams[0].Height
+	rotation, err := parseRotation(data)
+	if err != nil {
+		return 0, 0, err
+	}
+	if rotation == 90 || rotation == 270 {
+		width, height = height, width
+	}
+	return width, height, nil
+}
@@ -0,0 +1,145 @@
+package video
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestBrandingArgs_Watermark(t *testing.T) {
+	opts := BrandingOptions{WatermarkPath: "logo.png", WatermarkPosition: models.WatermarkTopRight, WatermarkOpacity: 0.6}
+	got := brandingArgs("in.mp4", "out.mp4", opts, nil, nil, 0, 0)
+	want := []string{
+		"-i", "in.mp4", "-i", "logo.png",
+		"-filter_complex", "[1:v]format=rgba,colorchannelmixer=aa=0.6[logo];[0:v][logo]overlay=W-w-10:10[mainv]",
+		"-map", "[mainv]", "-map", "0:a:0",
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "18", "-pix_fmt", "yuv420p", "-metadata:s:v:0", "rotate=0",
+		"-map_metadata", "0", "-map_metadata:s:a", "0:s:a", "-c:a", "copy",
+		"-movflags", "+faststart", "-y", "out.mp4",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestBrandingArgs_Bumpers(t *testing.T) {
+	opts := BrandingOptions{WatermarkPath: "logo.png", IntroPath: "intro.mp4", OutroPath: "outro.mp4"}
+	intro := &bumper{Path: "intro.mp4", HasAudio: true, Duration: 3}
+	outro := &bumper{Path: "outro.mp4", Duration: 2.5}
+	got := brandingArgs("in.mp4", "out.mp4", opts, intro, outro, 1280, 720)
+	want := []string{
+		"-i", "in.mp4", "-i", "intro.mp4", "-i", "outro.mp4", "-i", "logo.png",
+		"-filter_complex", "[1:v]scale=1280:720:force_original_aspect_ratio=decrease,pad=1280:720:(ow-iw)/2:(oh-ih)/2,setsar=1[introv];" +
+			"[1:a]aformat=sample_rates=48000:channel_layouts=stereo[introa];" +
+			"[2:v]scale=1280:720:force_original_aspect_ratio=decrease,pad=1280:720:(ow-iw)/2:(oh-ih)/2,setsar=1[outrov];" +
+			"anullsrc=r=48000:cl=stereo,atrim=duration=2.500[outroa];" +
+			"[3:v]format=rgba,colorchannelmixer=aa=1[logo];[0:v][logo]overlay=W-w-10:H-h-10[mainv];" +
+			"[mainv]scale=1280:720,setsar=1[mainsv];[0:a]aformat=sample_rates=48000:channel_layouts=stereo[maina];" +
+			"[introv][introa][mainsv][maina][outrov][outroa]concat=n=3:v=1:a=1[v][a]",
+		"-map", "[v]", "-map", "[a]",
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "18", "-pix_fmt", "yuv420p", "-metadata:s:v:0", "rotate=0",
+		"-map_metadata", "0", "-map_metadata:s:a", "0:s:a", "-c:a", "aac", "-map_chapters", "-1",
+		"-movflags", "+faststart", "-y", "out.mp4",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestBrandingArgs_OutroOnly(t *testing.T) {
+	outro := &bumper{Path: "outro.mp4", HasAudio: true, Duration: 4}
+	got := brandingArgs("in.mp4", "out.mp4", BrandingOptions{OutroPath: "outro.mp4"}, nil, outro, 720, 1280)
+	want := []string{
+		"-i", "in.mp4", "-i", "outro.mp4",
+		"-filter_complex", "[1:v]scale=720:1280:force_original_aspect_ratio=decrease,pad=720:1280:(ow-iw)/2:(oh-ih)/2,setsar=1[outrov];" +
+			"[1:a]aformat=sample_rates=48000:channel_layouts=stereo[outroa];" +
+			"[0:v]scale=720:1280,setsar=1[mainsv];[0:a]aformat=sample_rates=48000:channel_layouts=stereo[maina];" +
+			"[mainsv][maina][outrov][outroa]concat=n=2:v=1:a=1[v][a]",
+		"-map", "[v]", "-map", "[a]",
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "18", "-pix_fmt", "yuv420p", "-metadata:s:v:0", "rotate=0",
+		"-map_metadata", "0", "-map_metadata:s:a", "0:s:a", "-c:a", "aac",
+		"-movflags", "+faststart", "-y", "out.mp4",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestBrandingArgs_AudioEncoding(t *testing.T) {
+	outro := &bumper{Path: "outro.mp4", Duration: 2}
+	opts := BrandingOptions{OutroPath: "outro.mp4", Audio: AudioEncoding{SampleRate: 44100, Channels: 1, Bitrate: 128}}
+	got := brandingArgs("in.mp4", "out.mp4", opts, nil, outro, 640, 360)
+	want := []string{
+		"-i", "in.mp4", "-i", "outro.mp4",
+		"-filter_complex", "[1:v]scale=640:360:force_original_aspect_ratio=decrease,pad=640:360:(ow-iw)/2:(oh-ih)/2,setsar=1[outrov];" +
+			"anullsrc=r=44100:cl=mono,atrim=duration=2.000[outroa];" +
+			"[0:v]scale=640:360,setsar=1[mainsv];[0:a]aformat=sample_rates=44100:channel_layouts=mono[maina];" +
+			"[mainsv][maina][outrov][outroa]concat=n=2:v=1:a=1[v][a]",
+		"-map", "[v]", "-map", "[a]",
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "18", "-pix_fmt", "yuv420p", "-metadata:s:v:0", "rotate=0",
+		"-map_metadata", "0", "-map_metadata:s:a", "0:s:a", "-c:a", "aac", "-b:a", "128k",
+		"-movflags", "+faststart", "-y", "out.mp4",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestWatermarkOverlay(t *testing.T) {
+	tests := map[string]string{
+		models.WatermarkTopLeft:     "10:10",
+		models.WatermarkTopRight:    "W-w-10:10",
+		models.WatermarkBottomLeft:  "10:H-h-10",
+		models.WatermarkBottomRight: "W-w-10:H-h-10",
+		"":                          "W-w-10:H-h-10",
+	}
+	for position, want := range tests {
+		if got := watermarkOverlay(position); got != want {
+			t.Errorf("watermarkOverlay(%q): expected %s, got %s", position, want, got)
+		}
+	}
+}
+
+func TestParseVideoSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		wantWidth  int
+		wantHeight int
+		wantErr    bool
+	}{
+		{"landscape", `{"streams": [{"width": 1920, "height": 1080}]}`, 1920, 1080, false},
+		{"rotated portrait", `{"streams": [{"width": 1920, "height": 1080, "side_data_list": [{"rotation": -90}]}]}`, 1080, 1920, false},
+		{"upside down", `{"streams": [{"width": 1920, "height": 1080, "tags": {"rotate": "180"}}]}`, 1920, 1080, false},
+		{"no video", `{"streams": []}`, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height, err := parseVideoSize([]byte(tt.output))
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("expected %dx%d, got %dx%d", tt.wantWidth, tt.wantHeight, width, height)
+			}
+		})
+	}
+}
+
+func TestApplyBranding_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := ApplyBranding(ctx, "/nonexistent/video.mp4", "/tmp/out.mp4", BrandingOptions{WatermarkPath: "logo.png"}); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
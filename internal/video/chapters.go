@@ -0,0 +1,137 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SplitChapters cuts a video into consecutive chapters of about chapterDuration seconds without re-encoding
+// The chapters are written as outputPrefix000.ext, outputPrefix001.ext, ... with the extension of the video,
+// and returned in order. Cuts snap to the next keyframe, so chapters join back without gaps or overlaps.
+func SplitChapters(ctx context.Context, videoPath string, chapterDuration float64, outputPrefix string) ([]string, error) {
+	slog.Info("Splitting video into chapters",
+		"videoPath", videoPath,
+		"chapterDuration", chapterDuration)
+
+	if chapterDuration <= 0 {
+		return nil, fmt.Errorf("invalid chapter duration: %v", chapterDuration)
+	}
+
+	// Check context cancellation before starting
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("chapter split cancelled: %w", ctx.Err())
+	default:
+	}
+
+	ext := filepath.Ext(videoPath)
+	cmd := FFmpegCommand(ctx, splitArgs(videoPath, chapterDuration, outputPrefix+"%03d"+ext)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		chapters, _ := filepath.Glob(outputPrefix + "[0-9][0-9][0-9]" + ext)
+		for _, path := range chapters {
+			os.Remove(path)
+		}
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("chapter split cancelled: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("failed to split video into chapters: %w, stderr: %s", err, stderr.String())
+	}
+
+	chapters, err := filepath.Glob(outputPrefix + "[0-9][0-9][0-9]" + ext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chapters: %w", err)
+	}
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("video split produced no chapters")
+	}
+	sort.Strings(chapters)
+
+	slog.Info("Video split into chapters", "chapters", len(chapters))
+	return chapters, nil
+}
+
+// splitArgs builds the ffmpeg arguments for SplitChapters
+// ffmpeg -i video.mp4 -map 0:v:0 -map 0:a? -c copy -f segment -segment_time 600 -reset_timestamps 1 -y chapter%03d.mp4
+// Every audio track is kept so the requested source track can still be transcribed from each chapter.
+func splitArgs(videoPath string, chapterDuration float64, outputPattern string) []string {
+	return []string{
+		"-i", videoPath,
+		"-map", "0:v:0",
+		"-map", "0:a?",
+		"-c", "copy", // No re-encoding
+		"-f", "segment",
+		"-segment_time", formatSeconds(chapterDuration),
+		"-reset_timestamps", "1", // Each chapter starts at 0 like a standalone video
+		"-y", // Overwrite output files
+		outputPattern,
+	}
+}
+
+// ConcatMedia joins video or audio files with identical stream layouts into outputPath without re-encoding
+func ConcatMedia(ctx context.Context, paths []string, outputPath string) error {
+	slog.Info("Concatenating media",
+		"files", len(paths),
+		"outputPath", outputPath)
+
+	if len(paths) == 0 {
+		return fmt.Errorf("no files to concatenate")
+	}
+
+	// Check context cancellation before starting
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("concatenation cancelled: %w", ctx.Err())
+	default:
+	}
+
+	listPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "_concat.txt"
+	if err := os.WriteFile(listPath, []byte(concatList(paths)), 0644); err != nil {
+		return fmt.Errorf("failed to write concat list: %w", err)
+	}
+	defer os.Remove(listPath)
+
+	// ffmpeg -f concat -safe 0 -i list.txt -map 0 -c copy -y output.mp4
+	cmd := FFmpegCommand(ctx,
+		"-f", "concat",
+		"-safe", "0", // The list holds absolute paths
+		"-i", listPath,
+		"-map", "0",
+		"-c", "copy",
+		"-y", // Overwrite output file
+		outputPath,
+	)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			return fmt.Errorf("concatenation cancelled: %w", ctx.Err())
+		}
+		return fmt.Errorf("failed to concatenate media: %w, stderr: %s", err, stderr.String())
+	}
+
+	slog.Info("Media concatenated", "outputPath", outputPath)
+	return nil
+}
+
+// concatList builds an ffmpeg concat demuxer list, quoting each path
+func concatList(paths []string) string {
+	var list strings.Builder
+	for _, path := range paths {
+		list.WriteString("file '" + strings.ReplaceAll(path, "'", `'\''`) + "'\n")
+	}
+	return list.String()
+}
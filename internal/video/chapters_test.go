@@ -0,0 +1,44 @@
+package video
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	got := splitArgs("in.mp4", 600, "/tmp/chapter_%03d.mp4")
+	want := []string{
+		"-i", "in.mp4",
+		"-map", "0:v:0",
+		"-map", "0:a?",
+		"-c", "copy",
+		"-f", "segment",
+		"-segment_time", "600.000",
+		"-reset_timestamps", "1",
+		"-y", "/tmp/chapter_%03d.mp4",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestConcatList(t *testing.T) {
+	got := concatList([]string{"/tmp/a.mp4", "/tmp/it's.mp4"})
+	want := "file '/tmp/a.mp4'\nfile '/tmp/it'\\''s.mp4'\n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSplitChapters_InvalidDuration(t *testing.T) {
+	if _, err := SplitChapters(context.Background(), "in.mp4", 0, "/tmp/chapter_"); err == nil {
+		t.Error("expected error for zero chapter duration")
+	}
+}
+
+func TestConcatMedia_NoFiles(t *testing.T) {
+	if err := ConcatMedia(context.Background(), nil, "/tmp/out.mp4"); err == nil {
+		t.Error("expected error for empty file list")
+	}
+}
@@ -0,0 +1,67 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// ClipVideo copies the range [start, end) of a video to outputPath without re-encoding
+// An end of 0 keeps everything after start. Cuts snap to the nearest keyframes.
+func ClipVideo(ctx context.Context, videoPath string, start float64, end float64, outputPath string) error {
+	slog.Info("Clipping video",
+		"videoPath", videoPath,
+		"start", start,
+		"end", end,
+		"outputPath", outputPath)
+
+	// Check context cancellation before starting
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("video clipping cancelled: %w", ctx.Err())
+	default:
+	}
+
+	// ffmpeg -ss 30 -i input.mp4 -t 15 -map 0 -c copy -avoid_negative_ts make_zero output.mp4
+	cmd := FFmpegCommand(ctx, clipArgs(videoPath, start, end, outputPath)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			return fmt.Errorf("video clipping cancelled: %w", ctx.Err())
+		}
+		return fmt.Errorf("failed to clip video: %w, stderr: %s", err, stderr.String())
+	}
+
+	slog.Info("Video clipped successfully", "outputPath", outputPath)
+	return nil
+}
+
+// clipArgs builds the ffmpeg arguments for ClipVideo
+// Seeking before -i is fast; the duration (-t) is relative to the seek point.
+func clipArgs(videoPath string, start float64, end float64, outputPath string) []string {
+	args := []string{}
+	if start > 0 {
+		args = append(args, "-ss", formatSeconds(start))
+	}
+	args = append(args, "-i", videoPath)
+	if end > 0 {
+		args = append(args, "-t", formatSeconds(end-start))
+	}
+	return append(args,
+		"-map", "0", // Keep every stream
+		"-c", "copy", // No re-encoding
+		"-avoid_negative_ts", "make_zero",
+		"-y", // Overwrite output file
+		outputPath,
+	)
+}
+
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 3, 64)
+}
@@ -0,0 +1,38 @@
+package video
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestClipArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		start float64
+		end   float64
+		want  []string
+	}{
+		{"range", 30, 45.5, []string{"-ss", "30.000", "-i", "in.mp4", "-t", "15.500", "-map", "0", "-c", "copy", "-avoid_negative_ts", "make_zero", "-y", "out.mp4"}},
+		{"start only", 12.25, 0, []string{"-ss", "12.250", "-i", "in.mp4", "-map", "0", "-c", "copy", "-avoid_negative_ts", "make_zero", "-y", "out.mp4"}},
+		{"end only", 0, 10, []string{"-i", "in.mp4", "-t", "10.000", "-map", "0", "-c", "copy", "-avoid_negative_ts", "make_zero", "-y", "out.mp4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clipArgs("in.mp4", tt.start, tt.end, "out.mp4")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClipVideo_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := ClipVideo(ctx, "/nonexistent/video.mp4", 0, 10, "/tmp/clip.mp4"); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
@@ -0,0 +1,53 @@
+package video
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+)
+
+// ProcessLimits bounds the CPU and memory of every ffmpeg process, so concurrent jobs share an instance predictably
+type ProcessLimits struct {
+	Threads  int // Encoder and filter threads per process; 0 lets ffmpeg use every CPU
+	MemoryMB int // Virtual memory limit per process (ulimit -v); 0 for no limit
+	Nice     int // Scheduling niceness (nice -n); positive values yield the CPU to request handling
+}
+
+// processLimits applies to every ffmpeg command built by FFmpegCommand
+var processLimits ProcessLimits
+
+// SetProcessLimits sets the limits applied to ffmpeg processes started from then on
+func SetProcessLimits(limits ProcessLimits) {
+	processLimits = limits
+}
+
+// FFmpegCommand returns an ffmpeg command running args under the configured process limits
+// The last argument must be the output file, since the thread count is an output option.
+func FFmpegCommand(ctx context.Context, args ...string) *exec.Cmd {
+	name, argv := limitedArgs(processLimits, args)
+	return exec.CommandContext(ctx, name, argv...)
+}
+
+// limitedArgs returns the program and arguments running ffmpeg with args under limits:
+// thread options are added to the ffmpeg arguments, niceness through nice and memory through a shell's ulimit
+func limitedArgs(limits ProcessLimits, args []string) (string, []string) {
+	command := []string{"ffmpeg"}
+	if limits.Threads > 0 && len(args) > 0 {
+		threads := strconv.Itoa(limits.Threads)
+		command = append(command, "-filter_threads", threads, "-filter_complex_threads", threads)
+		command = append(command, args[:len(args)-1]...)
+		command = append(command, "-threads", threads, args[len(args)-1])
+	} else {
+		command = append(command, args...)
+	}
+
+	if limits.Nice != 0 {
+		command = append([]string{"nice", "-n", strconv.Itoa(limits.Nice)}, command...)
+	}
+	if limits.MemoryMB > 0 {
+		// The shell applies the limit to itself, then replaces itself with the command
+		script := "ulimit -v " + strconv.Itoa(limits.MemoryMB*1024) + ` && exec "$@"`
+		return "sh", append([]string{"-c", script, "sh"}, command...)
+	}
+	return command[0], command[1:]
+}
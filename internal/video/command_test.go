@@ -0,0 +1,44 @@
+package video
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLimitedArgs(t *testing.T) {
+	args := []string{"-i", "in.mp4", "-y", "out.mp4"}
+
+	tests := []struct {
+		name     string
+		limits   ProcessLimits
+		wantName string
+		wantArgs []string
+	}{
+		{"no limits", ProcessLimits{}, "ffmpeg", args},
+		{
+			"threads",
+			ProcessLimits{Threads: 2},
+			"ffmpeg",
+			[]string{"-filter_threads", "2", "-filter_complex_threads", "2", "-i", "in.mp4", "-y", "-threads", "2", "out.mp4"},
+		},
+		{"nice", ProcessLimits{Nice: 10}, "nice", []string{"-n", "10", "ffmpeg", "-i", "in.mp4", "-y", "out.mp4"}},
+		{
+			"memory and nice",
+			ProcessLimits{MemoryMB: 512, Nice: 5},
+			"sh",
+			[]string{"-c", `ulimit -v 524288 && exec "$@"`, "sh", "nice", "-n", "5", "ffmpeg", "-i", "in.mp4", "-y", "out.mp4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, got := limitedArgs(tt.limits, args)
+			if name != tt.wantName {
+				t.Errorf("expected program %s, got %s", tt.wantName, name)
+			}
+			if !reflect.DeepEqual(got, tt.wantArgs) {
+				t.Errorf("expected args %v, got %v", tt.wantArgs, got)
+			}
+		})
+	}
+}
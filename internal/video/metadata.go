@@ -0,0 +1,138 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// DefaultTitleTemplate names each dubbed output after the source title and target language
+const DefaultTitleTemplate = "{title} ({language} dub)"
+
+// Metadata holds the container metadata of a video
+type Metadata struct {
+	Tags     map[string]string // Container tags with lower-case keys (title, artist, comment, ...)
+	Chapters int               // Number of chapters
+}
+
+// ProbeMetadata reads the container tags and chapter count of a video using ffprobe
+func ProbeMetadata(ctx context.Context, videoPath string) (*Metadata, error) {
+	slog.Debug("Probing video metadata", "videoPath", videoPath)
+
+	// Check context cancellation before starting
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("metadata probe cancelled: %w", ctx.Err())
+	default:
+	}
+
+	// ffprobe -v error -show_entries format_tags:chapter=id -of json video.mp4
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-show_entries", "format_tags:chapter=id",
+		"-of", "json",
+		videoPath,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("metadata probe cancelled: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("failed to probe metadata: %w, stderr: %s", err, stderr.String())
+	}
+
+	metadata, err := parseMetadata(stdout.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Video metadata probed", "tags", len(metadata.Tags), "chapters", metadata.Chapters)
+	return metadata, nil
+}
+
+// parseMetadata converts ffprobe JSON output into Metadata
+// Matroska tags are upper case (TITLE) and MP4 tags lower case, so keys are lower-cased.
+func parseMetadata(data []byte) (*Metadata, error) {
+	var probe struct {
+		Format struct {
+			Tags map[string]string `json:"tags"`
+		} `json:"format"`
+		Chapters []json.RawMessage `json:"chapters"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata probe: %w", err)
+	}
+
+	tags := make(map[string]string, len(probe.Format.Tags))
+	for key, value := range probe.Format.Tags {
+		tags[strings.ToLower(key)] = value
+	}
+	return &Metadata{Tags: tags, Chapters: len(probe.Chapters)}, nil
+}
+
+// SourceTitle returns the title tag of a video, falling back to its file name without extension
+// The fallback accepts a URL (query and fragment are ignored) or a local path.
+func SourceTitle(metadata *Metadata, source string) string {
+	if metadata != nil {
+		if title := strings.TrimSpace(metadata.Tags["title"]); title != "" {
+			return title
+		}
+	}
+	if i := strings.IndexAny(source, "?#"); i >= 0 {
+		source = source[:i]
+	}
+	name := path.Base(source)
+	if name == "." || name == "/" {
+		return ""
+	}
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// FormatTitle fills a title template for a target language
+// {title} is the source title, {language} the English language name (e.g. Arabic) and {code} the language code.
+func FormatTitle(template string, title string, lang string) string {
+	replacer := strings.NewReplacer(
+		"{title}", title,
+		"{language}", LanguageName(lang),
+		"{code}", lang,
+	)
+	return strings.TrimSpace(replacer.Replace(template))
+}
+
+// LanguageName returns the English name of a language code, or the code itself when unknown
+func LanguageName(lang string) string {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return lang
+	}
+	if name := display.English.Tags().Name(tag); name != "" {
+		return name
+	}
+	return lang
+}
+
+// LanguageISO3 returns the ISO 639-2 code containers use to tag audio streams (e.g. "ara"), or "" when unknown
+func LanguageISO3(lang string) string {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return ""
+	}
+	base, confidence := tag.Base()
+	if confidence == language.No {
+		return ""
+	}
+	return base.ISO3()
+}
@@ -0,0 +1,116 @@
+package video
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseMetadata(t *testing.T) {
+	output := []byte(`{
+		"chapters": [{"id": 0}, {"id": 1}, {"id": 2}],
+		"format": {"tags": {"TITLE": "Product Launch", "artist": "Acme", "encoder": "Lavf60.3.100"}}
+	}`)
+
+	metadata, err := parseMetadata(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantTags := map[string]string{"title": "Product Launch", "artist": "Acme", "encoder": "Lavf60.3.100"}
+	if !reflect.DeepEqual(metadata.Tags, wantTags) {
+		t.Errorf("expected %v, got %v", wantTags, metadata.Tags)
+	}
+	if metadata.Chapters != 3 {
+		t.Errorf("expected 3 chapters, got %d", metadata.Chapters)
+	}
+}
+
+func TestParseMetadata_Empty(t *testing.T) {
+	metadata, err := parseMetadata([]byte(`{"format": {}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(metadata.Tags) != 0 || metadata.Chapters != 0 {
+		t.Errorf("expected no tags or chapters, got %+v", metadata)
+	}
+
+	if _, err := parseMetadata([]byte(`not json`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestSourceTitle(t *testing.T) {
+	tagged := &Metadata{Tags: map[string]string{"title": " Product Launch "}}
+	untagged := &Metadata{Tags: map[string]string{}}
+
+	tests := []struct {
+		name     string
+		metadata *Metadata
+		source   string
+		want     string
+	}{
+		{"title tag", tagged, "gs://bucket/launch.mp4", "Product Launch"},
+		{"file name", untagged, "gs://bucket/videos/launch.final.mp4", "launch.final"},
+		{"url with query", nil, "https://example.com/media/demo.mov?token=abc#t=10", "demo"},
+		{"no file name", nil, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SourceTitle(tt.metadata, tt.source); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatTitle(t *testing.T) {
+	tests := []struct {
+		template string
+		lang     string
+		want     string
+	}{
+		{DefaultTitleTemplate, "ar", "My Video (Arabic dub)"},
+		{DefaultTitleTemplate, "de", "My Video (German dub)"},
+		{"{title} [{code}]", "pt-BR", "My Video [pt-BR]"},
+		{"{title}", "en", "My Video"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatTitle(tt.template, "My Video", tt.lang); got != tt.want {
+			t.Errorf("FormatTitle(%q, %q): expected %q, got %q", tt.template, tt.lang, tt.want, got)
+		}
+	}
+}
+
+func TestLanguageNames(t *testing.T) {
+	tests := []struct {
+		lang string
+		name string
+		iso3 string
+	}{
+		{"ar", "Arabic", "ara"},
+		{"ru", "Russian", "rus"},
+		{"pt-BR", "Brazilian Portuguese", "por"},
+		{"not a code", "not a code", ""},
+	}
+
+	for _, tt := range tests {
+		if got := LanguageName(tt.lang); got != tt.name {
+			t.Errorf("LanguageName(%q): expected %q, got %q", tt.lang, tt.name, got)
+		}
+		if got := LanguageISO3(tt.lang); got != tt.iso3 {
+			t.Errorf("LanguageISO3(%q): expected %q, got %q", tt.lang, tt.iso3, got)
+		}
+	}
+}
+
+func TestProbeMetadata_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ProbeMetadata(ctx, "/nonexistent/video.mp4"); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
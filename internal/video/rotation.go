@@ -0,0 +1,97 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"os/exec"
+	"strconv"
+)
+
+// ProbeRotation returns the display rotation of the first video stream in degrees clockwise (0, 90, 180 or 270)
+// Phones record portrait video as landscape frames plus a rotate tag or display matrix telling players to turn them.
+func ProbeRotation(ctx context.Context, videoPath string) (int, error) {
+	slog.Debug("Probing video rotation", "videoPath", videoPath)
+
+	// Check context cancellation before starting
+	select {
+	case <-ctx.Done():
+		return 0, fmt.Errorf("rotation probe cancelled: %w", ctx.Err())
+	default:
+	}
+
+	// ffprobe -v error -select_streams v:0 -show_entries stream_tags=rotate:stream_side_data=rotation -of json video.mp4
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream_tags=rotate:stream_side_data=rotation",
+		"-of", "json",
+		videoPath,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("rotation probe cancelled: %w", ctx.Err())
+		}
+		return 0, fmt.Errorf("failed to probe rotation: %w, stderr: %s", err, stderr.String())
+	}
+
+	rotation, err := parseRotation(stdout.Bytes())
+	if err != nil {
+		return 0, err
+	}
+
+	slog.Debug("Video rotation probed", "rotation", rotation)
+	return rotation, nil
+}
+
+// parseRotation reads the rotation from ffprobe JSON output
+// The display matrix (newer FFmpeg) is counter-clockwise, the legacy rotate tag clockwise; both become clockwise degrees.
+func parseRotation(data []byte) (int, error) {
+	var probe struct {
+		Streams []struct {
+			Tags         map[string]string `json:"tags"`
+			SideDataList []struct {
+				Rotation *float64 `json:"rotation"`
+			} `json:"side_data_list"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return 0, fmt.Errorf("failed to parse rotation probe: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return 0, nil
+	}
+
+	stream := probe.Streams[0]
+	for _, sideData := range stream.SideDataList {
+		if sideData.Rotation != nil {
+			return normalizeRotation(-*sideData.Rotation), nil
+		}
+	}
+	if tag := stream.Tags["rotate"]; tag != "" {
+		degrees, err := strconv.ParseFloat(tag, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid rotate tag %q: %w", tag, err)
+		}
+		return normalizeRotation(degrees), nil
+	}
+	return 0, nil
+}
+
+// normalizeRotation maps any angle to the nearest quarter turn in [0, 360)
+func normalizeRotation(degrees float64) int {
+	quarter := int(math.Round(degrees/90)) % 4
+	if quarter < 0 {
+		quarter += 4
+	}
+	return quarter * 90
+}
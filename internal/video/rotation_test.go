@@ -0,0 +1,61 @@
+package video
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseRotation(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   int
+	}{
+		{"no rotation", `{"streams": [{}]}`, 0},
+		{"no video stream", `{"streams": []}`, 0},
+		{"display matrix portrait", `{"streams": [{"side_data_list": [{"rotation": -90}]}]}`, 90},
+		{"display matrix counter-clockwise", `{"streams": [{"side_data_list": [{"rotation": 90}]}]}`, 270},
+		{"display matrix upside down", `{"streams": [{"side_data_list": [{"rotation": 180}]}]}`, 180},
+		{"legacy rotate tag", `{"streams": [{"tags": {"rotate": "90"}}]}`, 90},
+		{"display matrix wins over tag", `{"streams": [{"tags": {"rotate": "90"}, "side_data_list": [{}, {"rotation": -270}]}]}`, 270},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRotation([]byte(tt.output))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseRotation_Invalid(t *testing.T) {
+	if _, err := parseRotation([]byte(`{"streams": [{"tags": {"rotate": "sideways"}}]}`)); err == nil {
+		t.Error("expected error for invalid rotate tag")
+	}
+	if _, err := parseRotation([]byte(`not json`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestNormalizeRotation(t *testing.T) {
+	tests := map[float64]int{0: 0, 90: 90, -90: 270, 360: 0, 450: 90, -180: 180, 89.5: 90}
+	for degrees, want := range tests {
+		if got := normalizeRotation(degrees); got != want {
+			t.Errorf("normalizeRotation(%g): expected %d, got %d", degrees, want, got)
+		}
+	}
+}
+
+func TestProbeRotation_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ProbeRotation(ctx, "/nonexistent/video.mp4"); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
@@ -0,0 +1,71 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// SilenceTrim configures TrimSilence
+type SilenceTrim struct {
+	ThresholdDB float64 // Audio quieter than this level, e.g. -50, is silence
+	MaxPause    float64 // Pauses longer than this many seconds are shortened to it; 0 keeps pauses
+}
+
+// TrimSilence removes the leading and trailing silence of generated speech and shortens overly long pauses
+func TrimSilence(ctx context.Context, audioPath string, outputPath string, opts SilenceTrim) error {
+	slog.Info("Trimming silence",
+		"audioPath", audioPath,
+		"thresholdDB", opts.ThresholdDB,
+		"maxPause", opts.MaxPause,
+		"outputPath", outputPath)
+
+	if opts.ThresholdDB >= 0 {
+		return fmt.Errorf("invalid silence threshold: %v dB (must be negative)", opts.ThresholdDB)
+	}
+
+	// Check context cancellation before starting
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("silence trimming cancelled: %w", ctx.Err())
+	default:
+	}
+
+	// ffmpeg -i speech.mp3 -filter:a silenceremove=...,areverse,silenceremove=...,areverse -y output.mp3
+	cmd := FFmpegCommand(ctx,
+		"-i", audioPath,
+		"-filter:a", silenceFilter(opts),
+		"-y", // Overwrite output file
+		outputPath,
+	)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			return fmt.Errorf("silence trimming cancelled: %w", ctx.Err())
+		}
+		return fmt.Errorf("failed to trim silence: %w, stderr: %s", err, stderr.String())
+	}
+
+	slog.Info("Silence trimmed", "outputPath", outputPath)
+	return nil
+}
+
+// silenceFilter builds the silenceremove filter chain for TrimSilence
+// Leading silence is removed, pauses longer than MaxPause keep MaxPause of silence, and the audio is
+// reversed to remove the trailing silence as leading silence.
+func silenceFilter(opts SilenceTrim) string {
+	threshold := strconv.FormatFloat(opts.ThresholdDB, 'f', -1, 64) + "dB"
+	leading := "silenceremove=start_periods=1:start_threshold=" + threshold
+	filter := leading
+	if opts.MaxPause > 0 {
+		pause := strconv.FormatFloat(opts.MaxPause, 'f', -1, 64)
+		filter += ":stop_periods=-1:stop_duration=" + pause + ":stop_silence=" + pause + ":stop_threshold=" + threshold
+	}
+	return filter + ",areverse," + leading + ",areverse"
+}
@@ -0,0 +1,44 @@
+package video
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSilenceFilter(t *testing.T) {
+	tests := []struct {
+		opts SilenceTrim
+		want string
+	}{
+		{
+			SilenceTrim{ThresholdDB: -50},
+			"silenceremove=start_periods=1:start_threshold=-50dB,areverse,silenceremove=start_periods=1:start_threshold=-50dB,areverse",
+		},
+		{
+			SilenceTrim{ThresholdDB: -45.5, MaxPause: 0.8},
+			"silenceremove=start_periods=1:start_threshold=-45.5dB:stop_periods=-1:stop_duration=0.8:stop_silence=0.8:stop_threshold=-45.5dB," +
+				"areverse,silenceremove=start_periods=1:start_threshold=-45.5dB,areverse",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := silenceFilter(tt.opts); got != tt.want {
+			t.Errorf("silenceFilter(%+v):\nexpected %s\ngot      %s", tt.opts, tt.want, got)
+		}
+	}
+}
+
+func TestTrimSilence_InvalidThreshold(t *testing.T) {
+	if err := TrimSilence(context.Background(), "in.mp3", "out.mp3", SilenceTrim{ThresholdDB: 0}); err == nil {
+		t.Error("expected error for non-negative threshold")
+	}
+}
+
+func TestTrimSilence_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := TrimSilence(ctx, "/nonexistent/audio.mp3", "/tmp/trimmed.mp3", SilenceTrim{ThresholdDB: -50}); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
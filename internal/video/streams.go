@@ -0,0 +1,203 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// ProbeAudioTracks lists the audio streams of a video file using ffprobe
+func ProbeAudioTracks(ctx context.Context, videoPath string) ([]models.AudioTrack, error) {
+	slog.Debug("Probing audio tracks", "videoPath", videoPath)
+
+	// Check context cancellation before starting
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("audio track probe cancelled: %w", ctx.Err())
+	default:
+	}
+
+	// ffprobe -v error -select_streams a -show_entries stream=codec_name,channels:stream_tags=language,title:stream_disposition=default -of json video.mp4
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-select_streams", "a",
+		"-show_entries", "stream=codec_name,channels:stream_tags=language,title:stream_disposition=default",
+		"-of", "json",
+		videoPath,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("audio track probe cancelled: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("failed to probe audio tracks: %w, stderr: %s", err, stderr.String())
+	}
+
+	tracks, err := parseAudioTracks(stdout.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Audio tracks probed", "tracks", len(tracks))
+	return tracks, nil
+}
+
+// parseAudioTracks converts ffprobe JSON output for audio streams into tracks, numbered in stream order
+func parseAudioTracks(data []byte) ([]models.AudioTrack, error) {
+	var probe struct {
+		Streams []struct {
+			CodecName   string            `json:"codec_name"`
+			Channels    int               `json:"channels"`
+			Tags        map[string]string `json:"tags"`
+			Disposition map[string]int    `json:"disposition"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse audio track probe: %w", err)
+	}
+
+	tracks := make([]models.AudioTrack, 0, len(probe.Streams))
+	for i, stream := range probe.Streams {
+		tracks = append(tracks, models.AudioTrack{
+			Track:    i,
+			Codec:    stream.CodecName,
+			Channels: stream.Channels,
+			Language: stream.Tags["language"],
+			Title:    stream.Tags["title"],
+			Default:  stream.Disposition["default"] == 1,
+		})
+	}
+	return tracks, nil
+}
+
+// ProbeMedia reports the duration, container format, video stream and audio tracks of a file in a single ffprobe call
+func ProbeMedia(ctx context.Context, path string) (*models.MediaInfo, error) {
+	slog.Debug("Probing media", "path", path)
+
+	// Check context cancellation before starting
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("media probe cancelled: %w", ctx.Err())
+	default:
+	}
+
+	// ffprobe -v error -show_entries format=duration,format_name:stream=codec_type,codec_name,width,height,avg_frame_rate,channels:stream_tags=language,title:stream_disposition=default,attached_pic -of json video.mp4
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration,format_name:stream=codec_type,codec_name,width,height,avg_frame_rate,channels:stream_tags=language,title:stream_disposition=default,attached_pic",
+		"-of", "json",
+		path,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("media probe cancelled: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("failed to probe media: %w, stderr: %s", err, stderr.String())
+	}
+
+	info, err := parseMediaInfo(stdout.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Media probed", "duration", info.Duration, "audioTracks", len(info.AudioTracks))
+	return info, nil
+}
+
+// parseMediaInfo converts ffprobe JSON output for the format and all streams into media info
+// Only the first video stream is kept; audio streams are numbered in stream order as by parseAudioTracks.
+func parseMediaInfo(data []byte) (*models.MediaInfo, error) {
+	var probe struct {
+		Format struct {
+			Duration   string `json:"duration"`
+			FormatName string `json:"format_name"`
+		} `json:"format"`
+		Streams []struct {
+			CodecType    string            `json:"codec_type"`
+			CodecName    string            `json:"codec_name"`
+			Width        int               `json:"width"`
+			Height       int               `json:"height"`
+			AvgFrameRate string            `json:"avg_frame_rate"`
+			Channels     int               `json:"channels"`
+			Tags         map[string]string `json:"tags"`
+			Disposition  map[string]int    `json:"disposition"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse media probe: %w", err)
+	}
+
+	info := &models.MediaInfo{
+		Format:      probe.Format.FormatName,
+		AudioTracks: []models.AudioTrack{},
+	}
+	if probe.Format.Duration != "" {
+		duration, err := strconv.ParseFloat(probe.Format.Duration, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse media duration: %w", err)
+		}
+		info.Duration = duration
+	}
+
+	for _, stream := range probe.Streams {
+		switch stream.CodecType {
+		case "video":
+			// Cover art is reported as a video stream too
+			if info.Video != nil || stream.Disposition["attached_pic"] == 1 {
+				continue
+			}
+			info.Video = &models.VideoStream{
+				Codec:     stream.CodecName,
+				Width:     stream.Width,
+				Height:    stream.Height,
+				FrameRate: parseFrameRate(stream.AvgFrameRate),
+			}
+		case "audio":
+			info.AudioTracks = append(info.AudioTracks, models.AudioTrack{
+				Track:    len(info.AudioTracks),
+				Codec:    stream.CodecName,
+				Channels: stream.Channels,
+				Language: stream.Tags["language"],
+				Title:    stream.Tags["title"],
+				Default:  stream.Disposition["default"] == 1,
+			})
+		}
+	}
+	return info, nil
+}
+
+// parseFrameRate converts an ffprobe rate such as "30000/1001" to frames per second, or 0 if unknown
+func parseFrameRate(rate string) float64 {
+	numerator, denominator, ok := strings.Cut(rate, "/")
+	if !ok {
+		fps, _ := strconv.ParseFloat(rate, 64)
+		return fps
+	}
+	num, err := strconv.ParseFloat(numerator, 64)
+	if err != nil {
+		return 0
+	}
+	den, err := strconv.ParseFloat(denominator, 64)
+	if err != nil || den == 0 {
+		return 0
+	}
+	return num / den
+}
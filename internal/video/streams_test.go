@@ -0,0 +1,118 @@
+package video
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestParseAudioTracks(t *testing.T) {
+	output := []byte(`{
+		"programs": [],
+		"streams": [
+			{"codec_name": "aac", "channels": 2, "disposition": {"default": 1}, "tags": {"language": "fra"}},
+			{"codec_name": "ac3", "channels": 6, "disposition": {"default": 0}, "tags": {"language": "eng", "title": "Commentary"}}
+		]
+	}`)
+
+	tracks, err := parseAudioTracks(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []models.AudioTrack{
+		{Track: 0, Codec: "aac", Channels: 2, Language: "fra", Default: true},
+		{Track: 1, Codec: "ac3", Channels: 6, Language: "eng", Title: "Commentary"},
+	}
+	if !reflect.DeepEqual(tracks, want) {
+		t.Errorf("expected %+v, got %+v", want, tracks)
+	}
+}
+
+func TestParseAudioTracks_NoAudio(t *testing.T) {
+	tracks, err := parseAudioTracks([]byte(`{"streams": []}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tracks) != 0 {
+		t.Errorf("expected no tracks, got %v", tracks)
+	}
+}
+
+func TestParseAudioTracks_InvalidJSON(t *testing.T) {
+	if _, err := parseAudioTracks([]byte("not json")); err == nil {
+		t.Error("expected error for invalid output")
+	}
+}
+
+func TestProbeAudioTracks_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ProbeAudioTracks(ctx, "/nonexistent/video.mp4"); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
+
+func TestParseMediaInfo(t *testing.T) {
+	output := []byte(`{
+		"streams": [
+			{"codec_type": "video", "codec_name": "h264", "width": 1920, "height": 1080, "avg_frame_rate": "30000/1001", "disposition": {"default": 1, "attached_pic": 0}},
+			{"codec_type": "audio", "codec_name": "aac", "channels": 2, "disposition": {"default": 1}, "tags": {"language": "eng"}},
+			{"codec_type": "video", "codec_name": "mjpeg", "width": 300, "height": 300, "avg_frame_rate": "0/0", "disposition": {"attached_pic": 1}},
+			{"codec_type": "audio", "codec_name": "ac3", "channels": 6, "tags": {"title": "Commentary"}}
+		],
+		"format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2", "duration": "125.458000"}
+	}`)
+
+	info, err := parseMediaInfo(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Duration != 125.458 || info.Format != "mov,mp4,m4a,3gp,3g2,mj2" {
+		t.Errorf("unexpected format info: %+v", info)
+	}
+	if info.Video == nil || info.Video.Codec != "h264" || info.Video.Width != 1920 || info.Video.Height != 1080 {
+		t.Errorf("expected the first video stream, got %+v", info.Video)
+	}
+	if info.Video != nil && (info.Video.FrameRate < 29.97 || info.Video.FrameRate > 29.98) {
+		t.Errorf("expected 29.97 fps, got %v", info.Video.FrameRate)
+	}
+	want := []models.AudioTrack{
+		{Track: 0, Codec: "aac", Channels: 2, Language: "eng", Default: true},
+		{Track: 1, Codec: "ac3", Channels: 6, Title: "Commentary"},
+	}
+	if !reflect.DeepEqual(info.AudioTracks, want) {
+		t.Errorf("expected %+v, got %+v", want, info.AudioTracks)
+	}
+}
+
+func TestParseMediaInfo_AudioOnly(t *testing.T) {
+	info, err := parseMediaInfo([]byte(`{"streams": [{"codec_type": "audio", "codec_name": "mp3"}], "format": {"duration": "3.5"}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Video != nil || len(info.AudioTracks) != 1 || info.Duration != 3.5 {
+		t.Errorf("unexpected media info: %+v", info)
+	}
+}
+
+func TestParseFrameRate(t *testing.T) {
+	tests := []struct {
+		rate string
+		want float64
+	}{
+		{"25/1", 25},
+		{"24", 24},
+		{"0/0", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := parseFrameRate(tt.rate); got != tt.want {
+			t.Errorf("parseFrameRate(%q): expected %v, got %v", tt.rate, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// AdjustTempo changes the speed of an audio file by factor without changing its pitch
+// A factor above 1 speeds the audio up (shortens it), below 1 slows it down.
+func AdjustTempo(ctx context.Context, audioPath string, outputPath string, factor float64) error {
+	slog.Info("Adjusting audio tempo",
+		"audioPath", audioPath,
+		"factor", factor,
+		"outputPath", outputPath)
+
+	if factor <= 0 {
+		return fmt.Errorf("invalid tempo factor: %v", factor)
+	}
+
+	// Check context cancellation before starting
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("tempo adjustment cancelled: %w", ctx.Err())
+	default:
+	}
+
+	// ffmpeg -i audio.mp3 -filter:a atempo=1.100 -y output.mp3
+	cmd := FFmpegCommand(ctx,
+		"-i", audioPath,
+		"-filter:a", atempoFilter(factor),
+		"-y", // Overwrite output file
+		outputPath,
+	)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			return fmt.Errorf("tempo adjustment cancelled: %w", ctx.Err())
+		}
+		return fmt.Errorf("failed to adjust audio tempo: %w, stderr: %s", err, stderr.String())
+	}
+
+	slog.Info("Audio tempo adjusted", "outputPath", outputPath)
+	return nil
+}
+
+// atempoFilter builds an atempo filter chain for factor
+// A single atempo filter accepts 0.5 to 2.0 on older ffmpeg builds, so larger changes are chained.
+func atempoFilter(factor float64) string {
+	var filters []string
+	for factor > 2 {
+		filters = append(filters, "atempo=2.0")
+		factor /= 2
+	}
+	for factor < 0.5 {
+		filters = append(filters, "atempo=0.5")
+		factor /= 0.5
+	}
+	filters = append(filters, "atempo="+strconv.FormatFloat(factor, 'f', 4, 64))
+	return strings.Join(filters, ",")
+}
@@ -0,0 +1,39 @@
+package video
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAtempoFilter(t *testing.T) {
+	tests := []struct {
+		factor float64
+		want   string
+	}{
+		{1.1, "atempo=1.1000"},
+		{0.8, "atempo=0.8000"},
+		{5, "atempo=2.0,atempo=2.0,atempo=1.2500"},
+		{0.2, "atempo=0.5,atempo=0.5,atempo=0.8000"},
+	}
+
+	for _, tt := range tests {
+		if got := atempoFilter(tt.factor); got != tt.want {
+			t.Errorf("atempoFilter(%v): expected %s, got %s", tt.factor, tt.want, got)
+		}
+	}
+}
+
+func TestAdjustTempo_InvalidFactor(t *testing.T) {
+	if err := AdjustTempo(context.Background(), "in.mp3", "out.mp3", 0); err == nil {
+		t.Error("expected error for zero factor")
+	}
+}
+
+func TestAdjustTempo_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := AdjustTempo(ctx, "/nonexistent/audio.mp3", "/tmp/tempo.mp3", 1.2); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
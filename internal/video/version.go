@@ -0,0 +1,33 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ToolVersion returns the version reported by an FFmpeg tool (e.g., "ffmpeg" or "ffprobe")
+func ToolVersion(ctx context.Context, tool string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, tool, "-version")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run %s -version: %w, stderr: %s", tool, err, stderr.String())
+	}
+	return parseToolVersion(tool, stdout.String())
+}
+
+// parseToolVersion extracts the version from the first line of -version output
+// e.g., "ffmpeg version 6.1.1 Copyright (c) 2000-2023 the FFmpeg developers" -> "6.1.1"
+func parseToolVersion(tool string, output string) (string, error) {
+	firstLine, _, _ := strings.Cut(output, "\n")
+	fields := strings.Fields(firstLine)
+	if len(fields) < 3 || fields[0] != tool || fields[1] != "version" {
+		return "", fmt.Errorf("unexpected %s -version output: %q", tool, firstLine)
+	}
+	return fields[2], nil
+}
@@ -0,0 +1,42 @@
+package video
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseToolVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		tool    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{"release", "ffmpeg", "ffmpeg version 6.1.1 Copyright (c) 2000-2023 the FFmpeg developers\nbuilt with gcc 13.2.1", "6.1.1", false},
+		{"distribution build", "ffprobe", "ffprobe version 4.4.2-0ubuntu0.22.04.1 Copyright (c) 2007-2021", "4.4.2-0ubuntu0.22.04.1", false},
+		{"other tool", "ffprobe", "ffmpeg version 6.1.1 Copyright", "", true},
+		{"empty output", "ffmpeg", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseToolVersion(tt.tool, tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestToolVersion_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ToolVersion(ctx, "ffmpeg"); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
@@ -0,0 +1,53 @@
+package workerpool
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// Pool bounds how many tasks of one kind run at once across all jobs on the instance
+// Separate pools keep CPU-bound ffmpeg processes and IO-bound API calls from starving each other.
+type Pool struct {
+	name    string
+	slots   chan struct{}
+	waiting atomic.Int64
+}
+
+// New creates a pool allowing size concurrent tasks
+func New(name string, size int) *Pool {
+	if size <= 0 {
+		size = 1
+	}
+	return &Pool{
+		name:  name,
+		slots: make(chan struct{}, size),
+	}
+}
+
+// Do runs fn once a slot is free, or returns the context error if ctx ends while waiting
+func (p *Pool) Do(ctx context.Context, fn func() error) error {
+	p.waiting.Add(1)
+	select {
+	case p.slots <- struct{}{}:
+		p.waiting.Add(-1)
+	case <-ctx.Done():
+		p.waiting.Add(-1)
+		return fmt.Errorf("waiting for %s worker: %w", p.name, ctx.Err())
+	}
+	defer func() { <-p.slots }()
+
+	return fn()
+}
+
+// Stats returns the pool's current usage
+func (p *Pool) Stats() models.PoolStats {
+	return models.PoolStats{
+		Name:    p.name,
+		Size:    cap(p.slots),
+		InUse:   len(p.slots),
+		Waiting: int(p.waiting.Load()),
+	}
+}
@@ -0,0 +1,88 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_BoundsConcurrency(t *testing.T) {
+	pool := New("ffmpeg", 2)
+
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pool.Do(context.Background(), func() error {
+				current := running.Add(1)
+				for {
+					observed := peak.Load()
+					if current <= observed || peak.CompareAndSwap(observed, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				running.Add(-1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent tasks, got %d", peak.Load())
+	}
+	if stats := pool.Stats(); stats.InUse != 0 || stats.Waiting != 0 || stats.Size != 2 {
+		t.Errorf("unexpected stats after completion: %+v", stats)
+	}
+}
+
+func TestPool_ReturnsTaskError(t *testing.T) {
+	pool := New("api", 1)
+	want := errors.New("boom")
+
+	if err := pool.Do(context.Background(), func() error { return want }); !errors.Is(err, want) {
+		t.Errorf("expected task error, got %v", err)
+	}
+}
+
+func TestPool_CancelledWhileWaiting(t *testing.T) {
+	pool := New("api", 1)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go pool.Do(context.Background(), func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	ran := false
+	err := pool.Do(ctx, func() error {
+		ran = true
+		return nil
+	})
+	close(release)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if ran {
+		t.Error("expected task not to run after the context ended")
+	}
+}
+
+func TestNew_MinimumSize(t *testing.T) {
+	if size := New("api", 0).Stats().Size; size != 1 {
+		t.Errorf("expected size 1, got %d", size)
+	}
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// Client calls the video translation API
+type Client struct {
+	BaseURL    string       // Service URL, e.g. https://your-function-url
+	APIKey     string       // Sent as X-API-Key when set
+	HTTPClient *http.Client // Defaults to a client with a 30 second timeout
+}
+
+// New creates a client for the service at baseURL
+func New(baseURL string, apiKey string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// APIError is an error response from the service
+type APIError struct {
+	StatusCode int
+	Code       string // Machine-readable error code, when the service sent one
+	Message    string
+	RetryAfter time.Duration // From the Retry-After header of 429 and 503 responses
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("api error %d (%s): %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("api error %d: %s", e.StatusCode, e.Message)
+}
+
+// Temporary reports whether the request may succeed when repeated later
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Translate submits a translation job and returns its ID
+func (c *Client) Translate(ctx context.Context, req *models.TranslateRequest) (*models.TranslateResponse, error) {
+	var resp models.TranslateResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/translate", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Status returns the current status of a job
+func (c *Client) Status(ctx context.Context, jobID string) (*models.StatusResponse, error) {
+	var status models.StatusResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/status/"+url.PathEscape(jobID), nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Transcript returns the source transcript of a job once it has been transcribed
+func (c *Client) Transcript(ctx context.Context, jobID string) (*models.TranscriptResponse, error) {
+	var transcript models.TranscriptResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/jobs/"+url.PathEscape(jobID)+"/transcript", nil, &transcript); err != nil {
+		return nil, err
+	}
+	return &transcript, nil
+}
+
+// Inspect probes a video and reports whether a translation job would accept it
+func (c *Client) Inspect(ctx context.Context, videoURL string) (*models.InspectResponse, error) {
+	var resp models.InspectResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/inspect", &models.InspectRequest{VideoURL: videoURL}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// do sends a JSON request and decodes the JSON response into out
+// Non-2xx responses are returned as *APIError.
+func (c *Client) do(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.APIKey != "" {
+		req.Header.Set("X-API-Key", c.APIKey)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(respBody))}
+		var errResp models.ErrorResponse
+		if json.Unmarshal(respBody, &errResp) == nil && (errResp.Message != "" || errResp.Code != "") {
+			apiErr.Code = errResp.Code
+			apiErr.Message = errResp.Message
+		}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			apiErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return apiErr
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
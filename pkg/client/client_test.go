@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+func TestClient_Translate(t *testing.T) {
+	var received models.TranslateRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/translate" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("X-API-Key") != "secret" {
+			t.Errorf("expected API key header, got %q", r.Header.Get("X-API-Key"))
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"jobId":"job-1","status":"processing"}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL+"/", "secret")
+	resp, err := client.Translate(context.Background(), &models.TranslateRequest{
+		VideoURL:        "gs://bucket/video.mp4",
+		TargetLanguages: []string{"de"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.JobID != "job-1" || resp.Status != models.StatusProcessing {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if received.VideoURL != "gs://bucket/video.mp4" {
+		t.Errorf("expected request body to be sent, got %+v", received)
+	}
+}
+
+func TestClient_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":"Service Unavailable","code":"instance_at_capacity","message":"instance is busy"}`))
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL, "").Status(context.Background(), "job-1")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusServiceUnavailable || apiErr.Code != "instance_at_capacity" || apiErr.Message != "instance is busy" {
+		t.Errorf("unexpected error fields: %+v", apiErr)
+	}
+	if apiErr.RetryAfter != 30*time.Second {
+		t.Errorf("expected Retry-After of 30s, got %v", apiErr.RetryAfter)
+	}
+	if !apiErr.Temporary() {
+		t.Error("expected 503 to be temporary")
+	}
+}
@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// State is the terminal state of a job
+type State string
+
+const (
+	StateCompleted          State = "completed"
+	StatePartiallyCompleted State = "partially_completed" // Some languages completed and the others failed
+	StateFailed             State = "failed"
+)
+
+// Result is a job that reached a terminal state
+type Result struct {
+	State  State
+	Status *models.StatusResponse
+}
+
+// Succeeded reports whether every language completed or was skipped
+func (r *Result) Succeeded() bool {
+	return r.State == StateCompleted
+}
+
+// FailedLanguages returns the languages that failed, sorted
+func (r *Result) FailedLanguages() []string {
+	var failed []string
+	for language, result := range r.Status.Results {
+		if result.Status == models.StatusFailed {
+			failed = append(failed, language)
+		}
+	}
+	sort.Strings(failed)
+	return failed
+}
+
+// WaitOptions controls how WaitForCompletion polls a job
+type WaitOptions struct {
+	InitialInterval time.Duration // Delay before the second poll (default 2s)
+	MaxInterval     time.Duration // Upper bound of the delay between polls (default 30s)
+	Multiplier      float64       // Growth of the delay after each poll (default 1.5)
+	MaxPollErrors   int           // Consecutive temporary poll failures tolerated (default 5)
+
+	// OnLanguage is called once for each language as soon as a poll shows it finished
+	OnLanguage func(language string, result *models.LanguageResult)
+
+	// OnStatus is called with every polled status, e.g. to report progress
+	OnStatus func(status *models.StatusResponse)
+}
+
+// withDefaults fills unset options with their defaults
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.InitialInterval <= 0 {
+		o.InitialInterval = 2 * time.Second
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = 30 * time.Second
+	}
+	if o.MaxInterval < o.InitialInterval {
+		o.MaxInterval = o.InitialInterval
+	}
+	if o.Multiplier < 1 {
+		o.Multiplier = 1.5
+	}
+	if o.MaxPollErrors <= 0 {
+		o.MaxPollErrors = 5
+	}
+	return o
+}
+
+// WaitForCompletion polls a job with bounded exponential backoff until it reaches a terminal state
+// A failed job with a scheduled retry (nextRetryAt) is not terminal: it is polled until the retry finishes.
+// Temporary failures (network errors, 429 and 5xx responses) are retried, honouring Retry-After;
+// other API errors, such as an unknown job, are returned at once. Use ctx to bound the total wait.
+func (c *Client) WaitForCompletion(ctx context.Context, jobID string, opts WaitOptions) (*Result, error) {
+	opts = opts.withDefaults()
+	interval := opts.InitialInterval
+	reported := make(map[string]bool)
+	pollErrors := 0
+
+	for {
+		wait := interval
+		status, err := c.Status(ctx, jobID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("waiting for job %s: %w", jobID, ctx.Err())
+			}
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && !apiErr.Temporary() {
+				return nil, err
+			}
+			pollErrors++
+			if pollErrors > opts.MaxPollErrors {
+				return nil, fmt.Errorf("polling job %s failed %d times: %w", jobID, pollErrors, err)
+			}
+			if apiErr != nil && apiErr.RetryAfter > wait {
+				wait = apiErr.RetryAfter
+			}
+		} else {
+			pollErrors = 0
+			reportLanguages(status, reported, opts.OnLanguage)
+			if opts.OnStatus != nil {
+				opts.OnStatus(status)
+			}
+			if state, ok := terminalState(status); ok {
+				return &Result{State: state, Status: status}, nil
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("waiting for job %s: %w", jobID, ctx.Err())
+		case <-timer.C:
+		}
+		interval = min(time.Duration(float64(interval)*opts.Multiplier), opts.MaxInterval)
+	}
+}
+
+// reportLanguages calls onLanguage, in language order, for finished languages not reported yet
+// Failed languages of a job with a scheduled retry are reported once the retry settled them.
+func reportLanguages(status *models.StatusResponse, reported map[string]bool, onLanguage func(string, *models.LanguageResult)) {
+	if onLanguage == nil {
+		return
+	}
+	languages := make([]string, 0, len(status.Results))
+	for language, result := range status.Results {
+		// "error" holds the reason of a job that failed before any language started
+		retrying := status.NextRetryAt != nil && result.Status == models.StatusFailed
+		if language != "error" && result.IsFinished() && !retrying && !reported[language] {
+			languages = append(languages, language)
+		}
+	}
+	sort.Strings(languages)
+	for _, language := range languages {
+		reported[language] = true
+		onLanguage(language, status.Results[language])
+	}
+}
+
+// terminalState maps a job status to its terminal state, or false while the job is still running
+// or waiting for a scheduled retry
+func terminalState(status *models.StatusResponse) (State, bool) {
+	if status.NextRetryAt != nil {
+		return "", false
+	}
+	switch status.Status {
+	case models.StatusCompleted:
+		return StateCompleted, true
+	case models.StatusPartiallyCompleted:
+		return StatePartiallyCompleted, true
+	case models.StatusFailed:
+		return StateFailed, true
+	}
+	return "", false
+}
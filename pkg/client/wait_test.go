@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/pkg/models"
+)
+
+// fastPolling keeps tests quick
+var fastPolling = WaitOptions{InitialInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+
+func TestWaitForCompletion(t *testing.T) {
+	responses := []string{
+		`{"jobId":"job-1","status":"processing","results":{"de":{"status":"processing"},"fr":{"status":"processing"}}}`,
+		`{"jobId":"job-1","status":"processing","results":{"de":{"status":"completed","videoUrl":"https://example.com/de.mp4"},"fr":{"status":"processing"}}}`,
+		`{"jobId":"job-1","status":"partially_completed","results":{"de":{"status":"completed","videoUrl":"https://example.com/de.mp4"},"fr":{"status":"failed","error":"tts failed"}}}`,
+	}
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(polls.Add(1)) - 1
+		w.Write([]byte(responses[min(n, len(responses)-1)]))
+	}))
+	defer server.Close()
+
+	var languages []string
+	opts := fastPolling
+	opts.OnLanguage = func(language string, result *models.LanguageResult) {
+		languages = append(languages, language+"="+string(result.Status))
+	}
+
+	result, err := New(server.URL, "").WaitForCompletion(context.Background(), "job-1", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.State != StatePartiallyCompleted || result.Succeeded() {
+		t.Errorf("expected partially completed job, got %s", result.State)
+	}
+	if failed := result.FailedLanguages(); !reflect.DeepEqual(failed, []string{"fr"}) {
+		t.Errorf("expected fr to fail, got %v", failed)
+	}
+	if want := []string{"de=completed", "fr=failed"}; !reflect.DeepEqual(languages, want) {
+		t.Errorf("expected each language reported once, got %v", languages)
+	}
+	if polls.Load() != 3 {
+		t.Errorf("expected 3 polls, got %d", polls.Load())
+	}
+}
+
+func TestWaitForCompletion_ScheduledRetry(t *testing.T) {
+	responses := []string{
+		`{"jobId":"job-1","status":"failed","nextRetryAt":"2026-01-01T00:00:00Z","results":{"de":{"status":"completed"},"fr":{"status":"failed","error":"quota exceeded"}}}`,
+		`{"jobId":"job-1","status":"processing","results":{"de":{"status":"completed"},"fr":{"status":"processing"}}}`,
+		`{"jobId":"job-1","status":"completed","results":{"de":{"status":"completed"},"fr":{"status":"completed"}}}`,
+	}
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(polls.Add(1)) - 1
+		w.Write([]byte(responses[min(n, len(responses)-1)]))
+	}))
+	defer server.Close()
+
+	var languages []string
+	opts := fastPolling
+	opts.OnLanguage = func(language string, result *models.LanguageResult) {
+		languages = append(languages, language+"="+string(result.Status))
+	}
+
+	result, err := New(server.URL, "").WaitForCompletion(context.Background(), "job-1", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Succeeded() || polls.Load() != 3 {
+		t.Errorf("expected the job completed by its retry after 3 polls, got %s after %d", result.State, polls.Load())
+	}
+	if want := []string{"de=completed", "fr=completed"}; !reflect.DeepEqual(languages, want) {
+		t.Errorf("expected the retried language reported once it completed, got %v", languages)
+	}
+}
+
+func TestWaitForCompletion_RetriesTemporaryErrors(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if polls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"jobId":"job-1","status":"completed"}`))
+	}))
+	defer server.Close()
+
+	result, err := New(server.URL, "").WaitForCompletion(context.Background(), "job-1", fastPolling)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Succeeded() {
+		t.Errorf("expected completed job, got %s", result.State)
+	}
+}
+
+func TestWaitForCompletion_PermanentError(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"Not Found","message":"job not found"}`))
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL, "").WaitForCompletion(context.Background(), "missing", fastPolling)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 APIError, got %v", err)
+	}
+	if polls.Load() != 1 {
+		t.Errorf("expected a single poll, got %d", polls.Load())
+	}
+}
+
+func TestWaitForCompletion_ContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jobId":"job-1","status":"processing"}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := New(server.URL, "").WaitForCompletion(ctx, "job-1", fastPolling)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestWaitOptions_Defaults(t *testing.T) {
+	opts := WaitOptions{InitialInterval: time.Minute}.withDefaults()
+	if opts.MaxInterval != time.Minute {
+		t.Errorf("expected max interval raised to the initial interval, got %v", opts.MaxInterval)
+	}
+	if opts.Multiplier != 1.5 || opts.MaxPollErrors != 5 {
+		t.Errorf("unexpected defaults: %+v", opts)
+	}
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Pipeline stages reported in job status while a job runs
+const (
+	StageCheckingSource    = "checking_source"
+	StageWaitingForDisk    = "waiting_for_disk"
+	StageDownloading       = "downloading"
+	StageSplittingChapters = "splitting_chapters"
+	StageExtractingAudio   = "extracting_audio"
+	StageLoadingSubtitles  = "loading_subtitles"
+	StageTranscribing      = "transcribing"
+	StageSeparatingAudio   = "separating_audio"
+	StageSummarizing       = "summarizing"
+	StagePunctuating       = "restoring_punctuation"
+	StageLanguages         = "processing_languages"
+	StageFinalizing        = "finalizing"
+)
+
+// AdminJob summarizes an active job for the admin API
+type AdminJob struct {
+	JobID     string         `json:"jobId"`
+	Owner     string         `json:"owner,omitempty"`
+	Stage     string         `json:"stage,omitempty"`
+	Running   bool           `json:"running"`   // Whether a worker on this instance is processing the job
+	Languages map[string]int `json:"languages"` // Progress (0-100) per target language
+	CreatedAt *time.Time     `json:"createdAt,omitempty"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	Age       string         `json:"age"`
+}
+
+// AdminJobsResponse represents the response from the admin active jobs endpoint
+type AdminJobsResponse struct {
+	Jobs  []*AdminJob `json:"jobs"`
+	Count int         `json:"count"`
+}
+
+// QueueStats describes the work currently held by this instance
+type QueueStats struct {
+	ActiveJobs       int `json:"activeJobs"`       // Jobs in processing status
+	RunningJobs      int `json:"runningJobs"`      // Jobs with a live worker on this instance
+	PendingLanguages int `json:"pendingLanguages"` // Target languages of active jobs not yet finished
+
+	Pools []PoolStats `json:"pools,omitempty"` // Per-stage worker pools
+}
+
+// PoolStats describes the usage of a worker pool
+type PoolStats struct {
+	Name    string `json:"name"`
+	Size    int    `json:"size"`    // Maximum concurrent tasks
+	InUse   int    `json:"inUse"`   // Tasks currently running
+	Waiting int    `json:"waiting"` // Tasks queued for a free slot
+}
+
+// AdminActionResponse represents the result of an admin operation
+type AdminActionResponse struct {
+	Action  string `json:"action"`
+	JobID   string `json:"jobId,omitempty"`
+	Removed int    `json:"removed,omitempty"` // Items removed by flush or cleanup
+}
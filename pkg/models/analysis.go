@@ -0,0 +1,16 @@
+package models
+
+// VideoAnalysis holds the keywords and chapter markers extracted from the transcript of a language (analysis option)
+type VideoAnalysis struct {
+	Keywords []string        `json:"keywords"`
+	Chapters []ChapterMarker `json:"chapters"`
+}
+
+// ChapterMarker is the start of a chapter of the video, in seconds, with its title
+type ChapterMarker struct {
+	Start float64 `json:"start"`
+	Title string  `json:"title"`
+}
+
+// MaxAnalysisKeywords bounds the keywords extracted per language
+const MaxAnalysisKeywords = 10
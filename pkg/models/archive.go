@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Job event log entries that are not webhook events
+const (
+	EventJobStage   = "job.stage"   // The job moved to another pipeline stage
+	EventJobRetried = "job.retried" // Failed languages were claimed for a retry
+)
+
+// JobEvent is an entry of a job's event log, kept for archival export
+type JobEvent struct {
+	Time     time.Time `json:"time"`
+	Event    string    `json:"event"`
+	Stage    string    `json:"stage,omitempty"`
+	Language string    `json:"language,omitempty"`
+	Message  string    `json:"message,omitempty"`
+}
+
+// RecordEvent appends an event at the job's current stage to its event log
+func (s *StatusResponse) RecordEvent(event string, language string, message string) {
+	s.Events = append(s.Events, JobEvent{
+		Time:     time.Now(),
+		Event:    event,
+		Stage:    s.Stage,
+		Language: language,
+		Message:  message,
+	})
+}
+
+// JobArchive is the full record of a job, exported as exports/{jobId}/job.json
+type JobArchive struct {
+	JobID          string             `json:"jobId"`
+	ExportedAt     time.Time          `json:"exportedAt"`
+	Owner          string             `json:"owner,omitempty"`
+	Request        *TranslateRequest  `json:"request,omitempty"`
+	Status         *StatusResponse    `json:"status"`
+	SourceLanguage string             `json:"sourceLanguage,omitempty"`
+	Transcript     string             `json:"transcript,omitempty"`
+	Translations   map[string]string  `json:"translations,omitempty"` // Translated text by target language
+	Outputs        []ArchivedArtifact `json:"outputs,omitempty"`
+	Events         []JobEvent         `json:"events,omitempty"`
+}
+
+// ArchivedArtifact is an output file of a job; Name is its path inside the export zip
+type ArchivedArtifact struct {
+	Language string `json:"language,omitempty"` // Empty for outputs shared by all languages
+	Kind     string `json:"kind"`               // video, transcript, translatedText, subtitles or an artifact kind
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+}
+
+// ExportResponse represents the response from the job export endpoint
+type ExportResponse struct {
+	JobID      string `json:"jobId"`
+	ArchiveURL string `json:"archiveUrl"`       // JSON record of the job
+	ZipURL     string `json:"zipUrl,omitempty"` // Zip of the record and output files, when requested
+}
@@ -0,0 +1,70 @@
+package models
+
+// Output audio channel layouts
+const (
+	ChannelLayoutMono   = "mono"
+	ChannelLayoutStereo = "stereo"
+)
+
+// Dubbed audio artifact formats
+const (
+	AudioFormatNone = "none" // Disables the deployment's DUBBED_AUDIO_FORMAT for a job
+	AudioFormatMP3  = "mp3"
+	AudioFormatWAV  = "wav"
+)
+
+// IsValidAudioFormat reports whether format is empty (deployment default) or a supported dubbed audio format
+func IsValidAudioFormat(format string) bool {
+	switch format {
+	case "", AudioFormatNone, AudioFormatMP3, AudioFormatWAV:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportedSampleRates lists the accepted output audio sample rates in Hz
+var SupportedSampleRates = []int{16000, 22050, 24000, 32000, 44100, 48000}
+
+// Output audio bitrate bounds in kbps
+const (
+	MinAudioBitrate = 32
+	MaxAudioBitrate = 512
+)
+
+// OutputAudioOptions overrides the deployment's encoding of the dubbed audio track
+type OutputAudioOptions struct {
+	SampleRate    int    `json:"sampleRate,omitempty"`    // Hz, e.g. 48000
+	Bitrate       int    `json:"bitrate,omitempty"`       // kbps, e.g. 192
+	ChannelLayout string `json:"channelLayout,omitempty"` // mono or stereo (mono speech is upmixed to both channels)
+}
+
+// IsValidSampleRate reports whether rate is 0 (encoder default) or a supported sample rate
+func IsValidSampleRate(rate int) bool {
+	if rate == 0 {
+		return true
+	}
+	for _, supported := range SupportedSampleRates {
+		if rate == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidAudioBitrate reports whether bitrate is 0 (encoder default) or within the supported range
+func IsValidAudioBitrate(bitrate int) bool {
+	return bitrate == 0 || (bitrate >= MinAudioBitrate && bitrate <= MaxAudioBitrate)
+}
+
+// ChannelCount returns the number of channels of a layout, or 0 for an empty or unknown layout
+func ChannelCount(layout string) int {
+	switch layout {
+	case ChannelLayoutMono:
+		return 1
+	case ChannelLayoutStereo:
+		return 2
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,55 @@
+package models
+
+// Watermark positions
+const (
+	WatermarkTopLeft     = "top-left"
+	WatermarkTopRight    = "top-right"
+	WatermarkBottomLeft  = "bottom-left"
+	WatermarkBottomRight = "bottom-right"
+)
+
+// WatermarkPositions lists the accepted watermark positions
+var WatermarkPositions = []string{WatermarkTopLeft, WatermarkTopRight, WatermarkBottomLeft, WatermarkBottomRight}
+
+// IsValidWatermarkPosition reports whether position is empty (bottom-right) or a supported corner
+func IsValidWatermarkPosition(position string) bool {
+	if position == "" {
+		return true
+	}
+	for _, supported := range WatermarkPositions {
+		if position == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// BrandingOptions overrides the deployment's watermark and bumper clips for a job
+type BrandingOptions struct {
+	WatermarkURL      string `json:"watermarkUrl,omitempty"`      // gs:// URL of an image overlaid on the dubbed videos
+	WatermarkPosition string `json:"watermarkPosition,omitempty"` // top-left, top-right, bottom-left or bottom-right
+	IntroURL          string `json:"introUrl,omitempty"`          // gs:// URL of a clip prepended to the dubbed videos
+	OutroURL          string `json:"outroUrl,omitempty"`          // gs:// URL of a clip appended to the dubbed videos
+}
+
+// BrandingAssets are the local copies of a job's watermark and bumper clips
+type BrandingAssets struct {
+	WatermarkPath     string
+	WatermarkPosition string
+	IntroPath         string
+	OutroPath         string
+}
+
+// Files returns the downloaded asset files
+func (b *BrandingAssets) Files() []string {
+	if b == nil {
+		return nil
+	}
+	var files []string
+	for _, path := range []string{b.WatermarkPath, b.IntroPath, b.OutroPath} {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	return files
+}
@@ -0,0 +1,54 @@
+package models
+
+// CapabilitiesResponse describes what a deployment supports so clients can adapt automatically
+type CapabilitiesResponse struct {
+	APIVersion     string            `json:"apiVersion"`
+	Languages      []string          `json:"languages"`      // Supported target languages
+	Presets        []string          `json:"presets"`        // Accepted values for the request "preset" field
+	OutputFormats  []string          `json:"outputFormats"`  // File formats the deployment can produce
+	Providers      map[string]string `json:"providers"`      // Backend per pipeline stage (stt, translation, tts)
+	Notifications  []string          `json:"notifications"`  // Enabled notification channels
+	Features       map[string]bool   `json:"features"`       // Optional features and whether they are enabled
+	Limits         CapabilityLimits  `json:"limits"`         // Request and processing limits
+	RequestOptions []string          `json:"requestOptions"` // Optional request fields accepted by /v1/translate
+
+	// TranslationRoutes lists the translation provider chain of target languages routed away from the default chain
+	TranslationRoutes map[string][]string `json:"translationRoutes,omitempty"`
+	// TTSRoutes lists the voice chain of target languages dubbed in other voices than the default ones
+	TTSRoutes map[string][]string `json:"ttsRoutes,omitempty"`
+	// APIVersions lists the request schemas accepted on /{version}/translate
+	APIVersions []string `json:"apiVersions"`
+}
+
+// CapabilityLimits lists the configured processing limits
+type CapabilityLimits struct {
+	MaxVideoDurationSeconds   int   `json:"maxVideoDurationSeconds"`
+	MaxVideoSizeMB            int   `json:"maxVideoSizeMB"`
+	MaxRequestBodyBytes       int64 `json:"maxRequestBodyBytes"`
+	MaxConcurrentTranslations int   `json:"maxConcurrentTranslations"`
+	MaxTargetLanguages        int   `json:"maxTargetLanguages,omitempty"`
+	RateLimitRPM              int   `json:"rateLimitRpm"`
+	RateLimitStatusRPM        int   `json:"rateLimitStatusRpm"`
+	MaxTranscriptChars        int   `json:"maxTranscriptChars,omitempty"`
+	MaxInFlightJobsPerClient  int   `json:"maxInFlightJobsPerClient,omitempty"`
+
+	// MaxChapteredVideoDurationSeconds is the longest video accepted when longer videos are processed in chapters
+	MaxChapteredVideoDurationSeconds int `json:"maxChapteredVideoDurationSeconds,omitempty"`
+	// SpendBudget lists the SPEND_BUDGET_* limits when a spend budget is set
+	SpendBudget *SpendBudgetLimits `json:"spendBudget,omitempty"`
+}
+
+// SpendBudgetScopeInstance is the scope of a spend budget counted by each instance on its own
+const SpendBudgetScopeInstance = "instance"
+
+// SpendBudgetLimits describes the spend budget of a deployment
+// Scope is "instance": every instance counts its usage on its own and forgets it on restart,
+// so a deployment of several instances may use up to that many times the limits.
+type SpendBudgetLimits struct {
+	Scope               string  `json:"scope"`
+	WindowSeconds       int     `json:"windowSeconds"`
+	Cost                float64 `json:"cost,omitempty"`
+	STTSeconds          float64 `json:"sttSeconds,omitempty"`
+	TranslateCharacters int64   `json:"translateCharacters,omitempty"`
+	TTSCharacters       int64   `json:"ttsCharacters,omitempty"`
+}
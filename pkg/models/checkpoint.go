@@ -0,0 +1,78 @@
+package models
+
+// JobCheckpoint holds the intermediate results needed to re-run target languages
+// without downloading and transcribing the source video again
+type JobCheckpoint struct {
+	Transcript     string  // Transcript after moderation, as sent to translation
+	SourceLanguage string  // Detected or requested source language
+	VideoDuration  float64 // Source video duration in seconds
+	VideoPath      string  // Local copy of the source video, empty once released
+	TranscriptURL  string  // Public URL of the uploaded source transcript
+
+	// Cues are the timed source subtitles when supplied with the request (subtitleUrl), nil for transcribed jobs
+	Cues []SubtitleCue
+
+	// BackgroundPath is the separated accompaniment (music and effects) mixed under the dubbed voice, empty when not kept
+	BackgroundPath string
+	// Rotation is the display rotation of the source video in degrees clockwise (0, 90, 180 or 270)
+	Rotation int
+	// Video is the source video stream sizing transcode offloads, nil without a transcode backend or when unknown
+	Video *VideoStream
+	// Title is the source title tag, or the video file name, that dubbed outputs are titled after
+	Title string
+	// Branding holds the watermark and bumper clips applied to the dubbed videos, nil without branding
+	Branding *BrandingAssets
+	// Translations are the transcript's translations keyed by target language when the Gemini pipeline produced them
+	Translations map[string]string
+	// Chapters are the parts of a video longer than MAX_VIDEO_DURATION, transcribed and dubbed separately; nil otherwise
+	Chapters []Chapter
+	// SpeakerGender is the estimated gender of the original speaker dubbed with voiceGender "match", empty when unknown
+	SpeakerGender string
+	// TextOffloaded is set once the transcript, translations and cues were dropped over TEXT_OFFLOAD_THRESHOLD;
+	// the transcript stays available at TranscriptURL
+	TextOffloaded bool
+}
+
+// Chapter is a part of a long source video processed on its own (CHAPTER_DURATION)
+type Chapter struct {
+	Start          float64 // Seconds from the start of the video
+	End            float64 // Seconds from the start of the video
+	VideoPath      string  // Local copy of the chapter, empty once released
+	BackgroundPath string  // Chapter range of the separated accompaniment, empty when not kept
+	Transcript     string  // Transcript of the chapter after moderation
+
+	// Translations are the chapter's translations keyed by target language when the Gemini pipeline produced them
+	Translations map[string]string
+}
+
+// TempFiles returns the local files backing the checkpoint, deleted once no retry can need them
+func (c *JobCheckpoint) TempFiles() []string {
+	var files []string
+	for _, path := range []string{c.VideoPath, c.BackgroundPath} {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	for _, chapter := range c.Chapters {
+		for _, path := range []string{chapter.VideoPath, chapter.BackgroundPath} {
+			if path != "" {
+				files = append(files, path)
+			}
+		}
+	}
+	return append(files, c.Branding.Files()...)
+}
+
+// SubtitleCue is a single timed subtitle entry
+type SubtitleCue struct {
+	Start float64 // Seconds from the start of the video
+	End   float64 // Seconds from the start of the video
+	Text  string
+}
+
+// RetryResponse represents the response from the job retry endpoint
+type RetryResponse struct {
+	JobID     string            `json:"jobId"`
+	Status    TranslationStatus `json:"status"`
+	Languages []string          `json:"languages"` // Target languages being re-run
+}
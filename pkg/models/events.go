@@ -0,0 +1,28 @@
+package models
+
+// Webhook event types
+const (
+	EventJobCompleted      = "job.completed"
+	EventJobFailed         = "job.failed"
+	EventJobPartial        = "job.partially_completed" // Some languages completed and the others failed
+	EventJobProcessing     = "job.processing"
+	EventLanguageCompleted = "language.completed" // A target language finished, successfully or not
+	EventJobProgress       = "job.progress"       // The share of finished target languages grew
+	EventJobStarted        = "job.started"        // The source video was downloaded and probed, processing begins
+)
+
+// SupportedWebhookEvents lists the events that can be selected with WEBHOOK_EVENTS or webhookEvents
+var SupportedWebhookEvents = []string{EventJobCompleted, EventJobFailed, EventJobPartial, EventLanguageCompleted, EventJobProgress, EventJobStarted}
+
+// DefaultWebhookEvents are delivered when no events are configured
+var DefaultWebhookEvents = []string{EventJobCompleted, EventJobFailed, EventJobPartial}
+
+// IsValidWebhookEvent reports whether event can be selected for webhook delivery
+func IsValidWebhookEvent(event string) bool {
+	for _, supported := range SupportedWebhookEvents {
+		if event == supported {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package models
+
+// Output presets selectable via TranslateRequest.Preset
+const (
+	// PresetAccessibility produces captions, a plain transcript and a dubbed audio track per language
+	PresetAccessibility = "accessibility"
+)
+
+// SupportedPresets lists every preset accepted by the API
+var SupportedPresets = []string{PresetAccessibility}
+
+// Artifact kinds used as keys in LanguageResult.Artifacts and the job manifest
+const (
+	ArtifactCaptions   = "captions"   // WebVTT closed captions
+	ArtifactTranscript = "transcript" // Plain-text transcript in the target language
+	ArtifactAudio      = "audio"      // Dubbed audio track (MP3)
+)
+
+// Manifest describes every output produced by a job, organized per language
+// It is uploaded next to the outputs as translations/{jobId}/manifest.json
+type Manifest struct {
+	JobID     string                    `json:"jobId"`
+	Preset    string                    `json:"preset,omitempty"`
+	Status    TranslationStatus         `json:"status"`
+	Languages map[string]*ManifestEntry `json:"languages"`
+}
+
+// ManifestEntry lists the outputs for a single target language
+type ManifestEntry struct {
+	Status    TranslationStatus `json:"status"`
+	Direction string            `json:"direction,omitempty"` // Text direction of the language (ltr or rtl)
+	VideoURL  string            `json:"videoUrl,omitempty"`
+	Artifacts map[string]string `json:"artifacts,omitempty"`
+	Summary   string            `json:"summary,omitempty"` // Short summary of the video in the language (summary option)
+
+	// Keywords and chapter markers of the language (analysis option), as JSON and YouTube-style chapter lines
+	AnalysisURL string `json:"analysisUrl,omitempty"`
+	ChaptersURL string `json:"chaptersUrl,omitempty"`
+}
+
+// IsValidPreset reports whether preset is empty or a supported preset
+func IsValidPreset(preset string) bool {
+	if preset == "" {
+		return true
+	}
+	for _, supported := range SupportedPresets {
+		if preset == supported {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package models
+
+// AudioTrack describes an audio stream of the source video
+type AudioTrack struct {
+	Track    int    `json:"track"` // 0-based position among audio streams, as used by sourceAudioTrack
+	Codec    string `json:"codec,omitempty"`
+	Channels int    `json:"channels,omitempty"`
+	Language string `json:"language,omitempty"` // Stream language tag (e.g., "eng")
+	Title    string `json:"title,omitempty"`    // Stream title tag (e.g., "Commentary")
+	Default  bool   `json:"default,omitempty"`
+}
+
+// VideoStream describes the video stream of a source video
+type VideoStream struct {
+	Codec     string  `json:"codec,omitempty"`
+	Width     int     `json:"width,omitempty"`
+	Height    int     `json:"height,omitempty"`
+	FrameRate float64 `json:"frameRate,omitempty"` // Frames per second
+}
+
+// MediaInfo describes a media file as reported by ffprobe
+type MediaInfo struct {
+	Duration    float64      `json:"duration"`         // Seconds
+	Format      string       `json:"format,omitempty"` // Container format names (e.g., "mov,mp4,m4a,3gp,3g2,mj2")
+	Video       *VideoStream `json:"video,omitempty"`  // First video stream, nil for audio-only files
+	AudioTracks []AudioTrack `json:"audioTracks"`
+}
+
+// SourceInfo describes the downloaded source video of a job, as probed when processing begins
+type SourceInfo struct {
+	Duration  float64 `json:"duration"`            // Seconds, of the clip when startTime/endTime are set
+	SizeBytes int64   `json:"sizeBytes"`           // Size of the downloaded file
+	Rotation  int     `json:"rotation,omitempty"`  // Display rotation in degrees clockwise
+	Chaptered bool    `json:"chaptered,omitempty"` // Processed in chapters (CHAPTER_DURATION)
+}
+
+// InspectRequest asks to probe a video before submitting a job for it
+type InspectRequest struct {
+	VideoURL string `json:"videoUrl"`
+	// ServiceAccount reads the video as this service account, as for jobs (defaults to the API key's)
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+}
+
+// InspectProblem is a reason a translation job would reject the video
+type InspectProblem struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// InspectResponse reports a probed video and whether it passes the configured limits
+type InspectResponse struct {
+	VideoURL  string           `json:"videoUrl"`
+	SizeBytes int64            `json:"sizeBytes"`
+	Media     *MediaInfo       `json:"media,omitempty"` // Nil when the video was too large to download for probing
+	Valid     bool             `json:"valid"`
+	Problems  []InspectProblem `json:"problems,omitempty"`
+}
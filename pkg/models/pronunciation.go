@@ -0,0 +1,10 @@
+package models
+
+// Pronunciation overrides how TTS speaks a word (e.g., brand names and proper nouns)
+// Exactly one of Phoneme or Alias must be set.
+type Pronunciation struct {
+	Word    string `json:"word"`              // Word or phrase as it appears in the translated text
+	Phoneme string `json:"phoneme,omitempty"` // IPA transcription, spoken via <phoneme alphabet="ipa">
+	Alias   string `json:"alias,omitempty"`   // Replacement text, spoken via <sub alias>
+	SSML    string `json:"ssml,omitempty"`    // SSML fragment spoken instead (e.g., <say-as>), sanitized to the allowed elements
+}
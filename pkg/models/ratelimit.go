@@ -0,0 +1,8 @@
+package models
+
+// RateLimitTier sets the request rates and concurrent jobs of the API keys assigned to it
+type RateLimitTier struct {
+	SubmitRPM         int // Job submissions per minute
+	StatusRPM         int // Status polling and job listing requests per minute
+	MaxConcurrentJobs int // Jobs processing at once; 0 for unlimited
+}
@@ -1,10 +1,113 @@
 package models
 
+import (
+	"fmt"
+	"strings"
+)
+
 // TranslateRequest represents the request body for video translation
 type TranslateRequest struct {
-	VideoURL        string   `json:"videoUrl"`                 // GCS URL or HTTPS URL of the video
-	TargetLanguages []string `json:"targetLanguages"`          // Languages to translate to (e.g., ["en", "ar", "de"])
-	SourceLanguage  string   `json:"sourceLanguage,omitempty"` // Optional source language hint (empty for auto-detect)
+	VideoURL           string                     `json:"videoUrl"`                     // GCS URL or HTTPS URL of the video
+	TargetLanguages    []string                   `json:"targetLanguages"`              // Languages to translate to (e.g., ["en", "ar", "de"], or ["*"] for all)
+	AllLanguages       bool                       `json:"allLanguages,omitempty"`       // Translate to every supported language except the source
+	SourceLanguage     string                     `json:"sourceLanguage,omitempty"`     // Optional source language hint (empty for auto-detect)
+	NotifyEmail        string                     `json:"notifyEmail,omitempty"`        // Optional email address notified on job completion/failure
+	Preset             string                     `json:"preset,omitempty"`             // Optional output preset (e.g., "accessibility")
+	ProfanityFilter    bool                       `json:"profanityFilter,omitempty"`    // Mask profanity in the transcript before translation
+	StyleInstructions  string                     `json:"styleInstructions,omitempty"`  // Tone/register guidance for LLM translation (e.g., "formal tone")
+	Pronunciations     map[string][]Pronunciation `json:"pronunciations,omitempty"`     // TTS pronunciation overrides keyed by target language
+	StartTime          float64                    `json:"startTime,omitempty"`          // Optional clip start in seconds
+	EndTime            float64                    `json:"endTime,omitempty"`            // Optional clip end in seconds (0 for the end of the video)
+	SourceAudioTrack   *int                       `json:"sourceAudioTrack,omitempty"`   // Optional audio stream to transcribe (0-based among audio streams)
+	SubtitleURL        string                     `json:"subtitleUrl,omitempty"`        // Optional SRT or WebVTT source subtitles used instead of speech-to-text
+	SourceText         string                     `json:"sourceText,omitempty"`         // Optional verified source transcript used instead of speech-to-text
+	Narration          bool                       `json:"narration,omitempty"`          // Accept videos without audio, voicing sourceText or subtitleUrl as narration
+	Transcription      *TranscriptionOptions      `json:"transcription,omitempty"`      // Optional speech recognition overrides
+	OutputDestinations map[string]string          `json:"outputDestinations,omitempty"` // Per-language output location (gs://bucket[/prefix])
+	WebhookEvents      []string                   `json:"webhookEvents,omitempty"`      // Webhook events to deliver for this job, overriding WEBHOOK_EVENTS
+	Tags               []string                   `json:"tags,omitempty"`               // Labels stored with the job and filterable on GET /v1/jobs
+	Metadata           map[string]string          `json:"metadata,omitempty"`           // Free-form key/value pairs echoed in status and notifications
+
+	// KeepBackgroundMusic mixes the separated music bed under the dubbed voice; nil uses KEEP_BACKGROUND_MUSIC
+	KeepBackgroundMusic *bool `json:"keepBackgroundMusic,omitempty"`
+	// Branding overrides the deployment's watermark and intro/outro clips (WATERMARK_URL, INTRO_URL, OUTRO_URL)
+	Branding *BrandingOptions `json:"branding,omitempty"`
+	// JobID is an optional client-chosen job ID (UUID or slug); resubmitting an identical request returns the job
+	JobID string `json:"jobId,omitempty"`
+	// OutputAudio overrides the sample rate, bitrate and channel layout of the dubbed audio (OUTPUT_AUDIO_*)
+	OutputAudio *OutputAudioOptions `json:"outputAudio,omitempty"`
+	// DubbedAudio uploads the dubbed speech of each language on its own (mp3, wav or none); empty uses DUBBED_AUDIO_FORMAT
+	DubbedAudio string `json:"dubbedAudio,omitempty"`
+	// ServiceAccount is impersonated to read the source video and subtitles; empty uses the API key's service account
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// VoiceID dubs every language in a brand voice: "elevenlabs:<voice ID>" or "google:<Custom Voice model>@<locale>"
+	VoiceID string `json:"voiceId,omitempty"`
+	// VoiceGender picks a male or female default voice, or "match" for the estimated gender of the original speaker
+	VoiceGender string `json:"voiceGender,omitempty"`
+	// OutputBucket receives the job's outputs instead of the deployment's bucket; must be allowed to the API key owner in ALLOWED_OUTPUT_BUCKETS
+	OutputBucket string `json:"outputBucket,omitempty"`
+	// SubtitleProfile lays out subtitles to a profile's line length and reading speed; empty uses SUBTITLE_PROFILE
+	SubtitleProfile string `json:"subtitleProfile,omitempty"`
+	// SubtitleOffset shifts subtitle timings by seconds (negative is earlier); nil uses SUBTITLE_OFFSET
+	SubtitleOffset *float64 `json:"subtitleOffset,omitempty"`
+	// Reprocess processes the video even when an identical job completed within RESULT_REUSE_WINDOW
+	Reprocess bool `json:"reprocess,omitempty"`
+	// Summary generates a short summary of the video in each target language, for catalog descriptions
+	Summary bool `json:"summary,omitempty"`
+	// Analysis extracts keywords and chapter markers from the transcript of each target language
+	Analysis bool `json:"analysis,omitempty"`
+	// Tenant is the API key owner whose namespace and bucket receive the outputs; set server-side, never by clients
+	Tenant string `json:"-"`
+}
+
+// AllLanguagesWildcard as the only target language requests every supported language
+const AllLanguagesWildcard = "*"
+
+// Voice genders accepted by voiceGender
+const (
+	VoiceGenderMatch  = "match" // The estimated gender of the original speaker
+	VoiceGenderMale   = "male"
+	VoiceGenderFemale = "female"
+)
+
+// IsValidVoiceGender reports whether gender is empty (default voices) or a supported voiceGender
+func IsValidVoiceGender(gender string) bool {
+	switch gender {
+	case "", VoiceGenderMatch, VoiceGenderMale, VoiceGenderFemale:
+		return true
+	default:
+		return false
+	}
+}
+
+// WantsAllLanguages reports whether the request targets every supported language
+func (r *TranslateRequest) WantsAllLanguages() bool {
+	return r.AllLanguages || (len(r.TargetLanguages) == 1 && r.TargetLanguages[0] == AllLanguagesWildcard)
+}
+
+// IsClip reports whether only a time range of the video should be processed
+func (r *TranslateRequest) IsClip() bool {
+	return r.StartTime > 0 || r.EndTime > 0
+}
+
+// SourceKey identifies the processed media: the video URL plus media fragments
+// for the clip range (t=start,end) and audio track (track=N) when set
+func (r *TranslateRequest) SourceKey() string {
+	var fragments []string
+	if r.IsClip() {
+		fragment := fmt.Sprintf("t=%g", r.StartTime)
+		if r.EndTime > 0 {
+			fragment += fmt.Sprintf(",%g", r.EndTime)
+		}
+		fragments = append(fragments, fragment)
+	}
+	if r.SourceAudioTrack != nil {
+		fragments = append(fragments, fmt.Sprintf("track=%d", *r.SourceAudioTrack))
+	}
+	if len(fragments) == 0 {
+		return r.VideoURL
+	}
+	return r.VideoURL + "#" + strings.Join(fragments, "&")
 }
 
 // Validate performs basic validation on the request
@@ -13,7 +116,7 @@ func (r *TranslateRequest) Validate() error {
 		return ErrMissingVideoURL
 	}
 
-	if len(r.TargetLanguages) == 0 {
+	if len(r.TargetLanguages) == 0 && !r.AllLanguages {
 		return ErrMissingTargetLanguages
 	}
 
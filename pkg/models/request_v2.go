@@ -0,0 +1,194 @@
+package models
+
+// API versions of the translate endpoint; v1 is frozen and new request fields are only added to v2
+const (
+	APIVersionV1 = "v1"
+	APIVersionV2 = "v2"
+)
+
+// SupportedAPIVersions lists the request schemas accepted on /{version}/translate
+var SupportedAPIVersions = []string{APIVersionV1, APIVersionV2}
+
+// Audio modes of a v2 request
+const (
+	// AudioModeDub replaces the original audio with the dubbed voice only
+	AudioModeDub = "dub"
+	// AudioModeMix mixes the separated background music and effects under the dubbed voice
+	AudioModeMix = "mix"
+)
+
+// TranslateRequestV2 is the request body of /v2/translate
+// It groups the v1 fields by concern; Request converts it to the TranslateRequest the pipeline runs.
+type TranslateRequestV2 struct {
+	Source    SourceV2           `json:"source"`              // Video to dub and how its speech is obtained
+	Targets   []string           `json:"targets"`             // Languages to translate to, or ["*"] for every supported language
+	Outputs   *OutputsV2         `json:"outputs,omitempty"`   // Where and in which formats results are written
+	Voice     *VoiceSelectionV2  `json:"voice,omitempty"`     // Voice used for every target language
+	Voices    map[string]VoiceV2 `json:"voices,omitempty"`    // Speech synthesis settings keyed by target language
+	AudioMode string             `json:"audioMode,omitempty"` // dub or mix; empty uses KEEP_BACKGROUND_MUSIC
+	Subtitles *SubtitlesV2       `json:"subtitles,omitempty"` // Source subtitles used instead of speech-to-text
+	Metadata  map[string]string  `json:"metadata,omitempty"`  // Free-form key/value pairs echoed in status and notifications
+	Tags      []string           `json:"tags,omitempty"`      // Labels stored with the job and filterable on GET /v1/jobs
+	JobID     string             `json:"jobId,omitempty"`     // Optional client-chosen job ID
+	Reprocess bool               `json:"reprocess,omitempty"` // Process the video even when an identical job completed recently
+
+	Translation *TranslationV2   `json:"translation,omitempty"` // Translation and moderation settings
+	Notify      *NotifyV2        `json:"notify,omitempty"`      // Per-job notification settings
+	Branding    *BrandingOptions `json:"branding,omitempty"`    // Watermark and intro/outro overrides
+}
+
+// SourceV2 describes the source video of a v2 request
+type SourceV2 struct {
+	URL           string                `json:"url"`                     // GCS URL or HTTPS URL of the video
+	Language      string                `json:"language,omitempty"`      // Optional source language hint (empty for auto-detect)
+	StartTime     float64               `json:"startTime,omitempty"`     // Optional clip start in seconds
+	EndTime       float64               `json:"endTime,omitempty"`       // Optional clip end in seconds (0 for the end of the video)
+	AudioTrack    *int                  `json:"audioTrack,omitempty"`    // Optional audio stream to transcribe (0-based among audio streams)
+	Text          string                `json:"text,omitempty"`          // Optional verified transcript used instead of speech-to-text
+	Narration     bool                  `json:"narration,omitempty"`     // Accept videos without audio, voicing the supplied text or subtitles
+	Transcription *TranscriptionOptions `json:"transcription,omitempty"` // Optional speech recognition overrides
+
+	ServiceAccount string `json:"serviceAccount,omitempty"` // Service account impersonated to read the source
+}
+
+// OutputsV2 selects the output location and formats of a v2 request
+type OutputsV2 struct {
+	Preset       string              `json:"preset,omitempty"`       // Optional output preset (e.g., "accessibility")
+	Destinations map[string]string   `json:"destinations,omitempty"` // Per-language output location (gs://bucket[/prefix])
+	Audio        *OutputAudioOptions `json:"audio,omitempty"`        // Sample rate, bitrate and channel layout of the dubbed audio
+	DubbedAudio  string              `json:"dubbedAudio,omitempty"`  // Also upload the dubbed speech on its own (mp3, wav or none)
+	Bucket       string              `json:"bucket,omitempty"`       // Bucket receiving the outputs instead of the deployment's bucket
+	Summary      bool                `json:"summary,omitempty"`      // Generate a short summary in each target language
+	Analysis     bool                `json:"analysis,omitempty"`     // Extract keywords and chapter markers in each target language
+
+	SubtitleProfile string   `json:"subtitleProfile,omitempty"` // Line length and reading speed profile of the subtitles
+	SubtitleOffset  *float64 `json:"subtitleOffset,omitempty"`  // Shift of subtitle timings in seconds (negative is earlier)
+}
+
+// VoiceSelectionV2 selects the voice of every target language of a v2 request
+type VoiceSelectionV2 struct {
+	ID     string `json:"id,omitempty"`     // Brand voice: "elevenlabs:<voice ID>" or "google:<Custom Voice model>@<locale>"
+	Gender string `json:"gender,omitempty"` // male, female, or match for the original speaker's estimated gender
+}
+
+// VoiceV2 holds the speech synthesis settings of one target language
+type VoiceV2 struct {
+	Pronunciations []Pronunciation `json:"pronunciations,omitempty"` // Pronunciation overrides for the language
+}
+
+// SubtitlesV2 points at source subtitles of a v2 request
+type SubtitlesV2 struct {
+	URL string `json:"url"` // SRT or WebVTT file
+}
+
+// TranslationV2 holds the translation settings of a v2 request
+type TranslationV2 struct {
+	StyleInstructions string `json:"styleInstructions,omitempty"` // Tone/register guidance for LLM translation
+	ProfanityFilter   bool   `json:"profanityFilter,omitempty"`   // Mask profanity in the transcript before translation
+}
+
+// NotifyV2 holds the per-job notification settings of a v2 request
+type NotifyV2 struct {
+	Email         string   `json:"email,omitempty"`         // Address notified on job completion/failure
+	WebhookEvents []string `json:"webhookEvents,omitempty"` // Webhook events to deliver, overriding WEBHOOK_EVENTS
+}
+
+// Validate performs basic validation on the request
+func (r *TranslateRequestV2) Validate() error {
+	if r.Source.URL == "" {
+		return ErrMissingSourceURL
+	}
+
+	if len(r.Targets) == 0 {
+		return ErrMissingTargets
+	}
+
+	switch r.AudioMode {
+	case "", AudioModeDub, AudioModeMix:
+	default:
+		return ErrInvalidAudioMode
+	}
+
+	if r.Subtitles != nil && r.Subtitles.URL == "" {
+		return ErrMissingSubtitlesURL
+	}
+
+	return nil
+}
+
+// Request converts the v2 request to the TranslateRequest processed by the pipeline
+func (r *TranslateRequestV2) Request() *TranslateRequest {
+	req := &TranslateRequest{
+		VideoURL:         r.Source.URL,
+		TargetLanguages:  r.Targets,
+		SourceLanguage:   r.Source.Language,
+		StartTime:        r.Source.StartTime,
+		EndTime:          r.Source.EndTime,
+		SourceAudioTrack: r.Source.AudioTrack,
+		SourceText:       r.Source.Text,
+		Narration:        r.Source.Narration,
+		Transcription:    r.Source.Transcription,
+		ServiceAccount:   r.Source.ServiceAccount,
+		Tags:             r.Tags,
+		Metadata:         r.Metadata,
+		Branding:         r.Branding,
+		JobID:            r.JobID,
+		Reprocess:        r.Reprocess,
+	}
+
+	if r.Outputs != nil {
+		req.Preset = r.Outputs.Preset
+		req.OutputDestinations = r.Outputs.Destinations
+		req.OutputAudio = r.Outputs.Audio
+		req.DubbedAudio = r.Outputs.DubbedAudio
+		req.OutputBucket = r.Outputs.Bucket
+		req.Summary = r.Outputs.Summary
+		req.Analysis = r.Outputs.Analysis
+		req.SubtitleProfile = r.Outputs.SubtitleProfile
+		req.SubtitleOffset = r.Outputs.SubtitleOffset
+	}
+
+	if r.Voice != nil {
+		req.VoiceID = r.Voice.ID
+		req.VoiceGender = r.Voice.Gender
+	}
+
+	for language, voice := range r.Voices {
+		if len(voice.Pronunciations) == 0 {
+			continue
+		}
+		if req.Pronunciations == nil {
+			req.Pronunciations = make(map[string][]Pronunciation)
+		}
+		req.Pronunciations[language] = voice.Pronunciations
+	}
+
+	if r.AudioMode != "" {
+		keep := r.AudioMode == AudioModeMix
+		req.KeepBackgroundMusic = &keep
+	}
+
+	if r.Subtitles != nil {
+		req.SubtitleURL = r.Subtitles.URL
+	}
+
+	if r.Translation != nil {
+		req.StyleInstructions = r.Translation.StyleInstructions
+		req.ProfanityFilter = r.Translation.ProfanityFilter
+	}
+
+	if r.Notify != nil {
+		req.NotifyEmail = r.Notify.Email
+		req.WebhookEvents = r.Notify.WebhookEvents
+	}
+
+	return req
+}
+
+// Validation errors of v2 requests
+var (
+	ErrMissingSourceURL    = &ValidationError{Message: "source.url is required"}
+	ErrMissingTargets      = &ValidationError{Message: "at least one target language is required"}
+	ErrInvalidAudioMode    = &ValidationError{Message: "audioMode must be dub or mix"}
+	ErrMissingSubtitlesURL = &ValidationError{Message: "subtitles.url is required"}
+)
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestTranslateRequestV2_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     TranslateRequestV2
+		wantErr error
+	}{
+		{
+			name: "valid",
+			req:  TranslateRequestV2{Source: SourceV2{URL: "gs://bucket/video.mp4"}, Targets: []string{"de"}},
+		},
+		{
+			name:    "missing source URL",
+			req:     TranslateRequestV2{Targets: []string{"de"}},
+			wantErr: ErrMissingSourceURL,
+		},
+		{
+			name:    "missing targets",
+			req:     TranslateRequestV2{Source: SourceV2{URL: "gs://bucket/video.mp4"}},
+			wantErr: ErrMissingTargets,
+		},
+		{
+			name: "invalid audio mode",
+			req: TranslateRequestV2{
+				Source:    SourceV2{URL: "gs://bucket/video.mp4"},
+				Targets:   []string{"de"},
+				AudioMode: "karaoke",
+			},
+			wantErr: ErrInvalidAudioMode,
+		},
+		{
+			name: "subtitles without URL",
+			req: TranslateRequestV2{
+				Source:    SourceV2{URL: "gs://bucket/video.mp4"},
+				Targets:   []string{"de"},
+				Subtitles: &SubtitlesV2{},
+			},
+			wantErr: ErrMissingSubtitlesURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTranslateRequestV2_Request(t *testing.T) {
+	body := `{
+		"source": {"url": "gs://bucket/video.mp4", "language": "fr", "startTime": 10, "endTime": 70, "audioTrack": 1, "serviceAccount": "reader@project.iam.gserviceaccount.com"},
+		"targets": ["en", "de"],
+		"outputs": {
+			"preset": "accessibility",
+			"destinations": {"de": "gs://de-bucket/dubs"},
+			"dubbedAudio": "mp3",
+			"bucket": "tenant-bucket",
+			"subtitleProfile": "mobile",
+			"subtitleOffset": -0.5,
+			"summary": true,
+			"analysis": true
+		},
+		"voice": {"id": "elevenlabs:brand", "gender": "female"},
+		"voices": {"de": {"pronunciations": [{"word": "Acme", "alias": "Akme"}]}, "en": {}},
+		"audioMode": "mix",
+		"subtitles": {"url": "gs://bucket/video.srt"},
+		"translation": {"styleInstructions": "formal", "profanityFilter": true},
+		"notify": {"email": "team@example.com", "webhookEvents": ["job.completed"]},
+		"metadata": {"campaign": "spring"},
+		"tags": ["promo"],
+		"jobId": "spring-promo",
+		"reprocess": true
+	}`
+
+	var v2 TranslateRequestV2
+	if err := json.Unmarshal([]byte(body), &v2); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	req := v2.Request()
+
+	if req.VideoURL != "gs://bucket/video.mp4" || req.SourceLanguage != "fr" {
+		t.Errorf("unexpected source: %q %q", req.VideoURL, req.SourceLanguage)
+	}
+	if req.StartTime != 10 || req.EndTime != 70 || req.SourceAudioTrack == nil || *req.SourceAudioTrack != 1 {
+		t.Errorf("unexpected clip: %v-%v track %v", req.StartTime, req.EndTime, req.SourceAudioTrack)
+	}
+	if req.ServiceAccount != "reader@project.iam.gserviceaccount.com" {
+		t.Errorf("unexpected service account %q", req.ServiceAccount)
+	}
+	if len(req.TargetLanguages) != 2 || req.TargetLanguages[1] != "de" {
+		t.Errorf("unexpected targets %v", req.TargetLanguages)
+	}
+	if req.Preset != "accessibility" || req.OutputDestinations["de"] != "gs://de-bucket/dubs" || req.DubbedAudio != "mp3" {
+		t.Errorf("unexpected outputs: %q %v %q", req.Preset, req.OutputDestinations, req.DubbedAudio)
+	}
+	if req.OutputBucket != "tenant-bucket" {
+		t.Errorf("expected output bucket, got %q", req.OutputBucket)
+	}
+	if req.SubtitleProfile != "mobile" || req.SubtitleOffset == nil || *req.SubtitleOffset != -0.5 {
+		t.Errorf("unexpected subtitle layout: %q %v", req.SubtitleProfile, req.SubtitleOffset)
+	}
+	if !req.Summary || !req.Analysis {
+		t.Errorf("expected summary and analysis, got %v %v", req.Summary, req.Analysis)
+	}
+	if req.VoiceID != "elevenlabs:brand" || req.VoiceGender != VoiceGenderFemale {
+		t.Errorf("unexpected voice: %q %q", req.VoiceID, req.VoiceGender)
+	}
+	if len(req.Pronunciations) != 1 || len(req.Pronunciations["de"]) != 1 {
+		t.Errorf("expected pronunciations for de only, got %v", req.Pronunciations)
+	}
+	if req.KeepBackgroundMusic == nil || !*req.KeepBackgroundMusic {
+		t.Errorf("expected mix to keep background music, got %v", req.KeepBackgroundMusic)
+	}
+	if req.SubtitleURL != "gs://bucket/video.srt" {
+		t.Errorf("unexpected subtitle URL %q", req.SubtitleURL)
+	}
+	if req.StyleInstructions != "formal" || !req.ProfanityFilter {
+		t.Errorf("unexpected translation settings: %q %v", req.StyleInstructions, req.ProfanityFilter)
+	}
+	if req.NotifyEmail != "team@example.com" || len(req.WebhookEvents) != 1 {
+		t.Errorf("unexpected notify settings: %q %v", req.NotifyEmail, req.WebhookEvents)
+	}
+	if req.Metadata["campaign"] != "spring" || len(req.Tags) != 1 || req.JobID != "spring-promo" || !req.Reprocess {
+		t.Errorf("unexpected job fields: %v %v %q %v", req.Metadata, req.Tags, req.JobID, req.Reprocess)
+	}
+}
+
+func TestTranslateRequestV2_RequestDefaults(t *testing.T) {
+	v2 := TranslateRequestV2{Source: SourceV2{URL: "gs://bucket/video.mp4"}, Targets: []string{"de"}, AudioMode: AudioModeDub}
+	req := v2.Request()
+
+	if req.KeepBackgroundMusic == nil || *req.KeepBackgroundMusic {
+		t.Errorf("expected dub to drop background music, got %v", req.KeepBackgroundMusic)
+	}
+	if req.OutputBucket != "" || req.VoiceID != "" || req.SubtitleOffset != nil || req.Pronunciations != nil {
+		t.Errorf("expected unset options to stay empty, got %+v", req)
+	}
+
+	v2.AudioMode = ""
+	if req := v2.Request(); req.KeepBackgroundMusic != nil {
+		t.Errorf("expected no audio mode to use the deployment default, got %v", *req.KeepBackgroundMusic)
+	}
+}
@@ -7,9 +7,42 @@ type TranslationStatus string
 
 const (
 	StatusIdle       TranslationStatus = "idle"
+	StatusPending    TranslationStatus = "pending" // A target language not started yet
 	StatusProcessing TranslationStatus = "processing"
 	StatusCompleted  TranslationStatus = "completed"
 	StatusFailed     TranslationStatus = "failed"
+
+	// StatusPartiallyCompleted is terminal: some languages completed and the others failed
+	StatusPartiallyCompleted TranslationStatus = "partially_completed"
+
+	// StatusSkipped marks a target language that matches the source language under SAME_LANGUAGE_POLICY=skip
+	StatusSkipped TranslationStatus = "skipped"
+)
+
+// Job error codes reported in StatusResponse.ErrorCode
+const (
+	ErrCodeNoSpeech = "ERR_NO_SPEECH"
+	ErrCodeNoAudio  = "ERR_NO_AUDIO"
+
+	// ErrCodeIntegrity marks a download or upload whose data did not match the GCS object checksums
+	ErrCodeIntegrity = "ERR_INTEGRITY"
+
+	// ErrCodeTranscriptTooLong marks a source text longer than MAX_TRANSCRIPT_CHARS under the fail policy
+	ErrCodeTranscriptTooLong = "ERR_TRANSCRIPT_TOO_LONG"
+
+	// ErrCodeInsufficientDisk marks a job whose video would not fit in the free temp disk space
+	ErrCodeInsufficientDisk = "ERR_INSUFFICIENT_DISK"
+
+	// HTTPS sources that fail the HEAD check run before the job downloads anything
+	ErrCodeSourceUnreachable = "ERR_SOURCE_UNREACHABLE" // The URL did not answer with a 2xx response
+	ErrCodeNotVideo          = "ERR_NOT_VIDEO"          // The URL is not served with a video/* content type
+	ErrCodeSourceTooLarge    = "ERR_SOURCE_TOO_LARGE"   // The reported size exceeds MAX_VIDEO_SIZE_MB
+
+	// ErrCodeStalled marks a job that stopped making progress, e.g. because the instance running it died
+	ErrCodeStalled = "ERR_STALLED"
+
+	// ErrCodeBudgetExceeded rejects a job submitted once provider usage reached the SPEND_BUDGET_* limits
+	ErrCodeBudgetExceeded = "ERR_BUDGET_EXCEEDED"
 )
 
 // TranslateResponse represents the response from the translation API
@@ -18,37 +51,234 @@ type TranslateResponse struct {
 	Status  TranslationStatus          `json:"status"`
 	Results map[string]*LanguageResult `json:"results,omitempty"`
 	Error   string                     `json:"error,omitempty"`
+
+	// Languages lists the target languages in the requested order, pending at submission
+	Languages []LanguageEntry `json:"languages,omitempty"`
+
+	// ReusedFrom is the ID of the completed job whose results an identical submission reused
+	ReusedFrom string `json:"reusedFrom,omitempty"`
 }
 
 // LanguageResult represents the result for a single target language
 type LanguageResult struct {
-	Status         TranslationStatus `json:"status"`
-	VideoURL       string            `json:"videoUrl,omitempty"`
-	TranslatedText string            `json:"translatedText,omitempty"`
-	Progress       int               `json:"progress,omitempty"` // 0-100
-	Error          string            `json:"error,omitempty"`
-	ProcessedAt    *time.Time        `json:"processedAt,omitempty"`
+	Status            TranslationStatus `json:"status"`
+	VideoURL          string            `json:"videoUrl,omitempty"`
+	TranscriptURL     string            `json:"transcriptUrl,omitempty"`     // Source-language transcript shared by all languages
+	TranslatedTextURL string            `json:"translatedTextUrl,omitempty"` // Translated text for this language
+	SubtitlesURL      string            `json:"subtitlesUrl,omitempty"`      // WebVTT subtitles with estimated timings
+	TranslatedText    string            `json:"translatedText,omitempty"`
+	Progress          int               `json:"progress,omitempty"`       // 0-100
+	ReusedSegments    int               `json:"reusedSegments,omitempty"` // Repeated sentences served from the job's translation memory, or from earlier jobs with TRANSLATION_MEMORY_BACKEND
+	Artifacts         map[string]string `json:"artifacts,omitempty"`      // Additional outputs by kind (captions, transcript, audio)
+	Error             string            `json:"error,omitempty"`
+	ErrorCode         string            `json:"errorCode,omitempty"` // Machine-readable failure reason, when known
+	ProcessedAt       *time.Time        `json:"processedAt,omitempty"`
+
+	// Transient is set when the failure was caused by a quota or server-side provider error
+	Transient bool `json:"-"`
+
+	// DurationDrift is how many seconds longer (positive) or shorter (negative) the dubbed audio is than the video,
+	// after any correction; unset when duration verification is disabled
+	DurationDrift *float64 `json:"durationDrift,omitempty"`
+
+	// DurationCorrection lists the corrections applied to the dubbed audio duration (resynthesize, atempo)
+	DurationCorrection []string `json:"durationCorrection,omitempty"`
+
+	// Passthrough is set when the target language matches the source language and the source video
+	// and transcript were published without translation or dubbing
+	Passthrough bool `json:"passthrough,omitempty"`
+
+	// AudioURL links the dubbed speech uploaded on its own (dubbedAudio or DUBBED_AUDIO_FORMAT)
+	AudioURL string `json:"audioUrl,omitempty"`
+
+	// Timings records where the language spent its time
+	Timings *LanguageTimings `json:"timings,omitempty"`
+
+	// Direction is the text direction of the language (ltr or rtl), so clients render its text and subtitles correctly
+	Direction string `json:"direction,omitempty"`
+
+	// Summary is a short summary of the video in the language (summary option), also uploaded as SummaryURL
+	Summary    string `json:"summary,omitempty"`
+	SummaryURL string `json:"summaryUrl,omitempty"`
+
+	// Analysis holds the keywords and chapter markers of the language (analysis option), also uploaded as JSON
+	// (AnalysisURL) and YouTube-style chapter lines (ChaptersURL)
+	Analysis    *VideoAnalysis `json:"analysis,omitempty"`
+	AnalysisURL string         `json:"analysisUrl,omitempty"`
+	ChaptersURL string         `json:"chaptersUrl,omitempty"`
+
+	// Replicas reports the copy of the outputs to each REPLICA_DESTINATIONS destination, keyed by destination URL
+	Replicas map[string]*ReplicaResult `json:"replicas,omitempty"`
+
+	// OutputObjects lists the uploaded outputs of the language by path relative to its destination, for replication
+	OutputObjects []string `json:"-"`
+}
+
+// LanguageEntry is the result of one target language, listed in the requested order by StatusResponse.Languages
+type LanguageEntry struct {
+	Language string `json:"language"`
+	*LanguageResult
+}
+
+// ReplicaResult is the replication status of a language's outputs in one replica destination
+type ReplicaResult struct {
+	Status   TranslationStatus `json:"status"`             // processing, completed or failed
+	VideoURL string            `json:"videoUrl,omitempty"` // Replicated video, once completed
+	Error    string            `json:"error,omitempty"`
+}
+
+// IsFinished reports whether the language reached a terminal status (completed, failed or skipped)
+func (r *LanguageResult) IsFinished() bool {
+	return r.Status == StatusCompleted || r.Status == StatusFailed || r.Status == StatusSkipped
+}
+
+// PendingResults returns a pending result for each target language, the results of a job not started yet
+func PendingResults(languages []string) map[string]*LanguageResult {
+	results := make(map[string]*LanguageResult, len(languages))
+	for _, lang := range languages {
+		results[lang] = &LanguageResult{Status: StatusPending, Direction: LanguageDirection(lang)}
+	}
+	return results
 }
 
 // StatusResponse represents the response from the status endpoint
 type StatusResponse struct {
-	JobID     string                     `json:"jobId"`
-	Status    TranslationStatus          `json:"status"`
-	Results   map[string]*LanguageResult `json:"results,omitempty"`
-	CreatedAt *time.Time                 `json:"createdAt,omitempty"`
-	UpdatedAt time.Time                  `json:"updatedAt,omitempty"`
+	JobID       string                     `json:"jobId"`
+	Status      TranslationStatus          `json:"status"`
+	Results     map[string]*LanguageResult `json:"results,omitempty"`
+	CreatedAt   *time.Time                 `json:"createdAt,omitempty"`
+	UpdatedAt   time.Time                  `json:"updatedAt,omitempty"`
+	ManifestURL string                     `json:"manifestUrl,omitempty"`
+
+	// Languages lists every requested target language in request order, including the ones without a result yet
+	Languages []LanguageEntry `json:"languages"`
+
+	// ErrorCode is a machine-readable reason for failed jobs (e.g., ERR_NO_SPEECH), when known
+	ErrorCode string `json:"errorCode,omitempty"`
+
+	// Stage is the current pipeline stage while processing, or the stage a failed job stopped at
+	Stage string `json:"stage,omitempty"`
+
+	// TranscriptConfidence is the average speech recognition confidence (0-1), omitted when unavailable
+	TranscriptConfidence float64  `json:"transcriptConfidence,omitempty"`
+	Warnings             []string `json:"warnings,omitempty"`
+
+	// Tags and Metadata are copied from the request to correlate the job with upstream systems
+	Tags     []string          `json:"tags,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// SkippedLanguages lists expanded target languages (allLanguages) dropped because they match the detected source language
+	SkippedLanguages []string `json:"skippedLanguages,omitempty"`
+
+	// RetryAttempts counts automatic retries scheduled after transient provider errors
+	RetryAttempts int        `json:"retryAttempts,omitempty"`
+	NextRetryAt   *time.Time `json:"nextRetryAt,omitempty"`
+
+	// TransientFailure is set when the job failed before language processing on a quota or server-side provider error
+	TransientFailure bool `json:"-"`
+
+	// AudioTracks lists the audio streams found in the source video
+	AudioTracks []AudioTrack `json:"audioTracks,omitempty"`
+
+	// Source describes the downloaded source video once processing began
+	Source *SourceInfo `json:"source,omitempty"`
+
+	// SpeakerGender is the gender estimated from the original speaker's pitch when voiceGender is "match"
+	SpeakerGender string `json:"speakerGender,omitempty"`
+
+	// PartialTranscript lists the transcript segments recognized so far while the job is transcribing, in video
+	// order (one per chapter for chaptered jobs); dropped once the complete transcript is available
+	PartialTranscript []TranscriptSegment `json:"partialTranscript,omitempty"`
+
+	// RedactedTerms lists the masked form of terms removed by the profanity filter
+	RedactedTerms []string `json:"redactedTerms,omitempty"`
+
+	// Timings records the wall-clock time of the job-wide stages; per-language timings are on each result
+	Timings *JobTimings `json:"timings,omitempty"`
+
+	// Request is the original submission, kept server-side for notifications and retries
+	Request *TranslateRequest `json:"-"`
+
+	// Checkpoint is set once transcription succeeds so failed languages can be retried
+	Checkpoint *JobCheckpoint `json:"-"`
+
+	// Owner identifies the API key owner that submitted the job (empty when auth is disabled)
+	Owner string `json:"-"`
+
+	// Events is the job's event log, included in archival exports
+	Events []JobEvent `json:"-"`
+
+	// Usage lists billable units and the estimated cost once the job finishes
+	Usage *JobUsage `json:"usage,omitempty"`
+
+	// PreviewURL links the job's HTML results page with a player per language (PREVIEW_PAGE)
+	PreviewURL string `json:"previewUrl,omitempty"`
+
+	// Meter accumulates usage while the job runs, including retries
+	Meter *UsageMeter `json:"-"`
+
+	// Progress is the overall completion percentage (0-100), covering the job-wide stages and every language
+	Progress int `json:"progress"`
+
+	// LanguageProgress holds the progress of languages still being processed, which have no result yet
+	LanguageProgress map[string]int `json:"-"`
+
+	// ReusedFrom is the ID of the identical completed job whose results this job reused (RESULT_REUSE_WINDOW)
+	ReusedFrom string `json:"reusedFrom,omitempty"`
+
+	// ContentKey identifies the source content and output options, to index the job once completed for reuse
+	ContentKey string `json:"-"`
+}
+
+// TargetLanguages returns the requested target languages that are processed, excluding skipped ones
+func (s *StatusResponse) TargetLanguages() []string {
+	if s.Request == nil {
+		return nil
+	}
+	var targets []string
+	for _, lang := range s.Request.TargetLanguages {
+		skipped := false
+		for _, skippedLang := range s.SkippedLanguages {
+			if lang == skippedLang {
+				skipped = true
+				break
+			}
+		}
+		if !skipped {
+			targets = append(targets, lang)
+		}
+	}
+	return targets
 }
 
 // HealthResponse represents the health check response
 type HealthResponse struct {
-	Status    string `json:"status"`
-	Timestamp string `json:"timestamp"`
-	Version   string `json:"version,omitempty"`
+	Status    string     `json:"status"`
+	Timestamp string     `json:"timestamp"`
+	Version   string     `json:"version,omitempty"`
+	Build     *BuildInfo `json:"build,omitempty"`
+}
+
+// BuildInfo describes what is deployed: the build, external tool versions and enabled backends
+type BuildInfo struct {
+	GitCommit    string            `json:"gitCommit,omitempty"`
+	BuildTime    string            `json:"buildTime,omitempty"`
+	GoVersion    string            `json:"goVersion"`
+	Dependencies map[string]string `json:"dependencies"` // Tool name -> version (e.g., "ffmpeg": "6.1.1")
+	Providers    map[string]string `json:"providers"`    // Component -> provider or backend (e.g., "cache": "redis")
+}
+
+// JobListResponse represents the response from the job list endpoint
+type JobListResponse struct {
+	Jobs  []*StatusResponse `json:"jobs"`
+	Count int               `json:"count"`
 }
 
 // ErrorResponse represents an error response
 type ErrorResponse struct {
-	Error     string `json:"error"`
-	Message   string `json:"message,omitempty"`
-	RequestID string `json:"requestId,omitempty"`
+	Error     string                 `json:"error"`
+	Code      string                 `json:"code,omitempty"` // Machine-readable error code (e.g., "too_many_languages")
+	Message   string                 `json:"message,omitempty"`
+	RequestID string                 `json:"requestId,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"` // Code-specific context (limits, existing job ID)
 }
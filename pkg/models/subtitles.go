@@ -0,0 +1,47 @@
+package models
+
+import "strings"
+
+// SubtitleProfile sets the reading constraints subtitle cues are laid out to
+// Full-width (Chinese, Japanese and Korean) characters count double towards line length and reading speed.
+type SubtitleProfile struct {
+	MaxLineChars int     // Characters per line
+	MaxLines     int     // Lines per cue
+	MaxCPS       float64 // Reading speed in characters per second
+	MinDuration  float64 // Seconds a cue stays on screen at least
+}
+
+// SubtitleProfiles lists the built-in subtitle profiles; SUBTITLE_PROFILES can add or override profiles
+var SubtitleProfiles = map[string]SubtitleProfile{
+	"standard":  {MaxLineChars: 42, MaxLines: 2, MaxCPS: 17, MinDuration: 1},
+	"broadcast": {MaxLineChars: 37, MaxLines: 2, MaxCPS: 15, MinDuration: 1.5},
+	"children":  {MaxLineChars: 32, MaxLines: 2, MaxCPS: 12, MinDuration: 1.5},
+}
+
+// MaxSubtitleOffset bounds subtitleOffset, in seconds either way
+const MaxSubtitleOffset = 600
+
+// rtlLanguages lists the languages written right to left
+var rtlLanguages = map[string]bool{
+	"ar": true, "dv": true, "fa": true, "he": true, "iw": true, "ps": true, "sd": true, "ug": true, "ur": true, "yi": true,
+}
+
+// IsRTLLanguage reports whether a language code (e.g., "ar" or "ar-XA") is written right to left
+func IsRTLLanguage(language string) bool {
+	base, _, _ := strings.Cut(strings.ToLower(language), "-")
+	return rtlLanguages[base]
+}
+
+// Text directions of a language, as in the HTML dir attribute
+const (
+	DirectionLTR = "ltr"
+	DirectionRTL = "rtl"
+)
+
+// LanguageDirection returns the text direction of a language code
+func LanguageDirection(language string) string {
+	if IsRTLLanguage(language) {
+		return DirectionRTL
+	}
+	return DirectionLTR
+}
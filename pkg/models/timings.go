@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// JobTimings records the wall-clock time of the job-wide pipeline stages, in milliseconds
+type JobTimings struct {
+	DownloadMs int64 `json:"downloadMs"`
+	STTMs      int64 `json:"sttMs,omitempty"` // Audio extraction and speech recognition; unset for supplied text
+}
+
+// LanguageTimings records the wall-clock time of one target language's stages, in milliseconds
+type LanguageTimings struct {
+	TranslateMs int64 `json:"translateMs"`
+	TTSMs       int64 `json:"ttsMs"`    // Speech synthesis, including duration correction
+	MuxMs       int64 `json:"muxMs"`    // Muxing the dubbed audio and applying branding
+	UploadMs    int64 `json:"uploadMs"` // Uploading the video and artifacts
+}
+
+// ElapsedMs returns the milliseconds since start
+func ElapsedMs(start time.Time) int64 {
+	return time.Since(start).Milliseconds()
+}
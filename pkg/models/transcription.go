@@ -0,0 +1,75 @@
+package models
+
+// TranscriptionOptions overrides the speech recognition settings of the deployment for one job
+type TranscriptionOptions struct {
+	Model                string   `json:"model,omitempty"`                // Recognition model (see SupportedSTTModels)
+	AutomaticPunctuation *bool    `json:"automaticPunctuation,omitempty"` // Insert punctuation into the transcript
+	AlternativeLanguages []string `json:"alternativeLanguages,omitempty"` // Other languages the audio may be in, for auto-detection
+	AudioChannel         *int     `json:"audioChannel,omitempty"`         // 1-based channel to transcribe; 0 downmixes all channels
+	HintPhrases          []string `json:"hintPhrases,omitempty"`          // Domain vocabulary and names the audio is likely to contain
+}
+
+// SupportedSTTModels lists the Speech-to-Text recognition models accepted in configuration and requests
+var SupportedSTTModels = []string{"default", "latest_long", "latest_short", "video", "phone_call", "command_and_search"}
+
+// MaxAlternativeLanguages is the most alternative language codes Speech-to-Text accepts
+const MaxAlternativeLanguages = 3
+
+// Speech adaptation limits of Speech-to-Text for hint phrases
+const (
+	MaxHintPhrases      = 500 // Phrases per request
+	MaxHintPhraseLength = 100 // Characters per phrase
+)
+
+// IsValidSTTModel reports whether model is empty (the API default) or a supported model
+func IsValidSTTModel(model string) bool {
+	if model == "" {
+		return true
+	}
+	for _, supported := range SupportedSTTModels {
+		if model == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// Sources of a job's transcript
+const (
+	TranscriptSourceSpeech    = "speech"    // Recognized from the audio
+	TranscriptSourceSubtitles = "subtitles" // Read from the request's subtitleUrl
+	TranscriptSourceText      = "text"      // Supplied as the request's sourceText
+)
+
+// TranscriptResponse represents the response from the transcript endpoint
+type TranscriptResponse struct {
+	JobID         string              `json:"jobId"`
+	Language      string              `json:"language,omitempty"`      // Detected language, or the requested source language
+	Source        string              `json:"source"`                  // speech, subtitles or text
+	Text          string              `json:"text"`                    // Transcript as sent to translation
+	Segments      []TranscriptSegment `json:"segments,omitempty"`      // Timed segments, when the source has timings
+	Confidence    float64             `json:"confidence,omitempty"`    // Average recognition confidence (0-1) of speech transcripts
+	TranscriptURL string              `json:"transcriptUrl,omitempty"` // Plain-text transcript in the output bucket
+	Partial       bool                `json:"partial,omitempty"`       // Recognized so far while the job is still transcribing
+	Offloaded     bool                `json:"offloaded,omitempty"`     // Text and segments are only available at transcriptUrl
+}
+
+// TranscriptSegment is a timed part of the transcript
+type TranscriptSegment struct {
+	Start float64 `json:"start"` // Seconds from the start of the video
+	End   float64 `json:"end"`   // Seconds from the start of the video
+	Text  string  `json:"text"`
+}
+
+// AddPartialTranscript records a segment recognized while the job is transcribing, keeping segments in video order
+// The slice is replaced rather than appended to, so a status being encoded keeps a consistent transcript.
+func (s *StatusResponse) AddPartialTranscript(segment TranscriptSegment) {
+	segments := make([]TranscriptSegment, 0, len(s.PartialTranscript)+1)
+	i := 0
+	for i < len(s.PartialTranscript) && s.PartialTranscript[i].Start <= segment.Start {
+		i++
+	}
+	segments = append(segments, s.PartialTranscript[:i]...)
+	segments = append(segments, segment)
+	s.PartialTranscript = append(segments, s.PartialTranscript[i:]...)
+}
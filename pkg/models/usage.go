@@ -0,0 +1,129 @@
+package models
+
+import (
+	"math"
+	"sync"
+)
+
+// JobUsage lists the billable units a job consumed and their estimated cost
+type JobUsage struct {
+	STTSeconds          float64          `json:"sttSeconds"`
+	TranslateCharacters map[string]int64 `json:"translateCharacters,omitempty"` // Characters sent to each translation provider
+	TTSCharacters       int64            `json:"ttsCharacters"`                 // SSML characters synthesized (cache hits excluded)
+	StorageBytes        int64            `json:"storageBytes"`                  // Bytes downloaded from and uploaded to GCS
+	EstimatedCost       float64          `json:"estimatedCost"`
+	Currency            string           `json:"currency,omitempty"`
+}
+
+// PriceTable holds the provider prices used to estimate job cost
+type PriceTable struct {
+	Currency                 string
+	STTPerMinute             float64
+	TranslatePerMillionChars map[string]float64 // By translation provider (google, deepl, openai, anthropic)
+	TTSPerMillionChars       float64
+	StoragePerGB             float64
+}
+
+// Cost estimates the cost of the usage, rounded to 1/10000 of the currency unit
+func (p PriceTable) Cost(usage JobUsage) float64 {
+	cost := usage.STTSeconds / 60 * p.STTPerMinute
+	for provider, characters := range usage.TranslateCharacters {
+		cost += float64(characters) / 1e6 * p.TranslatePerMillionChars[provider]
+	}
+	cost += float64(usage.TTSCharacters) / 1e6 * p.TTSPerMillionChars
+	cost += float64(usage.StorageBytes) / (1 << 30) * p.StoragePerGB
+	return math.Round(cost*1e4) / 1e4
+}
+
+// UsageSink receives the usage recorded on the meters it is attached to, e.g. to track spending across jobs
+type UsageSink interface {
+	RecordUsage(usage JobUsage)
+}
+
+// UsageMeter accumulates a job's billable units across its runs; a nil meter ignores usage
+type UsageMeter struct {
+	mu    sync.Mutex
+	usage JobUsage
+	sink  UsageSink
+}
+
+// NewUsageMeter creates an empty usage meter
+func NewUsageMeter() *UsageMeter {
+	return &UsageMeter{usage: JobUsage{TranslateCharacters: make(map[string]int64)}}
+}
+
+// NewUsageMeterWithSink creates an empty usage meter that also passes the usage it records to sink
+func NewUsageMeterWithSink(sink UsageSink) *UsageMeter {
+	meter := NewUsageMeter()
+	meter.sink = sink
+	return meter
+}
+
+// forward passes usage just recorded to the meter's sink, if any
+func (m *UsageMeter) forward(usage JobUsage) {
+	if m.sink != nil {
+		m.sink.RecordUsage(usage)
+	}
+}
+
+// AddSTTSeconds records seconds of audio sent to speech recognition
+func (m *UsageMeter) AddSTTSeconds(seconds float64) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.usage.STTSeconds += seconds
+	m.mu.Unlock()
+	m.forward(JobUsage{STTSeconds: seconds})
+}
+
+// AddTranslateCharacters records characters sent to a translation provider
+func (m *UsageMeter) AddTranslateCharacters(provider string, characters int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.usage.TranslateCharacters[provider] += int64(characters)
+	m.mu.Unlock()
+	m.forward(JobUsage{TranslateCharacters: map[string]int64{provider: int64(characters)}})
+}
+
+// AddTTSCharacters records characters sent to speech synthesis
+func (m *UsageMeter) AddTTSCharacters(characters int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.usage.TTSCharacters += int64(characters)
+	m.mu.Unlock()
+	m.forward(JobUsage{TTSCharacters: int64(characters)})
+}
+
+// AddStorageBytes records bytes transferred to or from storage
+func (m *UsageMeter) AddStorageBytes(bytes int64) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.usage.StorageBytes += bytes
+	m.mu.Unlock()
+	m.forward(JobUsage{StorageBytes: bytes})
+}
+
+// Snapshot returns the usage so far with its cost estimated from prices
+func (m *UsageMeter) Snapshot(prices PriceTable) *JobUsage {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	usage := m.usage
+	usage.TranslateCharacters = make(map[string]int64, len(m.usage.TranslateCharacters))
+	for provider, characters := range m.usage.TranslateCharacters {
+		usage.TranslateCharacters[provider] = characters
+	}
+	usage.EstimatedCost = prices.Cost(usage)
+	usage.Currency = prices.Currency
+	return &usage
+}
@@ -0,0 +1,117 @@
+package fakes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// Faults configures latency and error injection for a fake server
+type Faults struct {
+	Latency     time.Duration // Delay before every response
+	FailFirst   int           // Number of initial requests answered with ErrorStatus
+	FailEvery   int           // Answer every n-th request with ErrorStatus; 0 disables
+	ErrorStatus int           // HTTP status of injected errors; 0 for 503 Service Unavailable
+}
+
+// server is the httptest server shared by the fakes, counting requests and injecting faults
+type server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	faults   Faults
+	requests int
+}
+
+// newServer starts a fake serving handler behind fault injection
+func newServer(faults Faults, handler http.HandlerFunc) *server {
+	s := &server{faults: faults}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.inject(w, r) {
+			return
+		}
+		handler(w, r)
+	}))
+	return s
+}
+
+// SetFaults replaces the fault configuration; the request count is kept
+func (s *server) SetFaults(faults Faults) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = faults
+}
+
+// Requests returns the number of requests received, failed ones included
+func (s *server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// inject delays the request and answers it with an error when a fault applies, reporting whether it did
+func (s *server) inject(w http.ResponseWriter, r *http.Request) bool {
+	s.mu.Lock()
+	s.requests++
+	n, faults := s.requests, s.faults
+	s.mu.Unlock()
+
+	if faults.Latency > 0 {
+		select {
+		case <-time.After(faults.Latency):
+		case <-r.Context().Done():
+			return true
+		}
+	}
+
+	if n <= faults.FailFirst || (faults.FailEvery > 0 && n%faults.FailEvery == 0) {
+		status := faults.ErrorStatus
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		writeError(w, status, "injected fault")
+		return true
+	}
+	return false
+}
+
+// writeJSON writes a 200 response with a JSON body
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes an error in the Google API error format
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    status,
+			"message": message,
+			"status":  errorStatus(status),
+		},
+	})
+}
+
+// errorStatus returns the canonical Google API status name for an HTTP status
+func errorStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "INVALID_ARGUMENT"
+	case http.StatusUnauthorized:
+		return "UNAUTHENTICATED"
+	case http.StatusForbidden:
+		return "PERMISSION_DENIED"
+	case http.StatusNotFound:
+		return "NOT_FOUND"
+	case http.StatusTooManyRequests:
+		return "RESOURCE_EXHAUSTED"
+	case http.StatusServiceUnavailable:
+		return "UNAVAILABLE"
+	default:
+		return "INTERNAL"
+	}
+}
@@ -0,0 +1,43 @@
+package fakes
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestServer_FaultInjection(t *testing.T) {
+	server := NewTranslateServer(Faults{FailFirst: 1, FailEvery: 3, ErrorStatus: http.StatusTooManyRequests})
+	defer server.Close()
+
+	form := url.Values{"q": {"Hello"}, "target": {"de"}}
+	var statuses []int
+	for range 4 {
+		resp, err := http.PostForm(server.URL+"/language/translate/v2?key=test", form)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		statuses = append(statuses, resp.StatusCode)
+	}
+
+	want := []int{http.StatusTooManyRequests, http.StatusOK, http.StatusTooManyRequests, http.StatusOK}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Errorf("request %d: expected status %d, got %d", i+1, want[i], statuses[i])
+		}
+	}
+	if server.Requests() != 4 {
+		t.Errorf("expected 4 requests, got %d", server.Requests())
+	}
+
+	server.SetFaults(Faults{})
+	resp, err := http.PostForm(server.URL+"/language/translate/v2", form)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 without API key, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,65 @@
+package fakes
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// DefaultTranscript is the transcript a SpeechServer returns unless configured otherwise
+const DefaultTranscript = "Hello from the fake speech server."
+
+// SpeechServer fakes the Speech-to-Text v1 REST API, recognizing every audio as Transcript
+// Point stt.SetEndpoint (SPEECH_ENDPOINT) at its URL.
+type SpeechServer struct {
+	*server
+
+	Transcript string  // Transcript of every recognition
+	Confidence float32 // Confidence of the transcript (0-1)
+}
+
+// NewSpeechServer starts a fake Speech-to-Text server; Close it when done
+func NewSpeechServer(faults Faults) *SpeechServer {
+	s := &SpeechServer{Transcript: DefaultTranscript, Confidence: 0.9}
+	s.server = newServer(faults, s.handleRecognize)
+	return s
+}
+
+// handleRecognize answers POST /v1/speech:recognize
+func (s *SpeechServer) handleRecognize(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/v1/speech:recognize" {
+		writeError(w, http.StatusNotFound, "unknown method "+r.Method+" "+r.URL.Path)
+		return
+	}
+
+	var req struct {
+		Config struct {
+			LanguageCode string `json:"languageCode"`
+		} `json:"config"`
+		Audio struct {
+			Content string `json:"content"`
+		} `json:"audio"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if req.Audio.Content == "" {
+		writeError(w, http.StatusBadRequest, "audio content is required")
+		return
+	}
+
+	languageCode := strings.ToLower(req.Config.LanguageCode)
+	if languageCode == "" {
+		languageCode = "en-us"
+	}
+	writeJSON(w, map[string]any{
+		"results": []map[string]any{{
+			"alternatives": []map[string]any{{
+				"transcript": s.Transcript,
+				"confidence": s.Confidence,
+			}},
+			"languageCode": languageCode,
+		}},
+	})
+}
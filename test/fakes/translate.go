@@ -0,0 +1,52 @@
+package fakes
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// TranslateServer fakes the Google Translate v2 API, translating text to "[target] text"
+// Point translation.SetGoogleEndpoint (GOOGLE_TRANSLATE_ENDPOINT) at its URL.
+type TranslateServer struct {
+	*server
+}
+
+// NewTranslateServer starts a fake Google Translate server; Close it when done
+func NewTranslateServer(faults Faults) *TranslateServer {
+	return &TranslateServer{server: newServer(faults, handleTranslate)}
+}
+
+// handleTranslate answers POST /language/translate/v2 form requests
+func handleTranslate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/language/translate/v2" {
+		writeError(w, http.StatusNotFound, "unknown method "+r.Method+" "+r.URL.Path)
+		return
+	}
+	if r.URL.Query().Get("key") == "" {
+		writeError(w, http.StatusForbidden, "missing API key")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	texts, target := r.PostForm["q"], r.PostForm.Get("target")
+	if len(texts) == 0 || target == "" {
+		writeError(w, http.StatusBadRequest, "q and target are required")
+		return
+	}
+	source := r.PostForm.Get("source")
+	if source == "" {
+		source = "en"
+	}
+
+	translations := make([]map[string]string, len(texts))
+	for i, text := range texts {
+		translations[i] = map[string]string{
+			"translatedText":         fmt.Sprintf("[%s] %s", target, text),
+			"detectedSourceLanguage": source,
+		}
+	}
+	writeJSON(w, map[string]any{"data": map[string]any{"translations": translations}})
+}
@@ -0,0 +1,58 @@
+package fakes
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/sinouw/multilingual-video-processor/internal/mock"
+	"github.com/sinouw/multilingual-video-processor/internal/tts"
+)
+
+// TTSServer fakes the Text-to-Speech v1 REST API, returning silent MP3 audio as long as the SSML takes to speak
+// Point tts.SetEndpoint (TTS_ENDPOINT) at its URL.
+type TTSServer struct {
+	*server
+}
+
+// NewTTSServer starts a fake Text-to-Speech server; Close it when done
+func NewTTSServer(faults Faults) *TTSServer {
+	return &TTSServer{server: newServer(faults, handleSynthesize)}
+}
+
+// handleSynthesize answers POST /v1/text:synthesize
+func handleSynthesize(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/v1/text:synthesize" {
+		writeError(w, http.StatusNotFound, "unknown method "+r.Method+" "+r.URL.Path)
+		return
+	}
+
+	var req struct {
+		Input struct {
+			Text string `json:"text"`
+			SSML string `json:"ssml"`
+		} `json:"input"`
+		Voice struct {
+			LanguageCode string `json:"languageCode"`
+		} `json:"voice"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	input := req.Input.SSML
+	if input == "" {
+		input = req.Input.Text
+	}
+	if input == "" || req.Voice.LanguageCode == "" {
+		writeError(w, http.StatusBadRequest, "input and voice.languageCode are required")
+		return
+	}
+
+	audio, err := mock.Synthesize(r.Context(), input, &tts.VoiceConfig{LanguageCode: req.Voice.LanguageCode})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, map[string]string{"audioContent": base64.StdEncoding.EncodeToString(audio)})
+}
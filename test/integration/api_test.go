@@ -3,16 +3,13 @@
 package integration
 
 import (
-	"bytes"
-	"encoding/json"
-	"net/http"
-	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/sinouw/multilingual-video-processor/internal/api"
 	"github.com/sinouw/multilingual-video-processor/internal/config"
+	"github.com/sinouw/multilingual-video-processor/internal/validator"
 	"github.com/sinouw/multilingual-video-processor/pkg/models"
 )
 
@@ -40,25 +37,16 @@ func TestAPI_SubmitTranslationJob(t *testing.T) {
 		t.Fatalf("failed to load config: %v", err)
 	}
 
-	jobStore := api.NewInMemoryJobStore(cfg.JobTTL)
-	rateLimiter := api.NewRateLimiter(cfg.RateLimitRPM)
-
 	// Create a valid translation request
 	request := models.TranslateRequest{
 		VideoURL:        "gs://test-bucket/test-video.mp4",
 		TargetLanguages: []string{"en"},
 	}
-	body, _ := json.Marshal(request)
 
-	// Note: This test requires mocking external services or using test credentials
-	// For now, we test the request validation and job creation flow
-	req := httptest.NewRequest(http.MethodPost, "/v1/translate", bytes.NewBuffer(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	// Test would require full handler setup with mocked dependencies
-	// This is a placeholder for actual integration testing
-	t.Log("Integration test placeholder - requires mocked external services")
+	// Submitting the job needs the provider fakes (see providers_test.go); here the request is validated
+	if err := validator.ValidateTranslateRequest(&request, cfg); err != nil {
+		t.Errorf("expected a valid request, got %v", err)
+	}
 }
 
 func TestAPI_JobStatusFlow(t *testing.T) {
@@ -0,0 +1,141 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sinouw/multilingual-video-processor/internal/stt"
+	"github.com/sinouw/multilingual-video-processor/internal/transient"
+	"github.com/sinouw/multilingual-video-processor/internal/translation"
+	"github.com/sinouw/multilingual-video-processor/internal/tts"
+	"github.com/sinouw/multilingual-video-processor/test/fakes"
+)
+
+// These tests run the real provider clients against the fake servers, so they need no credentials
+
+func TestProviders_Pipeline(t *testing.T) {
+	speechServer := fakes.NewSpeechServer(fakes.Faults{})
+	defer speechServer.Close()
+	translateServer := fakes.NewTranslateServer(fakes.Faults{})
+	defer translateServer.Close()
+	ttsServer := fakes.NewTTSServer(fakes.Faults{})
+	defer ttsServer.Close()
+	useEndpoints(t, speechServer.URL, translateServer.URL, ttsServer.URL)
+
+	ctx := context.Background()
+	audioPath := writeAudio(t)
+
+	transcription, err := stt.SpeechToTextWithOptions(ctx, audioPath, stt.Options{LanguageHint: "en"})
+	if err != nil {
+		t.Fatalf("speech-to-text failed: %v", err)
+	}
+	if transcription.Text != fakes.DefaultTranscript || transcription.Confidence < 0.89 {
+		t.Errorf("unexpected transcription %+v", transcription)
+	}
+
+	translated, err := translation.TranslateText(ctx, transcription.Text, "en", "de")
+	if err != nil {
+		t.Fatalf("translation failed: %v", err)
+	}
+	if translated != "[de] "+fakes.DefaultTranscript {
+		t.Errorf("unexpected translation %q", translated)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "de.mp3")
+	if err := tts.GenerateTTS(ctx, translated, "de", 5, outputPath); err != nil {
+		t.Fatalf("TTS failed: %v", err)
+	}
+	audio, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read TTS output: %v", err)
+	}
+	if len(audio) < 4 || audio[0] != 0xFF || audio[1] != 0xFB {
+		t.Error("expected MP3 audio from the fake TTS server")
+	}
+
+	for name, requests := range map[string]int{
+		"speech":    speechServer.Requests(),
+		"translate": translateServer.Requests(),
+		"tts":       ttsServer.Requests(),
+	} {
+		if requests != 1 {
+			t.Errorf("expected 1 %s request, got %d", name, requests)
+		}
+	}
+}
+
+func TestProviders_ErrorInjection(t *testing.T) {
+	speechServer := fakes.NewSpeechServer(fakes.Faults{FailFirst: 1, ErrorStatus: http.StatusTooManyRequests})
+	defer speechServer.Close()
+	translateServer := fakes.NewTranslateServer(fakes.Faults{FailFirst: 1})
+	defer translateServer.Close()
+	ttsServer := fakes.NewTTSServer(fakes.Faults{FailFirst: 1, ErrorStatus: http.StatusBadRequest})
+	defer ttsServer.Close()
+	useEndpoints(t, speechServer.URL, translateServer.URL, ttsServer.URL)
+
+	ctx := context.Background()
+
+	_, err := stt.SpeechToTextWithOptions(ctx, writeAudio(t), stt.Options{LanguageHint: "en"})
+	if err == nil || !transient.IsTransient(err) {
+		t.Errorf("expected transient speech error for 429, got %v", err)
+	}
+
+	_, err = translation.TranslateText(ctx, "Hello", "en", "fr")
+	if err == nil || !transient.IsTransient(err) {
+		t.Errorf("expected transient translation error for 503, got %v", err)
+	}
+
+	err = tts.GenerateTTS(ctx, "Hello", "fr", 1, filepath.Join(t.TempDir(), "fr.mp3"))
+	if err == nil || transient.IsTransient(err) {
+		t.Errorf("expected permanent TTS error for 400, got %v", err)
+	}
+
+	// Faults only apply to the first request
+	if _, err := translation.TranslateText(ctx, "Hello", "en", "fr"); err != nil {
+		t.Errorf("expected second translation to succeed, got %v", err)
+	}
+}
+
+func TestProviders_Latency(t *testing.T) {
+	translateServer := fakes.NewTranslateServer(fakes.Faults{Latency: 500 * time.Millisecond})
+	defer translateServer.Close()
+	useEndpoints(t, "", translateServer.URL, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := translation.TranslateText(ctx, "Hello", "en", "ar")
+	if err == nil || !strings.Contains(err.Error(), "cancelled") {
+		t.Errorf("expected cancellation while waiting on a slow provider, got %v", err)
+	}
+}
+
+// useEndpoints points the providers at the fake servers until the test ends
+func useEndpoints(t *testing.T, speechURL, translateURL, ttsURL string) {
+	t.Helper()
+	stt.SetEndpoint(speechURL)
+	translation.SetGoogleEndpoint(translateURL)
+	tts.SetEndpoint(ttsURL)
+	t.Cleanup(func() {
+		stt.SetEndpoint("")
+		translation.SetGoogleEndpoint("")
+		tts.SetEndpoint("")
+	})
+}
+
+// writeAudio writes a small audio file for recognition; the fake does not decode it
+func writeAudio(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audio.wav")
+	if err := os.WriteFile(path, []byte("RIFF fake audio"), 0644); err != nil {
+		t.Fatalf("failed to write audio: %v", err)
+	}
+	return path
+}